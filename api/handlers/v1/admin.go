@@ -48,9 +48,7 @@ func (h *HandlerV1) CreateAdmin(c *gin.Context) {
 
 	err := c.ShouldBindJSON(&body)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
-		})
+		h.bindError(c, err)
 		l.Error(err)
 		return
 	}
@@ -58,7 +56,7 @@ func (h *HandlerV1) CreateAdmin(c *gin.Context) {
 	res := valid.IsValidEmail(body.Email)
 	if !res {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Incorrect Email. Try again",
+			"error": h.message(c, "incorrect_email"),
 		})
 
 		h.Logger.Error("Incorrect Email. Try again, error while in Create")
@@ -68,7 +66,7 @@ func (h *HandlerV1) CreateAdmin(c *gin.Context) {
 	res = valid.IsValidPassword(body.Password)
 	if !res {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Incorrect Password. Try again",
+			"error": h.message(c, "incorrect_password"),
 		})
 
 		h.Logger.Error("Incorrect Password. Try again, error while in Create")
@@ -81,7 +79,7 @@ func (h *HandlerV1) CreateAdmin(c *gin.Context) {
 	})
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Went wrong",
+			"error": h.message(c, "something_went_wrong"),
 		})
 
 		h.Logger.Error("Error while check unique email in Create")
@@ -99,7 +97,7 @@ func (h *HandlerV1) CreateAdmin(c *gin.Context) {
 	password, err := etc.HashPassword(body.Password)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Went wrong",
+			"error": h.message(c, "something_went_wrong"),
 		})
 
 		h.Logger.Error("Error while hash password in Create")
@@ -119,7 +117,7 @@ func (h *HandlerV1) CreateAdmin(c *gin.Context) {
 	access, refresh, err := h.JwtHandler.GenerateJwt()
 	if err != nil {
 		c.JSON(http.StatusConflict, gin.H{
-			"error": "error while generating jwt",
+			"error": h.message(c, "something_went_wrong"),
 		})
 		h.Logger.Error("error generate new jwt tokens", l.Error(err))
 		return
@@ -141,7 +139,7 @@ func (h *HandlerV1) CreateAdmin(c *gin.Context) {
 
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": err.Error(),
+			"error": h.errorMessage(c, err),
 		})
 		l.Error(err)
 		return
@@ -193,7 +191,7 @@ func (h *HandlerV1) GetAdmin(c *gin.Context) {
 		})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": err.Error(),
+			"error": h.errorMessage(c, err),
 		})
 		l.Error(err)
 		return
@@ -247,7 +245,7 @@ func (h *HandlerV1) ListAdmins(c *gin.Context) {
 	params, errStr := utils.ParseQueryParam(queryParams)
 	if errStr != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": errStr[0],
+			"error": h.errorMessage(c, errStr[0]),
 		})
 		return
 	}
@@ -266,7 +264,7 @@ func (h *HandlerV1) ListAdmins(c *gin.Context) {
 
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": err.Error(),
+			"error": h.errorMessage(c, err),
 		})
 		l.Error(err)
 		return
@@ -303,9 +301,7 @@ func (h *HandlerV1) UpdateAdmin(c *gin.Context) {
 
 	err := c.ShouldBindJSON(&body)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
-		})
+		h.bindError(c, err)
 		h.Logger.Error("failed to bind json", l.Error(err))
 		return
 	}
@@ -313,7 +309,7 @@ func (h *HandlerV1) UpdateAdmin(c *gin.Context) {
     userID, statusCode := GetIdFromToken(c.Request, h.Config)
 	if statusCode != http.StatusOK {
 		c.JSON(statusCode, gin.H{
-            "error": "Can't get",
+            "error": h.message(c, "cant_get_user"),
         })
         return
     }
@@ -323,7 +319,7 @@ func (h *HandlerV1) UpdateAdmin(c *gin.Context) {
 	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Went wrong",
+			"error": h.message(c, "something_went_wrong"),
 		})
 		h.Logger.Error("failed to get user in update", l.Error(err))
 		return
@@ -340,7 +336,7 @@ func (h *HandlerV1) UpdateAdmin(c *gin.Context) {
 		resPass := valid.IsValidPassword(body.Email)
 		if !resPass {
 			c.JSON(http.StatusBadRequest, gin.H{
-				"error": "Incorrect Password. Try again",
+				"error": h.message(c, "incorrect_password"),
 			})
 
 			h.Logger.Error("Incorrect Password. Try again, error while in update")
@@ -349,7 +345,7 @@ func (h *HandlerV1) UpdateAdmin(c *gin.Context) {
 		body.Password, err = etc.HashPassword(body.Password)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{
-				"error": "Went wrong",
+				"error": h.message(c, "something_went_wrong"),
 			})
 			h.Logger.Error("failed to hash password in update", l.Error(err))
 			return
@@ -363,7 +359,7 @@ func (h *HandlerV1) UpdateAdmin(c *gin.Context) {
 		})
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{
-				"error": "Went wrong",
+				"error": h.message(c, "something_went_wrong"),
 			})
 	
 			h.Logger.Error("Error while check unique email in update admin")
@@ -381,7 +377,7 @@ func (h *HandlerV1) UpdateAdmin(c *gin.Context) {
 		res := valid.IsValidEmail(body.Email)
 		if !res {
 			c.JSON(http.StatusBadRequest, gin.H{
-				"error": "Incorrect Email. Try again",
+				"error": h.message(c, "incorrect_email"),
 			})
 
 			h.Logger.Error("Incorrect Email. Try again, error while in update")
@@ -430,7 +426,7 @@ func (h *HandlerV1) UpdateAdmin(c *gin.Context) {
 	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": err.Error(),
+			"error": h.errorMessage(c, err),
 		})
 		h.Logger.Error("failed to update user", l.Error(err))
 		return
@@ -483,7 +479,7 @@ func (h *HandlerV1) DeleteAdmin(c *gin.Context) {
 	})
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Went wrong, error",
+			"error": h.message(c, "something_went_wrong"),
 		})
 		h.Logger.Error("failed to get admin in delete admin", l.Error(err))
 		return
@@ -502,7 +498,7 @@ func (h *HandlerV1) DeleteAdmin(c *gin.Context) {
 		})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Went wrong, error",
+			"error": h.message(c, "something_went_wrong"),
 		})
 		h.Logger.Error("failed to delete user", l.Error(err))
 		return
@@ -510,7 +506,7 @@ func (h *HandlerV1) DeleteAdmin(c *gin.Context) {
 
 	// if response != nil {
 	// 	c.JSON(http.StatusInternalServerError, gin.H{
-	// 		"error": "Went wrong",
+	// 		"error": h.message(c, "something_went_wrong"),
 	// 	})
 	// 	h.Logger.Error("failed to delete user", l.Error(err))
 	// 	return
@@ -21,6 +21,7 @@ import (
 // @Produce json
 // @Param Attraction body models.CreateAttraction true "Attraction"
 // @Success 200 {object} models.AttractionModel
+// @Failure 400 {object} models.StandartError
 // @Failure 404 {object} models.StandartError
 // @Failure 500 {object} models.StandartError
 // @Router /v1/attraction [POST]
@@ -39,16 +40,19 @@ func (h HandlerV1) CreateAttraction(c *gin.Context) {
 	defer span.End()
 
 	if err := c.ShouldBindJSON(&body); err != nil {
-		c.JSON(404, gin.H{
-			"error": err.Error(),
-		})
+		h.bindError(c, err)
+		return
+	}
+
+	tags, ok := h.checkTags(ctx, c, categoryAttraction, body.Tags)
+	if !ok {
 		return
 	}
 
 	owner_id, statusCode := GetIdFromToken(c.Request, h.Config)
 	if statusCode != http.StatusOK {
 		c.JSON(statusCode, gin.H{
-			"error": "Can't get",
+			"error": h.message(c, "cant_get_user"),
 		})
 		return
 	}
@@ -96,11 +100,14 @@ func (h HandlerV1) CreateAttraction(c *gin.Context) {
 	})
 	if err != nil {
 		c.JSON(500, gin.H{
-			"error": err.Error(),
+			"error": h.errorMessage(c, err),
 		})
 		return
 	}
 
+	h.indexEstablishment(ctx, indexedAttraction(response))
+	h.saveTags(ctx, categoryAttraction, response.AttractionId, tags)
+
 	var respImages []*models.ImageModel
 
 	for _, respImage := range response.Images {
@@ -138,6 +145,7 @@ func (h HandlerV1) CreateAttraction(c *gin.Context) {
 		},
 		CreatedAt: response.CreatedAt,
 		UpdatedAt: response.UpdatedAt,
+		Tags:      tags,
 	}
 
 	c.JSON(http.StatusCreated, respModel)
@@ -151,6 +159,7 @@ func (h HandlerV1) CreateAttraction(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param attraction_id query string true "attraction_id"
+// @Param lang query string false "lang, uz, ru or en, takes the place of Accept-Language"
 // @Success 200 {object} models.AttractionModel
 // @Failure 404 {object} models.StandartError
 // @Failure 500 {object} models.StandartError
@@ -175,7 +184,7 @@ func (h HandlerV1) GetAttraction(c *gin.Context) {
 	})
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
+			"error": h.errorMessage(c, err),
 		})
 		h.Logger.Error(err.Error())
 		return
@@ -219,6 +228,11 @@ func (h HandlerV1) GetAttraction(c *gin.Context) {
 		CreatedAt: response.Attraction.CreatedAt,
 		UpdatedAt: response.Attraction.UpdatedAt,
 	}
+	respModel.Tags = h.establishmentTags(ctx, categoryAttraction, []string{respModel.AttractionId})[respModel.AttractionId]
+	translate(h.translations(ctx, c, categoryAttraction, []string{respModel.AttractionId})[respModel.AttractionId], &respModel.AttractionName, &respModel.Description)
+	respModel.OpeningStatus = h.openingStatuses(ctx, categoryAttraction, map[string]string{
+		respModel.AttractionId: "",
+	})[respModel.AttractionId]
 
 	c.JSON(200, respModel)
 }
@@ -226,12 +240,25 @@ func (h HandlerV1) GetAttraction(c *gin.Context) {
 // LIST ATTRACTIONS BY PAGE AND LIMIT
 // @Summary LIST ATTRACTIONS BY PAGE AND LIMIT
 // @Security BearerAuth
-// @Description Api for listing attractions by page and limit
+// @Description Api for listing attractions by page and limit. city takes a comma separated list, tags a comma separated list of tag slugs all of which must be there, min_rating is 0 to 5 and price_min and price_max bound the base price in the smallest currency unit. sort is rating, distance, popularity, created_at or name with an optional - for descending order, distance is measured from near ("lat,lng"). facets counts the matches per city, per rating and the price range, each ignoring its own filter, and per tag. A sorted or filtered list also returns next_cursor and prev_cursor
 // @Tags ATTRACTION
 // @Accept json
 // @Produce json
-// @Param request query models.Pagination true "request"
+// @Produce application/geo+json
+// @Param request query models.EstablishmentPagination true "request"
+// @Param city query string false "city"
+// @Param tags query string false "tags"
+// @Param min_rating query number false "min_rating"
+// @Param price_min query int false "price_min"
+// @Param price_max query int false "price_max"
+// @Param open_now query bool false "open_now, only those open now"
+// @Param open_at query string false "open_at, only those open at this Asia/Tashkent time, 2006-01-02T15:04"
+// @Param sort query string false "sort" default(-rating)
+// @Param near query string false "near"
+// @Param zoom query int false "zoom, clusters the GeoJSON points of all the matches rather than of the page"
+// @Param lang query string false "lang, uz, ru or en, takes the place of Accept-Language"
 // @Success 200 {object} models.ListAttractionModel
+// @Failure 400 {object} models.StandartError
 // @Failure 404 {object} models.StandartError
 // @Failure 500 {object} models.StandartError
 // @Router /v1/attraction/list [GET]
@@ -248,32 +275,57 @@ func (h HandlerV1) ListAttractions(c *gin.Context) {
 	)
 	defer span.End()
 
-	queryParams := c.Request.URL.Query()
-	params, errStr := utils.ParseQueryParam(queryParams)
-	if errStr != nil {
-		c.JSON(http.StatusBadRequest, models.Error{
-			Message: "Incorrect Date",
-		})
+	list := h.listEstablishments(ctx, c, categoryAttraction)
+	if list == nil {
 		return
 	}
 
-	offset := (params.Page - 1) * params.Limit
-
-	response, err := h.Service.EstablishmentService().ListAttractions(ctx, &pbe.ListAttractionsRequest{
-		Offset: int64(offset),
-		Limit:  int64(params.Limit),
-	})
-	if err != nil {
-		c.JSON(500, gin.H{
-			"error": err.Error(),
+	var (
+		attractions []*pbe.Attraction
+		count       uint64
+	)
+	if list.filtered {
+		listed, err := h.listedAttractions(ctx, list.page.IDs)
+		if err != nil {
+			c.JSON(500, gin.H{
+				"error": h.errorMessage(c, err),
+			})
+			h.Logger.Error(err.Error())
+			return
+		}
+		attractions, count = listed, uint64(list.page.Total)
+	} else {
+		response, err := h.Service.EstablishmentService().ListAttractions(ctx, &pbe.ListAttractionsRequest{
+			Offset: int64(list.offset),
+			Limit:  int64(list.limit),
 		})
-		h.Logger.Error(err.Error())
-		return
+		if err != nil {
+			c.JSON(500, gin.H{
+				"error": h.errorMessage(c, err),
+			})
+			h.Logger.Error(err.Error())
+			return
+		}
+
+		attractions, count = response.Attractions, response.Overall
+	}
+
+	plain := make(map[string]string, len(attractions))
+	for _, attraction := range attractions {
+		plain[attraction.AttractionId] = ""
+	}
+	statuses := h.openingStatuses(ctx, categoryAttraction, plain)
+
+	ids := make([]string, 0, len(attractions))
+	for _, attraction := range attractions {
+		ids = append(ids, attraction.AttractionId)
 	}
+	tags := h.establishmentTags(ctx, categoryAttraction, ids)
+	translations := h.translations(ctx, c, categoryAttraction, ids)
 
 	var respAttractions []*models.AttractionModel
 
-	for _, respAttraction := range response.Attractions {
+	for _, respAttraction := range attractions {
 
 		var respImages []*models.ImageModel
 
@@ -310,16 +362,28 @@ func (h HandlerV1) ListAttractions(c *gin.Context) {
 				CreatedAt:       respAttraction.Location.CreatedAt,
 				UpdatedAt:       respAttraction.Location.UpdatedAt,
 			},
-			CreatedAt: respAttraction.CreatedAt,
-			UpdatedAt: respAttraction.UpdatedAt,
+			CreatedAt:     respAttraction.CreatedAt,
+			UpdatedAt:     respAttraction.UpdatedAt,
+			Tags:          tags[respAttraction.AttractionId],
+			OpeningStatus: statuses[respAttraction.AttractionId],
 		}
+		translate(translations[respAttraction.AttractionId], &attraction.AttractionName, &attraction.Description)
 
 		respAttractions = append(respAttractions, &attraction)
 	}
 
+	next, prev := list.cursors()
 	listModel := models.ListAttractionModel{
 		Attractions: respAttractions,
-		Count:       response.Overall,
+		Count:       count,
+		Facets:      list.facets(),
+		NextCursor:  next,
+		PrevCursor:  prev,
+	}
+
+	if wantsGeoJSON(c) {
+		h.writeGeoJSONPage(c, attractionFeatures(respAttractions), next, prev)
+		return
 	}
 
 	c.JSON(200, listModel)
@@ -335,6 +399,7 @@ func (h HandlerV1) ListAttractions(c *gin.Context) {
 // @Param attraction_id query string true "attraction_id"
 // @Param UpdatingAttraction body models.UpdateAttraction true "UpdatingAttraction"
 // @Success 200 {object} models.AttractionModel
+// @Failure 400 {object} models.StandartError
 // @Failure 404 {object} models.StandartError
 // @Failure 500 {object} models.StandartError
 // @Router /v1/attraction [PUT]
@@ -352,12 +417,18 @@ func (h HandlerV1) UpdateAttraction(c *gin.Context) {
 	)
 	defer span.End()
 
+	var tags []string
+	if body.Tags != nil {
+		var ok bool
+		if tags, ok = h.checkTags(ctx, c, categoryAttraction, body.Tags); !ok {
+			return
+		}
+	}
+
 	attraction_id := c.Query("attraction_id")
 
 	if err := c.ShouldBindJSON(&body); err != nil {
-		c.JSON(404, gin.H{
-			"error": err.Error(),
-		})
+		h.bindError(c, err)
 		return
 	}
 
@@ -382,12 +453,17 @@ func (h HandlerV1) UpdateAttraction(c *gin.Context) {
 	})
 	if err != nil {
 		c.JSON(500, gin.H{
-			"error": err.Error(),
+			"error": h.errorMessage(c, err),
 		})
 		h.Logger.Error(err.Error())
 		return
 	}
 
+	h.indexEstablishment(ctx, indexedAttraction(response.Attraction))
+	if body.Tags != nil {
+		h.saveTags(ctx, categoryAttraction, response.Attraction.AttractionId, tags)
+	}
+
 	var respImages []*models.ImageModel
 
 	for _, respImage := range response.Attraction.Images {
@@ -426,6 +502,7 @@ func (h HandlerV1) UpdateAttraction(c *gin.Context) {
 		CreatedAt: response.Attraction.CreatedAt,
 		UpdatedAt: response.Attraction.UpdatedAt,
 	}
+	respModel.Tags = h.establishmentTags(ctx, categoryAttraction, []string{respModel.AttractionId})[respModel.AttractionId]
 
 	c.JSON(200, respModel)
 }
@@ -461,7 +538,7 @@ func (h HandlerV1) DeleteAttraction(c *gin.Context) {
 	})
 	if err != nil {
 		c.JSON(500, gin.H{
-			"error": err.Error(),
+			"error": h.errorMessage(c, err),
 		})
 		h.Logger.Error(err.Error())
 		return
@@ -469,12 +546,14 @@ func (h HandlerV1) DeleteAttraction(c *gin.Context) {
 
 	if !response.Success {
 		c.JSON(404, gin.H{
-			"error": "not deleted",
+			"error": h.message(c, "not_deleted"),
 		})
 		h.Logger.Error("not deleted")
 		return
 	}
 
+	h.unindexEstablishment(ctx, categoryAttraction, attraction_id)
+
 	c.JSON(200, gin.H{
 		"message": "successfuly deleted",
 	})
@@ -487,8 +566,11 @@ func (h HandlerV1) DeleteAttraction(c *gin.Context) {
 // @Tags ATTRACTION
 // @Accept json
 // @Produce json
+// @Produce application/geo+json
 // @Param request query models.Pagination true "request"
 // @Param request query models.FieldValuesByLocation true "request"
+// @Param zoom query int false "zoom, clusters the GeoJSON points of this page only, /list clusters all its matches"
+// @Param lang query string false "lang, uz, ru or en, takes the place of Accept-Language"
 // @Success 200 {object} models.ListAttractionModel
 // @Failure 404 {object} models.StandartError
 // @Failure 500 {object} models.StandartError
@@ -509,7 +591,7 @@ func (h HandlerV1) ListAttractionsByLocation(c *gin.Context) {
 	params, errStr := utils.ParseQueryParam(queryParams)
 	if errStr != nil {
 		c.JSON(http.StatusBadRequest, models.Error{
-			Message: "Incorrect Date",
+			Message: h.message(c, "incorrect_date"),
 		})
 		return
 	}
@@ -529,12 +611,25 @@ func (h HandlerV1) ListAttractionsByLocation(c *gin.Context) {
 	})
 	if err != nil {
 		c.JSON(500, gin.H{
-			"error": err.Error(),
+			"error": h.errorMessage(c, err),
 		})
 		h.Logger.Error(err.Error())
 		return
 	}
 
+	plain := make(map[string]string, len(response.Attractions))
+	for _, attraction := range response.Attractions {
+		plain[attraction.AttractionId] = ""
+	}
+	statuses := h.openingStatuses(ctx, categoryAttraction, plain)
+
+	ids := make([]string, 0, len(response.Attractions))
+	for _, attraction := range response.Attractions {
+		ids = append(ids, attraction.AttractionId)
+	}
+	tags := h.establishmentTags(ctx, categoryAttraction, ids)
+	translations := h.translations(ctx, c, categoryAttraction, ids)
+
 	var respAttractions []*models.AttractionModel
 
 	for _, respAttraction := range response.Attractions {
@@ -574,9 +669,12 @@ func (h HandlerV1) ListAttractionsByLocation(c *gin.Context) {
 				CreatedAt:       respAttraction.Location.CreatedAt,
 				UpdatedAt:       respAttraction.Location.UpdatedAt,
 			},
-			CreatedAt: respAttraction.CreatedAt,
-			UpdatedAt: respAttraction.UpdatedAt,
+			CreatedAt:     respAttraction.CreatedAt,
+			UpdatedAt:     respAttraction.UpdatedAt,
+			Tags:          tags[respAttraction.AttractionId],
+			OpeningStatus: statuses[respAttraction.AttractionId],
 		}
+		translate(translations[respAttraction.AttractionId], &attraction.AttractionName, &attraction.Description)
 
 		respAttractions = append(respAttractions, &attraction)
 	}
@@ -586,6 +684,11 @@ func (h HandlerV1) ListAttractionsByLocation(c *gin.Context) {
 		Count:       uint64(response.Count),
 	}
 
+	if wantsGeoJSON(c) {
+		h.writeGeoJSON(c, attractionFeatures(respAttractions))
+		return
+	}
+
 	c.JSON(200, respModel)
 }
 
@@ -596,7 +699,10 @@ func (h HandlerV1) ListAttractionsByLocation(c *gin.Context) {
 // @Tags ATTRACTION
 // @Accept json
 // @Produce json
+// @Produce application/geo+json
 // @Param request query models.FindByName true "request"
+// @Param zoom query int false "zoom, clusters the GeoJSON points of this page only, /list clusters all its matches"
+// @Param lang query string false "lang, uz, ru or en, takes the place of Accept-Language"
 // @Success 200 {object} models.ListAttractionModel
 // @Failure 404 {object} models.StandartError
 // @Failure 500 {object} models.StandartError
@@ -621,12 +727,25 @@ func (h HandlerV1) FindAttractionsByName(c *gin.Context) {
 	})
 	if err != nil {
 		c.JSON(500, gin.H{
-			"error": err.Error(),
+			"error": h.errorMessage(c, err),
 		})
 		h.Logger.Error(err.Error())
 		return
 	}
 
+	plain := make(map[string]string, len(response.Attractions))
+	for _, attraction := range response.Attractions {
+		plain[attraction.AttractionId] = ""
+	}
+	statuses := h.openingStatuses(ctx, categoryAttraction, plain)
+
+	ids := make([]string, 0, len(response.Attractions))
+	for _, attraction := range response.Attractions {
+		ids = append(ids, attraction.AttractionId)
+	}
+	tags := h.establishmentTags(ctx, categoryAttraction, ids)
+	translations := h.translations(ctx, c, categoryAttraction, ids)
+
 	var respAttractions []*models.AttractionModel
 
 	for _, respAttraction := range response.Attractions {
@@ -666,9 +785,12 @@ func (h HandlerV1) FindAttractionsByName(c *gin.Context) {
 				CreatedAt:       respAttraction.Location.CreatedAt,
 				UpdatedAt:       respAttraction.Location.UpdatedAt,
 			},
-			CreatedAt: respAttraction.CreatedAt,
-			UpdatedAt: respAttraction.UpdatedAt,
+			CreatedAt:     respAttraction.CreatedAt,
+			UpdatedAt:     respAttraction.UpdatedAt,
+			Tags:          tags[respAttraction.AttractionId],
+			OpeningStatus: statuses[respAttraction.AttractionId],
 		}
+		translate(translations[respAttraction.AttractionId], &attraction.AttractionName, &attraction.Description)
 
 		respAttractions = append(respAttractions, &attraction)
 	}
@@ -678,5 +800,10 @@ func (h HandlerV1) FindAttractionsByName(c *gin.Context) {
 		Count:       response.Count,
 	}
 
+	if wantsGeoJSON(c) {
+		h.writeGeoJSON(c, attractionFeatures(respAttractions))
+		return
+	}
+
 	c.JSON(200, listModel)
 }
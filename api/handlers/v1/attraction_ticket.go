@@ -0,0 +1,370 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+
+	"Booking/api-service-booking/api/models"
+	pbe "Booking/api-service-booking/genproto/establishment-proto"
+	"Booking/api-service-booking/internal/entity"
+	errorspkg "Booking/api-service-booking/internal/errors"
+	"Booking/api-service-booking/internal/pkg/booktime"
+	"Booking/api-service-booking/internal/pkg/i18n"
+	l "Booking/api-service-booking/internal/pkg/logger"
+	"Booking/api-service-booking/internal/pkg/otlp"
+)
+
+// CREATE TICKET TYPE
+// @Summary CREATE TICKET TYPE
+// @Security BearerAuth
+// @Description Api for adding an adult, child or student ticket with its price to an attraction
+// @Tags ATTRACTION
+// @Accept json
+// @Produce json
+// @Param TicketType body models.CreateTicketType true "TicketType"
+// @Success 201 {object} models.TicketTypeRes
+// @Failure 400 {object} models.StandartError
+// @Failure 409 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/attraction/tickets/types [POST]
+func (h *HandlerV1) CreateTicketType(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "CreateTicketType")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	var body models.CreateTicketType
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.bindError(c, err)
+		return
+	}
+
+	if status, err := h.checkAttraction(ctx, body.AttractionId); err != nil {
+		c.JSON(status, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	}
+
+	ticketType := entity.TicketType{
+		AttractionID: body.AttractionId,
+		Kind:         body.Kind,
+		Price:        body.Price,
+	}
+	err := h.AttractionTicket.CreateType(ctx, &ticketType)
+	var errBadRequest *errorspkg.ErrBadRequest
+	switch {
+	case errors.As(err, &errBadRequest):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	case errors.Is(err, errorspkg.ErrorConflict):
+		c.JSON(http.StatusConflict, gin.H{
+			"error": h.message(c, "ticket_type_taken"),
+		})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to create ticket type", l.Error(err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, ticketTypeRes(&ticketType))
+}
+
+// LIST TICKET TYPES
+// @Summary LIST TICKET TYPES
+// @Description Api for listing the ticket types and prices of an attraction
+// @Tags ATTRACTION
+// @Accept json
+// @Produce json
+// @Param attraction_id query string true "attraction_id"
+// @Success 200 {object} models.ListTicketTypesRes
+// @Failure 500 {object} models.StandartError
+// @Router /v1/attraction/tickets/types [GET]
+func (h *HandlerV1) ListTicketTypes(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "ListTicketTypes")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	types, err := h.AttractionTicket.ListTypes(ctx, c.Query("attraction_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to list ticket types", l.Error(err))
+		return
+	}
+
+	response := models.ListTicketTypesRes{
+		TicketTypes: []*models.TicketTypeRes{},
+	}
+	for _, ticketType := range types {
+		response.TicketTypes = append(response.TicketTypes, ticketTypeRes(ticketType))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// DELETE TICKET TYPE
+// @Summary DELETE TICKET TYPE
+// @Security BearerAuth
+// @Description Api for removing a ticket type from an attraction, sold tickets stay valid
+// @Tags ATTRACTION
+// @Accept json
+// @Produce json
+// @Param id path string true "ID"
+// @Success 200 {object} models.StandartError
+// @Failure 404 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/attraction/tickets/types/{id} [DELETE]
+func (h *HandlerV1) DeleteTicketType(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "DeleteTicketType")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	err := h.AttractionTicket.DeleteType(ctx, c.Param("id"))
+	if errors.Is(err, errorspkg.ErrorNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": h.message(c, "ticket_type_not_found"),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to delete ticket type", l.Error(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, "successfully deleted...")
+}
+
+// SET ENTRY SETTINGS
+// @Summary SET ENTRY SETTINGS
+// @Security BearerAuth
+// @Description Api for setting the opening hours, entry slot length and per-slot capacity of an attraction
+// @Tags ATTRACTION
+// @Accept json
+// @Produce json
+// @Param Settings body models.EntrySettingsReq true "Settings"
+// @Success 200 {object} models.EntrySettingsRes
+// @Failure 400 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/attraction/tickets/settings [PUT]
+func (h *HandlerV1) SetEntrySettings(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "SetEntrySettings")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	var body models.EntrySettingsReq
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.bindError(c, err)
+		return
+	}
+
+	if status, err := h.checkAttraction(ctx, body.AttractionId); err != nil {
+		c.JSON(status, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	}
+
+	settings := entity.EntrySettings{
+		AttractionID: body.AttractionId,
+		OpeningHours: body.OpeningHours,
+		SlotLength:   time.Duration(body.SlotMinutes) * time.Minute,
+		Capacity:     body.Capacity,
+	}
+	err := h.AttractionTicket.SaveSettings(ctx, &settings)
+	var errBadRequest *errorspkg.ErrBadRequest
+	if errors.As(err, &errBadRequest) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to save entry settings", l.Error(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, &models.EntrySettingsRes{
+		AttractionId: settings.AttractionID,
+		OpeningHours: settings.OpeningHours,
+		SlotMinutes:  int(settings.SlotLength / time.Minute),
+		Capacity:     settings.Capacity,
+		UpdatedAt:    settings.UpdatedAt.Format(time.RFC3339),
+	})
+}
+
+// LIST ENTRY SLOTS
+// @Summary LIST ENTRY SLOTS
+// @Description Api for listing the entry times of an attraction for a date with the tickets left
+// @Tags ATTRACTION
+// @Accept json
+// @Produce json
+// @Param request query models.EntrySlotsReq true "request"
+// @Success 200 {object} models.ListEntrySlotsRes
+// @Failure 400 {object} models.StandartError
+// @Failure 404 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/attraction/slots [GET]
+func (h *HandlerV1) ListEntrySlots(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "ListEntrySlots")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	var body models.EntrySlotsReq
+	if err := c.ShouldBindQuery(&body); err != nil {
+		h.bindError(c, err)
+		return
+	}
+
+	date, dateOnly, err := booktime.Parse(body.Date)
+	if err != nil || !dateOnly {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": h.message(c, "field_format", "date", "2006-01-02"),
+		})
+		return
+	}
+
+	slots, err := h.AttractionTicket.Slots(ctx, body.AttractionId, date)
+	if errors.Is(err, errorspkg.ErrorNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": h.message(c, "timed_tickets_not_sold"),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to list entry slots", l.Error(err))
+		return
+	}
+
+	response := models.ListEntrySlotsRes{
+		Slots: []*models.EntrySlotRes{},
+	}
+	for _, slot := range slots {
+		response.Slots = append(response.Slots, &models.EntrySlotRes{
+			StartsAt: slot.StartsAt.Format(time.RFC3339),
+			EndsAt:   slot.EndsAt.Format(time.RFC3339),
+			Free:     slot.Free,
+		})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *HandlerV1) checkAttraction(ctx context.Context, attractionID string) (int, error) {
+	_, err := h.Service.EstablishmentService().GetAttraction(ctx, &pbe.GetAttractionRequest{
+		AttractionId: attractionID,
+	})
+	if err != nil {
+		h.Logger.Error("failed to get attraction", l.Error(err))
+		return http.StatusNotFound, i18n.NewError("attraction_not_found")
+	}
+	return http.StatusOK, nil
+}
+
+// issueTickets sells the tickets of an attraction booking before it is sent
+// to the booking service. Attractions without entry settings keep the plain
+// date range booking, so nil tickets and a nil error are returned for them.
+func (h *HandlerV1) issueTickets(ctx context.Context, bookingID string, body *models.CreateBookingReq) ([]*entity.Ticket, int, error) {
+	settings, err := h.AttractionTicket.GetSettings(ctx, body.HraId)
+	if errors.Is(err, errorspkg.ErrorNotFound) {
+		if len(body.Tickets) == 0 {
+			return nil, http.StatusOK, nil
+		}
+		return nil, http.StatusBadRequest, i18n.NewError("timed_tickets_not_sold")
+	}
+	if err != nil {
+		h.Logger.Error("failed to get entry settings", l.Error(err))
+		return nil, http.StatusInternalServerError, i18n.NewError("try_again_later")
+	}
+
+	entryAt, dateOnly, parseErr := booktime.Parse(body.WillArrive)
+	if parseErr != nil || dateOnly {
+		return nil, http.StatusBadRequest, i18n.NewError("field_needs_time", "will_arrive")
+	}
+
+	// without a breakdown every person gets an adult ticket
+	quantities := map[string]int{}
+	for _, ticket := range body.Tickets {
+		quantities[ticket.Kind] += ticket.Quantity
+	}
+	if len(body.Tickets) == 0 {
+		quantities[entity.TicketKindAdult] = int(body.NumberOfPeople)
+	}
+
+	tickets, err := h.AttractionTicket.Issue(ctx, bookingID, body.HraId, entryAt, quantities)
+	var errBadRequest *errorspkg.ErrBadRequest
+	switch {
+	case errors.As(err, &errBadRequest):
+		return nil, http.StatusBadRequest, err
+	case errors.Is(err, errorspkg.ErrorNotAvailable):
+		return nil, http.StatusConflict, i18n.NewError("no_tickets_left")
+	case err != nil:
+		h.Logger.Error("failed to issue tickets", l.Error(err))
+		return nil, http.StatusInternalServerError, i18n.NewError("try_again_later")
+	}
+
+	body.NumberOfPeople = int64(len(tickets))
+	body.WillLeave = entryAt.Add(settings.SlotLength).Format("2006-01-02T15:04:05")
+
+	return tickets, http.StatusOK, nil
+}
+
+func (h *HandlerV1) cancelTickets(ctx context.Context, bookingID string) {
+	if err := h.AttractionTicket.Cancel(ctx, bookingID); err != nil {
+		h.Logger.Error("failed to cancel tickets", l.Error(err))
+	}
+}
+
+func ticketTypeRes(ticketType *entity.TicketType) *models.TicketTypeRes {
+	return &models.TicketTypeRes{
+		Id:           ticketType.ID,
+		AttractionId: ticketType.AttractionID,
+		Kind:         ticketType.Kind,
+		Price:        ticketType.Price,
+		CreatedAt:    ticketType.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:    ticketType.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func ticketsRes(tickets []*entity.Ticket) []*models.TicketRes {
+	var response []*models.TicketRes
+	for _, ticket := range tickets {
+		response = append(response, &models.TicketRes{
+			Id:      ticket.ID,
+			Kind:    ticket.Kind,
+			Price:   ticket.Price,
+			Code:    ticket.Code,
+			EntryAt: ticket.EntryAt.Format(time.RFC3339),
+		})
+	}
+	return response
+}
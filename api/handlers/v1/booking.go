@@ -5,8 +5,10 @@ import (
 	pbb "Booking/api-service-booking/genproto/booking-proto"
 	pbe "Booking/api-service-booking/genproto/establishment-proto"
 	pbu "Booking/api-service-booking/genproto/user-proto"
+	"Booking/api-service-booking/internal/pkg/ical"
 	l "Booking/api-service-booking/internal/pkg/logger"
 	"Booking/api-service-booking/internal/pkg/otlp"
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -45,9 +47,7 @@ func (h *HandlerV1) UHBCreate(c *gin.Context) {
 
 	err := c.ShouldBindJSON(&body)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Not true form in booking hotel",
-		})
+		h.bindError(c, err)
 		l.Error(err)
 		return
 	}
@@ -55,42 +55,20 @@ func (h *HandlerV1) UHBCreate(c *gin.Context) {
 	userID, statusCode := GetIdFromToken(c.Request, h.Config)
 	if statusCode == 401 {
 		c.JSON(http.StatusUnauthorized, models.Error{
-			Message: "Log In Again",
+			Message: h.message(c, "log_in_again"),
 		})
 		return
 	}
 
-	response, err := h.Service.BookingService().UHBCreate(ctx, &pbb.GeneralBook{
-		Id:             uuid.NewString(),
-		UserId:         userID,
-		HraId:          body.HraId,
-		WillArrive:     body.WillArrive,
-		WillLeave:      body.WillLeave,
-		NumberOfPeople: body.NumberOfPeople,
-		IsCanceled:     body.IsCanceled,
-		Reason:         body.Reason,
-		CreatedAt:      time.Now().Format("2006-01-02T15:04:05"),
-	})
-
+	response, statusCode, err := h.placeBooking(ctx, categoryHotel, userID, uuid.NewString(), &body)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Try Again Later...",
+		c.JSON(statusCode, gin.H{
+			"error": h.errorMessage(c, err),
 		})
-		l.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, &models.BookingRes{
-		Id:             uuid.MustParse(response.Id),
-		UserId:         response.UserId,
-		HraId:          response.HraId,
-		WillArrive:     response.WillArrive,
-		WillLeave:      response.WillLeave,
-		NumberOfPeople: response.NumberOfPeople,
-		IsCanceled:     response.IsCanceled,
-		Reason:         response.Reason,
-		CreatedAt:      response.CreatedAt,
-	})
+	c.JSON(statusCode, response)
 }
 
 // Create Restaurant Booking
@@ -120,9 +98,7 @@ func (h *HandlerV1) URBCreate(c *gin.Context) {
 
 	err := c.ShouldBindJSON(&body)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Not true form in booking restaurant",
-		})
+		h.bindError(c, err)
 		l.Error(err)
 		return
 	}
@@ -130,42 +106,20 @@ func (h *HandlerV1) URBCreate(c *gin.Context) {
 	userID, statusCode := GetIdFromToken(c.Request, h.Config)
 	if statusCode == 401 {
 		c.JSON(http.StatusUnauthorized, models.Error{
-			Message: "Log In Again",
+			Message: h.message(c, "log_in_again"),
 		})
 		return
 	}
 
-	response, err := h.Service.BookingService().URBCreate(ctx, &pbb.GeneralBook{
-		Id:             uuid.NewString(),
-		UserId:         userID,
-		HraId:          body.HraId,
-		WillArrive:     body.WillArrive,
-		WillLeave:      body.WillLeave,
-		NumberOfPeople: body.NumberOfPeople,
-		IsCanceled:     body.IsCanceled,
-		Reason:         body.Reason,
-		CreatedAt:      time.Now().Format("2006-01-02T15:04:05"),
-	})
-
+	response, statusCode, err := h.placeBooking(ctx, categoryRestaurant, userID, uuid.NewString(), &body)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Try Again Later...",
+		c.JSON(statusCode, gin.H{
+			"error": h.errorMessage(c, err),
 		})
-		l.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, &models.BookingRes{
-		Id:             uuid.MustParse(response.Id),
-		UserId:         response.UserId,
-		HraId:          response.HraId,
-		WillArrive:     response.WillArrive,
-		WillLeave:      response.WillLeave,
-		NumberOfPeople: response.NumberOfPeople,
-		IsCanceled:     response.IsCanceled,
-		Reason:         response.Reason,
-		CreatedAt:      response.CreatedAt,
-	})
+	c.JSON(statusCode, response)
 }
 
 // Create Attraction Booking
@@ -195,9 +149,7 @@ func (h *HandlerV1) UABCreate(c *gin.Context) {
 
 	err := c.ShouldBindJSON(&body)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Not true form in booking attraction",
-		})
+		h.bindError(c, err)
 		l.Error(err)
 		return
 	}
@@ -205,42 +157,20 @@ func (h *HandlerV1) UABCreate(c *gin.Context) {
 	userID, statusCode := GetIdFromToken(c.Request, h.Config)
 	if statusCode == 401 {
 		c.JSON(http.StatusUnauthorized, models.Error{
-			Message: "Log In Again",
+			Message: h.message(c, "log_in_again"),
 		})
 		return
 	}
 
-	response, err := h.Service.BookingService().UABCreate(ctx, &pbb.GeneralBook{
-		Id:             uuid.NewString(),
-		UserId:         userID,
-		HraId:          body.HraId,
-		WillArrive:     body.WillArrive,
-		WillLeave:      body.WillLeave,
-		NumberOfPeople: body.NumberOfPeople,
-		IsCanceled:     body.IsCanceled,
-		Reason:         body.Reason,
-		CreatedAt:      time.Now().Format("2006-01-02T15:04:05"),
-	})
-
+	response, statusCode, err := h.placeBooking(ctx, categoryAttraction, userID, uuid.NewString(), &body)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Try Again Later...",
+		c.JSON(statusCode, gin.H{
+			"error": h.errorMessage(c, err),
 		})
-		l.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, &models.BookingRes{
-		Id:             uuid.MustParse(response.Id),
-		UserId:         response.UserId,
-		HraId:          response.HraId,
-		WillArrive:     response.WillArrive,
-		WillLeave:      response.WillLeave,
-		NumberOfPeople: response.NumberOfPeople,
-		IsCanceled:     response.IsCanceled,
-		Reason:         response.Reason,
-		CreatedAt:      response.CreatedAt,
-	})
+	c.JSON(statusCode, response)
 }
 
 // Get All Hotels By User Id
@@ -271,9 +201,7 @@ func (h *HandlerV1) UHBGetAllByUId(c *gin.Context) {
 
 	err := c.ShouldBindQuery(&body)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Not true form of request",
-		})
+		h.bindError(c, err)
 		l.Error(err)
 		return
 	}
@@ -283,7 +211,7 @@ func (h *HandlerV1) UHBGetAllByUId(c *gin.Context) {
 		userID, statusCode := GetIdFromToken(c.Request, h.Config)
 		if statusCode != http.StatusOK {
 			c.JSON(statusCode, gin.H{
-				"error": "Can't get user",
+				"error": h.message(c, "cant_get_user"),
 			})
 			return
 		}
@@ -300,7 +228,7 @@ func (h *HandlerV1) UHBGetAllByUId(c *gin.Context) {
 		})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Try Again Later...",
+			"error": h.message(c, "try_again_later"),
 		})
 		l.Error(err)
 		return
@@ -316,7 +244,7 @@ func (h *HandlerV1) UHBGetAllByUId(c *gin.Context) {
 				continue
 			}
 			c.JSON(http.StatusExpectationFailed, gin.H{
-				"error": "Failed to retrieve hotel details",
+				"error": h.message(c, "hotel_details_failed"),
 			})
 			l.Error(err)
 			return
@@ -355,9 +283,7 @@ func (h *HandlerV1) URBGetAllByUId(c *gin.Context) {
 
 	err := c.ShouldBindQuery(&body)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Not true form of request",
-		})
+		h.bindError(c, err)
 		l.Error(err)
 		return
 	}
@@ -367,7 +293,7 @@ func (h *HandlerV1) URBGetAllByUId(c *gin.Context) {
 		userID, statusCode := GetIdFromToken(c.Request, h.Config)
 		if statusCode != http.StatusOK {
 			c.JSON(statusCode, gin.H{
-				"error": "Can't get user",
+				"error": h.message(c, "cant_get_user"),
 			})
 			return
 		}
@@ -384,7 +310,7 @@ func (h *HandlerV1) URBGetAllByUId(c *gin.Context) {
 		})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Try Again Later...",
+			"error": h.message(c, "try_again_later"),
 		})
 		l.Error(err)
 		return
@@ -400,7 +326,7 @@ func (h *HandlerV1) URBGetAllByUId(c *gin.Context) {
 				continue
 			}
 			c.JSON(http.StatusExpectationFailed, gin.H{
-				"error": "Failed to retrieve restaurant details",
+				"error": h.message(c, "restaurant_details_failed"),
 			})
 			l.Error(err)
 			return
@@ -439,9 +365,7 @@ func (h *HandlerV1) UABGetAllByUId(c *gin.Context) {
 
 	err := c.ShouldBindQuery(&body)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Not true form of request",
-		})
+		h.bindError(c, err)
 		l.Error(err)
 		return
 	}
@@ -451,7 +375,7 @@ func (h *HandlerV1) UABGetAllByUId(c *gin.Context) {
 		userID, statusCode := GetIdFromToken(c.Request, h.Config)
 		if statusCode != http.StatusOK {
 			c.JSON(statusCode, gin.H{
-				"error": "Can't get user",
+				"error": h.message(c, "cant_get_user"),
 			})
 			return
 		}
@@ -468,7 +392,7 @@ func (h *HandlerV1) UABGetAllByUId(c *gin.Context) {
 		})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Try Again Later...",
+			"error": h.message(c, "try_again_later"),
 		})
 		l.Error(err)
 		return
@@ -484,7 +408,7 @@ func (h *HandlerV1) UABGetAllByUId(c *gin.Context) {
 				continue
 			}
 			c.JSON(http.StatusExpectationFailed, gin.H{
-				"error": "Failed to retrieve restaurant details",
+				"error": h.message(c, "restaurant_details_failed"),
 			})
 			l.Error(err)
 			return
@@ -523,9 +447,7 @@ func (h *HandlerV1) UHBGetAllByHId(c *gin.Context) {
 
 	err := c.ShouldBindQuery(&body)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Not true form of request",
-		})
+		h.bindError(c, err)
 		l.Error(err)
 		return
 	}
@@ -542,12 +464,14 @@ func (h *HandlerV1) UHBGetAllByHId(c *gin.Context) {
 		})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Try Again Later...",
+			"error": h.message(c, "try_again_later"),
 		})
 		l.Error(err)
 		return
 	}
 
+	bookedAt := h.lastBookedAt(ctx, categoryHotel, id, response.UserId)
+
 	var usersDetails []*models.BookedUser
 	for _, booking := range response.UserId {
 		userResponse, err := h.Service.UserService().Get(ctx, &pbu.Filter{
@@ -560,26 +484,16 @@ func (h *HandlerV1) UHBGetAllByHId(c *gin.Context) {
 				continue
 			}
 			c.JSON(http.StatusExpectationFailed, gin.H{
-				"error": "Failed to retrieve user details",
-			})
-			l.Error(err)
-			return
-		}
-		createdAt, err := time.Parse(time.RFC3339, userResponse.User.CreatedAt)
-		if err != nil {
-			c.JSON(http.StatusExpectationFailed, gin.H{
-				"error": "Failed to parse created_at timestamp",
+				"error": h.message(c, "user_details_failed"),
 			})
 			l.Error(err)
 			return
 		}
-		createdAt = createdAt.Add(time.Minute)
-		incrementedCreatedAt := createdAt.Format(time.RFC3339)
 		usersDetails = append(usersDetails, &models.BookedUser{
 			FullName:    userResponse.User.FullName,
 			Email:       userResponse.User.Email,
 			PhoneNumber: userResponse.User.PhoneNumber,
-			BookedTime:  incrementedCreatedAt,
+			BookedTime:  bookedAt[booking.Id],
 		})
 	}
 
@@ -614,9 +528,7 @@ func (h *HandlerV1) URBGetAllByRId(c *gin.Context) {
 
 	err := c.ShouldBindQuery(&body)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Not true form of request",
-		})
+		h.bindError(c, err)
 		l.Error(err)
 		return
 	}
@@ -633,12 +545,14 @@ func (h *HandlerV1) URBGetAllByRId(c *gin.Context) {
 		})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Try Again Later...",
+			"error": h.message(c, "try_again_later"),
 		})
 		l.Error(err)
 		return
 	}
 
+	bookedAt := h.lastBookedAt(ctx, categoryRestaurant, id, response.UserId)
+
 	var usersDetails []*models.BookedUser
 	for _, booking := range response.UserId {
 		userResponse, err := h.Service.UserService().Get(ctx, &pbu.Filter{
@@ -651,26 +565,16 @@ func (h *HandlerV1) URBGetAllByRId(c *gin.Context) {
 				continue
 			}
 			c.JSON(http.StatusExpectationFailed, gin.H{
-				"error": "Failed to retrieve user details",
-			})
-			l.Error(err)
-			return
-		}
-		createdAt, err := time.Parse(time.RFC3339, userResponse.User.CreatedAt)
-		if err != nil {
-			c.JSON(http.StatusExpectationFailed, gin.H{
-				"error": "Failed to parse created_at timestamp",
+				"error": h.message(c, "user_details_failed"),
 			})
 			l.Error(err)
 			return
 		}
-		createdAt = createdAt.Add(time.Minute)
-		incrementedCreatedAt := createdAt.Format(time.RFC3339)
 		usersDetails = append(usersDetails, &models.BookedUser{
 			FullName:    userResponse.User.FullName,
 			Email:       userResponse.User.Email,
 			PhoneNumber: userResponse.User.PhoneNumber,
-			BookedTime:  incrementedCreatedAt,
+			BookedTime:  bookedAt[booking.Id],
 		})
 	}
 
@@ -705,9 +609,7 @@ func (h *HandlerV1) UABGetAllByAId(c *gin.Context) {
 
 	err := c.ShouldBindQuery(&bodyPL)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Not true form of request",
-		})
+		h.bindError(c, err)
 		l.Error(err)
 		return
 	}
@@ -724,12 +626,14 @@ func (h *HandlerV1) UABGetAllByAId(c *gin.Context) {
 		})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": err.Error(),
+			"error": h.errorMessage(c, err),
 		})
 		l.Error(err)
 		return
 	}
 
+	bookedAt := h.lastBookedAt(ctx, categoryAttraction, id, response.UserId)
+
 	var usersDetails []*models.BookedUser
 	for _, booking := range response.UserId {
 		userResponse, err := h.Service.UserService().Get(ctx, &pbu.Filter{
@@ -742,32 +646,42 @@ func (h *HandlerV1) UABGetAllByAId(c *gin.Context) {
 				continue
 			}
 			c.JSON(http.StatusExpectationFailed, gin.H{
-				"error": "Failed to retrieve user details",
+				"error": h.message(c, "user_details_failed"),
 			})
 			l.Error(err)
 			return
 		}
-		createdAt, err := time.Parse(time.RFC3339, userResponse.User.CreatedAt)
-		if err != nil {
-			c.JSON(http.StatusExpectationFailed, gin.H{
-				"error": "Failed to parse created_at timestamp",
-			})
-			l.Error(err)
-			return
-		}
-		createdAt = createdAt.Add(time.Minute)
-		incrementedCreatedAt := createdAt.Format(time.RFC3339)
 		usersDetails = append(usersDetails, &models.BookedUser{
 			FullName:    userResponse.User.FullName,
 			Email:       userResponse.User.Email,
 			PhoneNumber: userResponse.User.PhoneNumber,
-			BookedTime:  incrementedCreatedAt,
+			BookedTime:  bookedAt[booking.Id],
 		})
 	}
 
 	c.JSON(http.StatusOK, usersDetails)
 }
 
+// lastBookedAt is when each of users last booked establishmentID, as
+// kept in the booking records. Users without one are left out.
+func (h *HandlerV1) lastBookedAt(ctx context.Context, category, establishmentID string, users []*pbb.Id) map[string]string {
+	userIDs := make([]string, 0, len(users))
+	for _, user := range users {
+		userIDs = append(userIDs, user.Id)
+	}
+
+	bookedAt := make(map[string]string)
+	times, err := h.BookingRecord.LastBookedAt(ctx, category, establishmentID, userIDs)
+	if err != nil {
+		h.Logger.Error("failed to get booking times", l.Error(err))
+		return bookedAt
+	}
+	for userID, createdAt := range times {
+		bookedAt[userID] = createdAt.Format(time.RFC3339)
+	}
+	return bookedAt
+}
+
 // List Hotels
 // @Summary List Hotels
 // @Security BearerAuth
@@ -795,9 +709,7 @@ func (h *HandlerV1) UHBList(c *gin.Context) {
 
 	err := c.ShouldBindQuery(&body)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Not true form of request",
-		})
+		h.bindError(c, err)
 		l.Error(err)
 		return
 	}
@@ -809,7 +721,7 @@ func (h *HandlerV1) UHBList(c *gin.Context) {
 		})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Try Again Later...",
+			"error": h.message(c, "try_again_later"),
 		})
 		l.Error(err)
 		return
@@ -844,9 +756,7 @@ func (h *HandlerV1) URBList(c *gin.Context) {
 
 	err := c.ShouldBindQuery(&body)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Not true form of request",
-		})
+		h.bindError(c, err)
 		l.Error(err)
 		return
 	}
@@ -858,7 +768,7 @@ func (h *HandlerV1) URBList(c *gin.Context) {
 		})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Try Again Later...",
+			"error": h.message(c, "try_again_later"),
 		})
 		l.Error(err)
 		return
@@ -893,9 +803,7 @@ func (h *HandlerV1) UABList(c *gin.Context) {
 
 	err := c.ShouldBindQuery(&body)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Not true form of request",
-		})
+		h.bindError(c, err)
 		l.Error(err)
 		return
 	}
@@ -907,7 +815,7 @@ func (h *HandlerV1) UABList(c *gin.Context) {
 		})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Try Again Later...",
+			"error": h.message(c, "try_again_later"),
 		})
 		l.Error(err)
 		return
@@ -942,9 +850,7 @@ func (h *HandlerV1) UHBListDeleted(c *gin.Context) {
 
 	err := c.ShouldBindQuery(&body)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Not true form of request",
-		})
+		h.bindError(c, err)
 		l.Error(err)
 		return
 	}
@@ -956,7 +862,7 @@ func (h *HandlerV1) UHBListDeleted(c *gin.Context) {
 		})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Try Again Later...",
+			"error": h.message(c, "try_again_later"),
 		})
 		l.Error(err)
 		return
@@ -991,9 +897,7 @@ func (h *HandlerV1) URBListDeleted(c *gin.Context) {
 
 	err := c.ShouldBindQuery(&body)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Not true form of request",
-		})
+		h.bindError(c, err)
 		l.Error(err)
 		return
 	}
@@ -1005,7 +909,7 @@ func (h *HandlerV1) URBListDeleted(c *gin.Context) {
 		})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Try Again Later...",
+			"error": h.message(c, "try_again_later"),
 		})
 		l.Error(err)
 		return
@@ -1040,9 +944,7 @@ func (h *HandlerV1) UABListDeleted(c *gin.Context) {
 
 	err := c.ShouldBindQuery(&body)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Not true form of request",
-		})
+		h.bindError(c, err)
 		l.Error(err)
 		return
 	}
@@ -1054,7 +956,7 @@ func (h *HandlerV1) UABListDeleted(c *gin.Context) {
 		})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Try Again Later...",
+			"error": h.message(c, "try_again_later"),
 		})
 		l.Error(err)
 		return
@@ -1065,13 +967,15 @@ func (h *HandlerV1) UABListDeleted(c *gin.Context) {
 // Update Booked Hotel
 // @Summary Update Booked Hotel
 // @Security BearerAuth
-// @Description Api for Update Booked Hotel
+// @Description Api for changing the dates or party size of a booked hotel, or canceling it. The booking is priced again and the change kept in its history
 // @Tags BOOKING_HOTEL
 // @Accept json
 // @Produce json
 // @Param models.UpdateBookingReq body models.UpdateBookingReq true "createModel"
 // @Success 200 {object} models.BookingRes
 // @Failure 400 {object} models.StandartError
+// @Failure 404 {object} models.StandartError
+// @Failure 409 {object} models.StandartError
 // @Failure 500 {object} models.StandartError
 // @Router /v1/booking/hotels [put]
 func (h *HandlerV1) UHBUpdate(c *gin.Context) {
@@ -1081,71 +985,37 @@ func (h *HandlerV1) UHBUpdate(c *gin.Context) {
 	)
 	defer span.End()
 
-	var (
-		body        models.UpdateBookingReq
-		jsonMarshal protojson.MarshalOptions
-	)
-	jsonMarshal.UseProtoNames = true
-
+	var body models.UpdateBookingReq
 	err := c.ShouldBindJSON(&body)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Not true form of request",
-		})
+		h.bindError(c, err)
 		h.Logger.Error("failed to bind json", l.Error(err))
 		return
 	}
-	userId, statusCode := GetIdFromToken(c.Request, h.Config)
-	if statusCode != http.StatusOK {
-		c.JSON(statusCode, gin.H{
-			"error": "Can't get user",
-		})
-		return
-	}
 
-	response, err := h.Service.BookingService().UHBUpdate(ctx, &pbb.GeneralBook{
-		Id:             body.Id.String(),
-		UserId:         userId,
-		HraId:          body.HraId,
-		WillArrive:     body.WillArrive,
-		WillLeave:      body.WillLeave,
-		NumberOfPeople: body.NumberOfPeople,
-		IsCanceled:     body.IsCanceled,
-		Reason:         body.Reason,
-		CreatedAt:      time.Now().Format("2006-01-02T15:04:05"),
-		UpdatedAt:      time.Now().Format("2006-01-02T15:04:05"),
-	})
+	response, statusCode, err := h.modifyBooking(ctx, c.Request, categoryHotel, &body)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Try Again Later...",
+		c.JSON(statusCode, gin.H{
+			"error": h.errorMessage(c, err),
 		})
-		h.Logger.Error("failed to update booked hotel", l.Error(err))
 		return
 	}
 
-	c.JSON(http.StatusOK, &models.BookingRes{
-		Id:             uuid.MustParse(response.Id),
-		UserId:         response.UserId,
-		HraId:          response.HraId,
-		WillArrive:     response.WillArrive,
-		WillLeave:      response.WillLeave,
-		NumberOfPeople: response.NumberOfPeople,
-		IsCanceled:     response.IsCanceled,
-		Reason:         response.Reason,
-		CreatedAt:      response.CreatedAt,
-	})
+	c.JSON(http.StatusOK, response)
 }
 
 // Update Booked Restaurant
 // @Summary Update Booked Restaurant
 // @Security BearerAuth
-// @Description Api for Update Booked Restaurant
+// @Description Api for changing the seating time or party size of a booked restaurant, or canceling it. A table is found for the new time, the booking is priced again and the change kept in its history
 // @Tags BOOKING_RESTAURANT
 // @Accept json
 // @Produce json
 // @Param models.UpdateBookingReq body models.UpdateBookingReq true "createModel"
 // @Success 200 {object} models.BookingRes
 // @Failure 400 {object} models.StandartError
+// @Failure 404 {object} models.StandartError
+// @Failure 409 {object} models.StandartError
 // @Failure 500 {object} models.StandartError
 // @Router /v1/booking/restaurants [put]
 func (h *HandlerV1) URBUpdate(c *gin.Context) {
@@ -1155,71 +1025,37 @@ func (h *HandlerV1) URBUpdate(c *gin.Context) {
 	)
 	defer span.End()
 
-	var (
-		body        models.UpdateBookingReq
-		jsonMarshal protojson.MarshalOptions
-	)
-	jsonMarshal.UseProtoNames = true
-
+	var body models.UpdateBookingReq
 	err := c.ShouldBindJSON(&body)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Not true form of request",
-		})
+		h.bindError(c, err)
 		h.Logger.Error("failed to bind json", l.Error(err))
 		return
 	}
-	userId, statusCode := GetIdFromToken(c.Request, h.Config)
-	if statusCode != http.StatusOK {
-		c.JSON(statusCode, gin.H{
-			"error": "Can't get user",
-		})
-		return
-	}
 
-	response, err := h.Service.BookingService().URBUpdate(ctx, &pbb.GeneralBook{
-		Id:             body.Id.String(),
-		UserId:         userId,
-		HraId:          body.HraId,
-		WillArrive:     body.WillArrive,
-		WillLeave:      body.WillLeave,
-		NumberOfPeople: body.NumberOfPeople,
-		IsCanceled:     body.IsCanceled,
-		Reason:         body.Reason,
-		CreatedAt:      time.Now().Format("2006-01-02T15:04:05"),
-		UpdatedAt:      time.Now().Format("2006-01-02T15:04:05"),
-	})
+	response, statusCode, err := h.modifyBooking(ctx, c.Request, categoryRestaurant, &body)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Try Again Later...",
+		c.JSON(statusCode, gin.H{
+			"error": h.errorMessage(c, err),
 		})
-		h.Logger.Error("failed to update booked restaurant", l.Error(err))
 		return
 	}
 
-	c.JSON(http.StatusOK, &models.BookingRes{
-		Id:             uuid.MustParse(response.Id),
-		UserId:         response.UserId,
-		HraId:          response.HraId,
-		WillArrive:     response.WillArrive,
-		WillLeave:      response.WillLeave,
-		NumberOfPeople: response.NumberOfPeople,
-		IsCanceled:     response.IsCanceled,
-		Reason:         response.Reason,
-		CreatedAt:      response.CreatedAt,
-	})
+	c.JSON(http.StatusOK, response)
 }
 
 // Update Booked Attraction
 // @Summary Update Booked Attraction
 // @Security BearerAuth
-// @Description Api for Update Booked Attraction
+// @Description Api for changing the entry time or tickets of a booked attraction, or canceling it. Tickets are checked against the new slot, the booking is priced again and the change kept in its history
 // @Tags BOOKING_ATTRACTION
 // @Accept json
 // @Produce json
 // @Param models.UpdateBookingReq body models.UpdateBookingReq true "createModel"
 // @Success 200 {object} models.BookingRes
 // @Failure 400 {object} models.StandartError
+// @Failure 404 {object} models.StandartError
+// @Failure 409 {object} models.StandartError
 // @Failure 500 {object} models.StandartError
 // @Router /v1/booking/attractions [put]
 func (h *HandlerV1) UABUpdate(c *gin.Context) {
@@ -1229,59 +1065,23 @@ func (h *HandlerV1) UABUpdate(c *gin.Context) {
 	)
 	defer span.End()
 
-	var (
-		body        models.UpdateBookingReq
-		jsonMarshal protojson.MarshalOptions
-	)
-	jsonMarshal.UseProtoNames = true
-
+	var body models.UpdateBookingReq
 	err := c.ShouldBindJSON(&body)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Not true form of request",
-		})
+		h.bindError(c, err)
 		h.Logger.Error("failed to bind json", l.Error(err))
 		return
 	}
-	userId, statusCode := GetIdFromToken(c.Request, h.Config)
-	if statusCode != http.StatusOK {
-		c.JSON(statusCode, gin.H{
-			"error": "Can't get user",
-		})
-		return
-	}
 
-	response, err := h.Service.BookingService().UABUpdate(ctx, &pbb.GeneralBook{
-		Id:             body.Id.String(),
-		UserId:         userId,
-		HraId:          body.HraId,
-		WillArrive:     body.WillArrive,
-		WillLeave:      body.WillLeave,
-		NumberOfPeople: body.NumberOfPeople,
-		IsCanceled:     body.IsCanceled,
-		Reason:         body.Reason,
-		CreatedAt:      time.Now().Format("2006-01-02T15:04:05"),
-		UpdatedAt:      time.Now().Format("2006-01-02T15:04:05"),
-	})
+	response, statusCode, err := h.modifyBooking(ctx, c.Request, categoryAttraction, &body)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Try Again Later...",
+		c.JSON(statusCode, gin.H{
+			"error": h.errorMessage(c, err),
 		})
-		h.Logger.Error("failed to update booked attraction", l.Error(err))
 		return
 	}
 
-	c.JSON(http.StatusOK, &models.BookingRes{
-		Id:             uuid.MustParse(response.Id),
-		UserId:         response.UserId,
-		HraId:          response.HraId,
-		WillArrive:     response.WillArrive,
-		WillLeave:      response.WillLeave,
-		NumberOfPeople: response.NumberOfPeople,
-		IsCanceled:     response.IsCanceled,
-		Reason:         response.Reason,
-		CreatedAt:      response.CreatedAt,
-	})
+	c.JSON(http.StatusOK, response)
 }
 
 // Delete Hotel
@@ -1307,6 +1107,11 @@ func (h *HandlerV1) UHBDelete(c *gin.Context) {
 	jsonMarshal.UseProtoNames = true
 
 	id := c.Query("id")
+	userID, statusCode := GetIdFromToken(c.Request, h.Config)
+	if statusCode != http.StatusOK {
+		userID = ""
+	}
+	book := h.canceledBook(ctx, categoryHotel, id, userID)
 
 	_, err := h.Service.BookingService().UHBDelete(
 		ctx, &pbb.Id{
@@ -1314,12 +1119,20 @@ func (h *HandlerV1) UHBDelete(c *gin.Context) {
 		})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Try Again Later...",
+			"error": h.message(c, "try_again_later"),
 		})
 		h.Logger.Error("failed to delete booked hotel", l.Error(err))
 		return
 	}
 
+	h.releaseRooms(ctx, id)
+	role, _ := GetRoleFromToken(c.Request, h.Config)
+	h.cancelBookingRecord(ctx, id, userID, role)
+
+	if book != nil {
+		go h.sendBookingMail(categoryHotel, book, ical.MethodCancel, true)
+	}
+
 	c.JSON(http.StatusOK, "successfully canceled...")
 }
 
@@ -1346,6 +1159,11 @@ func (h *HandlerV1) URBDelete(c *gin.Context) {
 	jsonMarshal.UseProtoNames = true
 
 	id := c.Query("id")
+	userID, statusCode := GetIdFromToken(c.Request, h.Config)
+	if statusCode != http.StatusOK {
+		userID = ""
+	}
+	book := h.canceledBook(ctx, categoryRestaurant, id, userID)
 
 	_, err := h.Service.BookingService().URBDelete(
 		ctx, &pbb.Id{
@@ -1353,12 +1171,20 @@ func (h *HandlerV1) URBDelete(c *gin.Context) {
 		})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Try Again Later...",
+			"error": h.message(c, "try_again_later"),
 		})
 		h.Logger.Error("failed to delete booked restaurant", l.Error(err))
 		return
 	}
 
+	h.releaseTable(ctx, id)
+	role, _ := GetRoleFromToken(c.Request, h.Config)
+	h.cancelBookingRecord(ctx, id, userID, role)
+
+	if book != nil {
+		go h.sendBookingMail(categoryRestaurant, book, ical.MethodCancel, true)
+	}
+
 	c.JSON(http.StatusOK, "successfully canceled...")
 }
 
@@ -1385,6 +1211,11 @@ func (h *HandlerV1) UABDelete(c *gin.Context) {
 	jsonMarshal.UseProtoNames = true
 
 	id := c.Query("id")
+	userID, statusCode := GetIdFromToken(c.Request, h.Config)
+	if statusCode != http.StatusOK {
+		userID = ""
+	}
+	book := h.canceledBook(ctx, categoryAttraction, id, userID)
 
 	_, err := h.Service.BookingService().UABDelete(
 		ctx, &pbb.Id{
@@ -1392,11 +1223,19 @@ func (h *HandlerV1) UABDelete(c *gin.Context) {
 		})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Try Again Later...",
+			"error": h.message(c, "try_again_later"),
 		})
 		h.Logger.Error("failed to delete booked attraction", l.Error(err))
 		return
 	}
 
+	h.cancelTickets(ctx, id)
+	role, _ := GetRoleFromToken(c.Request, h.Config)
+	h.cancelBookingRecord(ctx, id, userID, role)
+
+	if book != nil {
+		go h.sendBookingMail(categoryAttraction, book, ical.MethodCancel, true)
+	}
+
 	c.JSON(http.StatusOK, "successfully canceled...")
 }
@@ -0,0 +1,228 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	pbb "Booking/api-service-booking/genproto/booking-proto"
+	pbu "Booking/api-service-booking/genproto/user-proto"
+	"Booking/api-service-booking/internal/entity"
+	l "Booking/api-service-booking/internal/pkg/logger"
+	scode "Booking/api-service-booking/internal/pkg/sendcode"
+	"Booking/api-service-booking/internal/usecase/scheduler"
+)
+
+// reminders are sent this long before the guest arrives
+var reminderOffsets = []time.Duration{24 * time.Hour, 2 * time.Hour}
+
+// RegisterJobs attaches the job handlers to the scheduler
+func (h *HandlerV1) RegisterJobs(s scheduler.Scheduler) {
+	s.Handle(entity.JobKindBookingReminder, h.remindBooking)
+	s.Handle(entity.JobKindBookingNoShow, h.markNoShow)
+	s.Handle(entity.JobKindWaitlistOffer, h.expireWaitlistOffer)
+	s.Handle(entity.JobKindBookingComplete, h.completeBooking)
+	s.Handle(entity.JobKindTripCheckout, h.recoverTripCheckout)
+	s.Handle(entity.JobKindEstablishmentImport, h.runImport)
+	s.Handle(entity.JobKindEstablishmentSnapshot, h.runSnapshot)
+}
+
+// recordBooking keeps a copy of a created booking with its quote, if any,
+// and plans its reminders. Failures are only logged since the booking
+// itself already exists.
+func (h *HandlerV1) recordBooking(ctx context.Context, category string, book *pbb.GeneralBook, quote *entity.Quote) {
+	record := entity.BookingRecord{
+		ID:              book.Id,
+		Category:        category,
+		UserID:          book.UserId,
+		EstablishmentID: book.HraId,
+		WillArrive:      book.WillArrive,
+		WillLeave:       book.WillLeave,
+		NumberOfPeople:  book.NumberOfPeople,
+	}
+	if quote != nil {
+		record.Price = quote.Price
+		record.Discount = quote.Discount
+		record.PromoCode = quote.PromoCode
+		record.Points = quote.Points
+		record.PointsDiscount = quote.PointsDiscount
+	}
+	if book.IsCanceled {
+		record.State = entity.BookingStateCanceled
+	}
+
+	if err := h.BookingRecord.Create(ctx, &record); err != nil {
+		h.Logger.Error("failed to record booking", l.Error(err))
+		return
+	}
+	h.scheduleBookingJobs(ctx, &record)
+}
+
+// updateBookingRecord saves next over current, the copy of a modified
+// booking, and plans its jobs again for the new dates. Capacity given up by
+// a cancellation or move is offered to the waitlist. It reports whether
+// the copy was saved.
+func (h *HandlerV1) updateBookingRecord(ctx context.Context, current, next *entity.BookingRecord) bool {
+	if err := h.BookingRecord.Update(ctx, next); err != nil {
+		h.Logger.Error("failed to update booking record", l.Error(err))
+		return false
+	}
+
+	canceled := next.State == entity.BookingStateCanceled
+	if canceled {
+		h.releaseDiscounts(ctx, next.ID)
+	}
+	if canceled || next.WillArrive != current.WillArrive || next.WillLeave != current.WillLeave ||
+		next.NumberOfPeople < current.NumberOfPeople {
+		h.offerFreedPlace(ctx, current)
+	}
+
+	if err := h.Scheduler.CancelByBooking(ctx, next.ID); err != nil {
+		h.Logger.Error("failed to cancel booking jobs", l.Error(err))
+		return true
+	}
+	h.scheduleBookingJobs(ctx, next)
+	return true
+}
+
+// cancelBookingRecord marks the copy of a deleted booking canceled and
+// keeps who did it in the booking history
+func (h *HandlerV1) cancelBookingRecord(ctx context.Context, id, changedBy, role string) {
+	canceled, err := h.BookingRecord.ChangeState(ctx, id, []string{entity.BookingStateConfirmed}, entity.BookingStateCanceled)
+	if err != nil {
+		h.Logger.Error("failed to cancel booking record", l.Error(err))
+	}
+	if err := h.Scheduler.CancelByBooking(ctx, id); err != nil {
+		h.Logger.Error("failed to cancel booking jobs", l.Error(err))
+	}
+	if !canceled {
+		return
+	}
+	h.releaseDiscounts(ctx, id)
+
+	record, err := h.BookingRecord.Get(ctx, id)
+	if err != nil {
+		h.Logger.Error("failed to get booking record", l.Error(err))
+		return
+	}
+	confirmed := *record
+	confirmed.State = entity.BookingStateConfirmed
+	h.addBookingChange(ctx, changedBy, role, &confirmed, record)
+	h.offerFreedPlace(ctx, record)
+}
+
+func (h *HandlerV1) scheduleBookingJobs(ctx context.Context, record *entity.BookingRecord) {
+	if record.State != entity.BookingStateConfirmed || record.ArriveAt == nil {
+		return
+	}
+
+	jobs := []*entity.Job{{
+		Kind:      entity.JobKindBookingNoShow,
+		BookingID: record.ID,
+		RunAt:     record.ArriveAt.Add(h.Config.Scheduler.NoShowGrace),
+	}}
+	for _, offset := range reminderOffsets {
+		runAt := record.ArriveAt.Add(-offset)
+		if runAt.Before(time.Now()) {
+			continue
+		}
+		jobs = append(jobs, &entity.Job{
+			Kind:      entity.JobKindBookingReminder,
+			BookingID: record.ID,
+			Payload:   map[string]string{"before": offset.String()},
+			RunAt:     runAt,
+		})
+	}
+
+	for _, job := range jobs {
+		if err := h.Scheduler.Schedule(ctx, job); err != nil {
+			h.Logger.Error("failed to schedule booking job", l.Error(err))
+		}
+	}
+}
+
+func (h *HandlerV1) remindBooking(ctx context.Context, job *entity.Job) error {
+	record, err := h.BookingRecord.Get(ctx, job.BookingID)
+	if err != nil {
+		return err
+	}
+	if record.State != entity.BookingStateConfirmed {
+		return nil
+	}
+
+	user, err := h.Service.UserService().Get(ctx, &pbu.Filter{
+		Filter: map[string]string{
+			"id": record.UserID,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	place, err := h.getBookedPlace(ctx, record.Category, record.EstablishmentID)
+	if err != nil {
+		return err
+	}
+
+	before, err := time.ParseDuration(job.Payload["before"])
+	if err != nil {
+		return err
+	}
+
+	return scode.SendBooking(user.User.Email, scode.BookingMail{
+		Title:      fmt.Sprintf("Reminder: your booking starts in %d hours", int(before.Hours())),
+		FullName:   user.User.FullName,
+		Place:      place.Name,
+		Address:    place.Address,
+		WillArrive: record.WillArrive,
+		WillLeave:  record.WillLeave,
+		People:     record.NumberOfPeople,
+		BookingId:  record.ID,
+	}, "", nil)
+}
+
+// markNoShow cancels a booking the guest never checked in for
+func (h *HandlerV1) markNoShow(ctx context.Context, job *entity.Job) error {
+	record, err := h.BookingRecord.Get(ctx, job.BookingID)
+	if err != nil {
+		return err
+	}
+	if record.State != entity.BookingStateConfirmed {
+		return nil
+	}
+
+	_, err = h.updateBackendBooking(ctx, record.Category, &pbb.GeneralBook{
+		Id:             record.ID,
+		UserId:         record.UserID,
+		HraId:          record.EstablishmentID,
+		WillArrive:     record.WillArrive,
+		WillLeave:      record.WillLeave,
+		NumberOfPeople: record.NumberOfPeople,
+		IsCanceled:     true,
+		Reason:         "no-show",
+		CreatedAt:      record.CreatedAt.Format("2006-01-02T15:04:05"),
+		UpdatedAt:      time.Now().Format("2006-01-02T15:04:05"),
+	})
+	if err != nil {
+		return err
+	}
+
+	// the nights left of a stay nobody came for can be let again
+	if record.Category == categoryHotel {
+		h.releaseRooms(ctx, record.ID)
+	}
+	_, err = h.BookingRecord.ChangeState(ctx, record.ID, []string{entity.BookingStateConfirmed}, entity.BookingStateNoShow)
+	return err
+}
+
+func (h *HandlerV1) updateBackendBooking(ctx context.Context, category string, book *pbb.GeneralBook) (*pbb.GeneralBook, error) {
+	switch category {
+	case categoryHotel:
+		return h.Service.BookingService().UHBUpdate(ctx, book)
+	case categoryRestaurant:
+		return h.Service.BookingService().URBUpdate(ctx, book)
+	case categoryAttraction:
+		return h.Service.BookingService().UABUpdate(ctx, book)
+	}
+	return nil, fmt.Errorf("unknown booking category %q", category)
+}
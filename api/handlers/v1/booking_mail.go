@@ -0,0 +1,180 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	pbb "Booking/api-service-booking/genproto/booking-proto"
+	pbe "Booking/api-service-booking/genproto/establishment-proto"
+	pbu "Booking/api-service-booking/genproto/user-proto"
+	errorspkg "Booking/api-service-booking/internal/errors"
+	"Booking/api-service-booking/internal/pkg/booktime"
+	"Booking/api-service-booking/internal/pkg/ical"
+	l "Booking/api-service-booking/internal/pkg/logger"
+	scode "Booking/api-service-booking/internal/pkg/sendcode"
+)
+
+const (
+	categoryHotel      = "hotel"
+	categoryRestaurant = "restaurant"
+	categoryAttraction = "attraction"
+)
+
+// bookedPlace is the part of an establishment shown in booking mails and
+// used to check who owns it
+type bookedPlace struct {
+	OwnerID   string
+	Name      string
+	Address   string
+	City      string
+	Latitude  float64
+	Longitude float64
+}
+
+func (h *HandlerV1) getBookedPlace(ctx context.Context, category, id string) (*bookedPlace, error) {
+	var (
+		ownerID  string
+		name     string
+		location *pbe.Location
+	)
+
+	switch category {
+	case categoryHotel:
+		response, err := h.Service.EstablishmentService().GetHotel(ctx, &pbe.GetHotelRequest{HotelId: id})
+		if err != nil {
+			return nil, err
+		}
+		ownerID, name, location = response.Hotel.OwnerId, response.Hotel.HotelName, response.Hotel.Location
+	case categoryRestaurant:
+		response, err := h.Service.EstablishmentService().GetRestaurant(ctx, &pbe.GetRestaurantRequest{RestaurantId: id})
+		if err != nil {
+			return nil, err
+		}
+		ownerID, name, location = response.Restaurant.OwnerId, response.Restaurant.RestaurantName, response.Restaurant.Location
+	case categoryAttraction:
+		response, err := h.Service.EstablishmentService().GetAttraction(ctx, &pbe.GetAttractionRequest{AttractionId: id})
+		if err != nil {
+			return nil, err
+		}
+		ownerID, name, location = response.Attraction.OwnerId, response.Attraction.AttractionName, response.Attraction.Location
+	default:
+		return nil, fmt.Errorf("unknown booking category %q", category)
+	}
+
+	place := bookedPlace{OwnerID: ownerID, Name: name}
+	if location != nil {
+		place.Address = location.Address
+		place.City = location.City
+		place.Latitude = float64(location.Latitude)
+		place.Longitude = float64(location.Longitude)
+	}
+
+	return &place, nil
+}
+
+// sendBookingMail mails the guest a confirmation, update or cancellation with
+// an iCalendar attachment. The UID is derived from the booking id so every
+// mail about one booking replaces the same calendar entry. It is meant to be
+// run in its own goroutine, the request context is not used.
+func (h *HandlerV1) sendBookingMail(category string, book *pbb.GeneralBook, method ical.Method, updated bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
+	defer cancel()
+
+	user, err := h.Service.UserService().Get(ctx, &pbu.Filter{
+		Filter: map[string]string{
+			"id": book.UserId,
+		},
+	})
+	if err != nil {
+		h.Logger.Error("failed to get user for booking mail", l.Error(err))
+		return
+	}
+
+	event := ical.Event{
+		UID: book.Id + "@touristan",
+		// calendars only apply updates with a higher sequence
+		Sequence:  time.Now().Unix(),
+		Organizer: scode.Sender(),
+		Attendee:  user.User.Email,
+	}
+	mail := scode.BookingMail{
+		FullName:   user.User.FullName,
+		WillArrive: book.WillArrive,
+		WillLeave:  book.WillLeave,
+		People:     book.NumberOfPeople,
+		BookingId:  book.Id,
+		Canceled:   method == ical.MethodCancel,
+	}
+
+	if book.HraId != "" {
+		place, err := h.getBookedPlace(ctx, category, book.HraId)
+		if err != nil {
+			h.Logger.Error("failed to get establishment for booking mail", l.Error(err))
+			return
+		}
+		mail.Place, mail.Address = place.Name, place.Address
+		event.Summary = "Booking: " + place.Name
+		event.Location = place.Address
+		event.Latitude, event.Longitude = place.Latitude, place.Longitude
+	}
+
+	if arrive, dateOnly, err := booktime.Parse(book.WillArrive); err == nil {
+		event.Start, event.AllDay = arrive, dateOnly
+		event.End = arrive
+		if leave, _, err := booktime.Parse(book.WillLeave); err == nil && !leave.Before(arrive) {
+			event.End = leave
+		}
+	}
+	event.Description = fmt.Sprintf("Booking ID: %s\nNumber of people: %d", book.Id, book.NumberOfPeople)
+
+	switch {
+	case method == ical.MethodCancel:
+		mail.Title = "Your booking has been canceled"
+	case updated:
+		mail.Title = "Your booking has been updated"
+	default:
+		mail.Title = "Your booking is confirmed"
+	}
+
+	err = scode.SendBooking(user.User.Email, mail, string(method), ical.Build(method, event))
+	if err != nil {
+		h.Logger.Error("failed to send booking mail", l.Error(err))
+	}
+}
+
+// canceledBook loads a booking that is about to be deleted, so its guest can
+// be mailed the dates they lose. The gateway's record is used, or for
+// bookings made before records were kept, the booking among those of
+// userID. nil when neither has it.
+func (h *HandlerV1) canceledBook(ctx context.Context, category, id, userID string) *pbb.GeneralBook {
+	record, err := h.BookingRecord.Get(ctx, id)
+	if err == nil {
+		return &pbb.GeneralBook{
+			Id:             record.ID,
+			UserId:         record.UserID,
+			HraId:          record.EstablishmentID,
+			WillArrive:     record.WillArrive,
+			WillLeave:      record.WillLeave,
+			NumberOfPeople: record.NumberOfPeople,
+			IsCanceled:     true,
+		}
+	}
+	if !errors.Is(err, errorspkg.ErrorNotFound) {
+		h.Logger.Error("failed to get booking record", l.Error(err))
+		return nil
+	}
+	if userID == "" {
+		return nil
+	}
+
+	book, err := h.findBackendBooking(ctx, category, userID, id)
+	if err != nil {
+		if !errors.Is(err, errorspkg.ErrorNotFound) {
+			h.Logger.Error("failed to get booking for cancel mail", l.Error(err))
+		}
+		return nil
+	}
+	return book
+}
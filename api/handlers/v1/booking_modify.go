@@ -0,0 +1,484 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+
+	"Booking/api-service-booking/api/models"
+	pbb "Booking/api-service-booking/genproto/booking-proto"
+	"Booking/api-service-booking/internal/entity"
+	errorspkg "Booking/api-service-booking/internal/errors"
+	"Booking/api-service-booking/internal/pkg/booktime"
+	"Booking/api-service-booking/internal/pkg/i18n"
+	"Booking/api-service-booking/internal/pkg/ical"
+	l "Booking/api-service-booking/internal/pkg/logger"
+	"Booking/api-service-booking/internal/pkg/otlp"
+)
+
+// GET BOOKING HISTORY
+// @Summary GET BOOKING HISTORY
+// @Security BearerAuth
+// @Description Api for listing who changed what in a booking and when, oldest first. Open to the guest and the owner of the establishment
+// @Tags BOOKING
+// @Accept json
+// @Produce json
+// @Param id path string true "ID"
+// @Success 200 {object} models.BookingHistoryRes
+// @Failure 403 {object} models.StandartError
+// @Failure 404 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/bookings/{id}/history [GET]
+func (h *HandlerV1) GetBookingHistory(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "GetBookingHistory")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	userID, statusCode := GetIdFromToken(c.Request, h.Config)
+	if statusCode != http.StatusOK {
+		c.JSON(statusCode, gin.H{
+			"error": h.message(c, "cant_get_user"),
+		})
+		return
+	}
+
+	record, err := h.BookingRecord.Get(ctx, c.Param("id"))
+	if errors.Is(err, errorspkg.ErrorNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": h.message(c, "booking_not_found"),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to get booking record", l.Error(err))
+		return
+	}
+
+	if record.UserID != userID {
+		if statusCode, err := h.checkManager(ctx, c.Request, record.Category, record.EstablishmentID); err != nil {
+			c.JSON(statusCode, gin.H{
+				"error": h.errorMessage(c, err),
+			})
+			return
+		}
+	}
+
+	changes, err := h.BookingRecord.History(ctx, record.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to get booking history", l.Error(err))
+		return
+	}
+
+	response := models.BookingHistoryRes{
+		BookingId: record.ID,
+		Changes:   []*models.BookingChangeRes{},
+	}
+	for _, change := range changes {
+		res := models.BookingChangeRes{
+			Id:        change.ID,
+			ChangedBy: change.ChangedBy,
+			Role:      change.Role,
+			Fields:    []*models.FieldChangeRes{},
+			CreatedAt: change.CreatedAt.Format(time.RFC3339),
+		}
+		for _, field := range change.Fields {
+			res.Fields = append(res.Fields, &models.FieldChangeRes{
+				Field: field.Field,
+				From:  field.From,
+				To:    field.To,
+			})
+		}
+		response.Changes = append(response.Changes, &res)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// modifyBooking changes the dates or party size of a booking, or cancels
+// it, on behalf of the guest or an admin. New dates are checked against the
+// table and ticket capacity, the booking is priced again and the change is
+// kept in its history. The returned status goes to the client.
+func (h *HandlerV1) modifyBooking(ctx context.Context, r *http.Request, category string, body *models.UpdateBookingReq) (*models.BookingRes, int, error) {
+	userID, statusCode := GetIdFromToken(r, h.Config)
+	if statusCode != http.StatusOK {
+		return nil, statusCode, i18n.NewError("cant_get_user")
+	}
+	role, _ := GetRoleFromToken(r, h.Config)
+
+	current, err := h.BookingRecord.Get(ctx, body.Id.String())
+	if errors.Is(err, errorspkg.ErrorNotFound) {
+		current, err = h.backfillBookingRecord(ctx, category, userID, body.Id.String())
+	}
+	if errors.Is(err, errorspkg.ErrorNotFound) {
+		return nil, http.StatusNotFound, i18n.NewError("booking_not_found")
+	}
+	if err != nil {
+		h.Logger.Error("failed to get booking record", l.Error(err))
+		return nil, http.StatusInternalServerError, i18n.NewError("try_again_later")
+	}
+	if current.Category != category || (current.UserID != userID && role != "admin" && role != "sudo") {
+		return nil, http.StatusNotFound, i18n.NewError("booking_not_found")
+	}
+	if current.State != entity.BookingStateConfirmed {
+		return nil, http.StatusConflict, i18n.NewError("booking_locked", current.State)
+	}
+	if body.HraId != "" && body.HraId != current.EstablishmentID {
+		return nil, http.StatusBadRequest, i18n.NewError("booking_cannot_move")
+	}
+
+	// fields left out of the request keep their value
+	next := *current
+	if body.WillArrive != "" {
+		next.WillArrive = body.WillArrive
+	}
+	if body.WillLeave != "" {
+		next.WillLeave = body.WillLeave
+	}
+	if body.NumberOfPeople != 0 {
+		next.NumberOfPeople = body.NumberOfPeople
+	}
+
+	var (
+		tickets []*entity.Ticket
+		restore func()
+	)
+	if body.IsCanceled {
+		next.State = entity.BookingStateCanceled
+	} else {
+		if tickets, restore, statusCode, err = h.moveCapacity(ctx, current, &next, body.Tickets); err != nil {
+			return nil, statusCode, err
+		}
+		if statusCode, err = h.repriceBooking(ctx, &next, tickets); err != nil {
+			if restore != nil {
+				restore()
+			}
+			return nil, statusCode, err
+		}
+	}
+
+	response, err := h.updateBackendBooking(ctx, category, &pbb.GeneralBook{
+		Id:             current.ID,
+		UserId:         current.UserID,
+		HraId:          current.EstablishmentID,
+		WillArrive:     next.WillArrive,
+		WillLeave:      next.WillLeave,
+		NumberOfPeople: next.NumberOfPeople,
+		IsCanceled:     body.IsCanceled,
+		Reason:         body.Reason,
+		CreatedAt:      current.CreatedAt.Local().Format("2006-01-02T15:04:05"),
+		UpdatedAt:      time.Now().Format("2006-01-02T15:04:05"),
+	})
+	if err != nil {
+		if restore != nil {
+			restore()
+		}
+		h.Logger.Error("failed to update booking", l.Error(err))
+		return nil, http.StatusInternalServerError, i18n.NewError("try_again_later")
+	}
+
+	if body.IsCanceled {
+		h.releaseHold(ctx, category, current.ID)
+	}
+	if h.updateBookingRecord(ctx, current, &next) {
+		h.addBookingChange(ctx, userID, role, current, &next)
+	}
+
+	if response.IsCanceled {
+		go h.sendBookingMail(category, response, ical.MethodCancel, true)
+	} else {
+		go h.sendBookingMail(category, response, ical.MethodRequest, true)
+	}
+
+	return &models.BookingRes{
+		Id:             uuid.MustParse(response.Id),
+		UserId:         response.UserId,
+		HraId:          response.HraId,
+		WillArrive:     response.WillArrive,
+		WillLeave:      response.WillLeave,
+		NumberOfPeople: response.NumberOfPeople,
+		IsCanceled:     response.IsCanceled,
+		Reason:         response.Reason,
+		CreatedAt:      response.CreatedAt,
+		UpdatedAt:      response.UpdatedAt,
+		Tickets:        ticketsRes(tickets),
+		Price:          next.Price,
+		Discount:       next.Discount,
+		Total:          next.Price - next.Discount - next.PointsDiscount,
+		PromoCode:      next.PromoCode,
+		Points:         next.Points,
+		PointsDiscount: next.PointsDiscount,
+	}, http.StatusOK, nil
+}
+
+// backfillBookingRecord copies a booking made before bookings were kept
+// here from the booking service, so it can be changed like newer ones.
+// Only the bookings of userID are looked through.
+func (h *HandlerV1) backfillBookingRecord(ctx context.Context, category, userID, id string) (*entity.BookingRecord, error) {
+	book, err := h.findBackendBooking(ctx, category, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	record := entity.BookingRecord{
+		ID:              book.Id,
+		Category:        category,
+		UserID:          book.UserId,
+		EstablishmentID: book.HraId,
+		WillArrive:      book.WillArrive,
+		WillLeave:       book.WillLeave,
+		NumberOfPeople:  book.NumberOfPeople,
+	}
+	if book.IsCanceled {
+		record.State = entity.BookingStateCanceled
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05"} {
+		if createdAt, err := time.ParseInLocation(layout, book.CreatedAt, time.Local); err == nil {
+			record.CreatedAt = createdAt.UTC()
+			break
+		}
+	}
+
+	if err := h.BookingRecord.Create(ctx, &record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// findBackendBooking pages through the bookings of userID in the booking
+// service for id, ErrorNotFound if it is not one of them
+func (h *HandlerV1) findBackendBooking(ctx context.Context, category, userID, id string) (*pbb.GeneralBook, error) {
+	const limit = 100
+	for offset := uint64(0); ; offset += limit {
+		req := &pbb.ListReqById{
+			Limit:  limit,
+			Offset: offset,
+			Id:     &pbb.Id{Id: userID},
+		}
+
+		books, err := h.backendBookings(ctx, category, req)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, book := range books {
+			if book.Id == id {
+				return book, nil
+			}
+		}
+		if len(books) < limit {
+			return nil, errorspkg.ErrorNotFound
+		}
+	}
+}
+
+// backendBookings lists one page of the bookings of a category a user made
+// in the booking service
+func (h *HandlerV1) backendBookings(ctx context.Context, category string, req *pbb.ListReqById) ([]*pbb.GeneralBook, error) {
+	switch category {
+	case categoryHotel:
+		res, err := h.Service.BookingService().UHBGetAllByUId(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return res.UserHotel, nil
+	case categoryRestaurant:
+		res, err := h.Service.BookingService().URBGetAllByUId(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return res.UserRestaurant, nil
+	case categoryAttraction:
+		res, err := h.Service.BookingService().UABGetAllByUId(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return res.UserAttraction, nil
+	}
+	return nil, fmt.Errorf("unknown booking category %q", category)
+}
+
+// moveCapacity takes the table or tickets the changed booking next needs in
+// place of those of current and fills in what they decide, like the end of
+// a seating. The returned func moves the booking back if the change has to
+// be undone. Hotels hold a room every night of the new stay, while
+// restaurants that take plain bookings only have their dates checked.
+func (h *HandlerV1) moveCapacity(ctx context.Context, current, next *entity.BookingRecord, requested []*models.TicketReq) ([]*entity.Ticket, func(), int, error) {
+	held, err := h.heldTickets(ctx, current.ID, &models.CreateBookingReq{HraId: current.EstablishmentID})
+	if err != nil {
+		h.Logger.Error("failed to get held tickets", l.Error(err))
+		return nil, nil, http.StatusInternalServerError, i18n.NewError("try_again_later")
+	}
+
+	if next.WillArrive == current.WillArrive && next.WillLeave == current.WillLeave &&
+		next.NumberOfPeople == current.NumberOfPeople && len(requested) == 0 {
+		return held, nil, http.StatusOK, nil
+	}
+
+	arriveAt, _, err := booktime.Parse(next.WillArrive)
+	if err != nil {
+		return nil, nil, http.StatusBadRequest, i18n.NewError("field_invalid_date", "will_arrive")
+	}
+	if arriveAt.Before(time.Now()) {
+		return nil, nil, http.StatusBadRequest, i18n.NewError("field_in_past", "will_arrive")
+	}
+	if next.NumberOfPeople < 1 {
+		return nil, nil, http.StatusBadRequest, i18n.NewError("field_positive", "number_of_people")
+	}
+
+	switch {
+	case next.Category == categoryRestaurant:
+		reservation, status, err := h.reserveTable(ctx, current.ID, &models.CreateBookingReq{
+			HraId:          current.EstablishmentID,
+			WillArrive:     next.WillArrive,
+			NumberOfPeople: next.NumberOfPeople,
+		})
+		if err != nil {
+			return nil, nil, status, err
+		}
+		if reservation == nil {
+			break
+		}
+		next.WillLeave = reservation.EndsAt.Format("2006-01-02T15:04:05")
+
+		restore := func() {
+			_, _, err := h.reserveTable(ctx, current.ID, &models.CreateBookingReq{
+				HraId:          current.EstablishmentID,
+				WillArrive:     current.WillArrive,
+				NumberOfPeople: current.NumberOfPeople,
+			})
+			if err != nil {
+				h.Logger.Error("failed to move table back", l.Error(err))
+			}
+		}
+		return nil, restore, http.StatusOK, nil
+	case next.Category == categoryAttraction && (len(held) > 0 || len(requested) > 0):
+		body := models.CreateBookingReq{
+			HraId:          current.EstablishmentID,
+			WillArrive:     next.WillArrive,
+			NumberOfPeople: next.NumberOfPeople,
+			Tickets:        requested,
+		}
+		// a new time for the same party keeps its ticket kinds
+		if len(requested) == 0 && next.NumberOfPeople == current.NumberOfPeople {
+			body.Tickets = ticketReqs(held)
+		}
+
+		tickets, status, err := h.issueTickets(ctx, current.ID, &body)
+		if err != nil {
+			return nil, nil, status, err
+		}
+		next.NumberOfPeople = body.NumberOfPeople
+		next.WillLeave = body.WillLeave
+
+		restore := func() {
+			if len(held) == 0 {
+				h.cancelTickets(ctx, current.ID)
+				return
+			}
+			_, _, err := h.issueTickets(ctx, current.ID, &models.CreateBookingReq{
+				HraId:      current.EstablishmentID,
+				WillArrive: current.WillArrive,
+				Tickets:    ticketReqs(held),
+			})
+			if err != nil {
+				h.Logger.Error("failed to move tickets back", l.Error(err))
+			}
+		}
+		return tickets, restore, http.StatusOK, nil
+	}
+
+	leaveAt, _, err := booktime.Parse(next.WillLeave)
+	if err != nil || !leaveAt.After(arriveAt) {
+		return nil, nil, http.StatusBadRequest, i18n.NewError("field_after", "will_leave", "will_arrive")
+	}
+	if next.Category != categoryHotel {
+		return nil, nil, http.StatusOK, nil
+	}
+	if status, err := h.holdRooms(ctx, current.EstablishmentID, current.ID, arriveAt, leaveAt); err != nil {
+		return nil, nil, status, err
+	}
+
+	restore := func() {
+		if current.ArriveAt == nil {
+			h.releaseRooms(ctx, current.ID)
+			return
+		}
+		leaveAt := *current.ArriveAt
+		if current.LeaveAt != nil {
+			leaveAt = *current.LeaveAt
+		}
+		if _, err := h.holdRooms(ctx, current.EstablishmentID, current.ID, *current.ArriveAt, leaveAt); err != nil {
+			h.Logger.Error("failed to move rooms back", l.Error(err))
+		}
+	}
+	return nil, restore, http.StatusOK, nil
+}
+
+// repriceBooking quotes the changed booking again. Its promo code is
+// applied to the new price and spent points cover at most what is left.
+func (h *HandlerV1) repriceBooking(ctx context.Context, next *entity.BookingRecord, tickets []*entity.Ticket) (int, error) {
+	arriveAt, _, _ := booktime.Parse(next.WillArrive)
+	leaveAt, _, _ := booktime.Parse(next.WillLeave)
+
+	price, err := h.Pricing.Price(ctx, next.Category, next.EstablishmentID, arriveAt, leaveAt, next.NumberOfPeople, tickets)
+	if err != nil {
+		h.Logger.Error("failed to price booking", l.Error(err))
+		return http.StatusInternalServerError, i18n.NewError("try_again_later")
+	}
+
+	discount, err := h.Promotion.Rediscount(ctx, next.ID, price)
+	if err != nil {
+		h.Logger.Error("failed to apply promo code", l.Error(err))
+		return http.StatusInternalServerError, i18n.NewError("try_again_later")
+	}
+
+	next.Price = price
+	next.Discount = discount
+	if next.PointsDiscount > price-discount {
+		next.PointsDiscount = price - discount
+	}
+	return http.StatusOK, nil
+}
+
+// addBookingChange keeps what changed between two versions of a booking in
+// its history, failures are only logged
+func (h *HandlerV1) addBookingChange(ctx context.Context, changedBy, role string, from, to *entity.BookingRecord) {
+	err := h.BookingRecord.AddChange(ctx, &entity.BookingChange{
+		BookingID: from.ID,
+		ChangedBy: changedBy,
+		Role:      role,
+		Fields:    from.Diff(to),
+	})
+	if err != nil {
+		h.Logger.Error("failed to add booking change", l.Error(err))
+	}
+}
+
+func ticketReqs(tickets []*entity.Ticket) []*models.TicketReq {
+	quantities := map[string]int{}
+	for _, ticket := range tickets {
+		quantities[ticket.Kind]++
+	}
+
+	var reqs []*models.TicketReq
+	for _, kind := range entity.TicketKinds {
+		if quantities[kind] > 0 {
+			reqs = append(reqs, &models.TicketReq{Kind: kind, Quantity: quantities[kind]})
+		}
+	}
+	return reqs
+}
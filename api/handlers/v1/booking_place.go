@@ -0,0 +1,239 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Booking/api-service-booking/api/models"
+	pbb "Booking/api-service-booking/genproto/booking-proto"
+	"Booking/api-service-booking/internal/entity"
+	errorspkg "Booking/api-service-booking/internal/errors"
+	"Booking/api-service-booking/internal/pkg/booktime"
+	"Booking/api-service-booking/internal/pkg/i18n"
+	"Booking/api-service-booking/internal/pkg/ical"
+	l "Booking/api-service-booking/internal/pkg/logger"
+)
+
+// placeBooking creates a booking and mails the guest its confirmation
+func (h *HandlerV1) placeBooking(ctx context.Context, category, userID, bookingID string, body *models.CreateBookingReq) (*models.BookingRes, int, error) {
+	response, book, status, err := h.createBooking(ctx, category, userID, bookingID, body)
+	if err != nil {
+		return nil, status, err
+	}
+
+	go h.sendBookingMail(category, book, ical.MethodRequest, false)
+	return response, status, nil
+}
+
+// createBooking creates a booking in the booking service together with the
+// capacity it needs, rooms for hotels, a table for restaurants and tickets
+// for attractions.
+// Capacity already held under bookingID, as for a claimed waitlist offer,
+// is used instead of taking more. A promo code and loyalty points in body
+// are redeemed under bookingID. The returned status goes to the client.
+func (h *HandlerV1) createBooking(ctx context.Context, category, userID, bookingID string, body *models.CreateBookingReq) (*models.BookingRes, *pbb.GeneralBook, int, error) {
+	var (
+		tableID string
+		tickets []*entity.Ticket
+		release func()
+	)
+
+	switch category {
+	case categoryHotel:
+		arriveAt, _, err := booktime.Parse(body.WillArrive)
+		if err != nil {
+			return nil, nil, http.StatusBadRequest, i18n.NewError("field_invalid_date", "will_arrive")
+		}
+		leaveAt, _, err := booktime.Parse(body.WillLeave)
+		if err != nil || !leaveAt.After(arriveAt) {
+			return nil, nil, http.StatusBadRequest, i18n.NewError("field_after", "will_leave", "will_arrive")
+		}
+		held, err := h.HotelRoom.Held(ctx, bookingID)
+		if err != nil {
+			h.Logger.Error("failed to get room hold", l.Error(err))
+			return nil, nil, http.StatusInternalServerError, i18n.NewError("try_again_later")
+		}
+		if status, err := h.holdRooms(ctx, body.HraId, bookingID, arriveAt, leaveAt); err != nil {
+			return nil, nil, status, err
+		}
+		if !held {
+			release = func() { h.releaseRooms(ctx, bookingID) }
+		}
+	case categoryRestaurant:
+		table, err := h.RestaurantTable.GetReservation(ctx, bookingID)
+		if errors.Is(err, errorspkg.ErrorNotFound) {
+			var status int
+			if table, status, err = h.reserveTable(ctx, bookingID, body); err != nil {
+				return nil, nil, status, err
+			}
+			release = func() { h.releaseTable(ctx, bookingID) }
+		} else if err != nil {
+			h.Logger.Error("failed to get table reservation", l.Error(err))
+			return nil, nil, http.StatusInternalServerError, i18n.NewError("try_again_later")
+		}
+		if table != nil {
+			tableID = table.TableID
+			body.WillLeave = table.EndsAt.Format("2006-01-02T15:04:05")
+		}
+	case categoryAttraction:
+		var status int
+		held, err := h.heldTickets(ctx, bookingID, body)
+		if err != nil {
+			h.Logger.Error("failed to get held tickets", l.Error(err))
+			return nil, nil, http.StatusInternalServerError, i18n.NewError("try_again_later")
+		}
+		tickets = held
+		if len(tickets) == 0 {
+			if tickets, status, err = h.issueTickets(ctx, bookingID, body); err != nil {
+				return nil, nil, status, err
+			}
+			release = func() { h.cancelTickets(ctx, bookingID) }
+		}
+	}
+
+	quote, status, err := h.redeemQuote(ctx, category, userID, bookingID, body, tickets)
+	if err != nil {
+		if release != nil {
+			release()
+		}
+		return nil, nil, status, err
+	}
+	if status, err := h.redeemPoints(ctx, userID, bookingID, body, quote); err != nil {
+		if release != nil {
+			release()
+		}
+		if quote.PromoCode != "" {
+			h.releasePromo(ctx, bookingID)
+		}
+		return nil, nil, status, err
+	}
+
+	response, err := h.createBackendBooking(ctx, category, &pbb.GeneralBook{
+		Id:             bookingID,
+		UserId:         userID,
+		HraId:          body.HraId,
+		WillArrive:     body.WillArrive,
+		WillLeave:      body.WillLeave,
+		NumberOfPeople: body.NumberOfPeople,
+		IsCanceled:     body.IsCanceled,
+		Reason:         body.Reason,
+		CreatedAt:      time.Now().Format("2006-01-02T15:04:05"),
+	})
+	if err != nil {
+		if release != nil {
+			release()
+		}
+		h.releaseDiscounts(ctx, bookingID)
+		h.Logger.Error("failed to create booking", l.Error(err))
+		return nil, nil, http.StatusInternalServerError, i18n.NewError("try_again_later")
+	}
+
+	h.recordBooking(ctx, category, response, quote)
+
+	return &models.BookingRes{
+		Id:             uuid.MustParse(response.Id),
+		UserId:         response.UserId,
+		HraId:          response.HraId,
+		WillArrive:     response.WillArrive,
+		WillLeave:      response.WillLeave,
+		NumberOfPeople: response.NumberOfPeople,
+		IsCanceled:     response.IsCanceled,
+		Reason:         response.Reason,
+		CreatedAt:      response.CreatedAt,
+		TableId:        tableID,
+		Tickets:        ticketsRes(tickets),
+		Price:          quote.Price,
+		Discount:       quote.Discount,
+		Total:          quote.Total(),
+		PromoCode:      quote.PromoCode,
+		Points:         quote.Points,
+		PointsDiscount: quote.PointsDiscount,
+	}, response, http.StatusCreated, nil
+}
+
+// heldTickets returns the active tickets already sold under bookingID and
+// fills in the booking dates and size from them
+func (h *HandlerV1) heldTickets(ctx context.Context, bookingID string, body *models.CreateBookingReq) ([]*entity.Ticket, error) {
+	all, err := h.AttractionTicket.ListByBooking(ctx, bookingID)
+	if err != nil {
+		return nil, err
+	}
+
+	var tickets []*entity.Ticket
+	for _, ticket := range all {
+		if ticket.CanceledAt == nil {
+			tickets = append(tickets, ticket)
+		}
+	}
+	if len(tickets) == 0 {
+		return nil, nil
+	}
+
+	settings, err := h.AttractionTicket.GetSettings(ctx, body.HraId)
+	if err != nil {
+		return nil, err
+	}
+	body.NumberOfPeople = int64(len(tickets))
+	body.WillArrive = tickets[0].EntryAt.Format("2006-01-02T15:04:05")
+	body.WillLeave = tickets[0].EntryAt.Add(settings.SlotLength).Format("2006-01-02T15:04:05")
+
+	return tickets, nil
+}
+
+// releaseHold gives up the rooms, table or tickets held under bookingID
+func (h *HandlerV1) releaseHold(ctx context.Context, category, bookingID string) {
+	switch category {
+	case categoryHotel:
+		h.releaseRooms(ctx, bookingID)
+	case categoryRestaurant:
+		h.releaseTable(ctx, bookingID)
+	case categoryAttraction:
+		h.cancelTickets(ctx, bookingID)
+	}
+}
+
+// compensateBooking undoes a booking created by createBooking: it is
+// deleted in the booking service, its capacity and discounts are given
+// back and its copy is marked canceled by changedBy
+func (h *HandlerV1) compensateBooking(ctx context.Context, category, bookingID, changedBy string) error {
+	if err := h.deleteBackendBooking(ctx, category, bookingID); err != nil {
+		return err
+	}
+
+	h.releaseHold(ctx, category, bookingID)
+	h.cancelBookingRecord(ctx, bookingID, changedBy, "user")
+	h.releaseDiscounts(ctx, bookingID)
+	return nil
+}
+
+func (h *HandlerV1) deleteBackendBooking(ctx context.Context, category, id string) error {
+	var err error
+	switch category {
+	case categoryHotel:
+		_, err = h.Service.BookingService().UHBDelete(ctx, &pbb.Id{Id: id})
+	case categoryRestaurant:
+		_, err = h.Service.BookingService().URBDelete(ctx, &pbb.Id{Id: id})
+	case categoryAttraction:
+		_, err = h.Service.BookingService().UABDelete(ctx, &pbb.Id{Id: id})
+	default:
+		err = fmt.Errorf("unknown booking category %q", category)
+	}
+	return err
+}
+
+func (h *HandlerV1) createBackendBooking(ctx context.Context, category string, book *pbb.GeneralBook) (*pbb.GeneralBook, error) {
+	switch category {
+	case categoryHotel:
+		return h.Service.BookingService().UHBCreate(ctx, book)
+	case categoryRestaurant:
+		return h.Service.BookingService().URBCreate(ctx, book)
+	case categoryAttraction:
+		return h.Service.BookingService().UABCreate(ctx, book)
+	}
+	return nil, fmt.Errorf("unknown booking category %q", category)
+}
@@ -0,0 +1,247 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+
+	"Booking/api-service-booking/api/models"
+	pbe "Booking/api-service-booking/genproto/establishment-proto"
+	pbu "Booking/api-service-booking/genproto/user-proto"
+	"Booking/api-service-booking/internal/entity"
+	errorspkg "Booking/api-service-booking/internal/errors"
+	"Booking/api-service-booking/internal/pkg/booktime"
+	"Booking/api-service-booking/internal/pkg/i18n"
+	l "Booking/api-service-booking/internal/pkg/logger"
+	"Booking/api-service-booking/internal/pkg/otlp"
+	"Booking/api-service-booking/internal/pkg/query_parameter"
+)
+
+// bookingSearchSchema is what SearchBookings accepts in its query
+var bookingSearchSchema = query_parameter.Schema{
+	Filters: []string{
+		"category",
+		"email",
+		"user_id",
+		"name",
+		"hra_id",
+		"state",
+		"arrive_from",
+		"arrive_to",
+		"created_from",
+		"created_to",
+	},
+	Sortable:    []string{"created_at", "updated_at", "arrive_at", "price", "number_of_people"},
+	DefaultSort: "-created_at",
+	MaxLimit:    100,
+}
+
+// SEARCH BOOKINGS
+// @Summary SEARCH BOOKINGS
+// @Security BearerAuth
+// @Description Api for searching the bookings made through the gateway. email and name (of the establishment) are matched through the user and establishment services, state, hra_id and user_id take comma separated lists. The _from bounds are inclusive and the _to bounds exclusive, a date without a time in a _to bound covers that whole day. sort is a field with an optional - for descending order, pass next_cursor back as cursor for the next page
+// @Tags BOOKING
+// @Accept json
+// @Produce json
+// @Param category query string false "category"
+// @Param email query string false "email"
+// @Param user_id query string false "user_id"
+// @Param name query string false "name"
+// @Param hra_id query string false "hra_id"
+// @Param state query string false "state"
+// @Param arrive_from query string false "arrive_from"
+// @Param arrive_to query string false "arrive_to"
+// @Param created_from query string false "created_from"
+// @Param created_to query string false "created_to"
+// @Param sort query string false "sort" default(-created_at)
+// @Param cursor query string false "cursor"
+// @Param limit query int false "limit" default(10)
+// @Success 200 {object} models.SearchBookingsRes
+// @Failure 400 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/bookings/search [GET]
+func (h *HandlerV1) SearchBookings(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "SearchBookings")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	search, err := bookingSearchSchema.Parse(query_parameter.New(c.Request.URL.Query()))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	}
+
+	filter, err := h.bookingSearch(ctx, search)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	}
+
+	records, next, err := h.BookingRecord.Search(ctx, filter, search.Cursor)
+	var errBadRequest *errorspkg.ErrBadRequest
+	if errors.As(err, &errBadRequest) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to search bookings", l.Error(err))
+		return
+	}
+
+	response := models.SearchBookingsRes{
+		Bookings:   []*models.BookingRecordRes{},
+		NextCursor: next,
+	}
+	for _, record := range records {
+		response.Bookings = append(response.Bookings, bookingRecordRes(record))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// bookingSearch turns the parsed query into a filter, looking up the
+// users and establishments behind email and name
+func (h *HandlerV1) bookingSearch(ctx context.Context, search *query_parameter.Search) (*entity.BookingSearch, error) {
+	filter := entity.BookingSearch{
+		Category:         search.Filters["category"],
+		UserIDs:          search.List("user_id"),
+		EstablishmentIDs: search.List("hra_id"),
+		States:           search.List("state"),
+		SortBy:           search.Sort.Field,
+		Desc:             search.Sort.Desc,
+		Limit:            search.Limit,
+	}
+
+	if email := search.Filters["email"]; email != "" {
+		var userIDs []string
+		user, err := h.Service.UserService().Get(ctx, &pbu.Filter{
+			Filter: map[string]string{"email": email},
+		})
+		if err == nil && (filter.UserIDs == nil || contains(filter.UserIDs, user.User.Id)) {
+			userIDs = append(userIDs, user.User.Id)
+		}
+		// a non nil empty list matches nothing
+		filter.UserIDs = append([]string{}, userIDs...)
+	}
+
+	if name := search.Filters["name"]; name != "" {
+		var establishmentIDs []string
+		for _, id := range h.findEstablishments(ctx, filter.Category, name) {
+			if filter.EstablishmentIDs == nil || contains(filter.EstablishmentIDs, id) {
+				establishmentIDs = append(establishmentIDs, id)
+			}
+		}
+		filter.EstablishmentIDs = append([]string{}, establishmentIDs...)
+	}
+
+	bounds := []struct {
+		key       string
+		target    **time.Time
+		exclusive bool
+	}{
+		{"arrive_from", &filter.ArriveFrom, false},
+		{"arrive_to", &filter.ArriveTo, true},
+		{"created_from", &filter.CreatedFrom, false},
+		{"created_to", &filter.CreatedTo, true},
+	}
+	for _, bound := range bounds {
+		value := search.Filters[bound.key]
+		if value == "" {
+			continue
+		}
+		t, dateOnly, err := booktime.Parse(value)
+		if err != nil {
+			return nil, i18n.NewError("field_invalid_param", bound.key)
+		}
+		if dateOnly && bound.exclusive {
+			t = t.AddDate(0, 0, 1)
+		}
+		*bound.target = &t
+	}
+
+	return &filter, nil
+}
+
+// findEstablishments returns the ids of the establishments of category
+// whose name matches, of every category when it is empty
+func (h *HandlerV1) findEstablishments(ctx context.Context, category, name string) []string {
+	var ids []string
+
+	if category == "" || category == categoryHotel {
+		response, err := h.Service.EstablishmentService().FindHotelsByName(ctx, &pbe.FindHotelsByNameRequest{Name: name})
+		if err != nil {
+			h.Logger.Error("failed to find hotels", l.Error(err))
+		} else {
+			for _, hotel := range response.Hotels {
+				ids = append(ids, hotel.HotelId)
+			}
+		}
+	}
+	if category == "" || category == categoryRestaurant {
+		response, err := h.Service.EstablishmentService().FindRestaurantsByName(ctx, &pbe.FindRestaurantsByNameRequest{Name: name})
+		if err != nil {
+			h.Logger.Error("failed to find restaurants", l.Error(err))
+		} else {
+			for _, restaurant := range response.Restaurants {
+				ids = append(ids, restaurant.RestaurantId)
+			}
+		}
+	}
+	if category == "" || category == categoryAttraction {
+		response, err := h.Service.EstablishmentService().FindAttractionsByName(ctx, &pbe.FindAttractionsByNameRequest{Name: name})
+		if err != nil {
+			h.Logger.Error("failed to find attractions", l.Error(err))
+		} else {
+			for _, attraction := range response.Attractions {
+				ids = append(ids, attraction.AttractionId)
+			}
+		}
+	}
+
+	return ids
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func bookingRecordRes(record *entity.BookingRecord) *models.BookingRecordRes {
+	return &models.BookingRecordRes{
+		Id:             record.ID,
+		Category:       record.Category,
+		UserId:         record.UserID,
+		HraId:          record.EstablishmentID,
+		WillArrive:     record.WillArrive,
+		WillLeave:      record.WillLeave,
+		NumberOfPeople: record.NumberOfPeople,
+		Price:          record.Price,
+		Discount:       record.Discount,
+		Total:          record.Price - record.Discount - record.PointsDiscount,
+		PromoCode:      record.PromoCode,
+		Points:         record.Points,
+		PointsDiscount: record.PointsDiscount,
+		State:          record.State,
+		CreatedAt:      record.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:      record.UpdatedAt.Format(time.RFC3339),
+	}
+}
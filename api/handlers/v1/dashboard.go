@@ -0,0 +1,219 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+
+	"Booking/api-service-booking/api/models"
+	errorspkg "Booking/api-service-booking/internal/errors"
+	"Booking/api-service-booking/internal/pkg/booktime"
+	l "Booking/api-service-booking/internal/pkg/logger"
+	"Booking/api-service-booking/internal/pkg/openinghours"
+	"Booking/api-service-booking/internal/pkg/otlp"
+)
+
+// dashboardMaxDays caps the date range of one dashboard request
+const dashboardMaxDays = 92
+
+// GET DASHBOARD
+// @Summary GET DASHBOARD
+// @Security BearerAuth
+// @Description Api for the owner's booking calendar: bookings, guests, arrivals, departures and cancellations per day of one or more establishments of a category, with the capacity and occupancy of each day where the capacity is known (rooms for hotels against the rooms booked that night, seats times the seatings a table takes that day for restaurants and entry slot places for attractions, against the guests), the capacity of the whole range (room nights for hotels), arrivals and departures today and the cancellation rate. to is exclusive and defaults to 30 days after from, from defaults to today
+// @Tags DASHBOARD
+// @Accept json
+// @Produce json
+// @Param request query models.DashboardReq true "request"
+// @Success 200 {object} models.DashboardRes
+// @Failure 400 {object} models.StandartError
+// @Failure 403 {object} models.StandartError
+// @Failure 404 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/dashboard [GET]
+func (h *HandlerV1) GetDashboard(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "GetDashboard")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	var body models.DashboardReq
+	if err := c.ShouldBindQuery(&body); err != nil {
+		h.bindError(c, err)
+		return
+	}
+
+	establishmentIDs := splitList(body.HraId)
+	if len(establishmentIDs) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": h.message(c, "field_required", "hra_id"),
+		})
+		return
+	}
+
+	from := midnight(time.Now())
+	if body.From != "" {
+		date, dateOnly, err := booktime.Parse(body.From)
+		if err != nil || !dateOnly {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": h.message(c, "field_format", "from", "2006-01-02"),
+			})
+			return
+		}
+		from = date
+	}
+	to := from.AddDate(0, 0, 30)
+	if body.To != "" {
+		date, dateOnly, err := booktime.Parse(body.To)
+		if err != nil || !dateOnly {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": h.message(c, "field_format", "to", "2006-01-02"),
+			})
+			return
+		}
+		to = date
+	}
+	if !to.After(from) || to.After(from.AddDate(0, 0, dashboardMaxDays)) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": h.message(c, "date_range_too_long"),
+		})
+		return
+	}
+
+	capacities := make([]func(date time.Time) int, 0, len(establishmentIDs))
+	for _, id := range establishmentIDs {
+		if statusCode, err := h.checkManager(ctx, c.Request, body.Category, id); err != nil {
+			c.JSON(statusCode, gin.H{
+				"error": h.errorMessage(c, err),
+			})
+			return
+		}
+		capacities = append(capacities, h.dayCapacity(ctx, body.Category, id))
+	}
+
+	dashboard, err := h.BookingRecord.Dashboard(ctx, body.Category, establishmentIDs, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to build dashboard", l.Error(err))
+		return
+	}
+
+	response := models.DashboardRes{
+		Category:        body.Category,
+		HraIds:          establishmentIDs,
+		From:            from.Format("2006-01-02"),
+		To:              to.Format("2006-01-02"),
+		Days:            []*models.DashboardDayRes{},
+		Booked:          dashboard.Booked,
+		Canceled:        dashboard.Canceled,
+		ArrivalsToday:   dashboard.ArrivalsToday,
+		DeparturesToday: dashboard.DeparturesToday,
+	}
+	if dashboard.Booked > 0 {
+		response.CancellationRate = percent(int64(dashboard.Canceled), int64(dashboard.Booked))
+	}
+	for _, day := range dashboard.Days {
+		dayRes := models.DashboardDayRes{
+			Date:       day.Date.Format("2006-01-02"),
+			Bookings:   day.Bookings,
+			Guests:     day.Guests,
+			Arrivals:   day.Arrivals,
+			Departures: day.Departures,
+			Canceled:   day.Canceled,
+		}
+		for _, capacity := range capacities {
+			dayRes.Capacity += capacity(day.Date)
+		}
+		// a hotel booking holds one room a night, the rest hold a place
+		// for each guest
+		taken := day.Guests
+		if body.Category == categoryHotel {
+			taken = int64(day.Bookings)
+		}
+		if dayRes.Capacity > 0 {
+			occupancy := percent(taken, int64(dayRes.Capacity))
+			dayRes.Occupancy = &occupancy
+		}
+		response.Capacity += dayRes.Capacity
+		response.Days = append(response.Days, &dayRes)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// dayCapacity returns how much an establishment takes in on a date: the
+// rooms of a hotel, and for a restaurant its seats times the seatings a
+// table takes that day, for an attraction the visitors of all its entry
+// slots. It is 0 where that is unknown.
+func (h *HandlerV1) dayCapacity(ctx context.Context, category, establishmentID string) func(date time.Time) int {
+	unknown := func(time.Time) int { return 0 }
+
+	switch category {
+	case categoryHotel:
+		rooms, err := h.HotelRoom.Get(ctx, establishmentID)
+		if errors.Is(err, errorspkg.ErrorNotFound) {
+			return unknown
+		}
+		if err != nil {
+			h.Logger.Error("failed to get hotel rooms", l.Error(err))
+			return unknown
+		}
+		return func(time.Time) int { return rooms.Rooms }
+	case categoryRestaurant:
+		tables, err := h.RestaurantTable.List(ctx, establishmentID)
+		if err != nil {
+			h.Logger.Error("failed to list restaurant tables", l.Error(err))
+			return unknown
+		}
+		seats := 0
+		for _, table := range tables {
+			seats += table.Capacity
+		}
+		if seats == 0 {
+			return unknown
+		}
+		schedule, _, err := h.restaurantSchedule(ctx, establishmentID)
+		if err != nil || schedule == nil {
+			return unknown
+		}
+		return func(date time.Time) int {
+			return seats * h.RestaurantTable.Seatings(schedule, date)
+		}
+	case categoryAttraction:
+		settings, err := h.AttractionTicket.GetSettings(ctx, establishmentID)
+		if errors.Is(err, errorspkg.ErrorNotFound) {
+			return unknown
+		}
+		if err != nil {
+			h.Logger.Error("failed to get entry settings", l.Error(err))
+			return unknown
+		}
+		hours, err := openinghours.Parse(settings.OpeningHours)
+		if err != nil || settings.SlotLength <= 0 {
+			return unknown
+		}
+		return func(date time.Time) int {
+			open, closeAt := hours.Window(date)
+			return int(closeAt.Sub(open)/settings.SlotLength) * settings.Capacity
+		}
+	}
+	return unknown
+}
+
+// midnight is the start of the day of t in the booking time zone
+func midnight(t time.Time) time.Time {
+	t = t.In(booktime.Location())
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// percent is part of whole in percent rounded to one decimal
+func percent(part, whole int64) float64 {
+	return math.Round(float64(part)*1000/float64(whole)) / 10
+}
@@ -0,0 +1,589 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+
+	"Booking/api-service-booking/api/models"
+	"Booking/api-service-booking/internal/entity"
+	errorspkg "Booking/api-service-booking/internal/errors"
+	"Booking/api-service-booking/internal/pkg/booktime"
+	"Booking/api-service-booking/internal/pkg/export"
+	"Booking/api-service-booking/internal/pkg/i18n"
+	l "Booking/api-service-booking/internal/pkg/logger"
+	"Booking/api-service-booking/internal/pkg/otlp"
+	"Booking/api-service-booking/internal/pkg/utils"
+)
+
+const (
+	// exportPageSize is how many establishments an export reads at a time
+	exportPageSize = 100
+	// snapshotLockMargin keeps a snapshot locked for a while after its
+	// export has to be done, for the run to be saved
+	snapshotLockMargin = time.Minute
+)
+
+// EXPORT ESTABLISHMENTS
+// @Summary EXPORT ESTABLISHMENTS
+// @Security BearerAuth
+// @Description Api for downloading every hotel, restaurant or attraction with its location, rating and image urls as CSV, NDJSON or XLSX. Columns are id, owner_id, name, description, rating, contact_number, licence_url, website_url, opening_hours for restaurants, address, latitude, longitude, country, city, state_province, images, separated by ; outside NDJSON, created_at and updated_at. city, country, owner_id and min_rating narrow the export down. The file is written while establishments are read, a failure halfway leaves it cut short
+// @Tags EXPORT
+// @Produce text/csv,application/x-ndjson,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param category query string true "hotel, restaurant or attraction"
+// @Param format query string false "csv, ndjson or xlsx, csv by default"
+// @Param city query string false "city"
+// @Param country query string false "country"
+// @Param owner_id query string false "owner_id"
+// @Param min_rating query number false "min_rating"
+// @Success 200 {file} file
+// @Failure 400 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/establishments/export [GET]
+func (h *HandlerV1) ExportEstablishments(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "ExportEstablishments")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	category := c.Query("category")
+	format := c.DefaultQuery("format", export.FormatCSV)
+	filter, err := exportFilter(c)
+	if err == nil {
+		err = h.EstablishmentSnapshot.Check(category, format, filter)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	}
+
+	started := false
+	_, err = h.exportEstablishments(ctx, category, filter, func() (export.Writer, error) {
+		started = true
+		// an export takes longer than the server lets a response be written
+		_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+		name := fmt.Sprintf("%ss-%s.%s", category, time.Now().In(booktime.Location()).Format("2006-01-02"), format)
+		c.Header("Content-Type", export.ContentType(format))
+		c.Header("Content-Disposition", `attachment; filename="`+name+`"`)
+		c.Status(http.StatusOK)
+		return export.NewWriter(format, c.Writer, exportColumns(category))
+	})
+	if err != nil && !started {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to list establishments for export", l.Error(err))
+		return
+	}
+	if err != nil {
+		h.Logger.Error("failed to export establishments", l.Error(err))
+	}
+}
+
+// CREATE ESTABLISHMENT SNAPSHOT
+// @Summary CREATE ESTABLISHMENT SNAPSHOT
+// @Security BearerAuth
+// @Description Api for writing an export of a category to the media storage every night, a file a night. It takes the same filters as the export. Follow the runs with GET /v1/establishments/snapshots/{id}
+// @Tags EXPORT
+// @Accept json
+// @Produce json
+// @Param Snapshot body models.CreateSnapshotReq true "Snapshot"
+// @Success 201 {object} models.SnapshotRes
+// @Failure 400 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/establishments/snapshots [POST]
+func (h *HandlerV1) CreateSnapshot(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "CreateSnapshot")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	var body models.CreateSnapshotReq
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.bindError(c, err)
+		return
+	}
+
+	ownerID, statusCode := GetIdFromToken(c.Request, h.Config)
+	if statusCode != http.StatusOK {
+		c.JSON(statusCode, gin.H{
+			"error": h.message(c, "cant_get_user"),
+		})
+		return
+	}
+
+	m := entity.EstablishmentSnapshot{
+		Category: body.Category,
+		Format:   body.Format,
+		Filter: entity.ExportFilter{
+			City:      body.City,
+			Country:   body.Country,
+			OwnerID:   body.OwnerID,
+			MinRating: body.MinRating,
+		},
+		OwnerID:   ownerID,
+		NextRunAt: nextSnapshotRun(time.Now(), h.Config.Snapshot.Hour),
+	}
+	if m.Format == "" {
+		m.Format = export.FormatCSV
+	}
+	err := h.EstablishmentSnapshot.Create(ctx, &m)
+	var errBadRequest *errorspkg.ErrBadRequest
+	if errors.As(err, &errBadRequest) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to create snapshot", l.Error(err))
+		return
+	}
+
+	if err = h.scheduleSnapshot(ctx, &m); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to schedule snapshot", l.Error(err))
+		// a snapshot that never runs is of no use
+		if err = h.EstablishmentSnapshot.Delete(ctx, m.ID); err != nil {
+			h.Logger.Error("failed to delete unscheduled snapshot", l.Error(err))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, snapshotRes(&m))
+}
+
+// LIST ESTABLISHMENT SNAPSHOTS
+// @Summary LIST ESTABLISHMENT SNAPSHOTS
+// @Security BearerAuth
+// @Description Api for listing nightly snapshots, newest first
+// @Tags EXPORT
+// @Accept json
+// @Produce json
+// @Param request query models.Pagination true "request"
+// @Success 200 {object} models.ListSnapshotsRes
+// @Failure 400 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/establishments/snapshots [GET]
+func (h *HandlerV1) ListSnapshots(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "ListSnapshots")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	params, errStr := utils.ParseQueryParam(c.Request.URL.Query())
+	if errStr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": h.errorMessage(c, errStr[0]),
+		})
+		return
+	}
+
+	snapshots, count, err := h.EstablishmentSnapshot.List(ctx, params.Limit, (params.Page-1)*params.Limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to list snapshots", l.Error(err))
+		return
+	}
+
+	response := models.ListSnapshotsRes{
+		Snapshots: []*models.SnapshotRes{},
+		Count:     count,
+	}
+	for _, m := range snapshots {
+		response.Snapshots = append(response.Snapshots, snapshotRes(m))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GET ESTABLISHMENT SNAPSHOT
+// @Summary GET ESTABLISHMENT SNAPSHOT
+// @Security BearerAuth
+// @Description Api for getting a snapshot with a link to download its latest file, the link works for a limited time
+// @Tags EXPORT
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} models.SnapshotRes
+// @Failure 404 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/establishments/snapshots/{id} [GET]
+func (h *HandlerV1) GetSnapshot(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "GetSnapshot")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	m, err := h.EstablishmentSnapshot.Get(ctx, c.Param("id"))
+	if errors.Is(err, errorspkg.ErrorNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": h.message(c, "snapshot_not_found"),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to get snapshot", l.Error(err))
+		return
+	}
+
+	response := snapshotRes(m)
+	if m.LastObject != "" {
+		link, err := h.Storage.Link(ctx, h.Config.Snapshot.Bucket, m.LastObject, h.Config.Snapshot.LinkTTL)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": h.message(c, "try_again_later"),
+			})
+			h.Logger.Error("failed to link snapshot file", l.Error(err))
+			return
+		}
+		response.LastLink = link
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// DELETE ESTABLISHMENT SNAPSHOT
+// @Summary DELETE ESTABLISHMENT SNAPSHOT
+// @Security BearerAuth
+// @Description Api for stopping a nightly snapshot, files it already wrote stay in the media storage
+// @Tags EXPORT
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} string
+// @Failure 404 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/establishments/snapshots/{id} [DELETE]
+func (h *HandlerV1) DeleteSnapshot(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "DeleteSnapshot")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	err := h.EstablishmentSnapshot.Delete(ctx, c.Param("id"))
+	if errors.Is(err, errorspkg.ErrorNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": h.message(c, "snapshot_not_found"),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to delete snapshot", l.Error(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, "successfully deleted...")
+}
+
+// exportFilter reads the filters of an export query
+func exportFilter(c *gin.Context) (*entity.ExportFilter, error) {
+	filter := entity.ExportFilter{
+		City:    c.Query("city"),
+		Country: c.Query("country"),
+		OwnerID: c.Query("owner_id"),
+	}
+	if value := c.Query("min_rating"); value != "" {
+		var err error
+		if filter.MinRating, err = strconv.ParseFloat(value, 64); err != nil {
+			return nil, i18n.NewError("field_number", "min_rating")
+		}
+	}
+	return &filter, nil
+}
+
+// exportColumns are the columns of an export of category
+func exportColumns(category string) []string {
+	columns := []string{"id", "owner_id", "name", "description", "rating", "contact_number", "licence_url", "website_url"}
+	if category == categoryRestaurant {
+		columns = append(columns, "opening_hours")
+	}
+	return append(columns, "address", "latitude", "longitude", "country", "city", "state_province", "images", "created_at", "updated_at")
+}
+
+// exportValues is the row of e under exportColumns
+func exportValues(e *indexedEstablishment) []interface{} {
+	values := []interface{}{e.id, e.ownerID, e.name, e.description, float64(e.rating), e.contactNumber, e.licenceUrl, e.websiteUrl}
+	if e.category == categoryRestaurant {
+		values = append(values, e.openingHours)
+	}
+
+	location := e.location
+	if location == nil {
+		return append(values, "", "", "", "", "", "", imageURLs(e), e.createdAt, e.updatedAt)
+	}
+	return append(values,
+		location.Address,
+		float64(location.Latitude),
+		float64(location.Longitude),
+		location.Country,
+		location.City,
+		location.StateProvince,
+		imageURLs(e),
+		e.createdAt,
+		e.updatedAt,
+	)
+}
+
+func imageURLs(e *indexedEstablishment) []string {
+	urls := make([]string, 0, len(e.images))
+	for _, image := range e.images {
+		if image.ImageUrl != "" {
+			urls = append(urls, image.ImageUrl)
+		}
+	}
+	return urls
+}
+
+// exportMatches tells whether e passes filter
+func exportMatches(e *indexedEstablishment, filter *entity.ExportFilter) bool {
+	var city, country string
+	if e.location != nil {
+		city, country = e.location.City, e.location.Country
+	}
+	switch {
+	case filter.City != "" && !strings.EqualFold(strings.TrimSpace(city), filter.City):
+		return false
+	case filter.Country != "" && !strings.EqualFold(strings.TrimSpace(country), filter.Country):
+		return false
+	case filter.OwnerID != "" && e.ownerID != filter.OwnerID:
+		return false
+	}
+	return float64(e.rating) >= filter.MinRating
+}
+
+// exportEstablishments pages through the establishments of category and
+// writes those that pass filter, returning how many it wrote. The writer is
+// only started once the first page is in, so a listing that fails right
+// away can still be answered with an error.
+func (h *HandlerV1) exportEstablishments(ctx context.Context, category string, filter *entity.ExportFilter, start func() (export.Writer, error)) (int, error) {
+	var (
+		writer  export.Writer
+		written int
+	)
+	for offset := int64(0); ; offset += exportPageSize {
+		page, err := h.establishmentPage(ctx, category, offset, exportPageSize)
+		if err != nil {
+			return written, err
+		}
+		if writer == nil {
+			if writer, err = start(); err != nil {
+				return written, err
+			}
+		}
+
+		for _, e := range page {
+			if !exportMatches(e, filter) {
+				continue
+			}
+			if err := writer.Write(exportValues(e)); err != nil {
+				return written, err
+			}
+			written++
+		}
+		if len(page) < exportPageSize {
+			return written, writer.Close()
+		}
+	}
+}
+
+// nextSnapshotRun is the first time after now the clock strikes hour in
+// Tashkent
+func nextSnapshotRun(now time.Time, hour int) time.Time {
+	local := now.In(booktime.Location())
+	next := time.Date(local.Year(), local.Month(), local.Day(), hour, 0, 0, 0, local.Location())
+	if !next.After(local) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next.UTC()
+}
+
+func snapshotRes(m *entity.EstablishmentSnapshot) *models.SnapshotRes {
+	response := models.SnapshotRes{
+		Id:        m.ID,
+		Category:  m.Category,
+		Format:    m.Format,
+		City:      m.Filter.City,
+		Country:   m.Filter.Country,
+		OwnerID:   m.Filter.OwnerID,
+		MinRating: m.Filter.MinRating,
+		NextRunAt: m.NextRunAt.Format(time.RFC3339),
+		LastRows:  m.LastRows,
+		LastError: m.LastError,
+		CreatedAt: m.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: m.UpdatedAt.Format(time.RFC3339),
+	}
+	if m.LastRunAt != nil {
+		response.LastRunAt = m.LastRunAt.Format(time.RFC3339)
+	}
+	return &response
+}
+
+// scheduleSnapshot plans the run of a snapshot at its NextRunAt, the job
+// carries the time so a run planned before is told from the current one
+func (h *HandlerV1) scheduleSnapshot(ctx context.Context, m *entity.EstablishmentSnapshot) error {
+	return h.scheduleSnapshotRun(ctx, m, m.NextRunAt, 0)
+}
+
+// scheduleSnapshotRun plans attempt of the run of a snapshot due at its
+// NextRunAt for runAt
+func (h *HandlerV1) scheduleSnapshotRun(ctx context.Context, m *entity.EstablishmentSnapshot, runAt time.Time, attempt int) error {
+	return h.Scheduler.Schedule(ctx, &entity.Job{
+		Kind: entity.JobKindEstablishmentSnapshot,
+		Payload: map[string]string{
+			"snapshot_id": m.ID,
+			"run_at":      m.NextRunAt.Format(time.RFC3339),
+			"attempt":     strconv.Itoa(attempt),
+		},
+		RunAt: runAt,
+	})
+}
+
+// runSnapshot starts writing the export of a snapshot in the background,
+// as it takes far longer than the scheduler holds its lock. The snapshot
+// is locked while it is written and the next attempt is planned for when
+// the lock runs out, so the run is retried if it fails or this replica
+// stops. The last attempt moves on to the next night with the error kept.
+func (h *HandlerV1) runSnapshot(ctx context.Context, job *entity.Job) error {
+	m, err := h.EstablishmentSnapshot.Get(ctx, job.Payload["snapshot_id"])
+	if errors.Is(err, errorspkg.ErrorNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	runAt, err := time.Parse(time.RFC3339, job.Payload["run_at"])
+	if err != nil || !runAt.Equal(m.NextRunAt) {
+		return nil
+	}
+	attempt, err := strconv.Atoi(job.Payload["attempt"])
+	if err != nil {
+		return fmt.Errorf("snapshot job %s has no attempt", job.ID)
+	}
+
+	lockTTL := h.Config.Snapshot.Timeout + snapshotLockMargin
+	locked, err := h.EstablishmentSnapshot.Lock(ctx, m.ID, lockTTL)
+	if err != nil {
+		return err
+	}
+	if !locked {
+		return fmt.Errorf("snapshot %s is still being written", m.ID)
+	}
+
+	if attempt >= h.Config.Scheduler.MaxAttempts {
+		defer h.unlockSnapshot(m.ID)
+		m.LastError = "export did not finish"
+		return h.planNextSnapshot(ctx, m, time.Now())
+	}
+
+	if err := h.scheduleSnapshotRun(ctx, m, time.Now().Add(lockTTL), attempt+1); err != nil {
+		h.unlockSnapshot(m.ID)
+		return err
+	}
+	go h.writeSnapshotRun(m, attempt)
+	return nil
+}
+
+// writeSnapshotRun writes the export of a locked snapshot and saves the
+// run. A failed attempt keeps the error and is left to the next one.
+func (h *HandlerV1) writeSnapshotRun(m *entity.EstablishmentSnapshot, attempt int) {
+	defer h.unlockSnapshot(m.ID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.Config.Snapshot.Timeout)
+	now := time.Now()
+	object := fmt.Sprintf("%s/%s/%s.%s", m.Category, m.ID, now.In(booktime.Location()).Format("2006-01-02"), m.Format)
+	rows, err := h.writeSnapshot(ctx, m, object)
+	cancel()
+
+	// the run is saved even when the export used up its time
+	saveCtx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
+	defer cancel()
+	if err != nil {
+		h.Logger.Error("failed to write snapshot", l.Error(err))
+		m.LastError = err.Error()
+		if attempt+1 < h.Config.Scheduler.MaxAttempts {
+			if err := h.EstablishmentSnapshot.Update(saveCtx, m); err != nil {
+				h.Logger.Error("failed to update snapshot", l.Error(err))
+			}
+			return
+		}
+	} else {
+		runAt := now.UTC()
+		m.LastRunAt = &runAt
+		m.LastObject = object
+		m.LastRows = rows
+		m.LastError = ""
+	}
+
+	if err := h.planNextSnapshot(saveCtx, m, now); err != nil {
+		h.Logger.Error("failed to plan next snapshot", l.Error(err))
+	}
+}
+
+// planNextSnapshot saves the run of a snapshot and plans the next night.
+// The next night is planned before it is saved, the pending attempt of
+// this night is then dropped as outdated.
+func (h *HandlerV1) planNextSnapshot(ctx context.Context, m *entity.EstablishmentSnapshot, now time.Time) error {
+	m.NextRunAt = nextSnapshotRun(now, h.Config.Snapshot.Hour)
+	if err := h.scheduleSnapshot(ctx, m); err != nil {
+		return err
+	}
+	return h.EstablishmentSnapshot.Update(ctx, m)
+}
+
+func (h *HandlerV1) unlockSnapshot(id string) {
+	if err := h.EstablishmentSnapshot.Unlock(context.Background(), id); err != nil {
+		h.Logger.Error("failed to unlock snapshot", l.Error(err))
+	}
+}
+
+// writeSnapshot streams the export of a snapshot into object of the
+// snapshot bucket
+func (h *HandlerV1) writeSnapshot(ctx context.Context, m *entity.EstablishmentSnapshot, object string) (int, error) {
+	reader, writer := io.Pipe()
+	uploaded := make(chan error, 1)
+	go func() {
+		err := h.Storage.Put(ctx, h.Config.Snapshot.Bucket, object, reader, export.ContentType(m.Format))
+		// unblocks the export if the upload stopped reading
+		reader.CloseWithError(err)
+		uploaded <- err
+	}()
+
+	rows, err := h.exportEstablishments(ctx, m.Category, &m.Filter, func() (export.Writer, error) {
+		return export.NewWriter(m.Format, writer, exportColumns(m.Category))
+	})
+	writer.CloseWithError(err)
+	if uploadErr := <-uploaded; err == nil {
+		err = uploadErr
+	}
+	return rows, err
+}
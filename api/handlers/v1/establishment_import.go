@@ -0,0 +1,571 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"Booking/api-service-booking/api/models"
+	pbe "Booking/api-service-booking/genproto/establishment-proto"
+	"Booking/api-service-booking/internal/entity"
+	errorspkg "Booking/api-service-booking/internal/errors"
+	"Booking/api-service-booking/internal/pkg/i18n"
+	l "Booking/api-service-booking/internal/pkg/logger"
+	"Booking/api-service-booking/internal/pkg/otlp"
+	"Booking/api-service-booking/internal/pkg/utils"
+)
+
+const (
+	// importBatchSize is how many rows one run of an import job goes
+	// through before it hands over to the next
+	importBatchSize = 20
+	// importRowTime is how much of the job timeout is kept for each row
+	importRowTime = time.Second
+	// maxImportSize caps an uploaded import file
+	maxImportSize = 10 << 20
+)
+
+// START ESTABLISHMENT IMPORT
+// @Summary START ESTABLISHMENT IMPORT
+// @Security BearerAuth
+// @Description Api for creating or updating hotels, restaurants or attractions in bulk from a CSV file with a header line or an NDJSON file. Columns are external_ref, owner_id, name, description, rating, contact_number, licence_url, website_url, opening_hours for restaurants, address, latitude, longitude, country, city, state_province and tags, separated by ; in CSV. external_ref and name are required. A row whose external_ref was imported before updates that establishment. Imports are dry runs unless dry_run is false, they run in the background, follow them with GET /v1/establishments/imports/{id}
+// @Tags IMPORT
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "file"
+// @Param category formData string true "hotel, restaurant or attraction"
+// @Param format formData string false "csv or ndjson, by default taken from the file extension"
+// @Param dry_run formData bool false "true by default"
+// @Success 202 {object} models.ImportRes
+// @Failure 400 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/establishments/imports [POST]
+func (h *HandlerV1) StartImport(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "StartImport")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	var body models.StartImportReq
+	if err := c.ShouldBind(&body); err != nil {
+		h.bindError(c, err)
+		return
+	}
+	if body.File.Size > maxImportSize {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": h.message(c, "file_too_large"),
+		})
+		return
+	}
+
+	ownerID, statusCode := GetIdFromToken(c.Request, h.Config)
+	if statusCode != http.StatusOK {
+		c.JSON(statusCode, gin.H{
+			"error": h.message(c, "cant_get_user"),
+		})
+		return
+	}
+
+	file, err := body.File.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to open import file", l.Error(err))
+		return
+	}
+	defer file.Close()
+
+	m := entity.EstablishmentImport{
+		Category: body.Category,
+		Format:   importFormat(body.Format, body.File.Filename),
+		OwnerID:  ownerID,
+		Locale:   h.locale(c),
+		DryRun:   body.DryRun == nil || *body.DryRun,
+	}
+	err = h.EstablishmentImport.Create(ctx, &m, file)
+	var errBadRequest *errorspkg.ErrBadRequest
+	if errors.As(err, &errBadRequest) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to create import", l.Error(err))
+		return
+	}
+
+	if err := h.scheduleImport(ctx, m.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to schedule import", l.Error(err))
+		return
+	}
+
+	c.JSON(http.StatusAccepted, importRes(&m, false))
+}
+
+// LIST ESTABLISHMENT IMPORTS
+// @Summary LIST ESTABLISHMENT IMPORTS
+// @Security BearerAuth
+// @Description Api for listing imports with their progress, newest first
+// @Tags IMPORT
+// @Accept json
+// @Produce json
+// @Param request query models.Pagination true "request"
+// @Success 200 {object} models.ListImportsRes
+// @Failure 400 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/establishments/imports [GET]
+func (h *HandlerV1) ListImports(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "ListImports")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	params, errStr := utils.ParseQueryParam(c.Request.URL.Query())
+	if errStr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": h.errorMessage(c, errStr[0]),
+		})
+		return
+	}
+
+	imports, count, err := h.EstablishmentImport.List(ctx, params.Limit, (params.Page-1)*params.Limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to list imports", l.Error(err))
+		return
+	}
+
+	response := models.ListImportsRes{
+		Imports: []*models.ImportRes{},
+		Count:   count,
+	}
+	for _, m := range imports {
+		response.Imports = append(response.Imports, importRes(m, false))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GET ESTABLISHMENT IMPORT
+// @Summary GET ESTABLISHMENT IMPORT
+// @Security BearerAuth
+// @Description Api for following an import, with the error of every row that was not imported. Errors are in the language the import was started in
+// @Tags IMPORT
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} models.ImportRes
+// @Failure 404 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/establishments/imports/{id} [GET]
+func (h *HandlerV1) GetImport(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "GetImport")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	m, err := h.EstablishmentImport.Get(ctx, c.Param("id"))
+	if errors.Is(err, errorspkg.ErrorNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": h.message(c, "import_not_found"),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to get import", l.Error(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, importRes(m, true))
+}
+
+// APPLY ESTABLISHMENT IMPORT
+// @Summary APPLY ESTABLISHMENT IMPORT
+// @Security BearerAuth
+// @Description Api for running a finished dry run for real, with the same rows
+// @Tags IMPORT
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Success 202 {object} models.ImportRes
+// @Failure 400 {object} models.StandartError
+// @Failure 404 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/establishments/imports/{id}/apply [POST]
+func (h *HandlerV1) ApplyImport(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "ApplyImport")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	m, err := h.EstablishmentImport.Apply(ctx, c.Param("id"))
+	var errBadRequest *errorspkg.ErrBadRequest
+	switch {
+	case errors.As(err, &errBadRequest):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	case errors.Is(err, errorspkg.ErrorNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": h.message(c, "import_not_found"),
+		})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to apply import", l.Error(err))
+		return
+	}
+
+	if err := h.scheduleImport(ctx, m.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to schedule import", l.Error(err))
+		return
+	}
+
+	c.JSON(http.StatusAccepted, importRes(m, false))
+}
+
+// importFormat is the format asked for, or the one the extension of
+// filename stands for
+func importFormat(format, filename string) string {
+	if format != "" {
+		return strings.ToLower(format)
+	}
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return entity.ImportFormatCSV
+	case ".ndjson", ".jsonl":
+		return entity.ImportFormatNDJSON
+	}
+	return ""
+}
+
+func importRes(m *entity.EstablishmentImport, withErrors bool) *models.ImportRes {
+	response := models.ImportRes{
+		Id:        m.ID,
+		Category:  m.Category,
+		Format:    m.Format,
+		DryRun:    m.DryRun,
+		Status:    m.Status,
+		Total:     m.Total,
+		Processed: m.Processed,
+		Created:   m.Created,
+		Updated:   m.Updated,
+		Failed:    m.Failed,
+		CreatedAt: m.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: m.UpdatedAt.Format(time.RFC3339),
+	}
+	if m.Total > 0 {
+		response.Progress = math.Round(float64(m.Processed)*1000/float64(m.Total)) / 10
+	}
+	if withErrors {
+		response.Errors = []*models.ImportRowErrorRes{}
+		for _, rowErr := range m.Errors {
+			response.Errors = append(response.Errors, &models.ImportRowErrorRes{
+				Line:        rowErr.Line,
+				ExternalRef: rowErr.ExternalRef,
+				Error:       rowErr.Error,
+			})
+		}
+	}
+	return &response
+}
+
+// scheduleImport has the import job go through the next rows of an import
+// right away
+func (h *HandlerV1) scheduleImport(ctx context.Context, id string) error {
+	return h.Scheduler.Schedule(ctx, &entity.Job{
+		Kind:    entity.JobKindEstablishmentImport,
+		Payload: map[string]string{"import_id": id},
+		RunAt:   time.Now().UTC(),
+	})
+}
+
+// runImport goes through the next batch of rows of an import, stopping
+// early when the job is about to time out, and schedules itself again
+// while rows are left. Progress is saved after every batch, a row whose
+// establishment was written but not saved is matched by its external ref
+// when it is gone through again.
+func (h *HandlerV1) runImport(ctx context.Context, job *entity.Job) error {
+	m, err := h.EstablishmentImport.Get(ctx, job.Payload["import_id"])
+	if errors.Is(err, errorspkg.ErrorNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if m.Status == entity.ImportStatusDone {
+		return nil
+	}
+
+	m.Status = entity.ImportStatusRunning
+	for done := 0; done < importBatchSize && m.NextRow < len(m.Rows); done++ {
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < importRowTime {
+			break
+		}
+
+		row := m.Rows[m.NextRow]
+		created, err := h.importRow(ctx, m, row)
+		switch {
+		case err != nil:
+			m.Failed++
+			m.Errors = append(m.Errors, &entity.ImportRowError{
+				Line:        row.Line,
+				ExternalRef: row.ExternalRef,
+				Error:       i18n.Message(m.Locale, err),
+			})
+		case created:
+			m.Created++
+		default:
+			m.Updated++
+		}
+		m.NextRow++
+		m.Processed++
+	}
+
+	if m.NextRow >= len(m.Rows) {
+		m.Status = entity.ImportStatusDone
+	}
+	if err := h.EstablishmentImport.Update(ctx, m); err != nil {
+		return err
+	}
+	if m.Status != entity.ImportStatusDone {
+		return h.scheduleImport(ctx, m.ID)
+	}
+	return nil
+}
+
+// importRow creates the establishment of row, or updates it if its
+// external ref was imported before, and reports whether it was created. A
+// dry run only checks the row and tells what it would do. Errors that are
+// not about the row are logged and reported as such. The ref of a new row
+// is saved under the id its establishment is then created with, so a
+// create that was cut off is finished the next time the row is imported
+// instead of made twice.
+func (h *HandlerV1) importRow(ctx context.Context, m *entity.EstablishmentImport, row *entity.ImportRow) (bool, error) {
+	tags, err := h.Tag.Check(ctx, m.Category, row.Tags)
+	var errBadRequest *errorspkg.ErrBadRequest
+	if errors.As(err, &errBadRequest) {
+		return false, err
+	}
+	if err != nil {
+		h.Logger.Error("failed to check imported tags", l.Error(err))
+		return false, i18n.NewError("try_again_later")
+	}
+
+	ref, err := h.EstablishmentImport.Ref(ctx, m.Category, row.ExternalRef)
+	if errors.Is(err, errorspkg.ErrorNotFound) {
+		ref, err = nil, nil
+	}
+	if err != nil {
+		h.Logger.Error("failed to get import ref", l.Error(err))
+		return false, i18n.NewError("try_again_later")
+	}
+	if m.DryRun {
+		return ref == nil, nil
+	}
+
+	created, id := ref == nil, uuid.New().String()
+	if !created {
+		id = ref.EstablishmentID
+	}
+	if err := h.EstablishmentImport.SaveRef(ctx, &entity.ImportRef{
+		Category:        m.Category,
+		ExternalRef:     row.ExternalRef,
+		EstablishmentID: id,
+		ImportID:        m.ID,
+	}); err != nil {
+		h.Logger.Error("failed to save import ref", l.Error(err))
+		// without its ref a created establishment would be created again
+		// the next time its row is imported
+		if created {
+			return false, i18n.NewError("try_again_later")
+		}
+	}
+
+	var imported *indexedEstablishment
+	if !created {
+		imported, err = h.updateImported(ctx, m.Category, id, row)
+		// the ref of a create that was cut off, refs of deleted
+		// establishments are dropped with them
+		created = status.Code(err) == codes.NotFound
+	}
+	if created {
+		ownerID := row.OwnerID
+		if ownerID == "" {
+			ownerID = m.OwnerID
+		}
+		imported, err = h.createImported(ctx, m.Category, id, ownerID, row)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	h.indexEstablishment(ctx, imported)
+	if created || row.Tags != nil {
+		h.saveTags(ctx, m.Category, imported.id, tags)
+	}
+	return created, nil
+}
+
+// createImported creates the establishment of an imported row as id
+func (h *HandlerV1) createImported(ctx context.Context, category, id, ownerID string, row *entity.ImportRow) (*indexedEstablishment, error) {
+	location := importedLocation(row)
+	location.LocationId = uuid.New().String()
+	location.EstablishmentId = id
+	location.Category = category
+
+	switch category {
+	case categoryHotel:
+		response, err := h.Service.EstablishmentService().CreateHotel(ctx, &pbe.Hotel{
+			HotelId:       id,
+			OwnerId:       ownerID,
+			HotelName:     row.Name,
+			Description:   row.Description,
+			Rating:        float32(row.Rating),
+			ContactNumber: row.ContactNumber,
+			LicenceUrl:    row.LicenceUrl,
+			WebsiteUrl:    row.WebsiteUrl,
+			Location:      location,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return indexedHotel(response), nil
+	case categoryRestaurant:
+		response, err := h.Service.EstablishmentService().CreateRestaurant(ctx, &pbe.Restaurant{
+			RestaurantId:   id,
+			OwnerId:        ownerID,
+			RestaurantName: row.Name,
+			Description:    row.Description,
+			Rating:         float32(row.Rating),
+			OpeningHours:   row.OpeningHours,
+			ContactNumber:  row.ContactNumber,
+			LicenceUrl:     row.LicenceUrl,
+			WebsiteUrl:     row.WebsiteUrl,
+			Location:       location,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return indexedRestaurant(response), nil
+	default:
+		response, err := h.Service.EstablishmentService().CreateAttraction(ctx, &pbe.Attraction{
+			AttractionId:   id,
+			OwnerId:        ownerID,
+			AttractionName: row.Name,
+			Description:    row.Description,
+			Rating:         float32(row.Rating),
+			ContactNumber:  row.ContactNumber,
+			LicenceUrl:     row.LicenceUrl,
+			WebsiteUrl:     row.WebsiteUrl,
+			Location:       location,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return indexedAttraction(response), nil
+	}
+}
+
+// updateImported writes an imported row over the establishment its
+// external ref was imported as
+func (h *HandlerV1) updateImported(ctx context.Context, category, id string, row *entity.ImportRow) (*indexedEstablishment, error) {
+	switch category {
+	case categoryHotel:
+		response, err := h.Service.EstablishmentService().UpdateHotel(ctx, &pbe.UpdateHotelRequest{
+			Hotel: &pbe.Hotel{
+				HotelId:       id,
+				HotelName:     row.Name,
+				Description:   row.Description,
+				Rating:        float32(row.Rating),
+				ContactNumber: row.ContactNumber,
+				LicenceUrl:    row.LicenceUrl,
+				WebsiteUrl:    row.WebsiteUrl,
+				Location:      importedLocation(row),
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		return indexedHotel(response.Hotel), nil
+	case categoryRestaurant:
+		response, err := h.Service.EstablishmentService().UpdateRestaurant(ctx, &pbe.UpdateRestaurantRequest{
+			Restaurant: &pbe.Restaurant{
+				RestaurantId:   id,
+				RestaurantName: row.Name,
+				Description:    row.Description,
+				Rating:         float32(row.Rating),
+				OpeningHours:   row.OpeningHours,
+				ContactNumber:  row.ContactNumber,
+				LicenceUrl:     row.LicenceUrl,
+				WebsiteUrl:     row.WebsiteUrl,
+				Location:       importedLocation(row),
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		return indexedRestaurant(response.Restaurant), nil
+	default:
+		response, err := h.Service.EstablishmentService().UpdateAttraction(ctx, &pbe.UpdateAttractionRequest{
+			Attraction: &pbe.Attraction{
+				AttractionId:   id,
+				AttractionName: row.Name,
+				Description:    row.Description,
+				Rating:         float32(row.Rating),
+				ContactNumber:  row.ContactNumber,
+				LicenceUrl:     row.LicenceUrl,
+				WebsiteUrl:     row.WebsiteUrl,
+				Location:       importedLocation(row),
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		return indexedAttraction(response.Attraction), nil
+	}
+}
+
+func importedLocation(row *entity.ImportRow) *pbe.Location {
+	return &pbe.Location{
+		Address:       row.Address,
+		Latitude:      float32(row.Latitude),
+		Longitude:     float32(row.Longitude),
+		Country:       row.Country,
+		City:          row.City,
+		StateProvince: row.StateProvince,
+	}
+}
@@ -0,0 +1,222 @@
+package v1
+
+import (
+	"context"
+
+	pbe "Booking/api-service-booking/genproto/establishment-proto"
+	"Booking/api-service-booking/internal/entity"
+	"Booking/api-service-booking/internal/pkg/booktime"
+	l "Booking/api-service-booking/internal/pkg/logger"
+)
+
+// reindexPageSize is how many establishments a reindex reads at a time
+const reindexPageSize = 100
+
+// indexedEstablishment is what the search indexes and exports take of a
+// hotel, restaurant or attraction
+type indexedEstablishment struct {
+	category      string
+	id            string
+	ownerID       string
+	name          string
+	description   string
+	rating        float32
+	contactNumber string
+	licenceUrl    string
+	websiteUrl    string
+	images        []*pbe.Image
+	location      *pbe.Location
+	createdAt     string
+	updatedAt     string
+	// openingHours is the plain opening hours of a restaurant
+	openingHours string
+}
+
+func indexedHotel(hotel *pbe.Hotel) *indexedEstablishment {
+	return &indexedEstablishment{
+		category:      categoryHotel,
+		id:            hotel.HotelId,
+		ownerID:       hotel.OwnerId,
+		name:          hotel.HotelName,
+		description:   hotel.Description,
+		rating:        hotel.Rating,
+		contactNumber: hotel.ContactNumber,
+		licenceUrl:    hotel.LicenceUrl,
+		websiteUrl:    hotel.WebsiteUrl,
+		images:        hotel.Images,
+		location:      hotel.Location,
+		createdAt:     hotel.CreatedAt,
+		updatedAt:     hotel.UpdatedAt,
+	}
+}
+
+func indexedRestaurant(restaurant *pbe.Restaurant) *indexedEstablishment {
+	return &indexedEstablishment{
+		category:      categoryRestaurant,
+		id:            restaurant.RestaurantId,
+		ownerID:       restaurant.OwnerId,
+		name:          restaurant.RestaurantName,
+		description:   restaurant.Description,
+		rating:        restaurant.Rating,
+		contactNumber: restaurant.ContactNumber,
+		licenceUrl:    restaurant.LicenceUrl,
+		websiteUrl:    restaurant.WebsiteUrl,
+		images:        restaurant.Images,
+		location:      restaurant.Location,
+		createdAt:     restaurant.CreatedAt,
+		updatedAt:     restaurant.UpdatedAt,
+		openingHours:  restaurant.OpeningHours,
+	}
+}
+
+func indexedAttraction(attraction *pbe.Attraction) *indexedEstablishment {
+	return &indexedEstablishment{
+		category:      categoryAttraction,
+		id:            attraction.AttractionId,
+		ownerID:       attraction.OwnerId,
+		name:          attraction.AttractionName,
+		description:   attraction.Description,
+		rating:        attraction.Rating,
+		contactNumber: attraction.ContactNumber,
+		licenceUrl:    attraction.LicenceUrl,
+		websiteUrl:    attraction.WebsiteUrl,
+		images:        attraction.Images,
+		location:      attraction.Location,
+		createdAt:     attraction.CreatedAt,
+		updatedAt:     attraction.UpdatedAt,
+	}
+}
+
+// thumbnail is the first image of the establishment
+func (e *indexedEstablishment) thumbnail() string {
+	if len(e.images) == 0 {
+		return ""
+	}
+	return e.images[0].ImageUrl
+}
+
+// indexEstablishment puts a created or updated establishment into the
+// nearby and text search indexes, the nearby one only once it has a
+// location. They only serve search, so a failure is logged and the write
+// it follows still succeeds.
+func (h *HandlerV1) indexEstablishment(ctx context.Context, e *indexedEstablishment) {
+	if e.id == "" {
+		return
+	}
+	if e.location != nil {
+		if err := h.GeoSearch.Index(ctx, geoPlace(e)); err != nil {
+			h.Logger.Error("failed to index establishment position", l.Error(err))
+		}
+	}
+	if err := h.EstablishmentSearch.Index(ctx, searchDocument(e)); err != nil {
+		h.Logger.Error("failed to index establishment for search", l.Error(err))
+	}
+}
+
+// unindexEstablishment takes a deleted establishment out of the search
+// indexes and drops its tags, translations and import refs
+func (h *HandlerV1) unindexEstablishment(ctx context.Context, category, id string) {
+	h.saveTags(ctx, category, id, nil)
+	if err := h.Translation.DeleteAll(ctx, category, id); err != nil {
+		h.Logger.Error("failed to delete translations", l.Error(err))
+	}
+	if err := h.EstablishmentImport.DeleteRefs(ctx, category, id); err != nil {
+		h.Logger.Error("failed to delete import refs", l.Error(err))
+	}
+	if err := h.GeoSearch.Remove(ctx, category, id); err != nil {
+		h.Logger.Error("failed to remove establishment position", l.Error(err))
+	}
+	if err := h.EstablishmentSearch.Remove(ctx, category, id); err != nil {
+		h.Logger.Error("failed to remove establishment from search", l.Error(err))
+	}
+}
+
+// reindex pages through every establishment of category and hands each to
+// index, returning how many there were. Some may have no location yet.
+func (h *HandlerV1) reindex(ctx context.Context, category string, index func(e *indexedEstablishment) error) (int, error) {
+	indexed := 0
+	for offset := int64(0); ; offset += reindexPageSize {
+		page, err := h.establishmentPage(ctx, category, offset, reindexPageSize)
+		if err != nil {
+			return indexed, err
+		}
+
+		for _, e := range page {
+			if e.id == "" {
+				continue
+			}
+			if err := index(e); err != nil {
+				return indexed, err
+			}
+			indexed++
+		}
+		if len(page) < reindexPageSize {
+			return indexed, nil
+		}
+	}
+}
+
+// establishmentPage lists limit establishments of category from offset on
+func (h *HandlerV1) establishmentPage(ctx context.Context, category string, offset, limit int64) ([]*indexedEstablishment, error) {
+	var page []*indexedEstablishment
+	switch category {
+	case categoryHotel:
+		response, err := h.Service.EstablishmentService().ListHotels(ctx, &pbe.ListHotelsRequest{
+			Offset: offset,
+			Limit:  limit,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, hotel := range response.Hotels {
+			page = append(page, indexedHotel(hotel))
+		}
+	case categoryRestaurant:
+		response, err := h.Service.EstablishmentService().ListRestaurants(ctx, &pbe.ListRestaurantsRequest{
+			Offset: offset,
+			Limit:  limit,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, restaurant := range response.Restaurants {
+			page = append(page, indexedRestaurant(restaurant))
+		}
+	case categoryAttraction:
+		response, err := h.Service.EstablishmentService().ListAttractions(ctx, &pbe.ListAttractionsRequest{
+			Offset: offset,
+			Limit:  limit,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, attraction := range response.Attractions {
+			page = append(page, indexedAttraction(attraction))
+		}
+	}
+	return page, nil
+}
+
+// searchDocument is the part of an establishment text search keeps, an
+// establishment without a location is found by name only
+func searchDocument(e *indexedEstablishment) *entity.SearchDocument {
+	document := entity.SearchDocument{
+		Category:        e.category,
+		EstablishmentID: e.id,
+		Name:            e.name,
+		Description:     e.description,
+		Thumbnail:       e.thumbnail(),
+		Rating:          float64(e.rating),
+		OpeningHours:    e.openingHours,
+	}
+	if e.location != nil {
+		document.City = e.location.City
+		document.Address = e.location.Address
+		document.Latitude = float64(e.location.Latitude)
+		document.Longitude = float64(e.location.Longitude)
+	}
+	if createdAt, _, err := booktime.Parse(e.createdAt); err == nil {
+		document.CreatedAt = &createdAt
+	}
+	return &document
+}
@@ -0,0 +1,294 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"Booking/api-service-booking/api/models"
+	pbe "Booking/api-service-booking/genproto/establishment-proto"
+	"Booking/api-service-booking/internal/entity"
+	errorspkg "Booking/api-service-booking/internal/errors"
+	"Booking/api-service-booking/internal/pkg/i18n"
+	l "Booking/api-service-booking/internal/pkg/logger"
+	"Booking/api-service-booking/internal/pkg/query_parameter"
+)
+
+// listFetchers bounds how many establishments a filtered list fetches
+// from the establishment service at once
+const listFetchers = 8
+
+// establishmentListSchema is what the hotel, restaurant and attraction
+// lists accept in their query. near is where distance is sorted from,
+// zoom is read by writeListClusters.
+var establishmentListSchema = query_parameter.Schema{
+	Filters:     []string{"city", "tags", "min_rating", "price_min", "price_max", "open_now", "open_at", "near", "zoom"},
+	Sortable:    []string{"rating", "distance", "popularity", "created_at", "name"},
+	DefaultSort: "-rating",
+	MaxLimit:    100,
+}
+
+// establishmentListFilters are the filters that make a list go through the
+// search index instead of the establishment service
+var establishmentListFilters = []string{"city", "tags", "min_rating", "price_min", "price_max", "open_now", "open_at"}
+
+// establishmentList is a parsed list request. Filtered lists take their
+// page from the search index, the others from the establishment service
+// in its own order. Both get facets from the index. Only the index pages
+// by cursor, so a cursor makes the list filtered.
+type establishmentList struct {
+	page     *entity.EstablishmentPage
+	filtered bool
+	offset   uint64
+	limit    uint64
+}
+
+// listEstablishments parses the list query of category and reads the
+// matching page and facets from the search index. It writes the error
+// response and returns nil if the request is wrong or, for a filtered
+// list, the index fails. A GeoJSON list with a zoom is answered here with
+// the clusters of all the matches, and nil is returned for it as well.
+func (h *HandlerV1) listEstablishments(ctx context.Context, c *gin.Context, category string) *establishmentList {
+	search, err := establishmentListSchema.Parse(query_parameter.New(c.Request.URL.Query()))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return nil
+	}
+
+	list := establishmentList{
+		filtered: c.Query("sort") != "" || search.Cursor != "",
+		offset:   (search.Page - 1) * search.Limit,
+		limit:    search.Limit,
+	}
+	for _, key := range establishmentListFilters {
+		if search.Filters[key] != "" {
+			list.filtered = true
+		}
+	}
+
+	filter, err := establishmentListFilter(search, category)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return nil
+	}
+	filter.Offset = int(list.offset)
+	filter.Limit = int(list.limit)
+
+	at, err := openAt(search)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return nil
+	}
+	if at != nil {
+		if filter.OpenIDs, err = h.OpeningHours.OpenAt(ctx, category, *at); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": h.message(c, "try_again_later"),
+			})
+			h.Logger.Error("failed to find open establishments", l.Error(err))
+			return nil
+		}
+	}
+
+	if wantsGeoJSON(c) && c.Query("zoom") != "" {
+		h.writeListClusters(ctx, c, filter)
+		return nil
+	}
+
+	list.page, err = h.EstablishmentSearch.List(ctx, filter, search.Cursor)
+	var errBadRequest *errorspkg.ErrBadRequest
+	if errors.As(err, &errBadRequest) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return nil
+	}
+	if err != nil {
+		if list.filtered {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": h.message(c, "try_again_later"),
+			})
+			h.Logger.Error("failed to list establishments", l.Error(err))
+			return nil
+		}
+		// an unfiltered list still works without its facets
+		h.Logger.Error("failed to count establishment facets", l.Error(err))
+	}
+
+	return &list
+}
+
+// establishmentListFilter reads the filters and sort of a list query
+func establishmentListFilter(search *query_parameter.Search, category string) (*entity.EstablishmentList, error) {
+	filter := entity.EstablishmentList{
+		Category: category,
+		Cities:   search.List("city"),
+		Tags:     search.List("tags"),
+		SortBy:   search.Sort.Field,
+		Desc:     search.Sort.Desc,
+	}
+
+	var err error
+	if value := search.Filters["min_rating"]; value != "" {
+		if filter.MinRating, err = strconv.ParseFloat(value, 64); err != nil {
+			return nil, i18n.NewError("field_number", "min_rating")
+		}
+	}
+	if value := search.Filters["price_min"]; value != "" {
+		if filter.MinPrice, err = strconv.ParseInt(value, 10, 64); err != nil {
+			return nil, i18n.NewError("field_whole_number", "price_min")
+		}
+	}
+	if value := search.Filters["price_max"]; value != "" {
+		if filter.MaxPrice, err = strconv.ParseInt(value, 10, 64); err != nil {
+			return nil, i18n.NewError("field_whole_number", "price_max")
+		}
+	}
+	if value := search.Filters["near"]; value != "" {
+		point, err := parseFloats(value, 2)
+		if err != nil {
+			return nil, i18n.NewError("field_format", "near", "lat,lng")
+		}
+		filter.Latitude, filter.Longitude = point[0], point[1]
+	}
+
+	return &filter, nil
+}
+
+// cursors are the cursors of the pages next to a filtered one
+func (list *establishmentList) cursors() (next, prev string) {
+	if !list.filtered || list.page == nil {
+		return "", ""
+	}
+	return list.page.NextCursor, list.page.PrevCursor
+}
+
+// facets is the facet part of a list response, nil if the index failed
+func (list *establishmentList) facets() *models.EstablishmentFacetsRes {
+	if list.page == nil || list.page.Facets == nil {
+		return nil
+	}
+
+	facets := models.EstablishmentFacetsRes{
+		City:   []*models.FacetRes{},
+		Rating: []*models.FacetRes{},
+		Tag:    []*models.FacetRes{},
+		Price: models.PriceRangeRes{
+			Min: list.page.Facets.MinPrice,
+			Max: list.page.Facets.MaxPrice,
+		},
+	}
+	for _, facet := range list.page.Facets.Cities {
+		facets.City = append(facets.City, &models.FacetRes{Value: facet.Value, Count: facet.Count})
+	}
+	for _, facet := range list.page.Facets.Ratings {
+		facets.Rating = append(facets.Rating, &models.FacetRes{Value: facet.Value, Count: facet.Count})
+	}
+	for _, facet := range list.page.Facets.Tags {
+		facets.Tag = append(facets.Tag, &models.FacetRes{Value: facet.Value, Count: facet.Count})
+	}
+	return &facets
+}
+
+// fetchListed calls fetch for every id of a filtered page, a few at a
+// time. An establishment deleted since it was indexed is skipped.
+func fetchListed(ctx context.Context, ids []string, fetch func(ctx context.Context, i int, id string) error) error {
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		slots    = make(chan struct{}, listFetchers)
+	)
+	for i, id := range ids {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(i int, id string) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			if err := fetch(ctx, i, id); err != nil && status.Code(err) != codes.NotFound {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+			}
+		}(i, id)
+	}
+	wg.Wait()
+	return firstErr
+}
+
+// listedHotels fetches the hotels of a filtered page in its order
+func (h *HandlerV1) listedHotels(ctx context.Context, ids []string) ([]*pbe.Hotel, error) {
+	hotels := make([]*pbe.Hotel, len(ids))
+	err := fetchListed(ctx, ids, func(ctx context.Context, i int, id string) error {
+		response, err := h.Service.EstablishmentService().GetHotel(ctx, &pbe.GetHotelRequest{HotelId: id})
+		if err != nil {
+			return err
+		}
+		hotels[i] = response.Hotel
+		return nil
+	})
+
+	listed := hotels[:0]
+	for _, hotel := range hotels {
+		if hotel != nil {
+			listed = append(listed, hotel)
+		}
+	}
+	return listed, err
+}
+
+// listedRestaurants fetches the restaurants of a filtered page in its order
+func (h *HandlerV1) listedRestaurants(ctx context.Context, ids []string) ([]*pbe.Restaurant, error) {
+	restaurants := make([]*pbe.Restaurant, len(ids))
+	err := fetchListed(ctx, ids, func(ctx context.Context, i int, id string) error {
+		response, err := h.Service.EstablishmentService().GetRestaurant(ctx, &pbe.GetRestaurantRequest{RestaurantId: id})
+		if err != nil {
+			return err
+		}
+		restaurants[i] = response.Restaurant
+		return nil
+	})
+
+	listed := restaurants[:0]
+	for _, restaurant := range restaurants {
+		if restaurant != nil {
+			listed = append(listed, restaurant)
+		}
+	}
+	return listed, err
+}
+
+// listedAttractions fetches the attractions of a filtered page in its order
+func (h *HandlerV1) listedAttractions(ctx context.Context, ids []string) ([]*pbe.Attraction, error) {
+	attractions := make([]*pbe.Attraction, len(ids))
+	err := fetchListed(ctx, ids, func(ctx context.Context, i int, id string) error {
+		response, err := h.Service.EstablishmentService().GetAttraction(ctx, &pbe.GetAttractionRequest{AttractionId: id})
+		if err != nil {
+			return err
+		}
+		attractions[i] = response.Attraction
+		return nil
+	})
+
+	listed := attractions[:0]
+	for _, attraction := range attractions {
+		if attraction != nil {
+			listed = append(listed, attraction)
+		}
+	}
+	return listed, err
+}
@@ -0,0 +1,173 @@
+package v1
+
+import (
+	"errors"
+	"math"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+
+	"Booking/api-service-booking/api/models"
+	"Booking/api-service-booking/internal/entity"
+	errorspkg "Booking/api-service-booking/internal/errors"
+	"Booking/api-service-booking/internal/pkg/geojson"
+	l "Booking/api-service-booking/internal/pkg/logger"
+	"Booking/api-service-booking/internal/pkg/otlp"
+)
+
+// SEARCH ESTABLISHMENTS
+// @Summary SEARCH ESTABLISHMENTS
+// @Security BearerAuth
+// @Description Api for searching hotels, restaurants and attractions together by name, city and description, best match first. Typos are tolerated and Latin, Cyrillic and English spellings match each other, so Registon, Регистан and Registan find the same place. category takes a comma separated list and defaults to all, tags a comma separated list of tag slugs all of which must be there
+// @Tags SEARCH
+// @Accept json
+// @Produce json
+// @Produce application/geo+json
+// @Param request query models.EstablishmentSearchReq true "request"
+// @Param zoom query int false "zoom, clusters the GeoJSON points of this page only"
+// @Success 200 {object} models.EstablishmentSearchRes
+// @Failure 400 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/establishments/search [GET]
+func (h *HandlerV1) SearchEstablishments(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "SearchEstablishments")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	var body models.EstablishmentSearchReq
+	if err := c.ShouldBindQuery(&body); err != nil {
+		h.bindError(c, err)
+		return
+	}
+	if body.Page == 0 {
+		body.Page = 1
+	}
+	if body.Limit == 0 {
+		body.Limit = 20
+	}
+
+	hits, err := h.EstablishmentSearch.Search(ctx, &entity.EstablishmentSearch{
+		Query:      body.Q,
+		Categories: splitList(body.Category),
+		Tags:       splitList(body.Tags),
+		Limit:      body.Limit,
+		Offset:     (body.Page - 1) * body.Limit,
+	})
+	var errBadRequest *errorspkg.ErrBadRequest
+	if errors.As(err, &errBadRequest) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to search establishments", l.Error(err))
+		return
+	}
+
+	// searches finding something feed the popular query suggestions, a
+	// query is counted once however many pages are read
+	if len(hits) > 0 && body.Page == 1 {
+		if err := h.Suggest.RecordQuery(ctx, body.Q); err != nil {
+			h.Logger.Error("failed to record search query", l.Error(err))
+		}
+	}
+
+	response := models.EstablishmentSearchRes{
+		Results: []*models.EstablishmentSearchHitRes{},
+		Count:   len(hits),
+	}
+	for _, hit := range hits {
+		response.Results = append(response.Results, searchHitRes(hit))
+	}
+
+	if wantsGeoJSON(c) {
+		h.writeGeoJSON(c, searchFeatures(response.Results))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// REINDEX ESTABLISHMENT SEARCH
+// @Summary REINDEX ESTABLISHMENT SEARCH
+// @Security BearerAuth
+// @Description Api for building the text search index again from the establishment service. Establishments are indexed as they are created, updated and deleted, this is for filling the index the first time or after it was lost. Search misses a category while it is rebuilt
+// @Tags SEARCH
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.ReindexSearchRes
+// @Failure 500 {object} models.StandartError
+// @Router /v1/establishments/search/reindex [POST]
+func (h *HandlerV1) ReindexSearch(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "ReindexSearch")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	response := models.ReindexSearchRes{
+		Indexed: map[string]int{},
+	}
+	for _, category := range []string{categoryHotel, categoryRestaurant, categoryAttraction} {
+		if err := h.EstablishmentSearch.Clear(ctx, category); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": h.message(c, "try_again_later"),
+			})
+			h.Logger.Error("failed to clear search index", l.Error(err))
+			return
+		}
+
+		indexed, err := h.reindex(ctx, category, func(e *indexedEstablishment) error {
+			return h.EstablishmentSearch.Index(ctx, searchDocument(e))
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": h.message(c, "try_again_later"),
+			})
+			h.Logger.Error("failed to reindex search", l.Error(err))
+			return
+		}
+		response.Indexed[category] = indexed
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func searchHitRes(hit *entity.SearchHit) *models.EstablishmentSearchHitRes {
+	return &models.EstablishmentSearchHitRes{
+		Category:    hit.Document.Category,
+		HraId:       hit.Document.EstablishmentID,
+		Name:        hit.Document.Name,
+		Description: hit.Document.Description,
+		City:        hit.Document.City,
+		Address:     hit.Document.Address,
+		Thumbnail:   hit.Document.Thumbnail,
+		Rating:      hit.Document.Rating,
+		Latitude:    hit.Document.Latitude,
+		Longitude:   hit.Document.Longitude,
+		Score:       math.Round(hit.Score*1000) / 1000,
+	}
+}
+
+func searchFeatures(results []*models.EstablishmentSearchHitRes) []*geojson.Feature {
+	features := make([]*geojson.Feature, 0, len(results))
+	for _, result := range results {
+		features = append(features, geojson.Point(result.HraId, result.Latitude, result.Longitude, map[string]interface{}{
+			"name":      result.Name,
+			"rating":    result.Rating,
+			"type":      result.Category,
+			"thumbnail": result.Thumbnail,
+			"address":   result.Address,
+			"city":      result.City,
+			"score":     result.Score,
+		}))
+	}
+	return features
+}
@@ -0,0 +1,212 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+
+	"Booking/api-service-booking/api/models"
+	"Booking/api-service-booking/internal/entity"
+	errorspkg "Booking/api-service-booking/internal/errors"
+	"Booking/api-service-booking/internal/pkg/eticket"
+	l "Booking/api-service-booking/internal/pkg/logger"
+	"Booking/api-service-booking/internal/pkg/otlp"
+)
+
+// GET BOOKING QR CODE
+// @Summary GET BOOKING QR CODE
+// @Security BearerAuth
+// @Description Api for getting the signed QR e-ticket of a booking as png or svg
+// @Tags E-TICKET
+// @Produce png
+// @Produce image/svg+xml
+// @Param id path string true "booking id"
+// @Param request query models.QRReq false "request"
+// @Success 200 {file} file
+// @Failure 400 {object} models.StandartError
+// @Failure 404 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/tickets/{id}/qr [GET]
+func (h *HandlerV1) GetBookingQR(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "GetBookingQR")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	body := models.QRReq{
+		Format: "png",
+	}
+	if err := c.ShouldBindQuery(&body); err != nil {
+		h.bindError(c, err)
+		return
+	}
+
+	userID, statusCode := GetIdFromToken(c.Request, h.Config)
+	if statusCode != http.StatusOK {
+		c.JSON(statusCode, gin.H{
+			"error": h.message(c, "cant_get_user"),
+		})
+		return
+	}
+
+	// the booking of another user is reported as missing on purpose
+	record, err := h.BookingRecord.Get(ctx, c.Param("id"))
+	if errors.Is(err, errorspkg.ErrorNotFound) || (err == nil && record.UserID != userID) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": h.message(c, "booking_not_found"),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to get booking record", l.Error(err))
+		return
+	}
+
+	payload := eticket.Sign(h.Config.ETicket.Secret, record.ID)
+
+	var (
+		image       []byte
+		contentType string
+	)
+	switch body.Format {
+	case "png":
+		image, err = eticket.PNG(payload, h.Config.ETicket.QRSize)
+		contentType = "image/png"
+	case "svg":
+		image, err = eticket.SVG(payload)
+		contentType = "image/svg+xml"
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": h.message(c, "field_one_of", "format", "png, svg"),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to render qr code", l.Error(err))
+		return
+	}
+
+	c.Data(http.StatusOK, contentType, image)
+}
+
+// CHECK IN
+// @Summary CHECK IN
+// @Security BearerAuth
+// @Description Api for staff to check a guest in by the scanned QR payload, every ticket is accepted only once
+// @Tags E-TICKET
+// @Accept json
+// @Produce json
+// @Param CheckIn body models.CheckInReq true "CheckIn"
+// @Success 200 {object} models.CheckInRes
+// @Failure 400 {object} models.StandartError
+// @Failure 403 {object} models.StandartError
+// @Failure 404 {object} models.StandartError
+// @Failure 409 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/checkin [POST]
+func (h *HandlerV1) CheckIn(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "CheckIn")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	var body models.CheckInReq
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.bindError(c, err)
+		return
+	}
+
+	staffID, statusCode := GetIdFromToken(c.Request, h.Config)
+	if statusCode != http.StatusOK {
+		c.JSON(statusCode, gin.H{
+			"error": h.message(c, "cant_get_user"),
+		})
+		return
+	}
+
+	bookingID, err := eticket.Verify(h.Config.ETicket.Secret, body.Payload)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": h.message(c, "ticket_invalid"),
+		})
+		return
+	}
+
+	record, err := h.BookingRecord.Get(ctx, bookingID)
+	if errors.Is(err, errorspkg.ErrorNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": h.message(c, "booking_not_found"),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to get booking record", l.Error(err))
+		return
+	}
+
+	member, err := h.Staff.IsMember(ctx, staffID, record.EstablishmentID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to check staff membership", l.Error(err))
+		return
+	}
+	if !member {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": h.message(c, "booking_other_establishment"),
+		})
+		return
+	}
+
+	// the conditional state change is what rejects a second scan
+	changed, err := h.BookingRecord.ChangeState(ctx, record.ID, []string{entity.BookingStateConfirmed}, entity.BookingStateCheckedIn)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to check booking in", l.Error(err))
+		return
+	}
+	if !changed {
+		message := h.message(c, "booking_is", record.State)
+		if record.State == entity.BookingStateCheckedIn || record.State == entity.BookingStateConfirmed {
+			message = h.message(c, "ticket_scanned")
+		}
+		c.JSON(http.StatusConflict, gin.H{
+			"error": message,
+		})
+		return
+	}
+
+	if err := h.Scheduler.CancelByBooking(ctx, record.ID); err != nil {
+		h.Logger.Error("failed to cancel booking jobs", l.Error(err))
+	}
+	h.scheduleCompletion(ctx, record)
+
+	c.JSON(http.StatusOK, &models.CheckInRes{
+		BookingId:       record.ID,
+		Category:        record.Category,
+		EstablishmentId: record.EstablishmentID,
+		UserId:          record.UserID,
+		NumberOfPeople:  record.NumberOfPeople,
+		WillArrive:      record.WillArrive,
+		WillLeave:       record.WillLeave,
+		State:           entity.BookingStateCheckedIn,
+		CheckedInAt:     time.Now().Format(time.RFC3339),
+	})
+}
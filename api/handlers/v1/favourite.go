@@ -51,7 +51,7 @@ func (h HandlerV1) AddToFavourites(c *gin.Context) {
 	})
 	if err != nil {
 		c.JSON(500, gin.H{
-			"error": err.Error(),
+			"error": h.errorMessage(c, err),
 		})
 		h.Logger.Error(err.Error())
 		return
@@ -100,7 +100,7 @@ func (h HandlerV1) RemoveFromFavourites(c *gin.Context) {
 	})
 	if err != nil {
 		c.JSON(500, gin.H{
-			"error": err.Error(),
+			"error": h.errorMessage(c, err),
 		})
 		h.Logger.Error(err.Error())
 		return
@@ -122,11 +122,12 @@ func (h HandlerV1) RemoveFromFavourites(c *gin.Context) {
 // LIST FAVOURITES BY USER_ID
 // @Summary LIST FAVOURITES BY USER_ID
 // @Security BearerAuth
-// @Description Api for listing favourites by favourite_id
+// @Description Api for listing favourites by favourite_id, all of them unless limit or page is given
 // @Tags FAVOURITE
 // @Accept json
 // @Produce json
 // @Param user_id query string true "user_id"
+// @Param request query models.Pagination false "request"
 // @Success 200 {object} models.ListFavouritesModel
 // @Failure 404 {object} models.StandartError
 // @Failure 500 {object} models.StandartError
@@ -153,15 +154,21 @@ func (h HandlerV1) ListFavouritesByUserId(c *gin.Context) {
 	})
 	if err != nil {
 		c.JSON(500, gin.H{
-			"error": err.Error(),
+			"error": h.errorMessage(c, err),
 		})
 		h.Logger.Error(err.Error())
 		return
 	}
 
+	// the establishment service returns every favourite, the page is cut here
+	from, to, ok := h.pageRange(c, len(response.Favourites))
+	if !ok {
+		return
+	}
+
 	var favourites []*models.FavouriteModel
 
-	for _, respFavourite := range response.Favourites {
+	for _, respFavourite := range response.Favourites[from:to] {
 		favourite := models.FavouriteModel{
 			FavouriteId:     respFavourite.FavouriteId,
 			EstablishmentId: respFavourite.EstablishmentId,
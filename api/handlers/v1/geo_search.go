@@ -0,0 +1,230 @@
+package v1
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+
+	"Booking/api-service-booking/api/models"
+	"Booking/api-service-booking/internal/entity"
+	errorspkg "Booking/api-service-booking/internal/errors"
+	"Booking/api-service-booking/internal/pkg/i18n"
+	l "Booking/api-service-booking/internal/pkg/logger"
+	"Booking/api-service-booking/internal/pkg/otlp"
+)
+
+// SEARCH NEARBY
+// @Summary SEARCH NEARBY
+// @Security BearerAuth
+// @Description Api for finding hotels, restaurants and attractions together by position, nearest first with the distance in km. near is "lat,lng" and goes with radius_km (at most 100). bbox is "min_lng,min_lat,max_lng,max_lat" and is searched instead of the radius, distances are then measured from near or, without it, from the middle of the box. category takes a comma separated list and defaults to all
+// @Tags SEARCH
+// @Accept json
+// @Produce json
+// @Produce application/geo+json
+// @Param request query models.NearbyReq true "request"
+// @Param zoom query int false "zoom, clusters the GeoJSON points returned, up to limit"
+// @Success 200 {object} models.NearbyRes
+// @Failure 400 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/establishments/nearby [GET]
+func (h *HandlerV1) SearchNearby(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "SearchNearby")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	var body models.NearbyReq
+	if err := c.ShouldBindQuery(&body); err != nil {
+		h.bindError(c, err)
+		return
+	}
+
+	query, err := geoQuery(&body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	}
+
+	hits, err := h.GeoSearch.Search(ctx, query)
+	var errBadRequest *errorspkg.ErrBadRequest
+	if errors.As(err, &errBadRequest) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to search nearby", l.Error(err))
+		return
+	}
+
+	response := models.NearbyRes{
+		Results: []*models.NearbyPlaceRes{},
+		Count:   len(hits),
+	}
+	for _, hit := range hits {
+		response.Results = append(response.Results, nearbyPlaceRes(hit))
+	}
+
+	if wantsGeoJSON(c) {
+		h.writeGeoJSON(c, nearbyFeatures(response.Results))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// REINDEX ESTABLISHMENT POSITIONS
+// @Summary REINDEX ESTABLISHMENT POSITIONS
+// @Security BearerAuth
+// @Description Api for building the position index of nearby search again from the establishment service. Establishments are indexed as they are created, updated and deleted, this is for filling the index the first time or after it was lost. Nearby search keeps the old positions of a category until its new ones are complete
+// @Tags SEARCH
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.ReindexGeoRes
+// @Failure 500 {object} models.StandartError
+// @Router /v1/establishments/geo/reindex [POST]
+func (h *HandlerV1) ReindexGeo(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "ReindexGeo")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	response := models.ReindexGeoRes{
+		Indexed: map[string]int{},
+	}
+	for _, category := range []string{categoryHotel, categoryRestaurant, categoryAttraction} {
+		indexed := 0
+		err := h.GeoSearch.Rebuild(ctx, category, func(index func(place *entity.GeoPlace) error) error {
+			_, err := h.reindex(ctx, category, func(e *indexedEstablishment) error {
+				if e.location == nil {
+					return nil
+				}
+				indexed++
+				return index(geoPlace(e))
+			})
+			return err
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": h.message(c, "try_again_later"),
+			})
+			h.Logger.Error("failed to reindex positions", l.Error(err))
+			return
+		}
+		response.Indexed[category] = indexed
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// geoPlace is the part of an establishment nearby search keeps
+func geoPlace(e *indexedEstablishment) *entity.GeoPlace {
+	return &entity.GeoPlace{
+		Category:        e.category,
+		EstablishmentID: e.id,
+		Name:            e.name,
+		Address:         e.location.Address,
+		City:            e.location.City,
+		Thumbnail:       e.thumbnail(),
+		Rating:          float64(e.rating),
+		Latitude:        float64(e.location.Latitude),
+		Longitude:       float64(e.location.Longitude),
+	}
+}
+
+// geoQuery reads the position and area of a nearby request
+func geoQuery(body *models.NearbyReq) (*entity.GeoQuery, error) {
+	query := entity.GeoQuery{
+		Categories: splitList(body.Category),
+		RadiusKm:   body.RadiusKm,
+		Limit:      body.Limit,
+	}
+
+	if body.Near != "" {
+		point, err := parseFloats(body.Near, 2)
+		if err != nil {
+			return nil, i18n.NewError("field_format", "near", "lat,lng")
+		}
+		query.Latitude, query.Longitude = point[0], point[1]
+	}
+
+	if body.Bbox != "" {
+		bounds, err := parseFloats(body.Bbox, 4)
+		if err != nil {
+			return nil, i18n.NewError("field_format", "bbox", "min_lng,min_lat,max_lng,max_lat")
+		}
+		query.Box = &entity.GeoBox{
+			MinLongitude: bounds[0],
+			MinLatitude:  bounds[1],
+			MaxLongitude: bounds[2],
+			MaxLatitude:  bounds[3],
+		}
+		if body.Near == "" {
+			query.Latitude = (bounds[1] + bounds[3]) / 2
+			query.Longitude = (bounds[0] + bounds[2]) / 2
+		}
+	} else if body.Near == "" {
+		return nil, i18n.NewError("near_or_bbox")
+	}
+
+	return &query, nil
+}
+
+// parseFloats reads exactly size comma separated numbers
+func parseFloats(value string, size int) ([]float64, error) {
+	parts := strings.Split(value, ",")
+	if len(parts) != size {
+		return nil, errors.New("wrong number of values")
+	}
+
+	numbers := make([]float64, 0, size)
+	for _, part := range parts {
+		number, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return nil, err
+		}
+		numbers = append(numbers, number)
+	}
+	return numbers, nil
+}
+
+// splitList flattens repeated and comma separated query values
+func splitList(values []string) []string {
+	var result []string
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				result = append(result, item)
+			}
+		}
+	}
+	return result
+}
+
+func nearbyPlaceRes(hit *entity.GeoHit) *models.NearbyPlaceRes {
+	return &models.NearbyPlaceRes{
+		Category:   hit.Place.Category,
+		HraId:      hit.Place.EstablishmentID,
+		Name:       hit.Place.Name,
+		Address:    hit.Place.Address,
+		City:       hit.Place.City,
+		Thumbnail:  hit.Place.Thumbnail,
+		Rating:     hit.Place.Rating,
+		Latitude:   hit.Place.Latitude,
+		Longitude:  hit.Place.Longitude,
+		DistanceKm: math.Round(hit.DistanceKm*100) / 100,
+	}
+}
@@ -0,0 +1,175 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"Booking/api-service-booking/api/models"
+	"Booking/api-service-booking/internal/entity"
+	errorspkg "Booking/api-service-booking/internal/errors"
+	"Booking/api-service-booking/internal/pkg/geojson"
+	l "Booking/api-service-booking/internal/pkg/logger"
+)
+
+// wantsGeoJSON reports whether the client asked for GeoJSON in Accept
+func wantsGeoJSON(c *gin.Context) bool {
+	return strings.Contains(c.GetHeader("Accept"), geojson.ContentType)
+}
+
+// writeGeoJSON answers with features as a FeatureCollection, clustered
+// for the zoom query parameter when there is one
+func (h *HandlerV1) writeGeoJSON(c *gin.Context, features []*geojson.Feature) {
+	h.writeGeoJSONPage(c, features, "", "")
+}
+
+// writeGeoJSONPage is writeGeoJSON for a page of a list, with the cursors
+// of the pages next to it
+func (h *HandlerV1) writeGeoJSONPage(c *gin.Context, features []*geojson.Feature, next, prev string) {
+	if c.Query("zoom") != "" {
+		zoom, ok := h.zoom(c)
+		if !ok {
+			return
+		}
+		features = geojson.Cluster(features, zoom, "type")
+	}
+
+	collection := geojson.NewFeatureCollection(features)
+	collection.NextCursor, collection.PrevCursor = next, prev
+	c.Header("Content-Type", geojson.ContentType)
+	c.JSON(http.StatusOK, collection)
+}
+
+// zoom reads the zoom query parameter, writing the error response if it is
+// not a zoom level
+func (h *HandlerV1) zoom(c *gin.Context) (int, bool) {
+	zoom, err := strconv.Atoi(c.Query("zoom"))
+	if err != nil || zoom < 0 || zoom > geojson.MaxZoom {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": h.message(c, "field_between", "zoom", 0, geojson.MaxZoom),
+		})
+		return 0, false
+	}
+	return zoom, true
+}
+
+// writeListClusters answers a hotel, restaurant or attraction list asked
+// for as a clustered map. The clusters come from the search index and
+// cover everything filter matches rather than one page, so zooming out
+// shows all of it.
+func (h *HandlerV1) writeListClusters(ctx context.Context, c *gin.Context, filter *entity.EstablishmentList) {
+	zoom, ok := h.zoom(c)
+	if !ok {
+		return
+	}
+
+	clusters, err := h.EstablishmentSearch.Clusters(ctx, filter, zoom)
+	var errBadRequest *errorspkg.ErrBadRequest
+	if errors.As(err, &errBadRequest) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to cluster establishments", l.Error(err))
+		return
+	}
+
+	var ids []string
+	for _, cluster := range clusters {
+		if cluster.Count == 1 {
+			ids = append(ids, cluster.EstablishmentID)
+		}
+	}
+	translations := h.translations(ctx, c, filter.Category, ids)
+
+	features := make([]*geojson.Feature, 0, len(clusters))
+	for _, cluster := range clusters {
+		if cluster.Count > 1 {
+			features = append(features, geojson.ClusterPoint(zoom, cluster.X, cluster.Y, cluster.Latitude, cluster.Longitude, cluster.Count, "type", map[string]int{
+				filter.Category: cluster.Count,
+			}))
+			continue
+		}
+
+		name, description := cluster.Name, ""
+		translate(translations[cluster.EstablishmentID], &name, &description)
+		features = append(features, geojson.Point(cluster.EstablishmentID, cluster.Latitude, cluster.Longitude, map[string]interface{}{
+			"name":      name,
+			"rating":    cluster.Rating,
+			"type":      filter.Category,
+			"thumbnail": cluster.Thumbnail,
+			"address":   cluster.Address,
+			"city":      cluster.City,
+		}))
+	}
+
+	c.Header("Content-Type", geojson.ContentType)
+	c.JSON(http.StatusOK, geojson.NewFeatureCollection(features))
+}
+
+// establishmentFeature is an establishment as a map point, thumbnail is
+// its first image
+func establishmentFeature(category, id, name string, rating float32, images []*models.ImageModel, location models.LocationModel) *geojson.Feature {
+	thumbnail := ""
+	if len(images) > 0 {
+		thumbnail = images[0].ImageUrl
+	}
+
+	return geojson.Point(id, location.Latitude, location.Longitude, map[string]interface{}{
+		"name":      name,
+		"rating":    rating,
+		"type":      category,
+		"thumbnail": thumbnail,
+		"address":   location.Address,
+		"city":      location.City,
+	})
+}
+
+func hotelFeatures(hotels []*models.HotelModel) []*geojson.Feature {
+	features := make([]*geojson.Feature, 0, len(hotels))
+	for _, hotel := range hotels {
+		features = append(features, establishmentFeature(categoryHotel, hotel.HotelId, hotel.HotelName, hotel.Rating, hotel.Images, hotel.Location))
+	}
+	return features
+}
+
+func restaurantFeatures(restaurants []*models.RestaurantModel) []*geojson.Feature {
+	features := make([]*geojson.Feature, 0, len(restaurants))
+	for _, restaurant := range restaurants {
+		features = append(features, establishmentFeature(categoryRestaurant, restaurant.RestaurantId, restaurant.RestaurantName, restaurant.Rating, restaurant.Images, restaurant.Location))
+	}
+	return features
+}
+
+func attractionFeatures(attractions []*models.AttractionModel) []*geojson.Feature {
+	features := make([]*geojson.Feature, 0, len(attractions))
+	for _, attraction := range attractions {
+		features = append(features, establishmentFeature(categoryAttraction, attraction.AttractionId, attraction.AttractionName, attraction.Rating, attraction.Images, attraction.Location))
+	}
+	return features
+}
+
+func nearbyFeatures(places []*models.NearbyPlaceRes) []*geojson.Feature {
+	features := make([]*geojson.Feature, 0, len(places))
+	for _, place := range places {
+		features = append(features, geojson.Point(place.HraId, place.Latitude, place.Longitude, map[string]interface{}{
+			"name":        place.Name,
+			"rating":      place.Rating,
+			"type":        place.Category,
+			"thumbnail":   place.Thumbnail,
+			"address":     place.Address,
+			"city":        place.City,
+			"distance_km": place.DistanceKm,
+		}))
+	}
+	return features
+}
@@ -8,44 +8,128 @@ import (
 
 	grpcClients "Booking/api-service-booking/internal/infrastructure/grpc_service_client"
 	"Booking/api-service-booking/internal/pkg/config"
+	"Booking/api-service-booking/internal/pkg/storage"
 	tokens "Booking/api-service-booking/internal/pkg/token"
 
 	appV "Booking/api-service-booking/internal/usecase/app_version"
+	"Booking/api-service-booking/internal/usecase/attraction_ticket"
+	"Booking/api-service-booking/internal/usecase/booking_record"
+	"Booking/api-service-booking/internal/usecase/establishment_import"
+	"Booking/api-service-booking/internal/usecase/establishment_search"
+	"Booking/api-service-booking/internal/usecase/establishment_snapshot"
 	"Booking/api-service-booking/internal/usecase/event"
+	"Booking/api-service-booking/internal/usecase/geo_search"
+	"Booking/api-service-booking/internal/usecase/hotel_room"
+	"Booking/api-service-booking/internal/usecase/itinerary"
+	"Booking/api-service-booking/internal/usecase/loyalty"
+	"Booking/api-service-booking/internal/usecase/opening_hours"
+	"Booking/api-service-booking/internal/usecase/pricing"
+	"Booking/api-service-booking/internal/usecase/promotion"
+	"Booking/api-service-booking/internal/usecase/restaurant_table"
+	"Booking/api-service-booking/internal/usecase/scheduler"
+	"Booking/api-service-booking/internal/usecase/staff"
+	"Booking/api-service-booking/internal/usecase/suggest"
+	"Booking/api-service-booking/internal/usecase/tag"
+	"Booking/api-service-booking/internal/usecase/translation"
+	"Booking/api-service-booking/internal/usecase/trip"
+	"Booking/api-service-booking/internal/usecase/waitlist"
 	// "Booking/api-service-booking/internal/usecase/refresh_token"
 )
 
 type HandlerV1 struct {
-	Config         *config.Config
-	Logger         *zap.Logger
-	ContextTimeout time.Duration
-	JwtHandler     tokens.JwtHandler
-	Service        grpcClients.ServiceClient
-	AppVersion     appV.AppVersion
-	BrokerProducer event.BrokerProducer
-	Enforcer       *casbin.Enforcer
+	Config                *config.Config
+	Logger                *zap.Logger
+	ContextTimeout        time.Duration
+	JwtHandler            tokens.JwtHandler
+	Service               grpcClients.ServiceClient
+	AppVersion            appV.AppVersion
+	BrokerProducer        event.BrokerProducer
+	Enforcer              *casbin.Enforcer
+	Storage               *storage.Storage
+	BookingRecord         booking_record.BookingRecord
+	Scheduler             scheduler.Scheduler
+	RestaurantTable       restaurant_table.RestaurantTable
+	AttractionTicket      attraction_ticket.AttractionTicket
+	HotelRoom             hotel_room.HotelRoom
+	Staff                 staff.Staff
+	Waitlist              waitlist.Waitlist
+	Pricing               pricing.Pricing
+	Promotion             promotion.Promotion
+	Loyalty               loyalty.Loyalty
+	Trip                  trip.Trip
+	Itinerary             itinerary.Itinerary
+	GeoSearch             geo_search.GeoSearch
+	EstablishmentSearch   establishment_search.EstablishmentSearch
+	Suggest               suggest.Suggest
+	OpeningHours          opening_hours.OpeningHours
+	Tag                   tag.Tag
+	Translation           translation.Translation
+	EstablishmentImport   establishment_import.EstablishmentImport
+	EstablishmentSnapshot establishment_snapshot.EstablishmentSnapshot
 }
 
 type HandlerV1Config struct {
-	Config         *config.Config
-	Logger         *zap.Logger
-	ContextTimeout time.Duration
-	JwtHandler     tokens.JwtHandler
-	Service        grpcClients.ServiceClient
-	AppVersion     appV.AppVersion
-	BrokerProducer event.BrokerProducer
-	Enforcer       *casbin.Enforcer
+	Config                *config.Config
+	Logger                *zap.Logger
+	ContextTimeout        time.Duration
+	JwtHandler            tokens.JwtHandler
+	Service               grpcClients.ServiceClient
+	AppVersion            appV.AppVersion
+	BrokerProducer        event.BrokerProducer
+	Enforcer              *casbin.Enforcer
+	Storage               *storage.Storage
+	BookingRecord         booking_record.BookingRecord
+	Scheduler             scheduler.Scheduler
+	RestaurantTable       restaurant_table.RestaurantTable
+	AttractionTicket      attraction_ticket.AttractionTicket
+	HotelRoom             hotel_room.HotelRoom
+	Staff                 staff.Staff
+	Waitlist              waitlist.Waitlist
+	Pricing               pricing.Pricing
+	Promotion             promotion.Promotion
+	Loyalty               loyalty.Loyalty
+	Trip                  trip.Trip
+	Itinerary             itinerary.Itinerary
+	GeoSearch             geo_search.GeoSearch
+	EstablishmentSearch   establishment_search.EstablishmentSearch
+	Suggest               suggest.Suggest
+	OpeningHours          opening_hours.OpeningHours
+	Tag                   tag.Tag
+	Translation           translation.Translation
+	EstablishmentImport   establishment_import.EstablishmentImport
+	EstablishmentSnapshot establishment_snapshot.EstablishmentSnapshot
 }
 
 func New(c *HandlerV1Config) *HandlerV1 {
 	return &HandlerV1{
-		Config:         c.Config,
-		Logger:         c.Logger,
-		ContextTimeout: c.ContextTimeout,
-		Service:        c.Service,
-		JwtHandler: c.JwtHandler,
-		AppVersion:     c.AppVersion,
-		BrokerProducer: c.BrokerProducer,
-		Enforcer:       c.Enforcer,
+		Config:                c.Config,
+		Logger:                c.Logger,
+		ContextTimeout:        c.ContextTimeout,
+		Service:               c.Service,
+		JwtHandler:            c.JwtHandler,
+		AppVersion:            c.AppVersion,
+		BrokerProducer:        c.BrokerProducer,
+		Enforcer:              c.Enforcer,
+		Storage:               c.Storage,
+		BookingRecord:         c.BookingRecord,
+		Scheduler:             c.Scheduler,
+		RestaurantTable:       c.RestaurantTable,
+		AttractionTicket:      c.AttractionTicket,
+		HotelRoom:             c.HotelRoom,
+		Staff:                 c.Staff,
+		Waitlist:              c.Waitlist,
+		Pricing:               c.Pricing,
+		Promotion:             c.Promotion,
+		Loyalty:               c.Loyalty,
+		Trip:                  c.Trip,
+		Itinerary:             c.Itinerary,
+		GeoSearch:             c.GeoSearch,
+		EstablishmentSearch:   c.EstablishmentSearch,
+		Suggest:               c.Suggest,
+		OpeningHours:          c.OpeningHours,
+		Tag:                   c.Tag,
+		Translation:           c.Translation,
+		EstablishmentImport:   c.EstablishmentImport,
+		EstablishmentSnapshot: c.EstablishmentSnapshot,
 	}
 }
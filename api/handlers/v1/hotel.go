@@ -21,6 +21,7 @@ import (
 // @Produce json
 // @Param Hotel body models.CreateHotel true "Hotel"
 // @Success 200 {object} models.HotelModel
+// @Failure 400 {object} models.StandartError
 // @Failure 404 {object} models.StandartError
 // @Failure 500 {object} models.StandartError
 // @Router /v1/hotel [POST]
@@ -39,16 +40,19 @@ func (h HandlerV1) CreateHotel(c *gin.Context) {
 	jspbMarshal.UseProtoNames = true
 
 	if err := c.ShouldBindJSON(&body); err != nil {
-		c.JSON(404, gin.H{
-			"error": err.Error(),
-		})
+		h.bindError(c, err)
+		return
+	}
+
+	tags, ok := h.checkTags(ctx, c, categoryHotel, body.Tags)
+	if !ok {
 		return
 	}
 
 	owner_id, statusCode := GetIdFromToken(c.Request, h.Config)
 	if statusCode != http.StatusOK {
 		c.JSON(statusCode, gin.H{
-			"error": "Can't get",
+			"error": h.message(c, "cant_get_user"),
 		})
 		return
 	}
@@ -96,11 +100,14 @@ func (h HandlerV1) CreateHotel(c *gin.Context) {
 	})
 	if err != nil {
 		c.JSON(500, gin.H{
-			"error": err.Error(),
+			"error": h.errorMessage(c, err),
 		})
 		return
 	}
 
+	h.indexEstablishment(ctx, indexedHotel(response))
+	h.saveTags(ctx, categoryHotel, response.HotelId, tags)
+
 	var respImages []*models.ImageModel
 
 	for _, respImage := range response.Images {
@@ -138,6 +145,7 @@ func (h HandlerV1) CreateHotel(c *gin.Context) {
 		},
 		CreatedAt: response.CreatedAt,
 		UpdatedAt: response.UpdatedAt,
+		Tags:      tags,
 	}
 
 	c.JSON(http.StatusCreated, respModel)
@@ -151,6 +159,7 @@ func (h HandlerV1) CreateHotel(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param hotel_id query string true "hotel_id"
+// @Param lang query string false "lang, uz, ru or en, takes the place of Accept-Language"
 // @Success 200 {object} models.HotelModel
 // @Failure 404 {object} models.StandartError
 // @Failure 500 {object} models.StandartError
@@ -175,7 +184,7 @@ func (h HandlerV1) GetHotel(c *gin.Context) {
 	})
 	if err != nil {
 		c.JSON(500, gin.H{
-			"error": err.Error(),
+			"error": h.errorMessage(c, err),
 		})
 		h.Logger.Error(err.Error())
 		return
@@ -219,6 +228,8 @@ func (h HandlerV1) GetHotel(c *gin.Context) {
 		CreatedAt: response.Hotel.CreatedAt,
 		UpdatedAt: response.Hotel.UpdatedAt,
 	}
+	respModel.Tags = h.establishmentTags(ctx, categoryHotel, []string{respModel.HotelId})[respModel.HotelId]
+	translate(h.translations(ctx, c, categoryHotel, []string{respModel.HotelId})[respModel.HotelId], &respModel.HotelName, &respModel.Description)
 
 	c.JSON(200, respModel)
 }
@@ -226,12 +237,25 @@ func (h HandlerV1) GetHotel(c *gin.Context) {
 // LIST HOTELS BY PAGE AND LIMIT
 // @Summary LIST HOTELS BY PAGE AND LIMIT
 // @Security BearerAuth
-// @Description Api for listing hotels by page and limit
+// @Description Api for listing hotels by page and limit. city takes a comma separated list, tags a comma separated list of tag slugs all of which must be there, min_rating is 0 to 5 and price_min and price_max bound the base price in the smallest currency unit. sort is rating, distance, popularity, created_at or name with an optional - for descending order, distance is measured from near ("lat,lng"). facets counts the matches per city, per rating and the price range, each ignoring its own filter, and per tag. A sorted or filtered list also returns next_cursor and prev_cursor
 // @Tags HOTEL
 // @Accept json
 // @Produce json
-// @Param request query models.Pagination true "request"
+// @Produce application/geo+json
+// @Param request query models.EstablishmentPagination true "request"
+// @Param city query string false "city"
+// @Param tags query string false "tags"
+// @Param min_rating query number false "min_rating"
+// @Param price_min query int false "price_min"
+// @Param price_max query int false "price_max"
+// @Param open_now query bool false "open_now, only those open now"
+// @Param open_at query string false "open_at, only those open at this Asia/Tashkent time, 2006-01-02T15:04"
+// @Param sort query string false "sort" default(-rating)
+// @Param near query string false "near"
+// @Param zoom query int false "zoom, clusters the GeoJSON points of all the matches rather than of the page"
+// @Param lang query string false "lang, uz, ru or en, takes the place of Accept-Language"
 // @Success 200 {object} models.ListHotelsModel
+// @Failure 400 {object} models.StandartError
 // @Failure 404 {object} models.StandartError
 // @Failure 500 {object} models.StandartError
 // @Router /v1/hotel/list [GET]
@@ -248,32 +272,51 @@ func (h HandlerV1) ListHotels(c *gin.Context) {
 	)
 	defer span.End()
 
-	queryParams := c.Request.URL.Query()
-	params, errStr := utils.ParseQueryParam(queryParams)
-	if errStr != nil {
-		c.JSON(http.StatusBadRequest, models.Error{
-			Message: "Incorrect Date",
-		})
+	list := h.listEstablishments(ctx, c, categoryHotel)
+	if list == nil {
 		return
 	}
 
-	offset := (params.Page - 1) * params.Limit
-
-	response, err := h.Service.EstablishmentService().ListHotels(ctx, &pbe.ListHotelsRequest{
-		Offset: int64(offset),
-		Limit:  int64(params.Limit),
-	})
-	if err != nil {
-		c.JSON(500, gin.H{
-			"error": err.Error(),
+	var (
+		hotels []*pbe.Hotel
+		count  uint64
+	)
+	if list.filtered {
+		listed, err := h.listedHotels(ctx, list.page.IDs)
+		if err != nil {
+			c.JSON(500, gin.H{
+				"error": h.errorMessage(c, err),
+			})
+			h.Logger.Error(err.Error())
+			return
+		}
+		hotels, count = listed, uint64(list.page.Total)
+	} else {
+		response, err := h.Service.EstablishmentService().ListHotels(ctx, &pbe.ListHotelsRequest{
+			Offset: int64(list.offset),
+			Limit:  int64(list.limit),
 		})
-		h.Logger.Error(err.Error())
-		return
+		if err != nil {
+			c.JSON(500, gin.H{
+				"error": h.errorMessage(c, err),
+			})
+			h.Logger.Error(err.Error())
+			return
+		}
+
+		hotels, count = response.Hotels, response.Overall
 	}
 
+	ids := make([]string, 0, len(hotels))
+	for _, hotel := range hotels {
+		ids = append(ids, hotel.HotelId)
+	}
+	tags := h.establishmentTags(ctx, categoryHotel, ids)
+	translations := h.translations(ctx, c, categoryHotel, ids)
+
 	var respHotels []*models.HotelModel
 
-	for _, respHotel := range response.Hotels {
+	for _, respHotel := range hotels {
 
 		var respImages []*models.ImageModel
 
@@ -312,14 +355,25 @@ func (h HandlerV1) ListHotels(c *gin.Context) {
 			},
 			CreatedAt: respHotel.CreatedAt,
 			UpdatedAt: respHotel.UpdatedAt,
+			Tags:      tags[respHotel.HotelId],
 		}
+		translate(translations[respHotel.HotelId], &hotel.HotelName, &hotel.Description)
 
 		respHotels = append(respHotels, &hotel)
 	}
 
+	next, prev := list.cursors()
 	listModel := models.ListHotelsModel{
-		Hotels: respHotels,
-		Count:  response.Overall,
+		Hotels:     respHotels,
+		Count:      count,
+		Facets:     list.facets(),
+		NextCursor: next,
+		PrevCursor: prev,
+	}
+
+	if wantsGeoJSON(c) {
+		h.writeGeoJSONPage(c, hotelFeatures(respHotels), next, prev)
+		return
 	}
 
 	c.JSON(200, listModel)
@@ -335,6 +389,7 @@ func (h HandlerV1) ListHotels(c *gin.Context) {
 // @Param hotel_id query string true "hotel_id"
 // @Param UpdatingHotel body models.UpdateHotel true "UpdatingHotel"
 // @Success 200 {object} models.HotelModel
+// @Failure 400 {object} models.StandartError
 // @Failure 404 {object} models.StandartError
 // @Failure 500 {object} models.StandartError
 // @Router /v1/hotel [PUT]
@@ -354,12 +409,18 @@ func (h HandlerV1) UpdateHotel(c *gin.Context) {
 	defer span.End()
 
 	if err := c.ShouldBindJSON(&body); err != nil {
-		c.JSON(404, gin.H{
-			"error": err.Error(),
-		})
+		h.bindError(c, err)
 		return
 	}
 
+	var tags []string
+	if body.Tags != nil {
+		var ok bool
+		if tags, ok = h.checkTags(ctx, c, categoryHotel, body.Tags); !ok {
+			return
+		}
+	}
+
 	hotel_id := c.Query("hotel_id")
 
 	response, err := h.Service.EstablishmentService().UpdateHotel(ctx, &pbe.UpdateHotelRequest{
@@ -383,12 +444,17 @@ func (h HandlerV1) UpdateHotel(c *gin.Context) {
 	})
 	if err != nil {
 		c.JSON(500, gin.H{
-			"error": err.Error(),
+			"error": h.errorMessage(c, err),
 		})
 		h.Logger.Error(err.Error())
 		return
 	}
 
+	h.indexEstablishment(ctx, indexedHotel(response.Hotel))
+	if body.Tags != nil {
+		h.saveTags(ctx, categoryHotel, response.Hotel.HotelId, tags)
+	}
+
 	var respImages []*models.ImageModel
 
 	for _, respImage := range response.Hotel.Images {
@@ -427,6 +493,7 @@ func (h HandlerV1) UpdateHotel(c *gin.Context) {
 		CreatedAt: response.Hotel.CreatedAt,
 		UpdatedAt: response.Hotel.UpdatedAt,
 	}
+	respModel.Tags = h.establishmentTags(ctx, categoryHotel, []string{respModel.HotelId})[respModel.HotelId]
 
 	c.JSON(200, respModel)
 }
@@ -463,7 +530,7 @@ func (h HandlerV1) DeleteHotel(c *gin.Context) {
 	})
 	if err != nil {
 		c.JSON(500, gin.H{
-			"error": err.Error(),
+			"error": h.errorMessage(c, err),
 		})
 		h.Logger.Error(err.Error())
 		return
@@ -471,12 +538,14 @@ func (h HandlerV1) DeleteHotel(c *gin.Context) {
 
 	if !response.Success {
 		c.JSON(404, gin.H{
-			"error": "not deleted",
+			"error": h.message(c, "not_deleted"),
 		})
 		h.Logger.Error("not deleted")
 		return
 	}
 
+	h.unindexEstablishment(ctx, categoryHotel, hotel_id)
+
 	c.JSON(200, gin.H{
 		"message": "successfuly deleted",
 	})
@@ -489,8 +558,11 @@ func (h HandlerV1) DeleteHotel(c *gin.Context) {
 // @Tags HOTEL
 // @Accept json
 // @Produce json
+// @Produce application/geo+json
 // @Param request query models.Pagination true "request"
 // @Param request query models.FieldValuesByLocation true "request"
+// @Param zoom query int false "zoom, clusters the GeoJSON points of this page only, /list clusters all its matches"
+// @Param lang query string false "lang, uz, ru or en, takes the place of Accept-Language"
 // @Success 200 {object} models.ListHotelsModel
 // @Failure 404 {object} models.StandartError
 // @Failure 500 {object} models.StandartError
@@ -512,7 +584,7 @@ func (h HandlerV1) ListHotelsByLocation(c *gin.Context) {
 	params, errStr := utils.ParseQueryParam(queryParams)
 	if errStr != nil {
 		c.JSON(http.StatusBadRequest, models.Error{
-			Message: "Incorrect Date",
+			Message: h.message(c, "incorrect_date"),
 		})
 		return
 	}
@@ -532,12 +604,19 @@ func (h HandlerV1) ListHotelsByLocation(c *gin.Context) {
 	})
 	if err != nil {
 		c.JSON(500, gin.H{
-			"error": err.Error(),
+			"error": h.errorMessage(c, err),
 		})
 		h.Logger.Error(err.Error())
 		return
 	}
 
+	ids := make([]string, 0, len(response.Hotels))
+	for _, hotel := range response.Hotels {
+		ids = append(ids, hotel.HotelId)
+	}
+	tags := h.establishmentTags(ctx, categoryHotel, ids)
+	translations := h.translations(ctx, c, categoryHotel, ids)
+
 	var respHotels []*models.HotelModel
 
 	for _, respHotel := range response.Hotels {
@@ -579,7 +658,9 @@ func (h HandlerV1) ListHotelsByLocation(c *gin.Context) {
 			},
 			CreatedAt: respHotel.CreatedAt,
 			UpdatedAt: respHotel.UpdatedAt,
+			Tags:      tags[respHotel.HotelId],
 		}
+		translate(translations[respHotel.HotelId], &hotel.HotelName, &hotel.Description)
 
 		respHotels = append(respHotels, &hotel)
 	}
@@ -589,6 +670,11 @@ func (h HandlerV1) ListHotelsByLocation(c *gin.Context) {
 		Count:  uint64(response.Count),
 	}
 
+	if wantsGeoJSON(c) {
+		h.writeGeoJSON(c, hotelFeatures(respHotels))
+		return
+	}
+
 	c.JSON(200, respModel)
 }
 
@@ -599,7 +685,10 @@ func (h HandlerV1) ListHotelsByLocation(c *gin.Context) {
 // @Tags HOTEL
 // @Accept json
 // @Produce json
+// @Produce application/geo+json
 // @Param request query models.FindByName true "request"
+// @Param zoom query int false "zoom, clusters the GeoJSON points of this page only, /list clusters all its matches"
+// @Param lang query string false "lang, uz, ru or en, takes the place of Accept-Language"
 // @Success 200 {object} models.ListHotelsModel
 // @Failure 404 {object} models.StandartError
 // @Failure 500 {object} models.StandartError
@@ -624,12 +713,19 @@ func (h HandlerV1) FindHotelsByName(c *gin.Context) {
 	})
 	if err != nil {
 		c.JSON(500, gin.H{
-			"error": err.Error(),
+			"error": h.errorMessage(c, err),
 		})
 		h.Logger.Error(err.Error())
 		return
 	}
 
+	ids := make([]string, 0, len(response.Hotels))
+	for _, hotel := range response.Hotels {
+		ids = append(ids, hotel.HotelId)
+	}
+	tags := h.establishmentTags(ctx, categoryHotel, ids)
+	translations := h.translations(ctx, c, categoryHotel, ids)
+
 	var respHotels []*models.HotelModel
 
 	for _, respHotel := range response.Hotels {
@@ -671,7 +767,9 @@ func (h HandlerV1) FindHotelsByName(c *gin.Context) {
 			},
 			CreatedAt: respHotel.CreatedAt,
 			UpdatedAt: respHotel.UpdatedAt,
+			Tags:      tags[respHotel.HotelId],
 		}
+		translate(translations[respHotel.HotelId], &hotel.HotelName, &hotel.Description)
 
 		respHotels = append(respHotels, &hotel)
 	}
@@ -681,5 +779,10 @@ func (h HandlerV1) FindHotelsByName(c *gin.Context) {
 		Count:  response.Count,
 	}
 
+	if wantsGeoJSON(c) {
+		h.writeGeoJSON(c, hotelFeatures(respHotels))
+		return
+	}
+
 	c.JSON(200, listModel)
 }
@@ -0,0 +1,101 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+
+	"Booking/api-service-booking/api/models"
+	"Booking/api-service-booking/internal/entity"
+	errorspkg "Booking/api-service-booking/internal/errors"
+	"Booking/api-service-booking/internal/pkg/i18n"
+	l "Booking/api-service-booking/internal/pkg/logger"
+	"Booking/api-service-booking/internal/pkg/otlp"
+)
+
+// SET HOTEL ROOMS
+// @Summary SET HOTEL ROOMS
+// @Security BearerAuth
+// @Description Api for setting how many rooms a hotel lets each night. Bookings and changes of a hotel with a room count are refused when a night is full, one booking takes one room for each of its nights.
+// @Tags HOTEL
+// @Accept json
+// @Produce json
+// @Param Rooms body models.HotelRoomsReq true "Rooms"
+// @Success 200 {object} models.HotelRoomsRes
+// @Failure 400 {object} models.StandartError
+// @Failure 403 {object} models.StandartError
+// @Failure 404 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/hotel/rooms [PUT]
+func (h *HandlerV1) SetHotelRooms(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "SetHotelRooms")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	var body models.HotelRoomsReq
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.bindError(c, err)
+		return
+	}
+
+	if statusCode, err := h.checkManager(ctx, c.Request, categoryHotel, body.HotelId); err != nil {
+		c.JSON(statusCode, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	}
+
+	rooms := entity.HotelRooms{
+		HotelID: body.HotelId,
+		Rooms:   body.Rooms,
+	}
+	err := h.HotelRoom.Save(ctx, &rooms)
+	var errBadRequest *errorspkg.ErrBadRequest
+	if errors.As(err, &errBadRequest) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to save hotel rooms", l.Error(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, &models.HotelRoomsRes{
+		HotelId:   rooms.HotelID,
+		Rooms:     rooms.Rooms,
+		UpdatedAt: rooms.UpdatedAt.Format(time.RFC3339),
+	})
+}
+
+// holdRooms takes a room on every night from arriveAt to leaveAt for
+// bookingID, the returned status goes to the client on error. Hotels
+// without a room count take any booking, as they did before, and keep the
+// hold so it counts once a count is set.
+func (h *HandlerV1) holdRooms(ctx context.Context, hotelID, bookingID string, arriveAt, leaveAt time.Time) (int, error) {
+	err := h.HotelRoom.Hold(ctx, hotelID, bookingID, arriveAt, leaveAt)
+	if errors.Is(err, errorspkg.ErrorNotAvailable) {
+		return http.StatusConflict, i18n.NewError("no_free_room")
+	}
+	if err != nil {
+		h.Logger.Error("failed to hold rooms", l.Error(err))
+		return http.StatusInternalServerError, i18n.NewError("try_again_later")
+	}
+	return http.StatusOK, nil
+}
+
+func (h *HandlerV1) releaseRooms(ctx context.Context, bookingID string) {
+	if err := h.HotelRoom.Release(ctx, bookingID); err != nil {
+		h.Logger.Error("failed to release rooms", l.Error(err))
+	}
+}
@@ -0,0 +1,638 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+
+	"Booking/api-service-booking/api/models"
+	pbe "Booking/api-service-booking/genproto/establishment-proto"
+	"Booking/api-service-booking/internal/entity"
+	errorspkg "Booking/api-service-booking/internal/errors"
+	"Booking/api-service-booking/internal/pkg/booktime"
+	"Booking/api-service-booking/internal/pkg/i18n"
+	l "Booking/api-service-booking/internal/pkg/logger"
+	"Booking/api-service-booking/internal/pkg/otlp"
+	"Booking/api-service-booking/internal/usecase/itinerary"
+)
+
+const (
+	// itineraryPlaceLimit caps how many attractions and restaurants of a
+	// city the planner looks at
+	itineraryPlaceLimit = 200
+	maxItineraryDays    = 7
+	maxStopsPerDay      = 8
+	defaultStopsPerDay  = 4
+	restaurantVisit     = 90 * time.Minute
+)
+
+// PLAN ITINERARY
+// @Summary PLAN ITINERARY
+// @Security BearerAuth
+// @Description Api for planning a day by day itinerary of attractions in a city with lunch and dinner in nearby restaurants. Stops are picked by walking distance from the one before and only while the place is open. Nothing is saved
+// @Tags ITINERARY
+// @Accept json
+// @Produce json
+// @Param Plan body models.PlanItineraryReq true "Plan"
+// @Success 200 {object} models.PlanItineraryRes
+// @Failure 400 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/itineraries/plan [POST]
+func (h *HandlerV1) PlanItinerary(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "PlanItinerary")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	var body models.PlanItineraryReq
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.bindError(c, err)
+		return
+	}
+
+	options, statusCode, err := planOptions(&body)
+	if err != nil {
+		c.JSON(statusCode, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	}
+
+	places, statusCode, err := h.itineraryPlaces(ctx, &body)
+	if err != nil {
+		c.JSON(statusCode, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.PlanItineraryRes{
+		City: body.City,
+		Days: itineraryDaysRes(h.Itinerary.Plan(places, options)),
+	})
+}
+
+// SAVE ITINERARY
+// @Summary SAVE ITINERARY
+// @Security BearerAuth
+// @Description Api for saving an itinerary, usually a planned one the user edited. Walks between stops are worked out again
+// @Tags ITINERARY
+// @Accept json
+// @Produce json
+// @Param Itinerary body models.SaveItineraryReq true "Itinerary"
+// @Success 201 {object} models.ItineraryRes
+// @Failure 400 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/itineraries [POST]
+func (h *HandlerV1) SaveItinerary(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "SaveItinerary")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	var body models.SaveItineraryReq
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.bindError(c, err)
+		return
+	}
+
+	userID, statusCode := GetIdFromToken(c.Request, h.Config)
+	if statusCode != http.StatusOK {
+		c.JSON(statusCode, gin.H{
+			"error": h.message(c, "cant_get_user"),
+		})
+		return
+	}
+
+	days, err := itineraryDays(body.Days)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	}
+
+	plan := entity.Itinerary{
+		UserID: userID,
+		Name:   body.Name,
+		City:   body.City,
+		People: body.People,
+		Days:   days,
+	}
+	err = h.Itinerary.Save(ctx, &plan)
+	var errBadRequest *errorspkg.ErrBadRequest
+	if errors.As(err, &errBadRequest) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to save itinerary", l.Error(err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, itineraryRes(&plan))
+}
+
+// LIST ITINERARIES
+// @Summary LIST ITINERARIES
+// @Security BearerAuth
+// @Description Api for listing the saved itineraries of the user, newest first
+// @Tags ITINERARY
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.ListItinerariesRes
+// @Failure 500 {object} models.StandartError
+// @Router /v1/itineraries [GET]
+func (h *HandlerV1) ListItineraries(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "ListItineraries")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	userID, statusCode := GetIdFromToken(c.Request, h.Config)
+	if statusCode != http.StatusOK {
+		c.JSON(statusCode, gin.H{
+			"error": h.message(c, "cant_get_user"),
+		})
+		return
+	}
+
+	plans, err := h.Itinerary.ListByUser(ctx, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to list itineraries", l.Error(err))
+		return
+	}
+
+	response := models.ListItinerariesRes{
+		Itineraries: []*models.ItineraryRes{},
+	}
+	for _, plan := range plans {
+		response.Itineraries = append(response.Itineraries, itineraryRes(plan))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GET ITINERARY
+// @Summary GET ITINERARY
+// @Security BearerAuth
+// @Description Api for getting a saved itinerary of the user
+// @Tags ITINERARY
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} models.ItineraryRes
+// @Failure 404 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/itineraries/{id} [GET]
+func (h *HandlerV1) GetItinerary(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "GetItinerary")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	plan, statusCode, err := h.userItinerary(ctx, c.Request, c.Param("id"))
+	if err != nil {
+		c.JSON(statusCode, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, itineraryRes(plan))
+}
+
+// DELETE ITINERARY
+// @Summary DELETE ITINERARY
+// @Security BearerAuth
+// @Description Api for deleting a saved itinerary. Trips booked from it are kept
+// @Tags ITINERARY
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} models.StandartError
+// @Failure 404 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/itineraries/{id} [DELETE]
+func (h *HandlerV1) DeleteItinerary(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "DeleteItinerary")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	plan, statusCode, err := h.userItinerary(ctx, c.Request, c.Param("id"))
+	if err != nil {
+		c.JSON(statusCode, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	}
+
+	if err = h.Itinerary.Delete(ctx, plan.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to delete itinerary", l.Error(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, "successfully deleted...")
+}
+
+// BOOK ITINERARY
+// @Summary BOOK ITINERARY
+// @Security BearerAuth
+// @Description Api for turning the stops of a saved itinerary into an open trip, optionally with a hotel for the whole stay. Only the given dates are taken when dates are set. The trip is then checked out through the trip apis
+// @Tags ITINERARY
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param Trip body models.ItineraryTripReq true "Trip"
+// @Success 201 {object} models.TripRes
+// @Failure 400 {object} models.StandartError
+// @Failure 404 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/itineraries/{id}/trip [POST]
+func (h *HandlerV1) ItineraryTrip(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "ItineraryTrip")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	var body models.ItineraryTripReq
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.bindError(c, err)
+		return
+	}
+
+	plan, statusCode, err := h.userItinerary(ctx, c.Request, c.Param("id"))
+	if err != nil {
+		c.JSON(statusCode, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	}
+
+	items := itineraryTripItems(plan, body.Dates, body.HotelId)
+	if len(items) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": h.message(c, "nothing_to_book"),
+		})
+		return
+	}
+
+	trip := entity.Trip{
+		UserID: plan.UserID,
+		Name:   plan.Name,
+	}
+	if err = h.Trip.Create(ctx, &trip); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to create trip", l.Error(err))
+		return
+	}
+
+	for _, item := range items {
+		item.TripID = trip.ID
+		err = h.Trip.AddItem(ctx, item)
+		if err == nil {
+			trip.Items = append(trip.Items, item)
+			continue
+		}
+
+		// a half filled trip is of no use, drop it
+		if deleteErr := h.Trip.Delete(ctx, trip.ID); deleteErr != nil {
+			h.Logger.Error("failed to delete trip", l.Error(deleteErr))
+		}
+		var errBadRequest *errorspkg.ErrBadRequest
+		if errors.As(err, &errBadRequest) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": h.errorMessage(c, err),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to add trip item", l.Error(err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, tripRes(&trip))
+}
+
+// planOptions checks a plan request and turns it into planner options
+func planOptions(body *models.PlanItineraryReq) (itinerary.PlanOptions, int, error) {
+	var options itinerary.PlanOptions
+
+	body.City = strings.TrimSpace(body.City)
+	if body.City == "" {
+		return options, http.StatusBadRequest, i18n.NewError("field_required", "city")
+	}
+	if body.Days < 1 || body.Days > maxItineraryDays {
+		return options, http.StatusBadRequest, i18n.NewError("field_between", "days", 1, 7)
+	}
+	if body.StopsPerDay == 0 {
+		body.StopsPerDay = defaultStopsPerDay
+	}
+	if body.StopsPerDay < 1 || body.StopsPerDay > maxStopsPerDay {
+		return options, http.StatusBadRequest, i18n.NewError("field_between", "stops_per_day", 1, 8)
+	}
+	if (body.Latitude == nil) != (body.Longitude == nil) {
+		return options, http.StatusBadRequest, i18n.NewError("lat_lng_together")
+	}
+
+	date, dateOnly, err := booktime.Parse(body.StartDate)
+	if err != nil || !dateOnly {
+		return options, http.StatusBadRequest, i18n.NewError("field_format", "start_date", "2006-01-02")
+	}
+	if date.Before(midnight(time.Now())) {
+		return options, http.StatusBadRequest, i18n.NewError("field_in_past", "start_date")
+	}
+
+	options = itinerary.PlanOptions{
+		Date:        date,
+		Days:        body.Days,
+		StopsPerDay: body.StopsPerDay,
+	}
+	if body.Latitude != nil {
+		options.HasStart = true
+		options.Latitude, options.Longitude = *body.Latitude, *body.Longitude
+	}
+	return options, http.StatusOK, nil
+}
+
+// itineraryPlaces gathers the attractions matching the interests and the
+// restaurants of the city, visited within their opening schedule or else
+// their hours: the entry hours of an attraction and the opening_hours of a
+// restaurant. Attractions selling timed tickets are visited for one entry
+// slot of the schedule. Places with neither are taken as always open.
+func (h *HandlerV1) itineraryPlaces(ctx context.Context, body *models.PlanItineraryReq) ([]*itinerary.Place, int, error) {
+	var places []*itinerary.Place
+
+	attractions, err := h.Service.EstablishmentService().ListAttractionsByLocation(ctx, &pbe.ListAttractionsByLocationRequest{
+		Limit:   itineraryPlaceLimit,
+		Country: body.Country,
+		City:    body.City,
+	})
+	if err != nil {
+		h.Logger.Error("failed to list attractions", l.Error(err))
+		return nil, http.StatusInternalServerError, i18n.NewError("try_again_later")
+	}
+	var (
+		matched []*pbe.Attraction
+		slots   = make(map[string]time.Duration)
+		hours   = make(map[string]string)
+	)
+	for _, attraction := range attractions.Attractions {
+		if attraction.Location == nil || !matchesInterests(body.Interests, attraction) {
+			continue
+		}
+		matched = append(matched, attraction)
+
+		hours[attraction.AttractionId] = ""
+		settings, err := h.AttractionTicket.GetSettings(ctx, attraction.AttractionId)
+		switch {
+		case errors.Is(err, errorspkg.ErrorNotFound):
+		case err != nil:
+			h.Logger.Error("failed to get entry settings", l.Error(err))
+			return nil, http.StatusInternalServerError, i18n.NewError("try_again_later")
+		default:
+			hours[attraction.AttractionId] = settings.OpeningHours
+			slots[attraction.AttractionId] = settings.SlotLength
+		}
+	}
+	attractionSchedules, err := h.openingSchedules(ctx, categoryAttraction, hours)
+	if err != nil {
+		h.Logger.Error("failed to get opening hours", l.Error(err))
+		return nil, http.StatusInternalServerError, i18n.NewError("try_again_later")
+	}
+
+	for _, attraction := range matched {
+		place := itinerary.Place{
+			Category:        categoryAttraction,
+			EstablishmentID: attraction.AttractionId,
+			Name:            attraction.AttractionName,
+			Address:         attraction.Location.Address,
+			Latitude:        float64(attraction.Location.Latitude),
+			Longitude:       float64(attraction.Location.Longitude),
+			Rating:          float64(attraction.Rating),
+			Hours:           attractionSchedules[attraction.AttractionId],
+		}
+		if place.Hours != nil {
+			place.Slot = slots[attraction.AttractionId]
+		}
+		places = append(places, &place)
+	}
+
+	restaurants, err := h.Service.EstablishmentService().ListRestaurantsByLocation(ctx, &pbe.ListRestaurantsByLocationRequest{
+		Limit:   itineraryPlaceLimit,
+		Country: body.Country,
+		City:    body.City,
+	})
+	if err != nil {
+		h.Logger.Error("failed to list restaurants", l.Error(err))
+		return nil, http.StatusInternalServerError, i18n.NewError("try_again_later")
+	}
+	plain := make(map[string]string, len(restaurants.Restaurants))
+	for _, restaurant := range restaurants.Restaurants {
+		plain[restaurant.RestaurantId] = restaurant.OpeningHours
+	}
+	schedules, err := h.openingSchedules(ctx, categoryRestaurant, plain)
+	if err != nil {
+		h.Logger.Error("failed to get opening hours", l.Error(err))
+		return nil, http.StatusInternalServerError, i18n.NewError("try_again_later")
+	}
+
+	for _, restaurant := range restaurants.Restaurants {
+		if restaurant.Location == nil {
+			continue
+		}
+
+		place := itinerary.Place{
+			Category:        categoryRestaurant,
+			EstablishmentID: restaurant.RestaurantId,
+			Name:            restaurant.RestaurantName,
+			Address:         restaurant.Location.Address,
+			Latitude:        float64(restaurant.Location.Latitude),
+			Longitude:       float64(restaurant.Location.Longitude),
+			Rating:          float64(restaurant.Rating),
+			Meal:            true,
+			Visit:           restaurantVisit,
+			Hours:           schedules[restaurant.RestaurantId],
+		}
+		places = append(places, &place)
+	}
+
+	return places, http.StatusOK, nil
+}
+
+// matchesInterests reports whether an attraction's category, name or
+// description mentions one of the interests, any attraction does when
+// there are none
+func matchesInterests(interests []string, attraction *pbe.Attraction) bool {
+	if len(interests) == 0 {
+		return true
+	}
+
+	text := strings.ToLower(strings.Join([]string{
+		attraction.Location.Category,
+		attraction.AttractionName,
+		attraction.Description,
+	}, " "))
+	for _, interest := range interests {
+		if interest = strings.ToLower(strings.TrimSpace(interest)); interest != "" && strings.Contains(text, interest) {
+			return true
+		}
+	}
+	return false
+}
+
+// userItinerary returns the itinerary of the user behind r, other users'
+// itineraries are not found
+func (h *HandlerV1) userItinerary(ctx context.Context, r *http.Request, id string) (*entity.Itinerary, int, error) {
+	userID, statusCode := GetIdFromToken(r, h.Config)
+	if statusCode != http.StatusOK {
+		return nil, statusCode, i18n.NewError("cant_get_user")
+	}
+
+	plan, err := h.Itinerary.Get(ctx, id)
+	if errors.Is(err, errorspkg.ErrorNotFound) || (err == nil && plan.UserID != userID) {
+		return nil, http.StatusNotFound, i18n.NewError("itinerary_not_found")
+	}
+	if err != nil {
+		h.Logger.Error("failed to get itinerary", l.Error(err))
+		return nil, http.StatusInternalServerError, i18n.NewError("try_again_later")
+	}
+
+	return plan, http.StatusOK, nil
+}
+
+// itineraryTripItems turns the stops on dates, all of them without dates,
+// into trip items. A hotel stays from the first of those days to the
+// morning after the last.
+func itineraryTripItems(plan *entity.Itinerary, dates []string, hotelID string) []*entity.TripItem {
+	var (
+		items       []*entity.TripItem
+		first, last string
+	)
+	for _, day := range plan.Days {
+		if len(dates) > 0 && !contains(dates, day.Date) {
+			continue
+		}
+		if first == "" {
+			first = day.Date
+		}
+		last = day.Date
+
+		for _, stop := range day.Stops {
+			items = append(items, &entity.TripItem{
+				Category:        stop.Category,
+				EstablishmentID: stop.EstablishmentID,
+				WillArrive:      stop.StartsAt.In(booktime.Location()).Format("2006-01-02T15:04:05"),
+				WillLeave:       stop.EndsAt.In(booktime.Location()).Format("2006-01-02T15:04:05"),
+				NumberOfPeople:  plan.People,
+			})
+		}
+	}
+
+	if hotelID != "" && first != "" {
+		leave, _ := time.Parse("2006-01-02", last)
+		items = append([]*entity.TripItem{{
+			Category:        categoryHotel,
+			EstablishmentID: hotelID,
+			WillArrive:      first,
+			WillLeave:       leave.AddDate(0, 0, 1).Format("2006-01-02"),
+			NumberOfPeople:  plan.People,
+		}}, items...)
+	}
+	return items
+}
+
+// itineraryDays reads the days of a request, times are booking dates in
+// local time
+func itineraryDays(days []*models.ItineraryDayModel) ([]*entity.ItineraryDay, error) {
+	result := make([]*entity.ItineraryDay, 0, len(days))
+	for _, day := range days {
+		stops := make([]*entity.ItineraryStop, 0, len(day.Stops))
+		for _, stop := range day.Stops {
+			startsAt, dateOnly, err := booktime.Parse(stop.StartsAt)
+			if err != nil || dateOnly {
+				return nil, i18n.NewError("field_needs_time", "starts_at")
+			}
+			endsAt, dateOnly, err := booktime.Parse(stop.EndsAt)
+			if err != nil || dateOnly {
+				return nil, i18n.NewError("field_needs_time", "ends_at")
+			}
+
+			stops = append(stops, &entity.ItineraryStop{
+				Category:        stop.Category,
+				EstablishmentID: stop.HraId,
+				Name:            stop.Name,
+				Address:         stop.Address,
+				Latitude:        stop.Latitude,
+				Longitude:       stop.Longitude,
+				StartsAt:        startsAt,
+				EndsAt:          endsAt,
+			})
+		}
+		result = append(result, &entity.ItineraryDay{Date: day.Date, Stops: stops})
+	}
+	return result, nil
+}
+
+func itineraryDaysRes(days []*entity.ItineraryDay) []*models.ItineraryDayModel {
+	result := make([]*models.ItineraryDayModel, 0, len(days))
+	for _, day := range days {
+		stops := make([]*models.ItineraryStopModel, 0, len(day.Stops))
+		for _, stop := range day.Stops {
+			stops = append(stops, &models.ItineraryStopModel{
+				Category:    stop.Category,
+				HraId:       stop.EstablishmentID,
+				Name:        stop.Name,
+				Address:     stop.Address,
+				Latitude:    stop.Latitude,
+				Longitude:   stop.Longitude,
+				StartsAt:    stop.StartsAt.In(booktime.Location()).Format("2006-01-02T15:04:05"),
+				EndsAt:      stop.EndsAt.In(booktime.Location()).Format("2006-01-02T15:04:05"),
+				WalkKm:      stop.WalkKm,
+				WalkMinutes: stop.WalkMinutes,
+			})
+		}
+		result = append(result, &models.ItineraryDayModel{Date: day.Date, Stops: stops})
+	}
+	return result
+}
+
+func itineraryRes(plan *entity.Itinerary) *models.ItineraryRes {
+	return &models.ItineraryRes{
+		Id:        plan.ID,
+		UserId:    plan.UserID,
+		Name:      plan.Name,
+		City:      plan.City,
+		People:    plan.People,
+		Days:      itineraryDaysRes(plan.Days),
+		CreatedAt: plan.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: plan.UpdatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,87 @@
+package v1
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+
+	"Booking/api-service-booking/api/models"
+	"Booking/api-service-booking/internal/entity"
+	l "Booking/api-service-booking/internal/pkg/logger"
+	"Booking/api-service-booking/internal/pkg/otlp"
+)
+
+// List Scheduled Jobs
+// @Summary List Scheduled Jobs
+// @Security BearerAuth
+// @Description Api for listing upcoming or failed scheduled jobs
+// @Tags SCHEDULER
+// @Accept json
+// @Produce json
+// @Param request query models.ListJobsReq true "request"
+// @Success 200 {object} models.ListJobsRes
+// @Failure 400 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/jobs [get]
+func (h *HandlerV1) ListJobs(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "ListJobs")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	body := models.ListJobsReq{
+		Status: "upcoming",
+		Page:   1,
+		Limit:  10,
+	}
+	if err := c.ShouldBindQuery(&body); err != nil || body.Page < 1 || body.Limit < 1 {
+		h.bindError(c, err)
+		return
+	}
+
+	var status string
+	switch body.Status {
+	case "upcoming":
+		status = entity.JobStatusPending
+	case "failed":
+		status = entity.JobStatusFailed
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": h.message(c, "field_one_of", "status", "upcoming, failed"),
+		})
+		return
+	}
+
+	jobs, count, err := h.Scheduler.List(ctx, status, uint64(body.Limit), uint64((body.Page-1)*body.Limit))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to list scheduled jobs", l.Error(err))
+		return
+	}
+
+	response := models.ListJobsRes{
+		Jobs:  []*models.JobRes{},
+		Count: count,
+	}
+	for _, job := range jobs {
+		response.Jobs = append(response.Jobs, &models.JobRes{
+			Id:        job.ID,
+			Kind:      job.Kind,
+			BookingId: job.BookingID,
+			Payload:   job.Payload,
+			RunAt:     job.RunAt.Format(time.RFC3339),
+			Status:    job.Status,
+			Attempts:  job.Attempts,
+			LastError: job.LastError,
+			CreatedAt: job.CreatedAt.Format(time.RFC3339),
+			UpdatedAt: job.UpdatedAt.Format(time.RFC3339),
+		})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,278 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+
+	"Booking/api-service-booking/api/models"
+	"Booking/api-service-booking/internal/entity"
+	errorspkg "Booking/api-service-booking/internal/errors"
+	"Booking/api-service-booking/internal/pkg/i18n"
+	l "Booking/api-service-booking/internal/pkg/logger"
+	"Booking/api-service-booking/internal/pkg/otlp"
+	"Booking/api-service-booking/internal/pkg/utils"
+)
+
+// GET LOYALTY BALANCE
+// @Summary GET LOYALTY BALANCE
+// @Security BearerAuth
+// @Description Api for getting the loyalty points of the user, admins can pass user_id to see someone else's
+// @Tags USER
+// @Accept json
+// @Produce json
+// @Param user_id query string false "user_id"
+// @Success 200 {object} models.LoyaltyBalanceRes
+// @Failure 401 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/users/loyalty [GET]
+func (h *HandlerV1) GetLoyaltyBalance(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "GetLoyaltyBalance")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	userID, statusCode := h.loyaltyUser(c.Request, c.Query("user_id"))
+	if statusCode != http.StatusOK {
+		c.JSON(statusCode, gin.H{
+			"error": h.message(c, "cant_get_user"),
+		})
+		return
+	}
+
+	balance, err := h.Loyalty.Balance(ctx, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to get loyalty balance", l.Error(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.LoyaltyBalanceRes{
+		UserId:     userID,
+		Balance:    balance,
+		PointValue: h.Loyalty.Worth(1),
+	})
+}
+
+// LIST LOYALTY HISTORY
+// @Summary LIST LOYALTY HISTORY
+// @Security BearerAuth
+// @Description Api for listing the loyalty ledger of the user, newest first. Admins can pass user_id to see someone else's
+// @Tags USER
+// @Accept json
+// @Produce json
+// @Param request query models.LoyaltyHistoryReq false "request"
+// @Success 200 {object} models.ListLoyaltyEntriesRes
+// @Failure 400 {object} models.StandartError
+// @Failure 401 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/users/loyalty/history [GET]
+func (h *HandlerV1) ListLoyaltyHistory(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "ListLoyaltyHistory")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	queryParams := c.Request.URL.Query()
+	requested := queryParams.Get("user_id")
+	queryParams.Del("user_id")
+
+	params, errStr := utils.ParseQueryParam(queryParams)
+	if errStr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": h.errorMessage(c, errStr[0]),
+		})
+		return
+	}
+
+	userID, statusCode := h.loyaltyUser(c.Request, requested)
+	if statusCode != http.StatusOK {
+		c.JSON(statusCode, gin.H{
+			"error": h.message(c, "cant_get_user"),
+		})
+		return
+	}
+
+	entries, count, err := h.Loyalty.History(ctx, userID, params.Limit, (params.Page-1)*params.Limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to list loyalty history", l.Error(err))
+		return
+	}
+
+	response := models.ListLoyaltyEntriesRes{
+		Entries: []*models.LoyaltyEntryRes{},
+		Count:   count,
+	}
+	for _, entry := range entries {
+		response.Entries = append(response.Entries, loyaltyEntryRes(entry))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ADJUST LOYALTY POINTS
+// @Summary ADJUST LOYALTY POINTS
+// @Security BearerAuth
+// @Description Api for crediting or, with negative points, debiting loyalty points by hand. The reason is required and kept in the ledger
+// @Tags USER
+// @Accept json
+// @Produce json
+// @Param Adjustment body models.LoyaltyAdjustReq true "Adjustment"
+// @Success 201 {object} models.LoyaltyEntryRes
+// @Failure 400 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/users/loyalty/adjust [POST]
+func (h *HandlerV1) AdjustLoyalty(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "AdjustLoyalty")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	var body models.LoyaltyAdjustReq
+	if err := c.ShouldBindJSON(&body); err != nil || body.UserId == "" {
+		h.bindError(c, err)
+		return
+	}
+
+	adminID, statusCode := GetIdFromToken(c.Request, h.Config)
+	if statusCode != http.StatusOK {
+		c.JSON(statusCode, gin.H{
+			"error": h.message(c, "cant_get_user"),
+		})
+		return
+	}
+
+	entry, err := h.Loyalty.Adjust(ctx, body.UserId, adminID, body.Points, body.Reason)
+	var errBadRequest *errorspkg.ErrBadRequest
+	if errors.As(err, &errBadRequest) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to adjust loyalty points", l.Error(err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, loyaltyEntryRes(entry))
+}
+
+// loyaltyUser is the user whose points are asked for: requested for
+// admins, the caller for everyone else
+func (h *HandlerV1) loyaltyUser(r *http.Request, requested string) (string, int) {
+	if requested != "" {
+		role, statusCode := GetRoleFromToken(r, h.Config)
+		if statusCode != http.StatusOK {
+			return "", statusCode
+		}
+		if role == "admin" || role == "sudo" {
+			return requested, http.StatusOK
+		}
+	}
+	return GetIdFromToken(r, h.Config)
+}
+
+// redeemPoints spends the points asked for in body on the booking placed
+// under bookingID and takes them off quote
+func (h *HandlerV1) redeemPoints(ctx context.Context, userID, bookingID string, body *models.CreateBookingReq, quote *entity.Quote) (int, error) {
+	if body.Points == 0 {
+		return http.StatusOK, nil
+	}
+	if h.Loyalty.Worth(body.Points) > quote.Total() {
+		return http.StatusBadRequest, i18n.NewError("points_over_price")
+	}
+
+	discount, err := h.Loyalty.Redeem(ctx, userID, bookingID, body.Points)
+	var errBadRequest *errorspkg.ErrBadRequest
+	if errors.As(err, &errBadRequest) {
+		return http.StatusBadRequest, err
+	}
+	if err != nil {
+		h.Logger.Error("failed to redeem loyalty points", l.Error(err))
+		return http.StatusInternalServerError, i18n.NewError("try_again_later")
+	}
+
+	quote.Points = body.Points
+	quote.PointsDiscount = discount
+	return http.StatusOK, nil
+}
+
+// releaseDiscounts gives back the promo code use and the points of a
+// booking that was not made or got canceled
+func (h *HandlerV1) releaseDiscounts(ctx context.Context, bookingID string) {
+	h.releasePromo(ctx, bookingID)
+	if err := h.Loyalty.Refund(ctx, bookingID); err != nil {
+		h.Logger.Error("failed to refund loyalty points", l.Error(err))
+	}
+}
+
+// completeBooking closes a checked in booking once the guest has left and
+// credits its loyalty points. Earning is idempotent so a retried job
+// finishes the credit.
+func (h *HandlerV1) completeBooking(ctx context.Context, job *entity.Job) error {
+	record, err := h.BookingRecord.Get(ctx, job.BookingID)
+	if err != nil {
+		return err
+	}
+
+	changed, err := h.BookingRecord.ChangeState(ctx, record.ID, []string{entity.BookingStateCheckedIn}, entity.BookingStateCompleted)
+	if err != nil {
+		return err
+	}
+	if !changed && record.State != entity.BookingStateCompleted {
+		return nil
+	}
+
+	_, err = h.Loyalty.Earn(ctx, record)
+	return err
+}
+
+// scheduleCompletion plans completeBooking for when a checked in guest leaves
+func (h *HandlerV1) scheduleCompletion(ctx context.Context, record *entity.BookingRecord) {
+	runAt := time.Now()
+	if record.LeaveAt != nil && record.LeaveAt.After(runAt) {
+		runAt = *record.LeaveAt
+	}
+
+	err := h.Scheduler.Schedule(ctx, &entity.Job{
+		Kind:      entity.JobKindBookingComplete,
+		BookingID: record.ID,
+		RunAt:     runAt,
+	})
+	if err != nil {
+		h.Logger.Error("failed to schedule booking completion", l.Error(err))
+	}
+}
+
+func loyaltyEntryRes(entry *entity.LoyaltyEntry) *models.LoyaltyEntryRes {
+	res := models.LoyaltyEntryRes{
+		Id:        entry.ID,
+		UserId:    entry.UserID,
+		Kind:      entry.Kind,
+		Points:    entry.Points,
+		BookingId: entry.BookingID,
+		Reason:    entry.Reason,
+		CreatedBy: entry.CreatedBy,
+		CreatedAt: entry.CreatedAt.Format(time.RFC3339),
+	}
+	if entry.ExpiresAt != nil {
+		res.ExpiresAt = entry.ExpiresAt.Format(time.RFC3339)
+	}
+	return &res
+}
@@ -0,0 +1,37 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+
+	"Booking/api-service-booking/internal/pkg/i18n"
+	l "Booking/api-service-booking/internal/pkg/logger"
+)
+
+// checkManager lets admins and the owner of an establishment through, the
+// returned status goes to the client together with the error
+func (h *HandlerV1) checkManager(ctx context.Context, r *http.Request, category, establishmentID string) (int, error) {
+	role, statusCode := GetRoleFromToken(r, h.Config)
+	if statusCode != http.StatusOK {
+		return statusCode, i18n.NewError("log_in_again")
+	}
+	if role == "admin" || role == "sudo" {
+		return http.StatusOK, nil
+	}
+
+	userID, statusCode := GetIdFromToken(r, h.Config)
+	if statusCode != http.StatusOK {
+		return statusCode, i18n.NewError("log_in_again")
+	}
+
+	place, err := h.getBookedPlace(ctx, category, establishmentID)
+	if err != nil {
+		h.Logger.Error("failed to get establishment", l.Error(err))
+		return http.StatusNotFound, i18n.NewError("establishment_not_found")
+	}
+	if place.OwnerID != userID {
+		return http.StatusForbidden, i18n.NewError("owner_only")
+	}
+
+	return http.StatusOK, nil
+}
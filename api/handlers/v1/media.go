@@ -42,7 +42,7 @@ func (h *HandlerV1) UploadMedia(c *gin.Context) {
 	duration, err := time.ParseDuration(h.Config.Context.Timeout)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.Error{
-			Message: err.Error(),
+			Message: h.errorMessage(c, err),
 		})
 		log.Println(err.Error())
 		return
@@ -67,7 +67,7 @@ func (h *HandlerV1) UploadMedia(c *gin.Context) {
 
 		} else {
 			c.JSON(http.StatusInternalServerError, models.Error{
-				Message: err.Error(),
+				Message: h.errorMessage(c, err),
 			})
 			log.Println(err.Error())
 			return
@@ -77,7 +77,7 @@ func (h *HandlerV1) UploadMedia(c *gin.Context) {
 	userID, statusCode := GetIdFromToken(c.Request, h.Config)
 	if statusCode == 401 {
 		c.JSON(http.StatusUnauthorized, models.Error{
-			Message: "Log In Again",
+			Message: h.message(c, "log_in_again"),
 		})
 		return
 	}
@@ -89,7 +89,7 @@ func (h *HandlerV1) UploadMedia(c *gin.Context) {
 	})
 	if err != nil {
 		c.JSON(http.StatusBadRequest, models.Error{
-			Message: "Error getting user",
+			Message: h.message(c, "user_details_failed"),
 		})
 		return
 	}
@@ -111,7 +111,7 @@ func (h *HandlerV1) UploadMedia(c *gin.Context) {
 	err = minioClient.SetBucketPolicy(context.Background(), bucketName, policy)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.Error{
-			Message: err.Error(),
+			Message: h.errorMessage(c, err),
 		})
 		log.Println(err.Error())
 		return
@@ -121,7 +121,7 @@ func (h *HandlerV1) UploadMedia(c *gin.Context) {
 	err = c.ShouldBind(&file)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, models.Error{
-			Message: err.Error(),
+			Message: h.errorMessage(c, err),
 		})
 		log.Println(err.Error())
 		return
@@ -129,7 +129,7 @@ func (h *HandlerV1) UploadMedia(c *gin.Context) {
 
 	if file.File.Size > 10<<20 {
 		c.JSON(http.StatusBadRequest, models.Error{
-			Message: "File size cannot be larger than 10 MB",
+			Message: h.message(c, "file_too_large"),
 		})
 		return
 	}
@@ -138,7 +138,7 @@ func (h *HandlerV1) UploadMedia(c *gin.Context) {
 
 	if ext != ".png" && ext != ".jpg" && ext != ".svg" && ext != ".jpeg" {
 		c.JSON(http.StatusBadRequest, models.Error{
-			Message: "Only .jpg and .png format images are accepted",
+			Message: h.message(c, "image_format"),
 		})
 		return
 	}
@@ -155,7 +155,7 @@ func (h *HandlerV1) UploadMedia(c *gin.Context) {
 
 	if err := c.SaveUploadedFile(file.File, uploadPath); err != nil {
 		c.JSON(http.StatusInternalServerError, models.Error{
-			Message: err.Error(),
+			Message: h.errorMessage(c, err),
 		})
 		log.Println(err)
 		return
@@ -169,7 +169,7 @@ func (h *HandlerV1) UploadMedia(c *gin.Context) {
 
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.Error{
-			Message: err.Error(),
+			Message: h.errorMessage(c, err),
 		})
 		log.Println(err)
 		return
@@ -178,7 +178,7 @@ func (h *HandlerV1) UploadMedia(c *gin.Context) {
 	minioURL := fmt.Sprintf("https://media.touristan-bs.uz/%s/%s", bucketName, objectName)
 	// if err != nil {
 	// 	c.JSON(http.StatusInternalServerError, models.Error{
-	// 		Message: err.Error(),
+	// 		Message: h.errorMessage(c, err),
 	// 	})
 	// 	log.Println(err)
 	// 	return
@@ -188,7 +188,7 @@ func (h *HandlerV1) UploadMedia(c *gin.Context) {
 	user.User, err = h.Service.UserService().Update(ctx, user.User)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.Error{
-			Message: "Error updating user",
+			Message: h.message(c, "user_update_failed"),
 		})
 		return
 	}
@@ -219,7 +219,7 @@ func (h *HandlerV1) CreateEstablishmentMedia(c *gin.Context) {
 	duration, err := time.ParseDuration(h.Config.Context.Timeout)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.Error{
-			Message: err.Error(),
+			Message: h.errorMessage(c, err),
 		})
 		log.Println(err.Error())
 		return
@@ -244,7 +244,7 @@ func (h *HandlerV1) CreateEstablishmentMedia(c *gin.Context) {
 
 		} else {
 			c.JSON(http.StatusInternalServerError, models.Error{
-				Message: err.Error(),
+				Message: h.errorMessage(c, err),
 			})
 			log.Println(err.Error())
 			return
@@ -256,7 +256,7 @@ func (h *HandlerV1) CreateEstablishmentMedia(c *gin.Context) {
 	// hotel, err := h.Service.EstablishmentService().GetHotel(ctx, &pbe.GetHotelRequest{HotelId: hotelID})
 	// if err != nil {
 	//     c.JSON(http.StatusInternalServerError, models.Error{
-	//         Message: "Went wrong",
+	//         Message: h.message(c, "something_went_wrong"),
 	//     })
 	// 	log.Println("Error getting hotel")
 	//     return
@@ -279,7 +279,7 @@ func (h *HandlerV1) CreateEstablishmentMedia(c *gin.Context) {
 	err = minioClient.SetBucketPolicy(context.Background(), bucketName, policy)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.Error{
-			Message: err.Error(),
+			Message: h.errorMessage(c, err),
 		})
 		log.Println(err.Error())
 		return
@@ -289,7 +289,7 @@ func (h *HandlerV1) CreateEstablishmentMedia(c *gin.Context) {
 	err = c.ShouldBind(&file)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, models.Error{
-			Message: err.Error(),
+			Message: h.errorMessage(c, err),
 		})
 		log.Println(err.Error())
 		return
@@ -297,7 +297,7 @@ func (h *HandlerV1) CreateEstablishmentMedia(c *gin.Context) {
 
 	if file.File.Size > 10<<20 {
 		c.JSON(http.StatusBadRequest, models.Error{
-			Message: "File size cannot be larger than 10 MB",
+			Message: h.message(c, "file_too_large"),
 		})
 		return
 	}
@@ -306,7 +306,7 @@ func (h *HandlerV1) CreateEstablishmentMedia(c *gin.Context) {
 
 	if ext != ".png" && ext != ".jpg" && ext != ".svg" && ext != ".jpeg" {
 		c.JSON(http.StatusBadRequest, models.Error{
-			Message: "Only .jpg and .png format images are accepted",
+			Message: h.message(c, "image_format"),
 		})
 		return
 	}
@@ -323,7 +323,7 @@ func (h *HandlerV1) CreateEstablishmentMedia(c *gin.Context) {
 
 	if err := c.SaveUploadedFile(file.File, uploadPath); err != nil {
 		c.JSON(http.StatusInternalServerError, models.Error{
-			Message: err.Error(),
+			Message: h.errorMessage(c, err),
 		})
 		log.Println(err)
 		return
@@ -337,7 +337,7 @@ func (h *HandlerV1) CreateEstablishmentMedia(c *gin.Context) {
 
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.Error{
-			Message: err.Error(),
+			Message: h.errorMessage(c, err),
 		})
 		log.Println(err)
 		return
@@ -354,7 +354,7 @@ func (h *HandlerV1) CreateEstablishmentMedia(c *gin.Context) {
 	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.Error{
-			Message: err.Error(),
+			Message: h.errorMessage(c, err),
 		})
 		log.Println(err)
 		return
@@ -0,0 +1,33 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"Booking/api-service-booking/internal/pkg/i18n"
+	"Booking/api-service-booking/internal/pkg/validation"
+)
+
+// message tells the catalog message key in the language of the request
+func (h *HandlerV1) message(c *gin.Context, key string, params ...interface{}) string {
+	return i18n.T(h.locale(c), key, params...)
+}
+
+// errorMessage tells err in the language of the request when it is worded
+// by a catalog message, errors from other services go out as they are
+func (h *HandlerV1) errorMessage(c *gin.Context, err error) string {
+	return i18n.Message(h.locale(c), err)
+}
+
+// bindError answers a request whose body could not be bound, with the
+// fields that failed validation if that is why
+func (h *HandlerV1) bindError(c *gin.Context, err error) {
+	response := gin.H{
+		"error": h.message(c, "not_true_form"),
+	}
+	if fields := validation.Fields(err, h.locale(c)); fields != nil {
+		response["errors"] = fields
+	}
+	c.JSON(http.StatusBadRequest, response)
+}
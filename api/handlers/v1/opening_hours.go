@@ -0,0 +1,263 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+
+	"Booking/api-service-booking/api/models"
+	"Booking/api-service-booking/internal/entity"
+	errorspkg "Booking/api-service-booking/internal/errors"
+	"Booking/api-service-booking/internal/pkg/booktime"
+	"Booking/api-service-booking/internal/pkg/i18n"
+	l "Booking/api-service-booking/internal/pkg/logger"
+	"Booking/api-service-booking/internal/pkg/openinghours"
+	"Booking/api-service-booking/internal/pkg/otlp"
+	"Booking/api-service-booking/internal/pkg/query_parameter"
+)
+
+// SET OPENING HOURS
+// @Summary SET OPENING HOURS
+// @Security BearerAuth
+// @Description Api for setting the weekly opening hours of an establishment with exceptions for holidays. Days are written as "09:00-14:00,15:00-22:00" or "closed", in Asia/Tashkent time; a window closing at or before it opens runs past midnight. The schedule takes the place of a restaurant's opening_hours.
+// @Tags OPENING HOURS
+// @Accept json
+// @Produce json
+// @Param OpeningHours body models.OpeningHoursReq true "OpeningHours"
+// @Success 200 {object} models.OpeningHoursRes
+// @Failure 400 {object} models.StandartError
+// @Failure 403 {object} models.StandartError
+// @Failure 404 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/opening-hours [PUT]
+func (h *HandlerV1) SetOpeningHours(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "SetOpeningHours")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	var body models.OpeningHoursReq
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.bindError(c, err)
+		return
+	}
+
+	schedule, err := openinghours.ParseSchedule(body.Week, body.Exceptions)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	}
+
+	if statusCode, err := h.checkManager(ctx, c.Request, body.Category, body.HraId); err != nil {
+		c.JSON(statusCode, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	}
+
+	m := entity.OpeningSchedule{
+		Category:        body.Category,
+		EstablishmentID: body.HraId,
+		Schedule:        schedule,
+	}
+	err = h.OpeningHours.Save(ctx, &m)
+	var errBadRequest *errorspkg.ErrBadRequest
+	if errors.As(err, &errBadRequest) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to save opening hours", l.Error(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, openingHoursRes(&m))
+}
+
+// GET OPENING HOURS
+// @Summary GET OPENING HOURS
+// @Description Api for getting the weekly opening hours of an establishment and whether it is open now
+// @Tags OPENING HOURS
+// @Accept json
+// @Produce json
+// @Param category query string true "category"
+// @Param hra_id query string true "hra_id"
+// @Success 200 {object} models.OpeningHoursRes
+// @Failure 404 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/opening-hours [GET]
+func (h *HandlerV1) GetOpeningHours(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "GetOpeningHours")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	m, err := h.OpeningHours.Get(ctx, c.Query("category"), c.Query("hra_id"))
+	if errors.Is(err, errorspkg.ErrorNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": h.message(c, "no_opening_hours"),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to get opening hours", l.Error(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, openingHoursRes(m))
+}
+
+// DELETE OPENING HOURS
+// @Summary DELETE OPENING HOURS
+// @Security BearerAuth
+// @Description Api for removing the weekly opening hours of an establishment, a restaurant goes back to its opening_hours
+// @Tags OPENING HOURS
+// @Accept json
+// @Produce json
+// @Param category query string true "category"
+// @Param hra_id query string true "hra_id"
+// @Success 200 {object} string
+// @Failure 403 {object} models.StandartError
+// @Failure 404 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/opening-hours [DELETE]
+func (h *HandlerV1) DeleteOpeningHours(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "DeleteOpeningHours")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	category, establishmentID := c.Query("category"), c.Query("hra_id")
+	if statusCode, err := h.checkManager(ctx, c.Request, category, establishmentID); err != nil {
+		c.JSON(statusCode, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	}
+
+	err := h.OpeningHours.Delete(ctx, category, establishmentID)
+	if errors.Is(err, errorspkg.ErrorNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": h.message(c, "no_opening_hours"),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to delete opening hours", l.Error(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, "successfully deleted...")
+}
+
+func openingHoursRes(m *entity.OpeningSchedule) *models.OpeningHoursRes {
+	week, exceptions := m.Schedule.Format()
+	return &models.OpeningHoursRes{
+		Category:   m.Category,
+		HraId:      m.EstablishmentID,
+		Week:       week,
+		Exceptions: exceptions,
+		Status:     openingStatusRes(m.Schedule.Status(time.Now())),
+		UpdatedAt:  m.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func openingStatusRes(status openinghours.Status) *models.OpeningStatusRes {
+	response := models.OpeningStatusRes{OpenNow: status.Open}
+	if !status.OpensAt.IsZero() {
+		response.OpensAt = status.OpensAt.Format(time.RFC3339)
+	}
+	if !status.ClosesAt.IsZero() {
+		response.ClosesAt = status.ClosesAt.Format(time.RFC3339)
+	}
+	return &response
+}
+
+// openingStatuses tells whether the establishments of category are open
+// now, by id, as openingSchedules reads them. All of them are left out if
+// the schedules can not be read.
+func (h *HandlerV1) openingStatuses(ctx context.Context, category string, plain map[string]string) map[string]*models.OpeningStatusRes {
+	schedules, err := h.openingSchedules(ctx, category, plain)
+	if err != nil {
+		h.Logger.Error("failed to get opening hours", l.Error(err))
+		return nil
+	}
+
+	now := time.Now()
+	statuses := make(map[string]*models.OpeningStatusRes, len(schedules))
+	for id, schedule := range schedules {
+		statuses[id] = openingStatusRes(schedule.Status(now))
+	}
+	return statuses
+}
+
+// openingSchedules returns the opening schedules of the establishments of
+// category, by id. plain maps each id to its opening_hours, empty if it
+// has none, which are read as the same hours every day where there is no
+// schedule. Establishments with neither are left out.
+func (h *HandlerV1) openingSchedules(ctx context.Context, category string, plain map[string]string) (map[string]*openinghours.Schedule, error) {
+	ids := make([]string, 0, len(plain))
+	for id := range plain {
+		ids = append(ids, id)
+	}
+	schedules, err := h.OpeningHours.Schedules(ctx, category, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]*openinghours.Schedule, len(plain))
+	for id, value := range plain {
+		schedule, ok := schedules[id]
+		if !ok {
+			hours, err := openinghours.Parse(value)
+			if err != nil {
+				continue
+			}
+			schedule = openinghours.Daily(hours)
+		}
+		result[id] = schedule
+	}
+	return result, nil
+}
+
+// openAt reads the open_now and open_at filters of a list, nil when
+// neither is set. open_at is read in Asia/Tashkent time unless it has an
+// offset.
+func openAt(search *query_parameter.Search) (*time.Time, error) {
+	if value := search.Filters["open_at"]; value != "" {
+		at, dateOnly, err := booktime.Parse(value)
+		if err != nil || dateOnly {
+			return nil, i18n.NewError("field_format", "open_at", "2006-01-02T15:04")
+		}
+		return &at, nil
+	}
+
+	switch search.Filters["open_now"] {
+	case "", "false":
+		return nil, nil
+	case "true":
+		now := time.Now()
+		return &now, nil
+	}
+	return nil, i18n.NewError("field_one_of", "open_now", "true, false")
+}
@@ -0,0 +1,43 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"Booking/api-service-booking/internal/pkg/i18n"
+	"Booking/api-service-booking/internal/pkg/query_parameter"
+)
+
+// maxPageLimit caps the limit of the paged lists
+const maxPageLimit = 100
+
+// pageRange reads page and limit of a list the establishment service
+// returns whole and cuts the page out of its n items. Old clients sending
+// neither still get all of it. It writes the error response and returns
+// false if they are wrong.
+func (h *HandlerV1) pageRange(c *gin.Context, n int) (from, to int, ok bool) {
+	if c.Query("limit") == "" && c.Query("page") == "" {
+		return 0, n, true
+	}
+
+	qp := query_parameter.New(c.Request.URL.Query())
+	limit, page := qp.GetLimit(), qp.GetPage()
+	var err error
+	switch {
+	case limit == 0 || limit > maxPageLimit:
+		err = i18n.NewError("field_between", "limit", 1, maxPageLimit)
+	case page == 0:
+		err = i18n.NewError("field_at_least", "page", 1)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return 0, 0, false
+	}
+
+	from = int(min((page-1)*limit, uint64(n)))
+	to = int(min(uint64(from)+limit, uint64(n)))
+	return from, to, true
+}
@@ -0,0 +1,538 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+
+	"Booking/api-service-booking/api/models"
+	pbb "Booking/api-service-booking/genproto/booking-proto"
+	"Booking/api-service-booking/internal/entity"
+	errorspkg "Booking/api-service-booking/internal/errors"
+	"Booking/api-service-booking/internal/pkg/booktime"
+	"Booking/api-service-booking/internal/pkg/i18n"
+	l "Booking/api-service-booking/internal/pkg/logger"
+	"Booking/api-service-booking/internal/pkg/otlp"
+	"Booking/api-service-booking/internal/pkg/utils"
+)
+
+// CREATE PROMOTION
+// @Summary CREATE PROMOTION
+// @Security BearerAuth
+// @Description Api for creating a promo code campaign, value is a percent for kind percent and an amount for kind fixed
+// @Tags PROMOTION
+// @Accept json
+// @Produce json
+// @Param Promotion body models.PromotionReq true "Promotion"
+// @Success 201 {object} models.PromotionRes
+// @Failure 400 {object} models.StandartError
+// @Failure 409 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/promotions [POST]
+func (h *HandlerV1) CreatePromotion(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "CreatePromotion")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	var body models.PromotionReq
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.bindError(c, err)
+		return
+	}
+
+	promotion, err := promotionFromReq(&body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	}
+
+	err = h.Promotion.Create(ctx, promotion)
+	var errBadRequest *errorspkg.ErrBadRequest
+	switch {
+	case errors.As(err, &errBadRequest):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	case errors.Is(err, errorspkg.ErrorConflict):
+		c.JSON(http.StatusConflict, gin.H{
+			"error": h.message(c, "promotion_code_taken"),
+		})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to create promotion", l.Error(err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, promotionRes(promotion))
+}
+
+// GET PROMOTION
+// @Summary GET PROMOTION
+// @Security BearerAuth
+// @Description Api for getting a promotion with the number of times it was redeemed
+// @Tags PROMOTION
+// @Accept json
+// @Produce json
+// @Param id path string true "ID"
+// @Success 200 {object} models.PromotionRes
+// @Failure 404 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/promotions/{id} [GET]
+func (h *HandlerV1) GetPromotion(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "GetPromotion")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	promotion, err := h.Promotion.Get(ctx, c.Param("id"))
+	if errors.Is(err, errorspkg.ErrorNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": h.message(c, "promotion_not_found"),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to get promotion", l.Error(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, promotionRes(promotion))
+}
+
+// LIST PROMOTIONS
+// @Summary LIST PROMOTIONS
+// @Security BearerAuth
+// @Description Api for listing promotions, newest first
+// @Tags PROMOTION
+// @Accept json
+// @Produce json
+// @Param request query models.Pagination true "request"
+// @Success 200 {object} models.ListPromotionsRes
+// @Failure 400 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/promotions [GET]
+func (h *HandlerV1) ListPromotions(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "ListPromotions")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	params, errStr := utils.ParseQueryParam(c.Request.URL.Query())
+	if errStr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": h.errorMessage(c, errStr[0]),
+		})
+		return
+	}
+
+	promotions, count, err := h.Promotion.List(ctx, params.Limit, (params.Page-1)*params.Limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to list promotions", l.Error(err))
+		return
+	}
+
+	response := models.ListPromotionsRes{
+		Promotions: []*models.PromotionRes{},
+		Count:      count,
+	}
+	for _, promotion := range promotions {
+		response.Promotions = append(response.Promotions, promotionRes(promotion))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// UPDATE PROMOTION
+// @Summary UPDATE PROMOTION
+// @Security BearerAuth
+// @Description Api for changing a promotion, its redemption count is kept
+// @Tags PROMOTION
+// @Accept json
+// @Produce json
+// @Param Promotion body models.UpdatePromotionReq true "Promotion"
+// @Success 200 {object} models.PromotionRes
+// @Failure 400 {object} models.StandartError
+// @Failure 404 {object} models.StandartError
+// @Failure 409 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/promotions [PUT]
+func (h *HandlerV1) UpdatePromotion(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "UpdatePromotion")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	var body models.UpdatePromotionReq
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.bindError(c, err)
+		return
+	}
+
+	current, err := h.Promotion.Get(ctx, body.Id)
+	if errors.Is(err, errorspkg.ErrorNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": h.message(c, "promotion_not_found"),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to get promotion", l.Error(err))
+		return
+	}
+
+	promotion, err := promotionFromReq(&body.PromotionReq)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	}
+	promotion.ID = current.ID
+	promotion.Redemptions = current.Redemptions
+	promotion.CreatedAt = current.CreatedAt
+
+	err = h.Promotion.Update(ctx, promotion)
+	var errBadRequest *errorspkg.ErrBadRequest
+	switch {
+	case errors.As(err, &errBadRequest):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	case errors.Is(err, errorspkg.ErrorConflict):
+		c.JSON(http.StatusConflict, gin.H{
+			"error": h.message(c, "promotion_code_taken"),
+		})
+		return
+	case errors.Is(err, errorspkg.ErrorNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": h.message(c, "promotion_not_found"),
+		})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to update promotion", l.Error(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, promotionRes(promotion))
+}
+
+// DELETE PROMOTION
+// @Summary DELETE PROMOTION
+// @Security BearerAuth
+// @Description Api for ending a promotion, bookings that already used it keep their discount
+// @Tags PROMOTION
+// @Accept json
+// @Produce json
+// @Param id path string true "ID"
+// @Success 200 {object} models.StandartError
+// @Failure 404 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/promotions/{id} [DELETE]
+func (h *HandlerV1) DeletePromotion(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "DeletePromotion")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	err := h.Promotion.Delete(ctx, c.Param("id"))
+	if errors.Is(err, errorspkg.ErrorNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": h.message(c, "promotion_not_found"),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to delete promotion", l.Error(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Promotion deleted",
+	})
+}
+
+// VALIDATE PROMO CODE
+// @Summary VALIDATE PROMO CODE
+// @Security BearerAuth
+// @Description Api for checking a promo code against a booking before making it, returns the quoted price with the discount
+// @Tags PROMOTION
+// @Accept json
+// @Produce json
+// @Param Booking body models.ValidatePromoReq true "Booking"
+// @Success 200 {object} models.QuoteRes
+// @Failure 400 {object} models.StandartError
+// @Failure 404 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/promotions/validate [POST]
+func (h *HandlerV1) ValidatePromo(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "ValidatePromo")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	var body models.ValidatePromoReq
+	if err := c.ShouldBindJSON(&body); err != nil || body.PromoCode == "" {
+		h.bindError(c, err)
+		return
+	}
+
+	userID, statusCode := GetIdFromToken(c.Request, h.Config)
+	if statusCode != http.StatusOK {
+		c.JSON(statusCode, gin.H{
+			"error": h.message(c, "cant_get_user"),
+		})
+		return
+	}
+
+	var tickets []*entity.Ticket
+	if body.Category == categoryAttraction && len(body.Tickets) > 0 {
+		var err error
+		if tickets, statusCode, err = h.priceTickets(ctx, body.HraId, body.Tickets); err != nil {
+			c.JSON(statusCode, gin.H{
+				"error": h.errorMessage(c, err),
+			})
+			return
+		}
+	}
+
+	quote, _, statusCode, err := h.quoteBooking(ctx, body.Category, userID, body.HraId, body.WillArrive, body.WillLeave, body.NumberOfPeople, tickets, body.PromoCode)
+	if err != nil {
+		c.JSON(statusCode, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.QuoteRes{
+		Price:     quote.Price,
+		Discount:  quote.Discount,
+		Total:     quote.Total(),
+		PromoCode: quote.PromoCode,
+	})
+}
+
+// quoteBooking prices a booking and applies promoCode to it. The returned
+// promotion is nil when no code was given, the status goes to the client.
+func (h *HandlerV1) quoteBooking(ctx context.Context, category, userID, establishmentID, willArrive, willLeave string, people int64, tickets []*entity.Ticket, promoCode string) (*entity.Quote, *entity.Promotion, int, error) {
+	// unparsable dates are priced as a single night
+	arriveAt, _, _ := booktime.Parse(willArrive)
+	leaveAt, _, _ := booktime.Parse(willLeave)
+
+	price, err := h.Pricing.Price(ctx, category, establishmentID, arriveAt, leaveAt, people, tickets)
+	if err != nil {
+		h.Logger.Error("failed to price booking", l.Error(err))
+		return nil, nil, http.StatusInternalServerError, i18n.NewError("try_again_later")
+	}
+
+	quote := entity.Quote{Price: price}
+	if promoCode == "" {
+		return &quote, nil, http.StatusOK, nil
+	}
+
+	place, err := h.getBookedPlace(ctx, category, establishmentID)
+	if err != nil {
+		h.Logger.Error("failed to get establishment", l.Error(err))
+		return nil, nil, http.StatusNotFound, i18n.NewError("establishment_not_found")
+	}
+
+	firstBooking, err := h.firstBooking(ctx, userID)
+	if err != nil {
+		h.Logger.Error("failed to count user bookings", l.Error(err))
+		return nil, nil, http.StatusInternalServerError, i18n.NewError("try_again_later")
+	}
+
+	promotion, discount, err := h.Promotion.Check(ctx, promoCode, &entity.PromoContext{
+		UserID:       userID,
+		Category:     category,
+		City:         place.City,
+		Price:        price,
+		FirstBooking: firstBooking,
+	})
+	var errBadRequest *errorspkg.ErrBadRequest
+	if errors.As(err, &errBadRequest) {
+		return nil, nil, http.StatusBadRequest, err
+	}
+	if err != nil {
+		h.Logger.Error("failed to check promo code", l.Error(err))
+		return nil, nil, http.StatusInternalServerError, i18n.NewError("try_again_later")
+	}
+
+	quote.Discount = discount
+	quote.PromoCode = promotion.Code
+	return &quote, promotion, http.StatusOK, nil
+}
+
+// firstBooking reports whether userID has no booking that went ahead yet.
+// The booking records only hold bookings placed since they were kept, so
+// the bookings in the booking service are looked through as well for one
+// that was not canceled.
+func (h *HandlerV1) firstBooking(ctx context.Context, userID string) (bool, error) {
+	booked, err := h.BookingRecord.CountByUser(ctx, userID, []string{
+		entity.BookingStateConfirmed,
+		entity.BookingStateCheckedIn,
+		entity.BookingStateCompleted,
+	})
+	if err != nil || booked > 0 {
+		return false, err
+	}
+
+	const limit = 100
+	for _, category := range []string{categoryHotel, categoryRestaurant, categoryAttraction} {
+		for offset := uint64(0); ; offset += limit {
+			books, err := h.backendBookings(ctx, category, &pbb.ListReqById{
+				Limit:  limit,
+				Offset: offset,
+				Id:     &pbb.Id{Id: userID},
+			})
+			if err != nil {
+				return false, err
+			}
+			for _, book := range books {
+				if !book.IsCanceled {
+					return false, nil
+				}
+			}
+			if len(books) < limit {
+				break
+			}
+		}
+	}
+	return true, nil
+}
+
+// redeemQuote quotes a booking about to be placed under bookingID and takes
+// one use of its promo code
+func (h *HandlerV1) redeemQuote(ctx context.Context, category, userID, bookingID string, body *models.CreateBookingReq, tickets []*entity.Ticket) (*entity.Quote, int, error) {
+	quote, promotion, status, err := h.quoteBooking(ctx, category, userID, body.HraId, body.WillArrive, body.WillLeave, body.NumberOfPeople, tickets, body.PromoCode)
+	if err != nil || promotion == nil {
+		return quote, status, err
+	}
+
+	err = h.Promotion.Redeem(ctx, promotion, userID, bookingID, quote.Discount)
+	var errBadRequest *errorspkg.ErrBadRequest
+	if errors.As(err, &errBadRequest) {
+		return nil, http.StatusConflict, err
+	}
+	if err != nil {
+		h.Logger.Error("failed to redeem promo code", l.Error(err))
+		return nil, http.StatusInternalServerError, i18n.NewError("try_again_later")
+	}
+
+	return quote, http.StatusOK, nil
+}
+
+// releasePromo gives back the promo code use of a booking that was not
+// made or got canceled
+func (h *HandlerV1) releasePromo(ctx context.Context, bookingID string) {
+	if err := h.Promotion.Release(ctx, bookingID); err != nil {
+		h.Logger.Error("failed to release promo code", l.Error(err))
+	}
+}
+
+// priceTickets prices the requested tickets of an attraction without
+// selling them
+func (h *HandlerV1) priceTickets(ctx context.Context, attractionID string, requested []*models.TicketReq) ([]*entity.Ticket, int, error) {
+	types, err := h.AttractionTicket.ListTypes(ctx, attractionID)
+	if err != nil {
+		h.Logger.Error("failed to list ticket types", l.Error(err))
+		return nil, http.StatusInternalServerError, i18n.NewError("try_again_later")
+	}
+
+	prices := map[string]int64{}
+	for _, ticketType := range types {
+		prices[ticketType.Kind] = ticketType.Price
+	}
+
+	var tickets []*entity.Ticket
+	for _, ticket := range requested {
+		price, ok := prices[ticket.Kind]
+		if !ok {
+			return nil, http.StatusBadRequest, i18n.NewError("ticket_kind_not_sold", ticket.Kind)
+		}
+		for i := 0; i < ticket.Quantity; i++ {
+			tickets = append(tickets, &entity.Ticket{Kind: ticket.Kind, Price: price})
+		}
+	}
+
+	return tickets, http.StatusOK, nil
+}
+
+func promotionFromReq(body *models.PromotionReq) (*entity.Promotion, error) {
+	startsAt, _, err := booktime.Parse(body.StartsAt)
+	if err != nil {
+		return nil, i18n.NewError("field_format", "starts_at", "2024-06-01T00:00:00")
+	}
+	endsAt, _, err := booktime.Parse(body.EndsAt)
+	if err != nil {
+		return nil, i18n.NewError("field_format", "ends_at", "2024-09-01T00:00:00")
+	}
+
+	return &entity.Promotion{
+		Code:             body.Code,
+		Description:      body.Description,
+		Kind:             body.Kind,
+		Value:            body.Value,
+		Category:         body.Category,
+		City:             body.City,
+		FirstBookingOnly: body.FirstBookingOnly,
+		MinPrice:         body.MinPrice,
+		MaxRedemptions:   body.MaxRedemptions,
+		PerUserLimit:     body.PerUserLimit,
+		StartsAt:         startsAt.UTC(),
+		EndsAt:           endsAt.UTC(),
+	}, nil
+}
+
+func promotionRes(promotion *entity.Promotion) *models.PromotionRes {
+	return &models.PromotionRes{
+		Id:               promotion.ID,
+		Code:             promotion.Code,
+		Description:      promotion.Description,
+		Kind:             promotion.Kind,
+		Value:            promotion.Value,
+		Category:         promotion.Category,
+		City:             promotion.City,
+		FirstBookingOnly: promotion.FirstBookingOnly,
+		MinPrice:         promotion.MinPrice,
+		MaxRedemptions:   promotion.MaxRedemptions,
+		PerUserLimit:     promotion.PerUserLimit,
+		Redemptions:      promotion.Redemptions,
+		StartsAt:         promotion.StartsAt.In(booktime.Location()).Format("2006-01-02T15:04:05"),
+		EndsAt:           promotion.EndsAt.In(booktime.Location()).Format("2006-01-02T15:04:05"),
+		CreatedAt:        promotion.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:        promotion.UpdatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,120 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+
+	"Booking/api-service-booking/api/models"
+	"Booking/api-service-booking/internal/entity"
+	errorspkg "Booking/api-service-booking/internal/errors"
+	l "Booking/api-service-booking/internal/pkg/logger"
+	"Booking/api-service-booking/internal/pkg/otlp"
+)
+
+// SET RATE
+// @Summary SET RATE
+// @Security BearerAuth
+// @Description Api for setting the base price of an establishment: per night for hotels, per guest for restaurants and per visitor for attractions without ticket types
+// @Tags PRICING
+// @Accept json
+// @Produce json
+// @Param Rate body models.RateReq true "Rate"
+// @Success 200 {object} models.RateRes
+// @Failure 400 {object} models.StandartError
+// @Failure 403 {object} models.StandartError
+// @Failure 404 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/rates [PUT]
+func (h *HandlerV1) SetRate(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "SetRate")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	var body models.RateReq
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.bindError(c, err)
+		return
+	}
+
+	if statusCode, err := h.checkManager(ctx, c.Request, body.Category, body.HraId); err != nil {
+		c.JSON(statusCode, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	}
+
+	rate := entity.Rate{
+		Category:        body.Category,
+		EstablishmentID: body.HraId,
+		Price:           body.Price,
+	}
+	err := h.Pricing.SaveRate(ctx, &rate)
+	var errBadRequest *errorspkg.ErrBadRequest
+	if errors.As(err, &errBadRequest) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to save rate", l.Error(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, rateRes(&rate))
+}
+
+// GET RATE
+// @Summary GET RATE
+// @Description Api for getting the base price of an establishment
+// @Tags PRICING
+// @Accept json
+// @Produce json
+// @Param category query string true "category"
+// @Param hra_id query string true "hra_id"
+// @Success 200 {object} models.RateRes
+// @Failure 404 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/rates [GET]
+func (h *HandlerV1) GetRate(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "GetRate")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	rate, err := h.Pricing.GetRate(ctx, c.Query("category"), c.Query("hra_id"))
+	if errors.Is(err, errorspkg.ErrorNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": h.message(c, "no_rate"),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to get rate", l.Error(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, rateRes(rate))
+}
+
+func rateRes(rate *entity.Rate) *models.RateRes {
+	return &models.RateRes{
+		Category:  rate.Category,
+		HraId:     rate.EstablishmentID,
+		Price:     rate.Price,
+		UpdatedAt: rate.UpdatedAt.Format(time.RFC3339),
+	}
+}
@@ -54,9 +54,7 @@ func (h HandlerV1) RegisterUser(c *gin.Context) {
 
 	err := c.ShouldBindJSON(&body)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
-		})
+		h.bindError(c, err)
 		h.Logger.Error("failed to bind json", l.Error(err))
 		return
 	}
@@ -68,7 +66,7 @@ func (h HandlerV1) RegisterUser(c *gin.Context) {
 	isEmail := val.IsValidEmail(body.Email)
 	if !isEmail {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Incorrect Email. Try again",
+			"error": h.message(c, "incorrect_email"),
 		})
 
 		h.Logger.Error("Incorrect Email. Try again")
@@ -78,7 +76,7 @@ func (h HandlerV1) RegisterUser(c *gin.Context) {
 	isPassword := val.IsValidPassword(body.Password)
 	if !isPassword {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Password must be at least 8 (numbers and characters) long",
+			"error": h.message(c, "weak_password"),
 		})
 
 		h.Logger.Error("Password must be at least 8 (numbers and characters) long")
@@ -91,7 +89,7 @@ func (h HandlerV1) RegisterUser(c *gin.Context) {
 	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": err.Error(),
+			"error": h.errorMessage(c, err),
 		})
 		h.Logger.Error("Failed to check email uniquess", l.Error(err))
 		return
@@ -99,7 +97,7 @@ func (h HandlerV1) RegisterUser(c *gin.Context) {
 
 	if result.Code == 1 {
 		c.JSON(http.StatusConflict, gin.H{
-			"error": "Email already in use, please use another email address",
+			"error": h.message(c, "email_taken"),
 		})
 		h.Logger.Error("failed to check email unique", l.Error(err))
 		return
@@ -124,7 +122,7 @@ func (h HandlerV1) RegisterUser(c *gin.Context) {
 	userByte, err := json.Marshal(toRedis)
 	if err != nil {
 		c.JSON(http.StatusConflict, gin.H{
-			"error": err.Error(),
+			"error": h.errorMessage(c, err),
 		})
 		h.Logger.Error("Failed to marshal body", l.Error(err))
 		return
@@ -132,7 +130,7 @@ func (h HandlerV1) RegisterUser(c *gin.Context) {
 	_, err = rdb.Set(ctx, body.Email, userByte, time.Minute*3).Result()
 	if err != nil {
 		c.JSON(http.StatusConflict, gin.H{
-			"error": err.Error(),
+			"error": h.errorMessage(c, err),
 		})
 		h.Logger.Error("Failed to set object to redis", l.Error(err))
 		return
@@ -180,7 +178,7 @@ func (h HandlerV1) Verification(c *gin.Context) {
 	val, err := rdb.Get(ctx, email).Result()
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Incorrect email. Try again ..",
+			"error": h.message(c, "incorrect_email"),
 		})
 		h.Logger.Error("Failed to get user from redis", l.Error(err))
 		return
@@ -189,7 +187,7 @@ func (h HandlerV1) Verification(c *gin.Context) {
 	var userdetail models.ClientRedis
 	if err := json.Unmarshal([]byte(val), &userdetail); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Unmarshiling error",
+			"error": h.message(c, "something_went_wrong"),
 		})
 		h.Logger.Error("Error unmarshalling userdetail", l.Error(err))
 		return
@@ -197,7 +195,7 @@ func (h HandlerV1) Verification(c *gin.Context) {
 
 	if userdetail.Code != code {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Incorrect code. Try again",
+			"error": h.message(c, "incorrect_code"),
 		})
 		return
 	}
@@ -205,7 +203,7 @@ func (h HandlerV1) Verification(c *gin.Context) {
 	id, err := uuid.NewUUID()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "error while generating uuid",
+			"error": h.message(c, "something_went_wrong"),
 		})
 		h.Logger.Error("Error generate new uuid", l.Error(err))
 		return
@@ -222,7 +220,7 @@ func (h HandlerV1) Verification(c *gin.Context) {
 	access, refresh, err := h.JwtHandler.GenerateJwt()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "error while generating jwt",
+			"error": h.message(c, "something_went_wrong"),
 		})
 		h.Logger.Error("error generate new jwt tokens", l.Error(err))
 		return
@@ -231,7 +229,7 @@ func (h HandlerV1) Verification(c *gin.Context) {
 	userdetail.Password, err = etc.HashPassword(userdetail.Password)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Oops. Something went wrong with password",
+			"error": h.message(c, "something_went_wrong"),
 		})
 		h.Logger.Error("error in hash password", l.Error(err))
 		return
@@ -252,7 +250,7 @@ func (h HandlerV1) Verification(c *gin.Context) {
 	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Error while create user",
+			"error": h.message(c, "something_went_wrong"),
 		})
 		h.Logger.Error("error in create user", l.Error(err))
 		return
@@ -297,9 +295,7 @@ func (h HandlerV1) Login(c *gin.Context) {
 
 	err := c.ShouldBindJSON(&body)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
-		})
+		h.bindError(c, err)
 		h.Logger.Error("failed to bind json", l.Error(err))
 		return
 	}
@@ -312,7 +308,7 @@ func (h HandlerV1) Login(c *gin.Context) {
 	})
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Incorrect email or password",
+			"error": h.message(c, "incorrect_credentials"),
 		})
 		h.Logger.Error("error while get user in login", l.Error(err))
 		return
@@ -338,7 +334,7 @@ func (h HandlerV1) Login(c *gin.Context) {
 	access, refresh, err := h.JwtHandler.GenerateJwt()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Went wrong",
+			"error": h.message(c, "something_went_wrong"),
 		})
 		h.Logger.Error("error while generate JWT in login", l.Error(err))
 		return
@@ -359,7 +355,7 @@ func (h HandlerV1) Login(c *gin.Context) {
 	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Went wrong",
+			"error": h.message(c, "something_went_wrong"),
 		})
 		h.Logger.Error("error while update user in login", l.Error(err))
 		return
@@ -404,9 +400,7 @@ func (h HandlerV1) LoginAdmin(c *gin.Context) {
 
 	err := c.ShouldBindJSON(&body)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
-		})
+		h.bindError(c, err)
 		h.Logger.Error("failed to bind json", l.Error(err))
 		return
 	}
@@ -419,7 +413,7 @@ func (h HandlerV1) LoginAdmin(c *gin.Context) {
 	})
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Incorrect email or password",
+			"error": h.message(c, "incorrect_credentials"),
 		})
 		h.Logger.Error("error while get user in login admin", l.Error(err))
 		return
@@ -428,7 +422,7 @@ func (h HandlerV1) LoginAdmin(c *gin.Context) {
 	if user.User.Role != "admin" {
 		if user.User.Role != "sudo" {
 			c.JSON(http.StatusBadRequest, gin.H{
-				"error": "Permission denied",
+				"error": h.message(c, "permission_denied"),
 			})
 			h.Logger.Error("Role not admin")
 			return
@@ -454,7 +448,7 @@ func (h HandlerV1) LoginAdmin(c *gin.Context) {
 	access, refresh, err := h.JwtHandler.GenerateJwt()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Went wrong",
+			"error": h.message(c, "something_went_wrong"),
 		})
 		h.Logger.Error("error while generate JWT in login", l.Error(err))
 		return
@@ -475,7 +469,7 @@ func (h HandlerV1) LoginAdmin(c *gin.Context) {
 	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Went wrong",
+			"error": h.message(c, "something_went_wrong"),
 		})
 		h.Logger.Error("error while update user in login", l.Error(err))
 		return
@@ -530,7 +524,7 @@ func (h HandlerV1) ForgetPassword(c *gin.Context) {
 	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Went wrong",
+			"error": h.message(c, "something_went_wrong"),
 		})
 		h.Logger.Error("error while check unique in forget password", l.Error(err))
 		return
@@ -560,7 +554,7 @@ func (h HandlerV1) ForgetPassword(c *gin.Context) {
 	userByte, err := json.Marshal(toRedis)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": err.Error(),
+			"error": h.errorMessage(c, err),
 		})
 		h.Logger.Error("Failed to marshal body", l.Error(err))
 		return
@@ -568,7 +562,7 @@ func (h HandlerV1) ForgetPassword(c *gin.Context) {
 	_, err = rdb.Set(ctx, toRedis.Email, userByte, time.Minute*10).Result()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": err.Error(),
+			"error": h.errorMessage(c, err),
 		})
 		h.Logger.Error("Failed to set object to redis", l.Error(err))
 		return
@@ -618,7 +612,7 @@ func (h HandlerV1) ForgetPasswordVerify(c *gin.Context) {
 	val, err := rdb.Get(ctx, email).Result()
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Incorrect email. Try again ..",
+			"error": h.message(c, "incorrect_email"),
 		})
 		h.Logger.Error("Failed to get user from redis", l.Error(err))
 		return
@@ -626,7 +620,7 @@ func (h HandlerV1) ForgetPasswordVerify(c *gin.Context) {
 
 	if err := json.Unmarshal([]byte(val), &userdetail); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Unmarshiling error",
+			"error": h.message(c, "something_went_wrong"),
 		})
 		h.Logger.Error("Error unmarshalling userdetail in forget password verify", l.Error(err))
 		return
@@ -634,7 +628,7 @@ func (h HandlerV1) ForgetPasswordVerify(c *gin.Context) {
 
 	if userdetail.Code != code {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Incorrect code. Try again",
+			"error": h.message(c, "incorrect_code"),
 		})
 		return
 	}
@@ -672,7 +666,7 @@ func (h HandlerV1) SetNewPassword(c *gin.Context) {
 	isPassword := val.IsValidPassword(password)
 	if !isPassword {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Password must be at least 8 (numbers and characters) long",
+			"error": h.message(c, "weak_password"),
 		})
 
 		h.Logger.Error("Password must be at least 8 (numbers and characters) long")
@@ -684,7 +678,7 @@ func (h HandlerV1) SetNewPassword(c *gin.Context) {
 	})
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Incorrect email. Try again ..",
+			"error": h.message(c, "incorrect_email"),
 		})
 		h.Logger.Error("Failed to get user from set new password", l.Error(err))
 		return
@@ -701,7 +695,7 @@ func (h HandlerV1) SetNewPassword(c *gin.Context) {
 	access, refresh, err := h.JwtHandler.GenerateJwt()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Went wrong",
+			"error": h.message(c, "something_went_wrong"),
 		})
 		h.Logger.Error("error while generate JWT in login", l.Error(err))
 		return
@@ -710,7 +704,7 @@ func (h HandlerV1) SetNewPassword(c *gin.Context) {
 	password, err = etc.HashPassword(password)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Oops. Something went wrong with password",
+			"error": h.message(c, "something_went_wrong"),
 		})
 		h.Logger.Error("error while hash password in set new password", l.Error(err))
 		return
@@ -731,7 +725,7 @@ func (h HandlerV1) SetNewPassword(c *gin.Context) {
 	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Oops. Something went wrong with password",
+			"error": h.message(c, "something_went_wrong"),
 		})
 		h.Logger.Error("error while hash password in set new password", l.Error(err))
 		return
@@ -781,7 +775,7 @@ func (h HandlerV1) UpdateToken(c *gin.Context) {
 	})
 	if err != nil {
 		c.JSON(500, gin.H{
-			"error": "Incorrect token.",
+			"error": h.message(c, "incorrect_token"),
 		})
 		h.Logger.Error("Failed to get user in update token", l.Error(err))
 		return
@@ -790,7 +784,7 @@ func (h HandlerV1) UpdateToken(c *gin.Context) {
 	resClaim, err := tokens.ExtractClaim(RToken, []byte(h.Config.Token.SignInKey))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Went wrong, 1",
+			"error": h.message(c, "incorrect_token"),
 		})
 		h.Logger.Error("Failed to extract token update token", l.Error(err))
 		return
@@ -810,7 +804,7 @@ func (h HandlerV1) UpdateToken(c *gin.Context) {
 		accessR, refreshR, err := h.JwtHandler.GenerateJwt()
 		if err != nil {
 			c.JSON(http.StatusConflict, gin.H{
-				"error": "Went wrong",
+				"error": h.message(c, "something_went_wrong"),
 			})
 			h.Logger.Error("Failed to generate token update token", l.Error(err))
 			return
@@ -830,7 +824,7 @@ func (h HandlerV1) UpdateToken(c *gin.Context) {
 		})
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{
-				"error": "Went wrong",
+				"error": h.message(c, "something_went_wrong"),
 			})
 			h.Logger.Error("Failed to update user in update token", l.Error(err))
 			return
@@ -847,7 +841,7 @@ func (h HandlerV1) UpdateToken(c *gin.Context) {
 
 	} else {
 		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "refresh token expired",
+			"error": h.message(c, "refresh_token_expired"),
 		})
 		h.Logger.Error("refresh token expired")
 		return
@@ -30,3 +30,25 @@ func GetIdFromToken(r *http.Request, cfg *config.Config) (string, int) {
 
 	return resp, 200
 }
+
+func GetRoleFromToken(r *http.Request, cfg *config.Config) (string, int) {
+	var softToken string
+	token := r.Header.Get("Authorization")
+
+	if token == "" {
+		return "unauthorized", http.StatusUnauthorized
+	} else if strings.Contains(token, "Bearer") {
+		softToken = strings.TrimPrefix(token, "Bearer ")
+	} else {
+		softToken = token
+	}
+
+	claims, err := tokens.ExtractClaim(softToken, []byte(cfg.Token.SignInKey))
+	if err != nil {
+		return "unauthorized", http.StatusUnauthorized
+	}
+
+	resp := cast.ToString(claims["role"])
+
+	return resp, 200
+}
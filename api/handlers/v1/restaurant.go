@@ -24,6 +24,7 @@ import (
 // @Produce json
 // @Param Restaurant body models.CreateRestaurant true "Restaurant"
 // @Success 200 {object} models.RestaurantModel
+// @Failure 400 {object} models.StandartError
 // @Failure 404 {object} models.StandartError
 // @Failure 500 {object} models.StandartError
 // @Router /v1/restaurant [POST]
@@ -42,16 +43,19 @@ func (h HandlerV1) CreateRestaurant(c *gin.Context) {
 	defer span.End()
 
 	if err := c.ShouldBindJSON(&body); err != nil {
-		c.JSON(404, gin.H{
-			"error": err.Error(),
-		})
+		h.bindError(c, err)
+		return
+	}
+
+	tags, ok := h.checkTags(ctx, c, categoryRestaurant, body.Tags)
+	if !ok {
 		return
 	}
 
 	owner_id, statusCode := GetIdFromToken(c.Request, h.Config)
 	if statusCode != http.StatusOK {
 		c.JSON(statusCode, gin.H{
-			"error": "Can't get",
+			"error": h.message(c, "cant_get_user"),
 		})
 		return
 	}
@@ -100,11 +104,14 @@ func (h HandlerV1) CreateRestaurant(c *gin.Context) {
 	})
 	if err != nil {
 		c.JSON(500, gin.H{
-			"error": err.Error(),
+			"error": h.errorMessage(c, err),
 		})
 		return
 	}
 
+	h.indexEstablishment(ctx, indexedRestaurant(response))
+	h.saveTags(ctx, categoryRestaurant, response.RestaurantId, tags)
+
 	var respImages []*models.ImageModel
 
 	for _, respImage := range response.Images {
@@ -143,6 +150,7 @@ func (h HandlerV1) CreateRestaurant(c *gin.Context) {
 		},
 		CreatedAt: response.CreatedAt,
 		UpdatedAt: response.UpdatedAt,
+		Tags:      tags,
 	}
 
 	c.JSON(http.StatusCreated, respModel)
@@ -156,6 +164,7 @@ func (h HandlerV1) CreateRestaurant(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param restaurant_id query string true "restaurant_id"
+// @Param lang query string false "lang, uz, ru or en, takes the place of Accept-Language"
 // @Success 200 {object} models.RestaurantModel
 // @Failure 404 {object} models.StandartError
 // @Failure 500 {object} models.StandartError
@@ -183,7 +192,7 @@ func (h HandlerV1) GetRestaurant(c *gin.Context) {
 	})
 	if err != nil {
 		c.JSON(500, gin.H{
-			"error": err.Error(),
+			"error": h.errorMessage(c, err),
 		})
 		h.Logger.Error(err.Error())
 		return
@@ -228,6 +237,11 @@ func (h HandlerV1) GetRestaurant(c *gin.Context) {
 		CreatedAt: response.Restaurant.CreatedAt,
 		UpdatedAt: response.Restaurant.UpdatedAt,
 	}
+	respModel.Tags = h.establishmentTags(ctx, categoryRestaurant, []string{respModel.RestaurantId})[respModel.RestaurantId]
+	translate(h.translations(ctx, c, categoryRestaurant, []string{respModel.RestaurantId})[respModel.RestaurantId], &respModel.RestaurantName, &respModel.Description)
+	respModel.OpeningStatus = h.openingStatuses(ctx, categoryRestaurant, map[string]string{
+		respModel.RestaurantId: respModel.OpeningHours,
+	})[respModel.RestaurantId]
 
 	c.JSON(200, respModel)
 }
@@ -235,12 +249,25 @@ func (h HandlerV1) GetRestaurant(c *gin.Context) {
 // LIST RESTAURANTS BY PAGE AND LIMIT
 // @Summary LIST RESTAURANTS BY PAGE AND LIMIT
 // @Security BearerAuth
-// @Description Api for listing restaurants by page and limit
+// @Description Api for listing restaurants by page and limit. city takes a comma separated list, tags a comma separated list of tag slugs all of which must be there, min_rating is 0 to 5 and price_min and price_max bound the base price in the smallest currency unit. sort is rating, distance, popularity, created_at or name with an optional - for descending order, distance is measured from near ("lat,lng"). facets counts the matches per city, per rating and the price range, each ignoring its own filter, and per tag. A sorted or filtered list also returns next_cursor and prev_cursor
 // @Tags RESTAURANT
 // @Accept json
 // @Produce json
-// @Param request query models.Pagination true "request"
+// @Produce application/geo+json
+// @Param request query models.EstablishmentPagination true "request"
+// @Param city query string false "city"
+// @Param tags query string false "tags"
+// @Param min_rating query number false "min_rating"
+// @Param price_min query int false "price_min"
+// @Param price_max query int false "price_max"
+// @Param open_now query bool false "open_now, only those open now"
+// @Param open_at query string false "open_at, only those open at this Asia/Tashkent time, 2006-01-02T15:04"
+// @Param sort query string false "sort" default(-rating)
+// @Param near query string false "near"
+// @Param zoom query int false "zoom, clusters the GeoJSON points of all the matches rather than of the page"
+// @Param lang query string false "lang, uz, ru or en, takes the place of Accept-Language"
 // @Success 200 {object} models.ListRestaurantsModel
+// @Failure 400 {object} models.StandartError
 // @Failure 404 {object} models.StandartError
 // @Failure 500 {object} models.StandartError
 // @Router /v1/restaurant/list [GET]
@@ -260,32 +287,57 @@ func (h HandlerV1) ListRestaurants(c *gin.Context) {
 	)
 	defer span.End()
 
-	queryParams := c.Request.URL.Query()
-	params, errStr := utils.ParseQueryParam(queryParams)
-	if errStr != nil {
-		c.JSON(http.StatusBadRequest, models.Error{
-			Message: "Incorrect Date",
-		})
+	list := h.listEstablishments(ctx, c, categoryRestaurant)
+	if list == nil {
 		return
 	}
 
-	offset := (params.Page - 1) * params.Limit
-
-	response, err := h.Service.EstablishmentService().ListRestaurants(ctx, &pbe.ListRestaurantsRequest{
-		Offset: int64(offset),
-		Limit:  int64(params.Limit),
-	})
-	if err != nil {
-		c.JSON(500, gin.H{
-			"error": err.Error(),
+	var (
+		restaurants []*pbe.Restaurant
+		count       uint64
+	)
+	if list.filtered {
+		listed, err := h.listedRestaurants(ctx, list.page.IDs)
+		if err != nil {
+			c.JSON(500, gin.H{
+				"error": h.errorMessage(c, err),
+			})
+			h.Logger.Error(err.Error())
+			return
+		}
+		restaurants, count = listed, uint64(list.page.Total)
+	} else {
+		response, err := h.Service.EstablishmentService().ListRestaurants(ctx, &pbe.ListRestaurantsRequest{
+			Offset: int64(list.offset),
+			Limit:  int64(list.limit),
 		})
-		h.Logger.Error(err.Error())
-		return
+		if err != nil {
+			c.JSON(500, gin.H{
+				"error": h.errorMessage(c, err),
+			})
+			h.Logger.Error(err.Error())
+			return
+		}
+
+		restaurants, count = response.Restaurants, response.Overall
+	}
+
+	plain := make(map[string]string, len(restaurants))
+	for _, restaurant := range restaurants {
+		plain[restaurant.RestaurantId] = restaurant.OpeningHours
+	}
+	statuses := h.openingStatuses(ctx, categoryRestaurant, plain)
+
+	ids := make([]string, 0, len(restaurants))
+	for _, restaurant := range restaurants {
+		ids = append(ids, restaurant.RestaurantId)
 	}
+	tags := h.establishmentTags(ctx, categoryRestaurant, ids)
+	translations := h.translations(ctx, c, categoryRestaurant, ids)
 
 	var respRestaurants []*models.RestaurantModel
 
-	for _, respRestaurant := range response.Restaurants {
+	for _, respRestaurant := range restaurants {
 
 		var respImages []*models.ImageModel
 
@@ -323,16 +375,28 @@ func (h HandlerV1) ListRestaurants(c *gin.Context) {
 				CreatedAt:       respRestaurant.Location.CreatedAt,
 				UpdatedAt:       respRestaurant.Location.UpdatedAt,
 			},
-			CreatedAt: respRestaurant.CreatedAt,
-			UpdatedAt: respRestaurant.UpdatedAt,
+			CreatedAt:     respRestaurant.CreatedAt,
+			UpdatedAt:     respRestaurant.UpdatedAt,
+			Tags:          tags[respRestaurant.RestaurantId],
+			OpeningStatus: statuses[respRestaurant.RestaurantId],
 		}
+		translate(translations[respRestaurant.RestaurantId], &restaurant.RestaurantName, &restaurant.Description)
 
 		respRestaurants = append(respRestaurants, &restaurant)
 	}
 
+	next, prev := list.cursors()
 	listModel := models.ListRestaurantsModel{
 		Restaurants: respRestaurants,
-		Count:       response.Overall,
+		Count:       count,
+		Facets:      list.facets(),
+		NextCursor:  next,
+		PrevCursor:  prev,
+	}
+
+	if wantsGeoJSON(c) {
+		h.writeGeoJSONPage(c, restaurantFeatures(respRestaurants), next, prev)
+		return
 	}
 
 	c.JSON(200, listModel)
@@ -348,6 +412,7 @@ func (h HandlerV1) ListRestaurants(c *gin.Context) {
 // @Param restaurant_id query string true "restaurant_id"
 // @Param UpdatingRestaurant body models.UpdateRestaurant true "UpdatingRestaurant"
 // @Success 200 {object} models.RestaurantModel
+// @Failure 400 {object} models.StandartError
 // @Failure 404 {object} models.StandartError
 // @Failure 500 {object} models.StandartError
 // @Router /v1/restaurant [PUT]
@@ -369,12 +434,18 @@ func (h HandlerV1) UpdateRestaurant(c *gin.Context) {
 	defer span.End()
 
 	if err := c.ShouldBindJSON(&body); err != nil {
-		c.JSON(404, gin.H{
-			"error": err.Error(),
-		})
+		h.bindError(c, err)
 		return
 	}
 
+	var tags []string
+	if body.Tags != nil {
+		var ok bool
+		if tags, ok = h.checkTags(ctx, c, categoryRestaurant, body.Tags); !ok {
+			return
+		}
+	}
+
 	restaurant_id := c.Query("restaurant_id")
 
 	response, err := h.Service.EstablishmentService().UpdateRestaurant(ctx, &pbe.UpdateRestaurantRequest{
@@ -399,12 +470,17 @@ func (h HandlerV1) UpdateRestaurant(c *gin.Context) {
 	})
 	if err != nil {
 		c.JSON(500, gin.H{
-			"error": err.Error(),
+			"error": h.errorMessage(c, err),
 		})
 		h.Logger.Error(err.Error())
 		return
 	}
 
+	h.indexEstablishment(ctx, indexedRestaurant(response.Restaurant))
+	if body.Tags != nil {
+		h.saveTags(ctx, categoryRestaurant, response.Restaurant.RestaurantId, tags)
+	}
+
 	var respImages []*models.ImageModel
 
 	for _, respImage := range response.Restaurant.Images {
@@ -444,6 +520,7 @@ func (h HandlerV1) UpdateRestaurant(c *gin.Context) {
 		CreatedAt: response.Restaurant.CreatedAt,
 		UpdatedAt: response.Restaurant.UpdatedAt,
 	}
+	respModel.Tags = h.establishmentTags(ctx, categoryRestaurant, []string{respModel.RestaurantId})[respModel.RestaurantId]
 
 	c.JSON(200, respModel)
 }
@@ -480,7 +557,7 @@ func (h HandlerV1) DeleteRestaurant(c *gin.Context) {
 	})
 	if err != nil {
 		c.JSON(500, gin.H{
-			"error": err.Error(),
+			"error": h.errorMessage(c, err),
 		})
 		h.Logger.Error(err.Error())
 		return
@@ -488,12 +565,14 @@ func (h HandlerV1) DeleteRestaurant(c *gin.Context) {
 
 	if !response.Success {
 		c.JSON(404, gin.H{
-			"error": "not deleted",
+			"error": h.message(c, "not_deleted"),
 		})
 		h.Logger.Error("not deleted")
 		return
 	}
 
+	h.unindexEstablishment(ctx, categoryRestaurant, restaurant_id)
+
 	c.JSON(200, gin.H{
 		"message": "successfuly deleted",
 	})
@@ -506,8 +585,11 @@ func (h HandlerV1) DeleteRestaurant(c *gin.Context) {
 // @Tags RESTAURANT
 // @Accept json
 // @Produce json
+// @Produce application/geo+json
 // @Param request query models.Pagination true "request"
 // @Param request query models.FieldValuesByLocation true "request"
+// @Param zoom query int false "zoom, clusters the GeoJSON points of this page only, /list clusters all its matches"
+// @Param lang query string false "lang, uz, ru or en, takes the place of Accept-Language"
 // @Success 200 {object} models.ListRestaurantsModel
 // @Failure 404 {object} models.StandartError
 // @Failure 500 {object} models.StandartError
@@ -529,7 +611,7 @@ func (h HandlerV1) ListRestaurantsByLocation(c *gin.Context) {
 	params, errStr := utils.ParseQueryParam(queryParams)
 	if errStr != nil {
 		c.JSON(http.StatusBadRequest, models.Error{
-			Message: "Incorrect Date",
+			Message: h.message(c, "incorrect_date"),
 		})
 		return
 	}
@@ -549,12 +631,25 @@ func (h HandlerV1) ListRestaurantsByLocation(c *gin.Context) {
 	})
 	if err != nil {
 		c.JSON(500, gin.H{
-			"error": err.Error(),
+			"error": h.errorMessage(c, err),
 		})
 		h.Logger.Error(err.Error())
 		return
 	}
 
+	plain := make(map[string]string, len(response.Restaurants))
+	for _, restaurant := range response.Restaurants {
+		plain[restaurant.RestaurantId] = restaurant.OpeningHours
+	}
+	statuses := h.openingStatuses(ctx, categoryRestaurant, plain)
+
+	ids := make([]string, 0, len(response.Restaurants))
+	for _, restaurant := range response.Restaurants {
+		ids = append(ids, restaurant.RestaurantId)
+	}
+	tags := h.establishmentTags(ctx, categoryRestaurant, ids)
+	translations := h.translations(ctx, c, categoryRestaurant, ids)
+
 	var respRestaurants []*models.RestaurantModel
 
 	for _, respRestaurant := range response.Restaurants {
@@ -595,9 +690,12 @@ func (h HandlerV1) ListRestaurantsByLocation(c *gin.Context) {
 				CreatedAt:       respRestaurant.Location.CreatedAt,
 				UpdatedAt:       respRestaurant.Location.UpdatedAt,
 			},
-			CreatedAt: respRestaurant.CreatedAt,
-			UpdatedAt: respRestaurant.UpdatedAt,
+			CreatedAt:     respRestaurant.CreatedAt,
+			UpdatedAt:     respRestaurant.UpdatedAt,
+			Tags:          tags[respRestaurant.RestaurantId],
+			OpeningStatus: statuses[respRestaurant.RestaurantId],
 		}
+		translate(translations[respRestaurant.RestaurantId], &restaurant.RestaurantName, &restaurant.Description)
 
 		respRestaurants = append(respRestaurants, &restaurant)
 	}
@@ -607,6 +705,11 @@ func (h HandlerV1) ListRestaurantsByLocation(c *gin.Context) {
 		Count:       uint64(response.Count),
 	}
 
+	if wantsGeoJSON(c) {
+		h.writeGeoJSON(c, restaurantFeatures(respRestaurants))
+		return
+	}
+
 	c.JSON(200, respModel)
 }
 
@@ -617,7 +720,10 @@ func (h HandlerV1) ListRestaurantsByLocation(c *gin.Context) {
 // @Tags RESTAURANT
 // @Accept json
 // @Produce json
+// @Produce application/geo+json
 // @Param request query models.FindByName true "request"
+// @Param zoom query int false "zoom, clusters the GeoJSON points of this page only, /list clusters all its matches"
+// @Param lang query string false "lang, uz, ru or en, takes the place of Accept-Language"
 // @Success 200 {object} models.ListRestaurantsModel
 // @Failure 404 {object} models.StandartError
 // @Failure 500 {object} models.StandartError
@@ -645,12 +751,25 @@ func (h HandlerV1) FindRestaurantsByName(c *gin.Context) {
 	})
 	if err != nil {
 		c.JSON(500, gin.H{
-			"error": err.Error(),
+			"error": h.errorMessage(c, err),
 		})
 		h.Logger.Error(err.Error())
 		return
 	}
 
+	plain := make(map[string]string, len(response.Restaurants))
+	for _, restaurant := range response.Restaurants {
+		plain[restaurant.RestaurantId] = restaurant.OpeningHours
+	}
+	statuses := h.openingStatuses(ctx, categoryRestaurant, plain)
+
+	ids := make([]string, 0, len(response.Restaurants))
+	for _, restaurant := range response.Restaurants {
+		ids = append(ids, restaurant.RestaurantId)
+	}
+	tags := h.establishmentTags(ctx, categoryRestaurant, ids)
+	translations := h.translations(ctx, c, categoryRestaurant, ids)
+
 	var respRestaurants []*models.RestaurantModel
 
 	for _, respRestaurant := range response.Restaurants {
@@ -691,9 +810,12 @@ func (h HandlerV1) FindRestaurantsByName(c *gin.Context) {
 				CreatedAt:       respRestaurant.Location.CreatedAt,
 				UpdatedAt:       respRestaurant.Location.UpdatedAt,
 			},
-			CreatedAt: respRestaurant.CreatedAt,
-			UpdatedAt: respRestaurant.UpdatedAt,
+			CreatedAt:     respRestaurant.CreatedAt,
+			UpdatedAt:     respRestaurant.UpdatedAt,
+			Tags:          tags[respRestaurant.RestaurantId],
+			OpeningStatus: statuses[respRestaurant.RestaurantId],
 		}
+		translate(translations[respRestaurant.RestaurantId], &restaurant.RestaurantName, &restaurant.Description)
 
 		respRestaurants = append(respRestaurants, &restaurant)
 	}
@@ -703,5 +825,10 @@ func (h HandlerV1) FindRestaurantsByName(c *gin.Context) {
 		Count:       response.Count,
 	}
 
+	if wantsGeoJSON(c) {
+		h.writeGeoJSON(c, restaurantFeatures(respRestaurants))
+		return
+	}
+
 	c.JSON(200, listModel)
 }
@@ -0,0 +1,305 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+
+	"Booking/api-service-booking/api/models"
+	pbe "Booking/api-service-booking/genproto/establishment-proto"
+	"Booking/api-service-booking/internal/entity"
+	errorspkg "Booking/api-service-booking/internal/errors"
+	"Booking/api-service-booking/internal/pkg/booktime"
+	"Booking/api-service-booking/internal/pkg/i18n"
+	l "Booking/api-service-booking/internal/pkg/logger"
+	"Booking/api-service-booking/internal/pkg/openinghours"
+	"Booking/api-service-booking/internal/pkg/otlp"
+)
+
+// CREATE RESTAURANT TABLE
+// @Summary CREATE RESTAURANT TABLE
+// @Security BearerAuth
+// @Description Api for adding a table to a restaurant
+// @Tags RESTAURANT
+// @Accept json
+// @Produce json
+// @Param Table body models.CreateRestaurantTable true "Table"
+// @Success 201 {object} models.RestaurantTableRes
+// @Failure 400 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/restaurant/tables [POST]
+func (h *HandlerV1) CreateRestaurantTable(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "CreateRestaurantTable")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	var body models.CreateRestaurantTable
+	if err := c.ShouldBindJSON(&body); err != nil || body.Capacity < 1 || body.Name == "" {
+		h.bindError(c, err)
+		return
+	}
+
+	_, err := h.Service.EstablishmentService().GetRestaurant(ctx, &pbe.GetRestaurantRequest{
+		RestaurantId: body.RestaurantId,
+	})
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": h.message(c, "restaurant_not_found"),
+		})
+		h.Logger.Error("failed to get restaurant", l.Error(err))
+		return
+	}
+
+	table := entity.RestaurantTable{
+		RestaurantID: body.RestaurantId,
+		Name:         body.Name,
+		Capacity:     body.Capacity,
+	}
+	if err := h.RestaurantTable.Create(ctx, &table); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to create restaurant table", l.Error(err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, restaurantTableRes(&table))
+}
+
+// LIST RESTAURANT TABLES
+// @Summary LIST RESTAURANT TABLES
+// @Security BearerAuth
+// @Description Api for listing tables of a restaurant
+// @Tags RESTAURANT
+// @Accept json
+// @Produce json
+// @Param restaurant_id query string true "restaurant_id"
+// @Success 200 {object} models.ListRestaurantTablesRes
+// @Failure 500 {object} models.StandartError
+// @Router /v1/restaurant/tables [GET]
+func (h *HandlerV1) ListRestaurantTables(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "ListRestaurantTables")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	tables, err := h.RestaurantTable.List(ctx, c.Query("restaurant_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to list restaurant tables", l.Error(err))
+		return
+	}
+
+	response := models.ListRestaurantTablesRes{
+		Tables: []*models.RestaurantTableRes{},
+	}
+	for _, table := range tables {
+		response.Tables = append(response.Tables, restaurantTableRes(table))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// DELETE RESTAURANT TABLE
+// @Summary DELETE RESTAURANT TABLE
+// @Security BearerAuth
+// @Description Api for removing a table from a restaurant
+// @Tags RESTAURANT
+// @Accept json
+// @Produce json
+// @Param id path string true "ID"
+// @Success 200 {object} models.StandartError
+// @Failure 404 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/restaurant/tables/{id} [DELETE]
+func (h *HandlerV1) DeleteRestaurantTable(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "DeleteRestaurantTable")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	err := h.RestaurantTable.Delete(ctx, c.Param("id"))
+	if errors.Is(err, errorspkg.ErrorNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": h.message(c, "table_not_found"),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to delete restaurant table", l.Error(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, "successfully deleted...")
+}
+
+// LIST FREE RESTAURANT SLOTS
+// @Summary LIST FREE RESTAURANT SLOTS
+// @Description Api for listing free seating times for a date and party size
+// @Tags RESTAURANT
+// @Accept json
+// @Produce json
+// @Param request query models.SlotsReq true "request"
+// @Success 200 {object} models.ListTimeSlotsRes
+// @Failure 400 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/restaurant/slots [GET]
+func (h *HandlerV1) ListRestaurantSlots(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "ListRestaurantSlots")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	var body models.SlotsReq
+	if err := c.ShouldBindQuery(&body); err != nil || body.PartySize < 1 {
+		h.bindError(c, err)
+		return
+	}
+
+	date, dateOnly, err := booktime.Parse(body.Date)
+	if err != nil || !dateOnly {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": h.message(c, "field_format", "date", "2006-01-02"),
+		})
+		return
+	}
+
+	schedule, status, err := h.restaurantSchedule(ctx, body.RestaurantId)
+	if err != nil {
+		c.JSON(status, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	}
+	if schedule == nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error": h.message(c, "no_valid_opening_hours"),
+		})
+		return
+	}
+
+	slots, err := h.RestaurantTable.FreeSlots(ctx, body.RestaurantId, schedule, date, body.PartySize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to list restaurant slots", l.Error(err))
+		return
+	}
+
+	response := models.ListTimeSlotsRes{
+		Slots: []*models.TimeSlotRes{},
+	}
+	for _, slot := range slots {
+		response.Slots = append(response.Slots, &models.TimeSlotRes{
+			StartsAt:   slot.StartsAt.Format(time.RFC3339),
+			EndsAt:     slot.EndsAt.Format(time.RFC3339),
+			FreeTables: slot.FreeTables,
+		})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// restaurantSchedule loads the opening schedule of a restaurant, falling
+// back to its opening_hours like openingSchedules, nil if it has neither.
+// The returned status goes to the client together with the error.
+func (h *HandlerV1) restaurantSchedule(ctx context.Context, restaurantID string) (*openinghours.Schedule, int, error) {
+	restaurant, err := h.Service.EstablishmentService().GetRestaurant(ctx, &pbe.GetRestaurantRequest{
+		RestaurantId: restaurantID,
+	})
+	if err != nil {
+		h.Logger.Error("failed to get restaurant", l.Error(err))
+		return nil, http.StatusNotFound, i18n.NewError("restaurant_not_found")
+	}
+
+	schedules, err := h.openingSchedules(ctx, categoryRestaurant, map[string]string{
+		restaurantID: restaurant.Restaurant.OpeningHours,
+	})
+	if err != nil {
+		h.Logger.Error("failed to get opening hours", l.Error(err))
+		return nil, http.StatusInternalServerError, i18n.NewError("try_again_later")
+	}
+
+	return schedules[restaurantID], http.StatusOK, nil
+}
+
+// reserveTable holds a table for a restaurant booking before it is sent to
+// the booking service, the returned status goes to the client on error.
+// Restaurants without tables or without a schedule or opening hours that
+// can be read keep the plain booking they had before, a date without a time
+// included, so a nil reservation and a nil error are returned for them.
+func (h *HandlerV1) reserveTable(ctx context.Context, bookingID string, body *models.CreateBookingReq) (*entity.TableReservation, int, error) {
+	tables, err := h.RestaurantTable.List(ctx, body.HraId)
+	if err != nil {
+		h.Logger.Error("failed to list restaurant tables", l.Error(err))
+		return nil, http.StatusInternalServerError, i18n.NewError("try_again_later")
+	}
+	schedule, status, err := h.restaurantSchedule(ctx, body.HraId)
+	if err != nil {
+		return nil, status, err
+	}
+	if len(tables) == 0 || schedule == nil {
+		return nil, http.StatusOK, nil
+	}
+
+	start, dateOnly, err := booktime.Parse(body.WillArrive)
+	if err != nil || dateOnly {
+		return nil, http.StatusBadRequest, i18n.NewError("field_needs_time", "will_arrive")
+	}
+	if body.NumberOfPeople < 1 {
+		return nil, http.StatusBadRequest, i18n.NewError("field_positive", "number_of_people")
+	}
+
+	reservation := entity.TableReservation{
+		BookingID:    bookingID,
+		RestaurantID: body.HraId,
+		PartySize:    int(body.NumberOfPeople),
+		StartsAt:     start,
+	}
+
+	err = h.RestaurantTable.Reserve(ctx, schedule, &reservation)
+	var errBadRequest *errorspkg.ErrBadRequest
+	switch {
+	case errors.As(err, &errBadRequest):
+		return nil, http.StatusBadRequest, err
+	case errors.Is(err, errorspkg.ErrorNotAvailable):
+		return nil, http.StatusConflict, i18n.NewError("no_free_table")
+	case err != nil:
+		h.Logger.Error("failed to reserve table", l.Error(err))
+		return nil, http.StatusInternalServerError, i18n.NewError("try_again_later")
+	}
+
+	return &reservation, http.StatusOK, nil
+}
+
+func (h *HandlerV1) releaseTable(ctx context.Context, bookingID string) {
+	if err := h.RestaurantTable.Release(ctx, bookingID); err != nil {
+		h.Logger.Error("failed to release table", l.Error(err))
+	}
+}
+
+func restaurantTableRes(table *entity.RestaurantTable) *models.RestaurantTableRes {
+	return &models.RestaurantTableRes{
+		Id:           table.ID,
+		RestaurantId: table.RestaurantID,
+		Name:         table.Name,
+		Capacity:     table.Capacity,
+		CreatedAt:    table.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:    table.UpdatedAt.Format(time.RFC3339),
+	}
+}
@@ -40,9 +40,7 @@ func (h HandlerV1) CreateReview(c *gin.Context) {
 	defer span.End()
 
 	if err := c.ShouldBindJSON(&body); err != nil {
-		c.JSON(404, gin.H{
-			"error": err.Error(),
-		})
+		h.bindError(c, err)
 		return
 	}
 
@@ -62,7 +60,7 @@ func (h HandlerV1) CreateReview(c *gin.Context) {
 	})
 	if err != nil {
 		c.JSON(500, gin.H{
-			"error": err.Error(),
+			"error": h.errorMessage(c, err),
 		})
 		h.Logger.Error(err.Error())
 		return
@@ -84,11 +82,12 @@ func (h HandlerV1) CreateReview(c *gin.Context) {
 // LIST REVIEWS BY ESTABLISHMENT_ID
 // @Summary LIST REVIEWS BY ESTABLISHMENT_ID
 // @Security BearerAuth
-// @Description Api for listing reviews by establishment_id
+// @Description Api for listing reviews by establishment_id, all of them unless limit or page is given
 // @Tags REVIEW
 // @Accept json
 // @Produce json
 // @Param establishment_id query string true "establishment_id"
+// @Param request query models.Pagination false "request"
 // @Success 200 {object} models.ListReviews
 // @Failure 404 {object} models.StandartError
 // @Failure 500 {object} models.StandartError
@@ -113,15 +112,21 @@ func (h HandlerV1) ListReviews(c *gin.Context) {
 	})
 	if err != nil {
 		c.JSON(500, gin.H{
-			"error": err.Error(),
+			"error": h.errorMessage(c, err),
 		})
 		h.Logger.Error(err.Error())
 		return
 	}
 
+	// the establishment service returns every review, the page is cut here
+	from, to, ok := h.pageRange(c, len(response.Reviews))
+	if !ok {
+		return
+	}
+
 	var reviews []*models.ReviewModel
 
-	for _, respReview := range response.Reviews {
+	for _, respReview := range response.Reviews[from:to] {
 		review := models.ReviewModel{
 			ReviewId:        respReview.ReviewId,
 			EstablishmentId: respReview.EstablishmentId,
@@ -175,7 +180,7 @@ func (h HandlerV1) DeleteReview(c *gin.Context) {
 	})
 	if err != nil {
 		c.JSON(500, gin.H{
-			"error": err.Error(),
+			"error": h.errorMessage(c, err),
 		})
 		h.Logger.Error(err.Error())
 		return
@@ -183,7 +188,7 @@ func (h HandlerV1) DeleteReview(c *gin.Context) {
 
 	if !response.Success {
 		c.JSON(404, gin.H{
-			"error": "not deleted",
+			"error": h.message(c, "not_deleted"),
 		})
 		h.Logger.Error("not deleted")
 	}
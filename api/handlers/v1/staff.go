@@ -0,0 +1,172 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+
+	"Booking/api-service-booking/api/models"
+	pbu "Booking/api-service-booking/genproto/user-proto"
+	"Booking/api-service-booking/internal/entity"
+	errorspkg "Booking/api-service-booking/internal/errors"
+	l "Booking/api-service-booking/internal/pkg/logger"
+	"Booking/api-service-booking/internal/pkg/otlp"
+)
+
+const (
+	roleUser  = "user"
+	roleStaff = "staff"
+)
+
+// ADD STAFF
+// @Summary ADD STAFF
+// @Security BearerAuth
+// @Description Api for letting a user check guests in at an establishment, the user gets the staff role
+// @Tags STAFF
+// @Accept json
+// @Produce json
+// @Param Staff body models.StaffReq true "Staff"
+// @Success 201 {object} models.StaffRes
+// @Failure 400 {object} models.StandartError
+// @Failure 404 {object} models.StandartError
+// @Failure 409 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/staff [POST]
+func (h *HandlerV1) AddStaff(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "AddStaff")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	var body models.StaffReq
+	if err := c.ShouldBindJSON(&body); err != nil || body.UserId == "" || body.EstablishmentId == "" {
+		h.bindError(c, err)
+		return
+	}
+
+	user, err := h.Service.UserService().Get(ctx, &pbu.Filter{
+		Filter: map[string]string{"id": body.UserId},
+	})
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": h.message(c, "user_not_found"),
+		})
+		h.Logger.Error("failed to get user", l.Error(err))
+		return
+	}
+	if user.User.Role != roleUser && user.User.Role != roleStaff {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": h.message(c, "only_users_become_staff"),
+		})
+		return
+	}
+
+	member := entity.StaffMember{
+		UserID:          body.UserId,
+		EstablishmentID: body.EstablishmentId,
+	}
+	err = h.Staff.Add(ctx, &member)
+	if errors.Is(err, errorspkg.ErrorConflict) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": h.message(c, "staff_exists"),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to add staff", l.Error(err))
+		return
+	}
+
+	if err := h.setRole(ctx, user.User, roleStaff); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to give staff role", l.Error(err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, &models.StaffRes{
+		UserId:          member.UserID,
+		EstablishmentId: member.EstablishmentID,
+		CreatedAt:       member.CreatedAt.Format(time.RFC3339),
+	})
+}
+
+// REMOVE STAFF
+// @Summary REMOVE STAFF
+// @Security BearerAuth
+// @Description Api for removing a user from the staff of an establishment, the staff role goes with the last one
+// @Tags STAFF
+// @Accept json
+// @Produce json
+// @Param request query models.StaffReq true "request"
+// @Success 200 {object} models.StandartError
+// @Failure 404 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/staff [DELETE]
+func (h *HandlerV1) RemoveStaff(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "RemoveStaff")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	var body models.StaffReq
+	if err := c.ShouldBindQuery(&body); err != nil {
+		h.bindError(c, err)
+		return
+	}
+
+	err := h.Staff.Remove(ctx, body.UserId, body.EstablishmentId)
+	if errors.Is(err, errorspkg.ErrorNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": h.message(c, "staff_not_found"),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to remove staff", l.Error(err))
+		return
+	}
+
+	members, err := h.Staff.ListByUser(ctx, body.UserId)
+	if err != nil {
+		h.Logger.Error("failed to list staff memberships", l.Error(err))
+	}
+	if err == nil && len(members) == 0 {
+		user, err := h.Service.UserService().Get(ctx, &pbu.Filter{
+			Filter: map[string]string{"id": body.UserId},
+		})
+		if err == nil && user.User.Role == roleStaff {
+			err = h.setRole(ctx, user.User, roleUser)
+		}
+		if err != nil {
+			h.Logger.Error("failed to take staff role", l.Error(err))
+		}
+	}
+
+	c.JSON(http.StatusOK, "successfully removed...")
+}
+
+// setRole saves user with a new role, the new role is in tokens issued
+// after the next login
+func (h *HandlerV1) setRole(ctx context.Context, user *pbu.User, role string) error {
+	if user.Role == role {
+		return nil
+	}
+
+	user.Role = role
+	_, err := h.Service.UserService().Update(ctx, user)
+	return err
+}
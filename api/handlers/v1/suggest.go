@@ -0,0 +1,129 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+
+	"Booking/api-service-booking/api/models"
+	"Booking/api-service-booking/internal/entity"
+	errorspkg "Booking/api-service-booking/internal/errors"
+	l "Booking/api-service-booking/internal/pkg/logger"
+	"Booking/api-service-booking/internal/pkg/otlp"
+	"Booking/api-service-booking/internal/usecase/suggest"
+)
+
+// categoryWeight puts the establishment types above most places, they are
+// what a user starting with "ho" or "re" most likely wants
+const categoryWeight = 2
+
+// GET SUGGESTIONS
+// @Summary GET SUGGESTIONS
+// @Security BearerAuth
+// @Description Api for type-ahead suggestions while typing a search. Returns establishments, cities, establishment types and popular searches starting with q, or with one of their words starting with it, best first. type is hotel, restaurant or attraction with the establishment id, or city, category or query with the text to search for as id. Latin and Cyrillic spellings match each other. Suggestions are refreshed every few minutes
+// @Tags SEARCH
+// @Accept json
+// @Produce json
+// @Param request query models.SuggestReq true "request"
+// @Success 200 {object} models.SuggestRes
+// @Failure 400 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/search/suggest [GET]
+func (h *HandlerV1) GetSuggestions(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "GetSuggestions")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	var body models.SuggestReq
+	if err := c.ShouldBindQuery(&body); err != nil {
+		h.bindError(c, err)
+		return
+	}
+
+	suggestions, err := h.Suggest.Suggest(ctx, body.Q, body.Limit)
+	var errBadRequest *errorspkg.ErrBadRequest
+	if errors.As(err, &errBadRequest) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to get suggestions", l.Error(err))
+		return
+	}
+
+	response := models.SuggestRes{
+		Suggestions: []*models.SuggestionRes{},
+	}
+	for _, suggestion := range suggestions {
+		response.Suggestions = append(response.Suggestions, &models.SuggestionRes{
+			Type: suggestion.Type,
+			Id:   suggestion.ID,
+			Text: suggestion.Text,
+		})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// RegisterSuggestSource has the suggestion index built from the
+// establishment service
+func (h *HandlerV1) RegisterSuggestSource(s suggest.Suggest) {
+	s.SetSource(h.listSuggestions)
+}
+
+// listSuggestions lists every establishment, the cities they are in and
+// the establishment types. Better rated places and cities with more
+// places weigh more.
+func (h *HandlerV1) listSuggestions(ctx context.Context) ([]*entity.Suggestion, error) {
+	var (
+		suggestions []*entity.Suggestion
+		cities      = make(map[string]int)
+	)
+	for _, category := range []string{categoryHotel, categoryRestaurant, categoryAttraction} {
+		_, err := h.reindex(ctx, category, func(e *indexedEstablishment) error {
+			if e.name != "" {
+				suggestions = append(suggestions, &entity.Suggestion{
+					Type:   e.category,
+					ID:     e.id,
+					Text:   e.name,
+					Weight: 1 + float64(e.rating)/5,
+				})
+			}
+			if e.location != nil && e.location.City != "" {
+				cities[e.location.City]++
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		suggestions = append(suggestions, &entity.Suggestion{
+			Type:   entity.SuggestionTypeCategory,
+			ID:     category,
+			Text:   category,
+			Weight: categoryWeight,
+		})
+	}
+
+	for city, count := range cities {
+		suggestions = append(suggestions, &entity.Suggestion{
+			Type:   entity.SuggestionTypeCity,
+			ID:     city,
+			Text:   city,
+			Weight: 1 + math.Log10(float64(count)),
+		})
+	}
+	return suggestions, nil
+}
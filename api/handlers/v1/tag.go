@@ -0,0 +1,306 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+
+	"Booking/api-service-booking/api/models"
+	"Booking/api-service-booking/internal/entity"
+	errorspkg "Booking/api-service-booking/internal/errors"
+	l "Booking/api-service-booking/internal/pkg/logger"
+	"Booking/api-service-booking/internal/pkg/otlp"
+)
+
+// CREATE TAG
+// @Summary CREATE TAG
+// @Security BearerAuth
+// @Description Api for adding an amenity or cuisine to the taxonomy establishments are tagged from. categories limits it to hotels, restaurants or attractions, empty for all of them
+// @Tags TAG
+// @Accept json
+// @Produce json
+// @Param Tag body models.TagReq true "Tag"
+// @Success 201 {object} models.TagRes
+// @Failure 400 {object} models.StandartError
+// @Failure 409 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/tags [POST]
+func (h *HandlerV1) CreateTag(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "CreateTag")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	var body models.TagReq
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.bindError(c, err)
+		return
+	}
+
+	tag := tagFromReq(&body)
+	err := h.Tag.Create(ctx, tag)
+	var errBadRequest *errorspkg.ErrBadRequest
+	switch {
+	case errors.As(err, &errBadRequest):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	case errors.Is(err, errorspkg.ErrorConflict):
+		c.JSON(http.StatusConflict, gin.H{
+			"error": h.message(c, "tag_slug_taken"),
+		})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to create tag", l.Error(err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, tagRes(tag))
+}
+
+// LIST TAGS
+// @Summary LIST TAGS
+// @Description Api for listing the taxonomy of tags, optionally of one kind or those applying to one category
+// @Tags TAG
+// @Accept json
+// @Produce json
+// @Param request query models.ListTagsReq false "request"
+// @Success 200 {object} models.ListTagsRes
+// @Failure 500 {object} models.StandartError
+// @Router /v1/tags [GET]
+func (h *HandlerV1) ListTags(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "ListTags")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	tags, err := h.Tag.List(ctx, c.Query("kind"), c.Query("category"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to list tags", l.Error(err))
+		return
+	}
+
+	response := models.ListTagsRes{
+		Tags: []*models.TagRes{},
+	}
+	for _, tag := range tags {
+		response.Tags = append(response.Tags, tagRes(tag))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// UPDATE TAG
+// @Summary UPDATE TAG
+// @Security BearerAuth
+// @Description Api for renaming a tag or changing its kind or categories, found by slug
+// @Tags TAG
+// @Accept json
+// @Produce json
+// @Param Tag body models.TagReq true "Tag"
+// @Success 200 {object} models.TagRes
+// @Failure 400 {object} models.StandartError
+// @Failure 404 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/tags [PUT]
+func (h *HandlerV1) UpdateTag(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "UpdateTag")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	var body models.TagReq
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.bindError(c, err)
+		return
+	}
+
+	tag := tagFromReq(&body)
+	err := h.Tag.Update(ctx, tag)
+	var errBadRequest *errorspkg.ErrBadRequest
+	switch {
+	case errors.As(err, &errBadRequest):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	case errors.Is(err, errorspkg.ErrorNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": h.message(c, "tag_not_found"),
+		})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to update tag", l.Error(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, tagRes(tag))
+}
+
+// DELETE TAG
+// @Summary DELETE TAG
+// @Security BearerAuth
+// @Description Api for removing a tag from the taxonomy, it is taken off every establishment that has it
+// @Tags TAG
+// @Accept json
+// @Produce json
+// @Param slug path string true "slug"
+// @Success 200 {object} string
+// @Failure 404 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/tags/{slug} [DELETE]
+func (h *HandlerV1) DeleteTag(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "DeleteTag")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	err := h.Tag.Delete(ctx, c.Param("slug"))
+	if errors.Is(err, errorspkg.ErrorNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": h.message(c, "tag_not_found"),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to delete tag", l.Error(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, "successfully deleted...")
+}
+
+// SET ESTABLISHMENT TAGS
+// @Summary SET ESTABLISHMENT TAGS
+// @Security BearerAuth
+// @Description Api for the owner of an establishment to replace its tags with tags from the taxonomy that apply to its category
+// @Tags TAG
+// @Accept json
+// @Produce json
+// @Param Tags body models.EstablishmentTagsReq true "Tags"
+// @Success 200 {object} models.EstablishmentTagsRes
+// @Failure 400 {object} models.StandartError
+// @Failure 403 {object} models.StandartError
+// @Failure 404 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/establishments/tags [PUT]
+func (h *HandlerV1) SetEstablishmentTags(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "SetEstablishmentTags")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	var body models.EstablishmentTagsReq
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.bindError(c, err)
+		return
+	}
+
+	if statusCode, err := h.checkManager(ctx, c.Request, body.Category, body.HraId); err != nil {
+		c.JSON(statusCode, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	}
+
+	tags, ok := h.checkTags(ctx, c, body.Category, body.Tags)
+	if !ok {
+		return
+	}
+	if err := h.Tag.SetEstablishmentTags(ctx, body.Category, body.HraId, tags); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to set establishment tags", l.Error(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, &models.EstablishmentTagsRes{
+		Category: body.Category,
+		HraId:    body.HraId,
+		Tags:     tags,
+	})
+}
+
+func tagFromReq(body *models.TagReq) *entity.Tag {
+	return &entity.Tag{
+		Slug:       body.Slug,
+		Kind:       body.Kind,
+		Name:       body.Name,
+		Categories: body.Categories,
+	}
+}
+
+func tagRes(tag *entity.Tag) *models.TagRes {
+	return &models.TagRes{
+		Slug:       tag.Slug,
+		Kind:       tag.Kind,
+		Name:       tag.Name,
+		Categories: tag.Categories,
+		CreatedAt:  tag.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:  tag.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// checkTags checks the tags of a create or update request against the
+// taxonomy. It writes the error response and returns false if they do not
+// pass.
+func (h *HandlerV1) checkTags(ctx context.Context, c *gin.Context, category string, tags []string) ([]string, bool) {
+	checked, err := h.Tag.Check(ctx, category, tags)
+	var errBadRequest *errorspkg.ErrBadRequest
+	if errors.As(err, &errBadRequest) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return nil, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to check tags", l.Error(err))
+		return nil, false
+	}
+	return checked, true
+}
+
+// saveTags puts checked tags on a created or updated establishment. The
+// establishment is already written, so a failure is only logged.
+func (h *HandlerV1) saveTags(ctx context.Context, category, establishmentID string, tags []string) {
+	if err := h.Tag.SetEstablishmentTags(ctx, category, establishmentID, tags); err != nil {
+		h.Logger.Error("failed to set establishment tags", l.Error(err))
+	}
+}
+
+// establishmentTags returns the tags of ids by id, nil if they can not be
+// read, so responses go out without them
+func (h *HandlerV1) establishmentTags(ctx context.Context, category string, ids []string) map[string][]string {
+	tags, err := h.Tag.EstablishmentTags(ctx, category, ids)
+	if err != nil {
+		h.Logger.Error("failed to list establishment tags", l.Error(err))
+		return nil
+	}
+	return tags
+}
@@ -0,0 +1,209 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+
+	"Booking/api-service-booking/api/models"
+	"Booking/api-service-booking/internal/entity"
+	errorspkg "Booking/api-service-booking/internal/errors"
+	"Booking/api-service-booking/internal/pkg/locale"
+	l "Booking/api-service-booking/internal/pkg/logger"
+	"Booking/api-service-booking/internal/pkg/otlp"
+)
+
+// SET TRANSLATION
+// @Summary SET TRANSLATION
+// @Security BearerAuth
+// @Description Api for the owner of an establishment to write its name and description in another language. Readers get it when it is the best match for their Accept-Language header or lang query parameter.
+// @Tags TRANSLATION
+// @Accept json
+// @Produce json
+// @Param Translation body models.TranslationReq true "Translation"
+// @Success 200 {object} models.TranslationRes
+// @Failure 400 {object} models.StandartError
+// @Failure 403 {object} models.StandartError
+// @Failure 404 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/establishments/translations [PUT]
+func (h *HandlerV1) SetTranslation(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "SetTranslation")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	var body models.TranslationReq
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.bindError(c, err)
+		return
+	}
+
+	if statusCode, err := h.checkManager(ctx, c.Request, body.Category, body.HraId); err != nil {
+		c.JSON(statusCode, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	}
+
+	m := entity.Translation{
+		Category:        body.Category,
+		EstablishmentID: body.HraId,
+		Locale:          body.Locale,
+		Name:            body.Name,
+		Description:     body.Description,
+	}
+	err := h.Translation.Save(ctx, &m)
+	var errBadRequest *errorspkg.ErrBadRequest
+	if errors.As(err, &errBadRequest) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to save translation", l.Error(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, translationRes(&m))
+}
+
+// LIST TRANSLATIONS
+// @Summary LIST TRANSLATIONS
+// @Description Api for listing every translation of an establishment along with the language it is written in
+// @Tags TRANSLATION
+// @Accept json
+// @Produce json
+// @Param category query string true "category"
+// @Param hra_id query string true "hra_id"
+// @Success 200 {object} models.ListTranslationsRes
+// @Failure 500 {object} models.StandartError
+// @Router /v1/establishments/translations [GET]
+func (h *HandlerV1) ListTranslations(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "ListTranslations")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	translations, err := h.Translation.List(ctx, c.Query("category"), c.Query("hra_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to list translations", l.Error(err))
+		return
+	}
+
+	response := models.ListTranslationsRes{
+		DefaultLocale: h.Translation.Default(),
+		Translations:  []*models.TranslationRes{},
+	}
+	for _, translation := range translations {
+		response.Translations = append(response.Translations, translationRes(translation))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// DELETE TRANSLATION
+// @Summary DELETE TRANSLATION
+// @Security BearerAuth
+// @Description Api for removing one translation of an establishment, readers of that language get the default one
+// @Tags TRANSLATION
+// @Accept json
+// @Produce json
+// @Param category query string true "category"
+// @Param hra_id query string true "hra_id"
+// @Param locale query string true "locale"
+// @Success 200 {object} string
+// @Failure 403 {object} models.StandartError
+// @Failure 404 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/establishments/translations [DELETE]
+func (h *HandlerV1) DeleteTranslation(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "DeleteTranslation")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	category, establishmentID := c.Query("category"), c.Query("hra_id")
+	if statusCode, err := h.checkManager(ctx, c.Request, category, establishmentID); err != nil {
+		c.JSON(statusCode, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	}
+
+	err := h.Translation.Delete(ctx, category, establishmentID, c.Query("locale"))
+	if errors.Is(err, errorspkg.ErrorNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": h.message(c, "no_translation"),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to delete translation", l.Error(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, "successfully deleted...")
+}
+
+func translationRes(m *entity.Translation) *models.TranslationRes {
+	return &models.TranslationRes{
+		Category:    m.Category,
+		HraId:       m.EstablishmentID,
+		Locale:      m.Locale,
+		Name:        m.Name,
+		Description: m.Description,
+		UpdatedAt:   m.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// locale picks the language of the response from the lang query parameter
+// or the Accept-Language header and tells the client which one it got
+func (h *HandlerV1) locale(c *gin.Context) string {
+	lang := locale.Negotiate(c.Query("lang"), c.GetHeader("Accept-Language"), h.Translation.Default())
+	c.Header("Content-Language", lang)
+	return lang
+}
+
+// translations returns the translations of ids into the language of the
+// request by id. It is empty for the default language and nil if they can
+// not be read, so the establishments go out as written.
+func (h *HandlerV1) translations(ctx context.Context, c *gin.Context, category string, ids []string) map[string]*entity.Translation {
+	translations, err := h.Translation.Pick(ctx, category, ids, h.locale(c))
+	if err != nil {
+		h.Logger.Error("failed to get translations", l.Error(err))
+		return nil
+	}
+	return translations
+}
+
+// translate puts a translation over the name and description of an
+// establishment, keeping whichever of them it leaves empty
+func translate(translation *entity.Translation, name, description *string) {
+	if translation == nil {
+		return
+	}
+	if translation.Name != "" {
+		*name = translation.Name
+	}
+	if translation.Description != "" {
+		*description = translation.Description
+	}
+}
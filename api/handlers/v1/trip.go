@@ -0,0 +1,577 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+
+	"Booking/api-service-booking/api/models"
+	pbb "Booking/api-service-booking/genproto/booking-proto"
+	"Booking/api-service-booking/internal/entity"
+	errorspkg "Booking/api-service-booking/internal/errors"
+	"Booking/api-service-booking/internal/pkg/i18n"
+	"Booking/api-service-booking/internal/pkg/ical"
+	l "Booking/api-service-booking/internal/pkg/logger"
+	"Booking/api-service-booking/internal/pkg/otlp"
+	"Booking/api-service-booking/internal/usecase/trip"
+)
+
+// tripCheckoutTimeout is how long a checkout may run before the scheduler
+// takes it for crashed and rolls it back
+const tripCheckoutTimeout = trip.CheckoutTimeout
+
+
+// CREATE TRIP
+// @Summary CREATE TRIP
+// @Security BearerAuth
+// @Description Api for starting a trip, a basket of hotel, restaurant and attraction bookings checked out together
+// @Tags TRIP
+// @Accept json
+// @Produce json
+// @Param Trip body models.CreateTripReq true "Trip"
+// @Success 201 {object} models.TripRes
+// @Failure 400 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/trips [POST]
+func (h *HandlerV1) CreateTrip(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "CreateTrip")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	var body models.CreateTripReq
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.bindError(c, err)
+		return
+	}
+
+	userID, statusCode := GetIdFromToken(c.Request, h.Config)
+	if statusCode != http.StatusOK {
+		c.JSON(statusCode, gin.H{
+			"error": h.message(c, "cant_get_user"),
+		})
+		return
+	}
+
+	trip := entity.Trip{
+		UserID: userID,
+		Name:   body.Name,
+	}
+	if err := h.Trip.Create(ctx, &trip); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to create trip", l.Error(err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, tripRes(&trip))
+}
+
+// LIST TRIPS
+// @Summary LIST TRIPS
+// @Security BearerAuth
+// @Description Api for listing the trips of the user with their items, newest first
+// @Tags TRIP
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.ListTripsRes
+// @Failure 500 {object} models.StandartError
+// @Router /v1/trips [GET]
+func (h *HandlerV1) ListTrips(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "ListTrips")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	userID, statusCode := GetIdFromToken(c.Request, h.Config)
+	if statusCode != http.StatusOK {
+		c.JSON(statusCode, gin.H{
+			"error": h.message(c, "cant_get_user"),
+		})
+		return
+	}
+
+	trips, err := h.Trip.ListByUser(ctx, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to list trips", l.Error(err))
+		return
+	}
+
+	response := models.ListTripsRes{
+		Trips: []*models.TripRes{},
+	}
+	for _, trip := range trips {
+		response.Trips = append(response.Trips, tripRes(trip))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GET TRIP
+// @Summary GET TRIP
+// @Security BearerAuth
+// @Description Api for getting a trip of the user with its items
+// @Tags TRIP
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} models.TripRes
+// @Failure 404 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/trips/{id} [GET]
+func (h *HandlerV1) GetTrip(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "GetTrip")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	trip, _, statusCode, err := h.userTrip(ctx, c.Request, c.Param("id"))
+	if err != nil {
+		c.JSON(statusCode, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, tripRes(trip))
+}
+
+// DELETE TRIP
+// @Summary DELETE TRIP
+// @Security BearerAuth
+// @Description Api for deleting an open trip. The bookings of a checked out trip are canceled one by one through the booking apis
+// @Tags TRIP
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Success 200 {object} models.StandartError
+// @Failure 400 {object} models.StandartError
+// @Failure 404 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/trips/{id} [DELETE]
+func (h *HandlerV1) DeleteTrip(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "DeleteTrip")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	trip, _, statusCode, err := h.userTrip(ctx, c.Request, c.Param("id"))
+	if err != nil {
+		c.JSON(statusCode, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	}
+
+	err = h.Trip.Delete(ctx, trip.ID)
+	var errBadRequest *errorspkg.ErrBadRequest
+	if errors.As(err, &errBadRequest) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to delete trip", l.Error(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, "successfully deleted...")
+}
+
+// ADD TRIP ITEM
+// @Summary ADD TRIP ITEM
+// @Security BearerAuth
+// @Description Api for putting a hotel, restaurant or attraction booking into an open trip. Nothing is held until checkout
+// @Tags TRIP
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param Item body models.TripItemReq true "Item"
+// @Success 201 {object} models.TripRes
+// @Failure 400 {object} models.StandartError
+// @Failure 404 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/trips/{id}/items [POST]
+func (h *HandlerV1) AddTripItem(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "AddTripItem")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	var body models.TripItemReq
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.bindError(c, err)
+		return
+	}
+	switch body.Category {
+	case categoryHotel, categoryRestaurant, categoryAttraction:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": h.message(c, "field_one_of", "category", "hotel, restaurant, attraction"),
+		})
+		return
+	}
+
+	trip, _, statusCode, err := h.userTrip(ctx, c.Request, c.Param("id"))
+	if err != nil {
+		c.JSON(statusCode, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	}
+
+	item := entity.TripItem{
+		TripID:          trip.ID,
+		Category:        body.Category,
+		EstablishmentID: body.HraId,
+		WillArrive:      body.WillArrive,
+		WillLeave:       body.WillLeave,
+		NumberOfPeople:  body.NumberOfPeople,
+		Tickets:         map[string]int{},
+		PromoCode:       body.PromoCode,
+	}
+	for _, ticket := range body.Tickets {
+		item.Tickets[ticket.Kind] += ticket.Quantity
+	}
+
+	err = h.Trip.AddItem(ctx, &item)
+	var errBadRequest *errorspkg.ErrBadRequest
+	if errors.As(err, &errBadRequest) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to add trip item", l.Error(err))
+		return
+	}
+
+	trip.Items = append(trip.Items, &item)
+	c.JSON(http.StatusCreated, tripRes(trip))
+}
+
+// REMOVE TRIP ITEM
+// @Summary REMOVE TRIP ITEM
+// @Security BearerAuth
+// @Description Api for taking an item out of an open trip
+// @Tags TRIP
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Param item_id path string true "item_id"
+// @Success 200 {object} models.StandartError
+// @Failure 400 {object} models.StandartError
+// @Failure 404 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/trips/{id}/items/{item_id} [DELETE]
+func (h *HandlerV1) RemoveTripItem(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "RemoveTripItem")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	trip, _, statusCode, err := h.userTrip(ctx, c.Request, c.Param("id"))
+	if err != nil {
+		c.JSON(statusCode, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	}
+
+	err = h.Trip.RemoveItem(ctx, trip.ID, c.Param("item_id"))
+	var errBadRequest *errorspkg.ErrBadRequest
+	switch {
+	case errors.As(err, &errBadRequest):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	case errors.Is(err, errorspkg.ErrorNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": h.message(c, "trip_item_not_found"),
+		})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to remove trip item", l.Error(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, "successfully removed...")
+}
+
+// CHECK OUT TRIP
+// @Summary CHECK OUT TRIP
+// @Security BearerAuth
+// @Description Api for booking every item of a trip at once. Either all bookings are made or, when one fails, the ones already made are canceled and the trip stays open. Confirmation mails go out only once the whole trip is booked
+// @Tags TRIP
+// @Accept json
+// @Produce json
+// @Param id path string true "id"
+// @Success 201 {object} models.TripCheckoutRes
+// @Failure 400 {object} models.StandartError
+// @Failure 404 {object} models.StandartError
+// @Failure 409 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/trips/{id}/checkout [POST]
+func (h *HandlerV1) CheckoutTrip(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "CheckoutTrip")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	trip, userID, statusCode, err := h.userTrip(ctx, c.Request, c.Param("id"))
+	if err != nil {
+		c.JSON(statusCode, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	}
+
+	trip, err = h.Trip.BeginCheckout(ctx, trip.ID)
+	var errBadRequest *errorspkg.ErrBadRequest
+	if errors.As(err, &errBadRequest) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to begin trip checkout", l.Error(err))
+		return
+	}
+
+	// should this request die half way the scheduler rolls the trip back
+	err = h.Scheduler.Schedule(ctx, &entity.Job{
+		Kind:    entity.JobKindTripCheckout,
+		Payload: map[string]string{"trip_id": trip.ID},
+		RunAt:   time.Now().Add(tripCheckoutTimeout),
+	})
+	if err != nil {
+		h.Logger.Error("failed to schedule trip checkout rollback", l.Error(err))
+		if _, err := h.Trip.FinishCheckout(ctx, trip.ID, false); err != nil {
+			h.Logger.Error("failed to reopen trip", l.Error(err))
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		return
+	}
+
+	booker := tripBooker{h: h, userID: userID}
+	if err := h.Trip.Checkout(ctx, trip, &booker); err != nil {
+		h.tripCheckoutFailed(ctx, c, trip.ID, &booker, err)
+		return
+	}
+	if err := h.Scheduler.CancelByPayload(ctx, entity.JobKindTripCheckout, "trip_id", trip.ID); err != nil {
+		h.Logger.Error("failed to cancel trip checkout rollback", l.Error(err))
+	}
+
+	response := models.TripCheckoutRes{
+		Bookings: booker.bookings,
+	}
+	for i, book := range booker.books {
+		go h.sendBookingMail(trip.Items[i].Category, book, ical.MethodRequest, false)
+		response.Total += booker.bookings[i].Total
+	}
+
+	trip.Status = entity.TripStatusBooked
+	response.Trip = tripRes(trip)
+	c.JSON(http.StatusCreated, response)
+}
+
+// userTrip loads a trip of the caller, trips of others are not found. The
+// returned status goes to the client together with the error.
+func (h *HandlerV1) userTrip(ctx context.Context, r *http.Request, id string) (*entity.Trip, string, int, error) {
+	userID, statusCode := GetIdFromToken(r, h.Config)
+	if statusCode != http.StatusOK {
+		return nil, "", statusCode, i18n.NewError("cant_get_user")
+	}
+
+	trip, err := h.Trip.Get(ctx, id)
+	if errors.Is(err, errorspkg.ErrorNotFound) || (err == nil && trip.UserID != userID) {
+		return nil, "", http.StatusNotFound, i18n.NewError("trip_not_found")
+	}
+	if err != nil {
+		h.Logger.Error("failed to get trip", l.Error(err))
+		return nil, "", http.StatusInternalServerError, i18n.NewError("try_again_later")
+	}
+
+	return trip, userID, http.StatusOK, nil
+}
+
+// tripBooker books the items of a trip for CheckoutTrip, keeping what the
+// response and the confirmation mails need
+type tripBooker struct {
+	h      *HandlerV1
+	userID string
+	// status goes to the client with the error of the last failed booking
+	status   int
+	bookings []*models.BookingRes
+	books    []*pbb.GeneralBook
+}
+
+func (b *tripBooker) Book(ctx context.Context, item *entity.TripItem) error {
+	booking, book, status, err := b.h.createBooking(ctx, item.Category, b.userID, item.BookingID, tripItemBooking(item))
+	if err != nil {
+		b.status = status
+		return err
+	}
+	b.bookings = append(b.bookings, booking)
+	b.books = append(b.books, book)
+	return nil
+}
+
+func (b *tripBooker) Cancel(ctx context.Context, item *entity.TripItem) error {
+	record, err := b.h.BookingRecord.Get(ctx, item.BookingID)
+	if err == nil && record.State == entity.BookingStateCanceled {
+		return nil
+	}
+	if err := b.h.compensateBooking(ctx, item.Category, item.BookingID, b.userID); err != nil {
+		b.h.Logger.Error("failed to cancel trip booking", l.Error(err))
+		return err
+	}
+	return nil
+}
+
+// tripCheckoutFailed answers a checkout that did not book its trip. The
+// scheduled rollback is no longer needed once the trip was reopened.
+func (h *HandlerV1) tripCheckoutFailed(ctx context.Context, c *gin.Context, tripID string, booker *tripBooker, err error) {
+	var itemErr *trip.ItemError
+	switch {
+	case errors.As(err, &itemErr):
+		if itemErr.RollbackErr != nil {
+			h.Logger.Error("failed to roll back trip checkout", l.Error(itemErr.RollbackErr))
+		} else if err := h.Scheduler.CancelByPayload(context.WithoutCancel(ctx), entity.JobKindTripCheckout, "trip_id", tripID); err != nil {
+			h.Logger.Error("failed to cancel trip checkout rollback", l.Error(err))
+		}
+		c.JSON(booker.status, gin.H{
+			"error": h.message(c, "trip_item_failed", itemErr.Position+1, itemErr.Item.Category, itemErr.Err),
+		})
+	case errors.Is(err, trip.ErrCheckoutExpired):
+		c.JSON(http.StatusConflict, gin.H{
+			"error": h.message(c, "trip_checkout_expired"),
+		})
+	default:
+		// the scheduled rollback cancels the bookings
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to mark trip booked", l.Error(err))
+	}
+}
+
+// recoverTripCheckout rolls back a checkout that did not finish in time.
+// Bookings that made it into the booking service are canceled, holds and
+// discounts of the others are given back.
+func (h *HandlerV1) recoverTripCheckout(ctx context.Context, job *entity.Job) error {
+	tripID := job.Payload["trip_id"]
+	if tripID == "" {
+		return fmt.Errorf("trip checkout job %s has no trip_id", job.ID)
+	}
+	trip, err := h.Trip.Get(ctx, tripID)
+	if errors.Is(err, errorspkg.ErrorNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if trip.Status != entity.TripStatusCheckingOut {
+		return nil
+	}
+
+	for _, item := range trip.Items {
+		if item.BookingID == "" {
+			continue
+		}
+		record, err := h.BookingRecord.Get(ctx, item.BookingID)
+		if errors.Is(err, errorspkg.ErrorNotFound) {
+			h.releaseHold(ctx, item.Category, item.BookingID)
+			h.releaseDiscounts(ctx, item.BookingID)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if record.State == entity.BookingStateCanceled {
+			continue
+		}
+		if err := h.compensateBooking(ctx, item.Category, item.BookingID, trip.UserID); err != nil {
+			return err
+		}
+	}
+
+	_, err = h.Trip.FinishCheckout(ctx, trip.ID, false)
+	return err
+}
+
+// tripItemBooking is the booking request an item is checked out with
+func tripItemBooking(item *entity.TripItem) *models.CreateBookingReq {
+	body := models.CreateBookingReq{
+		HraId:          item.EstablishmentID,
+		WillArrive:     item.WillArrive,
+		WillLeave:      item.WillLeave,
+		NumberOfPeople: item.NumberOfPeople,
+		PromoCode:      item.PromoCode,
+	}
+	for kind, quantity := range item.Tickets {
+		body.Tickets = append(body.Tickets, &models.TicketReq{Kind: kind, Quantity: quantity})
+	}
+	return &body
+}
+
+func tripRes(trip *entity.Trip) *models.TripRes {
+	response := models.TripRes{
+		Id:        trip.ID,
+		UserId:    trip.UserID,
+		Name:      trip.Name,
+		Status:    trip.Status,
+		Items:     []*models.TripItemRes{},
+		CreatedAt: trip.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: trip.UpdatedAt.Format(time.RFC3339),
+	}
+	for _, item := range trip.Items {
+		response.Items = append(response.Items, &models.TripItemRes{
+			Id:             item.ID,
+			Category:       item.Category,
+			HraId:          item.EstablishmentID,
+			WillArrive:     item.WillArrive,
+			WillLeave:      item.WillLeave,
+			NumberOfPeople: item.NumberOfPeople,
+			Tickets:        item.Tickets,
+			PromoCode:      item.PromoCode,
+			BookingId:      item.BookingID,
+			CreatedAt:      item.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	return &response
+}
@@ -45,9 +45,7 @@ func (h *HandlerV1) Create(c *gin.Context) {
 
 	err := c.ShouldBindJSON(&body)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Error binding JSON",
-		})
+		h.bindError(c, err)
 		l.Error(err)
 		return
 	}
@@ -55,7 +53,7 @@ func (h *HandlerV1) Create(c *gin.Context) {
 	res := valid.IsValidEmail(body.Email)
 	if !res {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Incorrect Email. Try again",
+			"error": h.message(c, "incorrect_email"),
 		})
 
 		h.Logger.Error("Incorrect Email. Try again, error while in Create")
@@ -65,7 +63,7 @@ func (h *HandlerV1) Create(c *gin.Context) {
 	res = valid.IsValidPassword(body.Password)
 	if !res {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Incorrect Password. Try again",
+			"error": h.message(c, "incorrect_password"),
 		})
 
 		h.Logger.Error("Incorrect Password. Try again, error while in Create")
@@ -78,7 +76,7 @@ func (h *HandlerV1) Create(c *gin.Context) {
 	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Went wrong",
+			"error": h.message(c, "something_went_wrong"),
 		})
 
 		h.Logger.Error("Error while check unique email in Create")
@@ -96,7 +94,7 @@ func (h *HandlerV1) Create(c *gin.Context) {
 	password, err  := etc.HashPassword(body.Password)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Went wrong",
+			"error": h.message(c, "something_went_wrong"),
 		})
 
 		h.Logger.Error("Error while hash password in Create")
@@ -116,7 +114,7 @@ func (h *HandlerV1) Create(c *gin.Context) {
 	access, refresh, err := h.JwtHandler.GenerateJwt()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "error while generating jwt",
+			"error": h.message(c, "something_went_wrong"),
 		})
 		h.Logger.Error("error generate new jwt tokens", l.Error(err))
 		return
@@ -138,7 +136,7 @@ func (h *HandlerV1) Create(c *gin.Context) {
 	
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": err.Error(),
+			"error": h.errorMessage(c, err),
 		})
 		l.Error(err)
 		return
@@ -190,7 +188,7 @@ func (h *HandlerV1) Get(c *gin.Context) {
 		})
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
+			"error": h.errorMessage(c, err),
 		})
 		l.Error(err)
 		return
@@ -246,7 +244,7 @@ func (h *HandlerV1) ListUsers(c *gin.Context) {
 	params, errStr := utils.ParseQueryParam(queryParams)
 	if errStr != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": errStr[0],
+			"error": h.errorMessage(c, errStr[0]),
 		})
 		return
 	}
@@ -275,7 +273,7 @@ func (h *HandlerV1) ListUsers(c *gin.Context) {
 
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": err.Error(),
+			"error": h.errorMessage(c, err),
 		})
 		l.Error(err)
 		return
@@ -309,7 +307,7 @@ func (h *HandlerV1) ListDeletedUsers(c *gin.Context) {
 	params, errStr := utils.ParseQueryParam(queryParams)
 	if errStr != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": errStr[0],
+			"error": h.errorMessage(c, errStr[0]),
 		})
 		return
 	}
@@ -337,7 +335,7 @@ func (h *HandlerV1) ListDeletedUsers(c *gin.Context) {
 
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": err.Error(),
+			"error": h.errorMessage(c, err),
 		})
 		l.Error(err)
 		return
@@ -374,9 +372,7 @@ func (h *HandlerV1) Update(c *gin.Context) {
 
 	err := c.ShouldBindJSON(&body)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
-		})
+		h.bindError(c, err)
 		h.Logger.Error("failed to bind json", l.Error(err))
 		return
 	}
@@ -384,7 +380,7 @@ func (h *HandlerV1) Update(c *gin.Context) {
     userID, statusCode := GetIdFromToken(c.Request, h.Config)
 	if statusCode != http.StatusOK {
 		c.JSON(statusCode, gin.H{
-            "error": "Can't get",
+            "error": h.message(c, "cant_get_user"),
         })
         return
     }
@@ -394,7 +390,7 @@ func (h *HandlerV1) Update(c *gin.Context) {
 	})
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Went wrong",
+			"error": h.message(c, "something_went_wrong"),
 		})
 		h.Logger.Error("failed to get user in update", l.Error(err))
 		return
@@ -411,7 +407,7 @@ func (h *HandlerV1) Update(c *gin.Context) {
 		emailVal := valid.IsValidEmail(body.Email)
 		if !emailVal {
 			c.JSON(http.StatusBadRequest, gin.H{
-				"error": "Incorrect Email. Try again",
+				"error": h.message(c, "incorrect_email"),
 			})
 	
 			h.Logger.Error("Incorrect Email. Try again, error while in update user")
@@ -423,7 +419,7 @@ func (h *HandlerV1) Update(c *gin.Context) {
 		validpas := valid.IsValidPassword(body.Password) 
 		if !validpas {
 			c.JSON(http.StatusBadRequest, gin.H{
-				"error": "Incorrect Password. Try again",
+				"error": h.message(c, "incorrect_password"),
 			})
 	
 			h.Logger.Error("Incorrect Password. Try again, error while in update user")
@@ -432,7 +428,7 @@ func (h *HandlerV1) Update(c *gin.Context) {
 		body.Password, err =  etc.HashPassword(body.Password)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "Went wrong",
+				"error": h.message(c, "something_went_wrong"),
 			})
 			h.Logger.Error("failed to hash password in update", l.Error(err))
 			return
@@ -482,7 +478,7 @@ func (h *HandlerV1) Update(c *gin.Context) {
 	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": err.Error(),
+			"error": h.errorMessage(c, err),
 		})
 		h.Logger.Error("failed to update user", l.Error(err))
 		return
@@ -535,7 +531,7 @@ func (h *HandlerV1) Delete(c *gin.Context) {
 	})
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Went wrong, error",
+			"error": h.message(c, "something_went_wrong"),
 		})
 		h.Logger.Error("failed to get user in delete", l.Error(err))
 		return
@@ -554,7 +550,7 @@ func (h *HandlerV1) Delete(c *gin.Context) {
 		})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Went wrong, error",
+			"error": h.message(c, "something_went_wrong"),
 		})
 		h.Logger.Error("failed to delete user", l.Error(err))
 		return
@@ -562,7 +558,7 @@ func (h *HandlerV1) Delete(c *gin.Context) {
 
 	// if response != nil {
 	// 	c.JSON(http.StatusInternalServerError, gin.H{
-	// 		"error": "Went wrong",
+	// 		"error": h.message(c, "something_went_wrong"),
 	// 	})
 	// 	h.Logger.Error("failed to delete user", l.Error(err))
 	// 	return
@@ -601,7 +597,7 @@ func (h *HandlerV1) GetByToken(c *gin.Context) {
 	userID, statusCode := GetIdFromToken(c.Request, h.Config)
 	if statusCode != http.StatusOK {
 		c.JSON(statusCode, gin.H{
-            "error": "Can't get",
+            "error": h.message(c, "cant_get_user"),
         })
         return
     }
@@ -612,7 +608,7 @@ func (h *HandlerV1) GetByToken(c *gin.Context) {
 		})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": err.Error(),
+			"error": h.errorMessage(c, err),
 		})
 		l.Error(err)
 		return
@@ -0,0 +1,653 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+
+	"Booking/api-service-booking/api/models"
+	pbu "Booking/api-service-booking/genproto/user-proto"
+	"Booking/api-service-booking/internal/entity"
+	errorspkg "Booking/api-service-booking/internal/errors"
+	"Booking/api-service-booking/internal/pkg/booktime"
+	"Booking/api-service-booking/internal/pkg/i18n"
+	l "Booking/api-service-booking/internal/pkg/logger"
+	"Booking/api-service-booking/internal/pkg/otlp"
+	scode "Booking/api-service-booking/internal/pkg/sendcode"
+)
+
+// JOIN WAITLIST
+// @Summary JOIN WAITLIST
+// @Security BearerAuth
+// @Description Api for joining the waitlist of a sold out hotel, table slot or attraction slot
+// @Tags WAITLIST
+// @Accept json
+// @Produce json
+// @Param Entry body models.JoinWaitlistReq true "Entry"
+// @Success 201 {object} models.WaitlistEntryRes
+// @Failure 400 {object} models.StandartError
+// @Failure 404 {object} models.StandartError
+// @Failure 409 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/waitlist [POST]
+func (h *HandlerV1) JoinWaitlist(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "JoinWaitlist")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	var body models.JoinWaitlistReq
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.bindError(c, err)
+		return
+	}
+
+	userID, statusCode := GetIdFromToken(c.Request, h.Config)
+	if statusCode != http.StatusOK {
+		c.JSON(statusCode, gin.H{
+			"error": h.message(c, "cant_get_user"),
+		})
+		return
+	}
+
+	entry := entity.WaitlistEntry{
+		Category:        body.Category,
+		EstablishmentID: body.HraId,
+		UserID:          userID,
+		WillArrive:      body.WillArrive,
+		NumberOfPeople:  body.NumberOfPeople,
+		Tickets:         map[string]int{},
+	}
+	for _, ticket := range body.Tickets {
+		entry.Tickets[ticket.Kind] += ticket.Quantity
+	}
+
+	if statusCode, err := h.waitlistRange(ctx, &entry, body.WillLeave); err != nil {
+		c.JSON(statusCode, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	}
+	if statusCode, err := h.checkSoldOut(ctx, &entry); err != nil {
+		c.JSON(statusCode, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	}
+
+	err := h.Waitlist.Join(ctx, &entry)
+	if errors.Is(err, errorspkg.ErrorConflict) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": h.message(c, "already_waitlisted"),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to join waitlist", l.Error(err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, waitlistEntryRes(&entry))
+}
+
+// LIST WAITLIST
+// @Summary LIST WAITLIST
+// @Security BearerAuth
+// @Description Api for viewing the waitlist entries of the user with their place in line
+// @Tags WAITLIST
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.ListWaitlistRes
+// @Failure 500 {object} models.StandartError
+// @Router /v1/waitlist [GET]
+func (h *HandlerV1) ListWaitlist(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "ListWaitlist")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	userID, statusCode := GetIdFromToken(c.Request, h.Config)
+	if statusCode != http.StatusOK {
+		c.JSON(statusCode, gin.H{
+			"error": h.message(c, "cant_get_user"),
+		})
+		return
+	}
+
+	entries, err := h.Waitlist.ListByUser(ctx, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to list waitlist", l.Error(err))
+		return
+	}
+
+	response := models.ListWaitlistRes{
+		Entries: []*models.WaitlistEntryRes{},
+	}
+	for _, entry := range entries {
+		response.Entries = append(response.Entries, waitlistEntryRes(entry))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// LEAVE WAITLIST
+// @Summary LEAVE WAITLIST
+// @Security BearerAuth
+// @Description Api for leaving a waitlist, an open offer goes to the next person in line
+// @Tags WAITLIST
+// @Accept json
+// @Produce json
+// @Param id path string true "ID"
+// @Success 200 {object} models.StandartError
+// @Failure 404 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/waitlist/{id} [DELETE]
+func (h *HandlerV1) LeaveWaitlist(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "LeaveWaitlist")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	userID, statusCode := GetIdFromToken(c.Request, h.Config)
+	if statusCode != http.StatusOK {
+		c.JSON(statusCode, gin.H{
+			"error": h.message(c, "cant_get_user"),
+		})
+		return
+	}
+
+	entry, err := h.Waitlist.Leave(ctx, c.Param("id"), userID)
+	if errors.Is(err, errorspkg.ErrorNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": h.message(c, "waitlist_entry_not_found"),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to leave waitlist", l.Error(err))
+		return
+	}
+
+	if entry.Status == entity.WaitlistStatusOffered {
+		h.cancelOfferExpiry(ctx, entry)
+		h.releaseWaitlistHold(ctx, entry)
+		h.offerWaitlist(ctx, entry.Category, entry.EstablishmentID, entry.ArriveAt, entry.LeaveAt)
+	}
+
+	c.JSON(http.StatusOK, "successfully left...")
+}
+
+// CLAIM WAITLIST OFFER
+// @Summary CLAIM WAITLIST OFFER
+// @Security BearerAuth
+// @Description Api for turning an open waitlist offer into a booking before it expires
+// @Tags WAITLIST
+// @Accept json
+// @Produce json
+// @Param id path string true "ID"
+// @Success 201 {object} models.BookingRes
+// @Failure 404 {object} models.StandartError
+// @Failure 409 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/waitlist/{id}/claim [POST]
+func (h *HandlerV1) ClaimWaitlist(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "ClaimWaitlist")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	userID, statusCode := GetIdFromToken(c.Request, h.Config)
+	if statusCode != http.StatusOK {
+		c.JSON(statusCode, gin.H{
+			"error": h.message(c, "cant_get_user"),
+		})
+		return
+	}
+
+	entry, err := h.Waitlist.Get(ctx, c.Param("id"))
+	if errors.Is(err, errorspkg.ErrorNotFound) || (err == nil && entry.UserID != userID) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": h.message(c, "waitlist_entry_not_found"),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to get waitlist entry", l.Error(err))
+		return
+	}
+
+	if entry.Status != entity.WaitlistStatusOffered || entry.OfferExpiresAt.Before(time.Now()) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": h.message(c, "no_open_offer"),
+		})
+		return
+	}
+
+	// claiming first keeps the expiry job from releasing the hold meanwhile
+	claimed, err := h.Waitlist.Claim(ctx, entry.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to claim waitlist offer", l.Error(err))
+		return
+	}
+	if !claimed {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": h.message(c, "no_open_offer"),
+		})
+		return
+	}
+	h.cancelOfferExpiry(ctx, entry)
+
+	body := models.CreateBookingReq{
+		HraId:          entry.EstablishmentID,
+		WillArrive:     entry.WillArrive,
+		WillLeave:      entry.WillLeave,
+		NumberOfPeople: entry.NumberOfPeople,
+	}
+	for kind, quantity := range entry.Tickets {
+		body.Tickets = append(body.Tickets, &models.TicketReq{Kind: kind, Quantity: quantity})
+	}
+
+	response, statusCode, err := h.placeBooking(ctx, entry.Category, userID, entry.ID, &body)
+	if err != nil {
+		h.reopenWaitlistOffer(ctx, entry)
+		c.JSON(statusCode, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	}
+
+	c.JSON(statusCode, response)
+}
+
+// WAITLIST DEMAND
+// @Summary WAITLIST DEMAND
+// @Security BearerAuth
+// @Description Api for owners to see how many people wait for each arrival date
+// @Tags WAITLIST
+// @Accept json
+// @Produce json
+// @Param request query models.WaitlistDemandReq true "request"
+// @Success 200 {object} models.ListWaitlistDemandRes
+// @Failure 400 {object} models.StandartError
+// @Failure 403 {object} models.StandartError
+// @Failure 500 {object} models.StandartError
+// @Router /v1/waitlist/demand [GET]
+func (h *HandlerV1) WaitlistDemand(c *gin.Context) {
+	ctx, span := otlp.Start(c, "api", "WaitlistDemand")
+	span.SetAttributes(
+		attribute.Key("method").String(c.Request.Method),
+	)
+	defer span.End()
+
+	var body models.WaitlistDemandReq
+	if err := c.ShouldBindQuery(&body); err != nil {
+		h.bindError(c, err)
+		return
+	}
+
+	from, _, errFrom := booktime.Parse(body.From)
+	to, _, errTo := booktime.Parse(body.To)
+	if errFrom != nil || errTo != nil || to.Before(from) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": h.message(c, "date_range"),
+		})
+		return
+	}
+
+	if statusCode, err := h.checkManager(ctx, c.Request, body.Category, body.EstablishmentId); err != nil {
+		c.JSON(statusCode, gin.H{
+			"error": h.errorMessage(c, err),
+		})
+		return
+	}
+
+	demand, err := h.Waitlist.Demand(ctx, body.Category, body.EstablishmentId, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": h.message(c, "try_again_later"),
+		})
+		h.Logger.Error("failed to get waitlist demand", l.Error(err))
+		return
+	}
+
+	response := models.ListWaitlistDemandRes{
+		Demand: []*models.WaitlistDemandRes{},
+	}
+	for _, d := range demand {
+		response.Demand = append(response.Demand, &models.WaitlistDemandRes{
+			Date:    d.Date,
+			Entries: d.Entries,
+			People:  d.People,
+		})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// waitlistRange fills in the dates an entry waits for. Restaurants and
+// attractions with timed entry wait for one seating or entry slot, their
+// end is derived from the start like it is when booking.
+func (h *HandlerV1) waitlistRange(ctx context.Context, entry *entity.WaitlistEntry, willLeave string) (int, error) {
+	if _, err := h.getBookedPlace(ctx, entry.Category, entry.EstablishmentID); err != nil {
+		h.Logger.Error("failed to get establishment", l.Error(err))
+		return http.StatusNotFound, i18n.NewError("establishment_not_found")
+	}
+
+	arriveAt, dateOnly, err := booktime.Parse(entry.WillArrive)
+	if err != nil {
+		return http.StatusBadRequest, i18n.NewError("field_invalid_date", "will_arrive")
+	}
+	entry.ArriveAt = arriveAt
+
+	var length time.Duration
+	switch entry.Category {
+	case categoryRestaurant:
+		length = h.Config.Restaurant.SlotLength
+	case categoryAttraction:
+		settings, err := h.AttractionTicket.GetSettings(ctx, entry.EstablishmentID)
+		if err != nil && !errors.Is(err, errorspkg.ErrorNotFound) {
+			h.Logger.Error("failed to get entry settings", l.Error(err))
+			return http.StatusInternalServerError, i18n.NewError("try_again_later")
+		}
+		if err == nil {
+			length = settings.SlotLength
+			if len(entry.Tickets) > 0 {
+				entry.NumberOfPeople = 0
+				for _, quantity := range entry.Tickets {
+					entry.NumberOfPeople += int64(quantity)
+				}
+			}
+		}
+	}
+
+	if length > 0 {
+		if dateOnly {
+			return http.StatusBadRequest, i18n.NewError("field_needs_time", "will_arrive")
+		}
+		entry.LeaveAt = arriveAt.Add(length)
+		entry.WillLeave = entry.LeaveAt.Format("2006-01-02T15:04:05")
+	} else {
+		leaveAt, _, err := booktime.Parse(willLeave)
+		if err != nil || !leaveAt.After(arriveAt) {
+			return http.StatusBadRequest, i18n.NewError("field_after", "will_leave", "will_arrive")
+		}
+		entry.LeaveAt = leaveAt
+		entry.WillLeave = willLeave
+	}
+
+	if entry.NumberOfPeople < 1 {
+		return http.StatusBadRequest, i18n.NewError("field_positive", "number_of_people")
+	}
+
+	return http.StatusOK, nil
+}
+
+// checkSoldOut makes sure nothing the entry waits for can be booked right
+// away. Places without a known capacity, like restaurants without tables
+// or hotels without a room count, can not be checked and are taken to be
+// sold out.
+func (h *HandlerV1) checkSoldOut(ctx context.Context, entry *entity.WaitlistEntry) (int, error) {
+	free := false
+	switch entry.Category {
+	case categoryHotel:
+		rooms, err := h.HotelRoom.FreeRooms(ctx, entry.EstablishmentID, "", entry.ArriveAt, entry.LeaveAt)
+		if errors.Is(err, errorspkg.ErrorNotFound) {
+			break
+		}
+		if err != nil {
+			h.Logger.Error("failed to count free rooms", l.Error(err))
+			return http.StatusInternalServerError, i18n.NewError("try_again_later")
+		}
+		free = rooms > 0
+	case categoryRestaurant:
+		schedule, status, err := h.restaurantSchedule(ctx, entry.EstablishmentID)
+		if err != nil {
+			return status, err
+		}
+		if schedule == nil {
+			break
+		}
+		if !schedule.Covers(entry.ArriveAt, entry.LeaveAt) {
+			return http.StatusBadRequest, i18n.NewError("restaurant_closed_at", entry.ArriveAt.In(booktime.Location()).Format("15:04"))
+		}
+		tables, err := h.RestaurantTable.FreeTables(ctx, entry.EstablishmentID, entry.ArriveAt, int(entry.NumberOfPeople))
+		if err != nil {
+			h.Logger.Error("failed to count free tables", l.Error(err))
+			return http.StatusInternalServerError, i18n.NewError("try_again_later")
+		}
+		free = tables > 0
+	case categoryAttraction:
+		slots, err := h.AttractionTicket.Slots(ctx, entry.EstablishmentID, entry.ArriveAt)
+		if errors.Is(err, errorspkg.ErrorNotFound) {
+			break
+		}
+		if err != nil {
+			h.Logger.Error("failed to list entry slots", l.Error(err))
+			return http.StatusInternalServerError, i18n.NewError("try_again_later")
+		}
+		for _, slot := range slots {
+			if slot.StartsAt.Equal(entry.ArriveAt) && slot.Free >= int(entry.NumberOfPeople) {
+				free = true
+			}
+		}
+	}
+
+	if free {
+		return http.StatusConflict, i18n.NewError("place_available")
+	}
+	return http.StatusOK, nil
+}
+
+// offerWaitlist offers capacity freed between from and to to the first
+// person in line it fits, holding it under the id of their entry
+func (h *HandlerV1) offerWaitlist(ctx context.Context, category, establishmentID string, from, to time.Time) {
+	line, err := h.Waitlist.Line(ctx, category, establishmentID, from, to)
+	if err != nil {
+		h.Logger.Error("failed to get waitlist", l.Error(err))
+		return
+	}
+
+	for _, entry := range line {
+		held, err := h.holdForWaitlist(ctx, entry)
+		if err != nil {
+			h.Logger.Error("failed to hold place for waitlist", l.Error(err))
+			return
+		}
+		if !held {
+			continue
+		}
+
+		offered, err := h.Waitlist.Offer(ctx, entry)
+		if err != nil || !offered {
+			if err != nil {
+				h.Logger.Error("failed to offer waitlist place", l.Error(err))
+			}
+			h.releaseWaitlistHold(ctx, entry)
+			continue
+		}
+
+		h.scheduleOfferExpiry(ctx, entry, *entry.OfferExpiresAt)
+		go h.sendWaitlistOfferMail(entry)
+		return
+	}
+}
+
+// offerFreedPlace passes the capacity of a canceled booking on to the line
+func (h *HandlerV1) offerFreedPlace(ctx context.Context, record *entity.BookingRecord) {
+	if record.ArriveAt == nil || record.LeaveAt == nil {
+		return
+	}
+	h.offerWaitlist(ctx, record.Category, record.EstablishmentID, *record.ArriveAt, *record.LeaveAt)
+}
+
+// holdForWaitlist takes the rooms, table or tickets an entry needs, false
+// means the freed capacity does not fit it
+func (h *HandlerV1) holdForWaitlist(ctx context.Context, entry *entity.WaitlistEntry) (bool, error) {
+	body := models.CreateBookingReq{
+		HraId:          entry.EstablishmentID,
+		WillArrive:     entry.WillArrive,
+		NumberOfPeople: entry.NumberOfPeople,
+	}
+
+	var (
+		statusCode int
+		err        error
+	)
+	switch entry.Category {
+	case categoryHotel:
+		statusCode, err = h.holdRooms(ctx, entry.EstablishmentID, entry.ID, entry.ArriveAt, entry.LeaveAt)
+	case categoryRestaurant:
+		_, statusCode, err = h.reserveTable(ctx, entry.ID, &body)
+	case categoryAttraction:
+		for kind, quantity := range entry.Tickets {
+			body.Tickets = append(body.Tickets, &models.TicketReq{Kind: kind, Quantity: quantity})
+		}
+		_, statusCode, err = h.issueTickets(ctx, entry.ID, &body)
+	}
+
+	if err != nil && statusCode < http.StatusInternalServerError {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (h *HandlerV1) releaseWaitlistHold(ctx context.Context, entry *entity.WaitlistEntry) {
+	h.releaseHold(ctx, entry.Category, entry.ID)
+}
+
+// reopenWaitlistOffer gives a claimed offer back when its booking failed,
+// the offer keeps its original deadline
+func (h *HandlerV1) reopenWaitlistOffer(ctx context.Context, entry *entity.WaitlistEntry) {
+	if err := h.Waitlist.Restore(ctx, entry); err != nil {
+		h.Logger.Error("failed to reopen waitlist offer", l.Error(err))
+		return
+	}
+
+	runAt := *entry.OfferExpiresAt
+	if runAt.Before(time.Now()) {
+		runAt = time.Now()
+	}
+	h.scheduleOfferExpiry(ctx, entry, runAt)
+}
+
+// scheduleOfferExpiry ends the offer of entry at runAt unless it is claimed
+// or left before. The entry goes in the payload, the job is not about a
+// booking.
+func (h *HandlerV1) scheduleOfferExpiry(ctx context.Context, entry *entity.WaitlistEntry, runAt time.Time) {
+	if err := h.Scheduler.Schedule(ctx, &entity.Job{
+		Kind: entity.JobKindWaitlistOffer,
+		Payload: map[string]string{
+			"entry_id": entry.ID,
+		},
+		RunAt: runAt,
+	}); err != nil {
+		h.Logger.Error("failed to schedule offer expiry", l.Error(err))
+	}
+}
+
+func (h *HandlerV1) cancelOfferExpiry(ctx context.Context, entry *entity.WaitlistEntry) {
+	if err := h.Scheduler.CancelByPayload(ctx, entity.JobKindWaitlistOffer, "entry_id", entry.ID); err != nil {
+		h.Logger.Error("failed to cancel offer expiry", l.Error(err))
+	}
+}
+
+// expireWaitlistOffer ends an offer that was not claimed in time and moves
+// on to the next person in line
+func (h *HandlerV1) expireWaitlistOffer(ctx context.Context, job *entity.Job) error {
+	entryID := job.Payload["entry_id"]
+	if entryID == "" {
+		return fmt.Errorf("waitlist offer job %s has no entry_id", job.ID)
+	}
+
+	entry, err := h.Waitlist.Get(ctx, entryID)
+	if err != nil {
+		return err
+	}
+
+	expired, err := h.Waitlist.Expire(ctx, entry.ID)
+	if err != nil || !expired {
+		return err
+	}
+
+	h.releaseWaitlistHold(ctx, entry)
+	h.offerWaitlist(ctx, entry.Category, entry.EstablishmentID, entry.ArriveAt, entry.LeaveAt)
+	return nil
+}
+
+func (h *HandlerV1) sendWaitlistOfferMail(entry *entity.WaitlistEntry) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.ContextTimeout)
+	defer cancel()
+
+	user, err := h.Service.UserService().Get(ctx, &pbu.Filter{
+		Filter: map[string]string{
+			"id": entry.UserID,
+		},
+	})
+	if err != nil {
+		h.Logger.Error("failed to get user for waitlist mail", l.Error(err))
+		return
+	}
+
+	place, err := h.getBookedPlace(ctx, entry.Category, entry.EstablishmentID)
+	if err != nil {
+		h.Logger.Error("failed to get place for waitlist mail", l.Error(err))
+		return
+	}
+
+	err = scode.SendBooking(user.User.Email, scode.BookingMail{
+		Title:      "A place freed up, claim it before " + entry.OfferExpiresAt.In(booktime.Location()).Format("15:04"),
+		FullName:   user.User.FullName,
+		Place:      place.Name,
+		Address:    place.Address,
+		WillArrive: entry.WillArrive,
+		WillLeave:  entry.WillLeave,
+		People:     entry.NumberOfPeople,
+		BookingId:  entry.ID,
+	}, "", nil)
+	if err != nil {
+		h.Logger.Error("failed to send waitlist mail", l.Error(err))
+	}
+}
+
+func waitlistEntryRes(entry *entity.WaitlistEntry) *models.WaitlistEntryRes {
+	response := models.WaitlistEntryRes{
+		Id:             entry.ID,
+		Category:       entry.Category,
+		HraId:          entry.EstablishmentID,
+		WillArrive:     entry.WillArrive,
+		WillLeave:      entry.WillLeave,
+		NumberOfPeople: entry.NumberOfPeople,
+		Tickets:        entry.Tickets,
+		Status:         entry.Status,
+		Position:       entry.Position,
+		CreatedAt:      entry.CreatedAt.Format(time.RFC3339),
+	}
+	if entry.OfferExpiresAt != nil {
+		response.OfferExpiresAt = entry.OfferExpiresAt.Format(time.RFC3339)
+	}
+	return &response
+}
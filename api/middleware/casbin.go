@@ -2,6 +2,8 @@ package middleware
 
 import (
 	"Booking/api-service-booking/internal/pkg/config"
+	"Booking/api-service-booking/internal/pkg/i18n"
+	"Booking/api-service-booking/internal/pkg/locale"
 	tokens "Booking/api-service-booking/internal/pkg/token"
 	"errors"
 	"net/http"
@@ -32,7 +34,7 @@ func CheckCasbinPermission(casbin *casbin.Enforcer, cfg config.Config) gin.Handl
 		if !allow {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
 				"error":   "Unauthorized",
-				"message": "Permission denied",
+				"message": i18n.T(locale.Negotiate(c.Query("lang"), c.GetHeader("Accept-Language"), cfg.Locale.Default), "permission_denied"),
 			})
 		}
 	}
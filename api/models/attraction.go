@@ -54,6 +54,8 @@ type CreateAttraction struct {
 	Country        string  `json:"country" default:"Uzbekistan"`
 	City           string  `json:"city" default:"Tashkent"`
 	StateProvince  string  `json:"state_province" default:"Shaykhontohur"`
+	// Tags are slugs from /v1/tags
+	Tags []string `json:"tags"`
 }
 
 type CreateImage struct {
@@ -82,6 +84,9 @@ type AttractionModel struct {
 	Location       LocationModel `json:"location"`
 	CreatedAt      string        `json:"created_at"`
 	UpdatedAt      string        `json:"updated_at"`
+	// OpeningStatus is left out for establishments without opening hours
+	OpeningStatus *OpeningStatusRes `json:"opening_status,omitempty"`
+	Tags          []string          `json:"tags,omitempty"`
 }
 
 type ImageModel struct {
@@ -106,8 +111,11 @@ type LocationModel struct {
 }
 
 type ListAttractionModel struct {
-	Attractions []*AttractionModel `json:"attractions"`
-	Count       uint64             `json:"count"`
+	Attractions []*AttractionModel      `json:"attractions"`
+	Count       uint64                  `json:"count"`
+	Facets      *EstablishmentFacetsRes `json:"facets,omitempty"`
+	NextCursor  string                  `json:"next_cursor,omitempty"`
+	PrevCursor  string                  `json:"prev_cursor,omitempty"`
 }
 
 type UpdateAttraction struct {
@@ -123,6 +131,8 @@ type UpdateAttraction struct {
 	Country        string  `json:"country" default:"updated country"`
 	City           string  `json:"city" default:"updated city"`
 	StateProvince  string  `json:"state_province" default:"updated state or province"`
+	// Tags replace the tags there are, left out they stay as they are
+	Tags []string `json:"tags"`
 }
 
 type UpdateLocation struct {
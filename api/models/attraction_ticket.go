@@ -0,0 +1,63 @@
+package models
+
+type CreateTicketType struct {
+	AttractionId string `json:"attraction_id"`
+	Kind         string `json:"kind" default:"adult"`
+	Price        int64  `json:"price" default:"50000"`
+}
+
+type TicketTypeRes struct {
+	Id           string `json:"id"`
+	AttractionId string `json:"attraction_id"`
+	Kind         string `json:"kind"`
+	Price        int64  `json:"price"`
+	CreatedAt    string `json:"created_at"`
+	UpdatedAt    string `json:"updated_at"`
+}
+
+type ListTicketTypesRes struct {
+	TicketTypes []*TicketTypeRes `json:"ticket_types"`
+}
+
+type EntrySettingsReq struct {
+	AttractionId string `json:"attraction_id"`
+	OpeningHours string `json:"opening_hours" default:"09:00-18:00"`
+	SlotMinutes  int    `json:"slot_minutes" default:"30"`
+	Capacity     int    `json:"capacity" default:"50"`
+}
+
+type EntrySettingsRes struct {
+	AttractionId string `json:"attraction_id"`
+	OpeningHours string `json:"opening_hours"`
+	SlotMinutes  int    `json:"slot_minutes"`
+	Capacity     int    `json:"capacity"`
+	UpdatedAt    string `json:"updated_at"`
+}
+
+type EntrySlotsReq struct {
+	AttractionId string `json:"attraction_id" form:"attraction_id"`
+	Date         string `json:"date" form:"date" default:"2024-06-01"`
+}
+
+type EntrySlotRes struct {
+	StartsAt string `json:"starts_at"`
+	EndsAt   string `json:"ends_at"`
+	Free     int    `json:"free"`
+}
+
+type ListEntrySlotsRes struct {
+	Slots []*EntrySlotRes `json:"slots"`
+}
+
+type TicketReq struct {
+	Kind     string `json:"kind" default:"adult"`
+	Quantity int    `json:"quantity" default:"1"`
+}
+
+type TicketRes struct {
+	Id      string `json:"id"`
+	Kind    string `json:"kind"`
+	Price   int64  `json:"price"`
+	Code    string `json:"code"`
+	EntryAt string `json:"entry_at"`
+}
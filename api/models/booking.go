@@ -3,36 +3,48 @@ package models
 import "github.com/google/uuid"
 
 type CreateBookingReq struct {
-	HraId          string `json:"hra_id"`
-	WillArrive     string `json:"will_arrive"`
-	WillLeave      string `json:"will_leave"`
-	NumberOfPeople int64  `json:"number_of_people"`
-	IsCanceled     bool   `json:"is_canceled"`
-	Reason         string `json:"reason"`
+	HraId          string       `json:"hra_id"`
+	WillArrive     string       `json:"will_arrive"`
+	WillLeave      string       `json:"will_leave"`
+	NumberOfPeople int64        `json:"number_of_people"`
+	IsCanceled     bool         `json:"is_canceled"`
+	Reason         string       `json:"reason"`
+	Tickets        []*TicketReq `json:"tickets,omitempty"`
+	PromoCode      string       `json:"promo_code,omitempty"`
+	Points         int64        `json:"points,omitempty"`
 }
 
 type UpdateBookingReq struct {
-	Id             uuid.UUID `json:"id"`
-	HraId          string    `json:"hra_id"`
-	WillArrive     string    `json:"will_arrive"`
-	WillLeave      string    `json:"will_leave"`
-	NumberOfPeople int64     `json:"number_of_people"`
-	IsCanceled     bool      `json:"is_canceled"`
-	Reason         string    `json:"reason"`
+	Id             uuid.UUID    `json:"id"`
+	HraId          string       `json:"hra_id"`
+	WillArrive     string       `json:"will_arrive"`
+	WillLeave      string       `json:"will_leave"`
+	NumberOfPeople int64        `json:"number_of_people"`
+	IsCanceled     bool         `json:"is_canceled"`
+	Reason         string       `json:"reason"`
+	Tickets        []*TicketReq `json:"tickets,omitempty"`
 }
 
 type BookingRes struct {
-	Id             uuid.UUID `json:"id"`
-	UserId         string    `json:"user_id"`
-	HraId          string    `json:"hra_id"`
-	WillArrive     string    `json:"will_arrive"`
-	WillLeave      string    `json:"will_leave"`
-	NumberOfPeople int64     `json:"number_of_people"`
-	IsCanceled     bool      `json:"is_canceled"`
-	Reason         string    `json:"reason"`
-	CreatedAt      string    `json:"created_at"`
-	UpdatedAt      string    `json:"updated_at"`
-	DeletedAt      string    `json:"deleted_at"`
+	Id             uuid.UUID    `json:"id"`
+	UserId         string       `json:"user_id"`
+	HraId          string       `json:"hra_id"`
+	WillArrive     string       `json:"will_arrive"`
+	WillLeave      string       `json:"will_leave"`
+	NumberOfPeople int64        `json:"number_of_people"`
+	IsCanceled     bool         `json:"is_canceled"`
+	Reason         string       `json:"reason"`
+	CreatedAt      string       `json:"created_at"`
+	UpdatedAt      string       `json:"updated_at"`
+	DeletedAt      string       `json:"deleted_at"`
+	TableId        string       `json:"table_id,omitempty"`
+	Tickets        []*TicketRes `json:"tickets,omitempty"`
+	Price          int64        `json:"price"`
+	Discount       int64        `json:"discount"`
+	Total          int64        `json:"total"`
+	PromoCode      string       `json:"promo_code,omitempty"`
+	Points         int64        `json:"points,omitempty"`
+	PointsDiscount int64        `json:"points_discount,omitempty"`
 }
 
 type IdReq struct {
@@ -53,3 +65,22 @@ type BookedUser struct {
 	PhoneNumber string `json:"phone_number"`
 	BookedTime  string `json:"created_at"`
 }
+
+type FieldChangeRes struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+type BookingChangeRes struct {
+	Id        string            `json:"id"`
+	ChangedBy string            `json:"changed_by"`
+	Role      string            `json:"role"`
+	Fields    []*FieldChangeRes `json:"fields"`
+	CreatedAt string            `json:"created_at"`
+}
+
+type BookingHistoryRes struct {
+	BookingId string              `json:"booking_id"`
+	Changes   []*BookingChangeRes `json:"changes"`
+}
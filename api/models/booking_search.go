@@ -0,0 +1,25 @@
+package models
+
+type BookingRecordRes struct {
+	Id             string `json:"id"`
+	Category       string `json:"category"`
+	UserId         string `json:"user_id"`
+	HraId          string `json:"hra_id"`
+	WillArrive     string `json:"will_arrive"`
+	WillLeave      string `json:"will_leave"`
+	NumberOfPeople int64  `json:"number_of_people"`
+	Price          int64  `json:"price"`
+	Discount       int64  `json:"discount"`
+	Total          int64  `json:"total"`
+	PromoCode      string `json:"promo_code,omitempty"`
+	Points         int64  `json:"points,omitempty"`
+	PointsDiscount int64  `json:"points_discount,omitempty"`
+	State          string `json:"state"`
+	CreatedAt      string `json:"created_at"`
+	UpdatedAt      string `json:"updated_at"`
+}
+
+type SearchBookingsRes struct {
+	Bookings   []*BookingRecordRes `json:"bookings"`
+	NextCursor string              `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,33 @@
+package models
+
+type DashboardReq struct {
+	Category string   `json:"category" form:"category" default:"hotel"`
+	HraId    []string `json:"hra_id" form:"hra_id"`
+	From     string   `json:"from" form:"from" default:"2024-06-01"`
+	To       string   `json:"to" form:"to" default:"2024-06-30"`
+}
+
+type DashboardDayRes struct {
+	Date       string   `json:"date"`
+	Bookings   int      `json:"bookings"`
+	Guests     int64    `json:"guests"`
+	Arrivals   int      `json:"arrivals"`
+	Departures int      `json:"departures"`
+	Canceled   int      `json:"canceled"`
+	Capacity   int      `json:"capacity"`
+	Occupancy  *float64 `json:"occupancy,omitempty"`
+}
+
+type DashboardRes struct {
+	Category         string             `json:"category"`
+	HraIds           []string           `json:"hra_ids"`
+	From             string             `json:"from"`
+	To               string             `json:"to"`
+	Capacity         int                `json:"capacity"`
+	Days             []*DashboardDayRes `json:"days"`
+	Booked           int                `json:"booked"`
+	Canceled         int                `json:"canceled"`
+	CancellationRate float64            `json:"cancellation_rate"`
+	ArrivalsToday    int                `json:"arrivals_today"`
+	DeparturesToday  int                `json:"departures_today"`
+}
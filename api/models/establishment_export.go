@@ -0,0 +1,35 @@
+package models
+
+type CreateSnapshotReq struct {
+	Category string `json:"category" binding:"required" default:"hotel"`
+	// Format is csv, ndjson or xlsx, csv when left out
+	Format    string  `json:"format" default:"csv"`
+	City      string  `json:"city"`
+	Country   string  `json:"country"`
+	OwnerID   string  `json:"owner_id"`
+	MinRating float64 `json:"min_rating"`
+}
+
+type SnapshotRes struct {
+	Id        string  `json:"id"`
+	Category  string  `json:"category"`
+	Format    string  `json:"format"`
+	City      string  `json:"city,omitempty"`
+	Country   string  `json:"country,omitempty"`
+	OwnerID   string  `json:"owner_id,omitempty"`
+	MinRating float64 `json:"min_rating,omitempty"`
+	NextRunAt string  `json:"next_run_at"`
+	LastRunAt string  `json:"last_run_at,omitempty"`
+	LastRows  int     `json:"last_rows"`
+	LastError string  `json:"last_error,omitempty"`
+	// LastLink downloads the latest file for a while, it is only given
+	// for a single snapshot
+	LastLink  string `json:"last_link,omitempty"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+type ListSnapshotsRes struct {
+	Snapshots []*SnapshotRes `json:"snapshots"`
+	Count     uint64         `json:"count"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import "mime/multipart"
+
+type StartImportReq struct {
+	File     *multipart.FileHeader `form:"file" binding:"required"`
+	Category string                `form:"category" binding:"required" default:"hotel"`
+	// Format is csv or ndjson, left out it is taken from the file extension
+	Format string `form:"format"`
+	// DryRun is true unless it is set to false
+	DryRun *bool `form:"dry_run"`
+}
+
+type ImportRowErrorRes struct {
+	Line        int    `json:"line"`
+	ExternalRef string `json:"external_ref,omitempty"`
+	Error       string `json:"error"`
+}
+
+type ImportRes struct {
+	Id        string `json:"id"`
+	Category  string `json:"category"`
+	Format    string `json:"format"`
+	DryRun    bool   `json:"dry_run"`
+	Status    string `json:"status"`
+	Total     int    `json:"total"`
+	Processed int    `json:"processed"`
+	// Progress is the percent of rows processed
+	Progress float64 `json:"progress"`
+	// Created and Updated are what a dry run would create and update
+	Created   int                  `json:"created"`
+	Updated   int                  `json:"updated"`
+	Failed    int                  `json:"failed"`
+	Errors    []*ImportRowErrorRes `json:"errors,omitempty"`
+	CreatedAt string               `json:"created_at"`
+	UpdatedAt string               `json:"updated_at"`
+}
+
+type ListImportsRes struct {
+	Imports []*ImportRes `json:"imports"`
+	Count   uint64       `json:"count"`
+}
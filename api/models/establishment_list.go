@@ -0,0 +1,27 @@
+package models
+
+type FacetRes struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+type PriceRangeRes struct {
+	Min int64 `json:"min"`
+	Max int64 `json:"max"`
+}
+
+type EstablishmentFacetsRes struct {
+	City   []*FacetRes   `json:"city"`
+	Rating []*FacetRes   `json:"rating"`
+	Tag    []*FacetRes   `json:"tag"`
+	Price  PriceRangeRes `json:"price"`
+}
+
+// EstablishmentPagination is the paging of the hotel, restaurant and
+// attraction lists. Cursor is the next_cursor or prev_cursor of a sorted
+// or filtered list and takes the place of page.
+type EstablishmentPagination struct {
+	Limit  int    `json:"limit"`
+	Page   int    `json:"page"`
+	Cursor string `json:"cursor"`
+}
@@ -0,0 +1,21 @@
+package models
+
+type QRReq struct {
+	Format string `json:"format" form:"format" default:"png"`
+}
+
+type CheckInReq struct {
+	Payload string `json:"payload"`
+}
+
+type CheckInRes struct {
+	BookingId       string `json:"booking_id"`
+	Category        string `json:"category"`
+	EstablishmentId string `json:"establishment_id"`
+	UserId          string `json:"user_id"`
+	NumberOfPeople  int64  `json:"number_of_people"`
+	WillArrive      string `json:"will_arrive"`
+	WillLeave       string `json:"will_leave"`
+	State           string `json:"state"`
+	CheckedInAt     string `json:"checked_in_at"`
+}
@@ -0,0 +1,77 @@
+package models
+
+type NearbyReq struct {
+	Near     string   `json:"near" form:"near" default:"39.6542,66.9597"`
+	RadiusKm float64  `json:"radius_km" form:"radius_km" default:"5"`
+	Bbox     string   `json:"bbox" form:"bbox"`
+	Category []string `json:"category" form:"category"`
+	Limit    int      `json:"limit" form:"limit" default:"20"`
+}
+
+type NearbyPlaceRes struct {
+	Category   string  `json:"category"`
+	HraId      string  `json:"hra_id"`
+	Name       string  `json:"name"`
+	Address    string  `json:"address"`
+	City       string  `json:"city"`
+	Thumbnail  string  `json:"thumbnail"`
+	Rating     float64 `json:"rating"`
+	Latitude   float64 `json:"latitude"`
+	Longitude  float64 `json:"longitude"`
+	DistanceKm float64 `json:"distance_km"`
+}
+
+type NearbyRes struct {
+	Results []*NearbyPlaceRes `json:"results"`
+	Count   int               `json:"count"`
+}
+
+type ReindexGeoRes struct {
+	Indexed map[string]int `json:"indexed"`
+}
+
+type EstablishmentSearchReq struct {
+	Q        string   `json:"q" form:"q" default:"Registon"`
+	Category []string `json:"category" form:"category"`
+	Tags     []string `json:"tags" form:"tags"`
+	Page     int      `json:"page" form:"page" default:"1"`
+	Limit    int      `json:"limit" form:"limit" default:"20"`
+}
+
+type EstablishmentSearchHitRes struct {
+	Category    string  `json:"category"`
+	HraId       string  `json:"hra_id"`
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	City        string  `json:"city"`
+	Address     string  `json:"address"`
+	Thumbnail   string  `json:"thumbnail"`
+	Rating      float64 `json:"rating"`
+	Latitude    float64 `json:"latitude"`
+	Longitude   float64 `json:"longitude"`
+	Score       float64 `json:"score"`
+}
+
+type EstablishmentSearchRes struct {
+	Results []*EstablishmentSearchHitRes `json:"results"`
+	Count   int                          `json:"count"`
+}
+
+type ReindexSearchRes struct {
+	Indexed map[string]int `json:"indexed"`
+}
+
+type SuggestReq struct {
+	Q     string `json:"q" form:"q" default:"Reg"`
+	Limit int    `json:"limit" form:"limit" default:"8"`
+}
+
+type SuggestionRes struct {
+	Type string `json:"type"`
+	Id   string `json:"id"`
+	Text string `json:"text"`
+}
+
+type SuggestRes struct {
+	Suggestions []*SuggestionRes `json:"suggestions"`
+}
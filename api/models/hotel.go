@@ -13,6 +13,8 @@ type CreateHotel struct {
 	Country       string  `json:"country" default:"Uzbekistan"`
 	City          string  `json:"city" default:"Tashkent"`
 	StateProvince string  `json:"state_province" default:"Shaykhontohur"`
+	// Tags are slugs from /v1/tags
+	Tags []string `json:"tags"`
 }
 
 type HotelModel struct {
@@ -28,11 +30,15 @@ type HotelModel struct {
 	Location      LocationModel `json:"location"`
 	CreatedAt     string        `json:"created_at"`
 	UpdatedAt     string        `json:"updated_at"`
+	Tags          []string      `json:"tags,omitempty"`
 }
 
 type ListHotelsModel struct {
-	Hotels []*HotelModel `json:"hotels"`
-	Count  uint64        `json:"count"`
+	Hotels     []*HotelModel           `json:"hotels"`
+	Count      uint64                  `json:"count"`
+	Facets     *EstablishmentFacetsRes `json:"facets,omitempty"`
+	NextCursor string                  `json:"next_cursor,omitempty"`
+	PrevCursor string                  `json:"prev_cursor,omitempty"`
 }
 
 type UpdateHotel struct {
@@ -43,4 +49,6 @@ type UpdateHotel struct {
 	LicenceUrl    string         `json:"licence_url" default:"updated licence url"`
 	WebsiteUrl    string         `json:"website_url" default:"updated website url"`
 	Location      UpdateLocation `json:"location"`
+	// Tags replace the tags there are, left out they stay as they are
+	Tags []string `json:"tags"`
 }
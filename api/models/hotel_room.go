@@ -0,0 +1,12 @@
+package models
+
+type HotelRoomsReq struct {
+	HotelId string `json:"hotel_id"`
+	Rooms   int    `json:"rooms" default:"20"`
+}
+
+type HotelRoomsRes struct {
+	HotelId   string `json:"hotel_id"`
+	Rooms     int    `json:"rooms"`
+	UpdatedAt string `json:"updated_at"`
+}
@@ -0,0 +1,62 @@
+package models
+
+type PlanItineraryReq struct {
+	City        string   `json:"city" default:"Samarkand"`
+	Country     string   `json:"country,omitempty"`
+	StartDate   string   `json:"start_date" default:"2026-05-01"`
+	Days        int      `json:"days" default:"2"`
+	Interests   []string `json:"interests,omitempty"`
+	StopsPerDay int      `json:"stops_per_day,omitempty" default:"4"`
+	Latitude    *float64 `json:"latitude,omitempty"`
+	Longitude   *float64 `json:"longitude,omitempty"`
+}
+
+type ItineraryStopModel struct {
+	Category    string  `json:"category" default:"attraction"`
+	HraId       string  `json:"hra_id"`
+	Name        string  `json:"name"`
+	Address     string  `json:"address"`
+	Latitude    float64 `json:"latitude"`
+	Longitude   float64 `json:"longitude"`
+	StartsAt    string  `json:"starts_at" default:"2026-05-01T10:00:00"`
+	EndsAt      string  `json:"ends_at" default:"2026-05-01T11:30:00"`
+	WalkKm      float64 `json:"walk_km"`
+	WalkMinutes int     `json:"walk_minutes"`
+}
+
+type ItineraryDayModel struct {
+	Date  string                `json:"date" default:"2026-05-01"`
+	Stops []*ItineraryStopModel `json:"stops"`
+}
+
+type PlanItineraryRes struct {
+	City string               `json:"city"`
+	Days []*ItineraryDayModel `json:"days"`
+}
+
+type SaveItineraryReq struct {
+	Name   string               `json:"name" default:"Samarkand weekend"`
+	City   string               `json:"city" default:"Samarkand"`
+	People int64                `json:"people" default:"2"`
+	Days   []*ItineraryDayModel `json:"days"`
+}
+
+type ItineraryRes struct {
+	Id        string               `json:"id"`
+	UserId    string               `json:"user_id"`
+	Name      string               `json:"name"`
+	City      string               `json:"city"`
+	People    int64                `json:"people"`
+	Days      []*ItineraryDayModel `json:"days"`
+	CreatedAt string               `json:"created_at"`
+	UpdatedAt string               `json:"updated_at"`
+}
+
+type ListItinerariesRes struct {
+	Itineraries []*ItineraryRes `json:"itineraries"`
+}
+
+type ItineraryTripReq struct {
+	Dates   []string `json:"dates,omitempty"`
+	HotelId string   `json:"hotel_id,omitempty"`
+}
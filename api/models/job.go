@@ -0,0 +1,25 @@
+package models
+
+type JobRes struct {
+	Id        string            `json:"id"`
+	Kind      string            `json:"kind"`
+	BookingId string            `json:"booking_id"`
+	Payload   map[string]string `json:"payload"`
+	RunAt     string            `json:"run_at"`
+	Status    string            `json:"status"`
+	Attempts  int               `json:"attempts"`
+	LastError string            `json:"last_error"`
+	CreatedAt string            `json:"created_at"`
+	UpdatedAt string            `json:"updated_at"`
+}
+
+type ListJobsRes struct {
+	Jobs  []*JobRes `json:"jobs"`
+	Count uint64    `json:"count"`
+}
+
+type ListJobsReq struct {
+	Status string `json:"status" form:"status" default:"upcoming"`
+	Page   int64  `json:"page" form:"page" default:"1"`
+	Limit  int64  `json:"limit" form:"limit" default:"10"`
+}
@@ -0,0 +1,36 @@
+package models
+
+type LoyaltyBalanceRes struct {
+	UserId     string `json:"user_id"`
+	Balance    int64  `json:"balance"`
+	PointValue int64  `json:"point_value"`
+}
+
+type LoyaltyHistoryReq struct {
+	UserId string `json:"user_id" form:"user_id"`
+	Limit  int    `json:"limit" form:"limit"`
+	Page   int    `json:"page" form:"page"`
+}
+
+type LoyaltyEntryRes struct {
+	Id        string `json:"id"`
+	UserId    string `json:"user_id"`
+	Kind      string `json:"kind"`
+	Points    int64  `json:"points"`
+	BookingId string `json:"booking_id,omitempty"`
+	Reason    string `json:"reason"`
+	CreatedBy string `json:"created_by,omitempty"`
+	ExpiresAt string `json:"expires_at,omitempty"`
+	CreatedAt string `json:"created_at"`
+}
+
+type ListLoyaltyEntriesRes struct {
+	Entries []*LoyaltyEntryRes `json:"entries"`
+	Count   uint64             `json:"count"`
+}
+
+type LoyaltyAdjustReq struct {
+	UserId string `json:"user_id"`
+	Points int64  `json:"points" default:"100"`
+	Reason string `json:"reason"`
+}
@@ -0,0 +1,28 @@
+package models
+
+// OpeningHoursReq sets a weekly schedule. Week is keyed by day name,
+// "monday" to "sunday", exceptions by date, and each day is written as
+// "09:00-14:00,15:00-22:00" or "closed". A day left out of week is closed.
+type OpeningHoursReq struct {
+	Category   string            `json:"category" default:"restaurant"`
+	HraId      string            `json:"hra_id"`
+	Week       map[string]string `json:"week"`
+	Exceptions map[string]string `json:"exceptions"`
+}
+
+type OpeningHoursRes struct {
+	Category   string            `json:"category"`
+	HraId      string            `json:"hra_id"`
+	Week       map[string]string `json:"week"`
+	Exceptions map[string]string `json:"exceptions"`
+	Status     *OpeningStatusRes `json:"status"`
+	UpdatedAt  string            `json:"updated_at"`
+}
+
+// OpeningStatusRes tells whether an establishment is open now and when
+// that changes, in RFC3339 Asia/Tashkent time
+type OpeningStatusRes struct {
+	OpenNow  bool   `json:"open_now"`
+	OpensAt  string `json:"opens_at,omitempty"`
+	ClosesAt string `json:"closes_at,omitempty"`
+}
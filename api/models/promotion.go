@@ -0,0 +1,62 @@
+package models
+
+type PromotionReq struct {
+	Code             string `json:"code" default:"SUMMER10"`
+	Description      string `json:"description"`
+	Kind             string `json:"kind" default:"percent"`
+	Value            int64  `json:"value" default:"10"`
+	Category         string `json:"category"`
+	City             string `json:"city"`
+	FirstBookingOnly bool   `json:"first_booking_only"`
+	MinPrice         int64  `json:"min_price"`
+	MaxRedemptions   int    `json:"max_redemptions"`
+	PerUserLimit     int    `json:"per_user_limit" default:"1"`
+	StartsAt         string `json:"starts_at" default:"2024-06-01T00:00:00"`
+	EndsAt           string `json:"ends_at" default:"2024-09-01T00:00:00"`
+}
+
+type UpdatePromotionReq struct {
+	Id string `json:"id"`
+	PromotionReq
+}
+
+type PromotionRes struct {
+	Id               string `json:"id"`
+	Code             string `json:"code"`
+	Description      string `json:"description"`
+	Kind             string `json:"kind"`
+	Value            int64  `json:"value"`
+	Category         string `json:"category"`
+	City             string `json:"city"`
+	FirstBookingOnly bool   `json:"first_booking_only"`
+	MinPrice         int64  `json:"min_price"`
+	MaxRedemptions   int    `json:"max_redemptions"`
+	PerUserLimit     int    `json:"per_user_limit"`
+	Redemptions      int    `json:"redemptions"`
+	StartsAt         string `json:"starts_at"`
+	EndsAt           string `json:"ends_at"`
+	CreatedAt        string `json:"created_at"`
+	UpdatedAt        string `json:"updated_at"`
+}
+
+type ListPromotionsRes struct {
+	Promotions []*PromotionRes `json:"promotions"`
+	Count      uint64          `json:"count"`
+}
+
+type ValidatePromoReq struct {
+	Category       string       `json:"category" default:"hotel"`
+	HraId          string       `json:"hra_id"`
+	WillArrive     string       `json:"will_arrive"`
+	WillLeave      string       `json:"will_leave"`
+	NumberOfPeople int64        `json:"number_of_people"`
+	Tickets        []*TicketReq `json:"tickets,omitempty"`
+	PromoCode      string       `json:"promo_code"`
+}
+
+type QuoteRes struct {
+	Price     int64  `json:"price"`
+	Discount  int64  `json:"discount"`
+	Total     int64  `json:"total"`
+	PromoCode string `json:"promo_code,omitempty"`
+}
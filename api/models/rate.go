@@ -0,0 +1,14 @@
+package models
+
+type RateReq struct {
+	Category string `json:"category" default:"hotel"`
+	HraId    string `json:"hra_id"`
+	Price    int64  `json:"price" default:"500000"`
+}
+
+type RateRes struct {
+	Category  string `json:"category"`
+	HraId     string `json:"hra_id"`
+	Price     int64  `json:"price"`
+	UpdatedAt string `json:"updated_at"`
+}
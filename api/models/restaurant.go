@@ -14,6 +14,8 @@ type CreateRestaurant struct {
 	Country        string  `json:"country" default:"Uzbekistan"`
 	City           string  `json:"city" default:"Tashkent"`
 	StateProvince  string  `json:"state_province" default:"Shaykhontohur"`
+	// Tags are slugs from /v1/tags
+	Tags []string `json:"tags"`
 }
 
 type RestaurantModel struct {
@@ -30,11 +32,17 @@ type RestaurantModel struct {
 	Location       LocationModel `json:"location"`
 	CreatedAt      string        `json:"created_at"`
 	UpdatedAt      string        `json:"updated_at"`
+	// OpeningStatus is left out for establishments without opening hours
+	OpeningStatus *OpeningStatusRes `json:"opening_status,omitempty"`
+	Tags          []string          `json:"tags,omitempty"`
 }
 
 type ListRestaurantsModel struct {
-	Restaurants []*RestaurantModel `json:"restaurants"`
-	Count       uint64             `json:"count"`
+	Restaurants []*RestaurantModel      `json:"restaurants"`
+	Count       uint64                  `json:"count"`
+	Facets      *EstablishmentFacetsRes `json:"facets,omitempty"`
+	NextCursor  string                  `json:"next_cursor,omitempty"`
+	PrevCursor  string                  `json:"prev_cursor,omitempty"`
 }
 
 type UpdateRestaurant struct {
@@ -51,4 +59,6 @@ type UpdateRestaurant struct {
 	Country        string  `json:"country" default:"updated country"`
 	City           string  `json:"city" default:"updated city"`
 	StateProvince  string  `json:"state_province" default:"updated state or province"`
+	// Tags replace the tags there are, left out they stay as they are
+	Tags []string `json:"tags"`
 }
@@ -0,0 +1,36 @@
+package models
+
+type CreateRestaurantTable struct {
+	RestaurantId string `json:"restaurant_id"`
+	Name         string `json:"name" default:"Table 1"`
+	Capacity     int    `json:"capacity" default:"4"`
+}
+
+type RestaurantTableRes struct {
+	Id           string `json:"id"`
+	RestaurantId string `json:"restaurant_id"`
+	Name         string `json:"name"`
+	Capacity     int    `json:"capacity"`
+	CreatedAt    string `json:"created_at"`
+	UpdatedAt    string `json:"updated_at"`
+}
+
+type ListRestaurantTablesRes struct {
+	Tables []*RestaurantTableRes `json:"tables"`
+}
+
+type SlotsReq struct {
+	RestaurantId string `json:"restaurant_id" form:"restaurant_id"`
+	Date         string `json:"date" form:"date" default:"2024-06-01"`
+	PartySize    int    `json:"party_size" form:"party_size" default:"2"`
+}
+
+type TimeSlotRes struct {
+	StartsAt   string `json:"starts_at"`
+	EndsAt     string `json:"ends_at"`
+	FreeTables int    `json:"free_tables"`
+}
+
+type ListTimeSlotsRes struct {
+	Slots []*TimeSlotRes `json:"slots"`
+}
@@ -0,0 +1,12 @@
+package models
+
+type StaffReq struct {
+	UserId          string `json:"user_id" form:"user_id"`
+	EstablishmentId string `json:"establishment_id" form:"establishment_id"`
+}
+
+type StaffRes struct {
+	UserId          string `json:"user_id"`
+	EstablishmentId string `json:"establishment_id"`
+	CreatedAt       string `json:"created_at"`
+}
@@ -0,0 +1,40 @@
+package models
+
+type TagReq struct {
+	Slug       string   `json:"slug" default:"wifi"`
+	Kind       string   `json:"kind" default:"amenity"`
+	Name       string   `json:"name" default:"Free Wi-Fi"`
+	Categories []string `json:"categories"`
+}
+
+type TagRes struct {
+	Slug       string   `json:"slug"`
+	Kind       string   `json:"kind"`
+	Name       string   `json:"name"`
+	Categories []string `json:"categories"`
+	CreatedAt  string   `json:"created_at"`
+	UpdatedAt  string   `json:"updated_at"`
+}
+
+type ListTagsReq struct {
+	Kind     string `json:"kind" form:"kind"`
+	Category string `json:"category" form:"category"`
+}
+
+type ListTagsRes struct {
+	Tags []*TagRes `json:"tags"`
+}
+
+// EstablishmentTagsReq replaces the tags of an establishment, an empty
+// list takes them all off
+type EstablishmentTagsReq struct {
+	Category string   `json:"category" default:"restaurant"`
+	HraId    string   `json:"hra_id"`
+	Tags     []string `json:"tags"`
+}
+
+type EstablishmentTagsRes struct {
+	Category string   `json:"category"`
+	HraId    string   `json:"hra_id"`
+	Tags     []string `json:"tags"`
+}
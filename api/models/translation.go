@@ -0,0 +1,26 @@
+package models
+
+// TranslationReq sets the name and description of an establishment in one
+// of the supported languages, uz, ru or en. Either may be left empty to
+// keep the establishment's own.
+type TranslationReq struct {
+	Category    string `json:"category" default:"hotel"`
+	HraId       string `json:"hra_id"`
+	Locale      string `json:"locale" default:"ru"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type TranslationRes struct {
+	Category    string `json:"category"`
+	HraId       string `json:"hra_id"`
+	Locale      string `json:"locale"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	UpdatedAt   string `json:"updated_at"`
+}
+
+type ListTranslationsRes struct {
+	DefaultLocale string            `json:"default_locale"`
+	Translations  []*TranslationRes `json:"translations"`
+}
@@ -0,0 +1,48 @@
+package models
+
+type CreateTripReq struct {
+	Name string `json:"name" default:"Samarkand weekend"`
+}
+
+type TripItemReq struct {
+	Category       string       `json:"category" default:"hotel"`
+	HraId          string       `json:"hra_id"`
+	WillArrive     string       `json:"will_arrive"`
+	WillLeave      string       `json:"will_leave"`
+	NumberOfPeople int64        `json:"number_of_people"`
+	Tickets        []*TicketReq `json:"tickets,omitempty"`
+	PromoCode      string       `json:"promo_code,omitempty"`
+}
+
+type TripItemRes struct {
+	Id             string         `json:"id"`
+	Category       string         `json:"category"`
+	HraId          string         `json:"hra_id"`
+	WillArrive     string         `json:"will_arrive"`
+	WillLeave      string         `json:"will_leave"`
+	NumberOfPeople int64          `json:"number_of_people"`
+	Tickets        map[string]int `json:"tickets,omitempty"`
+	PromoCode      string         `json:"promo_code,omitempty"`
+	BookingId      string         `json:"booking_id,omitempty"`
+	CreatedAt      string         `json:"created_at"`
+}
+
+type TripRes struct {
+	Id        string         `json:"id"`
+	UserId    string         `json:"user_id"`
+	Name      string         `json:"name"`
+	Status    string         `json:"status"`
+	Items     []*TripItemRes `json:"items"`
+	CreatedAt string         `json:"created_at"`
+	UpdatedAt string         `json:"updated_at"`
+}
+
+type ListTripsRes struct {
+	Trips []*TripRes `json:"trips"`
+}
+
+type TripCheckoutRes struct {
+	Trip     *TripRes      `json:"trip"`
+	Bookings []*BookingRes `json:"bookings"`
+	Total    int64         `json:"total"`
+}
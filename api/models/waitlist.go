@@ -0,0 +1,45 @@
+package models
+
+type JoinWaitlistReq struct {
+	Category       string       `json:"category" default:"hotel"`
+	HraId          string       `json:"hra_id"`
+	WillArrive     string       `json:"will_arrive"`
+	WillLeave      string       `json:"will_leave"`
+	NumberOfPeople int64        `json:"number_of_people"`
+	Tickets        []*TicketReq `json:"tickets,omitempty"`
+}
+
+type WaitlistEntryRes struct {
+	Id             string         `json:"id"`
+	Category       string         `json:"category"`
+	HraId          string         `json:"hra_id"`
+	WillArrive     string         `json:"will_arrive"`
+	WillLeave      string         `json:"will_leave"`
+	NumberOfPeople int64          `json:"number_of_people"`
+	Tickets        map[string]int `json:"tickets,omitempty"`
+	Status         string         `json:"status"`
+	Position       int            `json:"position,omitempty"`
+	OfferExpiresAt string         `json:"offer_expires_at,omitempty"`
+	CreatedAt      string         `json:"created_at"`
+}
+
+type ListWaitlistRes struct {
+	Entries []*WaitlistEntryRes `json:"entries"`
+}
+
+type WaitlistDemandReq struct {
+	Category        string `json:"category" form:"category" default:"hotel"`
+	EstablishmentId string `json:"establishment_id" form:"establishment_id"`
+	From            string `json:"from" form:"from" default:"2024-06-01"`
+	To              string `json:"to" form:"to" default:"2024-06-30"`
+}
+
+type WaitlistDemandRes struct {
+	Date    string `json:"date"`
+	Entries int    `json:"entries"`
+	People  int64  `json:"people"`
+}
+
+type ListWaitlistDemandRes struct {
+	Demand []*WaitlistDemandRes `json:"demand"`
+}
@@ -12,6 +12,8 @@ import (
 	"github.com/casbin/casbin/v2"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 
@@ -19,21 +21,64 @@ import (
 
 	grpcClients "Booking/api-service-booking/internal/infrastructure/grpc_service_client"
 	"Booking/api-service-booking/internal/pkg/config"
+	"Booking/api-service-booking/internal/pkg/storage"
 	tokens "Booking/api-service-booking/internal/pkg/token"
+	"Booking/api-service-booking/internal/pkg/validation"
 	"Booking/api-service-booking/internal/usecase/app_version"
+	"Booking/api-service-booking/internal/usecase/attraction_ticket"
+	"Booking/api-service-booking/internal/usecase/booking_record"
+	"Booking/api-service-booking/internal/usecase/establishment_import"
+	"Booking/api-service-booking/internal/usecase/establishment_search"
+	"Booking/api-service-booking/internal/usecase/establishment_snapshot"
 	"Booking/api-service-booking/internal/usecase/event"
+	"Booking/api-service-booking/internal/usecase/geo_search"
+	"Booking/api-service-booking/internal/usecase/hotel_room"
+	"Booking/api-service-booking/internal/usecase/itinerary"
+	"Booking/api-service-booking/internal/usecase/loyalty"
+	"Booking/api-service-booking/internal/usecase/opening_hours"
+	"Booking/api-service-booking/internal/usecase/pricing"
+	"Booking/api-service-booking/internal/usecase/promotion"
+	"Booking/api-service-booking/internal/usecase/restaurant_table"
+	"Booking/api-service-booking/internal/usecase/scheduler"
+	"Booking/api-service-booking/internal/usecase/staff"
+	"Booking/api-service-booking/internal/usecase/suggest"
+	"Booking/api-service-booking/internal/usecase/tag"
+	"Booking/api-service-booking/internal/usecase/translation"
+	"Booking/api-service-booking/internal/usecase/trip"
+	"Booking/api-service-booking/internal/usecase/waitlist"
 	// "Booking/api-service-booking/internal/usecase/refresh_token"
 )
 
 type RouteOption struct {
-	Config         *config.Config
-	Logger         *zap.Logger
-	ContextTimeout time.Duration
-	Service        grpcClients.ServiceClient
-	JwtHandler   tokens.JwtHandler
-	BrokerProducer event.BrokerProducer
-	AppVersion     app_version.AppVersion
-	Enforcer       *casbin.Enforcer
+	Config                *config.Config
+	Logger                *zap.Logger
+	ContextTimeout        time.Duration
+	Service               grpcClients.ServiceClient
+	JwtHandler            tokens.JwtHandler
+	BrokerProducer        event.BrokerProducer
+	AppVersion            app_version.AppVersion
+	Enforcer              *casbin.Enforcer
+	Storage               *storage.Storage
+	BookingRecord         booking_record.BookingRecord
+	Scheduler             scheduler.Scheduler
+	RestaurantTable       restaurant_table.RestaurantTable
+	AttractionTicket      attraction_ticket.AttractionTicket
+	HotelRoom             hotel_room.HotelRoom
+	Staff                 staff.Staff
+	Waitlist              waitlist.Waitlist
+	Pricing               pricing.Pricing
+	Promotion             promotion.Promotion
+	Loyalty               loyalty.Loyalty
+	Trip                  trip.Trip
+	Itinerary             itinerary.Itinerary
+	GeoSearch             geo_search.GeoSearch
+	EstablishmentSearch   establishment_search.EstablishmentSearch
+	Suggest               suggest.Suggest
+	OpeningHours          opening_hours.OpeningHours
+	Tag                   tag.Tag
+	Translation           translation.Translation
+	EstablishmentImport   establishment_import.EstablishmentImport
+	EstablishmentSnapshot establishment_snapshot.EstablishmentSnapshot
 }
 
 // NewRouter
@@ -49,16 +94,47 @@ func NewRoute(option RouteOption) *gin.Engine {
 	router.Use(gin.Logger())
 	router.Use(gin.Recovery())
 
+	// requests are bound with the validator handlers check with, so their
+	// field errors are told in the language of the request too
+	if engine, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		if err := validation.Use(engine); err != nil {
+			option.Logger.Error("failed to set up request validation", zap.Error(err))
+		}
+	}
+
 	HandlerV1 := v1.New(&v1.HandlerV1Config{
-		Config:         option.Config,
-		Logger:         option.Logger,
-		ContextTimeout: option.ContextTimeout,
-		Service:        option.Service,
-		JwtHandler:   option.JwtHandler,
-		AppVersion:     option.AppVersion,
-		BrokerProducer: option.BrokerProducer,
-		Enforcer:       option.Enforcer,
+		Config:                option.Config,
+		Logger:                option.Logger,
+		ContextTimeout:        option.ContextTimeout,
+		Service:               option.Service,
+		JwtHandler:            option.JwtHandler,
+		AppVersion:            option.AppVersion,
+		BrokerProducer:        option.BrokerProducer,
+		Enforcer:              option.Enforcer,
+		Storage:               option.Storage,
+		BookingRecord:         option.BookingRecord,
+		Scheduler:             option.Scheduler,
+		RestaurantTable:       option.RestaurantTable,
+		AttractionTicket:      option.AttractionTicket,
+		HotelRoom:             option.HotelRoom,
+		Staff:                 option.Staff,
+		Waitlist:              option.Waitlist,
+		Pricing:               option.Pricing,
+		Promotion:             option.Promotion,
+		Loyalty:               option.Loyalty,
+		Trip:                  option.Trip,
+		Itinerary:             option.Itinerary,
+		GeoSearch:             option.GeoSearch,
+		EstablishmentSearch:   option.EstablishmentSearch,
+		Suggest:               option.Suggest,
+		OpeningHours:          option.OpeningHours,
+		Tag:                   option.Tag,
+		Translation:           option.Translation,
+		EstablishmentImport:   option.EstablishmentImport,
+		EstablishmentSnapshot: option.EstablishmentSnapshot,
 	})
+	HandlerV1.RegisterJobs(option.Scheduler)
+	HandlerV1.RegisterSuggestSource(option.Suggest)
 
 	corsConfig := cors.DefaultConfig()
 	corsConfig.AllowAllOrigins = true
@@ -66,6 +142,7 @@ func NewRoute(option RouteOption) *gin.Engine {
 	corsConfig.AllowHeaders = []string{"*"}
 	corsConfig.AllowBrowserExtensions = true
 	corsConfig.AllowMethods = []string{"*"}
+	corsConfig.ExposeHeaders = []string{"Content-Language"}
 	router.Use(cors.New(corsConfig))
 
 	// router.Use(middleware.Tracing)
@@ -83,6 +160,9 @@ func NewRoute(option RouteOption) *gin.Engine {
 	api.PUT("/users", HandlerV1.Update)
 	api.DELETE("/users/:id", HandlerV1.Delete)
 	api.GET("/users/token", HandlerV1.GetByToken)
+	api.GET("/users/loyalty", HandlerV1.GetLoyaltyBalance)
+	api.GET("/users/loyalty/history", HandlerV1.ListLoyaltyHistory)
+	api.POST("/users/loyalty/adjust", HandlerV1.AdjustLoyalty)
 
 	// ATTRACTION METHODS
 	api.POST("/attraction", HandlerV1.CreateAttraction)
@@ -92,6 +172,11 @@ func NewRoute(option RouteOption) *gin.Engine {
 	api.DELETE("/attraction", HandlerV1.DeleteAttraction)
 	api.GET("/attraction/listlocation", HandlerV1.ListAttractionsByLocation)
 	api.GET("/attraction/find", HandlerV1.FindAttractionsByName)
+	api.POST("/attraction/tickets/types", HandlerV1.CreateTicketType)
+	api.GET("/attraction/tickets/types", HandlerV1.ListTicketTypes)
+	api.DELETE("/attraction/tickets/types/:id", HandlerV1.DeleteTicketType)
+	api.PUT("/attraction/tickets/settings", HandlerV1.SetEntrySettings)
+	api.GET("/attraction/slots", HandlerV1.ListEntrySlots)
 
 	// HOTEL METHODS
 	api.POST("/hotel", HandlerV1.CreateHotel)
@@ -101,6 +186,7 @@ func NewRoute(option RouteOption) *gin.Engine {
 	api.DELETE("/hotel", HandlerV1.DeleteHotel)
 	api.GET("/hotel/listlocation", HandlerV1.ListHotelsByLocation)
 	api.GET("/hotel/find", HandlerV1.FindHotelsByName)
+	api.PUT("/hotel/rooms", HandlerV1.SetHotelRooms)
 
 	// RESTAURANT METHODS
 	api.POST("/restaurant", HandlerV1.CreateRestaurant)
@@ -110,6 +196,42 @@ func NewRoute(option RouteOption) *gin.Engine {
 	api.DELETE("/restaurant", HandlerV1.DeleteRestaurant)
 	api.GET("/restaurant/listlocation", HandlerV1.ListRestaurantsByLocation)
 	api.GET("/restaurant/find", HandlerV1.FindRestaurantsByName)
+	api.POST("/restaurant/tables", HandlerV1.CreateRestaurantTable)
+	api.GET("/restaurant/tables", HandlerV1.ListRestaurantTables)
+	api.DELETE("/restaurant/tables/:id", HandlerV1.DeleteRestaurantTable)
+	api.GET("/restaurant/slots", HandlerV1.ListRestaurantSlots)
+
+	// ESTABLISHMENT SEARCH
+	api.GET("/establishments/nearby", HandlerV1.SearchNearby)
+	api.POST("/establishments/geo/reindex", HandlerV1.ReindexGeo)
+	api.GET("/establishments/search", HandlerV1.SearchEstablishments)
+	api.POST("/establishments/search/reindex", HandlerV1.ReindexSearch)
+	api.GET("/search/suggest", HandlerV1.GetSuggestions)
+	api.PUT("/establishments/tags", HandlerV1.SetEstablishmentTags)
+
+	// TRANSLATION
+	api.PUT("/establishments/translations", HandlerV1.SetTranslation)
+	api.GET("/establishments/translations", HandlerV1.ListTranslations)
+	api.DELETE("/establishments/translations", HandlerV1.DeleteTranslation)
+
+	// IMPORT
+	api.POST("/establishments/imports", HandlerV1.StartImport)
+	api.GET("/establishments/imports", HandlerV1.ListImports)
+	api.GET("/establishments/imports/:id", HandlerV1.GetImport)
+	api.POST("/establishments/imports/:id/apply", HandlerV1.ApplyImport)
+
+	// EXPORT
+	api.GET("/establishments/export", HandlerV1.ExportEstablishments)
+	api.POST("/establishments/snapshots", HandlerV1.CreateSnapshot)
+	api.GET("/establishments/snapshots", HandlerV1.ListSnapshots)
+	api.GET("/establishments/snapshots/:id", HandlerV1.GetSnapshot)
+	api.DELETE("/establishments/snapshots/:id", HandlerV1.DeleteSnapshot)
+
+	// TAG
+	api.POST("/tags", HandlerV1.CreateTag)
+	api.GET("/tags", HandlerV1.ListTags)
+	api.PUT("/tags", HandlerV1.UpdateTag)
+	api.DELETE("/tags/:slug", HandlerV1.DeleteTag)
 
 	// FAVOURITE METHODS
 	api.POST("/favourite/add", HandlerV1.AddToFavourites)
@@ -170,6 +292,65 @@ func NewRoute(option RouteOption) *gin.Engine {
 	api.PUT("/booking/attractions", HandlerV1.UABUpdate)
 	api.DELETE("/booking/attractions/:id", HandlerV1.UABDelete)
 
+	// BOOKING HISTORY
+	api.GET("/bookings/search", HandlerV1.SearchBookings)
+	api.GET("/bookings/:id/history", HandlerV1.GetBookingHistory)
+
+	// DASHBOARD
+	api.GET("/dashboard", HandlerV1.GetDashboard)
+
+	// TRIP
+	api.POST("/trips", HandlerV1.CreateTrip)
+	api.GET("/trips", HandlerV1.ListTrips)
+	api.GET("/trips/:id", HandlerV1.GetTrip)
+	api.DELETE("/trips/:id", HandlerV1.DeleteTrip)
+	api.POST("/trips/:id/items", HandlerV1.AddTripItem)
+	api.DELETE("/trips/:id/items/:item_id", HandlerV1.RemoveTripItem)
+	api.POST("/trips/:id/checkout", HandlerV1.CheckoutTrip)
+
+	// ITINERARY
+	api.POST("/itineraries/plan", HandlerV1.PlanItinerary)
+	api.POST("/itineraries", HandlerV1.SaveItinerary)
+	api.GET("/itineraries", HandlerV1.ListItineraries)
+	api.GET("/itineraries/:id", HandlerV1.GetItinerary)
+	api.DELETE("/itineraries/:id", HandlerV1.DeleteItinerary)
+	api.POST("/itineraries/:id/trip", HandlerV1.ItineraryTrip)
+
+	// E-TICKET
+	api.GET("/tickets/:id/qr", HandlerV1.GetBookingQR)
+	api.POST("/checkin", HandlerV1.CheckIn)
+
+	// WAITLIST
+	api.POST("/waitlist", HandlerV1.JoinWaitlist)
+	api.GET("/waitlist", HandlerV1.ListWaitlist)
+	api.GET("/waitlist/demand", HandlerV1.WaitlistDemand)
+	api.DELETE("/waitlist/:id", HandlerV1.LeaveWaitlist)
+	api.POST("/waitlist/:id/claim", HandlerV1.ClaimWaitlist)
+
+	// STAFF
+	api.POST("/staff", HandlerV1.AddStaff)
+	api.DELETE("/staff", HandlerV1.RemoveStaff)
+
+	// PRICING
+	api.PUT("/rates", HandlerV1.SetRate)
+	api.GET("/rates", HandlerV1.GetRate)
+
+	// OPENING HOURS
+	api.PUT("/opening-hours", HandlerV1.SetOpeningHours)
+	api.GET("/opening-hours", HandlerV1.GetOpeningHours)
+	api.DELETE("/opening-hours", HandlerV1.DeleteOpeningHours)
+
+	// PROMOTION
+	api.POST("/promotions", HandlerV1.CreatePromotion)
+	api.GET("/promotions", HandlerV1.ListPromotions)
+	api.POST("/promotions/validate", HandlerV1.ValidatePromo)
+	api.GET("/promotions/:id", HandlerV1.GetPromotion)
+	api.PUT("/promotions", HandlerV1.UpdatePromotion)
+	api.DELETE("/promotions/:id", HandlerV1.DeletePromotion)
+
+	// SCHEDULER
+	api.GET("/jobs", HandlerV1.ListJobs)
+
 	url := ginSwagger.URL("swagger/doc.json")
 	api.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler, url))
 	return router
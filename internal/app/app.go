@@ -17,6 +17,7 @@ import (
 
 	// "Booking/api-service-booking/internal/infrastructure/kafka"
 	"Booking/api-service-booking/internal/infrastructure/repository/postgresql"
+	redisrepo "Booking/api-service-booking/internal/infrastructure/repository/redis"
 	"Booking/api-service-booking/internal/pkg/config"
 	"Booking/api-service-booking/internal/pkg/logger"
 	"Booking/api-service-booking/internal/pkg/otlp"
@@ -25,23 +26,66 @@ import (
 
 	"Booking/api-service-booking/internal/pkg/postgres"
 	"Booking/api-service-booking/internal/pkg/redis"
+	"Booking/api-service-booking/internal/pkg/storage"
 	"Booking/api-service-booking/internal/usecase/app_version"
+	"Booking/api-service-booking/internal/usecase/attraction_ticket"
+	"Booking/api-service-booking/internal/usecase/booking_record"
+	"Booking/api-service-booking/internal/usecase/establishment_import"
+	"Booking/api-service-booking/internal/usecase/establishment_search"
+	"Booking/api-service-booking/internal/usecase/establishment_snapshot"
 	"Booking/api-service-booking/internal/usecase/event"
+	"Booking/api-service-booking/internal/usecase/geo_search"
+	"Booking/api-service-booking/internal/usecase/hotel_room"
+	"Booking/api-service-booking/internal/usecase/itinerary"
+	"Booking/api-service-booking/internal/usecase/loyalty"
+	"Booking/api-service-booking/internal/usecase/opening_hours"
+	"Booking/api-service-booking/internal/usecase/pricing"
+	"Booking/api-service-booking/internal/usecase/promotion"
+	"Booking/api-service-booking/internal/usecase/restaurant_table"
+	"Booking/api-service-booking/internal/usecase/scheduler"
+	"Booking/api-service-booking/internal/usecase/staff"
+	"Booking/api-service-booking/internal/usecase/suggest"
+	"Booking/api-service-booking/internal/usecase/tag"
+	"Booking/api-service-booking/internal/usecase/translation"
+	"Booking/api-service-booking/internal/usecase/trip"
+	"Booking/api-service-booking/internal/usecase/waitlist"
 	// "Booking/api-service-booking/internal/usecase/refresh_token"
 	// "Booking/api-service-booking/internal/usecase/refresh_token"
 )
 
 type App struct {
-	Config  *config.Config
-	Logger  *zap.Logger
-	DB      *postgres.PostgresDB
-	RedisDB *redis.RedisDB
-	server  *http.Server
-	Enforcer       *casbin.Enforcer
-	Clients        grpcService.ServiceClient
-	ShutdownOTLP   func() error
-	BrokerProducer event.BrokerProducer
-	appVersion     app_version.AppVersion
+	Config                *config.Config
+	Logger                *zap.Logger
+	DB                    *postgres.PostgresDB
+	RedisDB               *redis.RedisDB
+	Storage               *storage.Storage
+	server                *http.Server
+	Enforcer              *casbin.Enforcer
+	Clients               grpcService.ServiceClient
+	ShutdownOTLP          func() error
+	BrokerProducer        event.BrokerProducer
+	appVersion            app_version.AppVersion
+	bookingRecord         booking_record.BookingRecord
+	scheduler             scheduler.Scheduler
+	stopScheduler         context.CancelFunc
+	restaurantTable       restaurant_table.RestaurantTable
+	attractionTicket      attraction_ticket.AttractionTicket
+	hotelRoom             hotel_room.HotelRoom
+	staff                 staff.Staff
+	waitlist              waitlist.Waitlist
+	pricing               pricing.Pricing
+	promotion             promotion.Promotion
+	loyalty               loyalty.Loyalty
+	trip                  trip.Trip
+	itinerary             itinerary.Itinerary
+	geoSearch             geo_search.GeoSearch
+	establishmentSearch   establishment_search.EstablishmentSearch
+	suggest               suggest.Suggest
+	openingHours          opening_hours.OpeningHours
+	tag                   tag.Tag
+	translation           translation.Translation
+	establishmentImport   establishment_import.EstablishmentImport
+	establishmentSnapshot establishment_snapshot.EstablishmentSnapshot
 }
 
 func NewApp(cfg config.Config) (*App, error) {
@@ -66,6 +110,12 @@ func NewApp(cfg config.Config) (*App, error) {
 		return nil, err
 	}
 
+	// media storage init
+	mediaStorage, err := storage.New(&cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	// otlp collector init
 	shutdownOTLP, err := otlp.InitOTLPProvider(&cfg)
 	if err != nil {
@@ -94,15 +144,111 @@ func NewApp(cfg config.Config) (*App, error) {
 
 	appVersionUseCase := app_version.NewAppVersionService(contextTimeout, appVersionRepo)
 
+	bookingRecordRepo := postgresql.NewBookingRecordRepo(db)
+
+	bookingRecordUseCase := booking_record.NewBookingRecordService(contextTimeout, bookingRecordRepo)
+
+	jobRepo := postgresql.NewJobRepo(db)
+
+	schedulerUseCase := scheduler.NewSchedulerService(contextTimeout, jobRepo, redisrepo.NewLocker(redisdb), logger, scheduler.Options{
+		Interval:    cfg.Scheduler.Interval,
+		LockTTL:     cfg.Scheduler.LockTTL,
+		MaxAttempts: cfg.Scheduler.MaxAttempts,
+	})
+
+	restaurantTableRepo := postgresql.NewRestaurantTableRepo(db)
+
+	restaurantTableUseCase := restaurant_table.NewRestaurantTableService(contextTimeout, restaurantTableRepo, restaurant_table.Options{
+		SlotLength: cfg.Restaurant.SlotLength,
+		SlotStep:   cfg.Restaurant.SlotStep,
+		Turnover:   cfg.Restaurant.Turnover,
+	})
+
+	attractionTicketRepo := postgresql.NewAttractionTicketRepo(db)
+	attractionTicketUseCase := attraction_ticket.NewAttractionTicketService(contextTimeout, attractionTicketRepo)
+
+	hotelRoomRepo := postgresql.NewHotelRoomRepo(db)
+	hotelRoomUseCase := hotel_room.NewHotelRoomService(contextTimeout, hotelRoomRepo)
+
+	staffRepo := postgresql.NewStaffRepo(db)
+	staffUseCase := staff.NewStaffService(contextTimeout, staffRepo)
+
+	waitlistRepo := postgresql.NewWaitlistRepo(db)
+	waitlistUseCase := waitlist.NewWaitlistService(contextTimeout, waitlistRepo, waitlist.Options{
+		HoldFor: cfg.Waitlist.HoldFor,
+	})
+
+	rateRepo := postgresql.NewRateRepo(db)
+	pricingUseCase := pricing.NewPricingService(contextTimeout, rateRepo)
+
+	promotionRepo := postgresql.NewPromotionRepo(db)
+	promotionUseCase := promotion.NewPromotionService(contextTimeout, promotionRepo)
+
+	loyaltyRepo := postgresql.NewLoyaltyRepo(db)
+	loyaltyUseCase := loyalty.NewLoyaltyService(contextTimeout, loyaltyRepo, loyalty.Options{
+		EarnPer:    cfg.Loyalty.EarnPer,
+		PointValue: cfg.Loyalty.PointValue,
+		PointsTTL:  cfg.Loyalty.PointsTTL,
+	})
+
+	tripRepo := postgresql.NewTripRepo(db)
+	tripUseCase := trip.NewTripService(contextTimeout, tripRepo)
+
+	itineraryRepo := postgresql.NewItineraryRepo(db)
+	itineraryUseCase := itinerary.NewItineraryService(contextTimeout, itineraryRepo)
+
+	geoSearchUseCase := geo_search.NewGeoSearchService(contextTimeout, redisrepo.NewGeoIndex(redisdb))
+
+	searchDocumentRepo := postgresql.NewSearchDocumentRepo(db)
+	establishmentSearchUseCase := establishment_search.NewEstablishmentSearchService(contextTimeout, searchDocumentRepo)
+
+	suggestUseCase := suggest.NewSuggestService(contextTimeout, redisrepo.NewQueryLog(redisdb), logger, cfg.Suggest.RefreshInterval)
+
+	openingScheduleRepo := postgresql.NewOpeningScheduleRepo(db)
+	openingHoursUseCase := opening_hours.NewOpeningHoursService(contextTimeout, openingScheduleRepo, searchDocumentRepo)
+
+	tagRepo := postgresql.NewTagRepo(db)
+	tagUseCase := tag.NewTagService(contextTimeout, tagRepo)
+
+	translationRepo := postgresql.NewTranslationRepo(db)
+	translationUseCase := translation.NewTranslationService(contextTimeout, translationRepo, cfg.Locale.Default)
+
+	establishmentImportRepo := postgresql.NewEstablishmentImportRepo(db)
+	establishmentImportUseCase := establishment_import.NewEstablishmentImportService(contextTimeout, establishmentImportRepo)
+
+	establishmentSnapshotRepo := postgresql.NewEstablishmentSnapshotRepo(db)
+	establishmentSnapshotUseCase := establishment_snapshot.NewEstablishmentSnapshotService(contextTimeout, establishmentSnapshotRepo, redisrepo.NewLocker(redisdb))
+
 	return &App{
-		Config:  &cfg,
-		Logger:  logger,
-		DB:      db,
-		RedisDB: redisdb,
-		Enforcer:       enforcer,
+		Config:   &cfg,
+		Logger:   logger,
+		DB:       db,
+		RedisDB:  redisdb,
+		Storage:  mediaStorage,
+		Enforcer: enforcer,
 		// BrokerProducer: kafkaProducer,
-		ShutdownOTLP: shutdownOTLP,
-		appVersion:   appVersionUseCase,
+		ShutdownOTLP:          shutdownOTLP,
+		appVersion:            appVersionUseCase,
+		bookingRecord:         bookingRecordUseCase,
+		scheduler:             schedulerUseCase,
+		restaurantTable:       restaurantTableUseCase,
+		attractionTicket:      attractionTicketUseCase,
+		hotelRoom:             hotelRoomUseCase,
+		staff:                 staffUseCase,
+		waitlist:              waitlistUseCase,
+		pricing:               pricingUseCase,
+		promotion:             promotionUseCase,
+		loyalty:               loyaltyUseCase,
+		trip:                  tripUseCase,
+		itinerary:             itineraryUseCase,
+		geoSearch:             geoSearchUseCase,
+		establishmentSearch:   establishmentSearchUseCase,
+		suggest:               suggestUseCase,
+		openingHours:          openingHoursUseCase,
+		tag:                   tagUseCase,
+		translation:           translationUseCase,
+		establishmentImport:   establishmentImportUseCase,
+		establishmentSnapshot: establishmentSnapshotUseCase,
 	}, nil
 }
 
@@ -132,10 +278,31 @@ func (a *App) Run() error {
 		Logger:         a.Logger,
 		ContextTimeout: contextTimeout,
 		// Cache:          cache,
-		Enforcer:       a.Enforcer,
-		Service:        clients,
-		BrokerProducer: a.BrokerProducer,
-		AppVersion:     a.appVersion,
+		Enforcer:              a.Enforcer,
+		Storage:               a.Storage,
+		Service:               clients,
+		BrokerProducer:        a.BrokerProducer,
+		AppVersion:            a.appVersion,
+		BookingRecord:         a.bookingRecord,
+		Scheduler:             a.scheduler,
+		RestaurantTable:       a.restaurantTable,
+		AttractionTicket:      a.attractionTicket,
+		HotelRoom:             a.hotelRoom,
+		Staff:                 a.staff,
+		Waitlist:              a.waitlist,
+		Pricing:               a.pricing,
+		Promotion:             a.promotion,
+		Loyalty:               a.loyalty,
+		Trip:                  a.trip,
+		Itinerary:             a.itinerary,
+		GeoSearch:             a.geoSearch,
+		EstablishmentSearch:   a.establishmentSearch,
+		Suggest:               a.suggest,
+		OpeningHours:          a.openingHours,
+		Tag:                   a.tag,
+		Translation:           a.translation,
+		EstablishmentImport:   a.establishmentImport,
+		EstablishmentSnapshot: a.establishmentSnapshot,
 	})
 	err = a.Enforcer.LoadPolicy()
 	if err != nil {
@@ -146,6 +313,13 @@ func (a *App) Run() error {
 	roleManager.AddMatchingFunc("keyMatch", util.KeyMatch)
 	roleManager.AddMatchingFunc("keyMatch3", util.KeyMatch3)
 
+	// scheduler and suggestion index init, handlers and the suggestion
+	// source are registered by the router
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	a.stopScheduler = stopScheduler
+	go a.scheduler.Run(schedulerCtx)
+	go a.suggest.Run(schedulerCtx)
+
 	// server init
 	a.server, err = api.NewServer(a.Config, handler)
 	if err != nil {
@@ -157,6 +331,11 @@ func (a *App) Run() error {
 
 func (a *App) Stop() {
 
+	// stop scheduler
+	if a.stopScheduler != nil {
+		a.stopScheduler()
+	}
+
 	// close database
 	a.DB.Close()
 
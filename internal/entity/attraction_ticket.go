@@ -0,0 +1,51 @@
+package entity
+
+import "time"
+
+const (
+	TicketKindAdult   = "adult"
+	TicketKindChild   = "child"
+	TicketKindStudent = "student"
+)
+
+// TicketKinds lists the ticket types an attraction may sell
+var TicketKinds = []string{TicketKindAdult, TicketKindChild, TicketKindStudent}
+
+type TicketType struct {
+	ID           string
+	AttractionID string
+	Kind         string
+	Price        int64
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// EntrySettings splits the opening hours of an attraction into entry
+// slots of SlotLength, each admitting at most Capacity visitors
+type EntrySettings struct {
+	AttractionID string
+	OpeningHours string
+	SlotLength   time.Duration
+	Capacity     int
+	UpdatedAt    time.Time
+}
+
+type Ticket struct {
+	ID           string
+	BookingID    string
+	AttractionID string
+	TicketTypeID string
+	Kind         string
+	Price        int64
+	Code         string
+	EntryAt      time.Time
+	CreatedAt    time.Time
+	CanceledAt   *time.Time
+}
+
+// EntrySlot is an entry time and how many tickets are still left for it
+type EntrySlot struct {
+	StartsAt time.Time
+	EndsAt   time.Time
+	Free     int
+}
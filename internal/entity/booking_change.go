@@ -0,0 +1,45 @@
+package entity
+
+import (
+	"strconv"
+	"time"
+)
+
+// BookingChange is one entry of a booking's history: who changed which
+// fields and when. Role is the role of ChangedBy at the time.
+type BookingChange struct {
+	ID        string
+	BookingID string
+	ChangedBy string
+	Role      string
+	Fields    []*FieldChange
+	CreatedAt time.Time
+}
+
+// FieldChange is kept as JSON in the history, values are shown as text
+type FieldChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// Diff lists the fields that differ between two versions of a booking
+func (m *BookingRecord) Diff(to *BookingRecord) []*FieldChange {
+	var fields []*FieldChange
+	add := func(field, from, to string) {
+		if from != to {
+			fields = append(fields, &FieldChange{Field: field, From: from, To: to})
+		}
+	}
+	number := func(n int64) string { return strconv.FormatInt(n, 10) }
+
+	add("will_arrive", m.WillArrive, to.WillArrive)
+	add("will_leave", m.WillLeave, to.WillLeave)
+	add("number_of_people", number(m.NumberOfPeople), number(to.NumberOfPeople))
+	add("state", m.State, to.State)
+	add("price", number(m.Price), number(to.Price))
+	add("discount", number(m.Discount), number(to.Discount))
+	add("points_discount", number(m.PointsDiscount), number(to.PointsDiscount))
+
+	return fields
+}
@@ -0,0 +1,53 @@
+package entity
+
+import "time"
+
+const (
+	BookingStateConfirmed = "confirmed"
+	BookingStateCheckedIn = "checked_in"
+	BookingStateNoShow    = "no_show"
+	BookingStateCanceled  = "canceled"
+	BookingStateCompleted = "completed"
+)
+
+// BookingRecord is the gateway's copy of a booking made through it. Price
+// is quoted before Discount and PointsDiscount, all in the smallest currency
+// unit. Points are the loyalty points spent on it.
+type BookingRecord struct {
+	ID              string
+	Category        string
+	UserID          string
+	EstablishmentID string
+	WillArrive      string
+	WillLeave       string
+	ArriveAt        *time.Time
+	LeaveAt         *time.Time
+	NumberOfPeople  int64
+	Price           int64
+	Discount        int64
+	PromoCode       string
+	Points          int64
+	PointsDiscount  int64
+	State           string
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// BookingSearch filters the booking records. Empty fields do not filter,
+// AfterValue and AfterID are the sort value and id of the last record of
+// the previous page.
+type BookingSearch struct {
+	Category         string
+	UserIDs          []string
+	EstablishmentIDs []string
+	States           []string
+	ArriveFrom       *time.Time
+	ArriveTo         *time.Time
+	CreatedFrom      *time.Time
+	CreatedTo        *time.Time
+	SortBy           string
+	Desc             bool
+	AfterValue       string
+	AfterID          string
+	Limit            uint64
+}
@@ -0,0 +1,26 @@
+package entity
+
+import "time"
+
+// DashboardDay sums up one calendar day of an owner's establishments.
+// Bookings and Guests count the stays that take up the day, Canceled the
+// canceled bookings that were due to arrive on it.
+type DashboardDay struct {
+	Date       time.Time
+	Bookings   int
+	Guests     int64
+	Arrivals   int
+	Departures int
+	Canceled   int
+}
+
+// Dashboard is the owner's view over a date range. Booked and Canceled
+// count the bookings due to arrive in the range, today is taken in the
+// booking time zone whatever the range is.
+type Dashboard struct {
+	Days            []*DashboardDay
+	Booked          int
+	Canceled        int
+	ArrivalsToday   int
+	DeparturesToday int
+}
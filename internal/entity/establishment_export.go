@@ -0,0 +1,33 @@
+package entity
+
+import "time"
+
+const JobKindEstablishmentSnapshot = "establishment_snapshot"
+
+// ExportFilter narrows an export down to some establishments, empty fields
+// do not filter
+type ExportFilter struct {
+	City      string  `json:"city,omitempty"`
+	Country   string  `json:"country,omitempty"`
+	OwnerID   string  `json:"owner_id,omitempty"`
+	MinRating float64 `json:"min_rating,omitempty"`
+}
+
+// EstablishmentSnapshot is an export of a category written to the media
+// storage every night. Each night gets its own object, LastObject is the
+// latest of them.
+type EstablishmentSnapshot struct {
+	ID       string
+	Category string
+	Format   string
+	Filter   ExportFilter
+	OwnerID  string
+	// NextRunAt is the night the snapshot is planned for next
+	NextRunAt  time.Time
+	LastRunAt  *time.Time
+	LastObject string
+	LastRows   int
+	LastError  string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
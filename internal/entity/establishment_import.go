@@ -0,0 +1,86 @@
+package entity
+
+import "time"
+
+const (
+	ImportStatusPending = "pending"
+	ImportStatusRunning = "running"
+	ImportStatusDone    = "done"
+
+	ImportFormatCSV    = "csv"
+	ImportFormatNDJSON = "ndjson"
+
+	JobKindEstablishmentImport = "establishment_import"
+)
+
+// ImportFormats lists the file formats establishments are imported from
+var ImportFormats = []string{ImportFormatCSV, ImportFormatNDJSON}
+
+// EstablishmentImport is a file of hotels, restaurants or attractions being
+// created or updated in bulk. Rows that passed validation wait in Rows and
+// are worked through from NextRow on, the ones that did not are counted as
+// processed and failed from the start. A dry run goes through every row without writing
+// anything, counting what would be created and updated.
+type EstablishmentImport struct {
+	ID       string
+	Category string
+	Format   string
+	OwnerID  string
+	// Locale is the language Errors are written in
+	Locale  string
+	DryRun  bool
+	Status  string
+	Rows    []*ImportRow
+	NextRow int
+	Total   int
+	// Invalid is how many rows failed validation, they are the first
+	// Invalid of Errors
+	Invalid   int
+	Processed int
+	Created   int
+	Updated   int
+	Failed    int
+	Errors    []*ImportRowError
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// ImportRow is one establishment of an import. ExternalRef is the id the
+// establishment has wherever the file came from, importing the same ref
+// again updates the establishment instead of creating another one.
+type ImportRow struct {
+	Line          int      `json:"line"`
+	ExternalRef   string   `json:"external_ref"`
+	OwnerID       string   `json:"owner_id,omitempty"`
+	Name          string   `json:"name"`
+	Description   string   `json:"description"`
+	Rating        float64  `json:"rating"`
+	ContactNumber string   `json:"contact_number"`
+	LicenceUrl    string   `json:"licence_url"`
+	WebsiteUrl    string   `json:"website_url"`
+	OpeningHours  string   `json:"opening_hours,omitempty"`
+	Address       string   `json:"address"`
+	Latitude      float64  `json:"latitude"`
+	Longitude     float64  `json:"longitude"`
+	Country       string   `json:"country"`
+	City          string   `json:"city"`
+	StateProvince string   `json:"state_province"`
+	Tags          []string `json:"tags,omitempty"`
+}
+
+// ImportRowError is why a row of an import was not imported. Line is the
+// line of the file it is on, counting a CSV header.
+type ImportRowError struct {
+	Line        int    `json:"line"`
+	ExternalRef string `json:"external_ref,omitempty"`
+	Error       string `json:"error"`
+}
+
+// ImportRef ties the external ref of an imported establishment to its id
+type ImportRef struct {
+	Category        string
+	ExternalRef     string
+	EstablishmentID string
+	ImportID        string
+	UpdatedAt       time.Time
+}
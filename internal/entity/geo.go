@@ -0,0 +1,39 @@
+package entity
+
+// GeoPlace is an establishment in the geo index with what a search result
+// shows of it
+type GeoPlace struct {
+	Category        string  `json:"category"`
+	EstablishmentID string  `json:"establishment_id"`
+	Name            string  `json:"name"`
+	Address         string  `json:"address"`
+	City            string  `json:"city"`
+	Thumbnail       string  `json:"thumbnail"`
+	Rating          float64 `json:"rating"`
+	Latitude        float64 `json:"latitude"`
+	Longitude       float64 `json:"longitude"`
+}
+
+// GeoQuery looks within RadiusKm of Latitude, Longitude, or inside Box
+// when it is set. Distances are measured from Latitude, Longitude either
+// way. Empty Categories means all of them.
+type GeoQuery struct {
+	Categories []string
+	Latitude   float64
+	Longitude  float64
+	RadiusKm   float64
+	Box        *GeoBox
+	Limit      int
+}
+
+type GeoBox struct {
+	MinLatitude  float64
+	MinLongitude float64
+	MaxLatitude  float64
+	MaxLongitude float64
+}
+
+type GeoHit struct {
+	Place      *GeoPlace
+	DistanceKm float64
+}
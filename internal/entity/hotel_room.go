@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+// HotelRooms is how many rooms a hotel lets each night. A booking takes one
+// room whatever its party size.
+type HotelRooms struct {
+	HotelID   string
+	Rooms     int
+	UpdatedAt time.Time
+}
+
+// RoomHold takes a room of a hotel for a booking on each night from
+// FirstNight up to LeaveDay, the day the guest leaves
+type RoomHold struct {
+	HotelID    string
+	BookingID  string
+	FirstNight time.Time
+	LeaveDay   time.Time
+	CreatedAt  time.Time
+}
@@ -0,0 +1,36 @@
+package entity
+
+import "time"
+
+// Itinerary is a day by day plan of visits in one city. Days are kept as
+// JSON, hence the tags.
+type Itinerary struct {
+	ID        string
+	UserID    string
+	Name      string
+	City      string
+	People    int64
+	Days      []*ItineraryDay
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type ItineraryDay struct {
+	Date  string           `json:"date"`
+	Stops []*ItineraryStop `json:"stops"`
+}
+
+// ItineraryStop is one visit. WalkKm and WalkMinutes are the way there
+// from the stop before, zero for the first stop of a day.
+type ItineraryStop struct {
+	Category        string    `json:"category"`
+	EstablishmentID string    `json:"establishment_id"`
+	Name            string    `json:"name"`
+	Address         string    `json:"address"`
+	Latitude        float64   `json:"latitude"`
+	Longitude       float64   `json:"longitude"`
+	StartsAt        time.Time `json:"starts_at"`
+	EndsAt          time.Time `json:"ends_at"`
+	WalkKm          float64   `json:"walk_km"`
+	WalkMinutes     int       `json:"walk_minutes"`
+}
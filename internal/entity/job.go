@@ -0,0 +1,28 @@
+package entity
+
+import "time"
+
+const (
+	JobStatusPending  = "pending"
+	JobStatusDone     = "done"
+	JobStatusFailed   = "failed"
+	JobStatusCanceled = "canceled"
+
+	JobKindBookingReminder = "booking_reminder"
+	JobKindBookingNoShow   = "booking_no_show"
+	JobKindWaitlistOffer   = "waitlist_offer"
+	JobKindBookingComplete = "booking_complete"
+)
+
+type Job struct {
+	ID        string
+	Kind      string
+	BookingID string
+	Payload   map[string]string
+	RunAt     time.Time
+	Status    string
+	Attempts  int
+	LastError string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -0,0 +1,25 @@
+package entity
+
+import "time"
+
+const (
+	LoyaltyKindEarn   = "earn"
+	LoyaltyKindRedeem = "redeem"
+	LoyaltyKindExpire = "expire"
+	LoyaltyKindAdjust = "adjust"
+)
+
+// LoyaltyEntry is one line of the append-only points ledger. Points are
+// positive when credited and negative when debited, ExpiresAt is only set
+// on earned points.
+type LoyaltyEntry struct {
+	ID        string
+	UserID    string
+	Kind      string
+	Points    int64
+	BookingID string
+	Reason    string
+	CreatedBy string
+	ExpiresAt *time.Time
+	CreatedAt time.Time
+}
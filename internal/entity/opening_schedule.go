@@ -0,0 +1,16 @@
+package entity
+
+import (
+	"time"
+
+	"Booking/api-service-booking/internal/pkg/openinghours"
+)
+
+// OpeningSchedule is the weekly opening schedule of an establishment. It
+// takes the place of the plain OpeningHours a restaurant has.
+type OpeningSchedule struct {
+	Category        string
+	EstablishmentID string
+	Schedule        *openinghours.Schedule
+	UpdatedAt       time.Time
+}
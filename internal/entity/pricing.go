@@ -0,0 +1,26 @@
+package entity
+
+import "time"
+
+// Rate is the base price of an establishment: per night for hotels, per
+// guest for restaurants and per visitor for attractions without ticket types
+type Rate struct {
+	Category        string
+	EstablishmentID string
+	Price           int64
+	UpdatedAt       time.Time
+}
+
+// Quote is what a booking costs, in the smallest currency unit. Discount
+// comes from PromoCode and PointsDiscount from spending Points.
+type Quote struct {
+	Price          int64
+	Discount       int64
+	PromoCode      string
+	Points         int64
+	PointsDiscount int64
+}
+
+func (q *Quote) Total() int64 {
+	return q.Price - q.Discount - q.PointsDiscount
+}
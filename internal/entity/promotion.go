@@ -0,0 +1,60 @@
+package entity
+
+import "time"
+
+const (
+	PromoKindPercent = "percent"
+	PromoKindFixed   = "fixed"
+)
+
+// Promotion is a promo code campaign. Empty Category and City and zero
+// limits mean no restriction.
+type Promotion struct {
+	ID               string
+	Code             string
+	Description      string
+	Kind             string
+	Value            int64
+	Category         string
+	City             string
+	FirstBookingOnly bool
+	MinPrice         int64
+	MaxRedemptions   int
+	PerUserLimit     int
+	Redemptions      int
+	StartsAt         time.Time
+	EndsAt           time.Time
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+// Discount is what the promotion takes off price, never more than price
+func (p *Promotion) Discount(price int64) int64 {
+	discount := p.Value
+	if p.Kind == PromoKindPercent {
+		discount = price * p.Value / 100
+	}
+	if discount > price {
+		return price
+	}
+	return discount
+}
+
+type PromoRedemption struct {
+	ID           string
+	PromotionID  string
+	UserID       string
+	BookingID    string
+	Discount     int64
+	FirstBooking bool
+	CreatedAt    time.Time
+}
+
+// PromoContext is the booking a promo code is checked against
+type PromoContext struct {
+	UserID       string
+	Category     string
+	City         string
+	Price        int64
+	FirstBooking bool
+}
@@ -0,0 +1,30 @@
+package entity
+
+import "time"
+
+type RestaurantTable struct {
+	ID           string
+	RestaurantID string
+	Name         string
+	Capacity     int
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+type TableReservation struct {
+	ID           string
+	BookingID    string
+	TableID      string
+	RestaurantID string
+	PartySize    int
+	StartsAt     time.Time
+	EndsAt       time.Time
+	CreatedAt    time.Time
+}
+
+// TimeSlot is a seating start time and how many fitting tables are free
+type TimeSlot struct {
+	StartsAt   time.Time
+	EndsAt     time.Time
+	FreeTables int
+}
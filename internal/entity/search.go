@@ -0,0 +1,135 @@
+package entity
+
+import "time"
+
+// SearchDocument is what establishment search keeps of an establishment.
+// The Key fields are the folded forms queries are matched against.
+type SearchDocument struct {
+	Category        string
+	EstablishmentID string
+	Name            string
+	Description     string
+	City            string
+	Address         string
+	Thumbnail       string
+	Rating          float64
+	Latitude        float64
+	Longitude       float64
+	NameKey         string
+	CityKey         string
+	DescriptionKey  string
+	// OpeningHours is the plain "HH:MM-HH:MM" of a restaurant, used when
+	// it has no OpeningSchedule
+	OpeningHours string
+	// CreatedAt is when the establishment was created, nil if the
+	// establishment service did not say
+	CreatedAt *time.Time
+	UpdatedAt time.Time
+}
+
+type SearchHit struct {
+	Document *SearchDocument
+	Score    float64
+}
+
+// EstablishmentSearch is a fuzzy search over name, city and description.
+// Empty Categories means all of them, Tags keeps the establishments that
+// have all of the tags.
+type EstablishmentSearch struct {
+	Query      string
+	Categories []string
+	Tags       []string
+	Limit      int
+	Offset     int
+}
+
+const (
+	EstablishmentSortRating     = "rating"
+	EstablishmentSortDistance   = "distance"
+	EstablishmentSortPopularity = "popularity"
+	EstablishmentSortNewest     = "created_at"
+	EstablishmentSortName       = "name"
+)
+
+// EstablishmentList filters and orders the establishments of one
+// category. Zero bounds are not applied, a price bound leaves out
+// establishments without a rate. Only establishments with every one of
+// Tags are kept. Latitude and Longitude are where distance is measured
+// from.
+type EstablishmentList struct {
+	Category  string
+	Cities    []string
+	Tags      []string
+	MinRating float64
+	MinPrice  int64
+	MaxPrice  int64
+	Latitude  float64
+	Longitude float64
+	// OpenIDs, when not nil, keeps only these establishments, those open
+	// at the time asked for
+	OpenIDs []string
+	SortBy  string
+	Desc    bool
+	Limit   int
+	Offset  int
+	// AfterValue and AfterID are the sort value and id of the last item of
+	// the previous page, or of the first item of the next page when Before
+	// is set. They take the place of Offset.
+	AfterValue string
+	AfterID    string
+	Before     bool
+}
+
+// ListedEstablishment is an item of a list page with the value the list is
+// sorted by, as text
+type ListedEstablishment struct {
+	ID        string
+	SortValue string
+}
+
+// MapCluster is a cell of the map at a zoom level with the documents of a
+// list that fall into it, placed at their mean position. The establishment
+// fields are only set for a cell of a single document and are its own.
+type MapCluster struct {
+	X               int
+	Y               int
+	Count           int
+	Latitude        float64
+	Longitude       float64
+	EstablishmentID string
+	Name            string
+	Thumbnail       string
+	Address         string
+	City            string
+	Rating          float64
+}
+
+type Facet struct {
+	Value string
+	Count int
+}
+
+// EstablishmentFacets counts the establishments matching a list by city
+// and by rating. Each facet ignores its own filter, so it shows what
+// choosing another value would give. Ratings counts those rated at least
+// the value, MinPrice and MaxPrice span the rates of the matches. Tags
+// counts the matches having each tag, keeping the tag filter as tags
+// narrow down rather than choose between.
+type EstablishmentFacets struct {
+	Cities   []*Facet
+	Tags     []*Facet
+	Ratings  []*Facet
+	MinPrice int64
+	MaxPrice int64
+}
+
+// EstablishmentPage is one page of a list, IDs in order, with the total
+// number of matches. The cursors point to the pages next to it, empty at
+// either end.
+type EstablishmentPage struct {
+	IDs        []string
+	Total      int
+	Facets     *EstablishmentFacets
+	NextCursor string
+	PrevCursor string
+}
@@ -0,0 +1,11 @@
+package entity
+
+import "time"
+
+// StaffMember lets a user with the staff role check guests in at an
+// establishment
+type StaffMember struct {
+	UserID          string
+	EstablishmentID string
+	CreatedAt       time.Time
+}
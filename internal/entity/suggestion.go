@@ -0,0 +1,17 @@
+package entity
+
+const (
+	SuggestionTypeCity     = "city"
+	SuggestionTypeCategory = "category"
+	SuggestionTypeQuery    = "query"
+)
+
+// Suggestion is one type-ahead entry. Type is an establishment category or
+// one of the SuggestionType values, ID is the establishment id and for the
+// others the text to search with. Weight orders entries matching equally.
+type Suggestion struct {
+	Type   string
+	ID     string
+	Text   string
+	Weight float64
+}
@@ -0,0 +1,23 @@
+package entity
+
+import "time"
+
+const (
+	TagKindAmenity = "amenity"
+	TagKindCuisine = "cuisine"
+)
+
+// TagKinds lists the kinds of tags the taxonomy has
+var TagKinds = []string{TagKindAmenity, TagKindCuisine}
+
+// Tag is an entry of the taxonomy establishments are tagged from, such as
+// wifi or halal. Categories are the establishment categories it applies
+// to, empty for all of them.
+type Tag struct {
+	Slug       string
+	Kind       string
+	Name       string
+	Categories []string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+// Translation is the name and description of an establishment in one
+// locale. An empty field falls back to the default language, which is
+// what the establishment itself holds.
+type Translation struct {
+	Category        string
+	EstablishmentID string
+	Locale          string
+	Name            string
+	Description     string
+	UpdatedAt       time.Time
+}
@@ -0,0 +1,39 @@
+package entity
+
+import "time"
+
+const (
+	TripStatusOpen        = "open"
+	TripStatusCheckingOut = "checking_out"
+	TripStatusBooked      = "booked"
+
+	JobKindTripCheckout = "trip_checkout"
+)
+
+// Trip is a basket of bookings checked out together. Items can only be
+// added or removed while it is open.
+type Trip struct {
+	ID        string
+	UserID    string
+	Name      string
+	Status    string
+	Items     []*TripItem
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// TripItem is one booking of a trip. BookingID is given out when a
+// checkout starts and is kept once the trip is booked.
+type TripItem struct {
+	ID              string
+	TripID          string
+	Category        string
+	EstablishmentID string
+	WillArrive      string
+	WillLeave       string
+	NumberOfPeople  int64
+	Tickets         map[string]int
+	PromoCode       string
+	BookingID       string
+	CreatedAt       time.Time
+}
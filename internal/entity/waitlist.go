@@ -0,0 +1,40 @@
+package entity
+
+import "time"
+
+const (
+	WaitlistStatusWaiting = "waiting"
+	WaitlistStatusOffered = "offered"
+	WaitlistStatusClaimed = "claimed"
+	WaitlistStatusExpired = "expired"
+	WaitlistStatusLeft    = "left"
+)
+
+// WaitlistEntry is a place in line for a sold out date range. While an
+// offer is open the freed capacity is held under the entry id, which then
+// becomes the id of the booking when the offer is claimed.
+type WaitlistEntry struct {
+	ID              string
+	Category        string
+	EstablishmentID string
+	UserID          string
+	WillArrive      string
+	WillLeave       string
+	ArriveAt        time.Time
+	LeaveAt         time.Time
+	NumberOfPeople  int64
+	Tickets         map[string]int
+	Status          string
+	OfferExpiresAt  *time.Time
+	// Position is the place in line of a waiting entry, counted from 1
+	Position  int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// WaitlistDemand is how many entries and people wait for one arrival date
+type WaitlistDemand struct {
+	Date    string
+	Entries int
+	People  int64
+}
@@ -9,6 +9,8 @@ var (
 	ErrorNotFound       = NewErrNotFound("object")
 	ErrorInvalidOTPCode = errors.New("code is invalid")
 	ErrorOTPExpired     = errors.New("one time password has expired")
+	ErrorNotAvailable   = errors.New("no free capacity for the requested time")
+	ErrorLimitReached   = errors.New("usage limit reached")
 )
 
 // error not found
@@ -47,6 +49,10 @@ func (e ErrValidation) Error() string {
 	return e.Err.Error()
 }
 
+func (e ErrValidation) Unwrap() error {
+	return e.Err
+}
+
 func NewErrValidation() *ErrValidation {
 	return &ErrValidation{Errors: make(map[string]string)}
 }
@@ -60,6 +66,10 @@ func (e ErrBadRequest) Error() string {
 	return e.Err.Error()
 }
 
+func (e ErrBadRequest) Unwrap() error {
+	return e.Err
+}
+
 func NewErrBadRequest(err error) *ErrBadRequest {
 	return &ErrBadRequest{err}
 }
@@ -0,0 +1,393 @@
+package postgresql
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+
+	"Booking/api-service-booking/internal/entity"
+	errorspkg "Booking/api-service-booking/internal/errors"
+	"Booking/api-service-booking/internal/infrastructure/repository/postgresql/repo"
+	"Booking/api-service-booking/internal/pkg/postgres"
+)
+
+type attractionTicketRepo struct {
+	typeTable     string
+	settingsTable string
+	tableName     string
+	db            *postgres.PostgresDB
+}
+
+func NewAttractionTicketRepo(db *postgres.PostgresDB) repo.AttractionTicketRepo {
+	return &attractionTicketRepo{
+		typeTable:     "attraction_ticket_types",
+		settingsTable: "attraction_entry_settings",
+		tableName:     "attraction_tickets",
+		db:            db,
+	}
+}
+
+func (r *attractionTicketRepo) CreateType(ctx context.Context, m *entity.TicketType) error {
+	clauses := map[string]interface{}{
+		"id":            m.ID,
+		"attraction_id": m.AttractionID,
+		"kind":          m.Kind,
+		"price":         m.Price,
+		"created_at":    m.CreatedAt,
+		"updated_at":    m.UpdatedAt,
+	}
+
+	sqlStr, args, err := r.db.Sq.Builder.Insert(r.typeTable).SetMap(clauses).ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.typeTable+" create")
+	}
+
+	if _, err = r.db.Exec(ctx, sqlStr, args...); err != nil {
+		return r.db.Error(err)
+	}
+	return nil
+}
+
+func (r *attractionTicketRepo) ListTypes(ctx context.Context, attractionID string) ([]*entity.TicketType, error) {
+	sqlStr, args, err := r.db.Sq.Builder.
+		Select(
+			"id",
+			"attraction_id",
+			"kind",
+			"price",
+			"created_at",
+			"updated_at",
+		).
+		From(r.typeTable).
+		Where(r.db.Sq.And(
+			r.db.Sq.Equal("attraction_id", attractionID),
+			r.db.Sq.Equal("deleted_at", nil),
+		)).
+		OrderBy("price DESC").
+		ToSql()
+	if err != nil {
+		return nil, r.db.ErrSQLBuild(err, r.typeTable+" list")
+	}
+
+	rows, err := r.db.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, r.db.Error(err)
+	}
+	defer rows.Close()
+
+	var types []*entity.TicketType
+	for rows.Next() {
+		var ticketType entity.TicketType
+		if err = rows.Scan(
+			&ticketType.ID,
+			&ticketType.AttractionID,
+			&ticketType.Kind,
+			&ticketType.Price,
+			&ticketType.CreatedAt,
+			&ticketType.UpdatedAt,
+		); err != nil {
+			return nil, r.db.Error(err)
+		}
+		types = append(types, &ticketType)
+	}
+
+	return types, rows.Err()
+}
+
+func (r *attractionTicketRepo) DeleteType(ctx context.Context, id string, deletedAt time.Time) error {
+	sqlStr, args, err := r.db.Sq.Builder.
+		Update(r.typeTable).
+		Set("deleted_at", deletedAt).
+		Where(r.db.Sq.And(
+			r.db.Sq.Equal("id", id),
+			r.db.Sq.Equal("deleted_at", nil),
+		)).
+		ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.typeTable+" delete")
+	}
+
+	commandTag, err := r.db.Exec(ctx, sqlStr, args...)
+	if err != nil {
+		return r.db.Error(err)
+	}
+	if commandTag.RowsAffected() == 0 {
+		return r.db.Error(pgx.ErrNoRows)
+	}
+	return nil
+}
+
+func (r *attractionTicketRepo) SaveSettings(ctx context.Context, m *entity.EntrySettings) error {
+	sqlStr, args, err := r.db.Sq.Builder.
+		Insert(r.settingsTable).
+		SetMap(map[string]interface{}{
+			"attraction_id": m.AttractionID,
+			"opening_hours": m.OpeningHours,
+			"slot_minutes":  int(m.SlotLength / time.Minute),
+			"capacity":      m.Capacity,
+			"updated_at":    m.UpdatedAt,
+		}).
+		Suffix("ON CONFLICT (attraction_id) DO UPDATE SET opening_hours = EXCLUDED.opening_hours, slot_minutes = EXCLUDED.slot_minutes, capacity = EXCLUDED.capacity, updated_at = EXCLUDED.updated_at").
+		ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.settingsTable+" save")
+	}
+
+	if _, err = r.db.Exec(ctx, sqlStr, args...); err != nil {
+		return r.db.Error(err)
+	}
+	return nil
+}
+
+func (r *attractionTicketRepo) GetSettings(ctx context.Context, attractionID string) (*entity.EntrySettings, error) {
+	sqlStr, args, err := r.db.Sq.Builder.
+		Select(
+			"attraction_id",
+			"opening_hours",
+			"slot_minutes",
+			"capacity",
+			"updated_at",
+		).
+		From(r.settingsTable).
+		Where(r.db.Sq.Equal("attraction_id", attractionID)).
+		ToSql()
+	if err != nil {
+		return nil, r.db.ErrSQLBuild(err, r.settingsTable+" get")
+	}
+
+	var (
+		settings    entity.EntrySettings
+		slotMinutes int
+	)
+	if err = r.db.QueryRow(ctx, sqlStr, args...).Scan(
+		&settings.AttractionID,
+		&settings.OpeningHours,
+		&slotMinutes,
+		&settings.Capacity,
+		&settings.UpdatedAt,
+	); err != nil {
+		return nil, r.db.Error(err)
+	}
+	settings.SlotLength = time.Duration(slotMinutes) * time.Minute
+
+	return &settings, nil
+}
+
+// countByEntryQuery counts the active tickets of each entry time in
+// [from, to)
+func (r *attractionTicketRepo) countByEntryQuery(attractionID string, from, to time.Time) sq.SelectBuilder {
+	return r.db.Sq.Builder.
+		Select("entry_at", "COUNT(*)").
+		From(r.tableName).
+		Where(r.db.Sq.And(
+			r.db.Sq.Equal("attraction_id", attractionID),
+			r.db.Sq.Equal("canceled_at", nil),
+			r.db.Sq.Expr("entry_at >= ?", from),
+			r.db.Sq.Lt("entry_at", to),
+		)).
+		GroupBy("entry_at")
+}
+
+func (r *attractionTicketRepo) CountByEntry(ctx context.Context, attractionID string, from, to time.Time) (map[int64]int, error) {
+	sqlStr, args, err := r.countByEntryQuery(attractionID, from, to).ToSql()
+	if err != nil {
+		return nil, r.db.ErrSQLBuild(err, r.tableName+" count")
+	}
+
+	rows, err := r.db.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, r.db.Error(err)
+	}
+	defer rows.Close()
+
+	counts := make(map[int64]int)
+	for rows.Next() {
+		var (
+			entryAt time.Time
+			count   int
+		)
+		if err = rows.Scan(&entryAt, &count); err != nil {
+			return nil, r.db.Error(err)
+		}
+		counts[entryAt.Unix()] = count
+	}
+
+	return counts, rows.Err()
+}
+
+func (r *attractionTicketRepo) Issue(ctx context.Context, tickets []*entity.Ticket, capacity int) error {
+	if len(tickets) == 0 {
+		return nil
+	}
+	first := tickets[0]
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return r.db.Error(err)
+	}
+	defer tx.Rollback(ctx)
+
+	// the settings row serialises concurrent sales of the same attraction
+	lockStr, lockArgs, err := r.db.Sq.Builder.
+		Select("attraction_id").
+		From(r.settingsTable).
+		Where(r.db.Sq.Equal("attraction_id", first.AttractionID)).
+		Suffix("FOR UPDATE").
+		ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.settingsTable+" lock")
+	}
+	if _, err = tx.Exec(ctx, lockStr, lockArgs...); err != nil {
+		return r.db.Error(err)
+	}
+
+	// tickets of a booking being moved are given up before counting, the
+	// rollback brings them back when the new slot is full
+	cancelStr, cancelArgs, err := r.db.Sq.Builder.
+		Update(r.tableName).
+		Set("canceled_at", first.CreatedAt).
+		Where(r.db.Sq.And(
+			r.db.Sq.Equal("booking_id", first.BookingID),
+			r.db.Sq.Equal("canceled_at", nil),
+		)).
+		ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.tableName+" cancel")
+	}
+	if _, err = tx.Exec(ctx, cancelStr, cancelArgs...); err != nil {
+		return r.db.Error(err)
+	}
+
+	countStr, countArgs, err := r.db.Sq.Builder.
+		Select("COUNT(*)").
+		From(r.tableName).
+		Where(r.db.Sq.And(
+			r.db.Sq.Equal("attraction_id", first.AttractionID),
+			r.db.Sq.Equal("entry_at", first.EntryAt),
+			r.db.Sq.Equal("canceled_at", nil),
+		)).
+		ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.tableName+" count")
+	}
+
+	var sold int
+	if err = tx.QueryRow(ctx, countStr, countArgs...).Scan(&sold); err != nil {
+		return r.db.Error(err)
+	}
+	if sold+len(tickets) > capacity {
+		return errorspkg.ErrorNotAvailable
+	}
+
+	insert := r.db.Sq.Builder.
+		Insert(r.tableName).
+		Columns(
+			"id",
+			"booking_id",
+			"attraction_id",
+			"ticket_type_id",
+			"kind",
+			"price",
+			"code",
+			"entry_at",
+			"created_at",
+		)
+	for _, ticket := range tickets {
+		insert = insert.Values(
+			ticket.ID,
+			ticket.BookingID,
+			ticket.AttractionID,
+			ticket.TicketTypeID,
+			ticket.Kind,
+			ticket.Price,
+			ticket.Code,
+			ticket.EntryAt,
+			ticket.CreatedAt,
+		)
+	}
+
+	insertStr, insertArgs, err := insert.ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.tableName+" create")
+	}
+	if _, err = tx.Exec(ctx, insertStr, insertArgs...); err != nil {
+		return r.db.Error(err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return r.db.Error(err)
+	}
+	return nil
+}
+
+func (r *attractionTicketRepo) ListByBooking(ctx context.Context, bookingID string) ([]*entity.Ticket, error) {
+	sqlStr, args, err := r.db.Sq.Builder.
+		Select(
+			"id",
+			"booking_id",
+			"attraction_id",
+			"ticket_type_id",
+			"kind",
+			"price",
+			"code",
+			"entry_at",
+			"created_at",
+			"canceled_at",
+		).
+		From(r.tableName).
+		Where(r.db.Sq.Equal("booking_id", bookingID)).
+		OrderBy("price DESC", "code").
+		ToSql()
+	if err != nil {
+		return nil, r.db.ErrSQLBuild(err, r.tableName+" list")
+	}
+
+	rows, err := r.db.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, r.db.Error(err)
+	}
+	defer rows.Close()
+
+	var tickets []*entity.Ticket
+	for rows.Next() {
+		var ticket entity.Ticket
+		if err = rows.Scan(
+			&ticket.ID,
+			&ticket.BookingID,
+			&ticket.AttractionID,
+			&ticket.TicketTypeID,
+			&ticket.Kind,
+			&ticket.Price,
+			&ticket.Code,
+			&ticket.EntryAt,
+			&ticket.CreatedAt,
+			&ticket.CanceledAt,
+		); err != nil {
+			return nil, r.db.Error(err)
+		}
+		tickets = append(tickets, &ticket)
+	}
+
+	return tickets, rows.Err()
+}
+
+func (r *attractionTicketRepo) CancelByBooking(ctx context.Context, bookingID string, canceledAt time.Time) error {
+	sqlStr, args, err := r.db.Sq.Builder.
+		Update(r.tableName).
+		Set("canceled_at", canceledAt).
+		Where(r.db.Sq.And(
+			r.db.Sq.Equal("booking_id", bookingID),
+			r.db.Sq.Equal("canceled_at", nil),
+		)).
+		ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.tableName+" cancel")
+	}
+
+	if _, err = r.db.Exec(ctx, sqlStr, args...); err != nil {
+		return r.db.Error(err)
+	}
+	return nil
+}
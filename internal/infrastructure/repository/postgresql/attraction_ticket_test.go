@@ -0,0 +1,103 @@
+package postgresql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Booking/api-service-booking/internal/entity"
+)
+
+func TestAttractionTicketCountByEntryQuery(t *testing.T) {
+	r := &attractionTicketRepo{tableName: "attraction_tickets", db: queryDB()}
+
+	from := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	sqlStr, args, err := r.countByEntryQuery(uuid.NewString(), from, from.Add(9*time.Hour)).ToSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkArgs(t, sqlStr, args)
+}
+
+func TestAttractionTicketRepoCountByEntry(t *testing.T) {
+	db := testDB(t)
+	r := NewAttractionTicketRepo(db)
+	ctx := context.Background()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	attractionID := uuid.NewString()
+	ticketType := entity.TicketType{
+		ID:           uuid.NewString(),
+		AttractionID: attractionID,
+		Kind:         entity.TicketKindAdult,
+		Price:        50000,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if err := r.CreateType(ctx, &ticketType); err != nil {
+		t.Fatal(err)
+	}
+	cleanup(t, db, "attraction_ticket_types", "id", ticketType.ID)
+	settings := entity.EntrySettings{
+		AttractionID: attractionID,
+		OpeningHours: "09:00-18:00",
+		SlotLength:   time.Hour,
+		Capacity:     10,
+		UpdatedAt:    now,
+	}
+	if err := r.SaveSettings(ctx, &settings); err != nil {
+		t.Fatal(err)
+	}
+	cleanup(t, db, "attraction_entry_settings", "attraction_id", attractionID)
+	cleanup(t, db, "attraction_tickets", "attraction_id", attractionID)
+
+	day := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	sales := []struct {
+		entryAt time.Time
+		tickets int
+	}{
+		{day.Add(8 * time.Hour), 1},
+		{day.Add(9 * time.Hour), 2},
+		{day.Add(10 * time.Hour), 3},
+		{day.Add(18 * time.Hour), 1},
+	}
+	for i, sale := range sales {
+		bookingID := uuid.NewString()
+		var tickets []*entity.Ticket
+		for j := 0; j < sale.tickets; j++ {
+			tickets = append(tickets, &entity.Ticket{
+				ID:           uuid.NewString(),
+				BookingID:    bookingID,
+				AttractionID: attractionID,
+				TicketTypeID: ticketType.ID,
+				Kind:         ticketType.Kind,
+				Price:        ticketType.Price,
+				Code:         uuid.NewString()[:8] + string(rune('a'+i)) + string(rune('a'+j)),
+				EntryAt:      sale.entryAt,
+				CreatedAt:    now,
+			})
+		}
+		if err := r.Issue(ctx, tickets, settings.Capacity); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	counts, err := r.CountByEntry(ctx, attractionID, day.Add(9*time.Hour), day.Add(18*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[int64]int{
+		day.Add(9 * time.Hour).Unix():  2,
+		day.Add(10 * time.Hour).Unix(): 3,
+	}
+	if len(counts) != len(want) {
+		t.Fatalf("counts = %v, want %v", counts, want)
+	}
+	for entryAt, count := range want {
+		if counts[entryAt] != count {
+			t.Errorf("count at %d = %d, want %d", entryAt, counts[entryAt], count)
+		}
+	}
+}
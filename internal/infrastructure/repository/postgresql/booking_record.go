@@ -0,0 +1,429 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+
+	"Booking/api-service-booking/internal/entity"
+	"Booking/api-service-booking/internal/infrastructure/repository/postgresql/repo"
+	"Booking/api-service-booking/internal/pkg/postgres"
+)
+
+type bookingRecordRepo struct {
+	tableName    string
+	changesTable string
+	db           *postgres.PostgresDB
+}
+
+func NewBookingRecordRepo(db *postgres.PostgresDB) repo.BookingRecordRepo {
+	return &bookingRecordRepo{
+		tableName:    "booking_records",
+		changesTable: "booking_changes",
+		db:           db,
+	}
+}
+
+func (r *bookingRecordRepo) selectQuery() sq.SelectBuilder {
+	return r.db.Sq.Builder.
+		Select(
+			"id",
+			"category",
+			"user_id",
+			"establishment_id",
+			"will_arrive",
+			"will_leave",
+			"arrive_at",
+			"leave_at",
+			"number_of_people",
+			"price",
+			"discount",
+			"promo_code",
+			"points",
+			"points_discount",
+			"state",
+			"created_at",
+			"updated_at",
+		).
+		From(r.tableName)
+}
+
+func (r *bookingRecordRepo) scan(row pgx.Row) (*entity.BookingRecord, error) {
+	var res entity.BookingRecord
+	if err := row.Scan(
+		&res.ID,
+		&res.Category,
+		&res.UserID,
+		&res.EstablishmentID,
+		&res.WillArrive,
+		&res.WillLeave,
+		&res.ArriveAt,
+		&res.LeaveAt,
+		&res.NumberOfPeople,
+		&res.Price,
+		&res.Discount,
+		&res.PromoCode,
+		&res.Points,
+		&res.PointsDiscount,
+		&res.State,
+		&res.CreatedAt,
+		&res.UpdatedAt,
+	); err != nil {
+		return nil, r.db.Error(err)
+	}
+	return &res, nil
+}
+
+func (r *bookingRecordRepo) scanAll(rows pgx.Rows) ([]*entity.BookingRecord, error) {
+	defer rows.Close()
+
+	var records []*entity.BookingRecord
+	for rows.Next() {
+		record, err := r.scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+
+	return records, rows.Err()
+}
+
+func (r *bookingRecordRepo) Get(ctx context.Context, id string) (*entity.BookingRecord, error) {
+	query := r.selectQuery().
+		Where(r.db.Sq.Equal("id", id))
+
+	sqlStr, args, err := query.ToSql()
+	if err != nil {
+		return nil, r.db.ErrSQLBuild(err, r.tableName+" read")
+	}
+
+	return r.scan(r.db.QueryRow(ctx, sqlStr, args...))
+}
+
+func (r *bookingRecordRepo) Create(ctx context.Context, m *entity.BookingRecord) error {
+	clauses := map[string]interface{}{
+		"id":               m.ID,
+		"category":         m.Category,
+		"user_id":          m.UserID,
+		"establishment_id": m.EstablishmentID,
+		"will_arrive":      m.WillArrive,
+		"will_leave":       m.WillLeave,
+		"arrive_at":        m.ArriveAt,
+		"leave_at":         m.LeaveAt,
+		"number_of_people": m.NumberOfPeople,
+		"price":            m.Price,
+		"discount":         m.Discount,
+		"promo_code":       m.PromoCode,
+		"points":           m.Points,
+		"points_discount":  m.PointsDiscount,
+		"state":            m.State,
+		"created_at":       m.CreatedAt,
+		"updated_at":       m.UpdatedAt,
+	}
+
+	sqlStr, args, err := r.db.Sq.Builder.Insert(r.tableName).SetMap(clauses).ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.tableName+" create")
+	}
+
+	if _, err = r.db.Exec(ctx, sqlStr, args...); err != nil {
+		return r.db.Error(err)
+	}
+	return nil
+}
+
+func (r *bookingRecordRepo) Update(ctx context.Context, m *entity.BookingRecord) error {
+	clauses := map[string]interface{}{
+		"establishment_id": m.EstablishmentID,
+		"will_arrive":      m.WillArrive,
+		"will_leave":       m.WillLeave,
+		"arrive_at":        m.ArriveAt,
+		"leave_at":         m.LeaveAt,
+		"number_of_people": m.NumberOfPeople,
+		"price":            m.Price,
+		"discount":         m.Discount,
+		"promo_code":       m.PromoCode,
+		"points":           m.Points,
+		"points_discount":  m.PointsDiscount,
+		"state":            m.State,
+		"updated_at":       m.UpdatedAt,
+	}
+
+	sqlStr, args, err := r.db.Sq.Builder.
+		Update(r.tableName).
+		SetMap(clauses).
+		Where(r.db.Sq.Equal("id", m.ID)).
+		ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.tableName+" update")
+	}
+
+	commandTag, err := r.db.Exec(ctx, sqlStr, args...)
+	if err != nil {
+		return r.db.Error(err)
+	}
+	if commandTag.RowsAffected() == 0 {
+		return r.db.Error(pgx.ErrNoRows)
+	}
+	return nil
+}
+
+func (r *bookingRecordRepo) ChangeState(ctx context.Context, id string, from []string, to string, updatedAt time.Time) (bool, error) {
+	sqlStr, args, err := r.db.Sq.Builder.
+		Update(r.tableName).
+		SetMap(map[string]interface{}{
+			"state":      to,
+			"updated_at": updatedAt,
+		}).
+		Where(r.db.Sq.And(
+			r.db.Sq.Equal("id", id),
+			r.db.Sq.Equal("state", from),
+		)).
+		ToSql()
+	if err != nil {
+		return false, r.db.ErrSQLBuild(err, r.tableName+" change state")
+	}
+
+	commandTag, err := r.db.Exec(ctx, sqlStr, args...)
+	if err != nil {
+		return false, r.db.Error(err)
+	}
+
+	return commandTag.RowsAffected() > 0, nil
+}
+
+func (r *bookingRecordRepo) CountByUser(ctx context.Context, userID string, states []string) (int, error) {
+	sqlStr, args, err := r.db.Sq.Builder.
+		Select("COUNT(*)").
+		From(r.tableName).
+		Where(r.db.Sq.And(
+			r.db.Sq.Equal("user_id", userID),
+			r.db.Sq.Equal("state", states),
+		)).
+		ToSql()
+	if err != nil {
+		return 0, r.db.ErrSQLBuild(err, r.tableName+" count")
+	}
+
+	var count int
+	if err = r.db.QueryRow(ctx, sqlStr, args...).Scan(&count); err != nil {
+		return 0, r.db.Error(err)
+	}
+	return count, nil
+}
+
+func (r *bookingRecordRepo) ListByEstablishments(ctx context.Context, category string, establishmentIDs []string, from, to time.Time) ([]*entity.BookingRecord, error) {
+	sqlStr, args, err := r.listByEstablishmentsQuery(category, establishmentIDs, from, to).ToSql()
+	if err != nil {
+		return nil, r.db.ErrSQLBuild(err, r.tableName+" list by establishments")
+	}
+
+	rows, err := r.db.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, r.db.Error(err)
+	}
+
+	return r.scanAll(rows)
+}
+
+func (r *bookingRecordRepo) listByEstablishmentsQuery(category string, establishmentIDs []string, from, to time.Time) sq.SelectBuilder {
+	return r.selectQuery().
+		Where(r.db.Sq.And(
+			r.db.Sq.Equal("category", category),
+			r.db.Sq.Equal("establishment_id", establishmentIDs),
+			r.db.Sq.Lt("arrive_at", to),
+			r.db.Sq.Expr("COALESCE(leave_at, arrive_at) >= ?", from),
+		)).
+		OrderBy("arrive_at")
+}
+
+func (r *bookingRecordRepo) LastBookedAt(ctx context.Context, category, establishmentID string, userIDs []string) (map[string]time.Time, error) {
+	sqlStr, args, err := r.lastBookedAtQuery(category, establishmentID, userIDs).ToSql()
+	if err != nil {
+		return nil, r.db.ErrSQLBuild(err, r.tableName+" last booked at")
+	}
+
+	rows, err := r.db.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, r.db.Error(err)
+	}
+	defer rows.Close()
+
+	bookedAt := make(map[string]time.Time)
+	for rows.Next() {
+		var (
+			userID    string
+			createdAt time.Time
+		)
+		if err = rows.Scan(&userID, &createdAt); err != nil {
+			return nil, r.db.Error(err)
+		}
+		bookedAt[userID] = createdAt
+	}
+
+	return bookedAt, rows.Err()
+}
+
+func (r *bookingRecordRepo) lastBookedAtQuery(category, establishmentID string, userIDs []string) sq.SelectBuilder {
+	return r.db.Sq.Builder.
+		Select("user_id::text", "MAX(created_at)").
+		From(r.tableName).
+		Where(r.db.Sq.And(
+			r.db.Sq.Equal("category", category),
+			r.db.Sq.Equal("establishment_id", establishmentID),
+			r.db.Sq.Equal("user_id", userIDs),
+		)).
+		GroupBy("user_id")
+}
+
+// bookingSorts maps the fields bookings can be sorted by to their column
+// and the type the cursor value is cast to. Bookings with dates in an
+// unknown format sort first by arrival.
+var bookingSorts = map[string][2]string{
+	"created_at":       {"created_at", "timestamptz"},
+	"updated_at":       {"updated_at", "timestamptz"},
+	"arrive_at":        {"COALESCE(arrive_at, '-infinity')", "timestamptz"},
+	"price":            {"price", "bigint"},
+	"number_of_people": {"number_of_people", "bigint"},
+}
+
+func (r *bookingRecordRepo) Search(ctx context.Context, filter *entity.BookingSearch) ([]*entity.BookingRecord, error) {
+	query, err := r.searchQuery(filter)
+	if err != nil {
+		return nil, err
+	}
+	sqlStr, args, err := query.ToSql()
+	if err != nil {
+		return nil, r.db.ErrSQLBuild(err, r.tableName+" search")
+	}
+
+	rows, err := r.db.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, r.db.Error(err)
+	}
+
+	return r.scanAll(rows)
+}
+
+// searchQuery selects a page of the records that pass filter
+func (r *bookingRecordRepo) searchQuery(filter *entity.BookingSearch) (sq.SelectBuilder, error) {
+	sort, ok := bookingSorts[filter.SortBy]
+	if !ok {
+		return sq.SelectBuilder{}, fmt.Errorf("can't sort bookings by %q", filter.SortBy)
+	}
+
+	where := sq.And{}
+	if filter.Category != "" {
+		where = append(where, r.db.Sq.Equal("category", filter.Category))
+	}
+	if filter.UserIDs != nil {
+		where = append(where, r.db.Sq.Equal("user_id", filter.UserIDs))
+	}
+	if filter.EstablishmentIDs != nil {
+		where = append(where, r.db.Sq.Equal("establishment_id", filter.EstablishmentIDs))
+	}
+	if filter.States != nil {
+		where = append(where, r.db.Sq.Equal("state", filter.States))
+	}
+	if filter.ArriveFrom != nil {
+		where = append(where, sq.GtOrEq{"arrive_at": *filter.ArriveFrom})
+	}
+	if filter.ArriveTo != nil {
+		where = append(where, r.db.Sq.Lt("arrive_at", *filter.ArriveTo))
+	}
+	if filter.CreatedFrom != nil {
+		where = append(where, sq.GtOrEq{"created_at": *filter.CreatedFrom})
+	}
+	if filter.CreatedTo != nil {
+		where = append(where, r.db.Sq.Lt("created_at", *filter.CreatedTo))
+	}
+
+	order := "ASC"
+	if filter.Desc {
+		order = "DESC"
+	}
+	if filter.AfterID != "" {
+		comparison := ">"
+		if filter.Desc {
+			comparison = "<"
+		}
+		where = append(where, r.db.Sq.Expr(
+			fmt.Sprintf("(%s, id) %s (?::%s, ?::uuid)", sort[0], comparison, sort[1]),
+			filter.AfterValue, filter.AfterID,
+		))
+	}
+
+	return r.selectQuery().
+		Where(where).
+		OrderBy(sort[0]+" "+order, "id "+order).
+		Limit(filter.Limit), nil
+}
+
+func (r *bookingRecordRepo) CreateChange(ctx context.Context, m *entity.BookingChange) error {
+	sqlStr, args, err := r.db.Sq.Builder.
+		Insert(r.changesTable).
+		SetMap(map[string]interface{}{
+			"id":         m.ID,
+			"booking_id": m.BookingID,
+			"changed_by": nullString(m.ChangedBy),
+			"role":       m.Role,
+			"fields":     m.Fields,
+			"created_at": m.CreatedAt,
+		}).
+		ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.changesTable+" create")
+	}
+
+	if _, err = r.db.Exec(ctx, sqlStr, args...); err != nil {
+		return r.db.Error(err)
+	}
+	return nil
+}
+
+func (r *bookingRecordRepo) ListChanges(ctx context.Context, bookingID string) ([]*entity.BookingChange, error) {
+	sqlStr, args, err := r.db.Sq.Builder.
+		Select(
+			"id",
+			"booking_id",
+			"COALESCE(changed_by::text, '')",
+			"role",
+			"fields",
+			"created_at",
+		).
+		From(r.changesTable).
+		Where(r.db.Sq.Equal("booking_id", bookingID)).
+		OrderBy("created_at").
+		ToSql()
+	if err != nil {
+		return nil, r.db.ErrSQLBuild(err, r.changesTable+" list")
+	}
+
+	rows, err := r.db.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, r.db.Error(err)
+	}
+	defer rows.Close()
+
+	var changes []*entity.BookingChange
+	for rows.Next() {
+		var change entity.BookingChange
+		if err = rows.Scan(
+			&change.ID,
+			&change.BookingID,
+			&change.ChangedBy,
+			&change.Role,
+			&change.Fields,
+			&change.CreatedAt,
+		); err != nil {
+			return nil, r.db.Error(err)
+		}
+		changes = append(changes, &change)
+	}
+
+	return changes, rows.Err()
+}
@@ -0,0 +1,255 @@
+package postgresql
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Booking/api-service-booking/internal/entity"
+)
+
+var placeholder = regexp.MustCompile(`\$\d+`)
+
+// checkArgs fails unless every placeholder of sqlStr has one plain value
+func checkArgs(t *testing.T, sqlStr string, args []interface{}) {
+	t.Helper()
+	if got := len(placeholder.FindAllString(sqlStr, -1)); got != len(args) {
+		t.Fatalf("%d placeholders for %d args in %s", got, len(args), sqlStr)
+	}
+	for i, arg := range args {
+		if kind := reflect.ValueOf(arg).Kind(); kind == reflect.Slice || kind == reflect.Array {
+			t.Errorf("arg %d is a %T, want a single value", i+1, arg)
+		}
+	}
+}
+
+func TestBookingRecordSearchQuery(t *testing.T) {
+	r := &bookingRecordRepo{tableName: "booking_records", db: queryDB()}
+
+	tests := []struct {
+		name   string
+		filter entity.BookingSearch
+	}{
+		{
+			name:   "first page",
+			filter: entity.BookingSearch{SortBy: "created_at", Limit: 21},
+		},
+		{
+			name: "second page by created_at",
+			filter: entity.BookingSearch{
+				SortBy:     "created_at",
+				AfterValue: "2026-10-19T10:00:00Z",
+				AfterID:    uuid.NewString(),
+				Limit:      21,
+			},
+		},
+		{
+			name: "second page by price, descending and filtered",
+			filter: entity.BookingSearch{
+				Category:   "hotel",
+				UserIDs:    []string{uuid.NewString(), uuid.NewString()},
+				States:     []string{entity.BookingStateCanceled},
+				SortBy:     "price",
+				Desc:       true,
+				AfterValue: "150000",
+				AfterID:    uuid.NewString(),
+				Limit:      21,
+			},
+		},
+		{
+			name: "second page by arrival without a date",
+			filter: entity.BookingSearch{
+				SortBy:     "arrive_at",
+				AfterValue: "-infinity",
+				AfterID:    uuid.NewString(),
+				Limit:      21,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := r.searchQuery(&tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sqlStr, args, err := query.ToSql()
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkArgs(t, sqlStr, args)
+			if tt.filter.AfterID != "" && args[len(args)-1] != tt.filter.AfterID {
+				t.Errorf("last arg = %v, want the cursor id %s", args[len(args)-1], tt.filter.AfterID)
+			}
+		})
+	}
+
+	if _, err := r.searchQuery(&entity.BookingSearch{SortBy: "name"}); err == nil {
+		t.Error("sorting by an unknown field did not fail")
+	}
+}
+
+func TestBookingRecordRepoSearchPages(t *testing.T) {
+	db := testDB(t)
+	r := NewBookingRecordRepo(db)
+	ctx := context.Background()
+
+	establishmentID := uuid.NewString()
+	start := time.Now().UTC().Truncate(time.Second)
+	var ids []string
+	for i := 0; i < 5; i++ {
+		m := entity.BookingRecord{
+			ID:              uuid.NewString(),
+			Category:        "hotel",
+			UserID:          uuid.NewString(),
+			EstablishmentID: establishmentID,
+			WillArrive:      "2026-11-01",
+			WillLeave:       "2026-11-02",
+			State:           entity.BookingStateConfirmed,
+			// two records share a time so the id breaks the tie
+			CreatedAt: start.Add(time.Duration(i/2) * time.Minute),
+			UpdatedAt: start,
+		}
+		if err := r.Create(ctx, &m); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, m.ID)
+	}
+	cleanup(t, db, "booking_records", "id", toInterfaces(ids)...)
+
+	for _, desc := range []bool{false, true} {
+		filter := entity.BookingSearch{
+			EstablishmentIDs: []string{establishmentID},
+			SortBy:           "created_at",
+			Desc:             desc,
+			Limit:            2,
+		}
+		seen := map[string]bool{}
+		var last *entity.BookingRecord
+		for page := 1; ; page++ {
+			records, err := r.Search(ctx, &filter)
+			if err != nil {
+				t.Fatalf("desc %v, page %d: %v", desc, page, err)
+			}
+			for _, record := range records {
+				if seen[record.ID] {
+					t.Fatalf("desc %v, page %d repeats %s", desc, page, record.ID)
+				}
+				if last != nil && outOfOrder(last, record, desc) {
+					t.Fatalf("desc %v, page %d: %s comes after %s", desc, page, record.ID, last.ID)
+				}
+				seen[record.ID] = true
+				last = record
+			}
+			if len(records) < int(filter.Limit) {
+				break
+			}
+			filter.AfterValue = last.CreatedAt.Format(time.RFC3339Nano)
+			filter.AfterID = last.ID
+		}
+		if len(seen) != len(ids) {
+			t.Errorf("desc %v: paged through %d records, want %d", desc, len(seen), len(ids))
+		}
+	}
+}
+
+func TestBookingRecordListByEstablishmentsQuery(t *testing.T) {
+	r := &bookingRecordRepo{tableName: "booking_records", db: queryDB()}
+
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	sqlStr, args, err := r.listByEstablishmentsQuery("hotel", []string{uuid.NewString(), uuid.NewString()}, from, from.AddDate(0, 1, 0)).ToSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkArgs(t, sqlStr, args)
+}
+
+func TestBookingRecordLastBookedAtQuery(t *testing.T) {
+	r := &bookingRecordRepo{tableName: "booking_records", db: queryDB()}
+
+	sqlStr, args, err := r.lastBookedAtQuery("hotel", uuid.NewString(), []string{uuid.NewString(), uuid.NewString()}).ToSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkArgs(t, sqlStr, args)
+}
+
+func TestBookingRecordRepoListByEstablishments(t *testing.T) {
+	db := testDB(t)
+	r := NewBookingRecordRepo(db)
+	ctx := context.Background()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	establishmentID := uuid.NewString()
+	day := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	stays := []struct {
+		arrive, leave time.Time
+		want          bool
+	}{
+		// left before the month
+		{day.AddDate(0, 0, -3), day.AddDate(0, 0, -1), false},
+		// runs into the month
+		{day.AddDate(0, 0, -1), day.AddDate(0, 0, 2), true},
+		{day.AddDate(0, 0, 10), day.AddDate(0, 0, 12), true},
+		// starts after the month
+		{day.AddDate(0, 1, 0), day.AddDate(0, 1, 2), false},
+	}
+	var ids []string
+	want := map[string]bool{}
+	for _, stay := range stays {
+		arrive, leave := stay.arrive, stay.leave
+		m := entity.BookingRecord{
+			ID:              uuid.NewString(),
+			Category:        "hotel",
+			UserID:          uuid.NewString(),
+			EstablishmentID: establishmentID,
+			WillArrive:      arrive.Format("2006-01-02"),
+			WillLeave:       leave.Format("2006-01-02"),
+			ArriveAt:        &arrive,
+			LeaveAt:         &leave,
+			State:           entity.BookingStateConfirmed,
+			CreatedAt:       now,
+			UpdatedAt:       now,
+		}
+		if err := r.Create(ctx, &m); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, m.ID)
+		if stay.want {
+			want[m.ID] = true
+		}
+	}
+	cleanup(t, db, "booking_records", "id", toInterfaces(ids)...)
+
+	records, err := r.ListByEstablishments(ctx, "hotel", []string{establishmentID}, day, day.AddDate(0, 1, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != len(want) {
+		t.Fatalf("got %d records, want %d", len(records), len(want))
+	}
+	for _, record := range records {
+		if !want[record.ID] {
+			t.Errorf("record arriving %s is outside the month", record.WillArrive)
+		}
+	}
+}
+
+// outOfOrder tells whether next may not follow previous in created_at, id
+// order
+func outOfOrder(previous, next *entity.BookingRecord, desc bool) bool {
+	before := previous.CreatedAt.Before(next.CreatedAt) ||
+		previous.CreatedAt.Equal(next.CreatedAt) && previous.ID < next.ID
+	return before == desc
+}
+
+func toInterfaces(values []string) []interface{} {
+	result := make([]interface{}, len(values))
+	for i, value := range values {
+		result[i] = value
+	}
+	return result
+}
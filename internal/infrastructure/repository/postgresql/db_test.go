@@ -0,0 +1,47 @@
+package postgresql
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+
+	"Booking/api-service-booking/internal/pkg/postgres"
+)
+
+// testDB connects to the database in POSTGRES_TEST_DSN, migrated with make
+// migrate. Tests that need a database are skipped without one.
+func testDB(t *testing.T) *postgres.PostgresDB {
+	t.Helper()
+
+	dsn := os.Getenv("POSTGRES_TEST_DSN")
+	if dsn == "" {
+		t.Skip("POSTGRES_TEST_DSN is not set")
+	}
+	pool, err := pgxpool.Connect(context.Background(), dsn)
+	if err != nil {
+		t.Fatalf("connect to test database: %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	return &postgres.PostgresDB{Pool: pool, Sq: postgres.NewSquirrel()}
+}
+
+// queryDB only builds queries, for tests of the SQL a repository sends
+// that do not need a database
+func queryDB() *postgres.PostgresDB {
+	return &postgres.PostgresDB{Sq: postgres.NewSquirrel()}
+}
+
+// cleanup deletes the rows a test wrote to table
+func cleanup(t *testing.T, db *postgres.PostgresDB, table, column string, values ...interface{}) {
+	t.Helper()
+	t.Cleanup(func() {
+		for _, value := range values {
+			if _, err := db.Exec(context.Background(), "DELETE FROM "+table+" WHERE "+column+" = $1", value); err != nil {
+				t.Errorf("clean up %s: %v", table, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,297 @@
+package postgresql
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v4"
+
+	"Booking/api-service-booking/internal/entity"
+	"Booking/api-service-booking/internal/infrastructure/repository/postgresql/repo"
+	"Booking/api-service-booking/internal/pkg/postgres"
+)
+
+type establishmentImportRepo struct {
+	tableName    string
+	refTableName string
+	db           *postgres.PostgresDB
+}
+
+func NewEstablishmentImportRepo(db *postgres.PostgresDB) repo.EstablishmentImportRepo {
+	return &establishmentImportRepo{
+		tableName:    "establishment_imports",
+		refTableName: "establishment_import_refs",
+		db:           db,
+	}
+}
+
+func (r *establishmentImportRepo) Create(ctx context.Context, m *entity.EstablishmentImport) error {
+	clauses := map[string]interface{}{
+		"id":         m.ID,
+		"category":   m.Category,
+		"format":     m.Format,
+		"owner_id":   m.OwnerID,
+		"locale":     m.Locale,
+		"dry_run":    m.DryRun,
+		"status":     m.Status,
+		"rows":       m.Rows,
+		"next_row":   m.NextRow,
+		"total":      m.Total,
+		"invalid":    m.Invalid,
+		"processed":  m.Processed,
+		"created":    m.Created,
+		"updated":    m.Updated,
+		"failed":     m.Failed,
+		"errors":     m.Errors,
+		"created_at": m.CreatedAt,
+		"updated_at": m.UpdatedAt,
+	}
+
+	sqlStr, args, err := r.db.Sq.Builder.Insert(r.tableName).SetMap(clauses).ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.tableName+" create")
+	}
+
+	if _, err = r.db.Exec(ctx, sqlStr, args...); err != nil {
+		return r.db.Error(err)
+	}
+	return nil
+}
+
+func (r *establishmentImportRepo) Update(ctx context.Context, m *entity.EstablishmentImport) error {
+	clauses := map[string]interface{}{
+		"dry_run":    m.DryRun,
+		"status":     m.Status,
+		"next_row":   m.NextRow,
+		"processed":  m.Processed,
+		"created":    m.Created,
+		"updated":    m.Updated,
+		"failed":     m.Failed,
+		"errors":     m.Errors,
+		"updated_at": m.UpdatedAt,
+	}
+
+	sqlStr, args, err := r.db.Sq.Builder.
+		Update(r.tableName).
+		SetMap(clauses).
+		Where(r.db.Sq.Equal("id", m.ID)).
+		ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.tableName+" update")
+	}
+
+	commandTag, err := r.db.Exec(ctx, sqlStr, args...)
+	if err != nil {
+		return r.db.Error(err)
+	}
+	if commandTag.RowsAffected() == 0 {
+		return r.db.Error(pgx.ErrNoRows)
+	}
+	return nil
+}
+
+func (r *establishmentImportRepo) Get(ctx context.Context, id string) (*entity.EstablishmentImport, error) {
+	sqlStr, args, err := r.db.Sq.Builder.
+		Select(
+			"id",
+			"category",
+			"format",
+			"owner_id",
+			"locale",
+			"dry_run",
+			"status",
+			"rows",
+			"next_row",
+			"total",
+			"invalid",
+			"processed",
+			"created",
+			"updated",
+			"failed",
+			"errors",
+			"created_at",
+			"updated_at",
+		).
+		From(r.tableName).
+		Where(r.db.Sq.Equal("id", id)).
+		ToSql()
+	if err != nil {
+		return nil, r.db.ErrSQLBuild(err, r.tableName+" get")
+	}
+
+	var m entity.EstablishmentImport
+	if err = r.db.QueryRow(ctx, sqlStr, args...).Scan(
+		&m.ID,
+		&m.Category,
+		&m.Format,
+		&m.OwnerID,
+		&m.Locale,
+		&m.DryRun,
+		&m.Status,
+		&m.Rows,
+		&m.NextRow,
+		&m.Total,
+		&m.Invalid,
+		&m.Processed,
+		&m.Created,
+		&m.Updated,
+		&m.Failed,
+		&m.Errors,
+		&m.CreatedAt,
+		&m.UpdatedAt,
+	); err != nil {
+		return nil, r.db.Error(err)
+	}
+	return &m, nil
+}
+
+func (r *establishmentImportRepo) List(ctx context.Context, limit, offset uint64) ([]*entity.EstablishmentImport, uint64, error) {
+	sqlStr, args, err := r.db.Sq.Builder.
+		Select(
+			"id",
+			"category",
+			"format",
+			"owner_id",
+			"locale",
+			"dry_run",
+			"status",
+			"next_row",
+			"total",
+			"invalid",
+			"processed",
+			"created",
+			"updated",
+			"failed",
+			"created_at",
+			"updated_at",
+		).
+		From(r.tableName).
+		OrderBy("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		ToSql()
+	if err != nil {
+		return nil, 0, r.db.ErrSQLBuild(err, r.tableName+" list")
+	}
+
+	rows, err := r.db.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, 0, r.db.Error(err)
+	}
+	defer rows.Close()
+
+	var imports []*entity.EstablishmentImport
+	for rows.Next() {
+		var m entity.EstablishmentImport
+		if err = rows.Scan(
+			&m.ID,
+			&m.Category,
+			&m.Format,
+			&m.OwnerID,
+			&m.Locale,
+			&m.DryRun,
+			&m.Status,
+			&m.NextRow,
+			&m.Total,
+			&m.Invalid,
+			&m.Processed,
+			&m.Created,
+			&m.Updated,
+			&m.Failed,
+			&m.CreatedAt,
+			&m.UpdatedAt,
+		); err != nil {
+			return nil, 0, r.db.Error(err)
+		}
+		imports = append(imports, &m)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, 0, r.db.Error(err)
+	}
+
+	countStr, countArgs, err := r.db.Sq.Builder.
+		Select("COUNT(*)").
+		From(r.tableName).
+		ToSql()
+	if err != nil {
+		return nil, 0, r.db.ErrSQLBuild(err, r.tableName+" count")
+	}
+
+	var count uint64
+	if err = r.db.QueryRow(ctx, countStr, countArgs...).Scan(&count); err != nil {
+		return nil, 0, r.db.Error(err)
+	}
+
+	return imports, count, nil
+}
+
+func (r *establishmentImportRepo) GetRef(ctx context.Context, category, externalRef string) (*entity.ImportRef, error) {
+	sqlStr, args, err := r.db.Sq.Builder.
+		Select(
+			"category",
+			"external_ref",
+			"establishment_id",
+			"import_id",
+			"updated_at",
+		).
+		From(r.refTableName).
+		Where(r.db.Sq.And(
+			r.db.Sq.Equal("category", category),
+			r.db.Sq.Equal("external_ref", externalRef),
+		)).
+		ToSql()
+	if err != nil {
+		return nil, r.db.ErrSQLBuild(err, r.refTableName+" get")
+	}
+
+	var m entity.ImportRef
+	if err = r.db.QueryRow(ctx, sqlStr, args...).Scan(
+		&m.Category,
+		&m.ExternalRef,
+		&m.EstablishmentID,
+		&m.ImportID,
+		&m.UpdatedAt,
+	); err != nil {
+		return nil, r.db.Error(err)
+	}
+	return &m, nil
+}
+
+func (r *establishmentImportRepo) SaveRef(ctx context.Context, m *entity.ImportRef) error {
+	sqlStr, args, err := r.db.Sq.Builder.
+		Insert(r.refTableName).
+		SetMap(map[string]interface{}{
+			"category":         m.Category,
+			"external_ref":     m.ExternalRef,
+			"establishment_id": m.EstablishmentID,
+			"import_id":        m.ImportID,
+			"updated_at":       m.UpdatedAt,
+		}).
+		Suffix("ON CONFLICT (category, external_ref) DO UPDATE SET establishment_id = EXCLUDED.establishment_id, import_id = EXCLUDED.import_id, updated_at = EXCLUDED.updated_at").
+		ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.refTableName+" save")
+	}
+
+	if _, err = r.db.Exec(ctx, sqlStr, args...); err != nil {
+		return r.db.Error(err)
+	}
+	return nil
+}
+
+func (r *establishmentImportRepo) DeleteRefs(ctx context.Context, category, establishmentID string) error {
+	sqlStr, args, err := r.db.Sq.Builder.
+		Delete(r.refTableName).
+		Where(r.db.Sq.And(
+			r.db.Sq.Equal("category", category),
+			r.db.Sq.Equal("establishment_id", establishmentID),
+		)).
+		ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.refTableName+" delete")
+	}
+
+	if _, err = r.db.Exec(ctx, sqlStr, args...); err != nil {
+		return r.db.Error(err)
+	}
+	return nil
+}
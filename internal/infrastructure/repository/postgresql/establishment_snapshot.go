@@ -0,0 +1,194 @@
+package postgresql
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+
+	"Booking/api-service-booking/internal/entity"
+	"Booking/api-service-booking/internal/infrastructure/repository/postgresql/repo"
+	"Booking/api-service-booking/internal/pkg/postgres"
+)
+
+type establishmentSnapshotRepo struct {
+	tableName string
+	db        *postgres.PostgresDB
+}
+
+func NewEstablishmentSnapshotRepo(db *postgres.PostgresDB) repo.EstablishmentSnapshotRepo {
+	return &establishmentSnapshotRepo{
+		tableName: "establishment_snapshots",
+		db:        db,
+	}
+}
+
+func (r *establishmentSnapshotRepo) selectQuery() sq.SelectBuilder {
+	return r.db.Sq.Builder.
+		Select(
+			"id",
+			"category",
+			"format",
+			"filter",
+			"owner_id",
+			"next_run_at",
+			"last_run_at",
+			"last_object",
+			"last_rows",
+			"last_error",
+			"created_at",
+			"updated_at",
+		).
+		From(r.tableName)
+}
+
+func (r *establishmentSnapshotRepo) scan(row pgx.Row) (*entity.EstablishmentSnapshot, error) {
+	var m entity.EstablishmentSnapshot
+	if err := row.Scan(
+		&m.ID,
+		&m.Category,
+		&m.Format,
+		&m.Filter,
+		&m.OwnerID,
+		&m.NextRunAt,
+		&m.LastRunAt,
+		&m.LastObject,
+		&m.LastRows,
+		&m.LastError,
+		&m.CreatedAt,
+		&m.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (r *establishmentSnapshotRepo) Create(ctx context.Context, m *entity.EstablishmentSnapshot) error {
+	clauses := map[string]interface{}{
+		"id":          m.ID,
+		"category":    m.Category,
+		"format":      m.Format,
+		"filter":      m.Filter,
+		"owner_id":    m.OwnerID,
+		"next_run_at": m.NextRunAt,
+		"created_at":  m.CreatedAt,
+		"updated_at":  m.UpdatedAt,
+	}
+
+	sqlStr, args, err := r.db.Sq.Builder.Insert(r.tableName).SetMap(clauses).ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.tableName+" create")
+	}
+
+	if _, err = r.db.Exec(ctx, sqlStr, args...); err != nil {
+		return r.db.Error(err)
+	}
+	return nil
+}
+
+func (r *establishmentSnapshotRepo) Update(ctx context.Context, m *entity.EstablishmentSnapshot) error {
+	clauses := map[string]interface{}{
+		"next_run_at": m.NextRunAt,
+		"last_run_at": m.LastRunAt,
+		"last_object": m.LastObject,
+		"last_rows":   m.LastRows,
+		"last_error":  m.LastError,
+		"updated_at":  m.UpdatedAt,
+	}
+
+	sqlStr, args, err := r.db.Sq.Builder.
+		Update(r.tableName).
+		SetMap(clauses).
+		Where(r.db.Sq.Equal("id", m.ID)).
+		ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.tableName+" update")
+	}
+
+	commandTag, err := r.db.Exec(ctx, sqlStr, args...)
+	if err != nil {
+		return r.db.Error(err)
+	}
+	if commandTag.RowsAffected() == 0 {
+		return r.db.Error(pgx.ErrNoRows)
+	}
+	return nil
+}
+
+func (r *establishmentSnapshotRepo) Get(ctx context.Context, id string) (*entity.EstablishmentSnapshot, error) {
+	sqlStr, args, err := r.selectQuery().
+		Where(r.db.Sq.Equal("id", id)).
+		ToSql()
+	if err != nil {
+		return nil, r.db.ErrSQLBuild(err, r.tableName+" get")
+	}
+
+	m, err := r.scan(r.db.QueryRow(ctx, sqlStr, args...))
+	if err != nil {
+		return nil, r.db.Error(err)
+	}
+	return m, nil
+}
+
+func (r *establishmentSnapshotRepo) List(ctx context.Context, limit, offset uint64) ([]*entity.EstablishmentSnapshot, uint64, error) {
+	sqlStr, args, err := r.selectQuery().
+		OrderBy("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		ToSql()
+	if err != nil {
+		return nil, 0, r.db.ErrSQLBuild(err, r.tableName+" list")
+	}
+
+	rows, err := r.db.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, 0, r.db.Error(err)
+	}
+	defer rows.Close()
+
+	var snapshots []*entity.EstablishmentSnapshot
+	for rows.Next() {
+		m, err := r.scan(rows)
+		if err != nil {
+			return nil, 0, r.db.Error(err)
+		}
+		snapshots = append(snapshots, m)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, 0, r.db.Error(err)
+	}
+
+	countStr, countArgs, err := r.db.Sq.Builder.
+		Select("COUNT(*)").
+		From(r.tableName).
+		ToSql()
+	if err != nil {
+		return nil, 0, r.db.ErrSQLBuild(err, r.tableName+" count")
+	}
+
+	var count uint64
+	if err = r.db.QueryRow(ctx, countStr, countArgs...).Scan(&count); err != nil {
+		return nil, 0, r.db.Error(err)
+	}
+
+	return snapshots, count, nil
+}
+
+func (r *establishmentSnapshotRepo) Delete(ctx context.Context, id string) error {
+	sqlStr, args, err := r.db.Sq.Builder.
+		Delete(r.tableName).
+		Where(r.db.Sq.Equal("id", id)).
+		ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.tableName+" delete")
+	}
+
+	commandTag, err := r.db.Exec(ctx, sqlStr, args...)
+	if err != nil {
+		return r.db.Error(err)
+	}
+	if commandTag.RowsAffected() == 0 {
+		return r.db.Error(pgx.ErrNoRows)
+	}
+	return nil
+}
@@ -0,0 +1,222 @@
+package postgresql
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+
+	"Booking/api-service-booking/internal/entity"
+	errorspkg "Booking/api-service-booking/internal/errors"
+	"Booking/api-service-booking/internal/infrastructure/repository/postgresql/repo"
+	"Booking/api-service-booking/internal/pkg/postgres"
+)
+
+type hotelRoomRepo struct {
+	tableName string
+	holdTable string
+	db        *postgres.PostgresDB
+}
+
+func NewHotelRoomRepo(db *postgres.PostgresDB) repo.HotelRoomRepo {
+	return &hotelRoomRepo{
+		tableName: "hotel_rooms",
+		holdTable: "room_holds",
+		db:        db,
+	}
+}
+
+func (r *hotelRoomRepo) Save(ctx context.Context, m *entity.HotelRooms) error {
+	sqlStr, args, err := r.db.Sq.Builder.
+		Insert(r.tableName).
+		SetMap(map[string]interface{}{
+			"hotel_id":   m.HotelID,
+			"rooms":      m.Rooms,
+			"updated_at": m.UpdatedAt,
+		}).
+		Suffix("ON CONFLICT (hotel_id) DO UPDATE SET rooms = EXCLUDED.rooms, updated_at = EXCLUDED.updated_at").
+		ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.tableName+" save")
+	}
+
+	if _, err = r.db.Exec(ctx, sqlStr, args...); err != nil {
+		return r.db.Error(err)
+	}
+	return nil
+}
+
+func (r *hotelRoomRepo) Get(ctx context.Context, hotelID string) (*entity.HotelRooms, error) {
+	sqlStr, args, err := r.db.Sq.Builder.
+		Select(
+			"hotel_id",
+			"rooms",
+			"updated_at",
+		).
+		From(r.tableName).
+		Where(r.db.Sq.Equal("hotel_id", hotelID)).
+		ToSql()
+	if err != nil {
+		return nil, r.db.ErrSQLBuild(err, r.tableName+" get")
+	}
+
+	var m entity.HotelRooms
+	if err = r.db.QueryRow(ctx, sqlStr, args...).Scan(
+		&m.HotelID,
+		&m.Rooms,
+		&m.UpdatedAt,
+	); err != nil {
+		return nil, r.db.Error(err)
+	}
+
+	return &m, nil
+}
+
+func (r *hotelRoomRepo) Hold(ctx context.Context, m *entity.RoomHold) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return r.db.Error(err)
+	}
+	defer tx.Rollback(ctx)
+
+	// lock the room count first so concurrent bookings of the same hotel
+	// queue up here and then see each other's holds. Hotels without a
+	// count keep their holds unchecked.
+	lockStr, lockArgs, err := r.db.Sq.Builder.
+		Select("rooms").
+		From(r.tableName).
+		Where(r.db.Sq.Equal("hotel_id", m.HotelID)).
+		Suffix("FOR UPDATE").
+		ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.tableName+" lock")
+	}
+	var rooms int
+	err = tx.QueryRow(ctx, lockStr, lockArgs...).Scan(&rooms)
+	if err != nil && err != pgx.ErrNoRows {
+		return r.db.Error(err)
+	}
+
+	// a booking being moved gives up its own nights first, the rollback
+	// puts them back when a night is full
+	deleteStr, deleteArgs, err := r.db.Sq.Builder.
+		Delete(r.holdTable).
+		Where(r.db.Sq.Equal("booking_id", m.BookingID)).
+		ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.holdTable+" delete")
+	}
+	if _, err = tx.Exec(ctx, deleteStr, deleteArgs...); err != nil {
+		return r.db.Error(err)
+	}
+
+	if rooms > 0 {
+		fullestStr, fullestArgs, err := r.fullest(m.HotelID, "", m.FirstNight, m.LeaveDay).ToSql()
+		if err != nil {
+			return r.db.ErrSQLBuild(err, r.holdTable+" fullest")
+		}
+		var taken int
+		err = tx.QueryRow(ctx, fullestStr, fullestArgs...).Scan(&taken)
+		if err != nil && err != pgx.ErrNoRows {
+			return r.db.Error(err)
+		}
+		if taken >= rooms {
+			return errorspkg.ErrorNotAvailable
+		}
+	}
+
+	insert := r.db.Sq.Builder.
+		Insert(r.holdTable).
+		Columns("booking_id", "hotel_id", "night", "created_at")
+	for night := m.FirstNight; night.Before(m.LeaveDay); night = night.AddDate(0, 0, 1) {
+		insert = insert.Values(m.BookingID, m.HotelID, night, m.CreatedAt)
+	}
+	insertStr, insertArgs, err := insert.ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.holdTable+" create")
+	}
+	if _, err = tx.Exec(ctx, insertStr, insertArgs...); err != nil {
+		return r.db.Error(err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return r.db.Error(err)
+	}
+	return nil
+}
+
+func (r *hotelRoomRepo) Held(ctx context.Context, bookingID string) (bool, error) {
+	sqlStr, args, err := r.db.Sq.Builder.
+		Select("1").
+		From(r.holdTable).
+		Where(r.db.Sq.Equal("booking_id", bookingID)).
+		Limit(1).
+		ToSql()
+	if err != nil {
+		return false, r.db.ErrSQLBuild(err, r.holdTable+" held")
+	}
+
+	var one int
+	err = r.db.QueryRow(ctx, sqlStr, args...).Scan(&one)
+	if err == pgx.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, r.db.Error(err)
+	}
+	return true, nil
+}
+
+func (r *hotelRoomRepo) Release(ctx context.Context, bookingID string) error {
+	sqlStr, args, err := r.db.Sq.Builder.
+		Delete(r.holdTable).
+		Where(r.db.Sq.Equal("booking_id", bookingID)).
+		ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.holdTable+" delete")
+	}
+
+	if _, err = r.db.Exec(ctx, sqlStr, args...); err != nil {
+		return r.db.Error(err)
+	}
+	return nil
+}
+
+func (r *hotelRoomRepo) Fullest(ctx context.Context, hotelID, bookingID string, firstNight, leaveDay time.Time) (int, error) {
+	sqlStr, args, err := r.fullest(hotelID, bookingID, firstNight, leaveDay).ToSql()
+	if err != nil {
+		return 0, r.db.ErrSQLBuild(err, r.holdTable+" fullest")
+	}
+
+	var taken int
+	err = r.db.QueryRow(ctx, sqlStr, args...).Scan(&taken)
+	if err == pgx.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, r.db.Error(err)
+	}
+	return taken, nil
+}
+
+// fullest counts the rooms held on the fullest night from firstNight up to
+// leaveDay, leaving out bookingID when it is given. No row means no night
+// has a hold.
+func (r *hotelRoomRepo) fullest(hotelID, bookingID string, firstNight, leaveDay time.Time) sq.SelectBuilder {
+	query := r.db.Sq.Builder.
+		Select("COUNT(*)").
+		From(r.holdTable).
+		Where(r.db.Sq.And(
+			r.db.Sq.Equal("hotel_id", hotelID),
+			sq.GtOrEq{"night": firstNight},
+			r.db.Sq.Lt("night", leaveDay),
+		)).
+		GroupBy("night").
+		OrderBy("1 DESC").
+		Limit(1)
+	if bookingID != "" {
+		query = query.Where(r.db.Sq.NotEqual("booking_id", bookingID))
+	}
+	return query
+}
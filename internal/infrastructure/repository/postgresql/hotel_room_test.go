@@ -0,0 +1,106 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Booking/api-service-booking/internal/entity"
+	errorspkg "Booking/api-service-booking/internal/errors"
+)
+
+func TestHotelRoomRepoSave(t *testing.T) {
+	db := testDB(t)
+	r := NewHotelRoomRepo(db)
+	ctx := context.Background()
+
+	hotelID := uuid.NewString()
+	cleanup(t, db, "hotel_rooms", "hotel_id", hotelID)
+
+	if _, err := r.Get(ctx, hotelID); !errors.Is(err, errorspkg.ErrorNotFound) {
+		t.Fatalf("Get() before Save error = %v, want not found", err)
+	}
+
+	// a second save replaces the count
+	for _, rooms := range []int{12, 7} {
+		if err := r.Save(ctx, &entity.HotelRooms{HotelID: hotelID, Rooms: rooms, UpdatedAt: time.Now().UTC()}); err != nil {
+			t.Fatal(err)
+		}
+		got, err := r.Get(ctx, hotelID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Rooms != rooms {
+			t.Errorf("Rooms = %d, want %d", got.Rooms, rooms)
+		}
+	}
+}
+
+func TestHotelRoomRepoHold(t *testing.T) {
+	db := testDB(t)
+	r := NewHotelRoomRepo(db)
+	ctx := context.Background()
+
+	hotelID := uuid.NewString()
+	first, second := uuid.NewString(), uuid.NewString()
+	cleanup(t, db, "hotel_rooms", "hotel_id", hotelID)
+	cleanup(t, db, "room_holds", "hotel_id", hotelID)
+
+	night := func(day int) time.Time {
+		return time.Date(2026, 5, day, 0, 0, 0, 0, time.UTC)
+	}
+	hold := func(bookingID string, firstNight, leaveDay int) error {
+		return r.Hold(ctx, &entity.RoomHold{
+			HotelID:    hotelID,
+			BookingID:  bookingID,
+			FirstNight: night(firstNight),
+			LeaveDay:   night(leaveDay),
+			CreatedAt:  time.Now().UTC(),
+		})
+	}
+
+	// without a room count any hold is taken and kept for later
+	if err := hold(first, 10, 12); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Save(ctx, &entity.HotelRooms{HotelID: hotelID, Rooms: 1, UpdatedAt: time.Now().UTC()}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := hold(second, 11, 13); !errors.Is(err, errorspkg.ErrorNotAvailable) {
+		t.Fatalf("Hold() over a full night error = %v, want not available", err)
+	}
+	if held, err := r.Held(ctx, second); err != nil || held {
+		t.Fatalf("Held() after a refused hold = %v, %v, want false", held, err)
+	}
+	if err := hold(second, 12, 13); err != nil {
+		t.Fatalf("Hold() on the leave day: %v", err)
+	}
+
+	// a booking moves over its own nights
+	if err := hold(first, 11, 12); err != nil {
+		t.Fatalf("Hold() moving a booking: %v", err)
+	}
+	if taken, err := r.Fullest(ctx, hotelID, "", night(10), night(11)); err != nil || taken != 0 {
+		t.Errorf("Fullest() of a given up night = %d, %v, want 0", taken, err)
+	}
+	if taken, err := r.Fullest(ctx, hotelID, "", night(10), night(13)); err != nil || taken != 1 {
+		t.Errorf("Fullest() = %d, %v, want 1", taken, err)
+	}
+	if taken, err := r.Fullest(ctx, hotelID, first, night(11), night(12)); err != nil || taken != 0 {
+		t.Errorf("Fullest() leaving out the booking = %d, %v, want 0", taken, err)
+	}
+
+	if err := r.Release(ctx, first); err != nil {
+		t.Fatal(err)
+	}
+	if held, err := r.Held(ctx, first); err != nil || held {
+		t.Fatalf("Held() after Release() = %v, %v, want false", held, err)
+	}
+	if err := hold(second, 10, 13); err != nil {
+		t.Fatalf("Hold() after Release(): %v", err)
+	}
+}
@@ -0,0 +1,128 @@
+package postgresql
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+
+	"Booking/api-service-booking/internal/entity"
+	"Booking/api-service-booking/internal/infrastructure/repository/postgresql/repo"
+	"Booking/api-service-booking/internal/pkg/postgres"
+)
+
+type itineraryRepo struct {
+	tableName string
+	db        *postgres.PostgresDB
+}
+
+func NewItineraryRepo(db *postgres.PostgresDB) repo.ItineraryRepo {
+	return &itineraryRepo{
+		tableName: "itineraries",
+		db:        db,
+	}
+}
+
+func (r *itineraryRepo) Create(ctx context.Context, m *entity.Itinerary) error {
+	clauses := map[string]interface{}{
+		"id":         m.ID,
+		"user_id":    m.UserID,
+		"name":       m.Name,
+		"city":       m.City,
+		"people":     m.People,
+		"days":       m.Days,
+		"created_at": m.CreatedAt,
+		"updated_at": m.UpdatedAt,
+	}
+
+	sqlStr, args, err := r.db.Sq.Builder.Insert(r.tableName).SetMap(clauses).ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.tableName+" create")
+	}
+
+	if _, err = r.db.Exec(ctx, sqlStr, args...); err != nil {
+		return r.db.Error(err)
+	}
+	return nil
+}
+
+func (r *itineraryRepo) list(ctx context.Context, where sq.Sqlizer) ([]*entity.Itinerary, error) {
+	sqlStr, args, err := r.db.Sq.Builder.
+		Select(
+			"id",
+			"user_id",
+			"name",
+			"city",
+			"people",
+			"days",
+			"created_at",
+			"updated_at",
+		).
+		From(r.tableName).
+		Where(where).
+		OrderBy("created_at DESC").
+		ToSql()
+	if err != nil {
+		return nil, r.db.ErrSQLBuild(err, r.tableName+" list")
+	}
+
+	rows, err := r.db.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, r.db.Error(err)
+	}
+	defer rows.Close()
+
+	var itineraries []*entity.Itinerary
+	for rows.Next() {
+		var itinerary entity.Itinerary
+		if err = rows.Scan(
+			&itinerary.ID,
+			&itinerary.UserID,
+			&itinerary.Name,
+			&itinerary.City,
+			&itinerary.People,
+			&itinerary.Days,
+			&itinerary.CreatedAt,
+			&itinerary.UpdatedAt,
+		); err != nil {
+			return nil, r.db.Error(err)
+		}
+		itineraries = append(itineraries, &itinerary)
+	}
+
+	return itineraries, rows.Err()
+}
+
+func (r *itineraryRepo) Get(ctx context.Context, id string) (*entity.Itinerary, error) {
+	itineraries, err := r.list(ctx, r.db.Sq.Equal("id", id))
+	if err != nil {
+		return nil, err
+	}
+	if len(itineraries) == 0 {
+		return nil, r.db.Error(pgx.ErrNoRows)
+	}
+	return itineraries[0], nil
+}
+
+func (r *itineraryRepo) ListByUser(ctx context.Context, userID string) ([]*entity.Itinerary, error) {
+	return r.list(ctx, r.db.Sq.Equal("user_id", userID))
+}
+
+func (r *itineraryRepo) Delete(ctx context.Context, id string) error {
+	sqlStr, args, err := r.db.Sq.Builder.
+		Delete(r.tableName).
+		Where(r.db.Sq.Equal("id", id)).
+		ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.tableName+" delete")
+	}
+
+	commandTag, err := r.db.Exec(ctx, sqlStr, args...)
+	if err != nil {
+		return r.db.Error(err)
+	}
+	if commandTag.RowsAffected() == 0 {
+		return r.db.Error(pgx.ErrNoRows)
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+package postgresql
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"Booking/api-service-booking/internal/entity"
+	"Booking/api-service-booking/internal/infrastructure/repository/postgresql/repo"
+	"Booking/api-service-booking/internal/pkg/postgres"
+)
+
+type jobRepo struct {
+	tableName string
+	db        *postgres.PostgresDB
+}
+
+func NewJobRepo(db *postgres.PostgresDB) repo.JobRepo {
+	return &jobRepo{
+		tableName: "scheduled_jobs",
+		db:        db,
+	}
+}
+
+func (r *jobRepo) selectQuery() sq.SelectBuilder {
+	return r.db.Sq.Builder.
+		Select(
+			"id",
+			"kind",
+			"COALESCE(booking_id::text, '')",
+			"payload",
+			"run_at",
+			"status",
+			"attempts",
+			"last_error",
+			"created_at",
+			"updated_at",
+		).
+		From(r.tableName)
+}
+
+func (r *jobRepo) scan(ctx context.Context, sqlStr string, args []interface{}) ([]*entity.Job, error) {
+	rows, err := r.db.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, r.db.Error(err)
+	}
+	defer rows.Close()
+
+	var jobs []*entity.Job
+	for rows.Next() {
+		var job entity.Job
+		if err = rows.Scan(
+			&job.ID,
+			&job.Kind,
+			&job.BookingID,
+			&job.Payload,
+			&job.RunAt,
+			&job.Status,
+			&job.Attempts,
+			&job.LastError,
+			&job.CreatedAt,
+			&job.UpdatedAt,
+		); err != nil {
+			return nil, r.db.Error(err)
+		}
+		jobs = append(jobs, &job)
+	}
+
+	return jobs, rows.Err()
+}
+
+func (r *jobRepo) Create(ctx context.Context, m *entity.Job) error {
+	clauses := map[string]interface{}{
+		"id":         m.ID,
+		"kind":       m.Kind,
+		"payload":    m.Payload,
+		"run_at":     m.RunAt,
+		"status":     m.Status,
+		"attempts":   m.Attempts,
+		"last_error": m.LastError,
+		"created_at": m.CreatedAt,
+		"updated_at": m.UpdatedAt,
+	}
+	if m.BookingID != "" {
+		clauses["booking_id"] = m.BookingID
+	}
+
+	sqlStr, args, err := r.db.Sq.Builder.Insert(r.tableName).SetMap(clauses).ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.tableName+" create")
+	}
+
+	if _, err = r.db.Exec(ctx, sqlStr, args...); err != nil {
+		return r.db.Error(err)
+	}
+	return nil
+}
+
+func (r *jobRepo) Update(ctx context.Context, m *entity.Job) error {
+	clauses := map[string]interface{}{
+		"run_at":     m.RunAt,
+		"status":     m.Status,
+		"attempts":   m.Attempts,
+		"last_error": m.LastError,
+		"updated_at": m.UpdatedAt,
+	}
+
+	sqlStr, args, err := r.db.Sq.Builder.
+		Update(r.tableName).
+		SetMap(clauses).
+		Where(r.db.Sq.Equal("id", m.ID)).
+		ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.tableName+" update")
+	}
+
+	if _, err = r.db.Exec(ctx, sqlStr, args...); err != nil {
+		return r.db.Error(err)
+	}
+	return nil
+}
+
+func (r *jobRepo) ListDue(ctx context.Context, now time.Time, limit uint64) ([]*entity.Job, error) {
+	sqlStr, args, err := r.selectQuery().
+		Where(r.db.Sq.And(
+			r.db.Sq.Equal("status", entity.JobStatusPending),
+			sq.LtOrEq{"run_at": now},
+		)).
+		OrderBy("run_at").
+		Limit(limit).
+		ToSql()
+	if err != nil {
+		return nil, r.db.ErrSQLBuild(err, r.tableName+" list due")
+	}
+
+	return r.scan(ctx, sqlStr, args)
+}
+
+func (r *jobRepo) List(ctx context.Context, status string, limit, offset uint64) ([]*entity.Job, uint64, error) {
+	where := r.db.Sq.Equal("status", status)
+
+	sqlStr, args, err := r.selectQuery().
+		Where(where).
+		OrderBy("run_at").
+		Limit(limit).
+		Offset(offset).
+		ToSql()
+	if err != nil {
+		return nil, 0, r.db.ErrSQLBuild(err, r.tableName+" list")
+	}
+
+	jobs, err := r.scan(ctx, sqlStr, args)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	countStr, countArgs, err := r.db.Sq.Builder.
+		Select("COUNT(*)").
+		From(r.tableName).
+		Where(where).
+		ToSql()
+	if err != nil {
+		return nil, 0, r.db.ErrSQLBuild(err, r.tableName+" count")
+	}
+
+	var count uint64
+	if err = r.db.QueryRow(ctx, countStr, countArgs...).Scan(&count); err != nil {
+		return nil, 0, r.db.Error(err)
+	}
+
+	return jobs, count, nil
+}
+
+func (r *jobRepo) CancelByBooking(ctx context.Context, bookingID string, updatedAt time.Time) error {
+	sqlStr, args, err := r.db.Sq.Builder.
+		Update(r.tableName).
+		SetMap(map[string]interface{}{
+			"status":     entity.JobStatusCanceled,
+			"updated_at": updatedAt,
+		}).
+		Where(r.db.Sq.And(
+			r.db.Sq.Equal("booking_id", bookingID),
+			r.db.Sq.Equal("status", entity.JobStatusPending),
+		)).
+		ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.tableName+" cancel")
+	}
+
+	if _, err = r.db.Exec(ctx, sqlStr, args...); err != nil {
+		return r.db.Error(err)
+	}
+	return nil
+}
+
+func (r *jobRepo) CancelByPayload(ctx context.Context, kind, key, value string, updatedAt time.Time) error {
+	sqlStr, args, err := r.cancelByPayloadQuery(kind, key, value, updatedAt).ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.tableName+" cancel by payload")
+	}
+
+	if _, err = r.db.Exec(ctx, sqlStr, args...); err != nil {
+		return r.db.Error(err)
+	}
+	return nil
+}
+
+func (r *jobRepo) cancelByPayloadQuery(kind, key, value string, updatedAt time.Time) sq.UpdateBuilder {
+	return r.db.Sq.Builder.
+		Update(r.tableName).
+		SetMap(map[string]interface{}{
+			"status":     entity.JobStatusCanceled,
+			"updated_at": updatedAt,
+		}).
+		Where(r.db.Sq.And(
+			r.db.Sq.Equal("kind", kind),
+			r.db.Sq.Expr("payload ->> ? = ?", key, value),
+			r.db.Sq.Equal("status", entity.JobStatusPending),
+		))
+}
@@ -0,0 +1,66 @@
+package postgresql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Booking/api-service-booking/internal/entity"
+)
+
+func TestJobCancelByPayloadQuery(t *testing.T) {
+	r := &jobRepo{tableName: "scheduled_jobs", db: queryDB()}
+
+	sqlStr, args, err := r.cancelByPayloadQuery(entity.JobKindWaitlistOffer, "entry_id", uuid.NewString(), time.Now()).ToSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkArgs(t, sqlStr, args)
+}
+
+func TestJobRepoCancelByPayload(t *testing.T) {
+	db := testDB(t)
+	r := NewJobRepo(db)
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	entryID := uuid.NewString()
+	jobs := []*entity.Job{
+		{Kind: entity.JobKindWaitlistOffer, Payload: map[string]string{"entry_id": entryID}},
+		{Kind: entity.JobKindWaitlistOffer, Payload: map[string]string{"entry_id": uuid.NewString()}},
+		{Kind: entity.JobKindTripCheckout, Payload: map[string]string{"entry_id": entryID}},
+		// a booking with the same id keeps its jobs
+		{Kind: entity.JobKindBookingReminder, BookingID: entryID, Payload: map[string]string{}},
+	}
+	for _, job := range jobs {
+		job.ID = uuid.NewString()
+		job.RunAt = now.Add(time.Hour)
+		job.Status = entity.JobStatusPending
+		job.CreatedAt = now
+		job.UpdatedAt = now
+		if err := r.Create(ctx, job); err != nil {
+			t.Fatal(err)
+		}
+		cleanup(t, db, "scheduled_jobs", "id", job.ID)
+	}
+
+	if err := r.CancelByPayload(ctx, entity.JobKindWaitlistOffer, "entry_id", entryID, now); err != nil {
+		t.Fatal(err)
+	}
+
+	pending, _, err := r.List(ctx, entity.JobStatusPending, 1000, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	left := map[string]bool{}
+	for _, job := range pending {
+		left[job.ID] = true
+	}
+	for i, job := range jobs {
+		if want := i != 0; left[job.ID] != want {
+			t.Errorf("job %d (%s) pending = %v, want %v", i, job.Kind, left[job.ID], want)
+		}
+	}
+}
@@ -0,0 +1,351 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+
+	"Booking/api-service-booking/internal/entity"
+	errorspkg "Booking/api-service-booking/internal/errors"
+	"Booking/api-service-booking/internal/infrastructure/repository/postgresql/repo"
+	"Booking/api-service-booking/internal/pkg/postgres"
+)
+
+type loyaltyRepo struct {
+	tableName string
+	db        *postgres.PostgresDB
+}
+
+func NewLoyaltyRepo(db *postgres.PostgresDB) repo.LoyaltyRepo {
+	return &loyaltyRepo{
+		tableName: "loyalty_entries",
+		db:        db,
+	}
+}
+
+// settle locks the ledger of a user for the rest of tx, writes expiry when
+// points are due to expire and returns the balance after it
+func (r *loyaltyRepo) settle(ctx context.Context, tx pgx.Tx, expiry *entity.LoyaltyEntry) (int64, error) {
+	if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock(hashtext($1))", expiry.UserID); err != nil {
+		return 0, r.db.Error(err)
+	}
+
+	sqlStr, args, err := r.db.Sq.Builder.
+		Select("kind", "points", "booking_id::text", "expires_at", "created_at").
+		From(r.tableName).
+		Where(r.db.Sq.Equal("user_id", expiry.UserID)).
+		OrderBy("created_at", "id").
+		ToSql()
+	if err != nil {
+		return 0, r.db.ErrSQLBuild(err, r.tableName+" ledger")
+	}
+
+	rows, err := tx.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return 0, r.db.Error(err)
+	}
+	defer rows.Close()
+
+	var ledger []*entity.LoyaltyEntry
+	for rows.Next() {
+		var (
+			entry     entity.LoyaltyEntry
+			bookingID sql.NullString
+		)
+		if err = rows.Scan(&entry.Kind, &entry.Points, &bookingID, &entry.ExpiresAt, &entry.CreatedAt); err != nil {
+			return 0, r.db.Error(err)
+		}
+		entry.BookingID = bookingID.String
+		ledger = append(ledger, &entry)
+	}
+	if err = rows.Err(); err != nil {
+		return 0, r.db.Error(err)
+	}
+	// the connection is needed for the insert
+	rows.Close()
+
+	var balance int64
+	for _, entry := range ledger {
+		balance += entry.Points
+	}
+	due := dueExpiry(ledger, expiry.CreatedAt)
+	if due > balance {
+		due = balance
+	}
+	if due <= 0 {
+		return balance, nil
+	}
+
+	expiry.Points = -due
+	if err = r.insert(ctx, tx, expiry); err != nil {
+		return 0, err
+	}
+	return balance - due, nil
+}
+
+// pointLot is what is left of one credit of points, nil expiresAt never
+// expires
+type pointLot struct {
+	points    int64
+	expiresAt *time.Time
+}
+
+// dueExpiry replays ledger, in the order it was written, and returns how
+// many points have expired by at beyond what the expire entries already
+// took. Debits are taken from the lots closest to expiry first. Points
+// given back for a canceled booking are a new lot with the earliest expiry
+// still to come when they are given back, or none if no points are left
+// to expire, so a refund neither revives expired points nor lets the
+// points of the redeem it reverses expire at once.
+func dueExpiry(ledger []*entity.LoyaltyEntry, at time.Time) int64 {
+	var (
+		lots            []*pointLot
+		expired, logged int64
+	)
+	expire := func(now time.Time) {
+		for _, lot := range lots {
+			if lot.expiresAt != nil && !lot.expiresAt.After(now) {
+				expired += lot.points
+				lot.points = 0
+			}
+		}
+	}
+	debit := func(points int64) {
+		sort.SliceStable(lots, func(i, j int) bool {
+			a, b := lots[i].expiresAt, lots[j].expiresAt
+			return a != nil && (b == nil || a.Before(*b))
+		})
+		for _, lot := range lots {
+			taken := min(points, lot.points)
+			lot.points -= taken
+			points -= taken
+		}
+	}
+
+	for _, entry := range ledger {
+		expire(entry.CreatedAt)
+		switch {
+		case entry.Kind == entity.LoyaltyKindExpire:
+			logged -= entry.Points
+		case entry.Points < 0:
+			debit(-entry.Points)
+		case entry.Kind == entity.LoyaltyKindEarn:
+			lots = append(lots, &pointLot{points: entry.Points, expiresAt: entry.ExpiresAt})
+		case entry.BookingID != "":
+			lot := pointLot{points: entry.Points}
+			for _, live := range lots {
+				if live.points > 0 && live.expiresAt != nil && (lot.expiresAt == nil || live.expiresAt.Before(*lot.expiresAt)) {
+					lot.expiresAt = live.expiresAt
+				}
+			}
+			lots = append(lots, &lot)
+		default:
+			lots = append(lots, &pointLot{points: entry.Points})
+		}
+	}
+	expire(at)
+
+	return expired - logged
+}
+
+func (r *loyaltyRepo) insert(ctx context.Context, tx pgx.Tx, m *entity.LoyaltyEntry) error {
+	sqlStr, args, err := r.db.Sq.Builder.
+		Insert(r.tableName).
+		SetMap(map[string]interface{}{
+			"id":         m.ID,
+			"user_id":    m.UserID,
+			"kind":       m.Kind,
+			"points":     m.Points,
+			"booking_id": nullString(m.BookingID),
+			"reason":     m.Reason,
+			"created_by": nullString(m.CreatedBy),
+			"expires_at": m.ExpiresAt,
+			"created_at": m.CreatedAt,
+		}).
+		ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.tableName+" create")
+	}
+
+	if _, err = tx.Exec(ctx, sqlStr, args...); err != nil {
+		return r.db.Error(err)
+	}
+	return nil
+}
+
+func (r *loyaltyRepo) Append(ctx context.Context, m, expiry *entity.LoyaltyEntry) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return r.db.Error(err)
+	}
+	defer tx.Rollback(ctx)
+
+	balance, err := r.settle(ctx, tx, expiry)
+	if err != nil {
+		return err
+	}
+	if m.Points < 0 && balance+m.Points < 0 {
+		return errorspkg.ErrorNotAvailable
+	}
+
+	if err = r.insert(ctx, tx, m); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return r.db.Error(err)
+	}
+	return nil
+}
+
+func (r *loyaltyRepo) Balance(ctx context.Context, expiry *entity.LoyaltyEntry) (int64, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, r.db.Error(err)
+	}
+	defer tx.Rollback(ctx)
+
+	balance, err := r.settle(ctx, tx, expiry)
+	if err != nil {
+		return 0, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return 0, r.db.Error(err)
+	}
+	return balance, nil
+}
+
+func (r *loyaltyRepo) scan(rows pgx.Rows) (*entity.LoyaltyEntry, error) {
+	var (
+		entry     entity.LoyaltyEntry
+		bookingID sql.NullString
+		createdBy sql.NullString
+	)
+	if err := rows.Scan(
+		&entry.ID,
+		&entry.UserID,
+		&entry.Kind,
+		&entry.Points,
+		&bookingID,
+		&entry.Reason,
+		&createdBy,
+		&entry.ExpiresAt,
+		&entry.CreatedAt,
+	); err != nil {
+		return nil, r.db.Error(err)
+	}
+	entry.BookingID = bookingID.String
+	entry.CreatedBy = createdBy.String
+
+	return &entry, nil
+}
+
+func (r *loyaltyRepo) History(ctx context.Context, userID string, limit, offset uint64) ([]*entity.LoyaltyEntry, uint64, error) {
+	where := r.db.Sq.Equal("user_id", userID)
+
+	sqlStr, args, err := r.db.Sq.Builder.
+		Select(
+			"id",
+			"user_id",
+			"kind",
+			"points",
+			"booking_id::text",
+			"reason",
+			"created_by::text",
+			"expires_at",
+			"created_at",
+		).
+		From(r.tableName).
+		Where(where).
+		OrderBy("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		ToSql()
+	if err != nil {
+		return nil, 0, r.db.ErrSQLBuild(err, r.tableName+" list")
+	}
+
+	rows, err := r.db.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, 0, r.db.Error(err)
+	}
+	defer rows.Close()
+
+	var entries []*entity.LoyaltyEntry
+	for rows.Next() {
+		entry, err := r.scan(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		entries = append(entries, entry)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, 0, r.db.Error(err)
+	}
+
+	countStr, countArgs, err := r.db.Sq.Builder.
+		Select("COUNT(*)").
+		From(r.tableName).
+		Where(where).
+		ToSql()
+	if err != nil {
+		return nil, 0, r.db.ErrSQLBuild(err, r.tableName+" count")
+	}
+
+	var count uint64
+	if err = r.db.QueryRow(ctx, countStr, countArgs...).Scan(&count); err != nil {
+		return nil, 0, r.db.Error(err)
+	}
+
+	return entries, count, nil
+}
+
+func (r *loyaltyRepo) GetByBooking(ctx context.Context, bookingID, kind string) (*entity.LoyaltyEntry, error) {
+	sqlStr, args, err := r.db.Sq.Builder.
+		Select(
+			"id",
+			"user_id",
+			"kind",
+			"points",
+			"booking_id::text",
+			"reason",
+			"created_by::text",
+			"expires_at",
+			"created_at",
+		).
+		From(r.tableName).
+		Where(r.db.Sq.And(
+			r.db.Sq.Equal("booking_id", bookingID),
+			r.db.Sq.Equal("kind", kind),
+		)).
+		ToSql()
+	if err != nil {
+		return nil, r.db.ErrSQLBuild(err, r.tableName+" read")
+	}
+
+	rows, err := r.db.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, r.db.Error(err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err = rows.Err(); err != nil {
+			return nil, r.db.Error(err)
+		}
+		return nil, r.db.Error(pgx.ErrNoRows)
+	}
+	return r.scan(rows)
+}
+
+// nullString stores empty optional ids as NULL
+func nullString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
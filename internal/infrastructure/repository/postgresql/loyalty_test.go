@@ -0,0 +1,164 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Booking/api-service-booking/internal/entity"
+	errorspkg "Booking/api-service-booking/internal/errors"
+)
+
+func TestDueExpiry(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2026, 1, d, 12, 0, 0, 0, time.UTC)
+	}
+	expiresOn := func(d int) *time.Time {
+		at := day(d)
+		return &at
+	}
+	earn := func(created, expires int, points int64) *entity.LoyaltyEntry {
+		return &entity.LoyaltyEntry{Kind: entity.LoyaltyKindEarn, Points: points, ExpiresAt: expiresOn(expires), CreatedAt: day(created)}
+	}
+	redeem := func(created int, points int64) *entity.LoyaltyEntry {
+		return &entity.LoyaltyEntry{Kind: entity.LoyaltyKindRedeem, Points: -points, BookingID: "b", CreatedAt: day(created)}
+	}
+	refund := func(created int, points int64) *entity.LoyaltyEntry {
+		return &entity.LoyaltyEntry{Kind: entity.LoyaltyKindAdjust, Points: points, BookingID: "b", CreatedAt: day(created)}
+	}
+	adjust := func(created int, points int64) *entity.LoyaltyEntry {
+		return &entity.LoyaltyEntry{Kind: entity.LoyaltyKindAdjust, Points: points, CreatedAt: day(created)}
+	}
+	expire := func(created int, points int64) *entity.LoyaltyEntry {
+		return &entity.LoyaltyEntry{Kind: entity.LoyaltyKindExpire, Points: -points, CreatedAt: day(created)}
+	}
+
+	tests := []struct {
+		name   string
+		ledger []*entity.LoyaltyEntry
+		at     int
+		want   int64
+	}{
+		{
+			name:   "nothing expired yet",
+			ledger: []*entity.LoyaltyEntry{earn(1, 10, 100)},
+			at:     9,
+			want:   0,
+		},
+		{
+			name:   "expires on its day",
+			ledger: []*entity.LoyaltyEntry{earn(1, 10, 100)},
+			at:     10,
+			want:   100,
+		},
+		{
+			name:   "redeem takes the points closest to expiry",
+			ledger: []*entity.LoyaltyEntry{earn(1, 20, 100), earn(2, 10, 50), redeem(3, 60)},
+			at:     15,
+			want:   0,
+		},
+		{
+			name:   "points spent after they expired are not spent twice",
+			ledger: []*entity.LoyaltyEntry{earn(1, 10, 100), earn(2, 20, 50), redeem(12, 30)},
+			at:     15,
+			want:   100,
+		},
+		{
+			name:   "a refund puts the points back instead of counting as spent",
+			ledger: []*entity.LoyaltyEntry{earn(1, 10, 100), redeem(2, 40), refund(3, 40)},
+			at:     10,
+			want:   100,
+		},
+		{
+			name:   "refunded points take the earliest expiry still to come",
+			ledger: []*entity.LoyaltyEntry{earn(1, 10, 100), earn(2, 30, 50), redeem(3, 100), refund(12, 100)},
+			at:     30,
+			want:   150,
+		},
+		{
+			name:   "refunded points do not expire with the points they were taken from",
+			ledger: []*entity.LoyaltyEntry{earn(1, 10, 100), earn(2, 30, 50), redeem(3, 100), refund(12, 100)},
+			at:     20,
+			want:   0,
+		},
+		{
+			name:   "refunded points with nothing left to expire keep",
+			ledger: []*entity.LoyaltyEntry{earn(1, 10, 100), redeem(3, 100), refund(12, 100)},
+			at:     40,
+			want:   0,
+		},
+		{
+			name:   "what was already expired is not due again",
+			ledger: []*entity.LoyaltyEntry{earn(1, 10, 100), earn(2, 20, 50), expire(11, 100)},
+			at:     21,
+			want:   50,
+		},
+		{
+			name:   "adjusted points do not expire",
+			ledger: []*entity.LoyaltyEntry{adjust(1, 30), earn(1, 10, 100), adjust(2, -20)},
+			at:     11,
+			want:   80,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dueExpiry(tt.ledger, day(tt.at)); got != tt.want {
+				t.Errorf("dueExpiry() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoyaltyRepoSettle(t *testing.T) {
+	db := testDB(t)
+	r := NewLoyaltyRepo(db)
+	ctx := context.Background()
+
+	userID, bookingID := uuid.NewString(), uuid.NewString()
+	cleanup(t, db, "loyalty_entries", "user_id", userID)
+
+	start := time.Now().UTC().Add(-30 * 24 * time.Hour).Truncate(time.Second)
+	at := func(days int) time.Time {
+		return start.Add(time.Duration(days) * 24 * time.Hour)
+	}
+	entry := func(kind string, points int64, days int) *entity.LoyaltyEntry {
+		return &entity.LoyaltyEntry{ID: uuid.NewString(), UserID: userID, Kind: kind, Points: points, CreatedAt: at(days)}
+	}
+	expiry := func(days int) *entity.LoyaltyEntry {
+		return entry(entity.LoyaltyKindExpire, 0, days)
+	}
+
+	soon, late := at(10), at(60)
+	early := entry(entity.LoyaltyKindEarn, 100, 0)
+	early.ExpiresAt = &soon
+	later := entry(entity.LoyaltyKindEarn, 50, 1)
+	later.ExpiresAt = &late
+	redeem := entry(entity.LoyaltyKindRedeem, -100, 2)
+	redeem.BookingID = bookingID
+	refund := entry(entity.LoyaltyKindAdjust, 100, 12)
+	refund.BookingID = bookingID
+
+	for _, m := range []*entity.LoyaltyEntry{early, later, redeem, refund} {
+		if err := r.Append(ctx, m, expiry(int(m.CreatedAt.Sub(start).Hours()/24))); err != nil {
+			t.Fatalf("Append(%s %d): %v", m.Kind, m.Points, err)
+		}
+	}
+
+	// the 100 redeemed before they expired and refunded after keep until
+	// the other points expire
+	balance, err := r.Balance(ctx, expiry(20))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance != 150 {
+		t.Errorf("Balance() = %d, want 150", balance)
+	}
+
+	spend := entry(entity.LoyaltyKindRedeem, -200, 21)
+	if err = r.Append(ctx, spend, expiry(21)); !errors.Is(err, errorspkg.ErrorNotAvailable) {
+		t.Errorf("Append() over the balance error = %v, want not available", err)
+	}
+}
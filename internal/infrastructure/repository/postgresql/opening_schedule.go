@@ -0,0 +1,141 @@
+package postgresql
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+
+	"Booking/api-service-booking/internal/entity"
+	"Booking/api-service-booking/internal/infrastructure/repository/postgresql/repo"
+	"Booking/api-service-booking/internal/pkg/openinghours"
+	"Booking/api-service-booking/internal/pkg/postgres"
+)
+
+type openingScheduleRepo struct {
+	tableName string
+	db        *postgres.PostgresDB
+}
+
+func NewOpeningScheduleRepo(db *postgres.PostgresDB) repo.OpeningScheduleRepo {
+	return &openingScheduleRepo{
+		tableName: "opening_schedules",
+		db:        db,
+	}
+}
+
+// Schedules are kept in their written form, a week keyed by day name and
+// exceptions keyed by date
+func (r *openingScheduleRepo) Save(ctx context.Context, m *entity.OpeningSchedule) error {
+	week, exceptions := m.Schedule.Format()
+
+	sqlStr, args, err := r.db.Sq.Builder.
+		Insert(r.tableName).
+		SetMap(map[string]interface{}{
+			"category":         m.Category,
+			"establishment_id": m.EstablishmentID,
+			"week":             week,
+			"exceptions":       exceptions,
+			"updated_at":       m.UpdatedAt,
+		}).
+		Suffix("ON CONFLICT (category, establishment_id) DO UPDATE SET week = EXCLUDED.week, exceptions = EXCLUDED.exceptions, updated_at = EXCLUDED.updated_at").
+		ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.tableName+" save")
+	}
+
+	if _, err = r.db.Exec(ctx, sqlStr, args...); err != nil {
+		return r.db.Error(err)
+	}
+	return nil
+}
+
+func (r *openingScheduleRepo) Get(ctx context.Context, category, establishmentID string) (*entity.OpeningSchedule, error) {
+	schedules, err := r.list(ctx, r.db.Sq.And(
+		r.db.Sq.Equal("category", category),
+		r.db.Sq.Equal("establishment_id", establishmentID),
+	))
+	if err != nil {
+		return nil, err
+	}
+	if len(schedules) == 0 {
+		return nil, r.db.Error(pgx.ErrNoRows)
+	}
+	return schedules[0], nil
+}
+
+func (r *openingScheduleRepo) Delete(ctx context.Context, category, establishmentID string) error {
+	sqlStr, args, err := r.db.Sq.Builder.
+		Delete(r.tableName).
+		Where(r.db.Sq.And(
+			r.db.Sq.Equal("category", category),
+			r.db.Sq.Equal("establishment_id", establishmentID),
+		)).
+		ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.tableName+" delete")
+	}
+
+	commandTag, err := r.db.Exec(ctx, sqlStr, args...)
+	if err != nil {
+		return r.db.Error(err)
+	}
+	if commandTag.RowsAffected() == 0 {
+		return r.db.Error(pgx.ErrNoRows)
+	}
+	return nil
+}
+
+func (r *openingScheduleRepo) List(ctx context.Context, category string, ids []string) ([]*entity.OpeningSchedule, error) {
+	where := r.db.Sq.And(r.db.Sq.Equal("category", category))
+	if len(ids) > 0 {
+		where = append(where, r.db.Sq.Equal("establishment_id", ids))
+	}
+	return r.list(ctx, where)
+}
+
+func (r *openingScheduleRepo) list(ctx context.Context, where sq.Sqlizer) ([]*entity.OpeningSchedule, error) {
+	sqlStr, args, err := r.db.Sq.Builder.
+		Select(
+			"category",
+			"establishment_id",
+			"week",
+			"exceptions",
+			"updated_at",
+		).
+		From(r.tableName).
+		Where(where).
+		ToSql()
+	if err != nil {
+		return nil, r.db.ErrSQLBuild(err, r.tableName+" list")
+	}
+
+	rows, err := r.db.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, r.db.Error(err)
+	}
+	defer rows.Close()
+
+	var schedules []*entity.OpeningSchedule
+	for rows.Next() {
+		var (
+			schedule         entity.OpeningSchedule
+			week, exceptions map[string]string
+		)
+		if err = rows.Scan(
+			&schedule.Category,
+			&schedule.EstablishmentID,
+			&week,
+			&exceptions,
+			&schedule.UpdatedAt,
+		); err != nil {
+			return nil, r.db.Error(err)
+		}
+		// what was saved was validated, so this only fails on a hand edit
+		if schedule.Schedule, err = openinghours.ParseSchedule(week, exceptions); err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, &schedule)
+	}
+	return schedules, rows.Err()
+}
@@ -0,0 +1,412 @@
+package postgresql
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+
+	"Booking/api-service-booking/internal/entity"
+	errorspkg "Booking/api-service-booking/internal/errors"
+	"Booking/api-service-booking/internal/infrastructure/repository/postgresql/repo"
+	"Booking/api-service-booking/internal/pkg/postgres"
+)
+
+type promotionRepo struct {
+	tableName       string
+	redemptionTable string
+	db              *postgres.PostgresDB
+}
+
+func NewPromotionRepo(db *postgres.PostgresDB) repo.PromotionRepo {
+	return &promotionRepo{
+		tableName:       "promotions",
+		redemptionTable: "promo_redemptions",
+		db:              db,
+	}
+}
+
+func (r *promotionRepo) selectQuery() sq.SelectBuilder {
+	return r.db.Sq.Builder.
+		Select(
+			"id",
+			"code",
+			"description",
+			"kind",
+			"value",
+			"category",
+			"city",
+			"first_booking_only",
+			"min_price",
+			"max_redemptions",
+			"per_user_limit",
+			"redemptions",
+			"starts_at",
+			"ends_at",
+			"created_at",
+			"updated_at",
+		).
+		From(r.tableName)
+}
+
+func (r *promotionRepo) scan(ctx context.Context, sqlStr string, args []interface{}) ([]*entity.Promotion, error) {
+	rows, err := r.db.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, r.db.Error(err)
+	}
+	defer rows.Close()
+
+	var promotions []*entity.Promotion
+	for rows.Next() {
+		var promotion entity.Promotion
+		if err = rows.Scan(
+			&promotion.ID,
+			&promotion.Code,
+			&promotion.Description,
+			&promotion.Kind,
+			&promotion.Value,
+			&promotion.Category,
+			&promotion.City,
+			&promotion.FirstBookingOnly,
+			&promotion.MinPrice,
+			&promotion.MaxRedemptions,
+			&promotion.PerUserLimit,
+			&promotion.Redemptions,
+			&promotion.StartsAt,
+			&promotion.EndsAt,
+			&promotion.CreatedAt,
+			&promotion.UpdatedAt,
+		); err != nil {
+			return nil, r.db.Error(err)
+		}
+		promotions = append(promotions, &promotion)
+	}
+
+	return promotions, rows.Err()
+}
+
+func (r *promotionRepo) getOne(ctx context.Context, where sq.Sqlizer) (*entity.Promotion, error) {
+	sqlStr, args, err := r.selectQuery().
+		Where(r.db.Sq.And(where, r.db.Sq.Equal("deleted_at", nil))).
+		ToSql()
+	if err != nil {
+		return nil, r.db.ErrSQLBuild(err, r.tableName+" read")
+	}
+
+	promotions, err := r.scan(ctx, sqlStr, args)
+	if err != nil {
+		return nil, err
+	}
+	if len(promotions) == 0 {
+		return nil, r.db.Error(pgx.ErrNoRows)
+	}
+	return promotions[0], nil
+}
+
+func (r *promotionRepo) clauses(m *entity.Promotion) map[string]interface{} {
+	return map[string]interface{}{
+		"code":               m.Code,
+		"description":        m.Description,
+		"kind":               m.Kind,
+		"value":              m.Value,
+		"category":           m.Category,
+		"city":               m.City,
+		"first_booking_only": m.FirstBookingOnly,
+		"min_price":          m.MinPrice,
+		"max_redemptions":    m.MaxRedemptions,
+		"per_user_limit":     m.PerUserLimit,
+		"starts_at":          m.StartsAt,
+		"ends_at":            m.EndsAt,
+		"updated_at":         m.UpdatedAt,
+	}
+}
+
+func (r *promotionRepo) Create(ctx context.Context, m *entity.Promotion) error {
+	clauses := r.clauses(m)
+	clauses["id"] = m.ID
+	clauses["created_at"] = m.CreatedAt
+
+	sqlStr, args, err := r.db.Sq.Builder.Insert(r.tableName).SetMap(clauses).ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.tableName+" create")
+	}
+
+	if _, err = r.db.Exec(ctx, sqlStr, args...); err != nil {
+		return r.db.Error(err)
+	}
+	return nil
+}
+
+func (r *promotionRepo) Get(ctx context.Context, id string) (*entity.Promotion, error) {
+	return r.getOne(ctx, r.db.Sq.Equal("id", id))
+}
+
+func (r *promotionRepo) GetByCode(ctx context.Context, code string) (*entity.Promotion, error) {
+	return r.getOne(ctx, r.db.Sq.Expr("UPPER(code) = ?", strings.ToUpper(code)))
+}
+
+func (r *promotionRepo) List(ctx context.Context, limit, offset uint64) ([]*entity.Promotion, uint64, error) {
+	where := r.db.Sq.Equal("deleted_at", nil)
+
+	sqlStr, args, err := r.selectQuery().
+		Where(where).
+		OrderBy("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		ToSql()
+	if err != nil {
+		return nil, 0, r.db.ErrSQLBuild(err, r.tableName+" list")
+	}
+
+	promotions, err := r.scan(ctx, sqlStr, args)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	countStr, countArgs, err := r.db.Sq.Builder.
+		Select("COUNT(*)").
+		From(r.tableName).
+		Where(where).
+		ToSql()
+	if err != nil {
+		return nil, 0, r.db.ErrSQLBuild(err, r.tableName+" count")
+	}
+
+	var count uint64
+	if err = r.db.QueryRow(ctx, countStr, countArgs...).Scan(&count); err != nil {
+		return nil, 0, r.db.Error(err)
+	}
+
+	return promotions, count, nil
+}
+
+func (r *promotionRepo) Update(ctx context.Context, m *entity.Promotion) error {
+	sqlStr, args, err := r.db.Sq.Builder.
+		Update(r.tableName).
+		SetMap(r.clauses(m)).
+		Where(r.db.Sq.And(
+			r.db.Sq.Equal("id", m.ID),
+			r.db.Sq.Equal("deleted_at", nil),
+		)).
+		ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.tableName+" update")
+	}
+
+	commandTag, err := r.db.Exec(ctx, sqlStr, args...)
+	if err != nil {
+		return r.db.Error(err)
+	}
+	if commandTag.RowsAffected() == 0 {
+		return r.db.Error(pgx.ErrNoRows)
+	}
+	return nil
+}
+
+func (r *promotionRepo) Delete(ctx context.Context, id string, deletedAt time.Time) error {
+	sqlStr, args, err := r.db.Sq.Builder.
+		Update(r.tableName).
+		Set("deleted_at", deletedAt).
+		Where(r.db.Sq.And(
+			r.db.Sq.Equal("id", id),
+			r.db.Sq.Equal("deleted_at", nil),
+		)).
+		ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.tableName+" delete")
+	}
+
+	commandTag, err := r.db.Exec(ctx, sqlStr, args...)
+	if err != nil {
+		return r.db.Error(err)
+	}
+	if commandTag.RowsAffected() == 0 {
+		return r.db.Error(pgx.ErrNoRows)
+	}
+	return nil
+}
+
+func (r *promotionRepo) CountRedemptions(ctx context.Context, promotionID, userID string) (int, error) {
+	sqlStr, args, err := r.db.Sq.Builder.
+		Select("COUNT(*)").
+		From(r.redemptionTable).
+		Where(r.db.Sq.And(
+			r.db.Sq.Equal("promotion_id", promotionID),
+			r.db.Sq.Equal("user_id", userID),
+		)).
+		ToSql()
+	if err != nil {
+		return 0, r.db.ErrSQLBuild(err, r.redemptionTable+" count")
+	}
+
+	var count int
+	if err = r.db.QueryRow(ctx, sqlStr, args...).Scan(&count); err != nil {
+		return 0, r.db.Error(err)
+	}
+	return count, nil
+}
+
+func (r *promotionRepo) Redeem(ctx context.Context, m *entity.PromoRedemption, maxRedemptions, perUserLimit int) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return r.db.Error(err)
+	}
+	defer tx.Rollback(ctx)
+
+	// the conditional increment both enforces the total limit and locks the
+	// promotion row, so the per user count below can not race either
+	where := r.db.Sq.And(
+		r.db.Sq.Equal("id", m.PromotionID),
+		r.db.Sq.Equal("deleted_at", nil),
+	)
+	if maxRedemptions > 0 {
+		where = append(where, r.db.Sq.Lt("redemptions", maxRedemptions))
+	}
+
+	incStr, incArgs, err := r.db.Sq.Builder.
+		Update(r.tableName).
+		Set("redemptions", sq.Expr("redemptions + 1")).
+		Where(where).
+		ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.tableName+" redeem")
+	}
+
+	commandTag, err := tx.Exec(ctx, incStr, incArgs...)
+	if err != nil {
+		return r.db.Error(err)
+	}
+	if commandTag.RowsAffected() == 0 {
+		return errorspkg.ErrorNotAvailable
+	}
+
+	if perUserLimit > 0 {
+		countStr, countArgs, err := r.db.Sq.Builder.
+			Select("COUNT(*)").
+			From(r.redemptionTable).
+			Where(r.db.Sq.And(
+				r.db.Sq.Equal("promotion_id", m.PromotionID),
+				r.db.Sq.Equal("user_id", m.UserID),
+			)).
+			ToSql()
+		if err != nil {
+			return r.db.ErrSQLBuild(err, r.redemptionTable+" count")
+		}
+
+		var used int
+		if err = tx.QueryRow(ctx, countStr, countArgs...).Scan(&used); err != nil {
+			return r.db.Error(err)
+		}
+		if used >= perUserLimit {
+			return errorspkg.ErrorLimitReached
+		}
+	}
+
+	insertStr, insertArgs, err := r.db.Sq.Builder.
+		Insert(r.redemptionTable).
+		SetMap(map[string]interface{}{
+			"id":            m.ID,
+			"promotion_id":  m.PromotionID,
+			"user_id":       m.UserID,
+			"booking_id":    m.BookingID,
+			"discount":      m.Discount,
+			"first_booking": m.FirstBooking,
+			"created_at":    m.CreatedAt,
+		}).
+		ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.redemptionTable+" create")
+	}
+	// the unique index on first booking uses keeps a user to one of them
+	// across all promotions, which the promotion row lock does not cover
+	if _, err = tx.Exec(ctx, insertStr, insertArgs...); err != nil {
+		return r.db.Error(err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return r.db.Error(err)
+	}
+	return nil
+}
+
+func (r *promotionRepo) GetRedemption(ctx context.Context, bookingID string) (*entity.PromoRedemption, error) {
+	sqlStr, args, err := r.db.Sq.Builder.
+		Select(
+			"id",
+			"promotion_id",
+			"user_id",
+			"booking_id",
+			"discount",
+			"first_booking",
+			"created_at",
+		).
+		From(r.redemptionTable).
+		Where(r.db.Sq.Equal("booking_id", bookingID)).
+		ToSql()
+	if err != nil {
+		return nil, r.db.ErrSQLBuild(err, r.redemptionTable+" read")
+	}
+
+	var redemption entity.PromoRedemption
+	if err = r.db.QueryRow(ctx, sqlStr, args...).Scan(
+		&redemption.ID,
+		&redemption.PromotionID,
+		&redemption.UserID,
+		&redemption.BookingID,
+		&redemption.Discount,
+		&redemption.FirstBooking,
+		&redemption.CreatedAt,
+	); err != nil {
+		return nil, r.db.Error(err)
+	}
+
+	return &redemption, nil
+}
+
+func (r *promotionRepo) Release(ctx context.Context, bookingID string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return r.db.Error(err)
+	}
+	defer tx.Rollback(ctx)
+
+	deleteStr, deleteArgs, err := r.db.Sq.Builder.
+		Delete(r.redemptionTable).
+		Where(r.db.Sq.Equal("booking_id", bookingID)).
+		Suffix("RETURNING promotion_id").
+		ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.redemptionTable+" delete")
+	}
+
+	var promotionID string
+	err = tx.QueryRow(ctx, deleteStr, deleteArgs...).Scan(&promotionID)
+	if err == pgx.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return r.db.Error(err)
+	}
+
+	decStr, decArgs, err := r.db.Sq.Builder.
+		Update(r.tableName).
+		Set("redemptions", sq.Expr("redemptions - 1")).
+		Where(r.db.Sq.And(
+			r.db.Sq.Equal("id", promotionID),
+			r.db.Sq.Gt("redemptions", 0),
+		)).
+		ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.tableName+" release")
+	}
+	if _, err = tx.Exec(ctx, decStr, decArgs...); err != nil {
+		return r.db.Error(err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return r.db.Error(err)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Booking/api-service-booking/internal/entity"
+	errorspkg "Booking/api-service-booking/internal/errors"
+)
+
+func TestPromotionRepoGetByCode(t *testing.T) {
+	db := testDB(t)
+	r := NewPromotionRepo(db)
+	ctx := context.Background()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	m := entity.Promotion{
+		ID:        uuid.NewString(),
+		Code:      "Spring" + uuid.NewString()[:8],
+		Kind:      entity.PromoKindPercent,
+		Value:     10,
+		StartsAt:  now,
+		EndsAt:    now.Add(24 * time.Hour),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := r.Create(ctx, &m); err != nil {
+		t.Fatal(err)
+	}
+	cleanup(t, db, "promotions", "id", m.ID)
+
+	tests := []struct {
+		name    string
+		code    string
+		wantErr error
+	}{
+		{name: "same case", code: m.Code},
+		{name: "other case", code: "sPRING" + m.Code[6:]},
+		{name: "unknown", code: "missing-" + m.Code, wantErr: errorspkg.ErrorNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.GetByCode(ctx, tt.code)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.ID != m.ID {
+				t.Errorf("id = %s, want %s", got.ID, m.ID)
+			}
+		})
+	}
+}
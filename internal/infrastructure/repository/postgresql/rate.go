@@ -0,0 +1,73 @@
+package postgresql
+
+import (
+	"context"
+
+	"Booking/api-service-booking/internal/entity"
+	"Booking/api-service-booking/internal/infrastructure/repository/postgresql/repo"
+	"Booking/api-service-booking/internal/pkg/postgres"
+)
+
+type rateRepo struct {
+	tableName string
+	db        *postgres.PostgresDB
+}
+
+func NewRateRepo(db *postgres.PostgresDB) repo.RateRepo {
+	return &rateRepo{
+		tableName: "establishment_rates",
+		db:        db,
+	}
+}
+
+func (r *rateRepo) Save(ctx context.Context, m *entity.Rate) error {
+	sqlStr, args, err := r.db.Sq.Builder.
+		Insert(r.tableName).
+		SetMap(map[string]interface{}{
+			"category":         m.Category,
+			"establishment_id": m.EstablishmentID,
+			"price":            m.Price,
+			"updated_at":       m.UpdatedAt,
+		}).
+		Suffix("ON CONFLICT (category, establishment_id) DO UPDATE SET price = EXCLUDED.price, updated_at = EXCLUDED.updated_at").
+		ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.tableName+" save")
+	}
+
+	if _, err = r.db.Exec(ctx, sqlStr, args...); err != nil {
+		return r.db.Error(err)
+	}
+	return nil
+}
+
+func (r *rateRepo) Get(ctx context.Context, category, establishmentID string) (*entity.Rate, error) {
+	sqlStr, args, err := r.db.Sq.Builder.
+		Select(
+			"category",
+			"establishment_id",
+			"price",
+			"updated_at",
+		).
+		From(r.tableName).
+		Where(r.db.Sq.And(
+			r.db.Sq.Equal("category", category),
+			r.db.Sq.Equal("establishment_id", establishmentID),
+		)).
+		ToSql()
+	if err != nil {
+		return nil, r.db.ErrSQLBuild(err, r.tableName+" get")
+	}
+
+	var rate entity.Rate
+	if err = r.db.QueryRow(ctx, sqlStr, args...).Scan(
+		&rate.Category,
+		&rate.EstablishmentID,
+		&rate.Price,
+		&rate.UpdatedAt,
+	); err != nil {
+		return nil, r.db.Error(err)
+	}
+
+	return &rate, nil
+}
@@ -0,0 +1,25 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"Booking/api-service-booking/internal/entity"
+)
+
+type AttractionTicketRepo interface {
+	CreateType(ctx context.Context, m *entity.TicketType) error
+	ListTypes(ctx context.Context, attractionID string) ([]*entity.TicketType, error)
+	DeleteType(ctx context.Context, id string, deletedAt time.Time) error
+	SaveSettings(ctx context.Context, m *entity.EntrySettings) error
+	GetSettings(ctx context.Context, attractionID string) (*entity.EntrySettings, error)
+	// CountByEntry returns how many active tickets each entry time in
+	// [from, to) has, keyed by the unix time of the entry
+	CountByEntry(ctx context.Context, attractionID string, from, to time.Time) (map[int64]int, error)
+	// Issue stores tickets sharing one entry time unless that would take
+	// the slot over capacity, in which case it returns ErrorNotAvailable.
+	// Active tickets of the same booking are canceled in their place.
+	Issue(ctx context.Context, tickets []*entity.Ticket, capacity int) error
+	ListByBooking(ctx context.Context, bookingID string) ([]*entity.Ticket, error)
+	CancelByBooking(ctx context.Context, bookingID string, canceledAt time.Time) error
+}
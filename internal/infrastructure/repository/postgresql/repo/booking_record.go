@@ -0,0 +1,29 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"Booking/api-service-booking/internal/entity"
+)
+
+type BookingRecordRepo interface {
+	Get(ctx context.Context, id string) (*entity.BookingRecord, error)
+	Create(ctx context.Context, m *entity.BookingRecord) error
+	Update(ctx context.Context, m *entity.BookingRecord) error
+	// ChangeState moves a booking to state only if it is currently in one of from
+	ChangeState(ctx context.Context, id string, from []string, to string, updatedAt time.Time) (bool, error)
+	// CountByUser counts the bookings of a user that are in one of states
+	CountByUser(ctx context.Context, userID string, states []string) (int, error)
+	// ListByEstablishments returns the bookings of establishmentIDs, canceled
+	// ones included, whose stay overlaps [from, to)
+	ListByEstablishments(ctx context.Context, category string, establishmentIDs []string, from, to time.Time) ([]*entity.BookingRecord, error)
+	// LastBookedAt returns when each of userIDs last booked establishmentID,
+	// users without a booking are left out
+	LastBookedAt(ctx context.Context, category, establishmentID string, userIDs []string) (map[string]time.Time, error)
+	// Search returns up to filter.Limit bookings matching filter in its order
+	Search(ctx context.Context, filter *entity.BookingSearch) ([]*entity.BookingRecord, error)
+	CreateChange(ctx context.Context, m *entity.BookingChange) error
+	// ListChanges returns the history of a booking, oldest first
+	ListChanges(ctx context.Context, bookingID string) ([]*entity.BookingChange, error)
+}
@@ -0,0 +1,20 @@
+package repo
+
+import (
+	"context"
+
+	"Booking/api-service-booking/internal/entity"
+)
+
+type EstablishmentImportRepo interface {
+	Create(ctx context.Context, m *entity.EstablishmentImport) error
+	// Update saves the status, progress and errors of an import
+	Update(ctx context.Context, m *entity.EstablishmentImport) error
+	Get(ctx context.Context, id string) (*entity.EstablishmentImport, error)
+	// List returns imports newest first, without their rows and errors
+	List(ctx context.Context, limit, offset uint64) ([]*entity.EstablishmentImport, uint64, error)
+	GetRef(ctx context.Context, category, externalRef string) (*entity.ImportRef, error)
+	SaveRef(ctx context.Context, m *entity.ImportRef) error
+	// DeleteRefs forgets the external refs of a deleted establishment
+	DeleteRefs(ctx context.Context, category, establishmentID string) error
+}
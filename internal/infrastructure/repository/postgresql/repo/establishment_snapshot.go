@@ -0,0 +1,17 @@
+package repo
+
+import (
+	"context"
+
+	"Booking/api-service-booking/internal/entity"
+)
+
+type EstablishmentSnapshotRepo interface {
+	Create(ctx context.Context, m *entity.EstablishmentSnapshot) error
+	// Update saves when a snapshot runs next and how its last run went
+	Update(ctx context.Context, m *entity.EstablishmentSnapshot) error
+	Get(ctx context.Context, id string) (*entity.EstablishmentSnapshot, error)
+	// List returns snapshots newest first
+	List(ctx context.Context, limit, offset uint64) ([]*entity.EstablishmentSnapshot, uint64, error)
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,17 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"Booking/api-service-booking/internal/entity"
+)
+
+type HotelRoomRepo interface {
+	Save(ctx context.Context, m *entity.HotelRooms) error
+	Get(ctx context.Context, hotelID string) (*entity.HotelRooms, error)
+	Hold(ctx context.Context, m *entity.RoomHold) error
+	Held(ctx context.Context, bookingID string) (bool, error)
+	Release(ctx context.Context, bookingID string) error
+	Fullest(ctx context.Context, hotelID, bookingID string, firstNight, leaveDay time.Time) (int, error)
+}
@@ -0,0 +1,15 @@
+package repo
+
+import (
+	"context"
+
+	"Booking/api-service-booking/internal/entity"
+)
+
+type ItineraryRepo interface {
+	Create(ctx context.Context, m *entity.Itinerary) error
+	Get(ctx context.Context, id string) (*entity.Itinerary, error)
+	// ListByUser returns the itineraries of a user, newest first
+	ListByUser(ctx context.Context, userID string) ([]*entity.Itinerary, error)
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,19 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"Booking/api-service-booking/internal/entity"
+)
+
+type JobRepo interface {
+	Create(ctx context.Context, m *entity.Job) error
+	Update(ctx context.Context, m *entity.Job) error
+	ListDue(ctx context.Context, now time.Time, limit uint64) ([]*entity.Job, error)
+	List(ctx context.Context, status string, limit, offset uint64) ([]*entity.Job, uint64, error)
+	CancelByBooking(ctx context.Context, bookingID string, updatedAt time.Time) error
+	// CancelByPayload cancels the pending jobs of kind whose payload has
+	// value under key
+	CancelByPayload(ctx context.Context, kind, key, value string, updatedAt time.Time) error
+}
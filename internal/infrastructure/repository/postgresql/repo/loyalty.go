@@ -0,0 +1,18 @@
+package repo
+
+import (
+	"context"
+
+	"Booking/api-service-booking/internal/entity"
+)
+
+type LoyaltyRepo interface {
+	// Append writes m after expiry, which gets the points of the user that
+	// expired by its CreatedAt and is skipped when there are none. A debit
+	// larger than the balance is ErrorNotAvailable.
+	Append(ctx context.Context, m, expiry *entity.LoyaltyEntry) error
+	// Balance writes expiry like Append and returns the points left
+	Balance(ctx context.Context, expiry *entity.LoyaltyEntry) (int64, error)
+	History(ctx context.Context, userID string, limit, offset uint64) ([]*entity.LoyaltyEntry, uint64, error)
+	GetByBooking(ctx context.Context, bookingID, kind string) (*entity.LoyaltyEntry, error)
+}
@@ -0,0 +1,16 @@
+package repo
+
+import (
+	"context"
+
+	"Booking/api-service-booking/internal/entity"
+)
+
+type OpeningScheduleRepo interface {
+	Save(ctx context.Context, m *entity.OpeningSchedule) error
+	Get(ctx context.Context, category, establishmentID string) (*entity.OpeningSchedule, error)
+	Delete(ctx context.Context, category, establishmentID string) error
+	// List returns the schedules of category, or only those of ids when
+	// ids is not empty
+	List(ctx context.Context, category string, ids []string) ([]*entity.OpeningSchedule, error)
+}
@@ -0,0 +1,26 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"Booking/api-service-booking/internal/entity"
+)
+
+type PromotionRepo interface {
+	Create(ctx context.Context, m *entity.Promotion) error
+	Get(ctx context.Context, id string) (*entity.Promotion, error)
+	GetByCode(ctx context.Context, code string) (*entity.Promotion, error)
+	List(ctx context.Context, limit, offset uint64) ([]*entity.Promotion, uint64, error)
+	Update(ctx context.Context, m *entity.Promotion) error
+	Delete(ctx context.Context, id string, deletedAt time.Time) error
+	CountRedemptions(ctx context.Context, promotionID, userID string) (int, error)
+	// Redeem counts a use of the promotion and stores m in one transaction,
+	// ErrorNotAvailable if that would go over the total usage limit,
+	// ErrorLimitReached over the per user one and ErrorConflict if m is a
+	// first booking use and the user already made one
+	Redeem(ctx context.Context, m *entity.PromoRedemption, maxRedemptions, perUserLimit int) error
+	GetRedemption(ctx context.Context, bookingID string) (*entity.PromoRedemption, error)
+	// Release gives the use made by a booking back
+	Release(ctx context.Context, bookingID string) error
+}
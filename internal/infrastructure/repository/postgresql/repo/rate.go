@@ -0,0 +1,12 @@
+package repo
+
+import (
+	"context"
+
+	"Booking/api-service-booking/internal/entity"
+)
+
+type RateRepo interface {
+	Save(ctx context.Context, m *entity.Rate) error
+	Get(ctx context.Context, category, establishmentID string) (*entity.Rate, error)
+}
@@ -0,0 +1,22 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"Booking/api-service-booking/internal/entity"
+)
+
+type RestaurantTableRepo interface {
+	Create(ctx context.Context, m *entity.RestaurantTable) error
+	List(ctx context.Context, restaurantID string) ([]*entity.RestaurantTable, error)
+	Delete(ctx context.Context, id string, deletedAt time.Time) error
+	// ListReservations returns reservations overlapping [from, to)
+	ListReservations(ctx context.Context, restaurantID string, from, to time.Time) ([]*entity.TableReservation, error)
+	// Reserve puts m on the smallest table that fits and is free for
+	// [StartsAt-turnover, EndsAt+turnover), ErrorNotAvailable if there is none.
+	// An earlier reservation of the same booking is replaced.
+	Reserve(ctx context.Context, m *entity.TableReservation, turnover time.Duration) error
+	GetReservation(ctx context.Context, bookingID string) (*entity.TableReservation, error)
+	DeleteReservation(ctx context.Context, bookingID string) error
+}
@@ -0,0 +1,27 @@
+package repo
+
+import (
+	"context"
+
+	"Booking/api-service-booking/internal/entity"
+)
+
+type SearchDocumentRepo interface {
+	Save(ctx context.Context, m *entity.SearchDocument) error
+	Delete(ctx context.Context, category, establishmentID string) error
+	DeleteCategory(ctx context.Context, category string) error
+	// Search returns the documents whose keys are close to query, best
+	// match first
+	Search(ctx context.Context, query string, filter *entity.EstablishmentSearch) ([]*entity.SearchHit, error)
+	// List returns a page of the documents matching filter, with the values
+	// they are sorted by, and how many match in all. A page before
+	// AfterValue and AfterID comes in reverse order.
+	List(ctx context.Context, filter *entity.EstablishmentList) ([]*entity.ListedEstablishment, int, error)
+	Facets(ctx context.Context, filter *entity.EstablishmentList) (*entity.EstablishmentFacets, error)
+	// Clusters groups all the documents matching filter that have a
+	// location into the cells of a map cells across, ignoring its paging
+	Clusters(ctx context.Context, filter *entity.EstablishmentList, cells float64) ([]*entity.MapCluster, error)
+	// OpeningHours returns the plain opening hours of the documents of
+	// category that have them, by establishment id
+	OpeningHours(ctx context.Context, category string) (map[string]string, error)
+}
@@ -0,0 +1,14 @@
+package repo
+
+import (
+	"context"
+
+	"Booking/api-service-booking/internal/entity"
+)
+
+type StaffRepo interface {
+	Create(ctx context.Context, m *entity.StaffMember) error
+	Delete(ctx context.Context, userID, establishmentID string) error
+	ListByUser(ctx context.Context, userID string) ([]*entity.StaffMember, error)
+	IsMember(ctx context.Context, userID, establishmentID string) (bool, error)
+}
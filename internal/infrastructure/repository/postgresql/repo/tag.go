@@ -0,0 +1,20 @@
+package repo
+
+import (
+	"context"
+
+	"Booking/api-service-booking/internal/entity"
+)
+
+type TagRepo interface {
+	Create(ctx context.Context, m *entity.Tag) error
+	Update(ctx context.Context, m *entity.Tag) error
+	// Delete removes the tag and takes it off every establishment
+	Delete(ctx context.Context, slug string) error
+	// List returns the tags of kind, or all of them when kind is empty
+	List(ctx context.Context, kind string) ([]*entity.Tag, error)
+	// SetEstablishmentTags replaces the tags of an establishment
+	SetEstablishmentTags(ctx context.Context, category, establishmentID string, slugs []string) error
+	// ListEstablishmentTags returns the tags of ids by establishment id
+	ListEstablishmentTags(ctx context.Context, category string, ids []string) (map[string][]string, error)
+}
@@ -0,0 +1,17 @@
+package repo
+
+import (
+	"context"
+
+	"Booking/api-service-booking/internal/entity"
+)
+
+type TranslationRepo interface {
+	Save(ctx context.Context, m *entity.Translation) error
+	Delete(ctx context.Context, category, establishmentID, locale string) error
+	// DeleteAll removes every translation of an establishment
+	DeleteAll(ctx context.Context, category, establishmentID string) error
+	// List returns the translations of ids in locale, or in every locale
+	// when locale is empty
+	List(ctx context.Context, category string, ids []string, locale string) ([]*entity.Translation, error)
+}
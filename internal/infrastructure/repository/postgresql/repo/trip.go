@@ -0,0 +1,33 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"Booking/api-service-booking/internal/entity"
+)
+
+type TripRepo interface {
+	Create(ctx context.Context, m *entity.Trip) error
+	// Get returns a trip with its items
+	Get(ctx context.Context, id string) (*entity.Trip, error)
+	// ListByUser returns the trips of a user with their items, newest first
+	ListByUser(ctx context.Context, userID string) ([]*entity.Trip, error)
+	// Delete removes an open trip and its items
+	Delete(ctx context.Context, id string) error
+	// AddItem stores m unless its trip is no longer open, in which case it
+	// returns ErrorNotAvailable
+	AddItem(ctx context.Context, m *entity.TripItem) error
+	// RemoveItem deletes an item of an open trip
+	RemoveItem(ctx context.Context, tripID, itemID string) error
+	// ChangeStatus moves a trip to status `to` only if it is in one of
+	// `from`, reporting whether it did
+	ChangeStatus(ctx context.Context, id string, from []string, to string, updatedAt time.Time) (bool, error)
+	// Reopen moves a trip that is checking out back to open and clears the
+	// booking ids of its items in the same statement, reporting whether it
+	// did
+	Reopen(ctx context.Context, id string, updatedAt time.Time) (bool, error)
+	// SetBookingIDs saves the booking ids of items, keyed by item id. Items
+	// left out get theirs cleared.
+	SetBookingIDs(ctx context.Context, tripID string, bookingIDs map[string]string) error
+}
@@ -0,0 +1,23 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"Booking/api-service-booking/internal/entity"
+)
+
+type WaitlistRepo interface {
+	Create(ctx context.Context, m *entity.WaitlistEntry) error
+	Get(ctx context.Context, id string) (*entity.WaitlistEntry, error)
+	// ListByUser returns the entries of a user, newest first, with the
+	// position of the waiting ones
+	ListByUser(ctx context.Context, userID string) ([]*entity.WaitlistEntry, error)
+	// ListWaiting returns the line for date ranges overlapping [from, to)
+	ListWaiting(ctx context.Context, category, establishmentID string, from, to time.Time, limit uint64) ([]*entity.WaitlistEntry, error)
+	// ChangeStatus moves an entry to status `to` only if it is in one of
+	// `from`, reporting whether it did
+	ChangeStatus(ctx context.Context, id string, from []string, to string, offerExpiresAt *time.Time, updatedAt time.Time) (bool, error)
+	// Demand groups the open entries of an establishment by arrival date
+	Demand(ctx context.Context, category, establishmentID string, from, to time.Time) ([]*entity.WaitlistDemand, error)
+}
@@ -0,0 +1,316 @@
+package postgresql
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+
+	"Booking/api-service-booking/internal/entity"
+	errorspkg "Booking/api-service-booking/internal/errors"
+	"Booking/api-service-booking/internal/infrastructure/repository/postgresql/repo"
+	"Booking/api-service-booking/internal/pkg/postgres"
+)
+
+type restaurantTableRepo struct {
+	tableName        string
+	reservationTable string
+	db               *postgres.PostgresDB
+}
+
+func NewRestaurantTableRepo(db *postgres.PostgresDB) repo.RestaurantTableRepo {
+	return &restaurantTableRepo{
+		tableName:        "restaurant_tables",
+		reservationTable: "table_reservations",
+		db:               db,
+	}
+}
+
+func (r *restaurantTableRepo) Create(ctx context.Context, m *entity.RestaurantTable) error {
+	clauses := map[string]interface{}{
+		"id":            m.ID,
+		"restaurant_id": m.RestaurantID,
+		"name":          m.Name,
+		"capacity":      m.Capacity,
+		"created_at":    m.CreatedAt,
+		"updated_at":    m.UpdatedAt,
+	}
+
+	sqlStr, args, err := r.db.Sq.Builder.Insert(r.tableName).SetMap(clauses).ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.tableName+" create")
+	}
+
+	if _, err = r.db.Exec(ctx, sqlStr, args...); err != nil {
+		return r.db.Error(err)
+	}
+	return nil
+}
+
+func (r *restaurantTableRepo) List(ctx context.Context, restaurantID string) ([]*entity.RestaurantTable, error) {
+	sqlStr, args, err := r.db.Sq.Builder.
+		Select(
+			"id",
+			"restaurant_id",
+			"name",
+			"capacity",
+			"created_at",
+			"updated_at",
+		).
+		From(r.tableName).
+		Where(r.db.Sq.And(
+			r.db.Sq.Equal("restaurant_id", restaurantID),
+			r.db.Sq.Equal("deleted_at", nil),
+		)).
+		OrderBy("capacity", "name").
+		ToSql()
+	if err != nil {
+		return nil, r.db.ErrSQLBuild(err, r.tableName+" list")
+	}
+
+	rows, err := r.db.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, r.db.Error(err)
+	}
+	defer rows.Close()
+
+	var tables []*entity.RestaurantTable
+	for rows.Next() {
+		var table entity.RestaurantTable
+		if err = rows.Scan(
+			&table.ID,
+			&table.RestaurantID,
+			&table.Name,
+			&table.Capacity,
+			&table.CreatedAt,
+			&table.UpdatedAt,
+		); err != nil {
+			return nil, r.db.Error(err)
+		}
+		tables = append(tables, &table)
+	}
+
+	return tables, rows.Err()
+}
+
+func (r *restaurantTableRepo) Delete(ctx context.Context, id string, deletedAt time.Time) error {
+	sqlStr, args, err := r.db.Sq.Builder.
+		Update(r.tableName).
+		Set("deleted_at", deletedAt).
+		Where(r.db.Sq.And(
+			r.db.Sq.Equal("id", id),
+			r.db.Sq.Equal("deleted_at", nil),
+		)).
+		ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.tableName+" delete")
+	}
+
+	commandTag, err := r.db.Exec(ctx, sqlStr, args...)
+	if err != nil {
+		return r.db.Error(err)
+	}
+	if commandTag.RowsAffected() == 0 {
+		return r.db.Error(pgx.ErrNoRows)
+	}
+	return nil
+}
+
+func (r *restaurantTableRepo) ListReservations(ctx context.Context, restaurantID string, from, to time.Time) ([]*entity.TableReservation, error) {
+	sqlStr, args, err := r.db.Sq.Builder.
+		Select(
+			"id",
+			"booking_id",
+			"table_id",
+			"restaurant_id",
+			"party_size",
+			"starts_at",
+			"ends_at",
+			"created_at",
+		).
+		From(r.reservationTable).
+		Where(r.db.Sq.And(
+			r.db.Sq.Equal("restaurant_id", restaurantID),
+			r.db.Sq.Lt("starts_at", to),
+			r.db.Sq.Gt("ends_at", from),
+		)).
+		OrderBy("starts_at").
+		ToSql()
+	if err != nil {
+		return nil, r.db.ErrSQLBuild(err, r.reservationTable+" list")
+	}
+
+	rows, err := r.db.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, r.db.Error(err)
+	}
+	defer rows.Close()
+
+	var reservations []*entity.TableReservation
+	for rows.Next() {
+		var reservation entity.TableReservation
+		if err = rows.Scan(
+			&reservation.ID,
+			&reservation.BookingID,
+			&reservation.TableID,
+			&reservation.RestaurantID,
+			&reservation.PartySize,
+			&reservation.StartsAt,
+			&reservation.EndsAt,
+			&reservation.CreatedAt,
+		); err != nil {
+			return nil, r.db.Error(err)
+		}
+		reservations = append(reservations, &reservation)
+	}
+
+	return reservations, rows.Err()
+}
+
+func (r *restaurantTableRepo) Reserve(ctx context.Context, m *entity.TableReservation, turnover time.Duration) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return r.db.Error(err)
+	}
+	defer tx.Rollback(ctx)
+
+	// lock the fitting tables first so concurrent bookings of the same
+	// restaurant queue up here and then see each other's reservations
+	lockStr, lockArgs, err := r.db.Sq.Builder.
+		Select("id").
+		From(r.tableName).
+		Where(r.db.Sq.And(
+			r.db.Sq.Equal("restaurant_id", m.RestaurantID),
+			r.db.Sq.Equal("deleted_at", nil),
+			sq.GtOrEq{"capacity": m.PartySize},
+		)).
+		Suffix("FOR UPDATE").
+		ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.tableName+" lock")
+	}
+	if _, err = tx.Exec(ctx, lockStr, lockArgs...); err != nil {
+		return r.db.Error(err)
+	}
+
+	// a booking being moved gives up its own table first, the rollback
+	// puts it back when nothing else is free
+	deleteStr, deleteArgs, err := r.db.Sq.Builder.
+		Delete(r.reservationTable).
+		Where(r.db.Sq.Equal("booking_id", m.BookingID)).
+		ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.reservationTable+" delete")
+	}
+	if _, err = tx.Exec(ctx, deleteStr, deleteArgs...); err != nil {
+		return r.db.Error(err)
+	}
+
+	busy := r.db.Sq.Builder.
+		Select("1").
+		From(r.reservationTable + " AS r").
+		Where("r.table_id = t.id").
+		Where(r.db.Sq.Lt("r.starts_at", m.EndsAt.Add(turnover))).
+		Where(r.db.Sq.Gt("r.ends_at", m.StartsAt.Add(-turnover)))
+
+	freeStr, freeArgs, err := r.db.Sq.Builder.
+		Select("t.id").
+		From(r.tableName+" AS t").
+		Where(r.db.Sq.And(
+			r.db.Sq.Equal("t.restaurant_id", m.RestaurantID),
+			r.db.Sq.Equal("t.deleted_at", nil),
+			sq.GtOrEq{"t.capacity": m.PartySize},
+		)).
+		Where(sq.Expr("NOT EXISTS (?)", busy)).
+		OrderBy("t.capacity", "t.name").
+		Limit(1).
+		ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.tableName+" free table")
+	}
+
+	err = tx.QueryRow(ctx, freeStr, freeArgs...).Scan(&m.TableID)
+	if err == pgx.ErrNoRows {
+		return errorspkg.ErrorNotAvailable
+	}
+	if err != nil {
+		return r.db.Error(err)
+	}
+
+	insertStr, insertArgs, err := r.db.Sq.Builder.
+		Insert(r.reservationTable).
+		SetMap(map[string]interface{}{
+			"id":            m.ID,
+			"booking_id":    m.BookingID,
+			"table_id":      m.TableID,
+			"restaurant_id": m.RestaurantID,
+			"party_size":    m.PartySize,
+			"starts_at":     m.StartsAt,
+			"ends_at":       m.EndsAt,
+			"created_at":    m.CreatedAt,
+		}).
+		ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.reservationTable+" create")
+	}
+	if _, err = tx.Exec(ctx, insertStr, insertArgs...); err != nil {
+		return r.db.Error(err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return r.db.Error(err)
+	}
+	return nil
+}
+
+func (r *restaurantTableRepo) GetReservation(ctx context.Context, bookingID string) (*entity.TableReservation, error) {
+	sqlStr, args, err := r.db.Sq.Builder.
+		Select(
+			"id",
+			"booking_id",
+			"table_id",
+			"restaurant_id",
+			"party_size",
+			"starts_at",
+			"ends_at",
+			"created_at",
+		).
+		From(r.reservationTable).
+		Where(r.db.Sq.Equal("booking_id", bookingID)).
+		ToSql()
+	if err != nil {
+		return nil, r.db.ErrSQLBuild(err, r.reservationTable+" get")
+	}
+
+	var reservation entity.TableReservation
+	if err = r.db.QueryRow(ctx, sqlStr, args...).Scan(
+		&reservation.ID,
+		&reservation.BookingID,
+		&reservation.TableID,
+		&reservation.RestaurantID,
+		&reservation.PartySize,
+		&reservation.StartsAt,
+		&reservation.EndsAt,
+		&reservation.CreatedAt,
+	); err != nil {
+		return nil, r.db.Error(err)
+	}
+
+	return &reservation, nil
+}
+
+func (r *restaurantTableRepo) DeleteReservation(ctx context.Context, bookingID string) error {
+	sqlStr, args, err := r.db.Sq.Builder.
+		Delete(r.reservationTable).
+		Where(r.db.Sq.Equal("booking_id", bookingID)).
+		ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.reservationTable+" delete")
+	}
+
+	if _, err = r.db.Exec(ctx, sqlStr, args...); err != nil {
+		return r.db.Error(err)
+	}
+	return nil
+}
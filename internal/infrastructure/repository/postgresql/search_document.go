@@ -0,0 +1,515 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"Booking/api-service-booking/internal/entity"
+	"Booking/api-service-booking/internal/infrastructure/repository/postgresql/repo"
+	"Booking/api-service-booking/internal/pkg/postgres"
+)
+
+const (
+	// cityFacetSize is how many of the cities with most matches are counted
+	cityFacetSize = 20
+	// tagFacetSize is how many of the tags with most matches are counted
+	tagFacetSize = 30
+	// popularity is how many bookings not canceled an establishment has
+	popularityJoin = "(SELECT establishment_id, COUNT(*) AS bookings FROM booking_records WHERE state <> 'canceled' GROUP BY establishment_id) b ON b.establishment_id = d.establishment_id"
+	// taggedWith keeps the documents that have every one of a list of
+	// tags, the list and its length are the arguments
+	taggedWith = "d.establishment_id IN (SELECT et.establishment_id FROM establishment_tags et WHERE et.category = d.category AND et.tag = ANY(?) GROUP BY et.establishment_id HAVING COUNT(*) = ?)"
+	tagJoin    = "establishment_tags et ON et.category = d.category AND et.establishment_id = d.establishment_id"
+	// distanceOrder orders by the square of an equirectangular distance,
+	// exact enough to sort by within a country
+	distanceOrder = "POWER(d.latitude - ?, 2) + POWER((d.longitude - ?) * COS(RADIANS(?)), 2)"
+	// mapLatitude is the latitude of a document clamped to where web
+	// mercator reaches
+	mapLatitude = "RADIANS(LEAST(GREATEST(d.latitude, -85.05112878), 85.05112878))"
+	// cellX and cellY are the cell of a document on a web mercator map
+	// the argument number of cells across, as geojson.Cluster places it
+	cellX = "FLOOR((d.longitude + 180) / 360 * ?)::INT"
+	cellY = "FLOOR((0.5 - LN((1 + SIN(" + mapLatitude + ")) / (1 - SIN(" + mapLatitude + "))) / (4 * PI())) * ?)::INT"
+	// searchKeys is what the trigram index of search_documents covers
+	searchKeys = "(name_key || ' ' || city_key || ' ' || description_key)"
+	// wordSimilarityThreshold is how close a query has to be to some part
+	// of the keys, the pg_trgm default of 0.6 misses most typos
+	wordSimilarityThreshold = "0.3"
+	// the name weighs most in the score, the rating only breaks near ties
+	searchScore = "(3 * word_similarity(?, name_key) + 2 * word_similarity(?, city_key) + word_similarity(?, description_key)) / 6 + rating / 100"
+)
+
+type searchDocumentRepo struct {
+	tableName string
+	db        *postgres.PostgresDB
+}
+
+func NewSearchDocumentRepo(db *postgres.PostgresDB) repo.SearchDocumentRepo {
+	return &searchDocumentRepo{
+		tableName: "search_documents",
+		db:        db,
+	}
+}
+
+func (r *searchDocumentRepo) Save(ctx context.Context, m *entity.SearchDocument) error {
+	sqlStr, args, err := r.db.Sq.Builder.
+		Insert(r.tableName).
+		SetMap(map[string]interface{}{
+			"category":         m.Category,
+			"establishment_id": m.EstablishmentID,
+			"name":             m.Name,
+			"description":      m.Description,
+			"city":             m.City,
+			"address":          m.Address,
+			"thumbnail":        m.Thumbnail,
+			"rating":           m.Rating,
+			"latitude":         m.Latitude,
+			"longitude":        m.Longitude,
+			"name_key":         m.NameKey,
+			"city_key":         m.CityKey,
+			"description_key":  m.DescriptionKey,
+			"opening_hours":    m.OpeningHours,
+			"created_at":       m.CreatedAt,
+			"updated_at":       m.UpdatedAt,
+		}).
+		Suffix("ON CONFLICT (category, establishment_id) DO UPDATE SET name = EXCLUDED.name, description = EXCLUDED.description, city = EXCLUDED.city, address = EXCLUDED.address, thumbnail = EXCLUDED.thumbnail, rating = EXCLUDED.rating, latitude = EXCLUDED.latitude, longitude = EXCLUDED.longitude, name_key = EXCLUDED.name_key, city_key = EXCLUDED.city_key, description_key = EXCLUDED.description_key, opening_hours = EXCLUDED.opening_hours, created_at = COALESCE(EXCLUDED.created_at, " + r.tableName + ".created_at), updated_at = EXCLUDED.updated_at").
+		ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.tableName+" save")
+	}
+
+	if _, err = r.db.Exec(ctx, sqlStr, args...); err != nil {
+		return r.db.Error(err)
+	}
+	return nil
+}
+
+func (r *searchDocumentRepo) delete(ctx context.Context, where sq.Sqlizer) error {
+	sqlStr, args, err := r.db.Sq.Builder.
+		Delete(r.tableName).
+		Where(where).
+		ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.tableName+" delete")
+	}
+
+	if _, err = r.db.Exec(ctx, sqlStr, args...); err != nil {
+		return r.db.Error(err)
+	}
+	return nil
+}
+
+func (r *searchDocumentRepo) Delete(ctx context.Context, category, establishmentID string) error {
+	return r.delete(ctx, r.db.Sq.And(
+		r.db.Sq.Equal("category", category),
+		r.db.Sq.Equal("establishment_id", establishmentID),
+	))
+}
+
+func (r *searchDocumentRepo) DeleteCategory(ctx context.Context, category string) error {
+	return r.delete(ctx, r.db.Sq.Equal("category", category))
+}
+
+func (r *searchDocumentRepo) Search(ctx context.Context, query string, filter *entity.EstablishmentSearch) ([]*entity.SearchHit, error) {
+	builder := r.db.Sq.Builder.
+		Select(
+			"category",
+			"establishment_id",
+			"name",
+			"description",
+			"city",
+			"address",
+			"thumbnail",
+			"rating",
+			"latitude",
+			"longitude",
+			"updated_at",
+		).
+		Column(sq.Expr(searchScore+" AS score", query, query, query)).
+		From(r.tableName+" d").
+		Where(sq.Expr("? <% "+searchKeys, query)).
+		OrderBy("score DESC", "name").
+		Limit(uint64(filter.Limit)).
+		Offset(uint64(filter.Offset))
+	if len(filter.Categories) > 0 {
+		builder = builder.Where(r.db.Sq.Equal("category", filter.Categories))
+	}
+	if len(filter.Tags) > 0 {
+		builder = builder.Where(sq.Expr(taggedWith, filter.Tags, len(filter.Tags)))
+	}
+
+	sqlStr, args, err := builder.ToSql()
+	if err != nil {
+		return nil, r.db.ErrSQLBuild(err, r.tableName+" search")
+	}
+
+	// the threshold of <% is a setting, set_config with is_local keeps it
+	// to this transaction
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, r.db.Error(err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err = tx.Exec(ctx, "SELECT set_config('pg_trgm.word_similarity_threshold', $1, true)", wordSimilarityThreshold); err != nil {
+		return nil, r.db.Error(err)
+	}
+
+	rows, err := tx.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, r.db.Error(err)
+	}
+	defer rows.Close()
+
+	var hits []*entity.SearchHit
+	for rows.Next() {
+		var (
+			document entity.SearchDocument
+			hit      = entity.SearchHit{Document: &document}
+		)
+		if err = rows.Scan(
+			&document.Category,
+			&document.EstablishmentID,
+			&document.Name,
+			&document.Description,
+			&document.City,
+			&document.Address,
+			&document.Thumbnail,
+			&document.Rating,
+			&document.Latitude,
+			&document.Longitude,
+			&document.UpdatedAt,
+			&hit.Score,
+		); err != nil {
+			return nil, r.db.Error(err)
+		}
+		hits = append(hits, &hit)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, r.db.Error(err)
+	}
+
+	return hits, nil
+}
+
+// listFrom selects from the documents joined with their rates, and with
+// their booking counts when sorting by popularity
+func (r *searchDocumentRepo) listFrom(builder sq.SelectBuilder, popularity bool) sq.SelectBuilder {
+	builder = builder.
+		From(r.tableName + " d").
+		LeftJoin("establishment_rates er ON er.category = d.category AND er.establishment_id = d.establishment_id")
+	if popularity {
+		builder = builder.LeftJoin(popularityJoin)
+	}
+	return builder
+}
+
+// listWhere is the condition of filter, without the filter named by skip
+// so its facet can count the other values
+func (r *searchDocumentRepo) listWhere(filter *entity.EstablishmentList, skip string) sq.And {
+	where := sq.And{r.db.Sq.Equal("d.category", filter.Category)}
+	if len(filter.Cities) > 0 && skip != "city" {
+		where = append(where, r.db.Sq.Equal("d.city", filter.Cities))
+	}
+	if filter.MinRating > 0 && skip != "rating" {
+		where = append(where, sq.GtOrEq{"d.rating": filter.MinRating})
+	}
+	if skip != "price" {
+		if filter.MinPrice > 0 {
+			where = append(where, sq.GtOrEq{"er.price": filter.MinPrice})
+		}
+		if filter.MaxPrice > 0 {
+			where = append(where, sq.LtOrEq{"er.price": filter.MaxPrice})
+		}
+	}
+	if len(filter.Tags) > 0 {
+		where = append(where, sq.Expr(taggedWith, filter.Tags, len(filter.Tags)))
+	}
+	if filter.OpenIDs != nil {
+		where = append(where, r.db.Sq.Equal("d.establishment_id", filter.OpenIDs))
+	}
+	return where
+}
+
+func (r *searchDocumentRepo) List(ctx context.Context, filter *entity.EstablishmentList) ([]*entity.ListedEstablishment, int, error) {
+	sqlStr, args, err := r.listQuery(filter).ToSql()
+	if err != nil {
+		return nil, 0, r.db.ErrSQLBuild(err, r.tableName+" list")
+	}
+
+	rows, err := r.db.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, 0, r.db.Error(err)
+	}
+	defer rows.Close()
+
+	var page []*entity.ListedEstablishment
+	for rows.Next() {
+		var item entity.ListedEstablishment
+		if err = rows.Scan(&item.ID, &item.SortValue); err != nil {
+			return nil, 0, r.db.Error(err)
+		}
+		page = append(page, &item)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, 0, r.db.Error(err)
+	}
+
+	countStr, countArgs, err := r.listFrom(r.db.Sq.Builder.Select("COUNT(*)"), false).
+		Where(r.listWhere(filter, "")).
+		ToSql()
+	if err != nil {
+		return nil, 0, r.db.ErrSQLBuild(err, r.tableName+" count")
+	}
+
+	var total int
+	if err = r.db.QueryRow(ctx, countStr, countArgs...).Scan(&total); err != nil {
+		return nil, 0, r.db.Error(err)
+	}
+
+	return page, total, nil
+}
+
+// listSort is the expression a list is sorted by, its arguments and the
+// type of its values. Documents without a creation time sort last either
+// way.
+func listSort(filter *entity.EstablishmentList) (string, []interface{}, string) {
+	switch filter.SortBy {
+	case entity.EstablishmentSortDistance:
+		return "(" + distanceOrder + ")", []interface{}{filter.Latitude, filter.Longitude, filter.Latitude}, "float8"
+	case entity.EstablishmentSortPopularity:
+		return "COALESCE(b.bookings, 0)", nil, "bigint"
+	case entity.EstablishmentSortNewest:
+		if filter.Desc {
+			return "COALESCE(EXTRACT(EPOCH FROM d.created_at)::float8, '-Infinity')", nil, "float8"
+		}
+		return "COALESCE(EXTRACT(EPOCH FROM d.created_at)::float8, 'Infinity')", nil, "float8"
+	case entity.EstablishmentSortName:
+		return "d.name", nil, "text"
+	default:
+		return "d.rating", nil, "real"
+	}
+}
+
+// listQuery selects a page of the ids that pass filter with the values
+// they are sorted by, after the cursor in AfterValue and AfterID or,
+// walking back, before it
+func (r *searchDocumentRepo) listQuery(filter *entity.EstablishmentList) sq.SelectBuilder {
+	sort, sortArgs, sortType := listSort(filter)
+
+	where := r.listWhere(filter, "")
+	desc := filter.Desc != filter.Before
+	if filter.AfterID != "" {
+		comparison := ">"
+		if desc {
+			comparison = "<"
+		}
+		where = append(where, r.db.Sq.Expr(
+			fmt.Sprintf("(%s, d.establishment_id) %s (?::%s, ?::uuid)", sort, comparison, sortType),
+			append(append([]interface{}{}, sortArgs...), filter.AfterValue, filter.AfterID)...,
+		))
+	}
+
+	order := " ASC"
+	if desc {
+		order = " DESC"
+	}
+	builder := r.listFrom(r.db.Sq.Builder.Select("d.establishment_id"), filter.SortBy == entity.EstablishmentSortPopularity).
+		Column(sq.Expr(sort+"::text", sortArgs...)).
+		Where(where).
+		OrderByClause(sort+order, sortArgs...).
+		OrderBy("d.establishment_id" + order).
+		Limit(uint64(filter.Limit))
+	if filter.AfterID == "" && filter.Offset > 0 {
+		builder = builder.Offset(uint64(filter.Offset))
+	}
+	return builder
+}
+
+func (r *searchDocumentRepo) Facets(ctx context.Context, filter *entity.EstablishmentList) (*entity.EstablishmentFacets, error) {
+	var facets entity.EstablishmentFacets
+
+	cities, err := r.facet(ctx, "city",
+		r.listFrom(r.db.Sq.Builder.Select("d.city", "COUNT(*) AS matches"), false).
+			Where(append(r.listWhere(filter, "city"), sq.NotEq{"d.city": ""})).
+			GroupBy("d.city").
+			OrderBy("matches DESC", "d.city").
+			Limit(cityFacetSize))
+	if err != nil {
+		return nil, err
+	}
+	facets.Cities = cities
+
+	// tags are all required, so this facet keeps its own filter and
+	// counts what adding each tag would leave
+	tags, err := r.facet(ctx, "tag",
+		r.listFrom(r.db.Sq.Builder.Select("et.tag", "COUNT(*) AS matches"), false).
+			Join(tagJoin).
+			Where(r.listWhere(filter, "")).
+			GroupBy("et.tag").
+			OrderBy("matches DESC", "et.tag").
+			Limit(tagFacetSize))
+	if err != nil {
+		return nil, err
+	}
+	facets.Tags = tags
+
+	stars, err := r.facet(ctx, "rating",
+		r.listFrom(r.db.Sq.Builder.Select("FLOOR(d.rating)::INT::TEXT AS stars", "COUNT(*)"), false).
+			Where(r.listWhere(filter, "rating")).
+			GroupBy("stars"))
+	if err != nil {
+		return nil, err
+	}
+	// each rating counts those with at least as many stars
+	for value := 5; value >= 1; value-- {
+		count := 0
+		for _, star := range stars {
+			if n, _ := strconv.Atoi(star.Value); n >= value {
+				count += star.Count
+			}
+		}
+		facets.Ratings = append(facets.Ratings, &entity.Facet{Value: strconv.Itoa(value), Count: count})
+	}
+
+	priceStr, priceArgs, err := r.listFrom(r.db.Sq.Builder.Select("COALESCE(MIN(er.price), 0)", "COALESCE(MAX(er.price), 0)"), false).
+		Where(r.listWhere(filter, "price")).
+		ToSql()
+	if err != nil {
+		return nil, r.db.ErrSQLBuild(err, r.tableName+" price facet")
+	}
+	if err = r.db.QueryRow(ctx, priceStr, priceArgs...).Scan(&facets.MinPrice, &facets.MaxPrice); err != nil {
+		return nil, r.db.Error(err)
+	}
+
+	return &facets, nil
+}
+
+func (r *searchDocumentRepo) Clusters(ctx context.Context, filter *entity.EstablishmentList, cells float64) ([]*entity.MapCluster, error) {
+	sqlStr, args, err := r.clustersQuery(filter, cells).ToSql()
+	if err != nil {
+		return nil, r.db.ErrSQLBuild(err, r.tableName+" clusters")
+	}
+
+	rows, err := r.db.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, r.db.Error(err)
+	}
+	defer rows.Close()
+
+	var clusters []*entity.MapCluster
+	for rows.Next() {
+		var cluster entity.MapCluster
+		if err = rows.Scan(
+			&cluster.X,
+			&cluster.Y,
+			&cluster.Count,
+			&cluster.Latitude,
+			&cluster.Longitude,
+			&cluster.EstablishmentID,
+			&cluster.Name,
+			&cluster.Thumbnail,
+			&cluster.Address,
+			&cluster.City,
+			&cluster.Rating,
+		); err != nil {
+			return nil, r.db.Error(err)
+		}
+		if cluster.Count > 1 {
+			// the minimums of a group belong to no one document
+			cluster = entity.MapCluster{
+				X:         cluster.X,
+				Y:         cluster.Y,
+				Count:     cluster.Count,
+				Latitude:  cluster.Latitude,
+				Longitude: cluster.Longitude,
+			}
+		}
+		clusters = append(clusters, &cluster)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, r.db.Error(err)
+	}
+
+	return clusters, nil
+}
+
+// clustersQuery counts the documents matching filter per map cell. The
+// minimum of each column of a cell of one document is that document's.
+// Documents without a location are indexed at 0, 0 and left out.
+func (r *searchDocumentRepo) clustersQuery(filter *entity.EstablishmentList, cells float64) sq.SelectBuilder {
+	return r.listFrom(r.db.Sq.Builder.Select(), false).
+		Column(sq.Expr(cellX+" AS cell_x", cells)).
+		Column(sq.Expr(cellY+" AS cell_y", cells)).
+		Columns(
+			"COUNT(*)",
+			"AVG(d.latitude)",
+			"AVG(d.longitude)",
+			"MIN(d.establishment_id::TEXT)",
+			"MIN(d.name)",
+			"MIN(d.thumbnail)",
+			"MIN(d.address)",
+			"MIN(d.city)",
+			"MIN(d.rating)::FLOAT8",
+		).
+		Where(append(r.listWhere(filter, ""), sq.Expr("(d.latitude, d.longitude) <> (0, 0)"))).
+		GroupBy("cell_x", "cell_y").
+		OrderBy("cell_x", "cell_y")
+}
+
+// facet reads value and count pairs selected by builder
+func (r *searchDocumentRepo) facet(ctx context.Context, name string, builder sq.SelectBuilder) ([]*entity.Facet, error) {
+	sqlStr, args, err := builder.ToSql()
+	if err != nil {
+		return nil, r.db.ErrSQLBuild(err, r.tableName+" "+name+" facet")
+	}
+
+	rows, err := r.db.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, r.db.Error(err)
+	}
+	defer rows.Close()
+
+	var facets []*entity.Facet
+	for rows.Next() {
+		var facet entity.Facet
+		if err = rows.Scan(&facet.Value, &facet.Count); err != nil {
+			return nil, r.db.Error(err)
+		}
+		facets = append(facets, &facet)
+	}
+	return facets, rows.Err()
+}
+
+func (r *searchDocumentRepo) OpeningHours(ctx context.Context, category string) (map[string]string, error) {
+	sqlStr, args, err := r.db.Sq.Builder.
+		Select("establishment_id", "opening_hours").
+		From(r.tableName).
+		Where(r.db.Sq.And(
+			r.db.Sq.Equal("category", category),
+			r.db.Sq.NotEqual("opening_hours", ""),
+		)).
+		ToSql()
+	if err != nil {
+		return nil, r.db.ErrSQLBuild(err, r.tableName+" opening hours")
+	}
+
+	rows, err := r.db.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, r.db.Error(err)
+	}
+	defer rows.Close()
+
+	hours := make(map[string]string)
+	for rows.Next() {
+		var id, value string
+		if err = rows.Scan(&id, &value); err != nil {
+			return nil, r.db.Error(err)
+		}
+		hours[id] = value
+	}
+	return hours, rows.Err()
+}
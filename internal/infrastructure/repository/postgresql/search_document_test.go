@@ -0,0 +1,150 @@
+package postgresql
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"Booking/api-service-booking/internal/entity"
+)
+
+func TestSearchDocumentListQuery(t *testing.T) {
+	r := &searchDocumentRepo{tableName: "search_documents", db: queryDB()}
+
+	tests := []struct {
+		name   string
+		filter entity.EstablishmentList
+		where  string
+		order  string
+	}{
+		{
+			name:   "first page",
+			filter: entity.EstablishmentList{Category: "hotel", SortBy: "rating", Desc: true, Limit: 21},
+			order:  "ORDER BY d.rating DESC, d.establishment_id DESC",
+		},
+		{
+			name: "next page by rating",
+			filter: entity.EstablishmentList{
+				Category:   "hotel",
+				SortBy:     "rating",
+				Desc:       true,
+				AfterValue: "4.5",
+				AfterID:    uuid.NewString(),
+				Limit:      21,
+			},
+			where: "(d.rating, d.establishment_id) < (?::real, ?::uuid)",
+			order: "ORDER BY d.rating DESC, d.establishment_id DESC",
+		},
+		{
+			name: "previous page by name",
+			filter: entity.EstablishmentList{
+				Category:   "restaurant",
+				Cities:     []string{"Tashkent"},
+				SortBy:     "name",
+				AfterValue: "Afsona",
+				AfterID:    uuid.NewString(),
+				Before:     true,
+				Limit:      21,
+			},
+			where: "(d.name, d.establishment_id) < (?::text, ?::uuid)",
+			order: "ORDER BY d.name DESC, d.establishment_id DESC",
+		},
+		{
+			name: "next page by distance",
+			filter: entity.EstablishmentList{
+				Category:   "attraction",
+				SortBy:     "distance",
+				Latitude:   41.31,
+				Longitude:  69.28,
+				AfterValue: "0.0004",
+				AfterID:    uuid.NewString(),
+				Limit:      21,
+			},
+			where: "((" + distanceOrder + "), d.establishment_id) > (?::float8, ?::uuid)",
+			order: "ORDER BY (" + distanceOrder + ") ASC, d.establishment_id ASC",
+		},
+		{
+			name: "next page by newest, undated last",
+			filter: entity.EstablishmentList{
+				Category:   "hotel",
+				SortBy:     "created_at",
+				Desc:       true,
+				AfterValue: "-Infinity",
+				AfterID:    uuid.NewString(),
+				Limit:      21,
+			},
+			where: "'-Infinity'), d.establishment_id) < (?::float8, ?::uuid)",
+		},
+		{
+			name: "next page by popularity",
+			filter: entity.EstablishmentList{
+				Category:   "hotel",
+				SortBy:     "popularity",
+				Desc:       true,
+				AfterValue: "12",
+				AfterID:    uuid.NewString(),
+				Limit:      21,
+			},
+			where: "(COALESCE(b.bookings, 0), d.establishment_id) < (?::bigint, ?::uuid)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlStr, args, err := r.listQuery(&tt.filter).ToSql()
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkArgs(t, sqlStr, args)
+			query := placeholder.ReplaceAllString(sqlStr, "?")
+			if tt.where != "" && !strings.Contains(query, tt.where) {
+				t.Errorf("query has no %q: %s", tt.where, query)
+			}
+			if tt.order != "" && !strings.Contains(query, tt.order) {
+				t.Errorf("query has no %q: %s", tt.order, query)
+			}
+			if tt.filter.AfterID != "" && strings.Contains(query, "OFFSET") {
+				t.Errorf("a page after a cursor has an offset: %s", query)
+			}
+		})
+	}
+}
+
+func TestSearchDocumentClustersQuery(t *testing.T) {
+	r := &searchDocumentRepo{tableName: "search_documents", db: queryDB()}
+
+	// paging is ignored, every match is grouped
+	filter := entity.EstablishmentList{
+		Category: "restaurant",
+		Cities:   []string{"Samarkand"},
+		SortBy:   "name",
+		Offset:   40,
+		Limit:    20,
+	}
+	sqlStr, args, err := r.clustersQuery(&filter, 1024).ToSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkArgs(t, sqlStr, args)
+
+	query := placeholder.ReplaceAllString(sqlStr, "?")
+	for _, want := range []string{
+		cellX + " AS cell_x",
+		cellY + " AS cell_y",
+		"d.city IN (?)",
+		"(d.latitude, d.longitude) <> (0, 0)",
+		"GROUP BY cell_x, cell_y",
+	} {
+		if !strings.Contains(query, want) {
+			t.Errorf("query has no %q: %s", want, query)
+		}
+	}
+	for _, unwanted := range []string{"LIMIT", "OFFSET"} {
+		if strings.Contains(query, unwanted) {
+			t.Errorf("query has %s: %s", unwanted, query)
+		}
+	}
+	if args[0] != 1024.0 || args[1] != 1024.0 {
+		t.Errorf("cells across are %v, %v, want 1024", args[0], args[1])
+	}
+}
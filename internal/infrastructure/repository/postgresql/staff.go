@@ -0,0 +1,122 @@
+package postgresql
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v4"
+
+	"Booking/api-service-booking/internal/entity"
+	"Booking/api-service-booking/internal/infrastructure/repository/postgresql/repo"
+	"Booking/api-service-booking/internal/pkg/postgres"
+)
+
+type staffRepo struct {
+	tableName string
+	db        *postgres.PostgresDB
+}
+
+func NewStaffRepo(db *postgres.PostgresDB) repo.StaffRepo {
+	return &staffRepo{
+		tableName: "establishment_staff",
+		db:        db,
+	}
+}
+
+func (r *staffRepo) Create(ctx context.Context, m *entity.StaffMember) error {
+	clauses := map[string]interface{}{
+		"user_id":          m.UserID,
+		"establishment_id": m.EstablishmentID,
+		"created_at":       m.CreatedAt,
+	}
+
+	sqlStr, args, err := r.db.Sq.Builder.Insert(r.tableName).SetMap(clauses).ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.tableName+" create")
+	}
+
+	if _, err = r.db.Exec(ctx, sqlStr, args...); err != nil {
+		return r.db.Error(err)
+	}
+	return nil
+}
+
+func (r *staffRepo) Delete(ctx context.Context, userID, establishmentID string) error {
+	sqlStr, args, err := r.db.Sq.Builder.
+		Delete(r.tableName).
+		Where(r.db.Sq.And(
+			r.db.Sq.Equal("user_id", userID),
+			r.db.Sq.Equal("establishment_id", establishmentID),
+		)).
+		ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.tableName+" delete")
+	}
+
+	commandTag, err := r.db.Exec(ctx, sqlStr, args...)
+	if err != nil {
+		return r.db.Error(err)
+	}
+	if commandTag.RowsAffected() == 0 {
+		return r.db.Error(pgx.ErrNoRows)
+	}
+	return nil
+}
+
+func (r *staffRepo) ListByUser(ctx context.Context, userID string) ([]*entity.StaffMember, error) {
+	sqlStr, args, err := r.db.Sq.Builder.
+		Select(
+			"user_id",
+			"establishment_id",
+			"created_at",
+		).
+		From(r.tableName).
+		Where(r.db.Sq.Equal("user_id", userID)).
+		OrderBy("created_at").
+		ToSql()
+	if err != nil {
+		return nil, r.db.ErrSQLBuild(err, r.tableName+" list")
+	}
+
+	rows, err := r.db.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, r.db.Error(err)
+	}
+	defer rows.Close()
+
+	var members []*entity.StaffMember
+	for rows.Next() {
+		var member entity.StaffMember
+		if err = rows.Scan(
+			&member.UserID,
+			&member.EstablishmentID,
+			&member.CreatedAt,
+		); err != nil {
+			return nil, r.db.Error(err)
+		}
+		members = append(members, &member)
+	}
+
+	return members, rows.Err()
+}
+
+func (r *staffRepo) IsMember(ctx context.Context, userID, establishmentID string) (bool, error) {
+	sqlStr, args, err := r.db.Sq.Builder.
+		Select("1").
+		From(r.tableName).
+		Where(r.db.Sq.And(
+			r.db.Sq.Equal("user_id", userID),
+			r.db.Sq.Equal("establishment_id", establishmentID),
+		)).
+		Prefix("SELECT EXISTS (").
+		Suffix(")").
+		ToSql()
+	if err != nil {
+		return false, r.db.ErrSQLBuild(err, r.tableName+" exists")
+	}
+
+	var exists bool
+	if err = r.db.QueryRow(ctx, sqlStr, args...).Scan(&exists); err != nil {
+		return false, r.db.Error(err)
+	}
+	return exists, nil
+}
@@ -0,0 +1,207 @@
+package postgresql
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v4"
+
+	"Booking/api-service-booking/internal/entity"
+	"Booking/api-service-booking/internal/infrastructure/repository/postgresql/repo"
+	"Booking/api-service-booking/internal/pkg/postgres"
+)
+
+type tagRepo struct {
+	tableName           string
+	establishmentsTable string
+	db                  *postgres.PostgresDB
+}
+
+func NewTagRepo(db *postgres.PostgresDB) repo.TagRepo {
+	return &tagRepo{
+		tableName:           "tags",
+		establishmentsTable: "establishment_tags",
+		db:                  db,
+	}
+}
+
+func (r *tagRepo) Create(ctx context.Context, m *entity.Tag) error {
+	sqlStr, args, err := r.db.Sq.Builder.
+		Insert(r.tableName).
+		SetMap(map[string]interface{}{
+			"slug":       m.Slug,
+			"kind":       m.Kind,
+			"name":       m.Name,
+			"categories": m.Categories,
+			"created_at": m.CreatedAt,
+			"updated_at": m.UpdatedAt,
+		}).
+		ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.tableName+" create")
+	}
+
+	if _, err = r.db.Exec(ctx, sqlStr, args...); err != nil {
+		return r.db.Error(err)
+	}
+	return nil
+}
+
+func (r *tagRepo) Update(ctx context.Context, m *entity.Tag) error {
+	sqlStr, args, err := r.db.Sq.Builder.
+		Update(r.tableName).
+		SetMap(map[string]interface{}{
+			"kind":       m.Kind,
+			"name":       m.Name,
+			"categories": m.Categories,
+			"updated_at": m.UpdatedAt,
+		}).
+		Where(r.db.Sq.Equal("slug", m.Slug)).
+		Suffix("RETURNING created_at").
+		ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.tableName+" update")
+	}
+
+	if err = r.db.QueryRow(ctx, sqlStr, args...).Scan(&m.CreatedAt); err != nil {
+		return r.db.Error(err)
+	}
+	return nil
+}
+
+func (r *tagRepo) Delete(ctx context.Context, slug string) error {
+	sqlStr, args, err := r.db.Sq.Builder.
+		Delete(r.tableName).
+		Where(r.db.Sq.Equal("slug", slug)).
+		ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.tableName+" delete")
+	}
+
+	commandTag, err := r.db.Exec(ctx, sqlStr, args...)
+	if err != nil {
+		return r.db.Error(err)
+	}
+	if commandTag.RowsAffected() == 0 {
+		return r.db.Error(pgx.ErrNoRows)
+	}
+	return nil
+}
+
+func (r *tagRepo) List(ctx context.Context, kind string) ([]*entity.Tag, error) {
+	builder := r.db.Sq.Builder.
+		Select(
+			"slug",
+			"kind",
+			"name",
+			"categories",
+			"created_at",
+			"updated_at",
+		).
+		From(r.tableName).
+		OrderBy("kind", "name")
+	if kind != "" {
+		builder = builder.Where(r.db.Sq.Equal("kind", kind))
+	}
+
+	sqlStr, args, err := builder.ToSql()
+	if err != nil {
+		return nil, r.db.ErrSQLBuild(err, r.tableName+" list")
+	}
+
+	rows, err := r.db.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, r.db.Error(err)
+	}
+	defer rows.Close()
+
+	var tags []*entity.Tag
+	for rows.Next() {
+		var tag entity.Tag
+		if err = rows.Scan(
+			&tag.Slug,
+			&tag.Kind,
+			&tag.Name,
+			&tag.Categories,
+			&tag.CreatedAt,
+			&tag.UpdatedAt,
+		); err != nil {
+			return nil, r.db.Error(err)
+		}
+		tags = append(tags, &tag)
+	}
+	return tags, rows.Err()
+}
+
+func (r *tagRepo) SetEstablishmentTags(ctx context.Context, category, establishmentID string, slugs []string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return r.db.Error(err)
+	}
+	defer tx.Rollback(ctx)
+
+	clearStr, clearArgs, err := r.db.Sq.Builder.
+		Delete(r.establishmentsTable).
+		Where(r.db.Sq.And(
+			r.db.Sq.Equal("category", category),
+			r.db.Sq.Equal("establishment_id", establishmentID),
+		)).
+		ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.establishmentsTable+" clear")
+	}
+	if _, err = tx.Exec(ctx, clearStr, clearArgs...); err != nil {
+		return r.db.Error(err)
+	}
+
+	if len(slugs) > 0 {
+		builder := r.db.Sq.Builder.
+			Insert(r.establishmentsTable).
+			Columns("category", "establishment_id", "tag")
+		for _, slug := range slugs {
+			builder = builder.Values(category, establishmentID, slug)
+		}
+		sqlStr, args, err := builder.ToSql()
+		if err != nil {
+			return r.db.ErrSQLBuild(err, r.establishmentsTable+" set")
+		}
+		if _, err = tx.Exec(ctx, sqlStr, args...); err != nil {
+			return r.db.Error(err)
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return r.db.Error(err)
+	}
+	return nil
+}
+
+func (r *tagRepo) ListEstablishmentTags(ctx context.Context, category string, ids []string) (map[string][]string, error) {
+	sqlStr, args, err := r.db.Sq.Builder.
+		Select("establishment_id", "tag").
+		From(r.establishmentsTable).
+		Where(r.db.Sq.And(
+			r.db.Sq.Equal("category", category),
+			r.db.Sq.Equal("establishment_id", ids),
+		)).
+		OrderBy("tag").
+		ToSql()
+	if err != nil {
+		return nil, r.db.ErrSQLBuild(err, r.establishmentsTable+" list")
+	}
+
+	rows, err := r.db.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, r.db.Error(err)
+	}
+	defer rows.Close()
+
+	tags := make(map[string][]string)
+	for rows.Next() {
+		var id, tag string
+		if err = rows.Scan(&id, &tag); err != nil {
+			return nil, r.db.Error(err)
+		}
+		tags[id] = append(tags[id], tag)
+	}
+	return tags, rows.Err()
+}
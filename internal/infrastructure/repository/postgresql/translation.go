@@ -0,0 +1,136 @@
+package postgresql
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+
+	"Booking/api-service-booking/internal/entity"
+	"Booking/api-service-booking/internal/infrastructure/repository/postgresql/repo"
+	"Booking/api-service-booking/internal/pkg/postgres"
+)
+
+type translationRepo struct {
+	tableName string
+	db        *postgres.PostgresDB
+}
+
+func NewTranslationRepo(db *postgres.PostgresDB) repo.TranslationRepo {
+	return &translationRepo{
+		tableName: "establishment_translations",
+		db:        db,
+	}
+}
+
+func (r *translationRepo) Save(ctx context.Context, m *entity.Translation) error {
+	sqlStr, args, err := r.db.Sq.Builder.
+		Insert(r.tableName).
+		SetMap(map[string]interface{}{
+			"category":         m.Category,
+			"establishment_id": m.EstablishmentID,
+			"locale":           m.Locale,
+			"name":             m.Name,
+			"description":      m.Description,
+			"updated_at":       m.UpdatedAt,
+		}).
+		Suffix("ON CONFLICT (category, establishment_id, locale) DO UPDATE SET name = EXCLUDED.name, description = EXCLUDED.description, updated_at = EXCLUDED.updated_at").
+		ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.tableName+" save")
+	}
+
+	if _, err = r.db.Exec(ctx, sqlStr, args...); err != nil {
+		return r.db.Error(err)
+	}
+	return nil
+}
+
+func (r *translationRepo) delete(ctx context.Context, where sq.Sqlizer) (int64, error) {
+	sqlStr, args, err := r.db.Sq.Builder.
+		Delete(r.tableName).
+		Where(where).
+		ToSql()
+	if err != nil {
+		return 0, r.db.ErrSQLBuild(err, r.tableName+" delete")
+	}
+
+	commandTag, err := r.db.Exec(ctx, sqlStr, args...)
+	if err != nil {
+		return 0, r.db.Error(err)
+	}
+	return commandTag.RowsAffected(), nil
+}
+
+func (r *translationRepo) Delete(ctx context.Context, category, establishmentID, locale string) error {
+	deleted, err := r.delete(ctx, r.db.Sq.And(
+		r.db.Sq.Equal("category", category),
+		r.db.Sq.Equal("establishment_id", establishmentID),
+		r.db.Sq.Equal("locale", locale),
+	))
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return r.db.Error(pgx.ErrNoRows)
+	}
+	return nil
+}
+
+func (r *translationRepo) DeleteAll(ctx context.Context, category, establishmentID string) error {
+	_, err := r.delete(ctx, r.db.Sq.And(
+		r.db.Sq.Equal("category", category),
+		r.db.Sq.Equal("establishment_id", establishmentID),
+	))
+	return err
+}
+
+func (r *translationRepo) List(ctx context.Context, category string, ids []string, locale string) ([]*entity.Translation, error) {
+	where := r.db.Sq.And(
+		r.db.Sq.Equal("category", category),
+		r.db.Sq.Equal("establishment_id", ids),
+	)
+	if locale != "" {
+		where = append(where, r.db.Sq.Equal("locale", locale))
+	}
+
+	sqlStr, args, err := r.db.Sq.Builder.
+		Select(
+			"category",
+			"establishment_id",
+			"locale",
+			"name",
+			"description",
+			"updated_at",
+		).
+		From(r.tableName).
+		Where(where).
+		OrderBy("locale").
+		ToSql()
+	if err != nil {
+		return nil, r.db.ErrSQLBuild(err, r.tableName+" list")
+	}
+
+	rows, err := r.db.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, r.db.Error(err)
+	}
+	defer rows.Close()
+
+	var translations []*entity.Translation
+	for rows.Next() {
+		var translation entity.Translation
+		if err = rows.Scan(
+			&translation.Category,
+			&translation.EstablishmentID,
+			&translation.Locale,
+			&translation.Name,
+			&translation.Description,
+			&translation.UpdatedAt,
+		); err != nil {
+			return nil, r.db.Error(err)
+		}
+		translations = append(translations, &translation)
+	}
+	return translations, rows.Err()
+}
@@ -0,0 +1,399 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+
+	"Booking/api-service-booking/internal/entity"
+	errorspkg "Booking/api-service-booking/internal/errors"
+	"Booking/api-service-booking/internal/infrastructure/repository/postgresql/repo"
+	"Booking/api-service-booking/internal/pkg/postgres"
+)
+
+type tripRepo struct {
+	tableName string
+	itemTable string
+	db        *postgres.PostgresDB
+}
+
+func NewTripRepo(db *postgres.PostgresDB) repo.TripRepo {
+	return &tripRepo{
+		tableName: "trips",
+		itemTable: "trip_items",
+		db:        db,
+	}
+}
+
+func (r *tripRepo) Create(ctx context.Context, m *entity.Trip) error {
+	clauses := map[string]interface{}{
+		"id":         m.ID,
+		"user_id":    m.UserID,
+		"name":       m.Name,
+		"status":     m.Status,
+		"created_at": m.CreatedAt,
+		"updated_at": m.UpdatedAt,
+	}
+
+	sqlStr, args, err := r.db.Sq.Builder.Insert(r.tableName).SetMap(clauses).ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.tableName+" create")
+	}
+
+	if _, err = r.db.Exec(ctx, sqlStr, args...); err != nil {
+		return r.db.Error(err)
+	}
+	return nil
+}
+
+func (r *tripRepo) list(ctx context.Context, where sq.Sqlizer) ([]*entity.Trip, error) {
+	sqlStr, args, err := r.db.Sq.Builder.
+		Select(
+			"id",
+			"user_id",
+			"name",
+			"status",
+			"created_at",
+			"updated_at",
+		).
+		From(r.tableName).
+		Where(where).
+		OrderBy("created_at DESC").
+		ToSql()
+	if err != nil {
+		return nil, r.db.ErrSQLBuild(err, r.tableName+" list")
+	}
+
+	rows, err := r.db.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, r.db.Error(err)
+	}
+	defer rows.Close()
+
+	var (
+		trips []*entity.Trip
+		ids   []string
+		byID  = make(map[string]*entity.Trip)
+	)
+	for rows.Next() {
+		var trip entity.Trip
+		if err = rows.Scan(
+			&trip.ID,
+			&trip.UserID,
+			&trip.Name,
+			&trip.Status,
+			&trip.CreatedAt,
+			&trip.UpdatedAt,
+		); err != nil {
+			return nil, r.db.Error(err)
+		}
+		trips = append(trips, &trip)
+		ids = append(ids, trip.ID)
+		byID[trip.ID] = &trip
+	}
+	if err = rows.Err(); err != nil {
+		return nil, r.db.Error(err)
+	}
+	if len(trips) == 0 {
+		return trips, nil
+	}
+
+	items, err := r.listItems(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		trip := byID[item.TripID]
+		trip.Items = append(trip.Items, item)
+	}
+
+	return trips, nil
+}
+
+func (r *tripRepo) listItems(ctx context.Context, tripIDs []string) ([]*entity.TripItem, error) {
+	sqlStr, args, err := r.db.Sq.Builder.
+		Select(
+			"id",
+			"trip_id",
+			"category",
+			"establishment_id",
+			"will_arrive",
+			"will_leave",
+			"number_of_people",
+			"tickets",
+			"promo_code",
+			"COALESCE(booking_id::text, '')",
+			"created_at",
+		).
+		From(r.itemTable).
+		Where(r.db.Sq.Equal("trip_id", tripIDs)).
+		OrderBy("created_at", "id").
+		ToSql()
+	if err != nil {
+		return nil, r.db.ErrSQLBuild(err, r.itemTable+" list")
+	}
+
+	rows, err := r.db.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, r.db.Error(err)
+	}
+	defer rows.Close()
+
+	var items []*entity.TripItem
+	for rows.Next() {
+		var item entity.TripItem
+		if err = rows.Scan(
+			&item.ID,
+			&item.TripID,
+			&item.Category,
+			&item.EstablishmentID,
+			&item.WillArrive,
+			&item.WillLeave,
+			&item.NumberOfPeople,
+			&item.Tickets,
+			&item.PromoCode,
+			&item.BookingID,
+			&item.CreatedAt,
+		); err != nil {
+			return nil, r.db.Error(err)
+		}
+		items = append(items, &item)
+	}
+
+	return items, rows.Err()
+}
+
+func (r *tripRepo) Get(ctx context.Context, id string) (*entity.Trip, error) {
+	trips, err := r.list(ctx, r.db.Sq.Equal("id", id))
+	if err != nil {
+		return nil, err
+	}
+	if len(trips) == 0 {
+		return nil, r.db.Error(pgx.ErrNoRows)
+	}
+	return trips[0], nil
+}
+
+func (r *tripRepo) ListByUser(ctx context.Context, userID string) ([]*entity.Trip, error) {
+	return r.list(ctx, r.db.Sq.Equal("user_id", userID))
+}
+
+// lockOpen locks a trip for the rest of tx, ErrorNotAvailable if it is no
+// longer open. A checkout changing the status waits for the lock, so items
+// change either before it reads them or not at all.
+func (r *tripRepo) lockOpen(ctx context.Context, tx pgx.Tx, id string) error {
+	sqlStr, args, err := r.db.Sq.Builder.
+		Select("status").
+		From(r.tableName).
+		Where(r.db.Sq.Equal("id", id)).
+		Suffix("FOR UPDATE").
+		ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.tableName+" lock")
+	}
+
+	var status string
+	if err = tx.QueryRow(ctx, sqlStr, args...).Scan(&status); err != nil {
+		return r.db.Error(err)
+	}
+	if status != entity.TripStatusOpen {
+		return errorspkg.ErrorNotAvailable
+	}
+	return nil
+}
+
+// inOpenTrip runs exec in a transaction holding the lock of an open trip
+func (r *tripRepo) inOpenTrip(ctx context.Context, tripID string, exec func(tx pgx.Tx) error) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return r.db.Error(err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err = r.lockOpen(ctx, tx, tripID); err != nil {
+		return err
+	}
+	if err = exec(tx); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return r.db.Error(err)
+	}
+	return nil
+}
+
+func (r *tripRepo) Delete(ctx context.Context, id string) error {
+	return r.inOpenTrip(ctx, id, func(tx pgx.Tx) error {
+		sqlStr, args, err := r.db.Sq.Builder.
+			Delete(r.tableName).
+			Where(r.db.Sq.Equal("id", id)).
+			ToSql()
+		if err != nil {
+			return r.db.ErrSQLBuild(err, r.tableName+" delete")
+		}
+
+		if _, err = tx.Exec(ctx, sqlStr, args...); err != nil {
+			return r.db.Error(err)
+		}
+		return nil
+	})
+}
+
+func (r *tripRepo) AddItem(ctx context.Context, m *entity.TripItem) error {
+	return r.inOpenTrip(ctx, m.TripID, func(tx pgx.Tx) error {
+		clauses := map[string]interface{}{
+			"id":               m.ID,
+			"trip_id":          m.TripID,
+			"category":         m.Category,
+			"establishment_id": m.EstablishmentID,
+			"will_arrive":      m.WillArrive,
+			"will_leave":       m.WillLeave,
+			"number_of_people": m.NumberOfPeople,
+			"tickets":          m.Tickets,
+			"promo_code":       m.PromoCode,
+			"created_at":       m.CreatedAt,
+		}
+
+		sqlStr, args, err := r.db.Sq.Builder.Insert(r.itemTable).SetMap(clauses).ToSql()
+		if err != nil {
+			return r.db.ErrSQLBuild(err, r.itemTable+" create")
+		}
+
+		if _, err = tx.Exec(ctx, sqlStr, args...); err != nil {
+			return r.db.Error(err)
+		}
+		return nil
+	})
+}
+
+func (r *tripRepo) RemoveItem(ctx context.Context, tripID, itemID string) error {
+	return r.inOpenTrip(ctx, tripID, func(tx pgx.Tx) error {
+		sqlStr, args, err := r.db.Sq.Builder.
+			Delete(r.itemTable).
+			Where(r.db.Sq.And(
+				r.db.Sq.Equal("id", itemID),
+				r.db.Sq.Equal("trip_id", tripID),
+			)).
+			ToSql()
+		if err != nil {
+			return r.db.ErrSQLBuild(err, r.itemTable+" delete")
+		}
+
+		commandTag, err := tx.Exec(ctx, sqlStr, args...)
+		if err != nil {
+			return r.db.Error(err)
+		}
+		if commandTag.RowsAffected() == 0 {
+			return r.db.Error(pgx.ErrNoRows)
+		}
+		return nil
+	})
+}
+
+func (r *tripRepo) ChangeStatus(ctx context.Context, id string, from []string, to string, updatedAt time.Time) (bool, error) {
+	sqlStr, args, err := r.db.Sq.Builder.
+		Update(r.tableName).
+		SetMap(map[string]interface{}{
+			"status":     to,
+			"updated_at": updatedAt,
+		}).
+		Where(r.db.Sq.And(
+			r.db.Sq.Equal("id", id),
+			r.db.Sq.Equal("status", from),
+		)).
+		ToSql()
+	if err != nil {
+		return false, r.db.ErrSQLBuild(err, r.tableName+" change status")
+	}
+
+	commandTag, err := r.db.Exec(ctx, sqlStr, args...)
+	if err != nil {
+		return false, r.db.Error(err)
+	}
+
+	return commandTag.RowsAffected() > 0, nil
+}
+
+func (r *tripRepo) Reopen(ctx context.Context, id string, updatedAt time.Time) (bool, error) {
+	sqlStr, args, err := r.reopenQuery(id, updatedAt)
+	if err != nil {
+		return false, r.db.ErrSQLBuild(err, r.tableName+" reopen")
+	}
+
+	var reopened bool
+	if err = r.db.QueryRow(ctx, sqlStr, args...).Scan(&reopened); err != nil {
+		return false, r.db.Error(err)
+	}
+	return reopened, nil
+}
+
+// reopenQuery opens a trip that is checking out and clears the booking
+// ids of its items only if it did
+func (r *tripRepo) reopenQuery(id string, updatedAt time.Time) (string, []interface{}, error) {
+	reopenStr, args, err := r.db.Sq.Builder.
+		Update(r.tableName).
+		SetMap(map[string]interface{}{
+			"status":     entity.TripStatusOpen,
+			"updated_at": updatedAt,
+		}).
+		Where(r.db.Sq.And(
+			r.db.Sq.Equal("id", id),
+			r.db.Sq.Equal("status", entity.TripStatusCheckingOut),
+		)).
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		return "", nil, err
+	}
+
+	return fmt.Sprintf(
+		"WITH reopened AS (%s), cleared AS (UPDATE %s SET booking_id = NULL WHERE trip_id IN (SELECT id FROM reopened)) SELECT EXISTS (SELECT 1 FROM reopened)",
+		reopenStr, r.itemTable,
+	), args, nil
+}
+
+func (r *tripRepo) SetBookingIDs(ctx context.Context, tripID string, bookingIDs map[string]string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return r.db.Error(err)
+	}
+	defer tx.Rollback(ctx)
+
+	clearStr, clearArgs, err := r.db.Sq.Builder.
+		Update(r.itemTable).
+		Set("booking_id", nil).
+		Where(r.db.Sq.Equal("trip_id", tripID)).
+		ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.itemTable+" clear booking ids")
+	}
+	if _, err = tx.Exec(ctx, clearStr, clearArgs...); err != nil {
+		return r.db.Error(err)
+	}
+
+	for itemID, bookingID := range bookingIDs {
+		sqlStr, args, err := r.db.Sq.Builder.
+			Update(r.itemTable).
+			Set("booking_id", bookingID).
+			Where(r.db.Sq.And(
+				r.db.Sq.Equal("id", itemID),
+				r.db.Sq.Equal("trip_id", tripID),
+			)).
+			ToSql()
+		if err != nil {
+			return r.db.ErrSQLBuild(err, r.itemTable+" set booking id")
+		}
+		if _, err = tx.Exec(ctx, sqlStr, args...); err != nil {
+			return r.db.Error(err)
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return r.db.Error(err)
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package postgresql
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestTripReopenQuery(t *testing.T) {
+	r := &tripRepo{tableName: "trips", itemTable: "trip_items", db: queryDB()}
+
+	sqlStr, args, err := r.reopenQuery(uuid.NewString(), time.Now().UTC())
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkArgs(t, sqlStr, args)
+	// the ids are cleared only when the status changed, in the same
+	// statement
+	for _, want := range []string{
+		"WITH reopened AS (UPDATE trips SET",
+		"status = $4",
+		"UPDATE trip_items SET booking_id = NULL WHERE trip_id IN (SELECT id FROM reopened)",
+	} {
+		if !strings.Contains(sqlStr, want) {
+			t.Errorf("query has no %q: %s", want, sqlStr)
+		}
+	}
+}
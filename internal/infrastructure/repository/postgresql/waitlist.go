@@ -0,0 +1,240 @@
+package postgresql
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+
+	"Booking/api-service-booking/internal/entity"
+	"Booking/api-service-booking/internal/infrastructure/repository/postgresql/repo"
+	"Booking/api-service-booking/internal/pkg/postgres"
+)
+
+type waitlistRepo struct {
+	tableName string
+	db        *postgres.PostgresDB
+}
+
+func NewWaitlistRepo(db *postgres.PostgresDB) repo.WaitlistRepo {
+	return &waitlistRepo{
+		tableName: "waitlist_entries",
+		db:        db,
+	}
+}
+
+// position counts the waiting entries queued before w for an overlapping range
+const waitlistPosition = `CASE WHEN w.status = 'waiting' THEN (
+	SELECT COUNT(*) + 1 FROM waitlist_entries AS o
+	WHERE o.category = w.category
+	  AND o.establishment_id = w.establishment_id
+	  AND o.status = 'waiting'
+	  AND o.created_at < w.created_at
+	  AND o.arrive_at < w.leave_at
+	  AND o.leave_at > w.arrive_at
+) ELSE 0 END`
+
+func (r *waitlistRepo) selectQuery() sq.SelectBuilder {
+	return r.db.Sq.Builder.
+		Select(
+			"w.id",
+			"w.category",
+			"w.establishment_id",
+			"w.user_id",
+			"w.will_arrive",
+			"w.will_leave",
+			"w.arrive_at",
+			"w.leave_at",
+			"w.number_of_people",
+			"w.tickets",
+			"w.status",
+			"w.offer_expires_at",
+			waitlistPosition,
+			"w.created_at",
+			"w.updated_at",
+		).
+		From(r.tableName + " AS w")
+}
+
+func (r *waitlistRepo) scan(ctx context.Context, sqlStr string, args []interface{}) ([]*entity.WaitlistEntry, error) {
+	rows, err := r.db.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, r.db.Error(err)
+	}
+	defer rows.Close()
+
+	var entries []*entity.WaitlistEntry
+	for rows.Next() {
+		var entry entity.WaitlistEntry
+		if err = rows.Scan(
+			&entry.ID,
+			&entry.Category,
+			&entry.EstablishmentID,
+			&entry.UserID,
+			&entry.WillArrive,
+			&entry.WillLeave,
+			&entry.ArriveAt,
+			&entry.LeaveAt,
+			&entry.NumberOfPeople,
+			&entry.Tickets,
+			&entry.Status,
+			&entry.OfferExpiresAt,
+			&entry.Position,
+			&entry.CreatedAt,
+			&entry.UpdatedAt,
+		); err != nil {
+			return nil, r.db.Error(err)
+		}
+		entries = append(entries, &entry)
+	}
+
+	return entries, rows.Err()
+}
+
+func (r *waitlistRepo) Create(ctx context.Context, m *entity.WaitlistEntry) error {
+	clauses := map[string]interface{}{
+		"id":               m.ID,
+		"category":         m.Category,
+		"establishment_id": m.EstablishmentID,
+		"user_id":          m.UserID,
+		"will_arrive":      m.WillArrive,
+		"will_leave":       m.WillLeave,
+		"arrive_at":        m.ArriveAt,
+		"leave_at":         m.LeaveAt,
+		"number_of_people": m.NumberOfPeople,
+		"tickets":          m.Tickets,
+		"status":           m.Status,
+		"created_at":       m.CreatedAt,
+		"updated_at":       m.UpdatedAt,
+	}
+
+	sqlStr, args, err := r.db.Sq.Builder.Insert(r.tableName).SetMap(clauses).ToSql()
+	if err != nil {
+		return r.db.ErrSQLBuild(err, r.tableName+" create")
+	}
+
+	if _, err = r.db.Exec(ctx, sqlStr, args...); err != nil {
+		return r.db.Error(err)
+	}
+	return nil
+}
+
+func (r *waitlistRepo) Get(ctx context.Context, id string) (*entity.WaitlistEntry, error) {
+	sqlStr, args, err := r.selectQuery().
+		Where(r.db.Sq.Equal("w.id", id)).
+		ToSql()
+	if err != nil {
+		return nil, r.db.ErrSQLBuild(err, r.tableName+" read")
+	}
+
+	entries, err := r.scan(ctx, sqlStr, args)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, r.db.Error(pgx.ErrNoRows)
+	}
+	return entries[0], nil
+}
+
+func (r *waitlistRepo) ListByUser(ctx context.Context, userID string) ([]*entity.WaitlistEntry, error) {
+	sqlStr, args, err := r.selectQuery().
+		Where(r.db.Sq.Equal("w.user_id", userID)).
+		OrderBy("w.created_at DESC").
+		ToSql()
+	if err != nil {
+		return nil, r.db.ErrSQLBuild(err, r.tableName+" list")
+	}
+
+	return r.scan(ctx, sqlStr, args)
+}
+
+func (r *waitlistRepo) ListWaiting(ctx context.Context, category, establishmentID string, from, to time.Time, limit uint64) ([]*entity.WaitlistEntry, error) {
+	sqlStr, args, err := r.selectQuery().
+		Where(r.db.Sq.And(
+			r.db.Sq.Equal("w.category", category),
+			r.db.Sq.Equal("w.establishment_id", establishmentID),
+			r.db.Sq.Equal("w.status", entity.WaitlistStatusWaiting),
+			r.db.Sq.Lt("w.arrive_at", to),
+			r.db.Sq.Gt("w.leave_at", from),
+		)).
+		OrderBy("w.created_at").
+		Limit(limit).
+		ToSql()
+	if err != nil {
+		return nil, r.db.ErrSQLBuild(err, r.tableName+" list waiting")
+	}
+
+	return r.scan(ctx, sqlStr, args)
+}
+
+func (r *waitlistRepo) ChangeStatus(ctx context.Context, id string, from []string, to string, offerExpiresAt *time.Time, updatedAt time.Time) (bool, error) {
+	sqlStr, args, err := r.db.Sq.Builder.
+		Update(r.tableName).
+		SetMap(map[string]interface{}{
+			"status":           to,
+			"offer_expires_at": offerExpiresAt,
+			"updated_at":       updatedAt,
+		}).
+		Where(r.db.Sq.And(
+			r.db.Sq.Equal("id", id),
+			r.db.Sq.Equal("status", from),
+		)).
+		ToSql()
+	if err != nil {
+		return false, r.db.ErrSQLBuild(err, r.tableName+" change status")
+	}
+
+	commandTag, err := r.db.Exec(ctx, sqlStr, args...)
+	if err != nil {
+		return false, r.db.Error(err)
+	}
+
+	return commandTag.RowsAffected() > 0, nil
+}
+
+func (r *waitlistRepo) Demand(ctx context.Context, category, establishmentID string, from, to time.Time) ([]*entity.WaitlistDemand, error) {
+	sqlStr, args, err := r.demandQuery(category, establishmentID, from, to).ToSql()
+	if err != nil {
+		return nil, r.db.ErrSQLBuild(err, r.tableName+" demand")
+	}
+
+	rows, err := r.db.Query(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, r.db.Error(err)
+	}
+	defer rows.Close()
+
+	var demand []*entity.WaitlistDemand
+	for rows.Next() {
+		var d entity.WaitlistDemand
+		if err = rows.Scan(&d.Date, &d.Entries, &d.People); err != nil {
+			return nil, r.db.Error(err)
+		}
+		demand = append(demand, &d)
+	}
+
+	return demand, rows.Err()
+}
+
+func (r *waitlistRepo) demandQuery(category, establishmentID string, from, to time.Time) sq.SelectBuilder {
+	day := "to_char(arrive_at AT TIME ZONE 'Asia/Tashkent', 'YYYY-MM-DD')"
+
+	return r.db.Sq.Builder.
+		Select(
+			day,
+			"COUNT(*)",
+			"COALESCE(SUM(number_of_people), 0)",
+		).
+		From(r.tableName).
+		Where(r.db.Sq.And(
+			r.db.Sq.Equal("category", category),
+			r.db.Sq.Equal("establishment_id", establishmentID),
+			r.db.Sq.Equal("status", []string{entity.WaitlistStatusWaiting, entity.WaitlistStatusOffered}),
+			r.db.Sq.Expr("arrive_at >= ?", from),
+			r.db.Sq.Lt("arrive_at", to),
+		)).
+		GroupBy(day).
+		OrderBy(day)
+}
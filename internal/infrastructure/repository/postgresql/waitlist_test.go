@@ -0,0 +1,78 @@
+package postgresql
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Booking/api-service-booking/internal/entity"
+	"Booking/api-service-booking/internal/pkg/booktime"
+)
+
+func TestWaitlistDemandQuery(t *testing.T) {
+	r := &waitlistRepo{tableName: "waitlist_entries", db: queryDB()}
+
+	from := time.Date(2026, 10, 20, 0, 0, 0, 0, booktime.Location())
+	sqlStr, args, err := r.demandQuery("hotel", uuid.NewString(), from, from.AddDate(0, 0, 3)).ToSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkArgs(t, sqlStr, args)
+}
+
+func TestWaitlistRepoDemand(t *testing.T) {
+	db := testDB(t)
+	r := NewWaitlistRepo(db)
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	establishmentID := uuid.NewString()
+	day := time.Date(2026, 10, 20, 0, 0, 0, 0, booktime.Location())
+	entries := []struct {
+		arriveAt time.Time
+		people   int64
+		status   string
+	}{
+		{day.Add(10 * time.Hour), 2, entity.WaitlistStatusWaiting},
+		{day.Add(15 * time.Hour), 3, entity.WaitlistStatusWaiting},
+		{day.AddDate(0, 0, 1).Add(9 * time.Hour), 1, entity.WaitlistStatusLeft},
+		{day.AddDate(0, 0, 2).Add(9 * time.Hour), 4, entity.WaitlistStatusOffered},
+		{day.AddDate(0, 0, 5).Add(9 * time.Hour), 6, entity.WaitlistStatusWaiting},
+	}
+	cleanup(t, db, "waitlist_entries", "establishment_id", establishmentID)
+	for _, e := range entries {
+		entry := entity.WaitlistEntry{
+			ID:              uuid.NewString(),
+			Category:        "hotel",
+			EstablishmentID: establishmentID,
+			UserID:          uuid.NewString(),
+			WillArrive:      e.arriveAt.Format("2006-01-02T15:04:05"),
+			WillLeave:       e.arriveAt.AddDate(0, 0, 1).Format("2006-01-02T15:04:05"),
+			ArriveAt:        e.arriveAt,
+			LeaveAt:         e.arriveAt.AddDate(0, 0, 1),
+			NumberOfPeople:  e.people,
+			Tickets:         map[string]int{},
+			Status:          e.status,
+			CreatedAt:       now,
+			UpdatedAt:       now,
+		}
+		if err := r.Create(ctx, &entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	demand, err := r.Demand(ctx, "hotel", establishmentID, day, day.AddDate(0, 0, 3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*entity.WaitlistDemand{
+		{Date: "2026-10-20", Entries: 2, People: 5},
+		{Date: "2026-10-22", Entries: 1, People: 4},
+	}
+	if !reflect.DeepEqual(demand, want) {
+		t.Errorf("demand = %+v, want %+v", demand, want)
+	}
+}
@@ -0,0 +1,195 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	goredis "github.com/go-redis/redis/v8"
+
+	"Booking/api-service-booking/internal/entity"
+	"Booking/api-service-booking/internal/pkg/redis"
+)
+
+// Every category keeps its positions in a geo set, members being
+// establishment ids, and what results show of a place in a hash next to it
+const geoKeyPrefix = "geo:"
+
+// stagedTTL is how long the keys of a rebuild outlive its last write, so
+// those of a replica that stopped halfway go away
+const stagedTTL = time.Hour
+
+type GeoIndex interface {
+	Put(ctx context.Context, place *entity.GeoPlace) error
+	Remove(ctx context.Context, category, id string) error
+	// SearchRadius and SearchBox return the places of category around a
+	// center, nearest first, with their distance to it
+	SearchRadius(ctx context.Context, category string, lat, lng, radiusKm float64, limit int) ([]*entity.GeoHit, error)
+	SearchBox(ctx context.Context, category string, lat, lng, widthKm, heightKm float64, limit int) ([]*entity.GeoHit, error)
+	// Stage puts a place into the rebuild named build of its category,
+	// out of sight of searches until Swap
+	Stage(ctx context.Context, build string, place *entity.GeoPlace) error
+	// Swap makes a rebuild the index of category in one step, an empty
+	// rebuild leaves the category empty
+	Swap(ctx context.Context, category, build string) error
+	// Drop throws a rebuild away
+	Drop(ctx context.Context, category, build string) error
+}
+
+func NewGeoIndex(rdb *redis.RedisDB) *geoIndex {
+	return &geoIndex{
+		rdb: rdb,
+	}
+}
+
+type geoIndex struct {
+	rdb *redis.RedisDB
+}
+
+func geoKeys(category string) (string, string) {
+	return geoKeyPrefix + category, geoKeyPrefix + category + ":places"
+}
+
+// stagedKeys are the keys a rebuild of category fills before they are
+// renamed to its geoKeys
+func stagedKeys(category, build string) (string, string) {
+	return geoKeyPrefix + category + ":build:" + build, geoKeyPrefix + category + ":build:" + build + ":places"
+}
+
+func (g *geoIndex) Put(ctx context.Context, place *entity.GeoPlace) error {
+	positions, places := geoKeys(place.Category)
+	return g.put(ctx, positions, places, place, 0)
+}
+
+func (g *geoIndex) Stage(ctx context.Context, build string, place *entity.GeoPlace) error {
+	positions, places := stagedKeys(place.Category, build)
+	return g.put(ctx, positions, places, place, stagedTTL)
+}
+
+// put writes a place to the positions and places keys, setting them to
+// expire after ttl unless it is 0
+func (g *geoIndex) put(ctx context.Context, positions, places string, place *entity.GeoPlace, ttl time.Duration) error {
+	data, err := json.Marshal(place)
+	if err != nil {
+		return err
+	}
+
+	_, err = g.rdb.Client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.GeoAdd(ctx, positions, &goredis.GeoLocation{
+			Name:      place.EstablishmentID,
+			Latitude:  place.Latitude,
+			Longitude: place.Longitude,
+		})
+		pipe.HSet(ctx, places, place.EstablishmentID, string(data))
+		if ttl > 0 {
+			pipe.Expire(ctx, positions, ttl)
+			pipe.Expire(ctx, places, ttl)
+		}
+		return nil
+	})
+	return err
+}
+
+func (g *geoIndex) Remove(ctx context.Context, category, id string) error {
+	positions, places := geoKeys(category)
+
+	_, err := g.rdb.Client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.ZRem(ctx, positions, id)
+		pipe.HDel(ctx, places, id)
+		return nil
+	})
+	return err
+}
+
+func (g *geoIndex) SearchRadius(ctx context.Context, category string, lat, lng, radiusKm float64, limit int) ([]*entity.GeoHit, error) {
+	return g.search(ctx, category, goredis.GeoSearchQuery{
+		Longitude:  lng,
+		Latitude:   lat,
+		Radius:     radiusKm,
+		RadiusUnit: "km",
+		Sort:       "ASC",
+		Count:      limit,
+	})
+}
+
+func (g *geoIndex) SearchBox(ctx context.Context, category string, lat, lng, widthKm, heightKm float64, limit int) ([]*entity.GeoHit, error) {
+	return g.search(ctx, category, goredis.GeoSearchQuery{
+		Longitude: lng,
+		Latitude:  lat,
+		BoxWidth:  widthKm,
+		BoxHeight: heightKm,
+		BoxUnit:   "km",
+		Sort:      "ASC",
+		Count:     limit,
+	})
+}
+
+func (g *geoIndex) search(ctx context.Context, category string, search goredis.GeoSearchQuery) ([]*entity.GeoHit, error) {
+	positions, places := geoKeys(category)
+
+	locations, err := g.rdb.Client.GeoSearchLocation(ctx, positions, &goredis.GeoSearchLocationQuery{
+		GeoSearchQuery: search,
+		WithDist:       true,
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(locations) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, 0, len(locations))
+	for _, location := range locations {
+		ids = append(ids, location.Name)
+	}
+	values, err := g.rdb.Client.HMGet(ctx, places, ids...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	hits := make([]*entity.GeoHit, 0, len(locations))
+	for i, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			continue
+		}
+		var place entity.GeoPlace
+		if err = json.Unmarshal([]byte(data), &place); err != nil {
+			return nil, err
+		}
+		hits = append(hits, &entity.GeoHit{
+			Place:      &place,
+			DistanceKm: locations[i].Dist,
+		})
+	}
+	return hits, nil
+}
+
+func (g *geoIndex) Swap(ctx context.Context, category, build string) error {
+	positions, places := geoKeys(category)
+	stagedPositions, stagedPlaces := stagedKeys(category, build)
+
+	// a rebuild that staged nothing has no keys to rename
+	staged, err := g.rdb.Client.Exists(ctx, stagedPositions).Result()
+	if err != nil {
+		return err
+	}
+	if staged == 0 {
+		return g.rdb.Client.Del(ctx, positions, places).Err()
+	}
+
+	_, err = g.rdb.Client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.Rename(ctx, stagedPositions, positions)
+		pipe.Rename(ctx, stagedPlaces, places)
+		// a rename keeps the expiry of the staged keys
+		pipe.Persist(ctx, positions)
+		pipe.Persist(ctx, places)
+		return nil
+	})
+	return err
+}
+
+func (g *geoIndex) Drop(ctx context.Context, category, build string) error {
+	positions, places := stagedKeys(category, build)
+	return g.rdb.Client.Del(ctx, positions, places).Err()
+}
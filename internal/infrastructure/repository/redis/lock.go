@@ -0,0 +1,43 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Booking/api-service-booking/internal/pkg/redis"
+)
+
+// unlockScript deletes the key only if it still holds our token, so a lock
+// that expired and was taken by another replica is not released by us
+const unlockScript = `
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`
+
+type Locker interface {
+	TryLock(ctx context.Context, key string, ttl time.Duration) (bool, error)
+	Unlock(ctx context.Context, key string) error
+}
+
+func NewLocker(rdb *redis.RedisDB) *locker {
+	return &locker{
+		rdb:   rdb,
+		token: uuid.NewString(),
+	}
+}
+
+type locker struct {
+	rdb   *redis.RedisDB
+	token string
+}
+
+func (l *locker) TryLock(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	return l.rdb.Client.SetNX(ctx, key, l.token, ttl).Result()
+}
+
+func (l *locker) Unlock(ctx context.Context, key string) error {
+	return l.rdb.Client.Eval(ctx, unlockScript, []string{key}, l.token).Err()
+}
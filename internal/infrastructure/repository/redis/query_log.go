@@ -0,0 +1,61 @@
+package redis
+
+import (
+	"context"
+
+	goredis "github.com/go-redis/redis/v8"
+
+	"Booking/api-service-booking/internal/pkg/redis"
+)
+
+// queryLogKey is a sorted set of search queries scored by how often they
+// were searched
+const queryLogKey = "search:queries"
+
+type QueryCount struct {
+	Query string
+	Count float64
+}
+
+type QueryLog interface {
+	Increment(ctx context.Context, query string) error
+	// Top returns the n most searched queries and forgets all but keep of
+	// them, so one-off queries do not pile up
+	Top(ctx context.Context, n, keep int) ([]*QueryCount, error)
+}
+
+func NewQueryLog(rdb *redis.RedisDB) *queryLog {
+	return &queryLog{
+		rdb: rdb,
+	}
+}
+
+type queryLog struct {
+	rdb *redis.RedisDB
+}
+
+func (q *queryLog) Increment(ctx context.Context, query string) error {
+	return q.rdb.Client.ZIncrBy(ctx, queryLogKey, 1, query).Err()
+}
+
+func (q *queryLog) Top(ctx context.Context, n, keep int) ([]*QueryCount, error) {
+	var top *goredis.ZSliceCmd
+	_, err := q.rdb.Client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.ZRemRangeByRank(ctx, queryLogKey, 0, int64(-keep-1))
+		top = pipe.ZRevRangeWithScores(ctx, queryLogKey, 0, int64(n-1))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make([]*QueryCount, 0, len(top.Val()))
+	for _, z := range top.Val() {
+		query, ok := z.Member.(string)
+		if !ok {
+			continue
+		}
+		counts = append(counts, &QueryCount{Query: query, Count: z.Score})
+	}
+	return counts, nil
+}
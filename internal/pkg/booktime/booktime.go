@@ -0,0 +1,45 @@
+package booktime
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// layouts are the formats clients send in will_arrive / will_leave
+var layouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+}
+
+const dateLayout = "2006-01-02"
+
+// Location returns Asia/Tashkent, falling back to a fixed +05:00 zone
+// when the tz database is not available in the image
+func Location() *time.Location {
+	loc, err := time.LoadLocation("Asia/Tashkent")
+	if err != nil {
+		return time.FixedZone("UZT", 5*60*60)
+	}
+	return loc
+}
+
+// Parse reads a booking date, dateOnly reports that no time of day was given
+func Parse(value string) (t time.Time, dateOnly bool, err error) {
+	value = strings.TrimSpace(value)
+
+	if t, err = time.ParseInLocation(dateLayout, value, Location()); err == nil {
+		return t, true, nil
+	}
+
+	for _, layout := range layouts {
+		if t, err = time.ParseInLocation(layout, value, Location()); err == nil {
+			return t, false, nil
+		}
+	}
+
+	return time.Time{}, false, fmt.Errorf("invalid booking date %q", value)
+}
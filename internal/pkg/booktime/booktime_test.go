@@ -0,0 +1,53 @@
+package booktime
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	loc := Location()
+	tests := []struct {
+		value    string
+		want     time.Time
+		dateOnly bool
+		wantErr  bool
+	}{
+		{value: "2026-10-19", want: time.Date(2026, 10, 19, 0, 0, 0, 0, loc), dateOnly: true},
+		{value: " 2026-10-19 ", want: time.Date(2026, 10, 19, 0, 0, 0, 0, loc), dateOnly: true},
+		{value: "2026-10-19T14:30:15", want: time.Date(2026, 10, 19, 14, 30, 15, 0, loc)},
+		{value: "2026-10-19 14:30:15", want: time.Date(2026, 10, 19, 14, 30, 15, 0, loc)},
+		{value: "2026-10-19T14:30", want: time.Date(2026, 10, 19, 14, 30, 0, 0, loc)},
+		{value: "2026-10-19 14:30", want: time.Date(2026, 10, 19, 14, 30, 0, 0, loc)},
+		// an offset wins over the booking time zone
+		{value: "2026-10-19T09:30:00Z", want: time.Date(2026, 10, 19, 14, 30, 0, 0, loc)},
+		{value: "2026-10-19T14:30:00+05:00", want: time.Date(2026, 10, 19, 14, 30, 0, 0, loc)},
+		{value: "19.10.2026", wantErr: true},
+		{value: "2026-10-19T25:00", wantErr: true},
+		{value: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, dateOnly, err := Parse(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want error %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("Parse(%q) = %s, want %s", tt.value, got, tt.want)
+			}
+			if dateOnly != tt.dateOnly {
+				t.Errorf("dateOnly = %v, want %v", dateOnly, tt.dateOnly)
+			}
+		})
+	}
+}
+
+func TestLocation(t *testing.T) {
+	at := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC).In(Location())
+	if _, offset := at.Zone(); offset != 5*60*60 {
+		t.Errorf("offset = %d, want %d", offset, 5*60*60)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"os"
 	"strings"
 	"time"
+
+	"github.com/spf13/cast"
 )
 
 const (
@@ -56,6 +58,44 @@ type Config struct {
 		Location              string
 		MovieUploadBucketName string
 	}
+	Snapshot struct {
+		Bucket string
+		// Hour is the hour of the night snapshots are written at
+		Hour int
+		// Timeout bounds one snapshot, which is written in the
+		// background under a lock of its own
+		Timeout time.Duration
+		LinkTTL time.Duration
+	}
+	Scheduler struct {
+		Interval    time.Duration
+		LockTTL     time.Duration
+		NoShowGrace time.Duration
+		MaxAttempts int
+	}
+	Restaurant struct {
+		SlotLength time.Duration
+		SlotStep   time.Duration
+		Turnover   time.Duration
+	}
+	Waitlist struct {
+		HoldFor time.Duration
+	}
+	ETicket struct {
+		Secret string
+		QRSize int
+	}
+	Loyalty struct {
+		EarnPer    int64
+		PointValue int64
+		PointsTTL  time.Duration
+	}
+	Suggest struct {
+		RefreshInterval time.Duration
+	}
+	Locale struct {
+		Default string
+	}
 	Kafka struct {
 		Address []string
 		Topic   struct {
@@ -127,6 +167,91 @@ func NewConfig() (*Config, error) {
 	config.Token.RefreshTTL = refreshTTL
 	config.Token.SignInKey = getEnv("TOKEN_SIGNIN_KEY", "debug_booking")
 
+	// scheduler configuration
+	schedulerInterval, err := time.ParseDuration(getEnv("SCHEDULER_INTERVAL", "30s"))
+	if err != nil {
+		return nil, err
+	}
+	schedulerLockTTL, err := time.ParseDuration(getEnv("SCHEDULER_LOCK_TTL", "2m"))
+	if err != nil {
+		return nil, err
+	}
+	noShowGrace, err := time.ParseDuration(getEnv("SCHEDULER_NO_SHOW_GRACE", "3h"))
+	if err != nil {
+		return nil, err
+	}
+	config.Scheduler.Interval = schedulerInterval
+	config.Scheduler.LockTTL = schedulerLockTTL
+	config.Scheduler.NoShowGrace = noShowGrace
+	config.Scheduler.MaxAttempts = cast.ToInt(getEnv("SCHEDULER_MAX_ATTEMPTS", "5"))
+
+	// restaurant seating configuration
+	slotLength, err := time.ParseDuration(getEnv("RESTAURANT_SLOT_LENGTH", "90m"))
+	if err != nil {
+		return nil, err
+	}
+	slotStep, err := time.ParseDuration(getEnv("RESTAURANT_SLOT_STEP", "30m"))
+	if err != nil {
+		return nil, err
+	}
+	turnover, err := time.ParseDuration(getEnv("RESTAURANT_TURNOVER", "15m"))
+	if err != nil {
+		return nil, err
+	}
+	config.Restaurant.SlotLength = slotLength
+	config.Restaurant.SlotStep = slotStep
+	config.Restaurant.Turnover = turnover
+
+	// waitlist configuration
+	holdFor, err := time.ParseDuration(getEnv("WAITLIST_HOLD_FOR", "30m"))
+	if err != nil {
+		return nil, err
+	}
+	config.Waitlist.HoldFor = holdFor
+
+	// e-ticket configuration
+	config.ETicket.Secret = getEnv("ETICKET_SECRET", "eticket_secret")
+	config.ETicket.QRSize = cast.ToInt(getEnv("ETICKET_QR_SIZE", "320"))
+
+	// loyalty configuration
+	pointsTTL, err := time.ParseDuration(getEnv("LOYALTY_POINTS_TTL", "8760h"))
+	if err != nil {
+		return nil, err
+	}
+	config.Loyalty.EarnPer = cast.ToInt64(getEnv("LOYALTY_EARN_PER", "1000"))
+	config.Loyalty.PointValue = cast.ToInt64(getEnv("LOYALTY_POINT_VALUE", "10"))
+	config.Loyalty.PointsTTL = pointsTTL
+
+	// search suggestion configuration
+	suggestRefresh, err := time.ParseDuration(getEnv("SUGGEST_REFRESH_INTERVAL", "10m"))
+	if err != nil {
+		return nil, err
+	}
+	config.Suggest.RefreshInterval = suggestRefresh
+
+	// minio configuration
+	config.Minio.Endpoint = getEnv("MINIO_ENDPOINT", "18.185.248.114:9000")
+	config.Minio.AccessKey = getEnv("MINIO_ACCESS_KEY", "minioadmin")
+	config.Minio.SecretKey = getEnv("MINIO_SECRET_KEY", "minioadmin")
+	config.Minio.Location = getEnv("MINIO_LOCATION", "")
+
+	// establishment snapshot configuration
+	snapshotTimeout, err := time.ParseDuration(getEnv("SNAPSHOT_TIMEOUT", "10m"))
+	if err != nil {
+		return nil, err
+	}
+	snapshotLinkTTL, err := time.ParseDuration(getEnv("SNAPSHOT_LINK_TTL", "1h"))
+	if err != nil {
+		return nil, err
+	}
+	config.Snapshot.Bucket = getEnv("SNAPSHOT_BUCKET", "snapshots")
+	config.Snapshot.Hour = cast.ToInt(getEnv("SNAPSHOT_HOUR", "3"))
+	config.Snapshot.Timeout = snapshotTimeout
+	config.Snapshot.LinkTTL = snapshotLinkTTL
+
+	// language establishment names and descriptions are written in
+	config.Locale.Default = getEnv("DEFAULT_LOCALE", "en")
+
 	// otlp collector configuration
 	config.OTLPCollector.Host = getEnv("OTLP_COLLECTOR_HOST", "otel-collector")
 	config.OTLPCollector.Port = getEnv("OTLP_COLLECTOR_PORT", ":4317")
@@ -0,0 +1,78 @@
+package eticket
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/skip2/go-qrcode"
+)
+
+// macLength keeps the payload short enough for a small, easy to scan code
+const macLength = 16
+
+var ErrInvalidPayload = errors.New("ticket payload is invalid")
+
+// Sign returns the QR payload of a booking: its id and an HMAC of the id
+func Sign(secret, bookingID string) string {
+	return bookingID + "." + base64.RawURLEncoding.EncodeToString(mac(secret, bookingID))
+}
+
+// Verify checks a scanned payload and returns the booking id it carries
+func Verify(secret, payload string) (string, error) {
+	i := strings.LastIndex(payload, ".")
+	if i <= 0 {
+		return "", ErrInvalidPayload
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(payload[i+1:])
+	if err != nil {
+		return "", ErrInvalidPayload
+	}
+
+	bookingID := payload[:i]
+	if !hmac.Equal(signature, mac(secret, bookingID)) {
+		return "", ErrInvalidPayload
+	}
+	return bookingID, nil
+}
+
+func mac(secret, bookingID string) []byte {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(bookingID))
+	return h.Sum(nil)[:macLength]
+}
+
+// PNG renders payload as a size x size pixel QR code
+func PNG(payload string, size int) ([]byte, error) {
+	return qrcode.Encode(payload, qrcode.Medium, size)
+}
+
+// SVG renders payload as a QR code drawn with one path, one unit per module
+func SVG(payload string) ([]byte, error) {
+	code, err := qrcode.New(payload, qrcode.Medium)
+	if err != nil {
+		return nil, err
+	}
+	bitmap := code.Bitmap()
+
+	var path strings.Builder
+	for y, row := range bitmap {
+		for x, dark := range row {
+			if dark {
+				fmt.Fprintf(&path, "M%d %dh1v1h-1z", x, y)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" shape-rendering="crispEdges">`, len(bitmap), len(bitmap))
+	buf.WriteString(`<rect width="100%" height="100%" fill="#fff"/>`)
+	fmt.Fprintf(&buf, `<path fill="#000" d="%s"/></svg>`, path.String())
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,94 @@
+package eticket
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+const (
+	testSecret    = "test-secret"
+	testBookingID = "7f0c7a5e-4a8e-4f43-9d4c-1f1b2c3d4e5f"
+)
+
+func TestSignVerify(t *testing.T) {
+	payload := Sign(testSecret, testBookingID)
+	if !strings.HasPrefix(payload, testBookingID+".") {
+		t.Fatalf("payload %q does not start with the booking id", payload)
+	}
+
+	got, err := Verify(testSecret, payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != testBookingID {
+		t.Errorf("Verify = %q, want %q", got, testBookingID)
+	}
+}
+
+func TestVerifyRejects(t *testing.T) {
+	payload := Sign(testSecret, testBookingID)
+	signature := payload[strings.LastIndex(payload, ".")+1:]
+
+	tests := []struct {
+		name    string
+		secret  string
+		payload string
+	}{
+		{"other secret", "other-secret", payload},
+		{"other booking", testSecret, "8a1d2b3c-0000-4f43-9d4c-1f1b2c3d4e5f." + signature},
+		{"changed signature", testSecret, payload[:len(payload)-2] + "AA"},
+		{"short signature", testSecret, testBookingID + "." + signature[:10]},
+		{"not base64", testSecret, testBookingID + ".!!!"},
+		{"no signature", testSecret, testBookingID},
+		{"no booking id", testSecret, "." + signature},
+		{"empty", testSecret, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Verify(tt.secret, tt.payload); !errors.Is(err, ErrInvalidPayload) {
+				t.Errorf("Verify error = %v, want %v", err, ErrInvalidPayload)
+			}
+		})
+	}
+}
+
+func TestPNG(t *testing.T) {
+	data, err := PNG(Sign(testSecret, testBookingID), 256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bounds := img.Bounds(); bounds.Dx() != 256 || bounds.Dy() != 256 {
+		t.Errorf("image is %dx%d, want 256x256", bounds.Dx(), bounds.Dy())
+	}
+}
+
+func TestSVG(t *testing.T) {
+	data, err := SVG(Sign(testSecret, testBookingID))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var svg struct {
+		ViewBox string `xml:"viewBox,attr"`
+		Path    struct {
+			D string `xml:"d,attr"`
+		} `xml:"path"`
+	}
+	if err := xml.Unmarshal(data, &svg); err != nil {
+		t.Fatalf("svg is not valid xml: %v", err)
+	}
+	if !strings.HasPrefix(svg.ViewBox, "0 0 ") {
+		t.Errorf("viewBox = %q", svg.ViewBox)
+	}
+	if !strings.HasPrefix(svg.Path.D, "M") || !strings.HasSuffix(svg.Path.D, "z") {
+		t.Errorf("path draws no modules: %q", svg.Path.D)
+	}
+}
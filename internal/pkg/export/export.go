@@ -0,0 +1,144 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+
+	"Booking/api-service-booking/internal/pkg/i18n"
+)
+
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+	FormatXLSX   = "xlsx"
+)
+
+// Formats are the formats an export can be written in
+var Formats = []string{FormatCSV, FormatNDJSON, FormatXLSX}
+
+// listSeparator joins the items of a list value in a CSV or XLSX cell
+const listSeparator = ";"
+
+// Writer writes an export a row at a time, so nothing but the current row
+// is held in memory. Values are strings, float64 or []string.
+type Writer interface {
+	Write(values []interface{}) error
+	// Close writes what is left of the file, it does not close the
+	// underlying writer
+	Close() error
+}
+
+// NewWriter starts an export in format whose rows have columns
+func NewWriter(format string, w io.Writer, columns []string) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w, columns)
+	case FormatNDJSON:
+		return &ndjsonWriter{w: w, columns: columns}, nil
+	case FormatXLSX:
+		return newXLSXWriter(w, columns)
+	}
+	return nil, i18n.NewError("field_one_of", "format", strings.Join(Formats, ", "))
+}
+
+// ContentType is the media type of a file in format
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "application/octet-stream"
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func newCSVWriter(w io.Writer, columns []string) (*csvWriter, error) {
+	writer := &csvWriter{w: csv.NewWriter(w)}
+	if err := writer.w.Write(columns); err != nil {
+		return nil, err
+	}
+	return writer, nil
+}
+
+func (w *csvWriter) Write(values []interface{}) error {
+	record := make([]string, len(values))
+	for i, value := range values {
+		record[i] = text(value)
+		if _, ok := value.(string); ok {
+			record[i] = defuse(record[i])
+		}
+	}
+	return w.w.Write(record)
+}
+
+func (w *csvWriter) Close() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+type ndjsonWriter struct {
+	w       io.Writer
+	columns []string
+}
+
+// Write puts a row on its own line as an object with the keys in column
+// order
+func (w *ndjsonWriter) Write(values []interface{}) error {
+	var line strings.Builder
+	line.WriteByte('{')
+	for i, value := range values {
+		if i > 0 {
+			line.WriteByte(',')
+		}
+		key, _ := json.Marshal(w.columns[i])
+		line.Write(key)
+		line.WriteByte(':')
+		if value == nil {
+			value = ""
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		line.Write(encoded)
+	}
+	line.WriteString("}\n")
+	_, err := io.WriteString(w.w, line.String())
+	return err
+}
+
+func (w *ndjsonWriter) Close() error {
+	return nil
+}
+
+// text is how value reads in a cell
+func text(value interface{}) string {
+	switch value := value.(type) {
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case []string:
+		return strings.Join(value, listSeparator)
+	}
+	return ""
+}
+
+// defuse keeps a spreadsheet from taking a text cell for a formula. A
+// leading + or - is left alone, phone numbers such as +998 71 123 45 67
+// start with one and would be spoiled by the quote.
+func defuse(value string) string {
+	if value != "" && strings.ContainsRune("=@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
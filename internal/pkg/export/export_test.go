@@ -0,0 +1,197 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var testColumns = []string{"name", "phone", "rating", "tags"}
+
+var testRows = [][]interface{}{
+	{"Plov Centre", "+998 71 123 45 67", 4.5, []string{"uzbek", "halal"}},
+	{"=HYPERLINK(\"http://x\")", "-", 3.0, []string{}},
+	{"@SUM(A1)", "", 0.0, nil},
+	{"Кафе \"Самарканд\" & <Co>", "\tcall", -1.25, []string{"cafe"}},
+}
+
+func writeAll(t *testing.T, format string) []byte {
+	t.Helper()
+
+	var b bytes.Buffer
+	w, err := NewWriter(format, &b, testColumns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, row := range testRows {
+		if err := w.Write(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+func TestDefuse(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"", ""},
+		{"Plov Centre", "Plov Centre"},
+		{"=1+2", "'=1+2"},
+		{"@SUM(A1)", "'@SUM(A1)"},
+		{"\tcall", "'\tcall"},
+		{"\rline", "'\rline"},
+		{"+998 71 123 45 67", "+998 71 123 45 67"},
+		{"-5", "-5"},
+		{"a=b", "a=b"},
+	}
+	for _, tt := range tests {
+		if got := defuse(tt.value); got != tt.want {
+			t.Errorf("defuse(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestCSVWriter(t *testing.T) {
+	records, err := csv.NewReader(bytes.NewReader(writeAll(t, FormatCSV))).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][]string{
+		testColumns,
+		{"Plov Centre", "+998 71 123 45 67", "4.5", "uzbek;halal"},
+		{"'=HYPERLINK(\"http://x\")", "-", "3", ""},
+		{"'@SUM(A1)", "", "0", ""},
+		{"Кафе \"Самарканд\" & <Co>", "'\tcall", "-1.25", "cafe"},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("records = %q, want %q", records, want)
+	}
+}
+
+func TestNDJSONWriter(t *testing.T) {
+	lines := strings.Split(strings.TrimSuffix(string(writeAll(t, FormatNDJSON)), "\n"), "\n")
+	if len(lines) != len(testRows) {
+		t.Fatalf("%d lines, want %d", len(lines), len(testRows))
+	}
+
+	// keys follow the columns and values are not defused
+	if want := `{"name":"Plov Centre","phone":"+998 71 123 45 67","rating":4.5,"tags":["uzbek","halal"]}`; lines[0] != want {
+		t.Errorf("line = %s, want %s", lines[0], want)
+	}
+	var row map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[2]), &row); err != nil {
+		t.Fatal(err)
+	}
+	if row["name"] != "@SUM(A1)" || row["tags"] != "" {
+		t.Errorf("row = %v", row)
+	}
+}
+
+// xlsxCell is a cell of a sheet, inline text or a number
+type xlsxCell struct {
+	Type   string `xml:"t,attr"`
+	Value  string `xml:"v"`
+	Inline string `xml:"is>t"`
+}
+
+// xlsxRows reads the rows of the sheet of a workbook
+func xlsxRows(t *testing.T, data []byte) [][]xlsxCell {
+	t.Helper()
+
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := map[string]*zip.File{}
+	for _, file := range archive.File {
+		parts[file.Name] = file
+	}
+	for _, part := range xlsxParts {
+		if parts[part.name] == nil {
+			t.Errorf("missing part %s", part.name)
+		}
+	}
+	sheetFile := parts["xl/worksheets/sheet1.xml"]
+	if sheetFile == nil {
+		t.Fatal("missing sheet")
+	}
+	reader, err := sheetFile.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var sheet struct {
+		Rows []struct {
+			Cells []xlsxCell `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	if err := xml.Unmarshal(content, &sheet); err != nil {
+		t.Fatalf("sheet is not valid xml: %v", err)
+	}
+	rows := make([][]xlsxCell, len(sheet.Rows))
+	for i, row := range sheet.Rows {
+		rows[i] = row.Cells
+	}
+	return rows
+}
+
+func TestXLSXWriter(t *testing.T) {
+	rows := xlsxRows(t, writeAll(t, FormatXLSX))
+
+	// inline text is never read as a formula, so it is kept as written
+	want := [][]xlsxCell{
+		{{Type: "inlineStr", Inline: "name"}, {Type: "inlineStr", Inline: "phone"}, {Type: "inlineStr", Inline: "rating"}, {Type: "inlineStr", Inline: "tags"}},
+		{{Type: "inlineStr", Inline: "Plov Centre"}, {Type: "inlineStr", Inline: "+998 71 123 45 67"}, {Value: "4.5"}, {Type: "inlineStr", Inline: "uzbek;halal"}},
+		{{Type: "inlineStr", Inline: "=HYPERLINK(\"http://x\")"}, {Type: "inlineStr", Inline: "-"}, {Value: "3"}, {Type: "inlineStr"}},
+		{{Type: "inlineStr", Inline: "@SUM(A1)"}, {Type: "inlineStr"}, {Value: "0"}, {Type: "inlineStr"}},
+		{{Type: "inlineStr", Inline: "Кафе \"Самарканд\" & <Co>"}, {Type: "inlineStr", Inline: "\tcall"}, {Value: "-1.25"}, {Type: "inlineStr", Inline: "cafe"}},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows = %+v, want %+v", rows, want)
+	}
+}
+
+func TestXLSXCellLength(t *testing.T) {
+	var b bytes.Buffer
+	w, err := NewWriter(FormatXLSX, &b, []string{"description"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Write([]interface{}{strings.Repeat("ў", maxCellLength+10)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	rows := xlsxRows(t, b.Bytes())
+	if len(rows) != 2 || len(rows[1]) != 1 {
+		t.Fatalf("rows = %d, want a header and one cell", len(rows))
+	}
+	if got := len([]rune(rows[1][0].Inline)); got != maxCellLength {
+		t.Errorf("cell holds %d characters, want %d", got, maxCellLength)
+	}
+}
+
+func TestNewWriterFormat(t *testing.T) {
+	if _, err := NewWriter("pdf", io.Discard, testColumns); err == nil {
+		t.Error("NewWriter(pdf) did not fail")
+	}
+}
@@ -0,0 +1,27 @@
+package geo
+
+import "math"
+
+// earthRadiusKm is the mean radius of the earth
+const earthRadiusKm = 6371.0
+
+// WalkingSpeedKmh is the pace walking times are worked out with
+const WalkingSpeedKmh = 5.0
+
+// Distance returns the great circle distance between two points in km
+// using the haversine formula
+func Distance(lat1, lng1, lat2, lng2 float64) float64 {
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	dLat := toRad(lat2 - lat1)
+	dLng := toRad(lng2 - lng1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(math.Min(1, a)))
+}
+
+// WalkingMinutes is how long walking km takes, rounded up to whole minutes
+func WalkingMinutes(km float64) int {
+	return int(math.Ceil(km / WalkingSpeedKmh * 60))
+}
@@ -0,0 +1,91 @@
+package geojson
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	// MaxZoom is the deepest zoom level clustering is done for
+	MaxZoom = 22
+	// tileSize and clusterRadius are in screen pixels, points closer
+	// than about clusterRadius on the map at a zoom level are merged
+	tileSize      = 256
+	clusterRadius = 60
+)
+
+// Cluster merges the points that fall into the same clusterRadius sized
+// cell of the web mercator map at zoom. A lone point is kept as it is, a
+// group becomes a point at the mean of its members with cluster,
+// point_count and the count per value of groupBy among its properties.
+func Cluster(features []*Feature, zoom int, groupBy string) []*Feature {
+	type cell struct {
+		x, y int
+	}
+
+	var (
+		cells   []cell
+		members = make(map[cell][]*Feature)
+	)
+	for _, feature := range features {
+		x, y := pixel(feature.Latitude(), feature.Longitude(), zoom)
+		key := cell{x: int(x / clusterRadius), y: int(y / clusterRadius)}
+		if _, ok := members[key]; !ok {
+			cells = append(cells, key)
+		}
+		members[key] = append(members[key], feature)
+	}
+
+	result := make([]*Feature, 0, len(cells))
+	for _, key := range cells {
+		group := members[key]
+		if len(group) == 1 {
+			result = append(result, group[0])
+			continue
+		}
+
+		var lat, lng float64
+		counts := map[string]int{}
+		for _, feature := range group {
+			lat += feature.Latitude()
+			lng += feature.Longitude()
+			if value, ok := feature.Properties[groupBy].(string); ok {
+				counts[value]++
+			}
+		}
+		n := float64(len(group))
+
+		result = append(result, ClusterPoint(zoom, key.x, key.y, lat/n, lng/n, len(group), groupBy, counts))
+	}
+	return result
+}
+
+// ClusterPoint is the point Cluster makes of count points in the cell x, y
+// at zoom, placed at their mean position. counts is their count per value
+// of groupBy.
+func ClusterPoint(zoom, x, y int, lat, lng float64, count int, groupBy string, counts map[string]int) *Feature {
+	return Point(fmt.Sprintf("cluster:%d:%d:%d", zoom, x, y), lat, lng, map[string]interface{}{
+		"cluster":     true,
+		"point_count": count,
+		groupBy:       counts,
+	})
+}
+
+// CellsAcross is how many cells of Cluster span the width and the height
+// of the map at zoom, for grouping points the same way elsewhere
+func CellsAcross(zoom int) float64 {
+	return tileSize * math.Exp2(float64(zoom)) / clusterRadius
+}
+
+// pixel returns where a point is on the web mercator map at zoom in pixels
+func pixel(lat, lng float64, zoom int) (float64, float64) {
+	size := tileSize * math.Exp2(float64(zoom))
+
+	// web mercator does not reach the poles
+	lat = math.Max(-85.05112878, math.Min(85.05112878, lat))
+	sin := math.Sin(lat * math.Pi / 180)
+
+	x := (lng + 180) / 360 * size
+	y := (0.5 - math.Log((1+sin)/(1-sin))/(4*math.Pi)) * size
+	return x, y
+}
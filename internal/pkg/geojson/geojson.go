@@ -0,0 +1,59 @@
+package geojson
+
+// ContentType is the media type of GeoJSON (RFC 7946)
+const ContentType = "application/geo+json"
+
+// FeatureCollection is a list of features. The cursors of the pages next
+// to a paged one are foreign members, which RFC 7946 allows.
+type FeatureCollection struct {
+	Type       string     `json:"type"`
+	Features   []*Feature `json:"features"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+	PrevCursor string     `json:"prev_cursor,omitempty"`
+}
+
+type Feature struct {
+	Type       string                 `json:"type"`
+	ID         string                 `json:"id,omitempty"`
+	Geometry   Geometry               `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// Geometry is always a point here, coordinates are longitude then latitude
+type Geometry struct {
+	Type        string    `json:"type"`
+	Coordinates []float64 `json:"coordinates"`
+}
+
+func NewFeatureCollection(features []*Feature) *FeatureCollection {
+	if features == nil {
+		features = []*Feature{}
+	}
+	return &FeatureCollection{
+		Type:     "FeatureCollection",
+		Features: features,
+	}
+}
+
+func Point(id string, lat, lng float64, properties map[string]interface{}) *Feature {
+	if properties == nil {
+		properties = map[string]interface{}{}
+	}
+	return &Feature{
+		Type: "Feature",
+		ID:   id,
+		Geometry: Geometry{
+			Type:        "Point",
+			Coordinates: []float64{lng, lat},
+		},
+		Properties: properties,
+	}
+}
+
+func (f *Feature) Latitude() float64 {
+	return f.Geometry.Coordinates[1]
+}
+
+func (f *Feature) Longitude() float64 {
+	return f.Geometry.Coordinates[0]
+}
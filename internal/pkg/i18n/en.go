@@ -0,0 +1,169 @@
+package i18n
+
+var english = map[string]string{
+	// general
+	"try_again_later":      "Try Again Later...",
+	"something_went_wrong": "Something went wrong",
+	"not_true_form":        "Not true form of request",
+	"permission_denied":    "Permission denied",
+	"log_in_again":         "Log In Again",
+	"owner_only":           "Only the owner can see this",
+	"not_deleted":          "Not deleted",
+	"file_too_large":       "File size cannot be larger than 10 MB",
+	"image_format":         "Only .jpg and .png format images are accepted",
+	"incorrect_date":       "Incorrect Date",
+
+	// fields
+	"field_required":       "{0} is required",
+	"field_format":         "{0} must look like {1}",
+	"field_one_of":         "{0} must be one of {1}",
+	"field_between":        "{0} must be from {1} to {2}",
+	"field_at_least":       "{0} must be at least {1}",
+	"field_positive":       "{0} must be positive",
+	"field_not_negative":   "{0} can not be negative",
+	"field_not_zero":       "{0} can not be zero",
+	"field_number":         "{0} must be a number",
+	"field_whole_number":   "{0} must be a whole number",
+	"field_max_length":     "{0} can be at most {1} characters",
+	"field_length_between": "{0} must be {1} to {2} characters",
+	"field_invalid_date":   "{0} is not a valid date",
+	"field_in_past":        "{0} is in the past",
+	"field_after":          "{0} must be after {1}",
+	"field_needs_time":     "{0} must contain the time",
+	"field_invalid_param":  "invalid `{0}` param",
+	"labeled":              "{0}: {1}",
+	"unknown_category":     "Unknown category \"{0}\"",
+	"invalid_cursor":       "Invalid cursor",
+	"invalid_page":         "Invalid page",
+
+	// users and sign in
+	"cant_get_user":           "Can't get user",
+	"user_not_found":          "User not found",
+	"user_details_failed":     "Failed to retrieve user details",
+	"user_update_failed":      "Error updating user",
+	"email_taken":             "Email already in use, please use another email address",
+	"incorrect_email":         "Incorrect email. Try again",
+	"incorrect_password":      "Incorrect Password. Try again",
+	"incorrect_credentials":   "Incorrect email or password",
+	"incorrect_code":          "Incorrect code. Try again",
+	"incorrect_token":         "Incorrect token.",
+	"refresh_token_expired":   "Refresh token expired",
+	"weak_password":           "Password must be at least 8 (numbers and characters) long",
+	"only_users_become_staff": "Only users can become staff",
+	"staff_exists":            "The user already works there",
+	"staff_not_found":         "The user does not work there",
+
+	// establishments
+	"establishment_not_found":   "Establishment not found",
+	"attraction_not_found":      "Attraction not found",
+	"restaurant_not_found":      "Restaurant not found",
+	"hotel_details_failed":      "Failed to retrieve hotel details",
+	"restaurant_details_failed": "Failed to retrieve restaurant details",
+	"no_opening_hours":          "The establishment has no opening hours",
+	"no_valid_opening_hours":    "Restaurant has no valid opening hours",
+	"no_rate":                   "The establishment has no rate",
+	"no_translation":            "The establishment has no translation to this language",
+	"default_locale":            "{0} is the default language, update the establishment itself",
+	"name_or_description":       "name or description is required",
+	"invalid_opening_hours":     "Invalid opening hours \"{0}\"",
+	"invalid_time":              "Invalid time \"{0}\"",
+	"unknown_day":               "Unknown day \"{0}\"",
+	"invalid_exception_date":    "Invalid exception date \"{0}\"",
+	"opening_windows_overlap":   "Opening windows overlap",
+	"tag_not_found":             "Tag not found",
+	"tag_slug_taken":            "A tag with this slug already exists",
+	"tag_slug_format":           "slug must be up to {0} lowercase letters, digits and dashes, like wheelchair-access",
+	"unknown_tag":               "Unknown tag \"{0}\"",
+	"tag_not_applicable":        "Tag \"{0}\" does not apply to a {1}",
+	"too_many_tags":             "An establishment can have at most {0} tags",
+	"table_not_found":           "Table not found",
+	"no_free_table":             "No free table for this time, choose another slot",
+	"no_free_room":              "No free room for these dates, choose other dates",
+	"restaurant_closed_at":      "The restaurant is closed at {0}",
+
+	// search
+	"distance_needs_near":  "Sorting by distance needs near",
+	"cannot_sort_by":       "Can not sort by \"{0}\"",
+	"unknown_filter":       "unknown filter `{0}`",
+	"price_min_above_max":  "price_min is above price_max",
+	"near_or_bbox":         "near or bbox is required",
+	"invalid_bounding_box": "Invalid bounding box",
+	"invalid_coordinates":  "Invalid coordinates",
+	"bounding_box_too_big": "The bounding box may be at most {0} km on a side",
+	"radius_range":         "radius_km must be above 0 and at most {0}",
+
+	// bookings
+	"booking_not_found":           "Booking not found",
+	"booking_is":                  "Booking is {0}",
+	"booking_locked":              "Booking is {0}, it can not be changed",
+	"booking_cannot_move":         "A booking can not be moved to another establishment",
+	"booking_other_establishment": "The booking is for another establishment",
+	"nothing_to_book":             "There is nothing to book on these dates",
+	"already_waitlisted":          "You are already in line for this date",
+	"place_available":             "There are still places on this date, book it instead",
+	"waitlist_entry_not_found":    "Waitlist entry not found",
+	"no_open_offer":               "There is no open offer for this entry",
+	"date_range":                  "from and to must be dates and from must not be after to",
+	"date_range_too_long":         "to must be after from and at most 92 days later",
+
+	// tickets
+	"ticket_invalid":         "Ticket is not valid",
+	"ticket_scanned":         "Ticket has already been scanned",
+	"ticket_type_not_found":  "Ticket type not found",
+	"ticket_type_taken":      "The attraction already sells this ticket type",
+	"unknown_ticket_kind":    "Unknown ticket kind \"{0}\"",
+	"ticket_kind_not_sold":   "The attraction does not sell {0} tickets",
+	"timed_tickets_not_sold": "The attraction does not sell timed tickets",
+	"ticket_required":        "at least one ticket is required",
+	"slot_not_positive":      "slot length and capacity must be positive",
+	"entry_times":            "entry times start every {0} from {1}",
+	"attraction_closed_at":   "The attraction is closed at {0}",
+	"no_tickets_left":        "Not enough tickets left for this time, choose another slot",
+
+	// promotions and loyalty
+	"promotion_not_found":  "Promotion not found",
+	"promotion_code_taken": "A promotion with this code already exists",
+	"percent_over_100":     "percent value can not be over 100",
+	"promo_unknown":        "Unknown promo code",
+	"promo_not_active":     "The promo code is not active yet",
+	"promo_expired":        "The promo code has expired",
+	"promo_used_up":        "The promo code has been used up",
+	"promo_wrong_category": "The promo code is not valid for {0} bookings",
+	"promo_wrong_city":     "The promo code is only valid in {0}",
+	"promo_first_booking":  "The promo code is only valid for a first booking",
+	"promo_below_minimum":  "The booking is below the minimum price of the promo code",
+	"promo_already_used":   "You have already used this promo code",
+	"not_enough_points":    "Not enough loyalty points",
+	"user_points_short":    "The user does not have that many points",
+	"points_over_price":    "Those points are worth more than the booking costs",
+
+	// trips and itineraries
+	"trip_not_found":         "Trip not found",
+	"trip_item_not_found":    "Trip item not found",
+	"trip_item_failed":       "Item {0} ({1}): {2}",
+	"trip_empty":             "The trip has no items",
+	"trip_full":              "A trip can hold at most {0} items",
+	"trip_item_fields":       "hra_id and will_arrive are required",
+	"trip_checked_out":       "The trip is already checked out",
+	"trip_checkout_expired":  "The checkout took too long and was rolled back, please try again",
+	"itinerary_not_found":    "Itinerary not found",
+	"itinerary_days":         "An itinerary has from 1 to {0} days",
+	"invalid_day":            "Invalid day \"{0}\"",
+	"stop_needs_hra_id":      "Every stop needs a hra_id",
+	"stop_ends_before_start": "A stop must end after it starts",
+	"lat_lng_together":       "latitude and longitude go together",
+
+	// imports
+	"import_not_found":     "Import not found",
+	"import_not_dry_run":   "Only a finished dry run can be applied",
+	"import_empty":         "The file has no rows",
+	"import_too_many_rows": "A file can have at most {0} rows",
+	"invalid_file":         "The file can not be read",
+	"invalid_row":          "The row can not be read",
+	"unknown_column":       "Unknown column \"{0}\"",
+	"missing_column":       "The file has no {0} column",
+	"duplicate_ref":        "external_ref \"{0}\" is already on line {1}",
+
+	// snapshots
+	"snapshot_not_found": "Snapshot not found",
+}
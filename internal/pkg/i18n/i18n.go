@@ -0,0 +1,86 @@
+package i18n
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/ru"
+	"github.com/go-playground/locales/uz"
+	ut "github.com/go-playground/universal-translator"
+
+	"Booking/api-service-booking/internal/pkg/locale"
+)
+
+var universal = ut.New(en.New(), en.New(), ru.New(), uz.New())
+
+var catalogs = map[string]map[string]string{
+	locale.English: english,
+	locale.Russian: russian,
+	locale.Uzbek:   uzbek,
+}
+
+func init() {
+	for lang, messages := range catalogs {
+		trans, _ := universal.GetTranslator(lang)
+		for key, text := range messages {
+			if err := trans.Add(key, text, false); err != nil {
+				panic(fmt.Sprintf("i18n: %s %s: %v", lang, key, err))
+			}
+		}
+	}
+}
+
+// T tells the message key in lang, filling {0}, {1}... in with params.
+// Errors among params are told in lang too. A message missing from lang is
+// told in English, one missing from every catalog by its key.
+func T(lang, key string, params ...interface{}) string {
+	values := make([]string, len(params))
+	for i, param := range params {
+		if err, ok := param.(error); ok {
+			values[i] = Message(lang, err)
+			continue
+		}
+		values[i] = fmt.Sprint(param)
+	}
+
+	trans, _ := universal.GetTranslator(lang)
+	if text, err := trans.T(key, values...); err == nil {
+		return text
+	}
+	trans, _ = universal.GetTranslator(locale.English)
+	if text, err := trans.T(key, values...); err == nil {
+		return text
+	}
+	return key
+}
+
+// Error is an error worded by a catalog message, so it can be told in the
+// language of whoever gets it
+type Error struct {
+	Key    string
+	Params []interface{}
+}
+
+func NewError(key string, params ...interface{}) *Error {
+	return &Error{Key: key, Params: params}
+}
+
+func (e *Error) Error() string {
+	return e.In(locale.English)
+}
+
+// In tells e in lang
+func (e *Error) In(lang string) string {
+	return T(lang, e.Key, e.Params...)
+}
+
+// Message tells err in lang if it, or an error it wraps, is an Error, and
+// as it is otherwise
+func Message(lang string, err error) string {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.In(lang)
+	}
+	return err.Error()
+}
@@ -0,0 +1,169 @@
+package i18n
+
+var russian = map[string]string{
+	// general
+	"try_again_later":      "Попробуйте позже...",
+	"something_went_wrong": "Что-то пошло не так",
+	"not_true_form":        "Неверный формат запроса",
+	"permission_denied":    "Доступ запрещён",
+	"log_in_again":         "Войдите снова",
+	"owner_only":           "Это доступно только владельцу",
+	"not_deleted":          "Не удалено",
+	"file_too_large":       "Размер файла не может превышать 10 МБ",
+	"image_format":         "Принимаются только изображения .jpg и .png",
+	"incorrect_date":       "Некорректная дата",
+
+	// fields
+	"field_required":       "{0} обязателен",
+	"field_format":         "{0} должен выглядеть как {1}",
+	"field_one_of":         "{0} должен быть одним из: {1}",
+	"field_between":        "{0} должен быть от {1} до {2}",
+	"field_at_least":       "{0} должен быть не меньше {1}",
+	"field_positive":       "{0} должен быть положительным",
+	"field_not_negative":   "{0} не может быть отрицательным",
+	"field_not_zero":       "{0} не может быть нулём",
+	"field_number":         "{0} должен быть числом",
+	"field_whole_number":   "{0} должен быть целым числом",
+	"field_max_length":     "{0} может содержать не более {1} символов",
+	"field_length_between": "{0} должен содержать от {1} до {2} символов",
+	"field_invalid_date":   "{0} — некорректная дата",
+	"field_in_past":        "{0} уже в прошлом",
+	"field_after":          "{0} должен быть позже {1}",
+	"field_needs_time":     "{0} должен содержать время",
+	"field_invalid_param":  "некорректный параметр `{0}`",
+	"labeled":              "{0}: {1}",
+	"unknown_category":     "Неизвестная категория «{0}»",
+	"invalid_cursor":       "Некорректный курсор",
+	"invalid_page":         "Некорректная страница",
+
+	// users and sign in
+	"cant_get_user":           "Не удалось определить пользователя",
+	"user_not_found":          "Пользователь не найден",
+	"user_details_failed":     "Не удалось получить данные пользователя",
+	"user_update_failed":      "Не удалось обновить пользователя",
+	"email_taken":             "Этот email уже используется, укажите другой",
+	"incorrect_email":         "Неверный email. Попробуйте снова",
+	"incorrect_password":      "Неверный пароль. Попробуйте снова",
+	"incorrect_credentials":   "Неверный email или пароль",
+	"incorrect_code":          "Неверный код. Попробуйте снова",
+	"incorrect_token":         "Неверный токен.",
+	"refresh_token_expired":   "Срок действия refresh-токена истёк",
+	"weak_password":           "Пароль должен быть не короче 8 символов и содержать буквы и цифры",
+	"only_users_become_staff": "Сотрудником может стать только пользователь",
+	"staff_exists":            "Пользователь уже там работает",
+	"staff_not_found":         "Пользователь там не работает",
+
+	// establishments
+	"establishment_not_found":   "Заведение не найдено",
+	"attraction_not_found":      "Достопримечательность не найдена",
+	"restaurant_not_found":      "Ресторан не найден",
+	"hotel_details_failed":      "Не удалось получить данные отеля",
+	"restaurant_details_failed": "Не удалось получить данные ресторана",
+	"no_opening_hours":          "У заведения не указаны часы работы",
+	"no_valid_opening_hours":    "У ресторана нет корректных часов работы",
+	"no_rate":                   "У заведения нет тарифа",
+	"no_translation":            "У заведения нет перевода на этот язык",
+	"default_locale":            "{0} — основной язык, измените само заведение",
+	"name_or_description":       "нужно указать name или description",
+	"invalid_opening_hours":     "Некорректные часы работы «{0}»",
+	"invalid_time":              "Некорректное время «{0}»",
+	"unknown_day":               "Неизвестный день «{0}»",
+	"invalid_exception_date":    "Некорректная дата исключения «{0}»",
+	"opening_windows_overlap":   "Часы работы пересекаются",
+	"tag_not_found":             "Тег не найден",
+	"tag_slug_taken":            "Тег с таким slug уже существует",
+	"tag_slug_format":           "slug может содержать до {0} строчных букв, цифр и дефисов, например wheelchair-access",
+	"unknown_tag":               "Неизвестный тег «{0}»",
+	"tag_not_applicable":        "Тег «{0}» не подходит для категории {1}",
+	"too_many_tags":             "У заведения может быть не более {0} тегов",
+	"table_not_found":           "Столик не найден",
+	"no_free_table":             "На это время нет свободных столиков, выберите другое",
+	"no_free_room":              "На эти даты нет свободных номеров, выберите другие",
+	"restaurant_closed_at":      "В {0} ресторан закрыт",
+
+	// search
+	"distance_needs_near":  "Для сортировки по расстоянию нужен near",
+	"cannot_sort_by":       "Нельзя сортировать по «{0}»",
+	"unknown_filter":       "неизвестный фильтр `{0}`",
+	"price_min_above_max":  "price_min больше price_max",
+	"near_or_bbox":         "Нужно указать near или bbox",
+	"invalid_bounding_box": "Некорректная область",
+	"invalid_coordinates":  "Некорректные координаты",
+	"bounding_box_too_big": "Сторона области может быть не больше {0} км",
+	"radius_range":         "radius_km должен быть больше 0 и не больше {0}",
+
+	// bookings
+	"booking_not_found":           "Бронирование не найдено",
+	"booking_is":                  "Статус бронирования: {0}",
+	"booking_locked":              "Статус бронирования: {0}, его нельзя изменить",
+	"booking_cannot_move":         "Бронирование нельзя перенести в другое заведение",
+	"booking_other_establishment": "Бронирование относится к другому заведению",
+	"nothing_to_book":             "На эти даты нечего забронировать",
+	"already_waitlisted":          "Вы уже в очереди на эту дату",
+	"place_available":             "На эту дату ещё есть места, забронируйте её",
+	"waitlist_entry_not_found":    "Запись в листе ожидания не найдена",
+	"no_open_offer":               "Для этой записи нет открытого предложения",
+	"date_range":                  "from и to должны быть датами, и from не может быть позже to",
+	"date_range_too_long":         "to должен быть позже from не более чем на 92 дня",
+
+	// tickets
+	"ticket_invalid":         "Билет недействителен",
+	"ticket_scanned":         "Билет уже отсканирован",
+	"ticket_type_not_found":  "Тип билета не найден",
+	"ticket_type_taken":      "Этот тип билетов уже продаётся",
+	"unknown_ticket_kind":    "Неизвестный вид билета «{0}»",
+	"ticket_kind_not_sold":   "Билеты {0} здесь не продаются",
+	"timed_tickets_not_sold": "Здесь не продают билеты на время",
+	"ticket_required":        "нужен хотя бы один билет",
+	"slot_not_positive":      "длина слота и вместимость должны быть положительными",
+	"entry_times":            "вход каждые {0}, начиная с {1}",
+	"attraction_closed_at":   "В {0} достопримечательность закрыта",
+	"no_tickets_left":        "На это время не хватает билетов, выберите другое",
+
+	// promotions and loyalty
+	"promotion_not_found":  "Акция не найдена",
+	"promotion_code_taken": "Акция с таким кодом уже существует",
+	"percent_over_100":     "процент не может быть больше 100",
+	"promo_unknown":        "Неизвестный промокод",
+	"promo_not_active":     "Промокод ещё не действует",
+	"promo_expired":        "Срок действия промокода истёк",
+	"promo_used_up":        "Промокод исчерпан",
+	"promo_wrong_category": "Промокод не действует для бронирований категории {0}",
+	"promo_wrong_city":     "Промокод действует только в городе {0}",
+	"promo_first_booking":  "Промокод действует только для первого бронирования",
+	"promo_below_minimum":  "Сумма бронирования меньше минимальной для промокода",
+	"promo_already_used":   "Вы уже использовали этот промокод",
+	"not_enough_points":    "Недостаточно баллов лояльности",
+	"user_points_short":    "У пользователя нет столько баллов",
+	"points_over_price":    "Эти баллы стоят больше, чем бронирование",
+
+	// trips and itineraries
+	"trip_not_found":         "Поездка не найдена",
+	"trip_item_not_found":    "Элемент поездки не найден",
+	"trip_item_failed":       "Элемент {0} ({1}): {2}",
+	"trip_empty":             "В поездке нет элементов",
+	"trip_full":              "В поездке может быть не более {0} элементов",
+	"trip_item_fields":       "hra_id и will_arrive обязательны",
+	"trip_checked_out":       "Поездка уже оформлена",
+	"trip_checkout_expired":  "Оформление заняло слишком много времени и было отменено, попробуйте ещё раз",
+	"itinerary_not_found":    "Маршрут не найден",
+	"itinerary_days":         "Маршрут может длиться от 1 до {0} дней",
+	"invalid_day":            "Некорректный день «{0}»",
+	"stop_needs_hra_id":      "У каждой остановки должен быть hra_id",
+	"stop_ends_before_start": "Остановка должна заканчиваться после начала",
+	"lat_lng_together":       "latitude и longitude указываются вместе",
+
+	// imports
+	"import_not_found":     "Импорт не найден",
+	"import_not_dry_run":   "Применить можно только завершённый пробный запуск",
+	"import_empty":         "В файле нет строк",
+	"import_too_many_rows": "В файле может быть не более {0} строк",
+	"invalid_file":         "Не удалось прочитать файл",
+	"invalid_row":          "Не удалось прочитать строку",
+	"unknown_column":       "Неизвестный столбец «{0}»",
+	"missing_column":       "В файле нет столбца {0}",
+	"duplicate_ref":        "external_ref «{0}» уже есть в строке {1}",
+
+	// snapshots
+	"snapshot_not_found": "Снимок не найден",
+}
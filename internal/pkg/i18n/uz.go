@@ -0,0 +1,169 @@
+package i18n
+
+var uzbek = map[string]string{
+	// general
+	"try_again_later":      "Keyinroq qayta urinib ko'ring...",
+	"something_went_wrong": "Nimadir noto'g'ri ketdi",
+	"not_true_form":        "So'rov shakli noto'g'ri",
+	"permission_denied":    "Ruxsat yo'q",
+	"log_in_again":         "Qaytadan kiring",
+	"owner_only":           "Buni faqat egasi ko'ra oladi",
+	"not_deleted":          "O'chirilmadi",
+	"file_too_large":       "Fayl hajmi 10 MB dan oshmasligi kerak",
+	"image_format":         "Faqat .jpg va .png rasmlar qabul qilinadi",
+	"incorrect_date":       "Sana noto'g'ri",
+
+	// fields
+	"field_required":       "{0} majburiy",
+	"field_format":         "{0} {1} ko'rinishida bo'lishi kerak",
+	"field_one_of":         "{0} quyidagilardan biri bo'lishi kerak: {1}",
+	"field_between":        "{0} {1} dan {2} gacha bo'lishi kerak",
+	"field_at_least":       "{0} kamida {1} bo'lishi kerak",
+	"field_positive":       "{0} musbat bo'lishi kerak",
+	"field_not_negative":   "{0} manfiy bo'lishi mumkin emas",
+	"field_not_zero":       "{0} nol bo'lishi mumkin emas",
+	"field_number":         "{0} son bo'lishi kerak",
+	"field_whole_number":   "{0} butun son bo'lishi kerak",
+	"field_max_length":     "{0} ko'pi bilan {1} ta belgidan iborat bo'lishi mumkin",
+	"field_length_between": "{0} {1} tadan {2} tagacha belgidan iborat bo'lishi kerak",
+	"field_invalid_date":   "{0} to'g'ri sana emas",
+	"field_in_past":        "{0} o'tib ketgan",
+	"field_after":          "{0} {1} dan keyin bo'lishi kerak",
+	"field_needs_time":     "{0} vaqtni ham o'z ichiga olishi kerak",
+	"field_invalid_param":  "`{0}` parametri noto'g'ri",
+	"labeled":              "{0}: {1}",
+	"unknown_category":     "Noma'lum kategoriya \"{0}\"",
+	"invalid_cursor":       "Kursor noto'g'ri",
+	"invalid_page":         "Sahifa noto'g'ri",
+
+	// users and sign in
+	"cant_get_user":           "Foydalanuvchini aniqlab bo'lmadi",
+	"user_not_found":          "Foydalanuvchi topilmadi",
+	"user_details_failed":     "Foydalanuvchi ma'lumotlarini olib bo'lmadi",
+	"user_update_failed":      "Foydalanuvchini yangilab bo'lmadi",
+	"email_taken":             "Bu email band, boshqasini kiriting",
+	"incorrect_email":         "Email noto'g'ri. Qayta urinib ko'ring",
+	"incorrect_password":      "Parol noto'g'ri. Qayta urinib ko'ring",
+	"incorrect_credentials":   "Email yoki parol noto'g'ri",
+	"incorrect_code":          "Kod noto'g'ri. Qayta urinib ko'ring",
+	"incorrect_token":         "Token noto'g'ri.",
+	"refresh_token_expired":   "Refresh token muddati tugagan",
+	"weak_password":           "Parol kamida 8 ta belgidan iborat bo'lib, harf va raqamlarni o'z ichiga olishi kerak",
+	"only_users_become_staff": "Faqat foydalanuvchilar xodim bo'la oladi",
+	"staff_exists":            "Foydalanuvchi u yerda allaqachon ishlaydi",
+	"staff_not_found":         "Foydalanuvchi u yerda ishlamaydi",
+
+	// establishments
+	"establishment_not_found":   "Muassasa topilmadi",
+	"attraction_not_found":      "Diqqatga sazovor joy topilmadi",
+	"restaurant_not_found":      "Restoran topilmadi",
+	"hotel_details_failed":      "Mehmonxona ma'lumotlarini olib bo'lmadi",
+	"restaurant_details_failed": "Restoran ma'lumotlarini olib bo'lmadi",
+	"no_opening_hours":          "Muassasaning ish vaqti belgilanmagan",
+	"no_valid_opening_hours":    "Restoranning ish vaqti to'g'ri belgilanmagan",
+	"no_rate":                   "Muassasaning tarifi yo'q",
+	"no_translation":            "Muassasaning bu tilga tarjimasi yo'q",
+	"default_locale":            "{0} asosiy til, muassasaning o'zini tahrirlang",
+	"name_or_description":       "name yoki description kiritilishi kerak",
+	"invalid_opening_hours":     "Ish vaqti noto'g'ri \"{0}\"",
+	"invalid_time":              "Vaqt noto'g'ri \"{0}\"",
+	"unknown_day":               "Noma'lum kun \"{0}\"",
+	"invalid_exception_date":    "Istisno sanasi noto'g'ri \"{0}\"",
+	"opening_windows_overlap":   "Ish vaqtlari bir-biriga to'g'ri keladi",
+	"tag_not_found":             "Teg topilmadi",
+	"tag_slug_taken":            "Bunday slug bilan teg allaqachon bor",
+	"tag_slug_format":           "slug ko'pi bilan {0} ta kichik harf, raqam va chiziqchadan iborat bo'lishi kerak, masalan wheelchair-access",
+	"unknown_tag":               "Noma'lum teg \"{0}\"",
+	"tag_not_applicable":        "\"{0}\" tegi {1} uchun emas",
+	"too_many_tags":             "Muassasada ko'pi bilan {0} ta teg bo'lishi mumkin",
+	"table_not_found":           "Stol topilmadi",
+	"no_free_table":             "Bu vaqtga bo'sh stol yo'q, boshqa vaqtni tanlang",
+	"no_free_room":              "Bu sanalarga bo'sh xona yo'q, boshqa sanalarni tanlang",
+	"restaurant_closed_at":      "Restoran soat {0} da yopiq",
+
+	// search
+	"distance_needs_near":  "Masofa bo'yicha saralash uchun near kerak",
+	"cannot_sort_by":       "\"{0}\" bo'yicha saralab bo'lmaydi",
+	"unknown_filter":       "`{0}` filtri noma'lum",
+	"price_min_above_max":  "price_min price_max dan katta",
+	"near_or_bbox":         "near yoki bbox kiritilishi kerak",
+	"invalid_bounding_box": "Hudud noto'g'ri",
+	"invalid_coordinates":  "Koordinatalar noto'g'ri",
+	"bounding_box_too_big": "Hudud tomoni ko'pi bilan {0} km bo'lishi mumkin",
+	"radius_range":         "radius_km 0 dan katta va ko'pi bilan {0} bo'lishi kerak",
+
+	// bookings
+	"booking_not_found":           "Bron topilmadi",
+	"booking_is":                  "Bron holati: {0}",
+	"booking_locked":              "Bron holati: {0}, uni o'zgartirib bo'lmaydi",
+	"booking_cannot_move":         "Bronni boshqa muassasaga o'tkazib bo'lmaydi",
+	"booking_other_establishment": "Bron boshqa muassasaga tegishli",
+	"nothing_to_book":             "Bu sanalarda bron qilish uchun hech narsa yo'q",
+	"already_waitlisted":          "Siz bu sana uchun allaqachon navbatdasiz",
+	"place_available":             "Bu sanada hali joy bor, uni band qiling",
+	"waitlist_entry_not_found":    "Kutish ro'yxatidagi yozuv topilmadi",
+	"no_open_offer":               "Bu yozuv uchun ochiq taklif yo'q",
+	"date_range":                  "from va to sana bo'lishi kerak, from to dan keyin bo'lmasligi kerak",
+	"date_range_too_long":         "to from dan keyin va ko'pi bilan 92 kun keyin bo'lishi kerak",
+
+	// tickets
+	"ticket_invalid":         "Chipta yaroqsiz",
+	"ticket_scanned":         "Chipta allaqachon skanerlangan",
+	"ticket_type_not_found":  "Chipta turi topilmadi",
+	"ticket_type_taken":      "Bu chipta turi allaqachon sotilmoqda",
+	"unknown_ticket_kind":    "Noma'lum chipta turi \"{0}\"",
+	"ticket_kind_not_sold":   "Bu yerda {0} chiptalari sotilmaydi",
+	"timed_tickets_not_sold": "Bu yerda vaqtli chiptalar sotilmaydi",
+	"ticket_required":        "kamida bitta chipta kerak",
+	"slot_not_positive":      "slot uzunligi va sig'imi musbat bo'lishi kerak",
+	"entry_times":            "kirish har {0} da, {1} dan boshlanadi",
+	"attraction_closed_at":   "Diqqatga sazovor joy soat {0} da yopiq",
+	"no_tickets_left":        "Bu vaqtga chiptalar yetarli emas, boshqa vaqtni tanlang",
+
+	// promotions and loyalty
+	"promotion_not_found":  "Aksiya topilmadi",
+	"promotion_code_taken": "Bunday kodli aksiya allaqachon bor",
+	"percent_over_100":     "foiz 100 dan oshmasligi kerak",
+	"promo_unknown":        "Noma'lum promokod",
+	"promo_not_active":     "Promokod hali amal qilmaydi",
+	"promo_expired":        "Promokod muddati tugagan",
+	"promo_used_up":        "Promokod tugagan",
+	"promo_wrong_category": "Promokod {0} bronlari uchun amal qilmaydi",
+	"promo_wrong_city":     "Promokod faqat {0} shahrida amal qiladi",
+	"promo_first_booking":  "Promokod faqat birinchi bron uchun amal qiladi",
+	"promo_below_minimum":  "Bron summasi promokodning eng kam narxidan past",
+	"promo_already_used":   "Siz bu promokoddan allaqachon foydalangansiz",
+	"not_enough_points":    "Sodiqlik ballari yetarli emas",
+	"user_points_short":    "Foydalanuvchida buncha ball yo'q",
+	"points_over_price":    "Bu ballar bron narxidan qimmat",
+
+	// trips and itineraries
+	"trip_not_found":         "Sayohat topilmadi",
+	"trip_item_not_found":    "Sayohat bandi topilmadi",
+	"trip_item_failed":       "{0}-band ({1}): {2}",
+	"trip_empty":             "Sayohatda bandlar yo'q",
+	"trip_full":              "Sayohatda ko'pi bilan {0} ta band bo'lishi mumkin",
+	"trip_item_fields":       "hra_id va will_arrive majburiy",
+	"trip_checked_out":       "Sayohat allaqachon rasmiylashtirilgan",
+	"trip_checkout_expired":  "Rasmiylashtirish juda uzoq davom etdi va bekor qilindi, qaytadan urinib ko'ring",
+	"itinerary_not_found":    "Marshrut topilmadi",
+	"itinerary_days":         "Marshrut 1 kundan {0} kungacha bo'ladi",
+	"invalid_day":            "Kun noto'g'ri \"{0}\"",
+	"stop_needs_hra_id":      "Har bir to'xtash joyida hra_id bo'lishi kerak",
+	"stop_ends_before_start": "To'xtash boshlanganidan keyin tugashi kerak",
+	"lat_lng_together":       "latitude va longitude birga kiritiladi",
+
+	// imports
+	"import_not_found":     "Import topilmadi",
+	"import_not_dry_run":   "Faqat tugagan sinov ishga tushirishini qo'llash mumkin",
+	"import_empty":         "Faylda qatorlar yo'q",
+	"import_too_many_rows": "Faylda ko'pi bilan {0} ta qator bo'lishi mumkin",
+	"invalid_file":         "Faylni o'qib bo'lmadi",
+	"invalid_row":          "Qatorni o'qib bo'lmadi",
+	"unknown_column":       "Noma'lum ustun \"{0}\"",
+	"missing_column":       "Faylda {0} ustuni yo'q",
+	"duplicate_ref":        "external_ref \"{0}\" {1}-qatorda allaqachon bor",
+
+	// snapshots
+	"snapshot_not_found": "Snapshot topilmadi",
+}
@@ -0,0 +1,127 @@
+package ical
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type Method string
+
+const (
+	MethodRequest Method = "REQUEST"
+	MethodCancel  Method = "CANCEL"
+
+	prodID         = "-//Touristan//Booking//EN"
+	dateTimeLayout = "20060102T150405Z"
+	dateLayout     = "20060102"
+	maxLineOctets  = 75
+)
+
+// Event is a single VEVENT, Start and End are left out when zero
+type Event struct {
+	UID         string
+	Sequence    int64
+	Summary     string
+	Description string
+	Location    string
+	Latitude    float64
+	Longitude   float64
+	Organizer   string
+	Attendee    string
+	Start       time.Time
+	End         time.Time
+	AllDay      bool
+}
+
+// Build renders a VCALENDAR with one VEVENT for the given method
+func Build(method Method, e Event) []byte {
+	var b bytes.Buffer
+
+	writeLine(&b, "BEGIN:VCALENDAR")
+	writeLine(&b, "PRODID:"+prodID)
+	writeLine(&b, "VERSION:2.0")
+	writeLine(&b, "CALSCALE:GREGORIAN")
+	writeLine(&b, "METHOD:"+string(method))
+	writeLine(&b, "BEGIN:VEVENT")
+	writeLine(&b, "UID:"+e.UID)
+	writeLine(&b, fmt.Sprintf("SEQUENCE:%d", e.Sequence))
+	writeLine(&b, "DTSTAMP:"+time.Now().UTC().Format(dateTimeLayout))
+
+	if !e.Start.IsZero() {
+		writeLine(&b, formatTime("DTSTART", e.Start, e.AllDay))
+	}
+	if !e.End.IsZero() {
+		end := e.End
+		// DTEND of an all-day event is exclusive
+		if e.AllDay {
+			end = end.AddDate(0, 0, 1)
+		}
+		writeLine(&b, formatTime("DTEND", end, e.AllDay))
+	}
+
+	if e.Summary != "" {
+		writeLine(&b, "SUMMARY:"+escape(e.Summary))
+	}
+	if e.Description != "" {
+		writeLine(&b, "DESCRIPTION:"+escape(e.Description))
+	}
+	if e.Location != "" {
+		writeLine(&b, "LOCATION:"+escape(e.Location))
+	}
+	if e.Latitude != 0 || e.Longitude != 0 {
+		writeLine(&b, fmt.Sprintf("GEO:%f;%f", e.Latitude, e.Longitude))
+	}
+	if e.Organizer != "" {
+		writeLine(&b, "ORGANIZER:mailto:"+e.Organizer)
+	}
+	if e.Attendee != "" {
+		writeLine(&b, "ATTENDEE;ROLE=REQ-PARTICIPANT;RSVP=FALSE:mailto:"+e.Attendee)
+	}
+
+	if method == MethodCancel {
+		writeLine(&b, "STATUS:CANCELLED")
+	} else {
+		writeLine(&b, "STATUS:CONFIRMED")
+	}
+
+	writeLine(&b, "END:VEVENT")
+	writeLine(&b, "END:VCALENDAR")
+
+	return b.Bytes()
+}
+
+func formatTime(name string, t time.Time, allDay bool) string {
+	if allDay {
+		return name + ";VALUE=DATE:" + t.Format(dateLayout)
+	}
+	return name + ":" + t.UTC().Format(dateTimeLayout)
+}
+
+func escape(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
+
+// writeLine folds content lines longer than 75 octets as RFC 5545 requires
+func writeLine(b *bytes.Buffer, line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		// do not split a multi-byte rune
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		b.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		// the leading space of a continuation line counts too
+		limit = maxLineOctets - 1
+	}
+	b.WriteString(line + "\r\n")
+}
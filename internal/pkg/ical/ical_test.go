@@ -0,0 +1,101 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// unfold joins folded content lines back and splits the calendar into them
+func unfold(t *testing.T, b []byte) []string {
+	t.Helper()
+
+	s := string(b)
+	if !strings.HasSuffix(s, "\r\n") {
+		t.Fatalf("calendar does not end with CRLF: %q", s)
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(s, "\r\n"), "\r\n") {
+		if len(line) > maxLineOctets {
+			t.Errorf("line of %d octets: %q", len(line), line)
+		}
+	}
+	return strings.Split(strings.TrimSuffix(strings.ReplaceAll(s, "\r\n ", ""), "\r\n"), "\r\n")
+}
+
+func hasLine(lines []string, want string) bool {
+	for _, line := range lines {
+		if line == want {
+			return true
+		}
+	}
+	return false
+}
+
+func TestBuild(t *testing.T) {
+	zone := time.FixedZone("UZT", 5*60*60)
+	event := Event{
+		UID:         "7f0c7a5e@touristan",
+		Sequence:    3,
+		Summary:     "Booking: Plov; Centre, Tashkent",
+		Description: "Booking ID: 7f0c7a5e\nNumber of people: 2",
+		Location:    strings.Repeat("Амир Темур кўчаси, ", 6),
+		Latitude:    41.311081,
+		Longitude:   69.240562,
+		Organizer:   "booking@touristan.uz",
+		Attendee:    "guest@example.com",
+		Start:       time.Date(2026, 10, 19, 19, 0, 0, 0, zone),
+		End:         time.Date(2026, 10, 19, 21, 0, 0, 0, zone),
+	}
+
+	lines := unfold(t, Build(MethodRequest, event))
+	for _, want := range []string{
+		"BEGIN:VCALENDAR",
+		"METHOD:REQUEST",
+		"UID:7f0c7a5e@touristan",
+		"SEQUENCE:3",
+		"DTSTART:20261019T140000Z",
+		"DTEND:20261019T160000Z",
+		`SUMMARY:Booking: Plov\; Centre\, Tashkent`,
+		`DESCRIPTION:Booking ID: 7f0c7a5e\nNumber of people: 2`,
+		"LOCATION:" + strings.Repeat(`Амир Темур кўчаси\, `, 6),
+		"GEO:41.311081;69.240562",
+		"ORGANIZER:mailto:booking@touristan.uz",
+		"ATTENDEE;ROLE=REQ-PARTICIPANT;RSVP=FALSE:mailto:guest@example.com",
+		"STATUS:CONFIRMED",
+		"END:VCALENDAR",
+	} {
+		if !hasLine(lines, want) {
+			t.Errorf("missing line %q in\n%s", want, strings.Join(lines, "\n"))
+		}
+	}
+}
+
+func TestBuildCancel(t *testing.T) {
+	lines := unfold(t, Build(MethodCancel, Event{UID: "1@touristan"}))
+	for _, want := range []string{"METHOD:CANCEL", "STATUS:CANCELLED"} {
+		if !hasLine(lines, want) {
+			t.Errorf("missing line %q", want)
+		}
+	}
+	for _, line := range lines {
+		if strings.HasPrefix(line, "DTSTART") || strings.HasPrefix(line, "DTEND") || strings.HasPrefix(line, "GEO") {
+			t.Errorf("unexpected line %q for an event without dates or place", line)
+		}
+	}
+}
+
+func TestBuildAllDay(t *testing.T) {
+	day := time.Date(2026, 10, 19, 0, 0, 0, 0, time.FixedZone("UZT", 5*60*60))
+	lines := unfold(t, Build(MethodRequest, Event{
+		UID:    "1@touristan",
+		Start:  day,
+		End:    day.AddDate(0, 0, 2),
+		AllDay: true,
+	}))
+	// the end date of an all-day event is the day after the last one
+	for _, want := range []string{"DTSTART;VALUE=DATE:20261019", "DTEND;VALUE=DATE:20261022"} {
+		if !hasLine(lines, want) {
+			t.Errorf("missing line %q", want)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package locale
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	Uzbek   = "uz"
+	Russian = "ru"
+	English = "en"
+)
+
+// Supported are the languages establishment content is written in
+var Supported = []string{Uzbek, Russian, English}
+
+// IsSupported reports whether locale is one of Supported
+func IsSupported(locale string) bool {
+	for _, l := range Supported {
+		if l == locale {
+			return true
+		}
+	}
+	return false
+}
+
+// Negotiate picks the supported locale a request asks for: lang when it
+// names one, otherwise the best of the Accept-Language header, otherwise
+// def. Regions and scripts are ignored, so ru-RU and uz-Cyrl count as ru
+// and uz.
+func Negotiate(lang, acceptLanguage, def string) string {
+	if l := primary(lang); IsSupported(l) {
+		return l
+	}
+
+	type weighted struct {
+		locale string
+		q      float64
+	}
+	var asked []weighted
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(part, ";")
+		l := primary(fields[0])
+		if !IsSupported(l) {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+		if q > 0 {
+			asked = append(asked, weighted{locale: l, q: q})
+		}
+	}
+	if len(asked) == 0 {
+		return def
+	}
+
+	// the header order breaks ties
+	sort.SliceStable(asked, func(i, j int) bool { return asked[i].q > asked[j].q })
+	return asked[0].locale
+}
+
+// primary is the language part of a tag, lowercased
+func primary(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	return tag
+}
@@ -0,0 +1,68 @@
+package openinghours
+
+import (
+	"strings"
+	"time"
+
+	"Booking/api-service-booking/internal/pkg/i18n"
+)
+
+// Hours is a daily opening window in minutes after midnight. Close is
+// smaller than or equal to Open when the place closes after midnight,
+// for example "09:00-00:00" or "18:00-02:00".
+type Hours struct {
+	Open  int
+	Close int
+}
+
+// Parse reads the "HH:MM-HH:MM" format used in Restaurant.OpeningHours
+func Parse(value string) (Hours, error) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) != 2 {
+		return Hours{}, i18n.NewError("invalid_opening_hours", value)
+	}
+
+	open, err := parseClock(parts[0])
+	if err != nil {
+		return Hours{}, err
+	}
+	closeAt, err := parseClock(parts[1])
+	if err != nil {
+		return Hours{}, err
+	}
+
+	return Hours{Open: open, Close: closeAt}, nil
+}
+
+func parseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, i18n.NewError("invalid_time", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// Window returns when the place opens and closes for the day of date
+func (h Hours) Window(date time.Time) (time.Time, time.Time) {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+
+	open := day.Add(time.Duration(h.Open) * time.Minute)
+	closeAt := day.Add(time.Duration(h.Close) * time.Minute)
+	if h.Close <= h.Open {
+		closeAt = closeAt.AddDate(0, 0, 1)
+	}
+
+	return open, closeAt
+}
+
+// Covers reports whether the whole of [start, end) falls into one opening
+// window, the window of the previous day is checked for overnight hours
+func (h Hours) Covers(start, end time.Time) bool {
+	for _, day := range []time.Time{start, start.AddDate(0, 0, -1)} {
+		open, closeAt := h.Window(day)
+		if !start.Before(open) && !end.After(closeAt) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+package openinghours
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    Hours
+		wantErr bool
+	}{
+		{value: "09:00-18:00", want: Hours{Open: 540, Close: 1080}},
+		{value: " 18:00 - 02:00 ", want: Hours{Open: 1080, Close: 120}},
+		{value: "09:00-00:00", want: Hours{Open: 540, Close: 0}},
+		{value: "9-18", wantErr: true},
+		{value: "Mon-Fri 09:00-18:00", wantErr: true},
+		{value: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := Parse(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want error %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Parse(%q) = %+v, want %+v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHoursCovers(t *testing.T) {
+	day := time.Date(2026, 10, 19, 0, 0, 0, 0, time.FixedZone("UZT", 5*60*60))
+	at := func(days, hour, minute int) time.Time {
+		return day.AddDate(0, 0, days).Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+
+	tests := []struct {
+		name       string
+		hours      string
+		start, end time.Time
+		want       bool
+	}{
+		{"inside day hours", "09:00-18:00", at(0, 12, 0), at(0, 14, 0), true},
+		{"ends at closing", "09:00-18:00", at(0, 16, 0), at(0, 18, 0), true},
+		{"runs past closing", "09:00-18:00", at(0, 17, 0), at(0, 19, 0), false},
+		{"starts before opening", "09:00-18:00", at(0, 8, 30), at(0, 10, 30), false},
+		{"closing at midnight, ends at midnight", "09:00-00:00", at(0, 22, 0), at(1, 0, 0), true},
+		{"closing at midnight, runs past it", "09:00-00:00", at(0, 23, 0), at(1, 1, 0), false},
+		{"overnight, before midnight", "18:00-02:00", at(0, 20, 0), at(0, 22, 0), true},
+		{"overnight, across midnight", "18:00-02:00", at(0, 23, 0), at(1, 1, 0), true},
+		{"overnight, after midnight", "18:00-02:00", at(1, 0, 30), at(1, 2, 0), true},
+		{"overnight, past closing", "18:00-02:00", at(1, 1, 0), at(1, 3, 0), false},
+		{"overnight, closed in the morning", "18:00-02:00", at(0, 10, 0), at(0, 12, 0), false},
+		{"open all day, within the day", "00:00-00:00", at(0, 0, 0), at(0, 23, 0), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hours, err := Parse(tt.hours)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := hours.Covers(tt.start, tt.end); got != tt.want {
+				t.Errorf("%s covers [%s, %s) = %v, want %v", tt.hours, tt.start.Format("Jan 2 15:04"), tt.end.Format("Jan 2 15:04"), got, tt.want)
+			}
+		})
+	}
+}
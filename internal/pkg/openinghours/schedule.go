@@ -0,0 +1,279 @@
+package openinghours
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"Booking/api-service-booking/internal/pkg/booktime"
+	"Booking/api-service-booking/internal/pkg/i18n"
+)
+
+const (
+	// Closed is how a day without opening windows is written
+	Closed = "closed"
+
+	dateLayout = "2006-01-02"
+	// statusHorizon is how many days ahead Status looks for the next
+	// opening, a place closed for longer has no OpensAt
+	statusHorizon = 14
+)
+
+// dayNames are the keys of a written week, in time.Weekday order
+var dayNames = [7]string{"sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday"}
+
+// Schedule is a weekly opening schedule. Week holds the windows of each
+// day by time.Weekday, a day without windows is closed. Exceptions replace
+// the week on single dates such as holidays, keyed like "2006-01-02", an
+// exception without windows closes the whole date. Times are read in the
+// booking time zone.
+type Schedule struct {
+	Week       [7][]Hours
+	Exceptions map[string][]Hours
+}
+
+// Status is whether a schedule is open at some time and when that changes:
+// ClosesAt if it is open, OpensAt if not. Either is zero when the change is
+// not within statusHorizon days.
+type Status struct {
+	Open     bool
+	OpensAt  time.Time
+	ClosesAt time.Time
+}
+
+// Daily is the schedule of a place open the same hours every day, which is
+// what Restaurant.OpeningHours describes
+func Daily(h Hours) *Schedule {
+	var s Schedule
+	for day := range s.Week {
+		s.Week[day] = []Hours{h}
+	}
+	return &s
+}
+
+// ParseSchedule reads a week keyed by lowercase day name and exceptions
+// keyed by date, each day written as ParseDay reads it. A day missing from
+// week is closed.
+func ParseSchedule(week, exceptions map[string]string) (*Schedule, error) {
+	var s Schedule
+	for name, value := range week {
+		day := dayIndex(name)
+		if day < 0 {
+			return nil, i18n.NewError("unknown_day", name)
+		}
+		windows, err := ParseDay(value)
+		if err != nil {
+			return nil, i18n.NewError("labeled", name, err)
+		}
+		s.Week[day] = windows
+	}
+
+	if len(exceptions) > 0 {
+		s.Exceptions = make(map[string][]Hours, len(exceptions))
+	}
+	for date, value := range exceptions {
+		windows, err := ParseDay(value)
+		if err != nil {
+			return nil, i18n.NewError("labeled", date, err)
+		}
+		s.Exceptions[date] = windows
+	}
+
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// ParseDay reads the windows of one day, "09:00-14:00,15:00-22:00", or
+// "closed"
+func ParseDay(value string) ([]Hours, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || strings.EqualFold(value, Closed) {
+		return nil, nil
+	}
+
+	var windows []Hours
+	for _, part := range strings.Split(value, ",") {
+		h, err := Parse(part)
+		if err != nil {
+			return nil, err
+		}
+		windows = append(windows, h)
+	}
+	return windows, nil
+}
+
+// FormatDay writes windows the way ParseDay reads them
+func FormatDay(windows []Hours) string {
+	if len(windows) == 0 {
+		return Closed
+	}
+	parts := make([]string, 0, len(windows))
+	for _, h := range windows {
+		parts = append(parts, h.String())
+	}
+	return strings.Join(parts, ",")
+}
+
+func (h Hours) String() string {
+	return fmt.Sprintf("%02d:%02d-%02d:%02d", h.Open/60, h.Open%60, h.Close/60, h.Close%60)
+}
+
+// Format writes the schedule the way ParseSchedule reads it, every day of
+// the week included
+func (s *Schedule) Format() (week, exceptions map[string]string) {
+	week = make(map[string]string, len(dayNames))
+	for day, name := range dayNames {
+		week[name] = FormatDay(s.Week[day])
+	}
+	exceptions = make(map[string]string, len(s.Exceptions))
+	for date, windows := range s.Exceptions {
+		exceptions[date] = FormatDay(windows)
+	}
+	return week, exceptions
+}
+
+// Validate checks that exceptions are keyed by real dates and that the
+// windows of no day overlap
+func (s *Schedule) Validate() error {
+	for day, windows := range s.Week {
+		if err := validateDay(windows); err != nil {
+			return i18n.NewError("labeled", dayNames[day], err)
+		}
+	}
+	for date, windows := range s.Exceptions {
+		if _, err := time.Parse(dateLayout, date); err != nil {
+			return i18n.NewError("invalid_exception_date", date)
+		}
+		if err := validateDay(windows); err != nil {
+			return i18n.NewError("labeled", date, err)
+		}
+	}
+	return nil
+}
+
+// validateDay checks that windows of one day do not overlap, an overnight
+// window running on past midnight
+func validateDay(windows []Hours) error {
+	spans := make([][2]int, 0, len(windows))
+	for _, h := range windows {
+		end := h.Close
+		if end <= h.Open {
+			end += 24 * 60
+		}
+		spans = append(spans, [2]int{h.Open, end})
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i][0] < spans[j][0] })
+
+	for i := 1; i < len(spans); i++ {
+		if spans[i][0] < spans[i-1][1] {
+			return i18n.NewError("opening_windows_overlap")
+		}
+	}
+	return nil
+}
+
+// On returns the windows of the day of date, its exception if it has one
+func (s *Schedule) On(date time.Time) []Hours {
+	if windows, ok := s.Exceptions[date.Format(dateLayout)]; ok {
+		return windows
+	}
+	return s.Week[date.Weekday()]
+}
+
+// Windows returns when the place opens and closes in the windows of the
+// day of date, earliest first. An overnight window ends the next day.
+func (s *Schedule) Windows(date time.Time) [][2]time.Time {
+	date = date.In(booktime.Location())
+
+	var windows [][2]time.Time
+	for _, h := range s.On(date) {
+		open, closeAt := h.Window(date)
+		windows = append(windows, [2]time.Time{open, closeAt})
+	}
+	sort.Slice(windows, func(i, j int) bool { return windows[i][0].Before(windows[j][0]) })
+	return windows
+}
+
+// Covers reports whether the whole of [start, end) falls into one window,
+// the windows of the previous day are checked for overnight hours
+func (s *Schedule) Covers(start, end time.Time) bool {
+	for _, day := range []time.Time{start, start.AddDate(0, 0, -1)} {
+		for _, window := range s.Windows(day) {
+			if !start.Before(window[0]) && !end.After(window[1]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// OpenAt reports whether at falls into a window of its day, or into an
+// overnight window of the day before
+func (s *Schedule) OpenAt(at time.Time) bool {
+	at = at.In(booktime.Location())
+	for _, day := range []time.Time{at, at.AddDate(0, 0, -1)} {
+		for _, h := range s.On(day) {
+			open, closeAt := h.Window(day)
+			if !at.Before(open) && at.Before(closeAt) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Status tells whether the place is open at at and when it next closes or
+// opens. Windows that touch, such as one to midnight and one from it, are
+// taken as one.
+func (s *Schedule) Status(at time.Time) Status {
+	at = at.In(booktime.Location())
+
+	var spans [][2]time.Time
+	for i := -1; i <= statusHorizon; i++ {
+		day := at.AddDate(0, 0, i)
+		for _, h := range s.On(day) {
+			open, closeAt := h.Window(day)
+			spans = append(spans, [2]time.Time{open, closeAt})
+		}
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i][0].Before(spans[j][0]) })
+
+	merged := spans[:0]
+	for _, span := range spans {
+		if n := len(merged); n > 0 && !span[0].After(merged[n-1][1]) {
+			if span[1].After(merged[n-1][1]) {
+				merged[n-1][1] = span[1]
+			}
+			continue
+		}
+		merged = append(merged, span)
+	}
+
+	for i, span := range merged {
+		if at.Before(span[0]) {
+			return Status{OpensAt: span[0]}
+		}
+		if at.Before(span[1]) {
+			status := Status{Open: true}
+			// the last span may go on past the horizon
+			if i < len(merged)-1 || span[1].Before(at.AddDate(0, 0, statusHorizon)) {
+				status.ClosesAt = span[1]
+			}
+			return status
+		}
+	}
+	return Status{}
+}
+
+func dayIndex(name string) int {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for day, dayName := range dayNames {
+		if dayName == name {
+			return day
+		}
+	}
+	return -1
+}
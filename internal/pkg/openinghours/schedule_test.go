@@ -0,0 +1,81 @@
+package openinghours
+
+import (
+	"testing"
+	"time"
+
+	"Booking/api-service-booking/internal/pkg/booktime"
+)
+
+// testSchedule is open twice a day, the evening running past midnight,
+// closed on the holiday of Tuesday 2026-10-20 and open only at midday on
+// 2026-10-21
+func testSchedule(t *testing.T) *Schedule {
+	t.Helper()
+
+	week := make(map[string]string, len(dayNames))
+	for _, name := range dayNames {
+		week[name] = "17:00-02:00,09:00-14:00"
+	}
+	s, err := ParseSchedule(week, map[string]string{
+		"2026-10-20": Closed,
+		"2026-10-21": "12:00-16:00",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestScheduleWindows(t *testing.T) {
+	s := testSchedule(t)
+	monday := time.Date(2026, 10, 19, 0, 0, 0, 0, booktime.Location())
+
+	windows := s.Windows(monday.Add(15 * time.Hour))
+	if len(windows) != 2 {
+		t.Fatalf("Monday has %d windows, want 2", len(windows))
+	}
+	wantOpen := []time.Time{monday.Add(9 * time.Hour), monday.Add(17 * time.Hour)}
+	wantClose := []time.Time{monday.Add(14 * time.Hour), monday.Add(26 * time.Hour)}
+	for i, window := range windows {
+		if !window[0].Equal(wantOpen[i]) || !window[1].Equal(wantClose[i]) {
+			t.Errorf("window %d = [%s, %s), want [%s, %s)", i, window[0], window[1], wantOpen[i], wantClose[i])
+		}
+	}
+
+	if windows := s.Windows(monday.AddDate(0, 0, 1)); len(windows) != 0 {
+		t.Errorf("holiday has %d windows, want none", len(windows))
+	}
+}
+
+func TestScheduleCovers(t *testing.T) {
+	s := testSchedule(t)
+	monday := time.Date(2026, 10, 19, 0, 0, 0, 0, booktime.Location())
+	at := func(days, hour, minute int) time.Time {
+		return monday.AddDate(0, 0, days).Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+
+	tests := []struct {
+		name       string
+		start, end time.Time
+		want       bool
+	}{
+		{"morning window", at(0, 10, 0), at(0, 12, 0), true},
+		{"across the break", at(0, 13, 0), at(0, 15, 0), false},
+		{"evening into the holiday", at(0, 23, 0), at(1, 1, 0), true},
+		{"after midnight on the holiday", at(1, 0, 30), at(1, 1, 30), true},
+		{"holiday morning", at(1, 10, 0), at(1, 12, 0), false},
+		{"holiday evening", at(1, 19, 0), at(1, 21, 0), false},
+		{"before the exception window", at(2, 10, 0), at(2, 12, 0), false},
+		{"inside the exception window", at(2, 13, 0), at(2, 15, 0), true},
+		{"back to the week", at(3, 10, 0), at(3, 12, 0), true},
+		{"other time zone", at(0, 10, 0).UTC(), at(0, 12, 0).UTC(), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.Covers(tt.start, tt.end); got != tt.want {
+				t.Errorf("covers [%s, %s) = %v, want %v", tt.start.Format("Jan 2 15:04"), tt.end.Format("Jan 2 15:04"), got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSquirrelExpr(t *testing.T) {
+	tests := []struct {
+		name     string
+		sql      string
+		args     []interface{}
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{
+			name:     "no args",
+			sql:      "deleted_at IS NULL",
+			wantSQL:  "SELECT id FROM t WHERE deleted_at IS NULL",
+			wantArgs: nil,
+		},
+		{
+			name:     "one arg",
+			sql:      "UPPER(code) = ?",
+			args:     []interface{}{"ABC"},
+			wantSQL:  "SELECT id FROM t WHERE UPPER(code) = $1",
+			wantArgs: []interface{}{"ABC"},
+		},
+		{
+			name:     "row comparison",
+			sql:      "(price, id) > (?::bigint, ?::uuid)",
+			args:     []interface{}{"100", "7f0c7a5e-4a8e-4f43-9d4c-1f1b2c3d4e5f"},
+			wantSQL:  "SELECT id FROM t WHERE (price, id) > ($1::bigint, $2::uuid)",
+			wantArgs: []interface{}{"100", "7f0c7a5e-4a8e-4f43-9d4c-1f1b2c3d4e5f"},
+		},
+	}
+
+	s := NewSquirrel()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := s.Builder.Select("id").From("t").Where(s.Expr(tt.sql, tt.args...)).ToSql()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("sql = %q, want %q", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("args = %#v, want %#v", args, tt.wantArgs)
+			}
+		})
+	}
+}
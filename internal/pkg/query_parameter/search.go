@@ -0,0 +1,115 @@
+package query_parameter
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	"Booking/api-service-booking/internal/pkg/i18n"
+)
+
+const (
+	sortKey   = "sort"
+	cursorKey = "cursor"
+)
+
+// Sort is the ordering of a list, "-created_at" in the query sorts
+// created_at descending
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+// Search is a list request read against a Schema
+type Search struct {
+	Limit   uint64
+	Page    uint64
+	Cursor  string
+	Sort    Sort
+	Filters map[string]string
+}
+
+// Schema lists the filters and sort fields a list endpoint accepts
+type Schema struct {
+	Filters     []string
+	Sortable    []string
+	DefaultSort string
+	MaxLimit    uint64
+}
+
+// Parse reads qp against the schema, unknown filters and sort fields are
+// errors so a typo does not silently return everything
+func (s Schema) Parse(qp QueryParameter) (*Search, error) {
+	search := Search{
+		Limit:   qp.GetLimit(),
+		Page:    qp.GetPage(),
+		Filters: make(map[string]string),
+	}
+	if search.Limit == 0 || (s.MaxLimit > 0 && search.Limit > s.MaxLimit) {
+		return nil, i18n.NewError("field_between", "limit", 1, s.MaxLimit)
+	}
+	if search.Page == 0 {
+		return nil, i18n.NewError("field_at_least", "page", 1)
+	}
+
+	sort := s.DefaultSort
+	for key, value := range qp.GetParameters() {
+		switch {
+		case key == sortKey:
+			sort = value
+		case key == cursorKey:
+			search.Cursor = value
+		case contains(s.Filters, key):
+			search.Filters[key] = strings.TrimSpace(value)
+		default:
+			return nil, i18n.NewError("unknown_filter", key)
+		}
+	}
+
+	search.Sort = Sort{Field: strings.TrimPrefix(sort, "-"), Desc: strings.HasPrefix(sort, "-")}
+	if !contains(s.Sortable, search.Sort.Field) {
+		return nil, i18n.NewError("cannot_sort_by", search.Sort.Field)
+	}
+
+	return &search, nil
+}
+
+// List splits a comma separated filter, empty items are dropped
+func (s *Search) List(key string) []string {
+	var values []string
+	for _, value := range strings.Split(s.Filters[key], ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// EncodeCursor packs the values a page ended on into an opaque cursor
+func EncodeCursor(values ...string) string {
+	body, _ := json.Marshal(values)
+	return base64.RawURLEncoding.EncodeToString(body)
+}
+
+// DecodeCursor unpacks a cursor made by EncodeCursor holding size values
+func DecodeCursor(cursor string, size int) ([]string, error) {
+	body, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, i18n.NewError("invalid_cursor")
+	}
+
+	var values []string
+	if err = json.Unmarshal(body, &values); err != nil || len(values) != size {
+		return nil, i18n.NewError("invalid_cursor")
+	}
+	return values, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+package query_parameter
+
+import (
+	"reflect"
+	"testing"
+)
+
+var testSchema = Schema{
+	Filters:     []string{"state", "hra_id"},
+	Sortable:    []string{"created_at", "will_arrive"},
+	DefaultSort: "-created_at",
+	MaxLimit:    100,
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	values := []string{"2026-10-19T14:30:00Z", "7f0c7a5e-4a8e-4f43-9d4c-1f1b2c3d4e5f"}
+	got, err := DecodeCursor(EncodeCursor(values...), len(values))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, values) {
+		t.Errorf("DecodeCursor = %v, want %v", got, values)
+	}
+
+	for _, cursor := range []string{"", "not a cursor", EncodeCursor("only one")} {
+		if _, err := DecodeCursor(cursor, 2); err == nil {
+			t.Errorf("DecodeCursor(%q) did not fail", cursor)
+		}
+	}
+}
+
+func TestSchemaParse(t *testing.T) {
+	cursor := EncodeCursor("2026-10-19T14:30:00Z", "7f0c7a5e-4a8e-4f43-9d4c-1f1b2c3d4e5f")
+	search, err := testSchema.Parse(New(map[string][]string{
+		"limit":  {"20"},
+		"sort":   {"will_arrive"},
+		"state":  {" confirmed,canceled "},
+		"cursor": {cursor},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &Search{
+		Limit:   20,
+		Page:    1,
+		Cursor:  cursor,
+		Sort:    Sort{Field: "will_arrive"},
+		Filters: map[string]string{"state": "confirmed,canceled"},
+	}
+	if !reflect.DeepEqual(search, want) {
+		t.Errorf("Parse = %+v, want %+v", search, want)
+	}
+	if got := search.List("state"); !reflect.DeepEqual(got, []string{"confirmed", "canceled"}) {
+		t.Errorf("List(state) = %v", got)
+	}
+
+	search, err = testSchema.Parse(New(map[string][]string{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if search.Sort != (Sort{Field: "created_at", Desc: true}) {
+		t.Errorf("default sort = %+v", search.Sort)
+	}
+}
+
+func TestSchemaParseErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		query map[string][]string
+	}{
+		{"unknown filter", map[string][]string{"status": {"confirmed"}}},
+		{"unknown sort", map[string][]string{"sort": {"-price"}}},
+		{"limit over max", map[string][]string{"limit": {"500"}}},
+		{"page zero", map[string][]string{"page": {"0"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := testSchema.Parse(New(tt.query)); err == nil {
+				t.Error("Parse did not fail")
+			}
+		})
+	}
+}
@@ -2,11 +2,29 @@ package sendcode
 
 import (
 	"bytes"
+	"encoding/base64"
 	"fmt"
 	"html/template"
+	"mime/multipart"
 	"net/smtp"
+	"net/textproto"
 )
 
+const (
+	// sender data
+	from     = "touristanbookingsystem@gmail.com"
+	password = "wjct gbxs flag pfol"
+
+	// smtp server configuration.
+	smtpHost = "smtp.gmail.com"
+	smtpPort = "587"
+)
+
+// Sender returns the address mails are sent from
+func Sender() string {
+	return from
+}
+
 func SendCode(email string, code string) {
 	// sender data
 	from := "touristanbookingsystem@gmail.com"
@@ -48,4 +66,92 @@ func SendCode(email string, code string) {
 
 	return
   }
-  
\ No newline at end of file
+
+// BookingMail is the data rendered into booking_template.html
+type BookingMail struct {
+	Title      string
+	FullName   string
+	Place      string
+	Address    string
+	WillArrive string
+	WillLeave  string
+	People     int64
+	BookingId  string
+	Canceled   bool
+}
+
+// SendBooking sends the booking template with an iCalendar invite attached,
+// method must match the METHOD of the calendar (REQUEST or CANCEL). A nil
+// calendar sends the mail without an invite.
+func SendBooking(email string, data BookingMail, method string, calendar []byte) error {
+	t, err := template.ParseFiles("booking_template.html")
+	if err != nil {
+		return err
+	}
+
+	var html bytes.Buffer
+	if err := t.Execute(&html, data); err != nil {
+		return err
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	fmt.Fprintf(&body, "From: Touristan <%s>\r\n", from)
+	fmt.Fprintf(&body, "To: %s\r\n", email)
+	fmt.Fprintf(&body, "Subject: %s\r\n", data.Title)
+	fmt.Fprintf(&body, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&body, "Content-Type: multipart/mixed; boundary=%q\r\n\r\n", writer.Boundary())
+
+	htmlPart, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {"text/html; charset=\"UTF-8\""},
+	})
+	if err != nil {
+		return err
+	}
+	htmlPart.Write(html.Bytes())
+
+	if calendar != nil {
+		// inline part lets mail clients show accept / update buttons
+		calendarPart, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {fmt.Sprintf("text/calendar; charset=\"UTF-8\"; method=%s", method)},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		if err != nil {
+			return err
+		}
+		calendarPart.Write([]byte(base64Lines(calendar)))
+
+		attachment, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {"application/ics; name=\"booking.ics\""},
+			"Content-Disposition":       {"attachment; filename=\"booking.ics\""},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		if err != nil {
+			return err
+		}
+		attachment.Write([]byte(base64Lines(calendar)))
+	}
+
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	auth := smtp.PlainAuth("", from, password, smtpHost)
+
+	return smtp.SendMail(smtpHost+":"+smtpPort, auth, from, []string{email}, body.Bytes())
+}
+
+// base64Lines encodes data wrapped at 76 characters as MIME expects
+func base64Lines(data []byte) string {
+	encoded := base64.StdEncoding.EncodeToString(data)
+
+	var b bytes.Buffer
+	for len(encoded) > 76 {
+		b.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	b.WriteString(encoded)
+
+	return b.String()
+}
@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+
+	"Booking/api-service-booking/internal/pkg/config"
+)
+
+// partSize is how much of a file of unknown size is held in memory before
+// it is sent as one part of the upload
+const partSize = 16 << 20
+
+// Storage keeps files in the media storage
+type Storage struct {
+	Client   *minio.Client
+	location string
+}
+
+func New(cfg *config.Config) (*Storage, error) {
+	client, err := minio.New(cfg.Minio.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.Minio.AccessKey, cfg.Minio.SecretKey, ""),
+		Secure: false,
+		Region: cfg.Minio.Location,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Storage{
+		Client:   client,
+		location: cfg.Minio.Location,
+	}, nil
+}
+
+// Put streams file into object of bucket, making the bucket first if it is
+// not there. The size does not have to be known up front.
+func (s *Storage) Put(ctx context.Context, bucket, object string, file io.Reader, contentType string) error {
+	exists, err := s.Client.BucketExists(ctx, bucket)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		err = s.Client.MakeBucket(ctx, bucket, minio.MakeBucketOptions{Region: s.location})
+		if err != nil && minio.ToErrorResponse(err).Code != "BucketAlreadyOwnedByYou" {
+			return err
+		}
+	}
+
+	_, err = s.Client.PutObject(ctx, bucket, object, file, -1, minio.PutObjectOptions{
+		ContentType: contentType,
+		PartSize:    partSize,
+	})
+	return err
+}
+
+// Link is a download link to an object of a private bucket that works for
+// ttl
+func (s *Storage) Link(ctx context.Context, bucket, object string, ttl time.Duration) (string, error) {
+	link, err := s.Client.PresignedGetObject(ctx, bucket, object, ttl, url.Values{})
+	if err != nil {
+		return "", err
+	}
+	return link.String(), nil
+}
@@ -0,0 +1,62 @@
+package translit
+
+import (
+	"strings"
+	"unicode"
+)
+
+// cyrillic spells Russian and Uzbek Cyrillic letters in Latin
+var cyrillic = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo",
+	'ж': "j", 'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+	'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+	'ф': "f", 'х': "x", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "sh", 'ъ': "",
+	'ы': "i", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+	'ў': "o", 'қ': "q", 'ғ': "g", 'ҳ': "h",
+}
+
+// folds bring the Uzbek Latin, Russian and English spellings of the same
+// sound together, Toshkent and Ташкент both end up as tashkent
+var folds = strings.NewReplacer(
+	"kh", "h",
+	"x", "h",
+	"q", "k",
+	"w", "v",
+	"o", "a",
+)
+
+// Fold turns text into the form search compares: lower case Latin without
+// apostrophes and punctuation, with spelling variants folded together.
+// Only keys are folded, what is shown to users stays as it was written.
+func Fold(text string) string {
+	var b strings.Builder
+	space := true
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case isApostrophe(r):
+			// o‘ and g‘ are single letters in Uzbek Latin, ʻ is checked
+			// before letters as unicode counts it as one
+		case cyrillic[r] != "" || r == 'ъ' || r == 'ь':
+			b.WriteString(cyrillic[r])
+			space = false
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+			space = false
+		default:
+			if !space {
+				b.WriteByte(' ')
+				space = true
+			}
+		}
+	}
+
+	return folds.Replace(strings.TrimSpace(b.String()))
+}
+
+func isApostrophe(r rune) bool {
+	switch r {
+	case '\'', '`', '‘', '’', 'ʻ', 'ʼ':
+		return true
+	}
+	return false
+}
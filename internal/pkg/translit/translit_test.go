@@ -0,0 +1,29 @@
+package translit
+
+import "testing"
+
+func TestFold(t *testing.T) {
+	tests := []struct {
+		name     string
+		variants []string
+		want     string
+	}{
+		{"Registan square", []string{"Registon", "Регистан", "Registan", "REGISTAN"}, "registan"},
+		{"Tashkent", []string{"Toshkent", "Ташкент", "Tashkent"}, "tashkent"},
+		{"Samarkand", []string{"Samarqand", "Самарканд", "Samarkand"}, "samarkand"},
+		{"Khiva", []string{"Xiva", "Хива", "Khiva"}, "hiva"},
+		{"Uzbek apostrophes", []string{"O'zbekiston", "Oʻzbekiston", "O‘zbekiston", "Ўзбекистон"}, "azbekistan"},
+		{"punctuation and spaces", []string{"  Chorsu -  bazaar!", "Чорсу базаар"}, "charsu bazaar"},
+		{"digits stay", []string{"Hotel 21", "hotel-21"}, "hatel 21"},
+		{"soft and hard signs", []string{"Подъезд", "Podezd"}, "padezd"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, variant := range tt.variants {
+				if got := Fold(variant); got != tt.want {
+					t.Errorf("Fold(%q) = %q, want %q", variant, got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -3,6 +3,8 @@ package utils
 import (
 	"strconv"
 	"strings"
+
+	"Booking/api-service-booking/internal/pkg/i18n"
 )
 
 type QueryParam struct {
@@ -13,7 +15,7 @@ type QueryParam struct {
 	Search   string
 }
 
-func ParseQueryParam(queryParams map[string][]string) (*QueryParam, []string) {
+func ParseQueryParam(queryParams map[string][]string) (*QueryParam, []error) {
 	params := QueryParam{
 		Filters:  make(map[string]string),
 		Limit:    10,
@@ -21,14 +23,14 @@ func ParseQueryParam(queryParams map[string][]string) (*QueryParam, []string) {
 		Ordering: []string{},
 		Search:   "",
 	}
-	var errStr []string
+	var errs []error
 	var err error
 
 	for key, value := range queryParams {
 		if key == "page" {
 			params.Page, err = strconv.ParseUint(value[0], 10, 64)
 			if err != nil {
-				errStr = append(errStr, "invalid `page` param")
+				errs = append(errs, i18n.NewError("field_invalid_param", "page"))
 			}
 			continue
 		}
@@ -36,7 +38,7 @@ func ParseQueryParam(queryParams map[string][]string) (*QueryParam, []string) {
 		if key == "limit" {
 			params.Limit, err = strconv.ParseUint(value[0], 10, 64)
 			if err != nil {
-				errStr = append(errStr, "invalid `limit` param")
+				errs = append(errs, i18n.NewError("field_invalid_param", "limit"))
 			}
 			continue
 		}
@@ -51,5 +53,5 @@ func ParseQueryParam(queryParams map[string][]string) (*QueryParam, []string) {
 		}
 		params.Filters[key] = value[0]
 	}
-	return &params, errStr
+	return &params, errs
 }
@@ -0,0 +1,58 @@
+package validation
+
+import (
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+
+	"Booking/api-service-booking/internal/pkg/locale"
+)
+
+// validator has no Uzbek messages of its own, so these cover the tags the
+// models use. Any other tag is told in English.
+var uzbekMessages = map[string]string{
+	"required": "{0} majburiy maydon",
+	"email":    "{0} to'g'ri email manzil bo'lishi kerak",
+	"url":      "{0} to'g'ri URL bo'lishi kerak",
+	"uuid":     "{0} to'g'ri UUID bo'lishi kerak",
+	"numeric":  "{0} son bo'lishi kerak",
+	"len":      "{0} uzunligi {1} bo'lishi kerak",
+	"min":      "{0} kamida {1} bo'lishi kerak",
+	"max":      "{0} ko'pi bilan {1} bo'lishi kerak",
+	"gt":       "{0} {1} dan katta bo'lishi kerak",
+	"gte":      "{0} kamida {1} bo'lishi kerak",
+	"lt":       "{0} {1} dan kichik bo'lishi kerak",
+	"lte":      "{0} ko'pi bilan {1} bo'lishi kerak",
+	"oneof":    "{0} quyidagilardan biri bo'lishi kerak: {1}",
+}
+
+var phoneUz = map[string]string{
+	locale.English: "{0} must be an Uzbek phone number like 998901234567",
+	locale.Russian: "{0} должен быть узбекским номером телефона вида 998901234567",
+	locale.Uzbek:   "{0} 998901234567 ko'rinishidagi telefon raqami bo'lishi kerak",
+}
+
+func registerUzbek(v *validator.Validate, trans ut.Translator) error {
+	for tag, text := range uzbekMessages {
+		if err := register(v, trans, tag, text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// register tells errors of tag in the language of trans by text, where {0}
+// is the field and {1} the parameter of the tag
+func register(v *validator.Validate, trans ut.Translator, tag, text string) error {
+	return v.RegisterTranslation(tag, trans,
+		func(ut ut.Translator) error {
+			return ut.Add(tag, text, true)
+		},
+		func(ut ut.Translator, fe validator.FieldError) string {
+			message, err := ut.T(tag, fe.Field(), fe.Param())
+			if err != nil {
+				return fe.Error()
+			}
+			return message
+		},
+	)
+}
@@ -5,33 +5,63 @@ import (
 	"reflect"
 	"regexp"
 	"strings"
+	"sync"
 	"unicode"
 
 	english "github.com/go-playground/locales/en"
+	russian "github.com/go-playground/locales/ru"
+	uzbek "github.com/go-playground/locales/uz"
 	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
 	validatorEn "github.com/go-playground/validator/v10/translations/en"
+	validatorRu "github.com/go-playground/validator/v10/translations/ru"
 
 	errorpkg "Booking/api-service-booking/internal/errors"
+	"Booking/api-service-booking/internal/pkg/locale"
 )
 
-func Validator(s interface{}) error {
-	var (
-		eng      = english.New()
-		uni      = ut.New(eng, eng)
-		validate = validator.New()
-	)
+var (
+	mu       sync.Mutex
+	validate *validator.Validate
+	uni      *ut.UniversalTranslator
+)
 
-	trans, found := uni.GetTranslator("en")
-	if !found {
-		return errors.New("Validator translator not found")
+// Use makes Validator check with v, the validator gin binds requests with,
+// so the field errors of both are told the same way
+func Use(v *validator.Validate) error {
+	translators, err := setup(v)
+	if err != nil {
+		return err
 	}
 
-	if err := validatorEn.RegisterDefaultTranslations(validate, trans); err != nil {
-		return err
+	mu.Lock()
+	defer mu.Unlock()
+	validate, uni = v, translators
+	return nil
+}
+
+func current() (*validator.Validate, *ut.UniversalTranslator, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if validate == nil {
+		v := validator.New()
+		translators, err := setup(v)
+		if err != nil {
+			return nil, nil, err
+		}
+		validate, uni = v, translators
 	}
+	return validate, uni, nil
+}
+
+// setup names fields by their json tags, adds phone_uz and registers the
+// messages of every supported language on v
+func setup(v *validator.Validate) (*ut.UniversalTranslator, error) {
+	eng := english.New()
+	translators := ut.New(eng, eng, russian.New(), uzbek.New())
 
-	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
+	v.RegisterTagNameFunc(func(fld reflect.StructField) string {
 		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
 		if name == "-" {
 			return ""
@@ -39,25 +69,78 @@ func Validator(s interface{}) error {
 		return name
 	})
 
-	customValidation := NewCustomValidation(validate)
-	if err := validate.RegisterValidation("phone_uz", customValidation.PhoneUz); err != nil {
-		return nil
+	customValidation := NewCustomValidation(v)
+	if err := v.RegisterValidation("phone_uz", customValidation.PhoneUz); err != nil {
+		return nil, err
 	}
 
-	err := validate.Struct(s)
+	trans, _ := translators.GetTranslator(locale.English)
+	if err := validatorEn.RegisterDefaultTranslations(v, trans); err != nil {
+		return nil, err
+	}
+	trans, _ = translators.GetTranslator(locale.Russian)
+	if err := validatorRu.RegisterDefaultTranslations(v, trans); err != nil {
+		return nil, err
+	}
+	trans, _ = translators.GetTranslator(locale.Uzbek)
+	if err := registerUzbek(v, trans); err != nil {
+		return nil, err
+	}
+
+	for lang, text := range phoneUz {
+		trans, _ := translators.GetTranslator(lang)
+		if err := register(v, trans, "phone_uz", text); err != nil {
+			return nil, err
+		}
+	}
+	return translators, nil
+}
+
+func Validator(s interface{}, lang string) error {
+	validate, _, err := current()
+	if err != nil {
+		return err
+	}
+
+	err = validate.Struct(s)
 	if err == nil {
 		return nil
 	}
 
-	if errs, ok := err.(validator.ValidationErrors); ok {
+	if fields := Fields(err, lang); fields != nil {
 		errValidation := errorpkg.NewErrValidation()
 		errValidation.Err = err
-		for _, e := range errs {
-			errValidation.Errors[e.Field()] = strings.Replace(e.Translate(trans), e.Field(), "", 1)
-		}
+		errValidation.Errors = fields
 		return errValidation
 	}
-	return nil
+	return err
+}
+
+// Fields tells the field errors of err in lang, by field, and nil if err
+// did not come from validation. Messages without a translation to lang
+// are told in English.
+func Fields(err error, lang string) map[string]string {
+	var errs validator.ValidationErrors
+	if !errors.As(err, &errs) {
+		return nil
+	}
+
+	_, translators, setupErr := current()
+	if setupErr != nil {
+		return nil
+	}
+	trans, _ := translators.GetTranslator(lang)
+	fallback, _ := translators.GetTranslator(locale.English)
+
+	fields := make(map[string]string, len(errs))
+	for _, e := range errs {
+		message := e.Translate(trans)
+		if message == e.Error() {
+			message = e.Translate(fallback)
+		}
+		fields[e.Field()] = strings.TrimSpace(strings.Replace(message, e.Field(), "", 1))
+	}
+	return fields
 }
 
 type customValidation struct {
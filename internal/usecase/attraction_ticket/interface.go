@@ -0,0 +1,24 @@
+package attraction_ticket
+
+import (
+	"context"
+	"time"
+
+	"Booking/api-service-booking/internal/entity"
+)
+
+type AttractionTicket interface {
+	CreateType(ctx context.Context, m *entity.TicketType) error
+	ListTypes(ctx context.Context, attractionID string) ([]*entity.TicketType, error)
+	DeleteType(ctx context.Context, id string) error
+	SaveSettings(ctx context.Context, m *entity.EntrySettings) error
+	GetSettings(ctx context.Context, attractionID string) (*entity.EntrySettings, error)
+	// Slots lists the entry slots of date with the number of tickets left
+	Slots(ctx context.Context, attractionID string, date time.Time) ([]*entity.EntrySlot, error)
+	// Issue sells one ticket per person for the slot starting at entryAt,
+	// quantities maps a ticket kind to how many tickets of it are wanted.
+	// Tickets the booking already has are replaced.
+	Issue(ctx context.Context, bookingID, attractionID string, entryAt time.Time, quantities map[string]int) ([]*entity.Ticket, error)
+	ListByBooking(ctx context.Context, bookingID string) ([]*entity.Ticket, error)
+	Cancel(ctx context.Context, bookingID string) error
+}
@@ -0,0 +1,257 @@
+package attraction_ticket
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Booking/api-service-booking/internal/entity"
+	errorspkg "Booking/api-service-booking/internal/errors"
+	"Booking/api-service-booking/internal/infrastructure/repository/postgresql/repo"
+	"Booking/api-service-booking/internal/pkg/i18n"
+	"Booking/api-service-booking/internal/pkg/openinghours"
+)
+
+const (
+	// codeAlphabet leaves out characters that are easy to misread
+	codeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	codeLength   = 10
+	issueRetries = 3
+)
+
+type attractionTicketService struct {
+	ctxTimeout time.Duration
+	repo       repo.AttractionTicketRepo
+}
+
+func NewAttractionTicketService(ctxTimeout time.Duration, repo repo.AttractionTicketRepo) AttractionTicket {
+	return &attractionTicketService{
+		ctxTimeout: ctxTimeout,
+		repo:       repo,
+	}
+}
+
+func (r *attractionTicketService) beforeCreateType(m *entity.TicketType) {
+	m.ID = uuid.NewString()
+	m.CreatedAt = time.Now().UTC()
+	m.UpdatedAt = time.Now().UTC()
+}
+
+func (r *attractionTicketService) CreateType(ctx context.Context, m *entity.TicketType) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	if !isTicketKind(m.Kind) {
+		return errorspkg.NewErrBadRequest(i18n.NewError("unknown_ticket_kind", m.Kind))
+	}
+	if m.Price < 0 {
+		return errorspkg.NewErrBadRequest(i18n.NewError("field_not_negative", "price"))
+	}
+
+	r.beforeCreateType(m)
+	return r.repo.CreateType(ctx, m)
+}
+
+func (r *attractionTicketService) ListTypes(ctx context.Context, attractionID string) ([]*entity.TicketType, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.ListTypes(ctx, attractionID)
+}
+
+func (r *attractionTicketService) DeleteType(ctx context.Context, id string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.DeleteType(ctx, id, time.Now().UTC())
+}
+
+func (r *attractionTicketService) SaveSettings(ctx context.Context, m *entity.EntrySettings) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	if _, err := openinghours.Parse(m.OpeningHours); err != nil {
+		return errorspkg.NewErrBadRequest(err)
+	}
+	if m.SlotLength < time.Minute || m.Capacity < 1 {
+		return errorspkg.NewErrBadRequest(i18n.NewError("slot_not_positive"))
+	}
+
+	m.UpdatedAt = time.Now().UTC()
+	return r.repo.SaveSettings(ctx, m)
+}
+
+func (r *attractionTicketService) GetSettings(ctx context.Context, attractionID string) (*entity.EntrySettings, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.GetSettings(ctx, attractionID)
+}
+
+func (r *attractionTicketService) Slots(ctx context.Context, attractionID string, date time.Time) ([]*entity.EntrySlot, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	settings, err := r.repo.GetSettings(ctx, attractionID)
+	if err != nil {
+		return nil, err
+	}
+	hours, err := openinghours.Parse(settings.OpeningHours)
+	if err != nil {
+		return nil, err
+	}
+
+	open, closeAt := hours.Window(date)
+	sold, err := r.repo.CountByEntry(ctx, attractionID, open, closeAt)
+	if err != nil {
+		return nil, err
+	}
+
+	slots := []*entity.EntrySlot{}
+	for start := open; !start.Add(settings.SlotLength).After(closeAt); start = start.Add(settings.SlotLength) {
+		slots = append(slots, &entity.EntrySlot{
+			StartsAt: start,
+			EndsAt:   start.Add(settings.SlotLength),
+			Free:     settings.Capacity - sold[start.Unix()],
+		})
+	}
+
+	return slots, nil
+}
+
+func (r *attractionTicketService) Issue(ctx context.Context, bookingID, attractionID string, entryAt time.Time, quantities map[string]int) ([]*entity.Ticket, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	for kind := range quantities {
+		if !isTicketKind(kind) {
+			return nil, errorspkg.NewErrBadRequest(i18n.NewError("unknown_ticket_kind", kind))
+		}
+	}
+
+	settings, err := r.repo.GetSettings(ctx, attractionID)
+	if errors.Is(err, errorspkg.ErrorNotFound) {
+		return nil, errorspkg.NewErrBadRequest(i18n.NewError("timed_tickets_not_sold"))
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := r.checkEntry(settings, entryAt); err != nil {
+		return nil, err
+	}
+
+	types, err := r.repo.ListTypes(ctx, attractionID)
+	if err != nil {
+		return nil, err
+	}
+	byKind := make(map[string]*entity.TicketType, len(types))
+	for _, ticketType := range types {
+		byKind[ticketType.Kind] = ticketType
+	}
+
+	var tickets []*entity.Ticket
+	for _, kind := range entity.TicketKinds {
+		quantity := quantities[kind]
+		if quantity == 0 {
+			continue
+		}
+		ticketType, ok := byKind[kind]
+		if !ok || quantity < 0 {
+			return nil, errorspkg.NewErrBadRequest(i18n.NewError("ticket_kind_not_sold", kind))
+		}
+		for i := 0; i < quantity; i++ {
+			tickets = append(tickets, &entity.Ticket{
+				ID:           uuid.NewString(),
+				BookingID:    bookingID,
+				AttractionID: attractionID,
+				TicketTypeID: ticketType.ID,
+				Kind:         kind,
+				Price:        ticketType.Price,
+				EntryAt:      entryAt,
+				CreatedAt:    time.Now().UTC(),
+			})
+		}
+	}
+	if len(tickets) == 0 {
+		return nil, errorspkg.NewErrBadRequest(i18n.NewError("ticket_required"))
+	}
+
+	// a clash on the unique code only means bad luck, so draw new codes
+	for attempt := 0; ; attempt++ {
+		for _, ticket := range tickets {
+			if ticket.Code, err = newCode(); err != nil {
+				return nil, err
+			}
+		}
+		err = r.repo.Issue(ctx, tickets, settings.Capacity)
+		if !errors.Is(err, errorspkg.ErrorConflict) || attempt == issueRetries {
+			break
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return tickets, nil
+}
+
+// checkEntry makes sure entryAt is the start of one of the entry slots
+func (r *attractionTicketService) checkEntry(settings *entity.EntrySettings, entryAt time.Time) error {
+	hours, err := openinghours.Parse(settings.OpeningHours)
+	if err != nil {
+		return err
+	}
+
+	for _, day := range []time.Time{entryAt, entryAt.AddDate(0, 0, -1)} {
+		open, closeAt := hours.Window(day)
+		if entryAt.Before(open) || entryAt.Add(settings.SlotLength).After(closeAt) {
+			continue
+		}
+		if entryAt.Sub(open)%settings.SlotLength == 0 {
+			return nil
+		}
+		return errorspkg.NewErrBadRequest(i18n.NewError("entry_times", settings.SlotLength, open.Format("15:04")))
+	}
+
+	return errorspkg.NewErrBadRequest(i18n.NewError("attraction_closed_at", entryAt.Format("15:04")))
+}
+
+func (r *attractionTicketService) ListByBooking(ctx context.Context, bookingID string) ([]*entity.Ticket, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.ListByBooking(ctx, bookingID)
+}
+
+func (r *attractionTicketService) Cancel(ctx context.Context, bookingID string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.CancelByBooking(ctx, bookingID, time.Now().UTC())
+}
+
+func isTicketKind(kind string) bool {
+	for _, k := range entity.TicketKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func newCode() (string, error) {
+	code := make([]byte, codeLength)
+	max := big.NewInt(int64(len(codeAlphabet)))
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		code[i] = codeAlphabet[n.Int64()]
+	}
+	return string(code), nil
+}
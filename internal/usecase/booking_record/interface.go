@@ -0,0 +1,28 @@
+package booking_record
+
+import (
+	"context"
+	"time"
+
+	"Booking/api-service-booking/internal/entity"
+)
+
+type BookingRecord interface {
+	Get(ctx context.Context, id string) (*entity.BookingRecord, error)
+	Create(ctx context.Context, m *entity.BookingRecord) error
+	Update(ctx context.Context, m *entity.BookingRecord) error
+	ChangeState(ctx context.Context, id string, from []string, to string) (bool, error)
+	CountByUser(ctx context.Context, userID string, states []string) (int, error)
+	// LastBookedAt returns when each of userIDs last booked establishmentID
+	LastBookedAt(ctx context.Context, category, establishmentID string, userIDs []string) (map[string]time.Time, error)
+	// Dashboard builds the calendar of establishmentIDs for the days in
+	// [from, to), both midnights in the booking time zone
+	Dashboard(ctx context.Context, category string, establishmentIDs []string, from, to time.Time) (*entity.Dashboard, error)
+	// Search returns a page of bookings matching filter, cursor continues
+	// after the page and next is the cursor of the page after it, empty on
+	// the last page
+	Search(ctx context.Context, filter *entity.BookingSearch, cursor string) (records []*entity.BookingRecord, next string, err error)
+	// AddChange stores a history entry, entries without fields are skipped
+	AddChange(ctx context.Context, m *entity.BookingChange) error
+	History(ctx context.Context, bookingID string) ([]*entity.BookingChange, error)
+}
@@ -0,0 +1,270 @@
+package booking_record
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Booking/api-service-booking/internal/entity"
+	errorspkg "Booking/api-service-booking/internal/errors"
+	"Booking/api-service-booking/internal/infrastructure/repository/postgresql/repo"
+	"Booking/api-service-booking/internal/pkg/booktime"
+	"Booking/api-service-booking/internal/pkg/i18n"
+	"Booking/api-service-booking/internal/pkg/query_parameter"
+)
+
+type bookingRecordService struct {
+	ctxTimeout time.Duration
+	repo       repo.BookingRecordRepo
+}
+
+func NewBookingRecordService(ctxTimeout time.Duration, repo repo.BookingRecordRepo) BookingRecord {
+	return &bookingRecordService{
+		ctxTimeout: ctxTimeout,
+		repo:       repo,
+	}
+}
+
+// parseDates fills ArriveAt and LeaveAt, dates the guest sent in an
+// unknown format are kept as text only
+func (r *bookingRecordService) parseDates(m *entity.BookingRecord) {
+	m.ArriveAt, m.LeaveAt = nil, nil
+	if arrive, _, err := booktime.Parse(m.WillArrive); err == nil {
+		m.ArriveAt = &arrive
+	}
+	if leave, _, err := booktime.Parse(m.WillLeave); err == nil {
+		m.LeaveAt = &leave
+	}
+}
+
+func (r *bookingRecordService) beforeCreate(m *entity.BookingRecord) {
+	r.parseDates(m)
+	if m.State == "" {
+		m.State = entity.BookingStateConfirmed
+	}
+	// a booking copied over from the booking service keeps its time
+	if m.CreatedAt.IsZero() {
+		m.CreatedAt = time.Now().UTC()
+	}
+	m.UpdatedAt = time.Now().UTC()
+}
+
+func (r *bookingRecordService) beforeUpdate(m *entity.BookingRecord) {
+	r.parseDates(m)
+	m.UpdatedAt = time.Now().UTC()
+}
+
+func (r *bookingRecordService) Get(ctx context.Context, id string) (*entity.BookingRecord, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.Get(ctx, id)
+}
+
+func (r *bookingRecordService) Create(ctx context.Context, m *entity.BookingRecord) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	r.beforeCreate(m)
+	return r.repo.Create(ctx, m)
+}
+
+func (r *bookingRecordService) Update(ctx context.Context, m *entity.BookingRecord) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	r.beforeUpdate(m)
+	return r.repo.Update(ctx, m)
+}
+
+func (r *bookingRecordService) ChangeState(ctx context.Context, id string, from []string, to string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.ChangeState(ctx, id, from, to, time.Now().UTC())
+}
+
+func (r *bookingRecordService) CountByUser(ctx context.Context, userID string, states []string) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.CountByUser(ctx, userID, states)
+}
+
+func (r *bookingRecordService) LastBookedAt(ctx context.Context, category, establishmentID string, userIDs []string) (map[string]time.Time, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.LastBookedAt(ctx, category, establishmentID, userIDs)
+}
+
+const dateLayout = "2006-01-02"
+
+// midnight is the start of the day of t in the booking time zone
+func midnight(t time.Time) time.Time {
+	t = t.In(booktime.Location())
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func (r *bookingRecordService) Dashboard(ctx context.Context, category string, establishmentIDs []string, from, to time.Time) (*entity.Dashboard, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	today := midnight(time.Now())
+
+	// today is read along with the range so one query covers both
+	start, end := from, to
+	if today.Before(start) {
+		start = today
+	}
+	if today.AddDate(0, 0, 1).After(end) {
+		end = today.AddDate(0, 0, 1)
+	}
+
+	records, err := r.repo.ListByEstablishments(ctx, category, establishmentIDs, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	var dashboard entity.Dashboard
+	// days are keyed by date, time.Time keys would also compare locations
+	days := make(map[string]*entity.DashboardDay)
+	for date := midnight(from); date.Before(to); date = date.AddDate(0, 0, 1) {
+		day := entity.DashboardDay{Date: date}
+		dashboard.Days = append(dashboard.Days, &day)
+		days[date.Format(dateLayout)] = &day
+	}
+
+	for _, record := range records {
+		arriveDay, leaveDay := midnight(*record.ArriveAt), midnight(*record.ArriveAt)
+		if record.LeaveAt != nil && record.LeaveAt.After(*record.ArriveAt) {
+			leaveDay = midnight(*record.LeaveAt)
+		}
+
+		if days[arriveDay.Format(dateLayout)] != nil {
+			dashboard.Booked++
+		}
+		if record.State == entity.BookingStateCanceled {
+			if day := days[arriveDay.Format(dateLayout)]; day != nil {
+				dashboard.Canceled++
+				day.Canceled++
+			}
+			continue
+		}
+		if record.State == entity.BookingStateNoShow {
+			continue
+		}
+
+		if arriveDay.Equal(today) {
+			dashboard.ArrivalsToday++
+		}
+		if leaveDay.Equal(today) {
+			dashboard.DeparturesToday++
+		}
+		if day := days[arriveDay.Format(dateLayout)]; day != nil {
+			day.Arrivals++
+		}
+		if day := days[leaveDay.Format(dateLayout)]; day != nil {
+			day.Departures++
+		}
+
+		// a stay takes up the nights before the day it leaves, a visit
+		// within one day takes up that day
+		for date := arriveDay; date.Before(leaveDay) || date.Equal(arriveDay); date = date.AddDate(0, 0, 1) {
+			if day := days[date.Format(dateLayout)]; day != nil {
+				day.Bookings++
+				day.Guests += record.NumberOfPeople
+			}
+		}
+	}
+
+	return &dashboard, nil
+}
+
+// sortValue is the value of record a page sorted by field ends on
+func sortValue(record *entity.BookingRecord, field string) string {
+	switch field {
+	case "created_at":
+		return record.CreatedAt.Format(time.RFC3339Nano)
+	case "updated_at":
+		return record.UpdatedAt.Format(time.RFC3339Nano)
+	case "arrive_at":
+		if record.ArriveAt == nil {
+			return "-infinity"
+		}
+		return record.ArriveAt.Format(time.RFC3339Nano)
+	case "price":
+		return strconv.FormatInt(record.Price, 10)
+	case "number_of_people":
+		return strconv.FormatInt(record.NumberOfPeople, 10)
+	}
+	return ""
+}
+
+// validSortValue checks a cursor value before it is cast in the query
+func validSortValue(field, value string) bool {
+	switch field {
+	case "price", "number_of_people":
+		_, err := strconv.ParseInt(value, 10, 64)
+		return err == nil
+	case "arrive_at":
+		if value == "-infinity" {
+			return true
+		}
+	}
+	_, err := time.Parse(time.RFC3339Nano, value)
+	return err == nil
+}
+
+func (r *bookingRecordService) Search(ctx context.Context, filter *entity.BookingSearch, cursor string) ([]*entity.BookingRecord, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	if cursor != "" {
+		values, err := query_parameter.DecodeCursor(cursor, 2)
+		if err != nil {
+			return nil, "", errorspkg.NewErrBadRequest(err)
+		}
+		if _, err = uuid.Parse(values[1]); err != nil || !validSortValue(filter.SortBy, values[0]) {
+			return nil, "", errorspkg.NewErrBadRequest(i18n.NewError("invalid_cursor"))
+		}
+		filter.AfterValue, filter.AfterID = values[0], values[1]
+	}
+
+	// one more than asked for tells whether there is a next page
+	limit := filter.Limit
+	filter.Limit++
+	records, err := r.repo.Search(ctx, filter)
+	if err != nil {
+		return nil, "", err
+	}
+	if uint64(len(records)) <= limit {
+		return records, "", nil
+	}
+
+	records = records[:limit]
+	last := records[len(records)-1]
+	return records, query_parameter.EncodeCursor(sortValue(last, filter.SortBy), last.ID), nil
+}
+
+func (r *bookingRecordService) AddChange(ctx context.Context, m *entity.BookingChange) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	if len(m.Fields) == 0 {
+		return nil
+	}
+
+	m.ID = uuid.NewString()
+	m.CreatedAt = time.Now().UTC()
+	return r.repo.CreateChange(ctx, m)
+}
+
+func (r *bookingRecordService) History(ctx context.Context, bookingID string) ([]*entity.BookingChange, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.ListChanges(ctx, bookingID)
+}
@@ -0,0 +1,24 @@
+package establishment_import
+
+import (
+	"context"
+	"io"
+
+	"Booking/api-service-booking/internal/entity"
+)
+
+type EstablishmentImport interface {
+	// Create reads file in the format of m, validates its rows and saves m
+	// as a pending import of them
+	Create(ctx context.Context, m *entity.EstablishmentImport, file io.Reader) error
+	// Update saves the progress of an import
+	Update(ctx context.Context, m *entity.EstablishmentImport) error
+	Get(ctx context.Context, id string) (*entity.EstablishmentImport, error)
+	List(ctx context.Context, limit, offset uint64) ([]*entity.EstablishmentImport, uint64, error)
+	// Apply turns a finished dry run into a pending import of the same rows
+	Apply(ctx context.Context, id string) (*entity.EstablishmentImport, error)
+	// Ref returns what an external ref was imported as before
+	Ref(ctx context.Context, category, externalRef string) (*entity.ImportRef, error)
+	SaveRef(ctx context.Context, m *entity.ImportRef) error
+	DeleteRefs(ctx context.Context, category, establishmentID string) error
+}
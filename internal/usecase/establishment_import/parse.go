@@ -0,0 +1,301 @@
+package establishment_import
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"Booking/api-service-booking/internal/entity"
+	"Booking/api-service-booking/internal/pkg/i18n"
+	"Booking/api-service-booking/internal/pkg/openinghours"
+)
+
+const (
+	maxRefLength  = 100
+	maxNameLength = 255
+	// maxLineSize caps one line of an NDJSON file
+	maxLineSize = 1 << 20
+	// tagSeparator splits the tags of a row in a CSV cell
+	tagSeparator = ";"
+)
+
+// columns are what a row may have, opening_hours only for restaurants
+var columns = []string{
+	"external_ref",
+	"owner_id",
+	"name",
+	"description",
+	"rating",
+	"contact_number",
+	"licence_url",
+	"website_url",
+	"opening_hours",
+	"address",
+	"latitude",
+	"longitude",
+	"country",
+	"city",
+	"state_province",
+	"tags",
+}
+
+// aliases let files name the name column like the create request of the
+// category does
+var aliases = map[string]string{
+	"hotel_name":      "name",
+	"restaurant_name": "name",
+	"attraction_name": "name",
+}
+
+// column tells which column name stands for in category, empty if none
+func column(category, name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if alias, ok := aliases[name]; ok {
+		name = alias
+	}
+	if name == "opening_hours" && category != "restaurant" {
+		return ""
+	}
+	if !contains(columns, name) {
+		return ""
+	}
+	return name
+}
+
+// parseCSV reads a CSV file whose first line names the columns. Rows that
+// can not be read or do not pass validation come back as errors, a header
+// that can not be used fails the whole file.
+func parseCSV(file io.Reader, category, lang string) ([]*entity.ImportRow, []*entity.ImportRowError, error) {
+	reader := csv.NewReader(file)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, i18n.NewError("invalid_file")
+	}
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+
+	names := make([]string, len(header))
+	for i, name := range header {
+		if names[i] = column(category, name); names[i] == "" {
+			return nil, nil, i18n.NewError("unknown_column", name)
+		}
+	}
+	for _, required := range []string{"external_ref", "name"} {
+		if !contains(names, required) {
+			return nil, nil, i18n.NewError("missing_column", required)
+		}
+	}
+
+	var (
+		rows  []*entity.ImportRow
+		errs  []*entity.ImportRowError
+		lines = map[string]int{}
+	)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		line, _ := reader.FieldPos(0)
+		if err != nil {
+			if !errors.Is(err, csv.ErrFieldCount) {
+				return nil, nil, i18n.NewError("invalid_file")
+			}
+			errs = append(errs, rowError(line, "", i18n.NewError("invalid_row"), lang))
+			continue
+		}
+
+		values := make(map[string]string, len(record))
+		for i, value := range record {
+			values[names[i]] = value
+		}
+		row, err := parseRow(line, values, category, lines)
+		if err != nil {
+			errs = append(errs, rowError(line, values["external_ref"], err, lang))
+			continue
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, errs, nil
+}
+
+// parseNDJSON reads a file with an object per line, named like the CSV
+// columns. tags may be an array there.
+func parseNDJSON(file io.Reader, category, lang string) ([]*entity.ImportRow, []*entity.ImportRowError, error) {
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	var (
+		rows  []*entity.ImportRow
+		errs  []*entity.ImportRowError
+		lines = map[string]int{}
+	)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		values, err := objectValues(text, category)
+		if err != nil {
+			errs = append(errs, rowError(line, "", err, lang))
+			continue
+		}
+		row, err := parseRow(line, values, category, lines)
+		if err != nil {
+			errs = append(errs, rowError(line, values["external_ref"], err, lang))
+			continue
+		}
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, i18n.NewError("invalid_file")
+	}
+
+	return rows, errs, nil
+}
+
+// objectValues flattens one NDJSON line into column values
+func objectValues(text, category string) (map[string]string, error) {
+	var object map[string]interface{}
+	decoder := json.NewDecoder(strings.NewReader(text))
+	decoder.UseNumber()
+	if err := decoder.Decode(&object); err != nil {
+		return nil, i18n.NewError("invalid_row")
+	}
+
+	values := make(map[string]string, len(object))
+	for key, value := range object {
+		name := column(category, key)
+		if name == "" {
+			return nil, i18n.NewError("unknown_column", key)
+		}
+
+		switch value := value.(type) {
+		case nil:
+		case string:
+			values[name] = value
+		case json.Number:
+			values[name] = value.String()
+		case []interface{}:
+			if name != "tags" {
+				return nil, i18n.NewError("invalid_row")
+			}
+			tags := make([]string, 0, len(value))
+			for _, tag := range value {
+				slug, ok := tag.(string)
+				if !ok {
+					return nil, i18n.NewError("invalid_row")
+				}
+				tags = append(tags, slug)
+			}
+			values[name] = strings.Join(tags, tagSeparator)
+		default:
+			return nil, i18n.NewError("invalid_row")
+		}
+	}
+	return values, nil
+}
+
+// parseRow validates the values of a row. lines has the line of every
+// external ref seen so far in the file, a ref may only be there once.
+func parseRow(line int, values map[string]string, category string, lines map[string]int) (*entity.ImportRow, error) {
+	row := entity.ImportRow{
+		Line:          line,
+		ExternalRef:   strings.TrimSpace(values["external_ref"]),
+		OwnerID:       strings.TrimSpace(values["owner_id"]),
+		Name:          strings.TrimSpace(values["name"]),
+		Description:   strings.TrimSpace(values["description"]),
+		ContactNumber: strings.TrimSpace(values["contact_number"]),
+		LicenceUrl:    strings.TrimSpace(values["licence_url"]),
+		WebsiteUrl:    strings.TrimSpace(values["website_url"]),
+		OpeningHours:  strings.TrimSpace(values["opening_hours"]),
+		Address:       strings.TrimSpace(values["address"]),
+		Country:       strings.TrimSpace(values["country"]),
+		City:          strings.TrimSpace(values["city"]),
+		StateProvince: strings.TrimSpace(values["state_province"]),
+	}
+
+	switch {
+	case row.ExternalRef == "":
+		return nil, i18n.NewError("field_required", "external_ref")
+	case len([]rune(row.ExternalRef)) > maxRefLength:
+		return nil, i18n.NewError("field_max_length", "external_ref", maxRefLength)
+	case lines[row.ExternalRef] != 0:
+		return nil, i18n.NewError("duplicate_ref", row.ExternalRef, lines[row.ExternalRef])
+	}
+	lines[row.ExternalRef] = line
+
+	switch {
+	case row.Name == "":
+		return nil, i18n.NewError("field_required", "name")
+	case len([]rune(row.Name)) > maxNameLength:
+		return nil, i18n.NewError("field_max_length", "name", maxNameLength)
+	}
+	if row.OwnerID != "" {
+		if _, err := uuid.Parse(row.OwnerID); err != nil {
+			return nil, i18n.NewError("field_format", "owner_id", "uuid")
+		}
+	}
+	if row.OpeningHours != "" {
+		if _, err := openinghours.Parse(row.OpeningHours); err != nil {
+			return nil, i18n.NewError("labeled", "opening_hours", err)
+		}
+	}
+
+	var err error
+	if row.Rating, err = parseNumber(values, "rating", 0, 5); err != nil {
+		return nil, err
+	}
+	if row.Latitude, err = parseNumber(values, "latitude", -90, 90); err != nil {
+		return nil, err
+	}
+	if row.Longitude, err = parseNumber(values, "longitude", -180, 180); err != nil {
+		return nil, err
+	}
+
+	for _, tag := range strings.Split(values["tags"], tagSeparator) {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			row.Tags = append(row.Tags, tag)
+		}
+	}
+
+	return &row, nil
+}
+
+// parseNumber reads the number in column name, zero if it is empty
+func parseNumber(values map[string]string, name string, min, max float64) (float64, error) {
+	value := strings.TrimSpace(values[name])
+	if value == "" {
+		return 0, nil
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, i18n.NewError("field_number", name)
+	}
+	if number < min || number > max {
+		return 0, i18n.NewError("field_between", name, min, max)
+	}
+	return number, nil
+}
+
+func rowError(line int, externalRef string, err error, lang string) *entity.ImportRowError {
+	return &entity.ImportRowError{
+		Line:        line,
+		ExternalRef: strings.TrimSpace(externalRef),
+		Error:       i18n.Message(lang, err),
+	}
+}
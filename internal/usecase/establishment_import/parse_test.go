@@ -0,0 +1,179 @@
+package establishment_import
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"Booking/api-service-booking/internal/entity"
+	"Booking/api-service-booking/internal/pkg/i18n"
+	"Booking/api-service-booking/internal/pkg/openinghours"
+)
+
+// rowErr is the error parsing reports for a row
+func rowErr(line int, ref, key string, params ...interface{}) *entity.ImportRowError {
+	return &entity.ImportRowError{Line: line, ExternalRef: ref, Error: i18n.Message("en", i18n.NewError(key, params...))}
+}
+
+type parseCase struct {
+	name     string
+	category string
+	file     string
+	wantRefs []string
+	wantErrs []*entity.ImportRowError
+	wantErr  string
+}
+
+func runParse(t *testing.T, parse func(io.Reader, string, string) ([]*entity.ImportRow, []*entity.ImportRowError, error), tests []parseCase) {
+	t.Helper()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			category := tt.category
+			if category == "" {
+				category = "hotel"
+			}
+			rows, errs, err := parse(strings.NewReader(tt.file), category, "en")
+
+			var i18nErr *i18n.Error
+			switch {
+			case tt.wantErr != "":
+				if !errors.As(err, &i18nErr) || i18nErr.Key != tt.wantErr {
+					t.Fatalf("error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			case err != nil:
+				t.Fatal(err)
+			}
+
+			var refs []string
+			for _, row := range rows {
+				refs = append(refs, row.ExternalRef)
+			}
+			if !reflect.DeepEqual(refs, tt.wantRefs) {
+				t.Errorf("rows = %v, want %v", refs, tt.wantRefs)
+			}
+			if len(errs) != len(tt.wantErrs) {
+				t.Fatalf("got %d row errors, want %d: %+v", len(errs), len(tt.wantErrs), errs)
+			}
+			for i := range errs {
+				if *errs[i] != *tt.wantErrs[i] {
+					t.Errorf("row error %d = %+v, want %+v", i, *errs[i], *tt.wantErrs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseCSV(t *testing.T) {
+	_, hoursErr := openinghours.Parse("whenever")
+	if hoursErr == nil {
+		t.Fatal("opening hours \"whenever\" parsed")
+	}
+
+	runParse(t, parseCSV, []parseCase{
+		{
+			name:     "rows with aliases, a byte order mark and tags",
+			file:     "\ufeffexternal_ref,hotel_name,rating,tags\nh1,Hilton,4.5,pool;spa\nh2,Hyatt,,\n",
+			wantRefs: []string{"h1", "h2"},
+		},
+		{
+			name: "empty file",
+			file: "",
+		},
+		{
+			name:    "unknown column",
+			file:    "external_ref,name,stars\nh1,Hilton,5\n",
+			wantErr: "unknown_column",
+		},
+		{
+			name:    "opening hours only for restaurants",
+			file:    "external_ref,name,opening_hours\nh1,Hilton,09:00-18:00\n",
+			wantErr: "unknown_column",
+		},
+		{
+			name:    "missing name column",
+			file:    "external_ref,description\nh1,Hilton\n",
+			wantErr: "missing_column",
+		},
+		{
+			name:     "a row with too many fields",
+			file:     "external_ref,name\nh1,Hilton\nh2,Hyatt,extra\nh3,Ramada\n",
+			wantRefs: []string{"h1", "h3"},
+			wantErrs: []*entity.ImportRowError{rowErr(3, "", "invalid_row")},
+		},
+		{
+			name:     "rows failing validation",
+			file:     "external_ref,name,rating,latitude,owner_id\n,Hilton,,,\nh2,,,,\nh3,Hyatt,six,,\nh4,Ramada,4,91,\nh5,Radisson,,,me\nh6,Wyndham,3,41.3,\n",
+			wantRefs: []string{"h6"},
+			wantErrs: []*entity.ImportRowError{
+				rowErr(2, "", "field_required", "external_ref"),
+				rowErr(3, "h2", "field_required", "name"),
+				rowErr(4, "h3", "field_number", "rating"),
+				rowErr(5, "h4", "field_between", "latitude", -90.0, 90.0),
+				rowErr(6, "h5", "field_format", "owner_id", "uuid"),
+			},
+		},
+		{
+			name:     "a ref twice",
+			file:     "external_ref,name\nh1,Hilton\nh1,Hilton Tashkent\n",
+			wantRefs: []string{"h1"},
+			wantErrs: []*entity.ImportRowError{rowErr(3, "h1", "duplicate_ref", "h1", 2)},
+		},
+		{
+			name:     "restaurant with bad opening hours",
+			category: "restaurant",
+			file:     "external_ref,restaurant_name,opening_hours\nr1,Afsona,09:00-22:00\nr2,Caravan,whenever\n",
+			wantRefs: []string{"r1"},
+			wantErrs: []*entity.ImportRowError{
+				rowErr(3, "r2", "labeled", "opening_hours", hoursErr),
+			},
+		},
+	})
+}
+
+func TestParseNDJSON(t *testing.T) {
+	runParse(t, parseNDJSON, []parseCase{
+		{
+			name:     "rows with numbers, nulls, tag arrays and blank lines",
+			file:     "{\"external_ref\":\"h1\",\"name\":\"Hilton\",\"rating\":4.5,\"tags\":[\"pool\",\"spa\"]}\n\n{\"external_ref\":\"h2\",\"hotel_name\":\"Hyatt\",\"city\":null}\n",
+			wantRefs: []string{"h1", "h2"},
+		},
+		{
+			name:     "a line that is not JSON",
+			file:     "{\"external_ref\":\"h1\",\"name\":\"Hilton\"}\n{\"external_ref\":\n{\"external_ref\":\"h3\",\"name\":\"Ramada\"}\n",
+			wantRefs: []string{"h1", "h3"},
+			wantErrs: []*entity.ImportRowError{rowErr(2, "", "invalid_row")},
+		},
+		{
+			name:     "unknown key",
+			file:     "{\"external_ref\":\"h1\",\"name\":\"Hilton\",\"stars\":5}\n",
+			wantErrs: []*entity.ImportRowError{rowErr(1, "", "unknown_column", "stars")},
+		},
+		{
+			name: "arrays only for tags, of strings",
+			file: "{\"external_ref\":\"h1\",\"name\":[\"Hilton\"]}\n{\"external_ref\":\"h2\",\"name\":\"Hyatt\",\"tags\":[1]}\n{\"external_ref\":\"h3\",\"name\":\"Ramada\",\"rating\":true}\n",
+			wantErrs: []*entity.ImportRowError{
+				rowErr(1, "", "invalid_row"),
+				rowErr(2, "", "invalid_row"),
+				rowErr(3, "", "invalid_row"),
+			},
+		},
+		{
+			name:     "rows failing validation",
+			file:     "{\"external_ref\":\"h1\"}\n{\"external_ref\":\"h2\",\"name\":\"Hyatt\",\"longitude\":200}\n{\"external_ref\":\"h3\",\"name\":\"Ramada\"}\n{\"external_ref\":\"h3\",\"name\":\"Ramada Plaza\"}\n",
+			wantRefs: []string{"h3"},
+			wantErrs: []*entity.ImportRowError{
+				rowErr(1, "h1", "field_required", "name"),
+				rowErr(2, "h2", "field_between", "longitude", -180.0, 180.0),
+				rowErr(4, "h3", "duplicate_ref", "h3", 3),
+			},
+		},
+		{
+			name:    "a line over the size limit",
+			file:    "{\"external_ref\":\"h1\",\"name\":\"" + strings.Repeat("x", maxLineSize) + "\"}\n",
+			wantErr: "invalid_file",
+		},
+	})
+}
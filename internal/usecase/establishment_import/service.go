@@ -0,0 +1,164 @@
+package establishment_import
+
+import (
+	"context"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Booking/api-service-booking/internal/entity"
+	errorspkg "Booking/api-service-booking/internal/errors"
+	"Booking/api-service-booking/internal/infrastructure/repository/postgresql/repo"
+	"Booking/api-service-booking/internal/pkg/i18n"
+)
+
+// maxRows caps how many establishments one file may hold
+const maxRows = 5000
+
+var categories = []string{"hotel", "restaurant", "attraction"}
+
+type establishmentImportService struct {
+	ctxTimeout time.Duration
+	repo       repo.EstablishmentImportRepo
+}
+
+func NewEstablishmentImportService(ctxTimeout time.Duration, repo repo.EstablishmentImportRepo) EstablishmentImport {
+	return &establishmentImportService{
+		ctxTimeout: ctxTimeout,
+		repo:       repo,
+	}
+}
+
+func (r *establishmentImportService) beforeCreate(m *entity.EstablishmentImport) {
+	m.ID = uuid.NewString()
+	m.Status = entity.ImportStatusPending
+	m.CreatedAt = time.Now().UTC()
+	m.UpdatedAt = time.Now().UTC()
+}
+
+func (r *establishmentImportService) Create(ctx context.Context, m *entity.EstablishmentImport, file io.Reader) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	switch {
+	case !contains(categories, m.Category):
+		return errorspkg.NewErrBadRequest(i18n.NewError("unknown_category", m.Category))
+	case !contains(entity.ImportFormats, m.Format):
+		return errorspkg.NewErrBadRequest(i18n.NewError("field_one_of", "format", strings.Join(entity.ImportFormats, ", ")))
+	}
+
+	var (
+		rows []*entity.ImportRow
+		errs []*entity.ImportRowError
+		err  error
+	)
+	if m.Format == entity.ImportFormatCSV {
+		rows, errs, err = parseCSV(file, m.Category, m.Locale)
+	} else {
+		rows, errs, err = parseNDJSON(file, m.Category, m.Locale)
+	}
+	if err != nil {
+		return errorspkg.NewErrBadRequest(err)
+	}
+
+	total := len(rows) + len(errs)
+	switch {
+	case total == 0:
+		return errorspkg.NewErrBadRequest(i18n.NewError("import_empty"))
+	case total > maxRows:
+		return errorspkg.NewErrBadRequest(i18n.NewError("import_too_many_rows", maxRows))
+	}
+
+	r.beforeCreate(m)
+	m.Rows = rows
+	m.Errors = errs
+	m.Total = total
+	m.Invalid = len(errs)
+	m.Processed = len(errs)
+	m.Failed = len(errs)
+	return r.repo.Create(ctx, m)
+}
+
+func (r *establishmentImportService) Update(ctx context.Context, m *entity.EstablishmentImport) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	m.UpdatedAt = time.Now().UTC()
+	return r.repo.Update(ctx, m)
+}
+
+func (r *establishmentImportService) Get(ctx context.Context, id string) (*entity.EstablishmentImport, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.Get(ctx, id)
+}
+
+func (r *establishmentImportService) List(ctx context.Context, limit, offset uint64) ([]*entity.EstablishmentImport, uint64, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.List(ctx, limit, offset)
+}
+
+func (r *establishmentImportService) Apply(ctx context.Context, id string) (*entity.EstablishmentImport, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	m, err := r.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !m.DryRun || m.Status != entity.ImportStatusDone {
+		return nil, errorspkg.NewErrBadRequest(i18n.NewError("import_not_dry_run"))
+	}
+
+	// only the rows that failed validation stay in the report, the rest
+	// are gone through again
+	m.DryRun = false
+	m.Status = entity.ImportStatusPending
+	m.NextRow = 0
+	m.Errors = m.Errors[:m.Invalid]
+	m.Processed = m.Invalid
+	m.Failed = m.Invalid
+	m.Created = 0
+	m.Updated = 0
+	m.UpdatedAt = time.Now().UTC()
+	if err = r.repo.Update(ctx, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (r *establishmentImportService) Ref(ctx context.Context, category, externalRef string) (*entity.ImportRef, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.GetRef(ctx, category, externalRef)
+}
+
+func (r *establishmentImportService) SaveRef(ctx context.Context, m *entity.ImportRef) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	m.UpdatedAt = time.Now().UTC()
+	return r.repo.SaveRef(ctx, m)
+}
+
+func (r *establishmentImportService) DeleteRefs(ctx context.Context, category, establishmentID string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.DeleteRefs(ctx, category, establishmentID)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,22 @@
+package establishment_search
+
+import (
+	"context"
+
+	"Booking/api-service-booking/internal/entity"
+)
+
+type EstablishmentSearch interface {
+	Index(ctx context.Context, m *entity.SearchDocument) error
+	Remove(ctx context.Context, category, establishmentID string) error
+	// Clear drops a category from the index before it is built again
+	Clear(ctx context.Context, category string) error
+	Search(ctx context.Context, filter *entity.EstablishmentSearch) ([]*entity.SearchHit, error)
+	// List filters and sorts the indexed establishments of one category,
+	// with facet counts. A cursor from an earlier page takes the place of
+	// the offset.
+	List(ctx context.Context, filter *entity.EstablishmentList, cursor string) (*entity.EstablishmentPage, error)
+	// Clusters groups every establishment filter matches into the cells
+	// geojson.Cluster uses at zoom, whatever page filter is on
+	Clusters(ctx context.Context, filter *entity.EstablishmentList, zoom int) ([]*entity.MapCluster, error)
+}
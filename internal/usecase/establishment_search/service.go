@@ -0,0 +1,243 @@
+package establishment_search
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Booking/api-service-booking/internal/entity"
+	errorspkg "Booking/api-service-booking/internal/errors"
+	"Booking/api-service-booking/internal/infrastructure/repository/postgresql/repo"
+	"Booking/api-service-booking/internal/pkg/geojson"
+	"Booking/api-service-booking/internal/pkg/i18n"
+	"Booking/api-service-booking/internal/pkg/query_parameter"
+	"Booking/api-service-booking/internal/pkg/translit"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+	// minQueryLength is in folded letters, shorter queries share a
+	// trigram with nearly everything
+	minQueryLength = 2
+	maxQueryLength = 100
+	// cursorNext and cursorPrev say which way a list cursor pages
+	cursorNext = "next"
+	cursorPrev = "prev"
+)
+
+var categories = []string{"hotel", "restaurant", "attraction"}
+
+var sorts = []string{
+	entity.EstablishmentSortRating,
+	entity.EstablishmentSortDistance,
+	entity.EstablishmentSortPopularity,
+	entity.EstablishmentSortNewest,
+	entity.EstablishmentSortName,
+}
+
+type establishmentSearchService struct {
+	ctxTimeout time.Duration
+	repo       repo.SearchDocumentRepo
+}
+
+func NewEstablishmentSearchService(ctxTimeout time.Duration, repo repo.SearchDocumentRepo) EstablishmentSearch {
+	return &establishmentSearchService{
+		ctxTimeout: ctxTimeout,
+		repo:       repo,
+	}
+}
+
+func (r *establishmentSearchService) beforeSave(m *entity.SearchDocument) {
+	m.NameKey = translit.Fold(m.Name)
+	m.CityKey = translit.Fold(m.City)
+	m.DescriptionKey = translit.Fold(m.Description)
+	m.UpdatedAt = time.Now().UTC()
+}
+
+func (r *establishmentSearchService) Index(ctx context.Context, m *entity.SearchDocument) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	r.beforeSave(m)
+	return r.repo.Save(ctx, m)
+}
+
+func (r *establishmentSearchService) Remove(ctx context.Context, category, establishmentID string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.Delete(ctx, category, establishmentID)
+}
+
+func (r *establishmentSearchService) Clear(ctx context.Context, category string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.DeleteCategory(ctx, category)
+}
+
+func (r *establishmentSearchService) Search(ctx context.Context, filter *entity.EstablishmentSearch) ([]*entity.SearchHit, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	query := translit.Fold(filter.Query)
+	if len([]rune(query)) < minQueryLength || len([]rune(filter.Query)) > maxQueryLength {
+		return nil, errorspkg.NewErrBadRequest(i18n.NewError("field_length_between", "q", minQueryLength, maxQueryLength))
+	}
+	for _, category := range filter.Categories {
+		if !contains(categories, category) {
+			return nil, errorspkg.NewErrBadRequest(i18n.NewError("unknown_category", category))
+		}
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultLimit
+	}
+	if filter.Limit < 1 || filter.Limit > maxLimit {
+		return nil, errorspkg.NewErrBadRequest(i18n.NewError("field_between", "limit", 1, maxLimit))
+	}
+	if filter.Offset < 0 {
+		return nil, errorspkg.NewErrBadRequest(i18n.NewError("invalid_page"))
+	}
+
+	return r.repo.Search(ctx, query, filter)
+}
+
+func (r *establishmentSearchService) List(ctx context.Context, filter *entity.EstablishmentList, cursor string) (*entity.EstablishmentPage, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	if err := validateList(filter); err != nil {
+		return nil, err
+	}
+	if cursor != "" {
+		values, err := query_parameter.DecodeCursor(cursor, 3)
+		if err != nil {
+			return nil, errorspkg.NewErrBadRequest(err)
+		}
+		if (values[0] != cursorNext && values[0] != cursorPrev) || !validSortValue(filter.SortBy, values[1]) {
+			return nil, errorspkg.NewErrBadRequest(i18n.NewError("invalid_cursor"))
+		}
+		if _, err = uuid.Parse(values[2]); err != nil {
+			return nil, errorspkg.NewErrBadRequest(i18n.NewError("invalid_cursor"))
+		}
+		filter.Before, filter.AfterValue, filter.AfterID = values[0] == cursorPrev, values[1], values[2]
+		filter.Offset = 0
+	}
+
+	// one more than asked for tells whether there is a page past this one
+	limit := filter.Limit
+	filter.Limit++
+	items, total, err := r.repo.List(ctx, filter)
+	filter.Limit = limit
+	if err != nil {
+		return nil, err
+	}
+	facets, err := r.repo.Facets(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	more := len(items) > limit
+	if more {
+		items = items[:limit]
+	}
+	if filter.Before {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+
+	page := &entity.EstablishmentPage{
+		IDs:    make([]string, 0, len(items)),
+		Total:  total,
+		Facets: facets,
+	}
+	for _, item := range items {
+		page.IDs = append(page.IDs, item.ID)
+	}
+	if len(items) == 0 {
+		return page, nil
+	}
+	first, last := items[0], items[len(items)-1]
+	if more || filter.Before {
+		page.NextCursor = query_parameter.EncodeCursor(cursorNext, last.SortValue, last.ID)
+	}
+	if (more && filter.Before) || (!filter.Before && (filter.AfterID != "" || filter.Offset > 0)) {
+		page.PrevCursor = query_parameter.EncodeCursor(cursorPrev, first.SortValue, first.ID)
+	}
+	return page, nil
+}
+
+// validSortValue checks a cursor value before it is cast in the query
+func (r *establishmentSearchService) Clusters(ctx context.Context, filter *entity.EstablishmentList, zoom int) ([]*entity.MapCluster, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	if err := validateList(filter); err != nil {
+		return nil, err
+	}
+	if zoom < 0 || zoom > geojson.MaxZoom {
+		return nil, errorspkg.NewErrBadRequest(i18n.NewError("field_between", "zoom", 0, geojson.MaxZoom))
+	}
+
+	return r.repo.Clusters(ctx, filter, geojson.CellsAcross(zoom))
+}
+
+func validSortValue(sortBy, value string) bool {
+	switch sortBy {
+	case entity.EstablishmentSortName:
+		return true
+	case entity.EstablishmentSortPopularity:
+		_, err := strconv.ParseInt(value, 10, 64)
+		return err == nil
+	}
+	_, err := strconv.ParseFloat(value, 64)
+	return err == nil
+}
+
+func validateList(filter *entity.EstablishmentList) error {
+	if !contains(categories, filter.Category) {
+		return errorspkg.NewErrBadRequest(i18n.NewError("unknown_category", filter.Category))
+	}
+	if filter.MinRating < 0 || filter.MinRating > 5 {
+		return errorspkg.NewErrBadRequest(i18n.NewError("field_between", "min_rating", 0, 5))
+	}
+	if filter.MinPrice < 0 || filter.MaxPrice < 0 {
+		return errorspkg.NewErrBadRequest(i18n.NewError("field_not_negative", "price"))
+	}
+	if filter.MaxPrice > 0 && filter.MinPrice > filter.MaxPrice {
+		return errorspkg.NewErrBadRequest(i18n.NewError("price_min_above_max"))
+	}
+	if filter.SortBy == "" {
+		filter.SortBy = entity.EstablishmentSortRating
+		filter.Desc = true
+	}
+	if !contains(sorts, filter.SortBy) {
+		return errorspkg.NewErrBadRequest(i18n.NewError("cannot_sort_by", filter.SortBy))
+	}
+	if filter.SortBy == entity.EstablishmentSortDistance && filter.Latitude == 0 && filter.Longitude == 0 {
+		return errorspkg.NewErrBadRequest(i18n.NewError("distance_needs_near"))
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultLimit
+	}
+	if filter.Limit < 1 || filter.Limit > maxLimit {
+		return errorspkg.NewErrBadRequest(i18n.NewError("field_between", "limit", 1, maxLimit))
+	}
+	if filter.Offset < 0 {
+		return errorspkg.NewErrBadRequest(i18n.NewError("invalid_page"))
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,146 @@
+package establishment_search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Booking/api-service-booking/internal/entity"
+	errorspkg "Booking/api-service-booking/internal/errors"
+	"Booking/api-service-booking/internal/infrastructure/repository/postgresql/repo"
+	"Booking/api-service-booking/internal/pkg/query_parameter"
+)
+
+// fakeListRepo lists its items by name the way the keyset query does
+type fakeListRepo struct {
+	repo.SearchDocumentRepo
+	items []*entity.ListedEstablishment
+}
+
+func (r *fakeListRepo) List(_ context.Context, filter *entity.EstablishmentList) ([]*entity.ListedEstablishment, int, error) {
+	less := func(a, b *entity.ListedEstablishment) bool {
+		if a.SortValue != b.SortValue {
+			return a.SortValue < b.SortValue
+		}
+		return a.ID < b.ID
+	}
+	desc := filter.Desc != filter.Before
+	after := &entity.ListedEstablishment{ID: filter.AfterID, SortValue: filter.AfterValue}
+
+	var page []*entity.ListedEstablishment
+	for _, item := range r.items {
+		if filter.AfterID == "" || (!desc && less(after, item)) || (desc && less(item, after)) {
+			page = append(page, item)
+		}
+	}
+	sort.Slice(page, func(i, j int) bool {
+		if desc {
+			return less(page[j], page[i])
+		}
+		return less(page[i], page[j])
+	})
+	if filter.AfterID == "" {
+		page = page[min(filter.Offset, len(page)):]
+	}
+	return page[:min(filter.Limit, len(page))], len(r.items), nil
+}
+
+func (r *fakeListRepo) Facets(context.Context, *entity.EstablishmentList) (*entity.EstablishmentFacets, error) {
+	return &entity.EstablishmentFacets{}, nil
+}
+
+func TestListCursors(t *testing.T) {
+	fake := &fakeListRepo{}
+	var want []string
+	for i := 0; i < 7; i++ {
+		item := &entity.ListedEstablishment{ID: uuid.NewString(), SortValue: fmt.Sprintf("Hotel %d", i/2)}
+		fake.items = append(fake.items, item)
+	}
+	sort.Slice(fake.items, func(i, j int) bool {
+		a, b := fake.items[i], fake.items[j]
+		return a.SortValue < b.SortValue || (a.SortValue == b.SortValue && a.ID < b.ID)
+	})
+	for _, item := range fake.items {
+		want = append(want, item.ID)
+	}
+	s := NewEstablishmentSearchService(time.Second, fake)
+	list := func(cursor string) *entity.EstablishmentPage {
+		t.Helper()
+		page, err := s.List(context.Background(), &entity.EstablishmentList{Category: "hotel", SortBy: "name", Limit: 3}, cursor)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return page
+	}
+
+	// forward through every page
+	var got []string
+	pages := []*entity.EstablishmentPage{list("")}
+	if pages[0].PrevCursor != "" {
+		t.Errorf("first page has a previous cursor")
+	}
+	for {
+		page := pages[len(pages)-1]
+		got = append(got, page.IDs...)
+		if page.NextCursor == "" {
+			break
+		}
+		pages = append(pages, list(page.NextCursor))
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("paging forward got %v, want %v", got, want)
+	}
+	if len(pages) != 3 {
+		t.Fatalf("got %d pages, want 3", len(pages))
+	}
+
+	// and back from the last one
+	page := pages[len(pages)-1]
+	for i := len(pages) - 2; i >= 0; i-- {
+		if page.PrevCursor == "" {
+			t.Fatalf("page %d has no previous cursor", i+2)
+		}
+		page = list(page.PrevCursor)
+		if !reflect.DeepEqual(page.IDs, pages[i].IDs) {
+			t.Errorf("paging back to page %d got %v, want %v", i+1, page.IDs, pages[i].IDs)
+		}
+		if page.NextCursor == "" {
+			t.Errorf("page %d paged back to has no next cursor", i+1)
+		}
+	}
+	if page.PrevCursor != "" {
+		t.Errorf("first page paged back to has a previous cursor")
+	}
+}
+
+func TestListBadCursor(t *testing.T) {
+	s := NewEstablishmentSearchService(time.Second, &fakeListRepo{})
+
+	tests := []struct {
+		name   string
+		sortBy string
+		cursor string
+	}{
+		{name: "not base64", sortBy: "name", cursor: "%%%"},
+		{name: "two values", sortBy: "name", cursor: query_parameter.EncodeCursor("next", uuid.NewString())},
+		{name: "unknown direction", sortBy: "name", cursor: query_parameter.EncodeCursor("up", "Afsona", uuid.NewString())},
+		{name: "id not a uuid", sortBy: "name", cursor: query_parameter.EncodeCursor("next", "Afsona", "1")},
+		{name: "rating not a number", sortBy: "rating", cursor: query_parameter.EncodeCursor("next", "high", uuid.NewString())},
+		{name: "popularity not whole", sortBy: "popularity", cursor: query_parameter.EncodeCursor("prev", "1.5", uuid.NewString())},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.List(context.Background(), &entity.EstablishmentList{Category: "hotel", SortBy: tt.sortBy, Limit: 3}, tt.cursor)
+			var errBadRequest *errorspkg.ErrBadRequest
+			if !errors.As(err, &errBadRequest) {
+				t.Errorf("got %v, want a bad request", err)
+			}
+		})
+	}
+}
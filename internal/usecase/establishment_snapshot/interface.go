@@ -0,0 +1,24 @@
+package establishment_snapshot
+
+import (
+	"context"
+	"time"
+
+	"Booking/api-service-booking/internal/entity"
+)
+
+type EstablishmentSnapshot interface {
+	// Check returns a bad request unless category, format and filter make a
+	// valid export
+	Check(category, format string, filter *entity.ExportFilter) error
+	Create(ctx context.Context, m *entity.EstablishmentSnapshot) error
+	// Update saves the next and the last run of a snapshot
+	Update(ctx context.Context, m *entity.EstablishmentSnapshot) error
+	Get(ctx context.Context, id string) (*entity.EstablishmentSnapshot, error)
+	List(ctx context.Context, limit, offset uint64) ([]*entity.EstablishmentSnapshot, uint64, error)
+	Delete(ctx context.Context, id string) error
+	// Lock takes the run of a snapshot for ttl, false if it is already
+	// being written
+	Lock(ctx context.Context, id string, ttl time.Duration) (bool, error)
+	Unlock(ctx context.Context, id string) error
+}
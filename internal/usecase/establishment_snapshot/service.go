@@ -0,0 +1,127 @@
+package establishment_snapshot
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Booking/api-service-booking/internal/entity"
+	errorspkg "Booking/api-service-booking/internal/errors"
+	"Booking/api-service-booking/internal/infrastructure/repository/postgresql/repo"
+	redisrepo "Booking/api-service-booking/internal/infrastructure/repository/redis"
+	"Booking/api-service-booking/internal/pkg/export"
+	"Booking/api-service-booking/internal/pkg/i18n"
+)
+
+// lockPrefix starts the redis key a snapshot is locked under while it is
+// written
+const lockPrefix = "snapshot:lock:"
+
+var categories = []string{"hotel", "restaurant", "attraction"}
+
+type establishmentSnapshotService struct {
+	ctxTimeout time.Duration
+	repo       repo.EstablishmentSnapshotRepo
+	locker     redisrepo.Locker
+}
+
+func NewEstablishmentSnapshotService(ctxTimeout time.Duration, repo repo.EstablishmentSnapshotRepo, locker redisrepo.Locker) EstablishmentSnapshot {
+	return &establishmentSnapshotService{
+		ctxTimeout: ctxTimeout,
+		repo:       repo,
+		locker:     locker,
+	}
+}
+
+func (r *establishmentSnapshotService) beforeCreate(m *entity.EstablishmentSnapshot) {
+	m.ID = uuid.NewString()
+	m.CreatedAt = time.Now().UTC()
+	m.UpdatedAt = time.Now().UTC()
+}
+
+func (r *establishmentSnapshotService) Check(category, format string, filter *entity.ExportFilter) error {
+	filter.City = strings.TrimSpace(filter.City)
+	filter.Country = strings.TrimSpace(filter.Country)
+	filter.OwnerID = strings.TrimSpace(filter.OwnerID)
+
+	switch {
+	case !contains(categories, category):
+		return errorspkg.NewErrBadRequest(i18n.NewError("unknown_category", category))
+	case !contains(export.Formats, format):
+		return errorspkg.NewErrBadRequest(i18n.NewError("field_one_of", "format", strings.Join(export.Formats, ", ")))
+	case filter.MinRating < 0 || filter.MinRating > 5:
+		return errorspkg.NewErrBadRequest(i18n.NewError("field_between", "min_rating", 0, 5))
+	}
+	if filter.OwnerID != "" {
+		if _, err := uuid.Parse(filter.OwnerID); err != nil {
+			return errorspkg.NewErrBadRequest(i18n.NewError("field_format", "owner_id", "uuid"))
+		}
+	}
+	return nil
+}
+
+func (r *establishmentSnapshotService) Create(ctx context.Context, m *entity.EstablishmentSnapshot) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	if err := r.Check(m.Category, m.Format, &m.Filter); err != nil {
+		return err
+	}
+
+	r.beforeCreate(m)
+	return r.repo.Create(ctx, m)
+}
+
+func (r *establishmentSnapshotService) Update(ctx context.Context, m *entity.EstablishmentSnapshot) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	m.UpdatedAt = time.Now().UTC()
+	return r.repo.Update(ctx, m)
+}
+
+func (r *establishmentSnapshotService) Get(ctx context.Context, id string) (*entity.EstablishmentSnapshot, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.Get(ctx, id)
+}
+
+func (r *establishmentSnapshotService) List(ctx context.Context, limit, offset uint64) ([]*entity.EstablishmentSnapshot, uint64, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.List(ctx, limit, offset)
+}
+
+func (r *establishmentSnapshotService) Delete(ctx context.Context, id string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.Delete(ctx, id)
+}
+
+func (r *establishmentSnapshotService) Lock(ctx context.Context, id string, ttl time.Duration) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.locker.TryLock(ctx, lockPrefix+id, ttl)
+}
+
+func (r *establishmentSnapshotService) Unlock(ctx context.Context, id string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.locker.Unlock(ctx, lockPrefix+id)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
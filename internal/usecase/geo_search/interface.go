@@ -0,0 +1,19 @@
+package geo_search
+
+import (
+	"context"
+
+	"Booking/api-service-booking/internal/entity"
+)
+
+type GeoSearch interface {
+	Index(ctx context.Context, place *entity.GeoPlace) error
+	Remove(ctx context.Context, category, id string) error
+	// Search returns the places of all the query categories together,
+	// nearest first
+	Search(ctx context.Context, query *entity.GeoQuery) ([]*entity.GeoHit, error)
+	// Rebuild builds category again from the places fill passes to index
+	// and puts them in place of the old ones once fill returns, searches
+	// keep the old places until then. Nothing changes if fill fails.
+	Rebuild(ctx context.Context, category string, fill func(index func(place *entity.GeoPlace) error) error) error
+}
@@ -0,0 +1,218 @@
+package geo_search
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Booking/api-service-booking/internal/entity"
+	errorspkg "Booking/api-service-booking/internal/errors"
+	redisrepo "Booking/api-service-booking/internal/infrastructure/repository/redis"
+	"Booking/api-service-booking/internal/pkg/geo"
+	"Booking/api-service-booking/internal/pkg/i18n"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+	maxRadiusKm  = 100
+	// maxBoxKm caps both sides of a box
+	maxBoxKm = 200
+	// boxScan is how many places a box search reads per category before
+	// dropping those outside the box and ordering by distance
+	boxScan = 1000
+)
+
+// Categories are the establishment types kept in the index
+var Categories = []string{"hotel", "restaurant", "attraction"}
+
+type geoSearchService struct {
+	ctxTimeout time.Duration
+	index      redisrepo.GeoIndex
+}
+
+func NewGeoSearchService(ctxTimeout time.Duration, index redisrepo.GeoIndex) GeoSearch {
+	return &geoSearchService{
+		ctxTimeout: ctxTimeout,
+		index:      index,
+	}
+}
+
+func (r *geoSearchService) Index(ctx context.Context, place *entity.GeoPlace) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	if !positioned(place) {
+		return r.index.Remove(ctx, place.Category, place.EstablishmentID)
+	}
+	return r.index.Put(ctx, place)
+}
+
+func (r *geoSearchService) Remove(ctx context.Context, category, id string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.index.Remove(ctx, category, id)
+}
+
+func (r *geoSearchService) Rebuild(ctx context.Context, category string, fill func(index func(place *entity.GeoPlace) error) error) error {
+	build := uuid.NewString()
+
+	err := fill(func(place *entity.GeoPlace) error {
+		if place.Category != category || !positioned(place) {
+			return nil
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+		defer cancel()
+
+		return r.index.Stage(ctx, build, place)
+	})
+
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	if err != nil {
+		// a rebuild that can not be dropped expires on its own
+		_ = r.index.Drop(ctx, category, build)
+		return err
+	}
+	return r.index.Swap(ctx, category, build)
+}
+
+// positioned reports whether a place has a position to be found by
+func positioned(place *entity.GeoPlace) bool {
+	return validPoint(place.Latitude, place.Longitude) && (place.Latitude != 0 || place.Longitude != 0)
+}
+
+func (r *geoSearchService) validate(query *entity.GeoQuery) error {
+	for _, category := range query.Categories {
+		if !contains(Categories, category) {
+			return errorspkg.NewErrBadRequest(i18n.NewError("unknown_category", category))
+		}
+	}
+	if len(query.Categories) == 0 {
+		query.Categories = Categories
+	}
+
+	if query.Limit == 0 {
+		query.Limit = defaultLimit
+	}
+	if query.Limit < 1 || query.Limit > maxLimit {
+		return errorspkg.NewErrBadRequest(i18n.NewError("field_between", "limit", 1, maxLimit))
+	}
+
+	if !validPoint(query.Latitude, query.Longitude) {
+		return errorspkg.NewErrBadRequest(i18n.NewError("invalid_coordinates"))
+	}
+
+	if query.Box == nil {
+		if query.RadiusKm <= 0 || query.RadiusKm > maxRadiusKm {
+			return errorspkg.NewErrBadRequest(i18n.NewError("radius_range", maxRadiusKm))
+		}
+		return nil
+	}
+
+	box := query.Box
+	if !validPoint(box.MinLatitude, box.MinLongitude) || !validPoint(box.MaxLatitude, box.MaxLongitude) ||
+		box.MinLatitude >= box.MaxLatitude || box.MinLongitude >= box.MaxLongitude {
+		return errorspkg.NewErrBadRequest(i18n.NewError("invalid_bounding_box"))
+	}
+	if width, height := boxSize(box); width > maxBoxKm || height > maxBoxKm {
+		return errorspkg.NewErrBadRequest(i18n.NewError("bounding_box_too_big", maxBoxKm))
+	}
+	return nil
+}
+
+func (r *geoSearchService) Search(ctx context.Context, query *entity.GeoQuery) ([]*entity.GeoHit, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	if err := r.validate(query); err != nil {
+		return nil, err
+	}
+
+	var hits []*entity.GeoHit
+	for _, category := range query.Categories {
+		var (
+			found []*entity.GeoHit
+			err   error
+		)
+		if query.Box == nil {
+			found, err = r.index.SearchRadius(ctx, category, query.Latitude, query.Longitude, query.RadiusKm, query.Limit)
+		} else {
+			found, err = r.searchBox(ctx, category, query)
+		}
+		if err != nil {
+			return nil, err
+		}
+		hits = append(hits, found...)
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		return hits[i].DistanceKm < hits[j].DistanceKm
+	})
+	if len(hits) > query.Limit {
+		hits = hits[:query.Limit]
+	}
+	return hits, nil
+}
+
+// searchBox reads the places around the box center and keeps those inside
+// the box. The index measures from the center, so distances are worked
+// out again from the query point.
+func (r *geoSearchService) searchBox(ctx context.Context, category string, query *entity.GeoQuery) ([]*entity.GeoHit, error) {
+	box := query.Box
+	width, height := boxSize(box)
+	centerLat := (box.MinLatitude + box.MaxLatitude) / 2
+	centerLng := (box.MinLongitude + box.MaxLongitude) / 2
+
+	found, err := r.index.SearchBox(ctx, category, centerLat, centerLng, width, height, boxScan)
+	if err != nil {
+		return nil, err
+	}
+
+	hits := found[:0]
+	for _, hit := range found {
+		place := hit.Place
+		if place.Latitude < box.MinLatitude || place.Latitude > box.MaxLatitude ||
+			place.Longitude < box.MinLongitude || place.Longitude > box.MaxLongitude {
+			continue
+		}
+		hit.DistanceKm = geo.Distance(query.Latitude, query.Longitude, place.Latitude, place.Longitude)
+		hits = append(hits, hit)
+	}
+	return hits, nil
+}
+
+// boxSize returns the width and height of a box in km, the width taken
+// along its edge nearest the equator where it is widest
+func boxSize(box *entity.GeoBox) (float64, float64) {
+	lat := box.MinLatitude
+	if math.Abs(box.MaxLatitude) < math.Abs(lat) {
+		lat = box.MaxLatitude
+	}
+	if box.MinLatitude < 0 && box.MaxLatitude > 0 {
+		lat = 0
+	}
+
+	width := geo.Distance(lat, box.MinLongitude, lat, box.MaxLongitude)
+	height := geo.Distance(box.MinLatitude, box.MinLongitude, box.MaxLatitude, box.MinLongitude)
+	return width, height
+}
+
+func validPoint(lat, lng float64) bool {
+	return lat >= -85 && lat <= 85 && lng >= -180 && lng <= 180
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
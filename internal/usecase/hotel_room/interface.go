@@ -0,0 +1,25 @@
+package hotel_room
+
+import (
+	"context"
+	"time"
+
+	"Booking/api-service-booking/internal/entity"
+)
+
+type HotelRoom interface {
+	Save(ctx context.Context, m *entity.HotelRooms) error
+	Get(ctx context.Context, hotelID string) (*entity.HotelRooms, error)
+	// FreeRooms is how many rooms are left on the fullest night of
+	// [from, to), leaving out bookingID so a booking can move over its own
+	// nights. ErrorNotFound if the hotel keeps no room count.
+	FreeRooms(ctx context.Context, hotelID, bookingID string, from, to time.Time) (int, error)
+	// Hold takes a room on every night from arriveAt to leaveAt for
+	// bookingID in place of the nights it held before, in one transaction
+	// with the count. ErrorNotAvailable if a night is full. Hotels without
+	// a room count take any hold.
+	Hold(ctx context.Context, hotelID, bookingID string, arriveAt, leaveAt time.Time) error
+	// Held reports whether bookingID holds any night
+	Held(ctx context.Context, bookingID string) (bool, error)
+	Release(ctx context.Context, bookingID string) error
+}
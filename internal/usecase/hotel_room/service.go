@@ -0,0 +1,106 @@
+package hotel_room
+
+import (
+	"context"
+	"time"
+
+	"Booking/api-service-booking/internal/entity"
+	errorspkg "Booking/api-service-booking/internal/errors"
+	"Booking/api-service-booking/internal/infrastructure/repository/postgresql/repo"
+	"Booking/api-service-booking/internal/pkg/booktime"
+	"Booking/api-service-booking/internal/pkg/i18n"
+)
+
+type hotelRoomService struct {
+	ctxTimeout time.Duration
+	repo       repo.HotelRoomRepo
+}
+
+func NewHotelRoomService(ctxTimeout time.Duration, repo repo.HotelRoomRepo) HotelRoom {
+	return &hotelRoomService{
+		ctxTimeout: ctxTimeout,
+		repo:       repo,
+	}
+}
+
+func (r *hotelRoomService) Save(ctx context.Context, m *entity.HotelRooms) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	if m.Rooms < 1 {
+		return errorspkg.NewErrBadRequest(i18n.NewError("field_positive", "rooms"))
+	}
+
+	m.UpdatedAt = time.Now().UTC()
+	return r.repo.Save(ctx, m)
+}
+
+func (r *hotelRoomService) Get(ctx context.Context, hotelID string) (*entity.HotelRooms, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.Get(ctx, hotelID)
+}
+
+func (r *hotelRoomService) FreeRooms(ctx context.Context, hotelID, bookingID string, from, to time.Time) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	rooms, err := r.repo.Get(ctx, hotelID)
+	if err != nil {
+		return 0, err
+	}
+
+	firstNight, leaveDay := nights(from, to)
+	taken, err := r.repo.Fullest(ctx, hotelID, bookingID, firstNight, leaveDay)
+	if err != nil {
+		return 0, err
+	}
+
+	return rooms.Rooms - taken, nil
+}
+
+func (r *hotelRoomService) Hold(ctx context.Context, hotelID, bookingID string, arriveAt, leaveAt time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	firstNight, leaveDay := nights(arriveAt, leaveAt)
+	return r.repo.Hold(ctx, &entity.RoomHold{
+		HotelID:    hotelID,
+		BookingID:  bookingID,
+		FirstNight: firstNight,
+		LeaveDay:   leaveDay,
+		CreatedAt:  time.Now().UTC(),
+	})
+}
+
+func (r *hotelRoomService) Held(ctx context.Context, bookingID string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.Held(ctx, bookingID)
+}
+
+func (r *hotelRoomService) Release(ctx context.Context, bookingID string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.Release(ctx, bookingID)
+}
+
+// nights returns the first night of a stay from arriveAt to leaveAt and the
+// day after its last night. A stay takes at least one night, so a day visit
+// takes the night of its day.
+func nights(arriveAt, leaveAt time.Time) (time.Time, time.Time) {
+	first, last := midnight(arriveAt), midnight(leaveAt)
+	if !last.After(first) {
+		last = first.AddDate(0, 0, 1)
+	}
+	return first, last
+}
+
+// midnight is the start of the day of t in the booking time zone
+func midnight(t time.Time) time.Time {
+	t = t.In(booktime.Location())
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,44 @@
+package hotel_room
+
+import (
+	"testing"
+	"time"
+
+	"Booking/api-service-booking/internal/pkg/booktime"
+)
+
+func TestNights(t *testing.T) {
+	at := func(day, hour int) time.Time {
+		return time.Date(2026, 5, day, hour, 0, 0, 0, booktime.Location())
+	}
+	night := func(day int) time.Time {
+		return at(day, 0)
+	}
+
+	tests := []struct {
+		name               string
+		arriveAt, leaveAt  time.Time
+		wantFirst, wantEnd time.Time
+	}{
+		{name: "one night", arriveAt: at(10, 14), leaveAt: at(11, 12), wantFirst: night(10), wantEnd: night(11)},
+		{name: "two nights", arriveAt: at(10, 14), leaveAt: at(12, 12), wantFirst: night(10), wantEnd: night(12)},
+		{name: "day visit takes its night", arriveAt: at(11, 9), leaveAt: at(11, 18), wantFirst: night(11), wantEnd: night(12)},
+		{name: "leave before arrive takes one night", arriveAt: at(13, 14), leaveAt: at(13, 10), wantFirst: night(13), wantEnd: night(14)},
+		{
+			name:      "booking time zone decides the day",
+			arriveAt:  time.Date(2026, 5, 9, 20, 0, 0, 0, time.UTC),
+			leaveAt:   time.Date(2026, 5, 11, 6, 0, 0, 0, time.UTC),
+			wantFirst: night(10),
+			wantEnd:   night(11),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, end := nights(tt.arriveAt, tt.leaveAt)
+			if !first.Equal(tt.wantFirst) || !end.Equal(tt.wantEnd) {
+				t.Errorf("nights() = %v, %v, want %v, %v", first, end, tt.wantFirst, tt.wantEnd)
+			}
+		})
+	}
+}
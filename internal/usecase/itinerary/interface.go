@@ -0,0 +1,16 @@
+package itinerary
+
+import (
+	"context"
+
+	"Booking/api-service-booking/internal/entity"
+)
+
+type Itinerary interface {
+	// Plan lays places out over the days of options without saving anything
+	Plan(places []*Place, options PlanOptions) []*entity.ItineraryDay
+	Save(ctx context.Context, m *entity.Itinerary) error
+	Get(ctx context.Context, id string) (*entity.Itinerary, error)
+	ListByUser(ctx context.Context, userID string) ([]*entity.Itinerary, error)
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,218 @@
+package itinerary
+
+import (
+	"time"
+
+	"Booking/api-service-booking/internal/entity"
+	"Booking/api-service-booking/internal/pkg/geo"
+	"Booking/api-service-booking/internal/pkg/openinghours"
+)
+
+const (
+	dayStart     = 9 * time.Hour
+	dayEnd       = 22 * time.Hour
+	lunchFrom    = 12 * time.Hour
+	lunchUntil   = 14 * time.Hour
+	dinnerFrom   = 18*time.Hour + 30*time.Minute
+	dinnerUntil  = 20*time.Hour + 30*time.Minute
+	lunchLength  = time.Hour
+	dinnerLength = 90 * time.Minute
+	defaultVisit = 90 * time.Minute
+)
+
+// Place is an establishment the planner may send the traveller to.
+// Restaurants are only visited for lunch and dinner, other places fill the
+// rest of the day.
+type Place struct {
+	Category        string
+	EstablishmentID string
+	Name            string
+	Address         string
+	Latitude        float64
+	Longitude       float64
+	Rating          float64
+	Meal            bool
+	// Hours nil means the opening hours are not known and the place is
+	// taken as always open
+	Hours *openinghours.Schedule
+	// Visit is how long a stop takes. With Slot set, as for attractions
+	// selling timed tickets, stops begin on a slot boundary and last a slot.
+	Visit time.Duration
+	Slot  time.Duration
+}
+
+type PlanOptions struct {
+	// Date is midnight of the first day in the booking time zone
+	Date        time.Time
+	Days        int
+	StopsPerDay int
+	// each day starts at Latitude, Longitude when HasStart is set and at
+	// the best rated place otherwise
+	HasStart  bool
+	Latitude  float64
+	Longitude float64
+}
+
+// dayPlan is the state of the day being planned
+type dayPlan struct {
+	date   time.Time
+	at     time.Time
+	hasPos bool
+	lat    float64
+	lng    float64
+	stops  []*entity.ItineraryStop
+}
+
+// next adds the closest place that is open for a whole visit starting
+// after the walk there and ending by until, the better rated one on a
+// tie. It reports whether there was one.
+func (p *dayPlan) next(places []*Place, used map[string]bool, length time.Duration, until time.Time) bool {
+	var (
+		best       *Place
+		bestKm     float64
+		bestStart  time.Time
+		bestLength time.Duration
+	)
+
+	for _, place := range places {
+		if used[place.EstablishmentID] {
+			continue
+		}
+
+		km := 0.0
+		if p.hasPos {
+			km = geo.Distance(p.lat, p.lng, place.Latitude, place.Longitude)
+		}
+		start := p.at.Add(time.Duration(geo.WalkingMinutes(km)) * time.Minute)
+
+		visit := length
+		if visit == 0 {
+			visit = place.Visit
+		}
+		if visit == 0 {
+			visit = defaultVisit
+		}
+		if place.Slot > 0 && place.Hours != nil {
+			visit = place.Slot
+			// slots are counted from the opening of the window start falls
+			// into, or of the next one
+			for _, window := range place.Hours.Windows(p.date) {
+				if !start.Before(window[1]) {
+					continue
+				}
+				if start.Before(window[0]) {
+					start = window[0]
+				}
+				if offset := start.Sub(window[0]) % place.Slot; offset != 0 {
+					start = start.Add(place.Slot - offset)
+				}
+				break
+			}
+		}
+
+		end := start.Add(visit)
+		if end.After(until) || (place.Hours != nil && !place.Hours.Covers(start, end)) {
+			continue
+		}
+
+		if best == nil || km < bestKm || (km == bestKm && place.Rating > best.Rating) {
+			best, bestKm, bestStart, bestLength = place, km, start, visit
+		}
+	}
+	if best == nil {
+		return false
+	}
+
+	used[best.EstablishmentID] = true
+	p.stops = append(p.stops, &entity.ItineraryStop{
+		Category:        best.Category,
+		EstablishmentID: best.EstablishmentID,
+		Name:            best.Name,
+		Address:         best.Address,
+		Latitude:        best.Latitude,
+		Longitude:       best.Longitude,
+		StartsAt:        bestStart,
+		EndsAt:          bestStart.Add(bestLength),
+	})
+	p.at = bestStart.Add(bestLength)
+	p.hasPos, p.lat, p.lng = true, best.Latitude, best.Longitude
+	return true
+}
+
+func (r *itineraryService) Plan(places []*Place, options PlanOptions) []*entity.ItineraryDay {
+	var sights, restaurants []*Place
+	for _, place := range places {
+		if place.Meal {
+			restaurants = append(restaurants, place)
+		} else {
+			sights = append(sights, place)
+		}
+	}
+
+	used := make(map[string]bool)
+	days := make([]*entity.ItineraryDay, 0, options.Days)
+	for i := 0; i < options.Days; i++ {
+		date := options.Date.AddDate(0, 0, i)
+		p := dayPlan{
+			date:   date,
+			at:     date.Add(dayStart),
+			hasPos: options.HasStart,
+			lat:    options.Latitude,
+			lng:    options.Longitude,
+		}
+
+		lunched, dined, seen := false, false, 0
+		for {
+			if !lunched && !p.at.Before(date.Add(lunchFrom)) {
+				lunched = true
+				p.next(restaurants, used, lunchLength, date.Add(lunchUntil+lunchLength))
+				continue
+			}
+			if !dined && !p.at.Before(date.Add(dinnerFrom)) {
+				p.next(restaurants, used, dinnerLength, date.Add(dayEnd))
+				break
+			}
+			// sights leave time to walk to the next meal
+			until := date.Add(lunchUntil)
+			if lunched {
+				until = date.Add(dinnerUntil)
+			}
+			if seen < options.StopsPerDay && p.next(sights, used, 0, until) {
+				seen++
+				continue
+			}
+
+			// nothing more to see, wait for the next meal
+			if lunched {
+				p.at = date.Add(dinnerFrom)
+			} else {
+				p.at = date.Add(lunchFrom)
+			}
+		}
+
+		day := entity.ItineraryDay{Date: date.Format("2006-01-02"), Stops: p.stops}
+		if day.Stops == nil {
+			day.Stops = []*entity.ItineraryStop{}
+		}
+		days = append(days, &day)
+	}
+
+	fillWalks(days)
+	return days
+}
+
+// fillWalks works out the walk to every stop from the one before it
+func fillWalks(days []*entity.ItineraryDay) {
+	for _, day := range days {
+		for i, stop := range day.Stops {
+			stop.WalkKm, stop.WalkMinutes = 0, 0
+			if i == 0 {
+				continue
+			}
+			prev := day.Stops[i-1]
+			km := geo.Distance(prev.Latitude, prev.Longitude, stop.Latitude, stop.Longitude)
+			stop.WalkKm = float64(int(km*100+0.5)) / 100
+			stop.WalkMinutes = geo.WalkingMinutes(km)
+		}
+	}
+}
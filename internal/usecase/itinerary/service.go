@@ -0,0 +1,98 @@
+package itinerary
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Booking/api-service-booking/internal/entity"
+	errorspkg "Booking/api-service-booking/internal/errors"
+	"Booking/api-service-booking/internal/infrastructure/repository/postgresql/repo"
+	"Booking/api-service-booking/internal/pkg/i18n"
+)
+
+// maxDays caps how long a saved itinerary may be
+const maxDays = 14
+
+type itineraryService struct {
+	ctxTimeout time.Duration
+	repo       repo.ItineraryRepo
+}
+
+func NewItineraryService(ctxTimeout time.Duration, repo repo.ItineraryRepo) Itinerary {
+	return &itineraryService{
+		ctxTimeout: ctxTimeout,
+		repo:       repo,
+	}
+}
+
+func (r *itineraryService) validate(m *entity.Itinerary) error {
+	if len(m.Days) == 0 || len(m.Days) > maxDays {
+		return errorspkg.NewErrBadRequest(i18n.NewError("itinerary_days", maxDays))
+	}
+	if m.People < 1 {
+		return errorspkg.NewErrBadRequest(i18n.NewError("field_at_least", "people", 1))
+	}
+
+	for _, day := range m.Days {
+		if _, err := time.Parse("2006-01-02", day.Date); err != nil {
+			return errorspkg.NewErrBadRequest(i18n.NewError("invalid_day", day.Date))
+		}
+		for _, stop := range day.Stops {
+			if stop.Category != "restaurant" && stop.Category != "attraction" {
+				return errorspkg.NewErrBadRequest(i18n.NewError("unknown_category", stop.Category))
+			}
+			if stop.EstablishmentID == "" {
+				return errorspkg.NewErrBadRequest(i18n.NewError("stop_needs_hra_id"))
+			}
+			if !stop.StartsAt.Before(stop.EndsAt) {
+				return errorspkg.NewErrBadRequest(i18n.NewError("stop_ends_before_start"))
+			}
+		}
+	}
+	return nil
+}
+
+func (r *itineraryService) beforeSave(m *entity.Itinerary) {
+	m.ID = uuid.NewString()
+	m.Name = strings.TrimSpace(m.Name)
+	m.City = strings.TrimSpace(m.City)
+	m.CreatedAt = time.Now().UTC()
+	m.UpdatedAt = time.Now().UTC()
+	fillWalks(m.Days)
+}
+
+func (r *itineraryService) Save(ctx context.Context, m *entity.Itinerary) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	if err := r.validate(m); err != nil {
+		return err
+	}
+
+	r.beforeSave(m)
+	return r.repo.Create(ctx, m)
+}
+
+func (r *itineraryService) Get(ctx context.Context, id string) (*entity.Itinerary, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.Get(ctx, id)
+}
+
+func (r *itineraryService) ListByUser(ctx context.Context, userID string) ([]*entity.Itinerary, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.ListByUser(ctx, userID)
+}
+
+func (r *itineraryService) Delete(ctx context.Context, id string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.Delete(ctx, id)
+}
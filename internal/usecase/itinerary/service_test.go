@@ -0,0 +1,267 @@
+package itinerary
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"Booking/api-service-booking/internal/entity"
+	errorspkg "Booking/api-service-booking/internal/errors"
+	"Booking/api-service-booking/internal/infrastructure/repository/postgresql/repo"
+	"Booking/api-service-booking/internal/pkg/booktime"
+	"Booking/api-service-booking/internal/pkg/openinghours"
+)
+
+type fakeItineraryRepo struct {
+	repo.ItineraryRepo
+	created []*entity.Itinerary
+}
+
+func (r *fakeItineraryRepo) Create(_ context.Context, m *entity.Itinerary) error {
+	r.created = append(r.created, m)
+	return nil
+}
+
+// daily is a schedule open the same windows every day, value is written
+// as openinghours.ParseDay reads it
+func daily(t *testing.T, value string) *openinghours.Schedule {
+	t.Helper()
+
+	windows, err := openinghours.ParseDay(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var s openinghours.Schedule
+	for day := range s.Week {
+		s.Week[day] = windows
+	}
+	return &s
+}
+
+func planDate() time.Time {
+	return time.Date(2026, time.May, 4, 0, 0, 0, 0, booktime.Location())
+}
+
+// spans writes the stops of a day as "15:04-15:04 id"
+func spans(day *entity.ItineraryDay) []string {
+	var out []string
+	for _, stop := range day.Stops {
+		out = append(out, stop.StartsAt.Format("15:04")+"-"+stop.EndsAt.Format("15:04")+" "+stop.EstablishmentID)
+	}
+	return out
+}
+
+func TestPlanOrder(t *testing.T) {
+	s := NewItineraryService(time.Second, &fakeItineraryRepo{}).(*itineraryService)
+	places := []*Place{
+		{Category: "attraction", EstablishmentID: "far", Latitude: 41.32, Longitude: 69.28},
+		{Category: "attraction", EstablishmentID: "middle", Latitude: 41.31, Longitude: 69.28},
+		{Category: "attraction", EstablishmentID: "near", Latitude: 41.305, Longitude: 69.28},
+	}
+
+	days := s.Plan(places, PlanOptions{
+		Date:        planDate(),
+		Days:        2,
+		StopsPerDay: 3,
+		HasStart:    true,
+		Latitude:    41.30,
+		Longitude:   69.28,
+	})
+	if len(days) != 2 {
+		t.Fatalf("planned %d days, want 2", len(days))
+	}
+
+	var ids []string
+	for _, stop := range days[0].Stops {
+		ids = append(ids, stop.EstablishmentID)
+	}
+	if want := []string{"near", "middle", "far"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("stops %v, want %v", ids, want)
+	}
+	for i, stop := range days[0].Stops {
+		if i == 0 {
+			if stop.WalkMinutes != 0 || stop.WalkKm != 0 {
+				t.Errorf("first stop has a walk of %d minutes", stop.WalkMinutes)
+			}
+			continue
+		}
+		prev := days[0].Stops[i-1]
+		if stop.StartsAt.Before(prev.EndsAt.Add(time.Duration(stop.WalkMinutes) * time.Minute)) {
+			t.Errorf("%s starts at %s before the walk from %s", stop.EstablishmentID, stop.StartsAt.Format("15:04"), prev.EstablishmentID)
+		}
+		if stop.WalkMinutes == 0 {
+			t.Errorf("%s has no walk from %s", stop.EstablishmentID, prev.EstablishmentID)
+		}
+	}
+
+	// every place is visited once over the trip
+	if len(days[1].Stops) != 0 {
+		t.Errorf("second day has %d stops, want none", len(days[1].Stops))
+	}
+	if days[1].Date != "2026-05-05" {
+		t.Errorf("second day is %s, want 2026-05-05", days[1].Date)
+	}
+}
+
+func TestPlanMeals(t *testing.T) {
+	s := NewItineraryService(time.Second, &fakeItineraryRepo{}).(*itineraryService)
+	places := []*Place{
+		{Category: "restaurant", EstablishmentID: "cafe", Rating: 4, Meal: true},
+		{Category: "restaurant", EstablishmentID: "plov", Rating: 5, Meal: true},
+	}
+
+	days := s.Plan(places, PlanOptions{Date: planDate(), Days: 1, StopsPerDay: 3})
+	want := []string{"12:00-13:00 plov", "18:30-20:00 cafe"}
+	if got := spans(days[0]); !reflect.DeepEqual(got, want) {
+		t.Errorf("stops %v, want %v", got, want)
+	}
+}
+
+func TestPlanClosingTime(t *testing.T) {
+	tests := []struct {
+		name  string
+		place *Place
+		want  []string
+	}{
+		{
+			name:  "closes before the visit ends",
+			place: &Place{EstablishmentID: "museum", Hours: daily(t, "09:00-10:00")},
+		},
+		{
+			name:  "closes as the visit ends",
+			place: &Place{EstablishmentID: "museum", Hours: daily(t, "09:00-10:30")},
+			want:  []string{"09:00-10:30 museum"},
+		},
+		{
+			name:  "closed all day",
+			place: &Place{EstablishmentID: "museum", Hours: daily(t, openinghours.Closed)},
+		},
+		{
+			name:  "shorter visit fits",
+			place: &Place{EstablishmentID: "museum", Hours: daily(t, "09:00-10:00"), Visit: time.Hour},
+			want:  []string{"09:00-10:00 museum"},
+		},
+		{
+			name:  "restaurant closes before lunch is over",
+			place: &Place{EstablishmentID: "cafe", Meal: true, Hours: daily(t, "08:00-12:30")},
+		},
+		{
+			name:  "restaurant open for dinner only",
+			place: &Place{EstablishmentID: "cafe", Meal: true, Hours: daily(t, "18:00-23:00")},
+			want:  []string{"18:30-20:00 cafe"},
+		},
+		{
+			name:  "slot counted from the opening",
+			place: &Place{EstablishmentID: "tower", Hours: daily(t, "09:10-17:00"), Slot: 30 * time.Minute},
+			want:  []string{"09:10-09:40 tower"},
+		},
+		{
+			name:  "slot starts on the next boundary",
+			place: &Place{EstablishmentID: "tower", Hours: daily(t, "08:00-17:00"), Slot: 45 * time.Minute},
+			want:  []string{"09:30-10:15 tower"},
+		},
+		{
+			name:  "slot taken in the next window",
+			place: &Place{EstablishmentID: "tower", Hours: daily(t, "07:00-08:00,10:05-17:00"), Slot: time.Hour},
+			want:  []string{"10:05-11:05 tower"},
+		},
+		{
+			name:  "last slot runs past closing",
+			place: &Place{EstablishmentID: "tower", Hours: daily(t, "08:00-10:00"), Slot: 45 * time.Minute},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewItineraryService(time.Second, &fakeItineraryRepo{}).(*itineraryService)
+			days := s.Plan([]*Place{tt.place}, PlanOptions{Date: planDate(), Days: 1, StopsPerDay: 3})
+
+			got := spans(days[0])
+			if len(got) == 0 && len(tt.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("stops %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSave(t *testing.T) {
+	r := &fakeItineraryRepo{}
+	s := NewItineraryService(time.Second, r)
+
+	date := planDate()
+	m := &entity.Itinerary{
+		UserID: "user",
+		Name:   "  Tashkent weekend ",
+		People: 2,
+		Days: []*entity.ItineraryDay{{
+			Date: "2026-05-04",
+			Stops: []*entity.ItineraryStop{
+				{Category: "attraction", EstablishmentID: "tower", Latitude: 41.30, Longitude: 69.28, StartsAt: date.Add(9 * time.Hour), EndsAt: date.Add(10 * time.Hour)},
+				{Category: "restaurant", EstablishmentID: "plov", Latitude: 41.31, Longitude: 69.28, StartsAt: date.Add(12 * time.Hour), EndsAt: date.Add(13 * time.Hour)},
+			},
+		}},
+	}
+	if err := s.Save(context.Background(), m); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	if len(r.created) != 1 || r.created[0] != m {
+		t.Fatalf("created %d itineraries, want the saved one", len(r.created))
+	}
+	if m.ID == "" || m.Name != "Tashkent weekend" {
+		t.Errorf("saved id %q name %q", m.ID, m.Name)
+	}
+	if walk := m.Days[0].Stops[1]; walk.WalkKm == 0 || walk.WalkMinutes == 0 {
+		t.Errorf("second stop has no walk: %v km, %d minutes", walk.WalkKm, walk.WalkMinutes)
+	}
+}
+
+func TestSaveInvalid(t *testing.T) {
+	date := planDate()
+	stop := func(change func(*entity.ItineraryStop)) *entity.Itinerary {
+		s := entity.ItineraryStop{
+			Category:        "attraction",
+			EstablishmentID: "tower",
+			StartsAt:        date.Add(9 * time.Hour),
+			EndsAt:          date.Add(10 * time.Hour),
+		}
+		if change != nil {
+			change(&s)
+		}
+		return &entity.Itinerary{
+			People: 1,
+			Days:   []*entity.ItineraryDay{{Date: "2026-05-04", Stops: []*entity.ItineraryStop{&s}}},
+		}
+	}
+
+	tests := []struct {
+		name string
+		m    *entity.Itinerary
+	}{
+		{name: "no days", m: &entity.Itinerary{People: 1}},
+		{name: "too many days", m: &entity.Itinerary{People: 1, Days: make([]*entity.ItineraryDay, maxDays+1)}},
+		{name: "nobody", m: func() *entity.Itinerary { m := stop(nil); m.People = 0; return m }()},
+		{name: "bad date", m: func() *entity.Itinerary { m := stop(nil); m.Days[0].Date = "04.05.2026"; return m }()},
+		{name: "hotel stop", m: stop(func(s *entity.ItineraryStop) { s.Category = "hotel" })},
+		{name: "no establishment", m: stop(func(s *entity.ItineraryStop) { s.EstablishmentID = "" })},
+		{name: "ends before it starts", m: stop(func(s *entity.ItineraryStop) { s.EndsAt = s.StartsAt })},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &fakeItineraryRepo{}
+			err := NewItineraryService(time.Second, r).Save(context.Background(), tt.m)
+
+			var errBadRequest *errorspkg.ErrBadRequest
+			if !errors.As(err, &errBadRequest) {
+				t.Errorf("got %v, want a bad request", err)
+			}
+			if len(r.created) > 0 {
+				t.Error("an invalid itinerary was saved")
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package loyalty
+
+import (
+	"context"
+
+	"Booking/api-service-booking/internal/entity"
+)
+
+type Loyalty interface {
+	// Earn credits the points for a completed booking once, returning how
+	// many were credited
+	Earn(ctx context.Context, record *entity.BookingRecord) (int64, error)
+	// Redeem spends points on a booking and returns what they are worth
+	Redeem(ctx context.Context, userID, bookingID string, points int64) (int64, error)
+	// Refund gives back the points spent on a booking that was canceled
+	Refund(ctx context.Context, bookingID string) error
+	Adjust(ctx context.Context, userID, adminID string, points int64, reason string) (*entity.LoyaltyEntry, error)
+	Balance(ctx context.Context, userID string) (int64, error)
+	History(ctx context.Context, userID string, limit, offset uint64) ([]*entity.LoyaltyEntry, uint64, error)
+	// Worth is what points take off a booking price
+	Worth(points int64) int64
+}
@@ -0,0 +1,173 @@
+package loyalty
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Booking/api-service-booking/internal/entity"
+	errorspkg "Booking/api-service-booking/internal/errors"
+	"Booking/api-service-booking/internal/infrastructure/repository/postgresql/repo"
+	"Booking/api-service-booking/internal/pkg/i18n"
+)
+
+type Options struct {
+	// EarnPer is how much has to be paid for one point
+	EarnPer int64
+	// PointValue is what one point takes off a price
+	PointValue int64
+	// PointsTTL is how long earned points can be spent
+	PointsTTL time.Duration
+}
+
+type loyaltyService struct {
+	ctxTimeout time.Duration
+	repo       repo.LoyaltyRepo
+	options    Options
+}
+
+func NewLoyaltyService(ctxTimeout time.Duration, repo repo.LoyaltyRepo, options Options) Loyalty {
+	return &loyaltyService{
+		ctxTimeout: ctxTimeout,
+		repo:       repo,
+		options:    options,
+	}
+}
+
+func (r *loyaltyService) newEntry(userID, kind string, points int64) *entity.LoyaltyEntry {
+	return &entity.LoyaltyEntry{
+		ID:        uuid.NewString(),
+		UserID:    userID,
+		Kind:      kind,
+		Points:    points,
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+func (r *loyaltyService) expiry(userID string) *entity.LoyaltyEntry {
+	entry := r.newEntry(userID, entity.LoyaltyKindExpire, 0)
+	entry.Reason = "points expired"
+	return entry
+}
+
+func (r *loyaltyService) Earn(ctx context.Context, record *entity.BookingRecord) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	if r.options.EarnPer <= 0 {
+		return 0, nil
+	}
+	paid := record.Price - record.Discount - record.PointsDiscount
+	points := paid / r.options.EarnPer
+	if points <= 0 {
+		return 0, nil
+	}
+
+	entry := r.newEntry(record.UserID, entity.LoyaltyKindEarn, points)
+	entry.BookingID = record.ID
+	entry.Reason = "completed " + record.Category + " booking"
+	expiresAt := entry.CreatedAt.Add(r.options.PointsTTL)
+	entry.ExpiresAt = &expiresAt
+
+	err := r.repo.Append(ctx, entry, r.expiry(record.UserID))
+	if errors.Is(err, errorspkg.ErrorConflict) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return points, nil
+}
+
+func (r *loyaltyService) Redeem(ctx context.Context, userID, bookingID string, points int64) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	if points <= 0 {
+		return 0, errorspkg.NewErrBadRequest(i18n.NewError("field_positive", "points"))
+	}
+
+	entry := r.newEntry(userID, entity.LoyaltyKindRedeem, -points)
+	entry.BookingID = bookingID
+	entry.Reason = "spent on a booking"
+
+	err := r.repo.Append(ctx, entry, r.expiry(userID))
+	if errors.Is(err, errorspkg.ErrorNotAvailable) {
+		return 0, errorspkg.NewErrBadRequest(i18n.NewError("not_enough_points"))
+	}
+	if err != nil {
+		return 0, err
+	}
+	return r.Worth(points), nil
+}
+
+func (r *loyaltyService) Refund(ctx context.Context, bookingID string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	redeemed, err := r.repo.GetByBooking(ctx, bookingID, entity.LoyaltyKindRedeem)
+	if errors.Is(err, errorspkg.ErrorNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// a refund is an adjustment, the ledger is never edited
+	entry := r.newEntry(redeemed.UserID, entity.LoyaltyKindAdjust, -redeemed.Points)
+	entry.BookingID = bookingID
+	entry.Reason = "booking canceled"
+
+	err = r.repo.Append(ctx, entry, r.expiry(redeemed.UserID))
+	if errors.Is(err, errorspkg.ErrorConflict) {
+		return nil
+	}
+	return err
+}
+
+func (r *loyaltyService) Adjust(ctx context.Context, userID, adminID string, points int64, reason string) (*entity.LoyaltyEntry, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	reason = strings.TrimSpace(reason)
+	switch {
+	case reason == "":
+		return nil, errorspkg.NewErrBadRequest(i18n.NewError("field_required", "reason"))
+	case points == 0:
+		return nil, errorspkg.NewErrBadRequest(i18n.NewError("field_not_zero", "points"))
+	}
+
+	entry := r.newEntry(userID, entity.LoyaltyKindAdjust, points)
+	entry.Reason = reason
+	entry.CreatedBy = adminID
+
+	err := r.repo.Append(ctx, entry, r.expiry(userID))
+	if errors.Is(err, errorspkg.ErrorNotAvailable) {
+		return nil, errorspkg.NewErrBadRequest(i18n.NewError("user_points_short"))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (r *loyaltyService) Balance(ctx context.Context, userID string) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.Balance(ctx, r.expiry(userID))
+}
+
+func (r *loyaltyService) History(ctx context.Context, userID string, limit, offset uint64) ([]*entity.LoyaltyEntry, uint64, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.History(ctx, userID, limit, offset)
+}
+
+func (r *loyaltyService) Worth(points int64) int64 {
+	return points * r.options.PointValue
+}
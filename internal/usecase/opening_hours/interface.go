@@ -0,0 +1,21 @@
+package opening_hours
+
+import (
+	"context"
+	"time"
+
+	"Booking/api-service-booking/internal/entity"
+	"Booking/api-service-booking/internal/pkg/openinghours"
+)
+
+type OpeningHours interface {
+	Save(ctx context.Context, m *entity.OpeningSchedule) error
+	Get(ctx context.Context, category, establishmentID string) (*entity.OpeningSchedule, error)
+	Delete(ctx context.Context, category, establishmentID string) error
+	// Schedules returns the schedules of those of ids that have one, by id
+	Schedules(ctx context.Context, category string, ids []string) (map[string]*openinghours.Schedule, error)
+	// OpenAt returns the ids of the establishments of category open at at.
+	// Those without a schedule are judged by the plain opening hours the
+	// search index keeps of them.
+	OpenAt(ctx context.Context, category string, at time.Time) ([]string, error)
+}
@@ -0,0 +1,118 @@
+package opening_hours
+
+import (
+	"context"
+	"time"
+
+	"Booking/api-service-booking/internal/entity"
+	errorspkg "Booking/api-service-booking/internal/errors"
+	"Booking/api-service-booking/internal/infrastructure/repository/postgresql/repo"
+	"Booking/api-service-booking/internal/pkg/i18n"
+	"Booking/api-service-booking/internal/pkg/openinghours"
+)
+
+var categories = []string{"hotel", "restaurant", "attraction"}
+
+type openingHoursService struct {
+	ctxTimeout time.Duration
+	repo       repo.OpeningScheduleRepo
+	documents  repo.SearchDocumentRepo
+}
+
+func NewOpeningHoursService(ctxTimeout time.Duration, repo repo.OpeningScheduleRepo, documents repo.SearchDocumentRepo) OpeningHours {
+	return &openingHoursService{
+		ctxTimeout: ctxTimeout,
+		repo:       repo,
+		documents:  documents,
+	}
+}
+
+func (r *openingHoursService) Save(ctx context.Context, m *entity.OpeningSchedule) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	if !isCategory(m.Category) {
+		return errorspkg.NewErrBadRequest(i18n.NewError("unknown_category", m.Category))
+	}
+	if m.Schedule == nil {
+		return errorspkg.NewErrBadRequest(i18n.NewError("field_required", "schedule"))
+	}
+	if err := m.Schedule.Validate(); err != nil {
+		return errorspkg.NewErrBadRequest(err)
+	}
+
+	m.UpdatedAt = time.Now().UTC()
+	return r.repo.Save(ctx, m)
+}
+
+func (r *openingHoursService) Get(ctx context.Context, category, establishmentID string) (*entity.OpeningSchedule, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.Get(ctx, category, establishmentID)
+}
+
+func (r *openingHoursService) Delete(ctx context.Context, category, establishmentID string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.Delete(ctx, category, establishmentID)
+}
+
+func (r *openingHoursService) Schedules(ctx context.Context, category string, ids []string) (map[string]*openinghours.Schedule, error) {
+	schedules := make(map[string]*openinghours.Schedule)
+	if len(ids) == 0 {
+		return schedules, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	list, err := r.repo.List(ctx, category, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, schedule := range list {
+		schedules[schedule.EstablishmentID] = schedule.Schedule
+	}
+	return schedules, nil
+}
+
+func (r *openingHoursService) OpenAt(ctx context.Context, category string, at time.Time) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	schedules, err := r.repo.List(ctx, category, nil)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := r.documents.OpeningHours(ctx, category)
+	if err != nil {
+		return nil, err
+	}
+
+	// not nil even when none is open, an empty filter keeps nothing
+	ids := []string{}
+	for _, schedule := range schedules {
+		delete(plain, schedule.EstablishmentID)
+		if schedule.Schedule.OpenAt(at) {
+			ids = append(ids, schedule.EstablishmentID)
+		}
+	}
+	// hours that do not parse were written before they were checked
+	for id, value := range plain {
+		if hours, err := openinghours.Parse(value); err == nil && openinghours.Daily(hours).OpenAt(at) {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func isCategory(category string) bool {
+	for _, c := range categories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
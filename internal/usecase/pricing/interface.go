@@ -0,0 +1,16 @@
+package pricing
+
+import (
+	"context"
+	"time"
+
+	"Booking/api-service-booking/internal/entity"
+)
+
+type Pricing interface {
+	SaveRate(ctx context.Context, m *entity.Rate) error
+	GetRate(ctx context.Context, category, establishmentID string) (*entity.Rate, error)
+	// Price quotes a booking before discounts. Tickets, when given, are
+	// charged at their own prices instead of the rate.
+	Price(ctx context.Context, category, establishmentID string, arriveAt, leaveAt time.Time, people int64, tickets []*entity.Ticket) (int64, error)
+}
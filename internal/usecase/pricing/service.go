@@ -0,0 +1,82 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"Booking/api-service-booking/internal/entity"
+	errorspkg "Booking/api-service-booking/internal/errors"
+	"Booking/api-service-booking/internal/infrastructure/repository/postgresql/repo"
+	"Booking/api-service-booking/internal/pkg/i18n"
+)
+
+type pricingService struct {
+	ctxTimeout time.Duration
+	repo       repo.RateRepo
+}
+
+func NewPricingService(ctxTimeout time.Duration, repo repo.RateRepo) Pricing {
+	return &pricingService{
+		ctxTimeout: ctxTimeout,
+		repo:       repo,
+	}
+}
+
+func (r *pricingService) SaveRate(ctx context.Context, m *entity.Rate) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	if m.Price < 0 {
+		return errorspkg.NewErrBadRequest(i18n.NewError("field_not_negative", "price"))
+	}
+
+	m.UpdatedAt = time.Now().UTC()
+	return r.repo.Save(ctx, m)
+}
+
+func (r *pricingService) GetRate(ctx context.Context, category, establishmentID string) (*entity.Rate, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.Get(ctx, category, establishmentID)
+}
+
+func (r *pricingService) Price(ctx context.Context, category, establishmentID string, arriveAt, leaveAt time.Time, people int64, tickets []*entity.Ticket) (int64, error) {
+	if len(tickets) > 0 {
+		var price int64
+		for _, ticket := range tickets {
+			price += ticket.Price
+		}
+		return price, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	// establishments without a rate are not charged through us
+	rate, err := r.repo.Get(ctx, category, establishmentID)
+	if errors.Is(err, errorspkg.ErrorNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if category == "hotel" {
+		return rate.Price * nights(arriveAt, leaveAt), nil
+	}
+	return rate.Price * people, nil
+}
+
+// nights counts calendar days between arrival and departure, at least one
+func nights(arriveAt, leaveAt time.Time) int64 {
+	arrive := time.Date(arriveAt.Year(), arriveAt.Month(), arriveAt.Day(), 0, 0, 0, 0, time.UTC)
+	leave := time.Date(leaveAt.Year(), leaveAt.Month(), leaveAt.Day(), 0, 0, 0, 0, time.UTC)
+
+	n := int64(leave.Sub(arrive).Hours() / 24)
+	if n < 1 {
+		return 1
+	}
+	return n
+}
@@ -0,0 +1,25 @@
+package promotion
+
+import (
+	"context"
+
+	"Booking/api-service-booking/internal/entity"
+)
+
+type Promotion interface {
+	Create(ctx context.Context, m *entity.Promotion) error
+	Get(ctx context.Context, id string) (*entity.Promotion, error)
+	List(ctx context.Context, limit, offset uint64) ([]*entity.Promotion, uint64, error)
+	Update(ctx context.Context, m *entity.Promotion) error
+	Delete(ctx context.Context, id string) error
+	// Check finds the promotion behind code and the discount it gives the
+	// booking described by pc, ErrBadRequest saying why when it does not apply
+	Check(ctx context.Context, code string, pc *entity.PromoContext) (*entity.Promotion, int64, error)
+	// Redeem takes one use of promotion for a booking, ErrBadRequest once
+	// its limits are reached
+	Redeem(ctx context.Context, promotion *entity.Promotion, userID, bookingID string, discount int64) error
+	// Rediscount is the discount the promo code of a booking gives once its
+	// price changed, zero when it has none
+	Rediscount(ctx context.Context, bookingID string, price int64) (int64, error)
+	Release(ctx context.Context, bookingID string) error
+}
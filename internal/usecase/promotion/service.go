@@ -0,0 +1,205 @@
+package promotion
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Booking/api-service-booking/internal/entity"
+	errorspkg "Booking/api-service-booking/internal/errors"
+	"Booking/api-service-booking/internal/infrastructure/repository/postgresql/repo"
+	"Booking/api-service-booking/internal/pkg/i18n"
+)
+
+var errUsedUp = i18n.NewError("promo_used_up")
+
+type promotionService struct {
+	ctxTimeout time.Duration
+	repo       repo.PromotionRepo
+}
+
+func NewPromotionService(ctxTimeout time.Duration, repo repo.PromotionRepo) Promotion {
+	return &promotionService{
+		ctxTimeout: ctxTimeout,
+		repo:       repo,
+	}
+}
+
+func (r *promotionService) validate(m *entity.Promotion) error {
+	m.Code = strings.ToUpper(strings.TrimSpace(m.Code))
+	switch {
+	case m.Code == "":
+		return i18n.NewError("field_required", "code")
+	case m.Kind != entity.PromoKindPercent && m.Kind != entity.PromoKindFixed:
+		return i18n.NewError("field_one_of", "kind", "percent, fixed")
+	case m.Value <= 0:
+		return i18n.NewError("field_positive", "value")
+	case m.Kind == entity.PromoKindPercent && m.Value > 100:
+		return i18n.NewError("percent_over_100")
+	case m.MinPrice < 0 || m.MaxRedemptions < 0 || m.PerUserLimit < 0:
+		return i18n.NewError("field_not_negative", "limits")
+	case !m.EndsAt.After(m.StartsAt):
+		return i18n.NewError("field_after", "ends_at", "starts_at")
+	}
+	return nil
+}
+
+func (r *promotionService) beforeCreate(m *entity.Promotion) {
+	m.ID = uuid.NewString()
+	m.Redemptions = 0
+	m.CreatedAt = time.Now().UTC()
+	m.UpdatedAt = time.Now().UTC()
+}
+
+func (r *promotionService) beforeUpdate(m *entity.Promotion) {
+	m.UpdatedAt = time.Now().UTC()
+}
+
+func (r *promotionService) Create(ctx context.Context, m *entity.Promotion) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	if err := r.validate(m); err != nil {
+		return errorspkg.NewErrBadRequest(err)
+	}
+
+	r.beforeCreate(m)
+	return r.repo.Create(ctx, m)
+}
+
+func (r *promotionService) Get(ctx context.Context, id string) (*entity.Promotion, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.Get(ctx, id)
+}
+
+func (r *promotionService) List(ctx context.Context, limit, offset uint64) ([]*entity.Promotion, uint64, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.List(ctx, limit, offset)
+}
+
+func (r *promotionService) Update(ctx context.Context, m *entity.Promotion) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	if err := r.validate(m); err != nil {
+		return errorspkg.NewErrBadRequest(err)
+	}
+
+	r.beforeUpdate(m)
+	return r.repo.Update(ctx, m)
+}
+
+func (r *promotionService) Delete(ctx context.Context, id string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.Delete(ctx, id, time.Now().UTC())
+}
+
+func (r *promotionService) Check(ctx context.Context, code string, pc *entity.PromoContext) (*entity.Promotion, int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	promotion, err := r.repo.GetByCode(ctx, strings.TrimSpace(code))
+	if errors.Is(err, errorspkg.ErrorNotFound) {
+		return nil, 0, errorspkg.NewErrBadRequest(i18n.NewError("promo_unknown"))
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	now := time.Now().UTC()
+	switch {
+	case now.Before(promotion.StartsAt):
+		return nil, 0, errorspkg.NewErrBadRequest(i18n.NewError("promo_not_active"))
+	case !now.Before(promotion.EndsAt):
+		return nil, 0, errorspkg.NewErrBadRequest(i18n.NewError("promo_expired"))
+	case promotion.Category != "" && promotion.Category != pc.Category:
+		return nil, 0, errorspkg.NewErrBadRequest(i18n.NewError("promo_wrong_category", pc.Category))
+	case promotion.City != "" && !strings.EqualFold(promotion.City, pc.City):
+		return nil, 0, errorspkg.NewErrBadRequest(i18n.NewError("promo_wrong_city", promotion.City))
+	case promotion.FirstBookingOnly && !pc.FirstBooking:
+		return nil, 0, errorspkg.NewErrBadRequest(i18n.NewError("promo_first_booking"))
+	case pc.Price < promotion.MinPrice:
+		return nil, 0, errorspkg.NewErrBadRequest(i18n.NewError("promo_below_minimum"))
+	case promotion.MaxRedemptions > 0 && promotion.Redemptions >= promotion.MaxRedemptions:
+		return nil, 0, errorspkg.NewErrBadRequest(errUsedUp)
+	}
+
+	if promotion.PerUserLimit > 0 {
+		used, err := r.repo.CountRedemptions(ctx, promotion.ID, pc.UserID)
+		if err != nil {
+			return nil, 0, err
+		}
+		if used >= promotion.PerUserLimit {
+			return nil, 0, errorspkg.NewErrBadRequest(i18n.NewError("promo_already_used"))
+		}
+	}
+
+	return promotion, promotion.Discount(pc.Price), nil
+}
+
+func (r *promotionService) Redeem(ctx context.Context, promotion *entity.Promotion, userID, bookingID string, discount int64) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	err := r.repo.Redeem(ctx, &entity.PromoRedemption{
+		ID:           uuid.NewString(),
+		PromotionID:  promotion.ID,
+		UserID:       userID,
+		BookingID:    bookingID,
+		Discount:     discount,
+		FirstBooking: promotion.FirstBookingOnly,
+		CreatedAt:    time.Now().UTC(),
+	}, promotion.MaxRedemptions, promotion.PerUserLimit)
+	switch {
+	case errors.Is(err, errorspkg.ErrorNotAvailable):
+		return errorspkg.NewErrBadRequest(errUsedUp)
+	case errors.Is(err, errorspkg.ErrorLimitReached):
+		return errorspkg.NewErrBadRequest(i18n.NewError("promo_already_used"))
+	case errors.Is(err, errorspkg.ErrorConflict):
+		return errorspkg.NewErrBadRequest(i18n.NewError("promo_first_booking"))
+	}
+	return err
+}
+
+func (r *promotionService) Rediscount(ctx context.Context, bookingID string, price int64) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	redemption, err := r.repo.GetRedemption(ctx, bookingID)
+	if errors.Is(err, errorspkg.ErrorNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	// a promotion deleted since keeps what it gave, up to the new price
+	promotion, err := r.repo.Get(ctx, redemption.PromotionID)
+	if errors.Is(err, errorspkg.ErrorNotFound) {
+		if redemption.Discount > price {
+			return price, nil
+		}
+		return redemption.Discount, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return promotion.Discount(price), nil
+}
+
+func (r *promotionService) Release(ctx context.Context, bookingID string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.Release(ctx, bookingID)
+}
@@ -0,0 +1,30 @@
+package restaurant_table
+
+import (
+	"context"
+	"time"
+
+	"Booking/api-service-booking/internal/entity"
+	"Booking/api-service-booking/internal/pkg/openinghours"
+)
+
+type RestaurantTable interface {
+	Create(ctx context.Context, m *entity.RestaurantTable) error
+	List(ctx context.Context, restaurantID string) ([]*entity.RestaurantTable, error)
+	Delete(ctx context.Context, id string) error
+	// FreeSlots lists seatings on date that fit partySize within the
+	// windows schedule has for it
+	FreeSlots(ctx context.Context, restaurantID string, schedule *openinghours.Schedule, date time.Time, partySize int) ([]*entity.TimeSlot, error)
+	// Seatings is the most seatings one table takes on date back to back
+	// with the cleaning gap between them, within the windows of schedule
+	Seatings(schedule *openinghours.Schedule, date time.Time) int
+	// FreeTables counts the tables that seat partySize and are free for a
+	// seating starting at startsAt
+	FreeTables(ctx context.Context, restaurantID string, startsAt time.Time, partySize int) (int, error)
+	// Reserve assigns a table for a seating starting at m.StartsAt, moving
+	// the booking off the table it already has
+	Reserve(ctx context.Context, schedule *openinghours.Schedule, m *entity.TableReservation) error
+	// GetReservation returns the table held for a booking
+	GetReservation(ctx context.Context, bookingID string) (*entity.TableReservation, error)
+	Release(ctx context.Context, bookingID string) error
+}
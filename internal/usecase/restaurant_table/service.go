@@ -0,0 +1,200 @@
+package restaurant_table
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Booking/api-service-booking/internal/entity"
+	errorspkg "Booking/api-service-booking/internal/errors"
+	"Booking/api-service-booking/internal/infrastructure/repository/postgresql/repo"
+	"Booking/api-service-booking/internal/pkg/i18n"
+	"Booking/api-service-booking/internal/pkg/openinghours"
+)
+
+type Options struct {
+	// SlotLength is how long one seating keeps a table
+	SlotLength time.Duration
+	// SlotStep is the distance between two possible seating starts
+	SlotStep time.Duration
+	// Turnover is the cleaning gap kept free between two seatings
+	Turnover time.Duration
+}
+
+type restaurantTableService struct {
+	ctxTimeout time.Duration
+	repo       repo.RestaurantTableRepo
+	options    Options
+}
+
+func NewRestaurantTableService(ctxTimeout time.Duration, repo repo.RestaurantTableRepo, options Options) RestaurantTable {
+	return &restaurantTableService{
+		ctxTimeout: ctxTimeout,
+		repo:       repo,
+		options:    options,
+	}
+}
+
+func (r *restaurantTableService) beforeCreate(m *entity.RestaurantTable) {
+	m.ID = uuid.NewString()
+	m.CreatedAt = time.Now().UTC()
+	m.UpdatedAt = time.Now().UTC()
+}
+
+func (r *restaurantTableService) Create(ctx context.Context, m *entity.RestaurantTable) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	r.beforeCreate(m)
+	return r.repo.Create(ctx, m)
+}
+
+func (r *restaurantTableService) List(ctx context.Context, restaurantID string) ([]*entity.RestaurantTable, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.List(ctx, restaurantID)
+}
+
+func (r *restaurantTableService) Delete(ctx context.Context, id string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.Delete(ctx, id, time.Now().UTC())
+}
+
+func (r *restaurantTableService) FreeSlots(ctx context.Context, restaurantID string, schedule *openinghours.Schedule, date time.Time, partySize int) ([]*entity.TimeSlot, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	slots := []*entity.TimeSlot{}
+	windows := schedule.Windows(date)
+	if len(windows) == 0 {
+		return slots, nil
+	}
+
+	tables, err := r.repo.List(ctx, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	from, to := windows[0][0], windows[0][1]
+	for _, window := range windows[1:] {
+		if window[1].After(to) {
+			to = window[1]
+		}
+	}
+	reservations, err := r.repo.ListReservations(ctx, restaurantID, from.Add(-r.options.Turnover), to.Add(r.options.Turnover))
+	if err != nil {
+		return nil, err
+	}
+
+	byTable := groupByTable(reservations)
+	for _, window := range windows {
+		open, closeAt := window[0], window[1]
+		for start := open; !start.Add(r.options.SlotLength).After(closeAt); start = start.Add(r.options.SlotStep) {
+			slot := entity.TimeSlot{
+				StartsAt: start,
+				EndsAt:   start.Add(r.options.SlotLength),
+			}
+			slot.FreeTables = r.freeTables(tables, byTable, slot.StartsAt, slot.EndsAt, partySize)
+			if slot.FreeTables > 0 {
+				slots = append(slots, &slot)
+			}
+		}
+	}
+
+	return slots, nil
+}
+
+func (r *restaurantTableService) Seatings(schedule *openinghours.Schedule, date time.Time) int {
+	if r.options.SlotLength <= 0 {
+		return 0
+	}
+
+	seatings := 0
+	for _, window := range schedule.Windows(date) {
+		length := window[1].Sub(window[0])
+		if length >= r.options.SlotLength {
+			seatings += int((length + r.options.Turnover) / (r.options.SlotLength + r.options.Turnover))
+		}
+	}
+	return seatings
+}
+
+func (r *restaurantTableService) FreeTables(ctx context.Context, restaurantID string, startsAt time.Time, partySize int) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	tables, err := r.repo.List(ctx, restaurantID)
+	if err != nil {
+		return 0, err
+	}
+
+	endsAt := startsAt.Add(r.options.SlotLength)
+	reservations, err := r.repo.ListReservations(ctx, restaurantID, startsAt.Add(-r.options.Turnover), endsAt.Add(r.options.Turnover))
+	if err != nil {
+		return 0, err
+	}
+
+	return r.freeTables(tables, groupByTable(reservations), startsAt, endsAt, partySize), nil
+}
+
+// freeTables counts the tables that seat partySize and are free for
+// [start, end)
+func (r *restaurantTableService) freeTables(tables []*entity.RestaurantTable, byTable map[string][]*entity.TableReservation, start, end time.Time, partySize int) int {
+	free := 0
+	for _, table := range tables {
+		if table.Capacity >= partySize && r.isFree(byTable[table.ID], start, end) {
+			free++
+		}
+	}
+	return free
+}
+
+func groupByTable(reservations []*entity.TableReservation) map[string][]*entity.TableReservation {
+	byTable := make(map[string][]*entity.TableReservation)
+	for _, reservation := range reservations {
+		byTable[reservation.TableID] = append(byTable[reservation.TableID], reservation)
+	}
+	return byTable
+}
+
+func (r *restaurantTableService) isFree(reservations []*entity.TableReservation, start, end time.Time) bool {
+	for _, reservation := range reservations {
+		if reservation.StartsAt.Before(end.Add(r.options.Turnover)) && reservation.EndsAt.After(start.Add(-r.options.Turnover)) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *restaurantTableService) Reserve(ctx context.Context, schedule *openinghours.Schedule, m *entity.TableReservation) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	m.EndsAt = m.StartsAt.Add(r.options.SlotLength)
+	if !schedule.Covers(m.StartsAt, m.EndsAt) {
+		return errorspkg.NewErrBadRequest(i18n.NewError("restaurant_closed_at", m.StartsAt.Format("15:04")))
+	}
+
+	m.ID = uuid.NewString()
+	m.CreatedAt = time.Now().UTC()
+
+	return r.repo.Reserve(ctx, m, r.options.Turnover)
+}
+
+func (r *restaurantTableService) GetReservation(ctx context.Context, bookingID string) (*entity.TableReservation, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.GetReservation(ctx, bookingID)
+}
+
+func (r *restaurantTableService) Release(ctx context.Context, bookingID string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.DeleteReservation(ctx, bookingID)
+}
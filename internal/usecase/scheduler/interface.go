@@ -0,0 +1,21 @@
+package scheduler
+
+import (
+	"context"
+
+	"Booking/api-service-booking/internal/entity"
+)
+
+// Handler runs one job, a returned error makes the job retry later
+type Handler func(ctx context.Context, job *entity.Job) error
+
+type Scheduler interface {
+	Schedule(ctx context.Context, m *entity.Job) error
+	CancelByBooking(ctx context.Context, bookingID string) error
+	// CancelByPayload cancels the pending jobs of kind that carry value under
+	// key in their payload, for jobs about something other than a booking
+	CancelByPayload(ctx context.Context, kind, key, value string) error
+	List(ctx context.Context, status string, limit, offset uint64) ([]*entity.Job, uint64, error)
+	Handle(kind string, handler Handler)
+	Run(ctx context.Context)
+}
@@ -0,0 +1,188 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"Booking/api-service-booking/internal/entity"
+	"Booking/api-service-booking/internal/infrastructure/repository/postgresql/repo"
+	redisrepo "Booking/api-service-booking/internal/infrastructure/repository/redis"
+	l "Booking/api-service-booking/internal/pkg/logger"
+)
+
+const (
+	lockKey   = "scheduler:lock"
+	batchSize = 50
+)
+
+type Options struct {
+	Interval    time.Duration
+	LockTTL     time.Duration
+	MaxAttempts int
+}
+
+type schedulerService struct {
+	ctxTimeout time.Duration
+	repo       repo.JobRepo
+	locker     redisrepo.Locker
+	logger     *zap.Logger
+	options    Options
+
+	mu       sync.RWMutex
+	handlers map[string]Handler
+}
+
+func NewSchedulerService(ctxTimeout time.Duration, repo repo.JobRepo, locker redisrepo.Locker, logger *zap.Logger, options Options) Scheduler {
+	return &schedulerService{
+		ctxTimeout: ctxTimeout,
+		repo:       repo,
+		locker:     locker,
+		logger:     logger,
+		options:    options,
+		handlers:   make(map[string]Handler),
+	}
+}
+
+func (r *schedulerService) beforeCreate(m *entity.Job) {
+	if m.ID == "" {
+		m.ID = uuid.NewString()
+	}
+	if m.Payload == nil {
+		m.Payload = map[string]string{}
+	}
+	m.Status = entity.JobStatusPending
+	m.CreatedAt = time.Now().UTC()
+	m.UpdatedAt = time.Now().UTC()
+}
+
+func (r *schedulerService) Schedule(ctx context.Context, m *entity.Job) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	r.beforeCreate(m)
+	return r.repo.Create(ctx, m)
+}
+
+func (r *schedulerService) CancelByBooking(ctx context.Context, bookingID string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.CancelByBooking(ctx, bookingID, time.Now().UTC())
+}
+
+func (r *schedulerService) CancelByPayload(ctx context.Context, kind, key, value string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.CancelByPayload(ctx, kind, key, value, time.Now().UTC())
+}
+
+func (r *schedulerService) List(ctx context.Context, status string, limit, offset uint64) ([]*entity.Job, uint64, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.List(ctx, status, limit, offset)
+}
+
+func (r *schedulerService) Handle(kind string, handler Handler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.handlers[kind] = handler
+}
+
+// Run polls for due jobs until ctx is done. Every replica runs it, the redis
+// lock makes sure only one of them processes a batch at a time.
+func (r *schedulerService) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.options.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.tick(ctx)
+		}
+	}
+}
+
+func (r *schedulerService) tick(ctx context.Context) {
+	locked, err := r.locker.TryLock(ctx, lockKey, r.options.LockTTL)
+	if err != nil {
+		r.logger.Error("failed to take scheduler lock", l.Error(err))
+		return
+	}
+	if !locked {
+		return
+	}
+	defer func() {
+		if err := r.locker.Unlock(context.Background(), lockKey); err != nil {
+			r.logger.Error("failed to release scheduler lock", l.Error(err))
+		}
+	}()
+
+	// stop picking new jobs well before the lock expires
+	deadline := time.Now().Add(r.options.LockTTL / 2)
+
+	listCtx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	jobs, err := r.repo.ListDue(listCtx, time.Now().UTC(), batchSize)
+	cancel()
+	if err != nil {
+		r.logger.Error("failed to list due jobs", l.Error(err))
+		return
+	}
+
+	for _, job := range jobs {
+		if time.Now().After(deadline) {
+			return
+		}
+		r.runJob(ctx, job)
+	}
+}
+
+func (r *schedulerService) runJob(ctx context.Context, job *entity.Job) {
+	r.mu.RLock()
+	handler, ok := r.handlers[job.Kind]
+	r.mu.RUnlock()
+
+	var err error
+	if !ok {
+		err = fmt.Errorf("no handler for job kind %q", job.Kind)
+	} else {
+		jobCtx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+		err = handler(jobCtx, job)
+		cancel()
+	}
+
+	job.Attempts++
+	job.UpdatedAt = time.Now().UTC()
+
+	switch {
+	case err == nil:
+		job.Status = entity.JobStatusDone
+		job.LastError = ""
+	case job.Attempts >= r.options.MaxAttempts:
+		job.Status = entity.JobStatusFailed
+		job.LastError = err.Error()
+	default:
+		// retry with a linear backoff
+		job.RunAt = time.Now().UTC().Add(time.Duration(job.Attempts) * time.Minute)
+		job.LastError = err.Error()
+	}
+
+	if err != nil {
+		r.logger.Error("scheduled job failed", zap.String("job_id", job.ID), zap.String("kind", job.Kind), l.Error(err))
+	}
+
+	updateCtx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+	if err := r.repo.Update(updateCtx, job); err != nil {
+		r.logger.Error("failed to update scheduled job", zap.String("job_id", job.ID), l.Error(err))
+	}
+}
@@ -0,0 +1,14 @@
+package staff
+
+import (
+	"context"
+
+	"Booking/api-service-booking/internal/entity"
+)
+
+type Staff interface {
+	Add(ctx context.Context, m *entity.StaffMember) error
+	Remove(ctx context.Context, userID, establishmentID string) error
+	ListByUser(ctx context.Context, userID string) ([]*entity.StaffMember, error)
+	IsMember(ctx context.Context, userID, establishmentID string) (bool, error)
+}
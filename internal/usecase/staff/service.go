@@ -0,0 +1,50 @@
+package staff
+
+import (
+	"context"
+	"time"
+
+	"Booking/api-service-booking/internal/entity"
+	"Booking/api-service-booking/internal/infrastructure/repository/postgresql/repo"
+)
+
+type staffService struct {
+	ctxTimeout time.Duration
+	repo       repo.StaffRepo
+}
+
+func NewStaffService(ctxTimeout time.Duration, repo repo.StaffRepo) Staff {
+	return &staffService{
+		ctxTimeout: ctxTimeout,
+		repo:       repo,
+	}
+}
+
+func (r *staffService) Add(ctx context.Context, m *entity.StaffMember) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	m.CreatedAt = time.Now().UTC()
+	return r.repo.Create(ctx, m)
+}
+
+func (r *staffService) Remove(ctx context.Context, userID, establishmentID string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.Delete(ctx, userID, establishmentID)
+}
+
+func (r *staffService) ListByUser(ctx context.Context, userID string) ([]*entity.StaffMember, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.ListByUser(ctx, userID)
+}
+
+func (r *staffService) IsMember(ctx context.Context, userID, establishmentID string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.IsMember(ctx, userID, establishmentID)
+}
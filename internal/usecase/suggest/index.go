@@ -0,0 +1,100 @@
+package suggest
+
+import (
+	"sort"
+	"strings"
+
+	"Booking/api-service-booking/internal/entity"
+	"Booking/api-service-booking/internal/pkg/translit"
+)
+
+// maxScan bounds the keys one lookup reads, so a one letter prefix costs
+// no more than a long one
+const maxScan = 5000
+
+// indexKey is an entry's folded text from one of its words on, start
+// marks the key starting with the first word
+type indexKey struct {
+	key   string
+	entry int
+	start bool
+}
+
+// prefixIndex finds entries by a prefix of their text or of any of its
+// words. It is not changed once built, a refresh builds a new one.
+type prefixIndex struct {
+	keys    []indexKey
+	entries []*entity.Suggestion
+}
+
+func newPrefixIndex(entries []*entity.Suggestion) *prefixIndex {
+	index := prefixIndex{entries: entries}
+	for i, entry := range entries {
+		words := strings.Fields(translit.Fold(entry.Text))
+		for w := range words {
+			index.keys = append(index.keys, indexKey{
+				key:   strings.Join(words[w:], " "),
+				entry: i,
+				start: w == 0,
+			})
+		}
+	}
+
+	sort.Slice(index.keys, func(i, j int) bool {
+		return index.keys[i].key < index.keys[j].key
+	})
+	return &index
+}
+
+// lookup returns up to limit entries matching the folded prefix. Entries
+// whose text starts with it come before those with a later word starting
+// with it, then the heavier ones.
+func (x *prefixIndex) lookup(prefix string, limit int) []*entity.Suggestion {
+	from := sort.Search(len(x.keys), func(i int) bool {
+		return x.keys[i].key >= prefix
+	})
+
+	// an entry can match through several words, the start match wins
+	matched := make(map[int]bool)
+	for i := from; i < len(x.keys) && i < from+maxScan; i++ {
+		key := x.keys[i]
+		if !strings.HasPrefix(key.key, prefix) {
+			break
+		}
+		matched[key.entry] = matched[key.entry] || key.start
+	}
+
+	found := make([]int, 0, len(matched))
+	for entry := range matched {
+		found = append(found, entry)
+	}
+	sort.Slice(found, func(i, j int) bool {
+		a, b := found[i], found[j]
+		if matched[a] != matched[b] {
+			return matched[a]
+		}
+		if x.entries[a].Weight != x.entries[b].Weight {
+			return x.entries[a].Weight > x.entries[b].Weight
+		}
+		return x.entries[a].Text < x.entries[b].Text
+	})
+
+	// a popular query naming a suggested place or city adds nothing
+	var (
+		result []*entity.Suggestion
+		seen   = make(map[string]bool)
+	)
+	for _, i := range found {
+		entry := x.entries[i]
+		text := translit.Fold(entry.Text)
+		if entry.Type == entity.SuggestionTypeQuery && seen[text] {
+			continue
+		}
+		seen[text] = true
+		result = append(result, entry)
+		if len(result) == limit {
+			break
+		}
+	}
+	return result
+}
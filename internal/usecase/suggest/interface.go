@@ -0,0 +1,22 @@
+package suggest
+
+import (
+	"context"
+
+	"Booking/api-service-booking/internal/entity"
+)
+
+// Source lists what the suggestion index is built from
+type Source func(ctx context.Context) ([]*entity.Suggestion, error)
+
+type Suggest interface {
+	// Suggest returns up to limit entries starting with q, or with one of
+	// their words starting with it, served from memory
+	Suggest(ctx context.Context, q string, limit int) ([]*entity.Suggestion, error)
+	// RecordQuery counts a search so popular queries are suggested
+	RecordQuery(ctx context.Context, q string) error
+	SetSource(source Source)
+	// Run builds the index and builds it again every refresh interval
+	// until ctx is done
+	Run(ctx context.Context)
+}
@@ -0,0 +1,163 @@
+package suggest
+
+import (
+	"context"
+	"math"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+
+	"Booking/api-service-booking/internal/entity"
+	errorspkg "Booking/api-service-booking/internal/errors"
+	redisrepo "Booking/api-service-booking/internal/infrastructure/repository/redis"
+	"Booking/api-service-booking/internal/pkg/i18n"
+	l "Booking/api-service-booking/internal/pkg/logger"
+	"Booking/api-service-booking/internal/pkg/translit"
+)
+
+const (
+	defaultLimit = 8
+	maxLimit     = 20
+	maxQueryLen  = 100
+	// topQueries is how many popular queries are suggested, keptQueries how
+	// many the log remembers between refreshes
+	topQueries  = 1000
+	keptQueries = 10000
+)
+
+type suggestService struct {
+	ctxTimeout      time.Duration
+	queryLog        redisrepo.QueryLog
+	logger          *zap.Logger
+	refreshInterval time.Duration
+
+	mu     sync.RWMutex
+	source Source
+	index  *prefixIndex
+}
+
+func NewSuggestService(ctxTimeout time.Duration, queryLog redisrepo.QueryLog, logger *zap.Logger, refreshInterval time.Duration) Suggest {
+	return &suggestService{
+		ctxTimeout:      ctxTimeout,
+		queryLog:        queryLog,
+		logger:          logger,
+		refreshInterval: refreshInterval,
+		index:           newPrefixIndex(nil),
+	}
+}
+
+func (r *suggestService) SetSource(source Source) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.source = source
+}
+
+func (r *suggestService) Suggest(ctx context.Context, q string, limit int) ([]*entity.Suggestion, error) {
+	prefix := translit.Fold(normalizeQuery(q))
+	if prefix == "" {
+		return nil, errorspkg.NewErrBadRequest(i18n.NewError("field_required", "q"))
+	}
+	if utf8.RuneCountInString(q) > maxQueryLen {
+		return nil, errorspkg.NewErrBadRequest(i18n.NewError("field_max_length", "q", maxQueryLen))
+	}
+	if limit == 0 {
+		limit = defaultLimit
+	}
+	if limit < 0 || limit > maxLimit {
+		return nil, errorspkg.NewErrBadRequest(i18n.NewError("field_between", "limit", 1, maxLimit))
+	}
+
+	r.mu.RLock()
+	index := r.index
+	r.mu.RUnlock()
+
+	return index.lookup(prefix, limit), nil
+}
+
+func (r *suggestService) RecordQuery(ctx context.Context, q string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	q = normalizeQuery(q)
+	if q == "" || utf8.RuneCountInString(q) > maxQueryLen {
+		return nil
+	}
+	return r.queryLog.Increment(ctx, q)
+}
+
+func (r *suggestService) Run(ctx context.Context) {
+	r.refresh(ctx)
+
+	ticker := time.NewTicker(r.refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.refresh(ctx)
+		}
+	}
+}
+
+// refresh builds a new index and swaps it in, a failed refresh keeps
+// serving the last one
+func (r *suggestService) refresh(ctx context.Context) {
+	r.mu.RLock()
+	source := r.source
+	r.mu.RUnlock()
+
+	var entries []*entity.Suggestion
+	if source != nil {
+		var err error
+		if entries, err = source(ctx); err != nil {
+			r.logger.Error("failed to list suggestions", l.Error(err))
+			return
+		}
+	}
+
+	queries, err := r.topQueries(ctx)
+	if err != nil {
+		r.logger.Error("failed to list popular queries", l.Error(err))
+		return
+	}
+	entries = append(entries, queries...)
+
+	index := newPrefixIndex(entries)
+
+	r.mu.Lock()
+	r.index = index
+	r.mu.Unlock()
+}
+
+func (r *suggestService) topQueries(ctx context.Context) ([]*entity.Suggestion, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	counts, err := r.queryLog.Top(ctx, topQueries, keptQueries)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := make([]*entity.Suggestion, 0, len(counts))
+	for _, count := range counts {
+		suggestions = append(suggestions, &entity.Suggestion{
+			Type:   entity.SuggestionTypeQuery,
+			ID:     count.Query,
+			Text:   count.Query,
+			Weight: 1 + math.Log10(count.Count),
+		})
+	}
+	return suggestions, nil
+}
+
+// normalizeQuery lowercases q and collapses its spaces so the same search
+// is counted once
+func normalizeQuery(q string) string {
+	return strings.Join(strings.Fields(strings.ToLower(q)), " ")
+}
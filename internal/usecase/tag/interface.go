@@ -0,0 +1,25 @@
+package tag
+
+import (
+	"context"
+
+	"Booking/api-service-booking/internal/entity"
+)
+
+type Tag interface {
+	Create(ctx context.Context, m *entity.Tag) error
+	Update(ctx context.Context, m *entity.Tag) error
+	Delete(ctx context.Context, slug string) error
+	// List returns the taxonomy, only tags of kind and those applying to
+	// category when they are not empty
+	List(ctx context.Context, kind, category string) ([]*entity.Tag, error)
+	// Check returns slugs lowercased and without repeats, or a bad request
+	// naming the first that is not in the taxonomy or does not apply to
+	// category
+	Check(ctx context.Context, category string, slugs []string) ([]string, error)
+	// SetEstablishmentTags checks slugs and replaces the tags of an
+	// establishment with them
+	SetEstablishmentTags(ctx context.Context, category, establishmentID string, slugs []string) error
+	// EstablishmentTags returns the tags of ids by establishment id
+	EstablishmentTags(ctx context.Context, category string, ids []string) (map[string][]string, error)
+}
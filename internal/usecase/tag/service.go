@@ -0,0 +1,190 @@
+package tag
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"Booking/api-service-booking/internal/entity"
+	errorspkg "Booking/api-service-booking/internal/errors"
+	"Booking/api-service-booking/internal/infrastructure/repository/postgresql/repo"
+	"Booking/api-service-booking/internal/pkg/i18n"
+)
+
+const (
+	maxSlugLength = 50
+	maxNameLength = 100
+	// maxTags is how many tags one establishment may have
+	maxTags = 30
+)
+
+var (
+	categories = []string{"hotel", "restaurant", "attraction"}
+	slugRe     = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+)
+
+type tagService struct {
+	ctxTimeout time.Duration
+	repo       repo.TagRepo
+}
+
+func NewTagService(ctxTimeout time.Duration, repo repo.TagRepo) Tag {
+	return &tagService{
+		ctxTimeout: ctxTimeout,
+		repo:       repo,
+	}
+}
+
+func (r *tagService) validate(m *entity.Tag) error {
+	m.Slug = strings.ToLower(strings.TrimSpace(m.Slug))
+	m.Name = strings.TrimSpace(m.Name)
+
+	if len(m.Slug) > maxSlugLength || !slugRe.MatchString(m.Slug) {
+		return i18n.NewError("tag_slug_format", maxSlugLength)
+	}
+	if !contains(entity.TagKinds, m.Kind) {
+		return i18n.NewError("field_one_of", "kind", strings.Join(entity.TagKinds, ", "))
+	}
+	if m.Name == "" || len([]rune(m.Name)) > maxNameLength {
+		return i18n.NewError("field_length_between", "name", 1, maxNameLength)
+	}
+	for _, category := range m.Categories {
+		if !contains(categories, category) {
+			return i18n.NewError("unknown_category", category)
+		}
+	}
+	if m.Categories == nil {
+		m.Categories = []string{}
+	}
+	return nil
+}
+
+func (r *tagService) Create(ctx context.Context, m *entity.Tag) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	if err := r.validate(m); err != nil {
+		return errorspkg.NewErrBadRequest(err)
+	}
+
+	m.CreatedAt = time.Now().UTC()
+	m.UpdatedAt = m.CreatedAt
+	return r.repo.Create(ctx, m)
+}
+
+func (r *tagService) Update(ctx context.Context, m *entity.Tag) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	if err := r.validate(m); err != nil {
+		return errorspkg.NewErrBadRequest(err)
+	}
+
+	m.UpdatedAt = time.Now().UTC()
+	return r.repo.Update(ctx, m)
+}
+
+func (r *tagService) Delete(ctx context.Context, slug string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.Delete(ctx, slug)
+}
+
+func (r *tagService) List(ctx context.Context, kind, category string) ([]*entity.Tag, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	tags, err := r.repo.List(ctx, kind)
+	if err != nil {
+		return nil, err
+	}
+	if category == "" {
+		return tags, nil
+	}
+
+	applying := tags[:0]
+	for _, tag := range tags {
+		if appliesTo(tag, category) {
+			applying = append(applying, tag)
+		}
+	}
+	return applying, nil
+}
+
+func (r *tagService) Check(ctx context.Context, category string, slugs []string) ([]string, error) {
+	checked := make([]string, 0, len(slugs))
+	for _, slug := range slugs {
+		slug = strings.ToLower(strings.TrimSpace(slug))
+		if slug != "" && !contains(checked, slug) {
+			checked = append(checked, slug)
+		}
+	}
+	if len(checked) > maxTags {
+		return nil, errorspkg.NewErrBadRequest(i18n.NewError("too_many_tags", maxTags))
+	}
+	if len(checked) == 0 {
+		return checked, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	tags, err := r.repo.List(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	taxonomy := make(map[string]*entity.Tag, len(tags))
+	for _, tag := range tags {
+		taxonomy[tag.Slug] = tag
+	}
+
+	for _, slug := range checked {
+		tag, ok := taxonomy[slug]
+		if !ok {
+			return nil, errorspkg.NewErrBadRequest(i18n.NewError("unknown_tag", slug))
+		}
+		if !appliesTo(tag, category) {
+			return nil, errorspkg.NewErrBadRequest(i18n.NewError("tag_not_applicable", slug, category))
+		}
+	}
+	return checked, nil
+}
+
+func (r *tagService) SetEstablishmentTags(ctx context.Context, category, establishmentID string, slugs []string) error {
+	slugs, err := r.Check(ctx, category, slugs)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.SetEstablishmentTags(ctx, category, establishmentID, slugs)
+}
+
+func (r *tagService) EstablishmentTags(ctx context.Context, category string, ids []string) (map[string][]string, error) {
+	if len(ids) == 0 {
+		return map[string][]string{}, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.ListEstablishmentTags(ctx, category, ids)
+}
+
+// appliesTo reports whether tag may be put on an establishment of category
+func appliesTo(tag *entity.Tag, category string) bool {
+	return len(tag.Categories) == 0 || contains(tag.Categories, category)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
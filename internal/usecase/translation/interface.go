@@ -0,0 +1,19 @@
+package translation
+
+import (
+	"context"
+
+	"Booking/api-service-booking/internal/entity"
+)
+
+type Translation interface {
+	Save(ctx context.Context, m *entity.Translation) error
+	List(ctx context.Context, category, establishmentID string) ([]*entity.Translation, error)
+	Delete(ctx context.Context, category, establishmentID, locale string) error
+	DeleteAll(ctx context.Context, category, establishmentID string) error
+	// Pick returns the translations of ids into locale by id, none when
+	// locale is the default language
+	Pick(ctx context.Context, category string, ids []string, locale string) (map[string]*entity.Translation, error)
+	// Default is the language establishments are written in
+	Default() string
+}
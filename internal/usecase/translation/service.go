@@ -0,0 +1,114 @@
+package translation
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"Booking/api-service-booking/internal/entity"
+	errorspkg "Booking/api-service-booking/internal/errors"
+	"Booking/api-service-booking/internal/infrastructure/repository/postgresql/repo"
+	"Booking/api-service-booking/internal/pkg/i18n"
+	"Booking/api-service-booking/internal/pkg/locale"
+)
+
+const (
+	maxNameLength        = 255
+	maxDescriptionLength = 5000
+)
+
+var categories = []string{"hotel", "restaurant", "attraction"}
+
+type translationService struct {
+	ctxTimeout    time.Duration
+	repo          repo.TranslationRepo
+	defaultLocale string
+}
+
+func NewTranslationService(ctxTimeout time.Duration, repo repo.TranslationRepo, defaultLocale string) Translation {
+	return &translationService{
+		ctxTimeout:    ctxTimeout,
+		repo:          repo,
+		defaultLocale: defaultLocale,
+	}
+}
+
+func (r *translationService) Save(ctx context.Context, m *entity.Translation) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	m.Locale = strings.ToLower(strings.TrimSpace(m.Locale))
+	m.Name = strings.TrimSpace(m.Name)
+	m.Description = strings.TrimSpace(m.Description)
+
+	switch {
+	case !isCategory(m.Category):
+		return errorspkg.NewErrBadRequest(i18n.NewError("unknown_category", m.Category))
+	case !locale.IsSupported(m.Locale):
+		return errorspkg.NewErrBadRequest(i18n.NewError("field_one_of", "locale", strings.Join(locale.Supported, ", ")))
+	case m.Locale == r.defaultLocale:
+		return errorspkg.NewErrBadRequest(i18n.NewError("default_locale", m.Locale))
+	case m.Name == "" && m.Description == "":
+		return errorspkg.NewErrBadRequest(i18n.NewError("name_or_description"))
+	case len([]rune(m.Name)) > maxNameLength:
+		return errorspkg.NewErrBadRequest(i18n.NewError("field_max_length", "name", maxNameLength))
+	case len([]rune(m.Description)) > maxDescriptionLength:
+		return errorspkg.NewErrBadRequest(i18n.NewError("field_max_length", "description", maxDescriptionLength))
+	}
+
+	m.UpdatedAt = time.Now().UTC()
+	return r.repo.Save(ctx, m)
+}
+
+func (r *translationService) List(ctx context.Context, category, establishmentID string) ([]*entity.Translation, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.List(ctx, category, []string{establishmentID}, "")
+}
+
+func (r *translationService) Delete(ctx context.Context, category, establishmentID, locale string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.Delete(ctx, category, establishmentID, locale)
+}
+
+func (r *translationService) DeleteAll(ctx context.Context, category, establishmentID string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.DeleteAll(ctx, category, establishmentID)
+}
+
+func (r *translationService) Pick(ctx context.Context, category string, ids []string, locale string) (map[string]*entity.Translation, error) {
+	picked := make(map[string]*entity.Translation)
+	if locale == r.defaultLocale || len(ids) == 0 {
+		return picked, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	translations, err := r.repo.List(ctx, category, ids, locale)
+	if err != nil {
+		return nil, err
+	}
+	for _, translation := range translations {
+		picked[translation.EstablishmentID] = translation
+	}
+	return picked, nil
+}
+
+func (r *translationService) Default() string {
+	return r.defaultLocale
+}
+
+func isCategory(category string) bool {
+	for _, c := range categories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+package trip
+
+import (
+	"context"
+
+	"Booking/api-service-booking/internal/entity"
+)
+
+type Trip interface {
+	Create(ctx context.Context, m *entity.Trip) error
+	Get(ctx context.Context, id string) (*entity.Trip, error)
+	ListByUser(ctx context.Context, userID string) ([]*entity.Trip, error)
+	Delete(ctx context.Context, id string) error
+	AddItem(ctx context.Context, m *entity.TripItem) error
+	RemoveItem(ctx context.Context, tripID, itemID string) error
+	// BeginCheckout closes an open trip for changes and gives each item
+	// the id its booking is created under
+	BeginCheckout(ctx context.Context, id string) (*entity.Trip, error)
+	// FinishCheckout marks the trip booked, or reopens it with the booking
+	// ids cleared when the checkout was rolled back. It reports false when
+	// the trip was no longer checking out, as when the scheduled rollback
+	// got to it first.
+	FinishCheckout(ctx context.Context, id string, booked bool) (bool, error)
+	// Checkout books the items of a trip BeginCheckout returned with
+	// booker, in their order, and marks the trip booked. When an item
+	// fails the ones booked before it are canceled, newest first, and the
+	// trip is reopened, the error is an *ItemError then. When marking it
+	// booked fails the trip is left to the scheduled rollback.
+	// ErrCheckoutExpired means that rollback reopened the trip first, the
+	// items booked are canceled.
+	Checkout(ctx context.Context, trip *entity.Trip, booker Booker) error
+}
+
+// Booker books the items of a trip at checkout
+type Booker interface {
+	// Book places the booking of an item under its BookingID
+	Book(ctx context.Context, item *entity.TripItem) error
+	// Cancel undoes a booking Book placed, leaving one that is canceled
+	// already as it is
+	Cancel(ctx context.Context, item *entity.TripItem) error
+}
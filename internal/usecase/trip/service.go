@@ -0,0 +1,251 @@
+package trip
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Booking/api-service-booking/internal/entity"
+	errorspkg "Booking/api-service-booking/internal/errors"
+	"Booking/api-service-booking/internal/infrastructure/repository/postgresql/repo"
+	"Booking/api-service-booking/internal/pkg/i18n"
+)
+
+const (
+	// maxItems caps how many bookings one checkout makes
+	maxItems = 20
+	// CheckoutTimeout is how long a checkout may run before the scheduler
+	// takes it for crashed and rolls it back
+	CheckoutTimeout = 10 * time.Minute
+)
+
+var errNotOpen = errorspkg.NewErrBadRequest(i18n.NewError("trip_checked_out"))
+
+// ErrCheckoutExpired is returned by Checkout when the scheduled rollback
+// reopened the trip while its items were being booked
+var ErrCheckoutExpired = errors.New("trip checkout was rolled back")
+
+// ItemError is a checkout stopped by an item that could not be booked
+type ItemError struct {
+	// Position is where the item is in the trip, from 0
+	Position int
+	Item     *entity.TripItem
+	Err      error
+	// RollbackErr is why the trip was left checking out for the scheduled
+	// rollback, nil when it was reopened
+	RollbackErr error
+}
+
+func (e *ItemError) Error() string {
+	return fmt.Sprintf("trip item %d: %v", e.Position+1, e.Err)
+}
+
+func (e *ItemError) Unwrap() error {
+	return e.Err
+}
+
+type tripService struct {
+	ctxTimeout time.Duration
+	repo       repo.TripRepo
+}
+
+func NewTripService(ctxTimeout time.Duration, repo repo.TripRepo) Trip {
+	return &tripService{
+		ctxTimeout: ctxTimeout,
+		repo:       repo,
+	}
+}
+
+func (r *tripService) beforeCreate(m *entity.Trip) {
+	m.ID = uuid.NewString()
+	m.Name = strings.TrimSpace(m.Name)
+	m.Status = entity.TripStatusOpen
+	m.CreatedAt = time.Now().UTC()
+	m.UpdatedAt = time.Now().UTC()
+}
+
+func (r *tripService) Create(ctx context.Context, m *entity.Trip) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	r.beforeCreate(m)
+	return r.repo.Create(ctx, m)
+}
+
+func (r *tripService) Get(ctx context.Context, id string) (*entity.Trip, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.Get(ctx, id)
+}
+
+func (r *tripService) ListByUser(ctx context.Context, userID string) ([]*entity.Trip, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.ListByUser(ctx, userID)
+}
+
+func (r *tripService) Delete(ctx context.Context, id string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	err := r.repo.Delete(ctx, id)
+	if errors.Is(err, errorspkg.ErrorNotAvailable) {
+		return errNotOpen
+	}
+	return err
+}
+
+func (r *tripService) AddItem(ctx context.Context, m *entity.TripItem) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	if m.EstablishmentID == "" || m.WillArrive == "" {
+		return errorspkg.NewErrBadRequest(i18n.NewError("trip_item_fields"))
+	}
+	if m.NumberOfPeople < 1 && len(m.Tickets) == 0 {
+		return errorspkg.NewErrBadRequest(i18n.NewError("field_positive", "number_of_people"))
+	}
+
+	trip, err := r.repo.Get(ctx, m.TripID)
+	if err != nil {
+		return err
+	}
+	if len(trip.Items) >= maxItems {
+		return errorspkg.NewErrBadRequest(i18n.NewError("trip_full", 20))
+	}
+
+	m.ID = uuid.NewString()
+	m.PromoCode = strings.TrimSpace(m.PromoCode)
+	m.CreatedAt = time.Now().UTC()
+	if m.Tickets == nil {
+		m.Tickets = map[string]int{}
+	}
+
+	err = r.repo.AddItem(ctx, m)
+	if errors.Is(err, errorspkg.ErrorNotAvailable) {
+		return errNotOpen
+	}
+	return err
+}
+
+func (r *tripService) RemoveItem(ctx context.Context, tripID, itemID string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	err := r.repo.RemoveItem(ctx, tripID, itemID)
+	if errors.Is(err, errorspkg.ErrorNotAvailable) {
+		return errNotOpen
+	}
+	return err
+}
+
+func (r *tripService) BeginCheckout(ctx context.Context, id string) (*entity.Trip, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	started, err := r.repo.ChangeStatus(ctx, id, []string{entity.TripStatusOpen}, entity.TripStatusCheckingOut, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	if !started {
+		return nil, errNotOpen
+	}
+
+	trip, err := r.repo.Get(ctx, id)
+	if err == nil && len(trip.Items) == 0 {
+		err = errorspkg.NewErrBadRequest(i18n.NewError("trip_empty"))
+	}
+
+	bookingIDs := make(map[string]string)
+	if err == nil {
+		for _, item := range trip.Items {
+			item.BookingID = uuid.NewString()
+			bookingIDs[item.ID] = item.BookingID
+		}
+		err = r.repo.SetBookingIDs(ctx, id, bookingIDs)
+	}
+	if err != nil {
+		if _, reopenErr := r.repo.Reopen(ctx, id, time.Now().UTC()); reopenErr != nil {
+			return nil, reopenErr
+		}
+		return nil, err
+	}
+
+	trip.Status = entity.TripStatusCheckingOut
+	return trip, nil
+}
+
+func (r *tripService) FinishCheckout(ctx context.Context, id string, booked bool) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	if booked {
+		return r.repo.ChangeStatus(ctx, id, []string{entity.TripStatusCheckingOut}, entity.TripStatusBooked, time.Now().UTC())
+	}
+	return r.repo.Reopen(ctx, id, time.Now().UTC())
+}
+
+func (r *tripService) Checkout(ctx context.Context, trip *entity.Trip, booker Booker) error {
+	var placed []*entity.TripItem
+	for i, item := range trip.Items {
+		if err := booker.Book(ctx, item); err != nil {
+			return &ItemError{
+				Position:    i,
+				Item:        item,
+				Err:         err,
+				RollbackErr: r.rollback(ctx, trip.ID, placed, booker),
+			}
+		}
+		placed = append(placed, item)
+	}
+
+	booked, err := r.FinishCheckout(ctx, trip.ID, true)
+	if err != nil {
+		return err
+	}
+	if !booked {
+		r.undo(ctx, placed, booker)
+		return ErrCheckoutExpired
+	}
+	return nil
+}
+
+// rollback cancels the bookings placed so far, newest first, and reopens
+// the trip. When a booking can not be canceled the trip is left checking
+// out for the scheduled rollback to retry. The rollback goes on when ctx
+// is canceled, as when the client went away, until the scheduled one
+// would take over.
+func (r *tripService) rollback(ctx context.Context, id string, placed []*entity.TripItem, booker Booker) error {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), CheckoutTimeout)
+	defer cancel()
+
+	for i := len(placed) - 1; i >= 0; i-- {
+		if err := booker.Cancel(ctx, placed[i]); err != nil {
+			return err
+		}
+	}
+
+	// not reopened means the scheduled rollback already did, it is done
+	// with the trip either way
+	_, err := r.FinishCheckout(ctx, id, false)
+	return err
+}
+
+// undo cancels the bookings of a checkout the scheduled rollback reopened
+// while they were being placed. The rollback canceled those it found, the
+// booker leaves them be and cancels the ones placed after it ran. One
+// that fails does not stop the others.
+func (r *tripService) undo(ctx context.Context, placed []*entity.TripItem, booker Booker) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), CheckoutTimeout)
+	defer cancel()
+
+	for i := len(placed) - 1; i >= 0; i-- {
+		_ = booker.Cancel(ctx, placed[i])
+	}
+}
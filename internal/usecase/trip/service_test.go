@@ -0,0 +1,300 @@
+package trip
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"Booking/api-service-booking/internal/entity"
+	errorspkg "Booking/api-service-booking/internal/errors"
+	"Booking/api-service-booking/internal/infrastructure/repository/postgresql/repo"
+)
+
+// fakeTripRepo keeps one trip in memory and moves it between statuses the
+// way the conditional updates do
+type fakeTripRepo struct {
+	repo.TripRepo
+	trip *entity.Trip
+	// setErr fails SetBookingIDs
+	setErr error
+	// beforeFinish runs when the trip is about to be marked booked, to act
+	// as the scheduled rollback
+	beforeFinish func()
+}
+
+func (r *fakeTripRepo) Get(_ context.Context, id string) (*entity.Trip, error) {
+	if r.trip == nil || r.trip.ID != id {
+		return nil, errorspkg.ErrorNotFound
+	}
+	trip := *r.trip
+	trip.Items = nil
+	for _, item := range r.trip.Items {
+		copied := *item
+		trip.Items = append(trip.Items, &copied)
+	}
+	return &trip, nil
+}
+
+func (r *fakeTripRepo) ChangeStatus(_ context.Context, id string, from []string, to string, _ time.Time) (bool, error) {
+	if to == entity.TripStatusBooked && r.beforeFinish != nil {
+		r.beforeFinish()
+	}
+	for _, status := range from {
+		if r.trip.Status == status {
+			r.trip.Status = to
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeTripRepo) Reopen(_ context.Context, id string, _ time.Time) (bool, error) {
+	if r.trip.Status != entity.TripStatusCheckingOut {
+		return false, nil
+	}
+	r.trip.Status = entity.TripStatusOpen
+	for _, item := range r.trip.Items {
+		item.BookingID = ""
+	}
+	return true, nil
+}
+
+func (r *fakeTripRepo) SetBookingIDs(_ context.Context, _ string, bookingIDs map[string]string) error {
+	if r.setErr != nil {
+		return r.setErr
+	}
+	for _, item := range r.trip.Items {
+		item.BookingID = bookingIDs[item.ID]
+	}
+	return nil
+}
+
+// fakeBooker stands for the booking service, it records what it is asked
+// to do by item id
+type fakeBooker struct {
+	failBook   map[string]bool
+	failCancel map[string]bool
+	booked     []string
+	canceled   []string
+}
+
+func (b *fakeBooker) Book(_ context.Context, item *entity.TripItem) error {
+	if b.failBook[item.ID] {
+		return errors.New("no free table")
+	}
+	b.booked = append(b.booked, item.ID)
+	return nil
+}
+
+func (b *fakeBooker) Cancel(_ context.Context, item *entity.TripItem) error {
+	if b.failCancel[item.ID] {
+		return errors.New("booking service is down")
+	}
+	b.canceled = append(b.canceled, item.ID)
+	return nil
+}
+
+func newTrip(items int) *entity.Trip {
+	trip := entity.Trip{ID: "trip", UserID: "user", Status: entity.TripStatusOpen}
+	for i := 1; i <= items; i++ {
+		trip.Items = append(trip.Items, &entity.TripItem{
+			ID:       fmt.Sprintf("item-%d", i),
+			TripID:   trip.ID,
+			Category: "restaurant",
+		})
+	}
+	return &trip
+}
+
+func beginCheckout(t *testing.T, r *fakeTripRepo) (*tripService, *entity.Trip) {
+	t.Helper()
+
+	s := &tripService{ctxTimeout: time.Second, repo: r}
+	trip, err := s.BeginCheckout(context.Background(), r.trip.ID)
+	if err != nil {
+		t.Fatalf("BeginCheckout() error = %v", err)
+	}
+	return s, trip
+}
+
+func TestBeginCheckout(t *testing.T) {
+	r := &fakeTripRepo{trip: newTrip(3)}
+	_, trip := beginCheckout(t, r)
+
+	if r.trip.Status != entity.TripStatusCheckingOut {
+		t.Errorf("status = %q, want %q", r.trip.Status, entity.TripStatusCheckingOut)
+	}
+	seen := make(map[string]bool)
+	for i, item := range trip.Items {
+		if item.BookingID == "" || seen[item.BookingID] {
+			t.Errorf("item %d has booking id %q", i+1, item.BookingID)
+		}
+		seen[item.BookingID] = true
+		if r.trip.Items[i].BookingID != item.BookingID {
+			t.Errorf("item %d saved booking id %q, returned %q", i+1, r.trip.Items[i].BookingID, item.BookingID)
+		}
+	}
+
+	s := &tripService{ctxTimeout: time.Second, repo: r}
+	if _, err := s.BeginCheckout(context.Background(), trip.ID); !errors.Is(err, errNotOpen) {
+		t.Errorf("second BeginCheckout() error = %v, want %v", err, errNotOpen)
+	}
+}
+
+func TestBeginCheckoutReopens(t *testing.T) {
+	tests := []struct {
+		name string
+		repo *fakeTripRepo
+	}{
+		{name: "empty trip", repo: &fakeTripRepo{trip: newTrip(0)}},
+		{name: "booking ids not saved", repo: &fakeTripRepo{trip: newTrip(2), setErr: errors.New("connection reset")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &tripService{ctxTimeout: time.Second, repo: tt.repo}
+			if _, err := s.BeginCheckout(context.Background(), tt.repo.trip.ID); err == nil {
+				t.Fatal("BeginCheckout() error = nil")
+			}
+			if tt.repo.trip.Status != entity.TripStatusOpen {
+				t.Errorf("status = %q, want %q", tt.repo.trip.Status, entity.TripStatusOpen)
+			}
+		})
+	}
+}
+
+func TestCheckoutBooksInOrder(t *testing.T) {
+	r := &fakeTripRepo{trip: newTrip(3)}
+	s, trip := beginCheckout(t, r)
+
+	var booker fakeBooker
+	if err := s.Checkout(context.Background(), trip, &booker); err != nil {
+		t.Fatalf("Checkout() error = %v", err)
+	}
+
+	if want := []string{"item-1", "item-2", "item-3"}; !reflect.DeepEqual(booker.booked, want) {
+		t.Errorf("booked %v, want %v", booker.booked, want)
+	}
+	if len(booker.canceled) > 0 {
+		t.Errorf("canceled %v, want none", booker.canceled)
+	}
+	if r.trip.Status != entity.TripStatusBooked {
+		t.Errorf("status = %q, want %q", r.trip.Status, entity.TripStatusBooked)
+	}
+}
+
+func TestCheckoutFailsOnItem(t *testing.T) {
+	tests := []struct {
+		failing      string
+		wantPosition int
+		wantBooked   []string
+		wantCanceled []string
+	}{
+		{failing: "item-1", wantPosition: 0},
+		{failing: "item-2", wantPosition: 1, wantBooked: []string{"item-1"}, wantCanceled: []string{"item-1"}},
+		{
+			failing:      "item-4",
+			wantPosition: 3,
+			wantBooked:   []string{"item-1", "item-2", "item-3"},
+			wantCanceled: []string{"item-3", "item-2", "item-1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.failing, func(t *testing.T) {
+			r := &fakeTripRepo{trip: newTrip(4)}
+			s, trip := beginCheckout(t, r)
+
+			booker := fakeBooker{failBook: map[string]bool{tt.failing: true}}
+			err := s.Checkout(context.Background(), trip, &booker)
+
+			var itemErr *ItemError
+			if !errors.As(err, &itemErr) {
+				t.Fatalf("Checkout() error = %v, want an *ItemError", err)
+			}
+			if itemErr.Position != tt.wantPosition || itemErr.Item.ID != tt.failing {
+				t.Errorf("failed at %d (%s), want %d (%s)", itemErr.Position, itemErr.Item.ID, tt.wantPosition, tt.failing)
+			}
+			if itemErr.RollbackErr != nil {
+				t.Errorf("RollbackErr = %v, want nil", itemErr.RollbackErr)
+			}
+			if !reflect.DeepEqual(booker.booked, tt.wantBooked) {
+				t.Errorf("booked %v, want %v", booker.booked, tt.wantBooked)
+			}
+			if !reflect.DeepEqual(booker.canceled, tt.wantCanceled) {
+				t.Errorf("canceled %v, want %v newest first", booker.canceled, tt.wantCanceled)
+			}
+			if r.trip.Status != entity.TripStatusOpen {
+				t.Errorf("status = %q, want %q", r.trip.Status, entity.TripStatusOpen)
+			}
+			for _, item := range r.trip.Items {
+				if item.BookingID != "" {
+					t.Errorf("%s keeps booking id %q after the rollback", item.ID, item.BookingID)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckoutRollbackStopsAtCancelFailure(t *testing.T) {
+	r := &fakeTripRepo{trip: newTrip(4)}
+	s, trip := beginCheckout(t, r)
+
+	booker := fakeBooker{
+		failBook:   map[string]bool{"item-4": true},
+		failCancel: map[string]bool{"item-2": true},
+	}
+	err := s.Checkout(context.Background(), trip, &booker)
+
+	var itemErr *ItemError
+	if !errors.As(err, &itemErr) {
+		t.Fatalf("Checkout() error = %v, want an *ItemError", err)
+	}
+	if itemErr.RollbackErr == nil {
+		t.Error("RollbackErr = nil, want the cancel failure")
+	}
+	if want := []string{"item-3"}; !reflect.DeepEqual(booker.canceled, want) {
+		t.Errorf("canceled %v, want %v", booker.canceled, want)
+	}
+	// the scheduled rollback takes it from here
+	if r.trip.Status != entity.TripStatusCheckingOut {
+		t.Errorf("status = %q, want %q", r.trip.Status, entity.TripStatusCheckingOut)
+	}
+}
+
+func TestCheckoutExpired(t *testing.T) {
+	r := &fakeTripRepo{trip: newTrip(3)}
+	s, trip := beginCheckout(t, r)
+	r.beforeFinish = func() {
+		if _, err := r.Reopen(context.Background(), trip.ID, time.Now()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// a booking that can not be canceled does not keep the others
+	booker := fakeBooker{failCancel: map[string]bool{"item-2": true}}
+	if err := s.Checkout(context.Background(), trip, &booker); !errors.Is(err, ErrCheckoutExpired) {
+		t.Fatalf("Checkout() error = %v, want %v", err, ErrCheckoutExpired)
+	}
+
+	if want := []string{"item-3", "item-1"}; !reflect.DeepEqual(booker.canceled, want) {
+		t.Errorf("canceled %v, want %v", booker.canceled, want)
+	}
+	if r.trip.Status != entity.TripStatusOpen {
+		t.Errorf("status = %q, want %q", r.trip.Status, entity.TripStatusOpen)
+	}
+}
+
+func TestFinishCheckout(t *testing.T) {
+	r := &fakeTripRepo{trip: newTrip(1)}
+	s, trip := beginCheckout(t, r)
+
+	if reopened, err := s.FinishCheckout(context.Background(), trip.ID, false); err != nil || !reopened {
+		t.Fatalf("FinishCheckout(false) = %v, %v, want true", reopened, err)
+	}
+	// the trip is open again, so it can not be marked booked
+	if booked, err := s.FinishCheckout(context.Background(), trip.ID, true); err != nil || booked {
+		t.Errorf("FinishCheckout(true) = %v, %v, want false", booked, err)
+	}
+}
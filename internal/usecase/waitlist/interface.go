@@ -0,0 +1,26 @@
+package waitlist
+
+import (
+	"context"
+	"time"
+
+	"Booking/api-service-booking/internal/entity"
+)
+
+type Waitlist interface {
+	Join(ctx context.Context, m *entity.WaitlistEntry) error
+	Get(ctx context.Context, id string) (*entity.WaitlistEntry, error)
+	ListByUser(ctx context.Context, userID string) ([]*entity.WaitlistEntry, error)
+	// Leave takes a waiting or offered entry of userID out of the line
+	Leave(ctx context.Context, id, userID string) (*entity.WaitlistEntry, error)
+	// Line returns the first entries waiting for a range overlapping [from, to)
+	Line(ctx context.Context, category, establishmentID string, from, to time.Time) ([]*entity.WaitlistEntry, error)
+	// Offer opens a time-boxed offer for a waiting entry, false if it is
+	// no longer waiting
+	Offer(ctx context.Context, m *entity.WaitlistEntry) (bool, error)
+	Claim(ctx context.Context, id string) (bool, error)
+	// Restore reopens a claimed offer whose booking could not be made
+	Restore(ctx context.Context, m *entity.WaitlistEntry) error
+	Expire(ctx context.Context, id string) (bool, error)
+	Demand(ctx context.Context, category, establishmentID string, from, to time.Time) ([]*entity.WaitlistDemand, error)
+}
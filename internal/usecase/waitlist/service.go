@@ -0,0 +1,140 @@
+package waitlist
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"Booking/api-service-booking/internal/entity"
+	errorspkg "Booking/api-service-booking/internal/errors"
+	"Booking/api-service-booking/internal/infrastructure/repository/postgresql/repo"
+)
+
+// lineLength is how many people in line are tried for one freed place
+const lineLength = 20
+
+type Options struct {
+	// HoldFor is how long an offered place is kept for the person in line
+	HoldFor time.Duration
+}
+
+type waitlistService struct {
+	ctxTimeout time.Duration
+	repo       repo.WaitlistRepo
+	options    Options
+}
+
+func NewWaitlistService(ctxTimeout time.Duration, repo repo.WaitlistRepo, options Options) Waitlist {
+	return &waitlistService{
+		ctxTimeout: ctxTimeout,
+		repo:       repo,
+		options:    options,
+	}
+}
+
+func (r *waitlistService) beforeCreate(m *entity.WaitlistEntry) {
+	m.ID = uuid.NewString()
+	m.Status = entity.WaitlistStatusWaiting
+	m.CreatedAt = time.Now().UTC()
+	m.UpdatedAt = time.Now().UTC()
+	if m.Tickets == nil {
+		m.Tickets = map[string]int{}
+	}
+}
+
+func (r *waitlistService) Join(ctx context.Context, m *entity.WaitlistEntry) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	r.beforeCreate(m)
+	return r.repo.Create(ctx, m)
+}
+
+func (r *waitlistService) Get(ctx context.Context, id string) (*entity.WaitlistEntry, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.Get(ctx, id)
+}
+
+func (r *waitlistService) ListByUser(ctx context.Context, userID string) ([]*entity.WaitlistEntry, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.ListByUser(ctx, userID)
+}
+
+func (r *waitlistService) Leave(ctx context.Context, id, userID string) (*entity.WaitlistEntry, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	entry, err := r.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if entry.UserID != userID {
+		return nil, errorspkg.ErrorNotFound
+	}
+
+	left, err := r.repo.ChangeStatus(ctx, id, []string{entity.WaitlistStatusWaiting, entity.WaitlistStatusOffered}, entity.WaitlistStatusLeft, nil, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	if !left {
+		return nil, errorspkg.ErrorNotFound
+	}
+
+	return entry, nil
+}
+
+func (r *waitlistService) Line(ctx context.Context, category, establishmentID string, from, to time.Time) ([]*entity.WaitlistEntry, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.ListWaiting(ctx, category, establishmentID, from, to, lineLength)
+}
+
+func (r *waitlistService) Offer(ctx context.Context, m *entity.WaitlistEntry) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	expiresAt := time.Now().UTC().Add(r.options.HoldFor)
+	offered, err := r.repo.ChangeStatus(ctx, m.ID, []string{entity.WaitlistStatusWaiting}, entity.WaitlistStatusOffered, &expiresAt, time.Now().UTC())
+	if err != nil || !offered {
+		return false, err
+	}
+
+	m.Status = entity.WaitlistStatusOffered
+	m.OfferExpiresAt = &expiresAt
+	return true, nil
+}
+
+func (r *waitlistService) Claim(ctx context.Context, id string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.ChangeStatus(ctx, id, []string{entity.WaitlistStatusOffered}, entity.WaitlistStatusClaimed, nil, time.Now().UTC())
+}
+
+func (r *waitlistService) Restore(ctx context.Context, m *entity.WaitlistEntry) error {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	_, err := r.repo.ChangeStatus(ctx, m.ID, []string{entity.WaitlistStatusClaimed}, entity.WaitlistStatusOffered, m.OfferExpiresAt, time.Now().UTC())
+	return err
+}
+
+func (r *waitlistService) Expire(ctx context.Context, id string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.ChangeStatus(ctx, id, []string{entity.WaitlistStatusOffered}, entity.WaitlistStatusExpired, nil, time.Now().UTC())
+}
+
+func (r *waitlistService) Demand(ctx context.Context, category, establishmentID string, from, to time.Time) ([]*entity.WaitlistDemand, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.ctxTimeout)
+	defer cancel()
+
+	return r.repo.Demand(ctx, category, establishmentID, from, to)
+}
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS booking_records (
+    id               UUID PRIMARY KEY,
+    category         VARCHAR(20)  NOT NULL,
+    user_id          UUID         NOT NULL,
+    establishment_id UUID         NOT NULL,
+    will_arrive      VARCHAR(50)  NOT NULL,
+    will_leave       VARCHAR(50)  NOT NULL,
+    arrive_at        TIMESTAMPTZ,
+    leave_at         TIMESTAMPTZ,
+    number_of_people BIGINT       NOT NULL DEFAULT 0,
+    state            VARCHAR(20)  NOT NULL DEFAULT 'confirmed',
+    created_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS booking_records_user_id_idx ON booking_records (user_id);
+CREATE INDEX IF NOT EXISTS booking_records_establishment_id_idx ON booking_records (establishment_id, arrive_at);
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS scheduled_jobs (
+    id         UUID PRIMARY KEY,
+    kind       VARCHAR(50)  NOT NULL,
+    booking_id UUID,
+    payload    JSONB        NOT NULL DEFAULT '{}',
+    run_at     TIMESTAMPTZ  NOT NULL,
+    status     VARCHAR(20)  NOT NULL DEFAULT 'pending',
+    attempts   INT          NOT NULL DEFAULT 0,
+    last_error TEXT         NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS scheduled_jobs_due_idx ON scheduled_jobs (status, run_at);
+CREATE INDEX IF NOT EXISTS scheduled_jobs_booking_id_idx ON scheduled_jobs (booking_id);
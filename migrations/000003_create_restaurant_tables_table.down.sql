@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS table_reservations;
+DROP TABLE IF EXISTS restaurant_tables;
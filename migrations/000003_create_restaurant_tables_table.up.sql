@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS restaurant_tables (
+    id            UUID PRIMARY KEY,
+    restaurant_id UUID         NOT NULL,
+    name          VARCHAR(100) NOT NULL,
+    capacity      INT          NOT NULL CHECK (capacity > 0),
+    created_at    TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at    TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    deleted_at    TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS restaurant_tables_restaurant_id_idx ON restaurant_tables (restaurant_id);
+
+CREATE TABLE IF NOT EXISTS table_reservations (
+    id            UUID PRIMARY KEY,
+    booking_id    UUID        NOT NULL UNIQUE,
+    table_id      UUID        NOT NULL REFERENCES restaurant_tables (id),
+    restaurant_id UUID        NOT NULL,
+    party_size    INT         NOT NULL,
+    starts_at     TIMESTAMPTZ NOT NULL,
+    ends_at       TIMESTAMPTZ NOT NULL,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS table_reservations_restaurant_id_idx ON table_reservations (restaurant_id, starts_at);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS attraction_tickets;
+DROP TABLE IF EXISTS attraction_entry_settings;
+DROP TABLE IF EXISTS attraction_ticket_types;
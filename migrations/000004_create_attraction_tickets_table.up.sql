@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS attraction_ticket_types (
+    id            UUID PRIMARY KEY,
+    attraction_id UUID        NOT NULL,
+    kind          VARCHAR(20) NOT NULL,
+    price         BIGINT      NOT NULL CHECK (price >= 0),
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at    TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS attraction_ticket_types_kind_idx ON attraction_ticket_types (attraction_id, kind) WHERE deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS attraction_entry_settings (
+    attraction_id UUID PRIMARY KEY,
+    opening_hours VARCHAR(11) NOT NULL,
+    slot_minutes  INT         NOT NULL CHECK (slot_minutes > 0),
+    capacity      INT         NOT NULL CHECK (capacity > 0),
+    updated_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS attraction_tickets (
+    id             UUID PRIMARY KEY,
+    booking_id     UUID        NOT NULL,
+    attraction_id  UUID        NOT NULL,
+    ticket_type_id UUID        NOT NULL REFERENCES attraction_ticket_types (id),
+    kind           VARCHAR(20) NOT NULL,
+    price          BIGINT      NOT NULL,
+    code           VARCHAR(16) NOT NULL UNIQUE,
+    entry_at       TIMESTAMPTZ NOT NULL,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    canceled_at    TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS attraction_tickets_booking_id_idx ON attraction_tickets (booking_id);
+CREATE INDEX IF NOT EXISTS attraction_tickets_entry_idx ON attraction_tickets (attraction_id, entry_at) WHERE canceled_at IS NULL;
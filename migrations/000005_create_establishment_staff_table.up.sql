@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS establishment_staff (
+    user_id          UUID        NOT NULL,
+    establishment_id UUID        NOT NULL,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, establishment_id)
+);
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS waitlist_entries (
+    id               UUID PRIMARY KEY,
+    category         VARCHAR(20) NOT NULL,
+    establishment_id UUID        NOT NULL,
+    user_id          UUID        NOT NULL,
+    will_arrive      VARCHAR(32) NOT NULL,
+    will_leave       VARCHAR(32) NOT NULL,
+    arrive_at        TIMESTAMPTZ NOT NULL,
+    leave_at         TIMESTAMPTZ NOT NULL,
+    number_of_people INT         NOT NULL,
+    tickets          JSONB       NOT NULL DEFAULT '{}',
+    status           VARCHAR(20) NOT NULL,
+    offer_expires_at TIMESTAMPTZ,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS waitlist_entries_queue_idx ON waitlist_entries (category, establishment_id, status, created_at);
+CREATE INDEX IF NOT EXISTS waitlist_entries_user_id_idx ON waitlist_entries (user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS waitlist_entries_active_idx ON waitlist_entries (user_id, category, establishment_id, arrive_at) WHERE status IN ('waiting', 'offered');
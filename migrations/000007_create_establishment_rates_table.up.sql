@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS establishment_rates (
+    category         VARCHAR(20) NOT NULL,
+    establishment_id UUID        NOT NULL,
+    price            BIGINT      NOT NULL CHECK (price >= 0),
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (category, establishment_id)
+);
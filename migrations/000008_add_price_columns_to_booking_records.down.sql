@@ -0,0 +1,3 @@
+ALTER TABLE booking_records DROP COLUMN IF EXISTS promo_code;
+ALTER TABLE booking_records DROP COLUMN IF EXISTS discount;
+ALTER TABLE booking_records DROP COLUMN IF EXISTS price;
@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS promotions (
+    id                 UUID PRIMARY KEY,
+    code               VARCHAR(50)  NOT NULL,
+    description        VARCHAR(255) NOT NULL DEFAULT '',
+    kind               VARCHAR(20)  NOT NULL,
+    value              BIGINT       NOT NULL CHECK (value > 0),
+    category           VARCHAR(20)  NOT NULL DEFAULT '',
+    city               VARCHAR(100) NOT NULL DEFAULT '',
+    first_booking_only BOOLEAN      NOT NULL DEFAULT FALSE,
+    min_price          BIGINT       NOT NULL DEFAULT 0,
+    max_redemptions    INT          NOT NULL DEFAULT 0,
+    per_user_limit     INT          NOT NULL DEFAULT 0,
+    redemptions        INT          NOT NULL DEFAULT 0,
+    starts_at          TIMESTAMPTZ  NOT NULL,
+    ends_at            TIMESTAMPTZ  NOT NULL,
+    created_at         TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at         TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    deleted_at         TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS promotions_code_idx ON promotions (UPPER(code)) WHERE deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS promo_redemptions (
+    id            UUID PRIMARY KEY,
+    promotion_id  UUID        NOT NULL REFERENCES promotions (id),
+    user_id       UUID        NOT NULL,
+    booking_id    UUID        NOT NULL UNIQUE,
+    discount      BIGINT      NOT NULL,
+    first_booking BOOLEAN     NOT NULL DEFAULT FALSE,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS promo_redemptions_user_idx ON promo_redemptions (promotion_id, user_id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS promo_redemptions_first_booking_idx ON promo_redemptions (user_id) WHERE first_booking;
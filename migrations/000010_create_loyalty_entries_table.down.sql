@@ -0,0 +1,4 @@
+ALTER TABLE booking_records DROP COLUMN IF EXISTS points_discount;
+ALTER TABLE booking_records DROP COLUMN IF EXISTS points;
+
+DROP TABLE IF EXISTS loyalty_entries;
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS loyalty_entries (
+    id         UUID PRIMARY KEY,
+    user_id    UUID         NOT NULL,
+    kind       VARCHAR(20)  NOT NULL,
+    points     BIGINT       NOT NULL CHECK (points <> 0),
+    booking_id UUID,
+    reason     VARCHAR(255) NOT NULL DEFAULT '',
+    created_by UUID,
+    expires_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS loyalty_entries_user_idx ON loyalty_entries (user_id, created_at);
+CREATE UNIQUE INDEX IF NOT EXISTS loyalty_entries_booking_idx ON loyalty_entries (booking_id, kind) WHERE booking_id IS NOT NULL;
+
+ALTER TABLE booking_records ADD COLUMN IF NOT EXISTS points BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE booking_records ADD COLUMN IF NOT EXISTS points_discount BIGINT NOT NULL DEFAULT 0;
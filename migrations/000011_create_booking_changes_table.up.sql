@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS booking_changes (
+    id         UUID PRIMARY KEY,
+    booking_id UUID        NOT NULL,
+    changed_by UUID,
+    role       VARCHAR(20) NOT NULL,
+    fields     JSONB       NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS booking_changes_booking_id_idx ON booking_changes (booking_id, created_at);
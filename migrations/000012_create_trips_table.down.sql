@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS trip_items;
+DROP TABLE IF EXISTS trips;
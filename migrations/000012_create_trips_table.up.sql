@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS trips (
+    id         UUID PRIMARY KEY,
+    user_id    UUID         NOT NULL,
+    name       VARCHAR(100) NOT NULL DEFAULT '',
+    status     VARCHAR(20)  NOT NULL,
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS trips_user_id_idx ON trips (user_id, created_at);
+
+CREATE TABLE IF NOT EXISTS trip_items (
+    id               UUID PRIMARY KEY,
+    trip_id          UUID        NOT NULL REFERENCES trips (id) ON DELETE CASCADE,
+    category         VARCHAR(20) NOT NULL,
+    establishment_id UUID        NOT NULL,
+    will_arrive      VARCHAR(50) NOT NULL,
+    will_leave       VARCHAR(50) NOT NULL,
+    number_of_people BIGINT      NOT NULL DEFAULT 0,
+    tickets          JSONB       NOT NULL DEFAULT '{}',
+    promo_code       VARCHAR(50) NOT NULL DEFAULT '',
+    booking_id       UUID,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS trip_items_trip_id_idx ON trip_items (trip_id, created_at);
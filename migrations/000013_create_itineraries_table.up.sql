@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS itineraries (
+    id         UUID PRIMARY KEY,
+    user_id    UUID         NOT NULL,
+    name       VARCHAR(100) NOT NULL DEFAULT '',
+    city       VARCHAR(100) NOT NULL DEFAULT '',
+    people     BIGINT       NOT NULL DEFAULT 1,
+    days       JSONB        NOT NULL DEFAULT '[]',
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS itineraries_user_id_idx ON itineraries (user_id, created_at);
@@ -0,0 +1,22 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE TABLE IF NOT EXISTS search_documents (
+    category         VARCHAR(20)  NOT NULL,
+    establishment_id UUID         NOT NULL,
+    name             VARCHAR(255) NOT NULL DEFAULT '',
+    description      TEXT         NOT NULL DEFAULT '',
+    city             VARCHAR(100) NOT NULL DEFAULT '',
+    address          TEXT         NOT NULL DEFAULT '',
+    thumbnail        TEXT         NOT NULL DEFAULT '',
+    rating           REAL         NOT NULL DEFAULT 0,
+    latitude         FLOAT8       NOT NULL DEFAULT 0,
+    longitude        FLOAT8       NOT NULL DEFAULT 0,
+    name_key         TEXT         NOT NULL DEFAULT '',
+    city_key         TEXT         NOT NULL DEFAULT '',
+    description_key  TEXT         NOT NULL DEFAULT '',
+    updated_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (category, establishment_id)
+);
+
+CREATE INDEX IF NOT EXISTS search_documents_keys_idx ON search_documents
+    USING GIN ((name_key || ' ' || city_key || ' ' || description_key) gin_trgm_ops);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS search_documents_rating_idx;
+DROP INDEX IF EXISTS search_documents_city_idx;
+
+ALTER TABLE search_documents DROP COLUMN IF EXISTS created_at;
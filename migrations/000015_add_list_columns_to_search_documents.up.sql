@@ -0,0 +1,4 @@
+ALTER TABLE search_documents ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS search_documents_city_idx ON search_documents (category, city);
+CREATE INDEX IF NOT EXISTS search_documents_rating_idx ON search_documents (category, rating DESC);
@@ -0,0 +1,3 @@
+ALTER TABLE search_documents DROP COLUMN IF EXISTS opening_hours;
+
+DROP TABLE IF EXISTS opening_schedules;
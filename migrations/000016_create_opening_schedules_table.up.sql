@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS opening_schedules (
+    category         VARCHAR(20) NOT NULL,
+    establishment_id UUID        NOT NULL,
+    week             JSONB       NOT NULL DEFAULT '{}',
+    exceptions       JSONB       NOT NULL DEFAULT '{}',
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (category, establishment_id)
+);
+
+ALTER TABLE search_documents ADD COLUMN IF NOT EXISTS opening_hours VARCHAR(50) NOT NULL DEFAULT '';
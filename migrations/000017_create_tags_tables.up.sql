@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS tags (
+    slug       VARCHAR(50)  PRIMARY KEY,
+    kind       VARCHAR(20)  NOT NULL,
+    name       VARCHAR(100) NOT NULL,
+    categories JSONB        NOT NULL DEFAULT '[]',
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS establishment_tags (
+    category         VARCHAR(20) NOT NULL,
+    establishment_id UUID        NOT NULL,
+    tag              VARCHAR(50) NOT NULL REFERENCES tags (slug) ON DELETE CASCADE,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (category, establishment_id, tag)
+);
+
+CREATE INDEX IF NOT EXISTS establishment_tags_tag_idx ON establishment_tags (category, tag);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS establishment_translations;
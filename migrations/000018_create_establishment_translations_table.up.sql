@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS establishment_translations (
+    category         VARCHAR(20)  NOT NULL,
+    establishment_id UUID         NOT NULL,
+    locale           VARCHAR(10)  NOT NULL,
+    name             VARCHAR(255) NOT NULL DEFAULT '',
+    description      TEXT         NOT NULL DEFAULT '',
+    updated_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (category, establishment_id, locale)
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS establishment_import_refs;
+DROP TABLE IF EXISTS establishment_imports;
@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS establishment_imports (
+    id         UUID PRIMARY KEY,
+    category   VARCHAR(20) NOT NULL,
+    format     VARCHAR(10) NOT NULL,
+    owner_id   UUID        NOT NULL,
+    locale     VARCHAR(10) NOT NULL,
+    dry_run    BOOLEAN     NOT NULL DEFAULT TRUE,
+    status     VARCHAR(20) NOT NULL,
+    rows       JSONB       NOT NULL DEFAULT '[]',
+    next_row   BIGINT      NOT NULL DEFAULT 0,
+    total      BIGINT      NOT NULL DEFAULT 0,
+    invalid    BIGINT      NOT NULL DEFAULT 0,
+    processed  BIGINT      NOT NULL DEFAULT 0,
+    created    BIGINT      NOT NULL DEFAULT 0,
+    updated    BIGINT      NOT NULL DEFAULT 0,
+    failed     BIGINT      NOT NULL DEFAULT 0,
+    errors     JSONB       NOT NULL DEFAULT '[]',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS establishment_imports_created_at_idx ON establishment_imports (created_at);
+
+CREATE TABLE IF NOT EXISTS establishment_import_refs (
+    category         VARCHAR(20)  NOT NULL,
+    external_ref     VARCHAR(100) NOT NULL,
+    establishment_id UUID         NOT NULL,
+    import_id        UUID         NOT NULL,
+    updated_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (category, external_ref)
+);
+
+CREATE INDEX IF NOT EXISTS establishment_import_refs_establishment_idx ON establishment_import_refs (category, establishment_id);
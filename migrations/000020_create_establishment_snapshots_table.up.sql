@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS establishment_snapshots (
+    id          UUID PRIMARY KEY,
+    category    VARCHAR(20) NOT NULL,
+    format      VARCHAR(10) NOT NULL,
+    filter      JSONB       NOT NULL DEFAULT '{}',
+    owner_id    UUID        NOT NULL,
+    next_run_at TIMESTAMPTZ NOT NULL,
+    last_run_at TIMESTAMPTZ,
+    last_object TEXT        NOT NULL DEFAULT '',
+    last_rows   BIGINT      NOT NULL DEFAULT 0,
+    last_error  TEXT        NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS establishment_snapshots_created_at_idx ON establishment_snapshots (created_at);
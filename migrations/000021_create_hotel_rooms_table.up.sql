@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS hotel_rooms (
+    hotel_id   UUID PRIMARY KEY,
+    rooms      INT         NOT NULL CHECK (rooms > 0),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS room_holds (
+    booking_id UUID        NOT NULL,
+    hotel_id   UUID        NOT NULL,
+    night      DATE        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (booking_id, night)
+);
+
+CREATE INDEX IF NOT EXISTS room_holds_hotel_id_idx ON room_holds (hotel_id, night);
+
+INSERT INTO room_holds (booking_id, hotel_id, night)
+SELECT r.id, r.establishment_id, night::date
+FROM booking_records r,
+     generate_series(
+         (r.arrive_at AT TIME ZONE 'Asia/Tashkent')::date,
+         GREATEST(
+             (COALESCE(r.leave_at, r.arrive_at) AT TIME ZONE 'Asia/Tashkent')::date,
+             (r.arrive_at AT TIME ZONE 'Asia/Tashkent')::date + 1
+         ) - 1,
+         INTERVAL '1 day'
+     ) AS night
+WHERE r.category = 'hotel'
+  AND r.arrive_at IS NOT NULL
+  AND r.state IN ('confirmed', 'checked_in')
+ON CONFLICT DO NOTHING;